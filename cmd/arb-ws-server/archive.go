@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/archive"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+)
+
+// newArchiver connects to the archive bucket and, when lifecycle days are
+// configured, installs lifecycle rules for the archived tables' prefixes
+func newArchiver(ctx context.Context, cfg *config.Config, st *store.Store, logger *slog.Logger) (*archive.Archiver, error) {
+	objects, err := archive.NewObjectStore(ctx, archive.StoreConfig{
+		Bucket:       cfg.ArchiveBucket,
+		Endpoint:     cfg.ArchiveEndpoint,
+		Region:       cfg.ArchiveRegion,
+		StorageClass: cfg.ArchiveStorageClass,
+	})
+	if err != nil {
+		return nil, err
+	}
+	archiver := archive.New(st, objects, cfg.ArchivePrefix, cfg.ArchiveAge, logger)
+
+	if cfg.ArchiveTransitionDays > 0 || cfg.ArchiveExpireDays > 0 {
+		var rules []archive.LifecycleRule
+		for _, table := range archive.Tables {
+			rules = append(rules, archive.LifecycleRule{
+				Prefix:          archiver.TablePrefix(table),
+				TransitionDays:  cfg.ArchiveTransitionDays,
+				TransitionClass: cfg.ArchiveTransitionClass,
+				ExpireDays:      cfg.ArchiveExpireDays,
+			})
+		}
+		if err := objects.PutLifecycle(ctx, rules); err != nil {
+			return nil, err
+		}
+		logger.Info("archive lifecycle configured", "bucket", cfg.ArchiveBucket,
+			"transition_days", cfg.ArchiveTransitionDays, "expire_days", cfg.ArchiveExpireDays)
+	}
+	return archiver, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/catalog"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// backfillDelay is how long after a reconnect the gap is backfilled: long
+// enough for the last candlestick of the gap to close and for edges that
+// survived the outage to be published again
+const backfillDelay = time.Minute
+
+// backfillKalshiGap recovers the Kalshi quotes of open opportunities missed
+// between lostAt and restoredAt from the candlestick API, fills the gap in
+// their streaks and persists the recovered samples when st is set
+func backfillKalshiGap(ctx context.Context, client *http.Client, baseURL string, engine *arb.Engine, st *store.Store, lostAt, restoredAt time.Time, logger *slog.Logger) {
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(backfillDelay):
+	}
+
+	opps, err := engine.GetOpportunities(ctx)
+	if err != nil {
+		return
+	}
+	history := make(map[string][]ws.KalshiPriceUpdate)
+	for _, o := range opps {
+		if _, done := history[o.KalshiTicker]; done {
+			continue
+		}
+		series := catalog.KalshiSeriesTicker(o.Market.KalshiEventTicker)
+		quotes, err := catalog.FetchKalshiCandlesticks(ctx, client, baseURL, series, o.KalshiTicker, lostAt, restoredAt.Add(time.Minute))
+		if err != nil {
+			logger.Warn("kalshi backfill fetch failed", "ticker", o.KalshiTicker, "error", err)
+			continue
+		}
+		history[o.KalshiTicker] = quotes
+	}
+
+	streaks := engine.BackfillKalshi(lostAt, restoredAt, history)
+	var samples int
+	for _, s := range streaks {
+		samples += len(s.Samples)
+	}
+	logger.Info("kalshi gap backfilled",
+		"lost_at", lostAt,
+		"restored_at", restoredAt,
+		"tickers", len(history),
+		"streaks", len(streaks),
+		"samples", samples,
+	)
+
+	if st != nil && len(streaks) > 0 {
+		if err := st.RecordBackfill(ctx, streaks); err != nil {
+			logger.Error("failed to persist kalshi backfill", "error", err)
+		}
+	}
+}
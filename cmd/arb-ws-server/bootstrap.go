@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// defaultBootstrapBatch is the number of Kalshi markets matched per batch
+// when BOOTSTRAP_BATCH is unset
+const defaultBootstrapBatch = 200
+
+// bootstrapBatch returns the configured matching batch size
+func bootstrapBatch(cfg *config.Config) int {
+	if cfg.BootstrapBatch > 0 {
+		return cfg.BootstrapBatch
+	}
+	return defaultBootstrapBatch
+}
+
+// matchTopK matches Kalshi markets against pmMarkets in descending liquidity
+// order, batch by batch, until at least cfg.BootstrapTopK pairs are found. It
+// returns those pairs and the Kalshi markets not yet matched.
+func matchTopK(pmMarkets []ws.PolymarketMarket, kalshiMarkets []ws.KalshiMarket, cfg *config.Config, nearMisses *arb.NearMissRegistry, collisions *pairCollisions, logger *slog.Logger) ([]arb.MarketPair, []ws.KalshiMarket) {
+	sorted := make([]ws.KalshiMarket, len(kalshiMarkets))
+	copy(sorted, kalshiMarkets)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Liquidity > sorted[j].Liquidity })
+
+	batch := bootstrapBatch(cfg)
+	pairs := make([]arb.MarketPair, 0, cfg.BootstrapTopK)
+	i := 0
+	for i < len(sorted) && len(pairs) < cfg.BootstrapTopK {
+		end := min(i+batch, len(sorted))
+		pairs = append(pairs, createMarketPairs(pmMarkets, sorted[i:end], cfg, nearMisses, collisions, logger)...)
+		i = end
+	}
+	return pairs, sorted[i:]
+}
+
+// matchBacklog is the long tail of Kalshi markets left to match after the
+// first bootstrap phase
+type matchBacklog struct {
+	pm         []ws.PolymarketMarket
+	kalshi     []ws.KalshiMarket
+	nearMisses *arb.NearMissRegistry
+	collisions *pairCollisions
+	remaining  atomic.Int64
+	activated  atomic.Int64
+}
+
+// newMatchBacklog returns nil when there is nothing left to match
+func newMatchBacklog(pm []ws.PolymarketMarket, kalshi []ws.KalshiMarket, nearMisses *arb.NearMissRegistry, collisions *pairCollisions) *matchBacklog {
+	if len(kalshi) == 0 {
+		return nil
+	}
+	b := &matchBacklog{pm: pm, kalshi: kalshi, nearMisses: nearMisses, collisions: collisions}
+	b.remaining.Store(int64(len(kalshi)))
+	return b
+}
+
+// Remaining returns the number of Kalshi markets not yet matched
+func (b *matchBacklog) Remaining() int {
+	if b == nil {
+		return 0
+	}
+	return int(b.remaining.Load())
+}
+
+// Status reports background matching progress for /status
+func (b *matchBacklog) Status() map[string]interface{} {
+	if b == nil {
+		return map[string]interface{}{"complete": true}
+	}
+	return map[string]interface{}{
+		"complete":               b.remaining.Load() == 0,
+		"pending_kalshi_markets": b.remaining.Load(),
+		"activated_pairs":        b.activated.Load(),
+	}
+}
+
+// Run matches the backlog in batches, handing each batch's pairs to activate
+// as soon as they're confirmed, until done or ctx is cancelled
+func (b *matchBacklog) Run(ctx context.Context, cfg *config.Config, activate func([]arb.MarketPair), logger *slog.Logger) {
+	if b == nil {
+		return
+	}
+
+	start := time.Now()
+	batch := bootstrapBatch(cfg)
+	for i := 0; i < len(b.kalshi); i += batch {
+		if ctx.Err() != nil {
+			return
+		}
+		end := min(i+batch, len(b.kalshi))
+
+		pairs := createMarketPairs(b.pm, b.kalshi[i:end], cfg, b.nearMisses, b.collisions, logger)
+		if len(pairs) > 0 {
+			activate(pairs)
+			b.activated.Add(int64(len(pairs)))
+		}
+		b.remaining.Store(int64(len(b.kalshi) - end))
+		logger.Debug("background matching batch done", "pairs", len(pairs), "pending_kalshi_markets", len(b.kalshi)-end)
+	}
+
+	logger.Info("background matching complete",
+		"activated_pairs", b.activated.Load(),
+		"kalshi_markets", len(b.kalshi),
+		"duration", time.Since(start).Round(time.Millisecond).String(),
+	)
+}
+
+// loadNearMisses creates the near-miss registry, seeded from and persisted to
+// the store when one is configured
+func loadNearMisses(ctx context.Context, st *store.Store, logger *slog.Logger) (*arb.NearMissRegistry, error) {
+	if st == nil {
+		return arb.NewNearMissRegistry(nil), nil
+	}
+
+	persisted, err := st.NearMisses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	registry := arb.NewNearMissRegistry(persisted)
+	registry.OnChange(func(n arb.NearMiss) {
+		if err := st.SaveNearMiss(context.WithoutCancel(ctx), n); err != nil {
+			logger.Error("failed to persist near-miss", "id", n.ID, "error", err)
+		}
+	})
+	logger.Info("near-misses loaded", "count", len(persisted))
+	return registry, nil
+}
+
+// loadAnnotations creates the pair annotation registry from the mapping file,
+// if any, overlaid with annotations set through the API in earlier runs
+func loadAnnotations(ctx context.Context, path string, st *store.Store, logger *slog.Logger) (*arb.AnnotationRegistry, error) {
+	var file, persisted []arb.Annotation
+	var err error
+	if path != "" {
+		if file, err = arb.LoadAnnotationsFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if st != nil {
+		if persisted, err = st.Annotations(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	registry := arb.NewAnnotationRegistry(file, persisted)
+	if st != nil {
+		registry.OnChange(func(a arb.Annotation) {
+			if err := st.SaveAnnotation(context.WithoutCancel(ctx), a); err != nil {
+				logger.Error("failed to persist pair annotation", "pm_condition_id", a.PMConditionID, "kalshi_ticker", a.KalshiTicker, "error", err)
+			}
+			if err := st.SetPairTags(context.WithoutCancel(ctx), a); err != nil {
+				logger.Error("failed to index pair tags", "pm_condition_id", a.PMConditionID, "kalshi_ticker", a.KalshiTicker, "error", err)
+			}
+		})
+	}
+	logger.Info("pair annotations loaded", "file", len(file), "persisted", len(persisted))
+	return registry, nil
+}
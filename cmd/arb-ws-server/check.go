@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+	httpserver "github.com/artemgubar/prediction-markets/arb-ws/internal/http"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/notify"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/schedule"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/secrets"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/auth"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/match"
+)
+
+// checkTimeout bounds each probe run by --check
+const checkTimeout = 15 * time.Second
+
+// errSkipped marks a check that does not apply to this configuration
+var errSkipped = errors.New("skipped")
+
+// checkResult is one line of the --check report
+type checkResult struct {
+	name   string
+	detail string
+	err    error
+}
+
+// runCheck validates the configuration and probes every external dependency
+// the server would use: the Kalshi key, both venues' REST APIs and websocket
+// handshakes, the history database and the notification and publishing
+// targets. Nothing is subscribed to or delivered. It prints a report to out
+// and returns false when any check failed.
+func runCheck(ctx context.Context, cfg *config.Config, out io.Writer) bool {
+	var results []checkResult
+	check := func(name string, fn func(ctx context.Context) (string, error)) {
+		ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		defer cancel()
+		detail, err := fn(ctx)
+		results = append(results, checkResult{name: name, detail: detail, err: err})
+	}
+
+	check("config", func(context.Context) (string, error) {
+		detail := "no profile"
+		if cfg.Profile.Name != "" {
+			detail = "profile " + cfg.Profile.Name
+		}
+		return detail, validateConfig(cfg)
+	})
+
+	var kalshiKey *rsa.PrivateKey
+	check("kalshi key", func(ctx context.Context) (string, error) {
+		if cfg.KalshiKeyID == "" || cfg.KalshiKeyPath == "" {
+			if cfg.KalshiPollInterval > 0 {
+				return "credentials not provided, polling public quotes every " + cfg.KalshiPollInterval.String(), errSkipped
+			}
+			return "credentials not provided, kalshi websocket disabled", errSkipped
+		}
+		source, err := secrets.New(ctx, cfg.SecretsProvider, secrets.Options{
+			VaultAddr:       cfg.VaultAddr,
+			VaultToken:      cfg.VaultToken,
+			AWSRegion:       cfg.AWSRegion,
+			AgeIdentityFile: cfg.AgeIdentityFile,
+		})
+		if err != nil {
+			return "", fmt.Errorf("secrets provider %s: %w", cfg.SecretsProvider, err)
+		}
+		kalshiKey, err = readKalshiKey(ctx, cfg, source)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("key id %s, %d-bit RSA", cfg.KalshiKeyID, kalshiKey.N.BitLen()), nil
+	})
+
+	check("polymarket wallet", func(ctx context.Context) (string, error) {
+		if cfg.PMWalletKeyPath == "" {
+			return "PM_WALLET_KEY_PATH not set, api credentials not derived", errSkipped
+		}
+		source, err := secrets.New(ctx, cfg.SecretsProvider, secrets.Options{
+			VaultAddr:       cfg.VaultAddr,
+			VaultToken:      cfg.VaultToken,
+			AWSRegion:       cfg.AWSRegion,
+			AgeIdentityFile: cfg.AgeIdentityFile,
+		})
+		if err != nil {
+			return "", fmt.Errorf("secrets provider %s: %w", cfg.SecretsProvider, err)
+		}
+		wallet, err := readPMWallet(ctx, cfg, source)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("address %s, nonce %d", wallet.Address(), cfg.PMAPINonce), nil
+	})
+
+	client := &http.Client{}
+	check("polymarket rest", func(ctx context.Context) (string, error) {
+		return probeREST(ctx, client, cfg.PMRESTURL+"/markets")
+	})
+	check("kalshi rest", func(ctx context.Context) (string, error) {
+		return probeREST(ctx, client, cfg.KalshiRESTURL+"/markets?limit=1")
+	})
+
+	pmDialer, kalshiDialer, dialErr := newDialers(cfg)
+	check("polymarket websocket", func(ctx context.Context) (string, error) {
+		if dialErr != nil {
+			return "", dialErr
+		}
+		conn, _, err := pmDialer.DialContext(ctx, cfg.PMWSURL, nil)
+		if err != nil {
+			return "", err
+		}
+		return cfg.PMWSURL, conn.Close()
+	})
+	check("kalshi websocket", func(ctx context.Context) (string, error) {
+		if dialErr != nil {
+			return "", dialErr
+		}
+		if kalshiKey == nil {
+			return "no usable key", errSkipped
+		}
+		signer, err := auth.NewKalshiSigner(cfg.KalshiKeyID, kalshiKey, auth.NewClock(0))
+		if err != nil {
+			return "", err
+		}
+		u, err := url.Parse(cfg.KalshiWSURL)
+		if err != nil {
+			return "", fmt.Errorf("parse ws url: %w", err)
+		}
+		headers, err := signer.Headers(http.MethodGet, u.Path, nil)
+		if err != nil {
+			return "", err
+		}
+		conn, _, err := kalshiDialer.DialContext(ctx, cfg.KalshiWSURL, headers)
+		if err != nil {
+			return "", err
+		}
+		return cfg.KalshiWSURL + ", handshake authenticated", conn.Close()
+	})
+
+	check("database", func(ctx context.Context) (string, error) {
+		if cfg.DBPath == "" {
+			return "DB_PATH not set, persistence disabled", errSkipped
+		}
+		st, err := store.Open(ctx, cfg.DBPath, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		if err != nil {
+			return "", err
+		}
+		return cfg.DBPath, st.Close()
+	})
+
+	// Targets that fail to build are reported by the config check
+	senders, _ := notifySenders(cfg)
+	opsSenders, _ := buildSenders(cfg.OpsNotifySenders)
+	for _, s := range append(senders, opsSenders...) {
+		check("notify "+s.Name(), func(ctx context.Context) (string, error) { return probeTarget(ctx, s) })
+	}
+	pubs, _ := publishers(cfg)
+	for _, p := range pubs {
+		check("publish "+p.Name(), func(ctx context.Context) (string, error) { return probeTarget(ctx, p) })
+	}
+
+	return printCheckReport(out, results)
+}
+
+// validateConfig runs every parse the server does at startup, so malformed
+// settings are reported before deploying rather than when starting
+func validateConfig(cfg *config.Config) error {
+	var errs []error
+	if _, err := match.ParseAbbreviations(cfg.MatchAbbreviations, cfg.MatchAbbreviationsDisable); err != nil {
+		errs = append(errs, fmt.Errorf("match abbreviations: %w", err))
+	}
+	if _, err := arb.BuildFilters(cfg.Filters, filterParams(cfg)); err != nil {
+		errs = append(errs, fmt.Errorf("filters: %w", err))
+	}
+	if _, err := arb.BuildStrategies(cfg.Strategies, strategyParams(cfg, nil)); err != nil {
+		errs = append(errs, fmt.Errorf("strategies: %w", err))
+	}
+	if _, err := schedule.Parse(cfg.KalshiMaintenanceWindows, cfg.KalshiMaintenanceTZ); err != nil {
+		errs = append(errs, fmt.Errorf("kalshi maintenance schedule: %w", err))
+	}
+	if _, err := schedule.ParseCalendar(cfg.KalshiTradingHours, cfg.KalshiHolidays, cfg.KalshiTradingHoursTZ); err != nil {
+		errs = append(errs, fmt.Errorf("kalshi trading hours: %w", err))
+	}
+	if _, err := httpserver.ParseRouteTimeouts(cfg.HTTPRouteTimeouts); err != nil {
+		errs = append(errs, fmt.Errorf("http route timeouts: %w", err))
+	}
+	listeners := httpserver.Listeners{Admin: cfg.HTTPAdminAddr, Metrics: cfg.HTTPMetricsAddr}
+	if err := listeners.Validate(cfg.HTTPAddr); err != nil {
+		errs = append(errs, fmt.Errorf("http listeners: %w", err))
+	}
+	if cfg.Chaos != "" {
+		if _, err := chaos.ParseFaults(cfg.Chaos); err != nil {
+			errs = append(errs, fmt.Errorf("chaos: %w", err))
+		} else if !chaos.Enabled {
+			errs = append(errs, errors.New("chaos: set, but this binary was built without -tags chaos"))
+		}
+	}
+	if _, _, err := newDialers(cfg); err != nil {
+		errs = append(errs, fmt.Errorf("websocket options: %w", err))
+	}
+	if _, err := notifySenders(cfg); err != nil {
+		errs = append(errs, fmt.Errorf("notifications: %w", err))
+	}
+	if _, err := buildSenders(cfg.OpsNotifySenders); err != nil {
+		errs = append(errs, fmt.Errorf("ops notifications: %w", err))
+	}
+	if _, err := publishers(cfg); err != nil {
+		errs = append(errs, fmt.Errorf("publishers: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// probeREST expects a 2xx response to an unauthenticated GET
+func probeREST(ctx context.Context, client *http.Client, rawURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("GET %s: status %d", rawURL, resp.StatusCode)
+	}
+	return fmt.Sprintf("%s in %s", rawURL, time.Since(start).Round(time.Millisecond)), nil
+}
+
+// probeTarget checks a notification or publishing target that supports it
+func probeTarget(ctx context.Context, target interface{}) (string, error) {
+	c, ok := target.(notify.Checker)
+	if !ok {
+		return "cannot be probed without delivering", errSkipped
+	}
+	return "reachable", c.Check(ctx)
+}
+
+// printCheckReport writes one line per check and a summary, and reports
+// whether every check passed or was skipped
+func printCheckReport(out io.Writer, results []checkResult) bool {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	var failed, skipped int
+	for _, r := range results {
+		status, detail := "ok", r.detail
+		switch {
+		case errors.Is(r.err, errSkipped):
+			status = "skip"
+			skipped++
+		case r.err != nil:
+			status, detail = "FAIL", strings.ReplaceAll(r.err.Error(), "\n", "; ")
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", status, r.name, detail)
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\n%d checks: %d ok, %d failed, %d skipped\n", len(results), len(results)-failed-skipped, failed, skipped)
+	return failed == 0
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/match"
+)
+
+// maxCollisionRecords caps the suppressed matches listed on /status
+const maxCollisionRecords = 100
+
+// collisionRecord is a suppressed match as listed on /status
+type collisionRecord struct {
+	Side         string  `json:"side"` // Contested market's venue
+	PMTitle      string  `json:"pm_title"`
+	KalshiTicker string  `json:"kalshi_ticker"`
+	Score        float64 `json:"score"`
+	KeptPMTitle  string  `json:"kept_pm_title"`
+	KeptTicker   string  `json:"kept_kalshi_ticker"`
+	KeptScore    float64 `json:"kept_score"`
+}
+
+// pairCollisions resolves markets matched more than their allowance across
+// every matching pass, and keeps the suppressed matches for /status
+type pairCollisions struct {
+	resolver *match.Resolver
+	logger   *slog.Logger
+
+	mu     sync.Mutex
+	total  int
+	recent []collisionRecord // Most recent last
+}
+
+func newPairCollisions(cfg *config.Config, logger *slog.Logger) *pairCollisions {
+	return &pairCollisions{resolver: match.NewResolver(cfg.MatchMaxPerPM, cfg.MatchMaxPerKalshi), logger: logger}
+}
+
+// resolve drops the matches beyond their markets' allowance
+func (c *pairCollisions) resolve(matches []match.MarketMatch) []match.MarketMatch {
+	kept, collisions := c.resolver.Resolve(matches)
+	if len(collisions) == 0 {
+		return kept
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, col := range collisions {
+		metrics.RecordPairCollision(col.Side)
+		s, k := col.Suppressed, col.KeptBy
+		c.logger.Debug("market match suppressed",
+			"contested", col.Side,
+			"pm_title", s.PM.Question,
+			"kalshi_ticker", s.Kalshi.Ticker,
+			"similarity", fmt.Sprintf("%.2f", s.Score),
+			"kept_pm_title", k.PM.Question,
+			"kept_kalshi_ticker", k.Kalshi.Ticker,
+			"kept_similarity", fmt.Sprintf("%.2f", k.Score),
+		)
+		c.recent = append(c.recent, collisionRecord{
+			Side:         col.Side,
+			PMTitle:      s.PM.Question,
+			KalshiTicker: s.Kalshi.Ticker,
+			Score:        s.Score,
+			KeptPMTitle:  k.PM.Question,
+			KeptTicker:   k.Kalshi.Ticker,
+			KeptScore:    k.Score,
+		})
+	}
+	c.total += len(collisions)
+	if over := len(c.recent) - maxCollisionRecords; over > 0 {
+		c.recent = append(c.recent[:0:0], c.recent[over:]...)
+	}
+	return kept
+}
+
+// Status reports the suppressed matches for /status
+func (c *pairCollisions) Status() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return map[string]interface{}{
+		"suppressed": c.total,
+		"recent":     append([]collisionRecord(nil), c.recent...),
+	}
+}
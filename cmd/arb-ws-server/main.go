@@ -2,24 +2,71 @@ package main
 
 import (
 	"context"
+	"crypto/rsa"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Maintenance schedules need zone data; the runtime image ships none
 
-	"github.com/artemgubar/prediction-markets/arb-ws/internal/arb"
+	"github.com/gorilla/websocket"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/account"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/budget"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/calibration"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/compliance"
 	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/diag"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/feedhealth"
 	httpserver "github.com/artemgubar/prediction-markets/arb-ws/internal/http"
-	"github.com/artemgubar/prediction-markets/arb-ws/internal/match"
-	"github.com/artemgubar/prediction-markets/arb-ws/internal/ws"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/killswitch"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/lifecycle"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/marketstatus"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/notify"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/plugin"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/schedule"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/secrets"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/service"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/shadow"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/auth"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/catalog"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/match"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
 )
 
 func main() {
+	// Under the Windows service control manager, stop requests arrive through
+	// the service handler instead of signals
+	if service.IsWindowsService() {
+		if err := service.RunWindowsService("arb-ws-server", run); err != nil {
+			slog.Error("windows service failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	shutdown, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	run(shutdown)
+}
+
+// run starts all components and blocks until shutdown is cancelled
+func run(shutdown context.Context) {
 	// Setup structured logging
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -28,36 +75,261 @@ func main() {
 
 	logger.Info("starting arb-ws-server")
 
-	// Load configuration
-	cfg := config.Load()
+	// Load configuration: flags over environment over config file over profile
+	cfg, err := config.LoadArgs("arb-ws-server", os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(2)
+	}
+	// --check validates and probes dependencies instead of serving, as a
+	// pre-deploy gate
+	if cfg.Check {
+		if !runCheck(shutdown, cfg, os.Stdout) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	logger.Info("configuration loaded",
+		"profile", cfg.Profile.Name,
 		"http_addr", cfg.HTTPAddr,
+		"http_admin_addr", cfg.HTTPAdminAddr,
+		"http_metrics_addr", cfg.HTTPMetricsAddr,
 		"edge_threshold", cfg.EdgeMinRORPct,
 		"title_sim", cfg.TitleSim,
 		"time_window_h", cfg.TimeWindowH,
 		"pm_chunk", cfg.PMChunk,
+		"filters", cfg.Filters,
+		"strategies", cfg.Strategies,
 	)
 
+	// Staging builds inject faults into exchange traffic to exercise the
+	// clients' and engine's defenses
+	if cfg.Chaos != "" {
+		if err := chaos.Configure(cfg.Chaos); err != nil {
+			logger.Error("invalid chaos faults", "error", err)
+			os.Exit(2)
+		}
+		logger.Warn("injecting faults into exchange traffic", "faults", cfg.Chaos)
+	}
+
 	// Create context that can be cancelled
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Bootstrap: Fetch markets and create pairs
-	logger.Info("bootstrapping: fetching markets and creating pairs")
-	pairs, pmTokenIDs, kalshiTickers, err := bootstrap(ctx, cfg, logger)
+	// Subsystems start once bootstrap completes and stop at shutdown
+	hooks := lifecycle.New(logger)
+
+	// Region check: execution is disabled where either venue is off-limits,
+	// read-only scanning always continues
+	complianceStatus := compliance.Check(ctx, cfg, logger)
+	logger.Info("region check complete",
+		"region", complianceStatus.Region,
+		"source", complianceStatus.RegionSource,
+		"pm_execution", complianceStatus.ExecutionAllowed("polymarket"),
+		"kalshi_execution", complianceStatus.ExecutionAllowed("kalshi"),
+	)
+
+	// Matcher abbreviation dictionary, with per-deployment additions and removals
+	abbreviations, err := match.ParseAbbreviations(cfg.MatchAbbreviations, cfg.MatchAbbreviationsDisable)
 	if err != nil {
-		logger.Error("bootstrap failed", "error", err)
+		logger.Error("invalid match abbreviations", "error", err)
 		os.Exit(1)
 	}
+	match.SetAbbreviations(abbreviations)
+	logger.Info("match abbreviations loaded", "entries", abbreviations.Len())
+
+	// Stop words and aliases scoped to one category of markets
+	vocabularies, err := match.ParseVocabularies(cfg.MatchCategoryStopWords, cfg.MatchCategoryAliases)
+	if err != nil {
+		logger.Error("invalid match vocabularies", "error", err)
+		os.Exit(1)
+	}
+	match.SetVocabularies(vocabularies)
+	if vocabularies.Len() > 0 {
+		logger.Info("match category vocabularies loaded", "categories", vocabularies.Len())
+	}
+
+	// API usage accounting against per-exchange budgets
+	apiBudget := budget.NewAccountant(map[string]budget.Limits{
+		arb.VenuePolymarket: {RESTCalls: cfg.PMRESTBudget, Window: cfg.APIBudgetWindow, Subscriptions: cfg.PMSubscriptionBudget},
+		arb.VenueKalshi:     {RESTCalls: cfg.KalshiRESTBudget, Window: cfg.APIBudgetWindow, Subscriptions: cfg.KalshiSubscriptionBudget},
+	}, cfg.APIBudgetThrottleAt)
+
+	// Execution kill switch: engaged by config or the admin endpoint, and
+	// tripped automatically by realized losses or failed unwinds
+	killSwitch := killswitch.New(killswitch.Thresholds{
+		MaxLoss:          cfg.KillSwitchMaxLoss,
+		MaxFailedUnwinds: cfg.KillSwitchMaxFailedUnwinds,
+		Window:           cfg.KillSwitchWindow,
+	}, logger)
+	if cfg.KillSwitch {
+		killSwitch.Engage(killswitch.SourceConfig, "KILL_SWITCH=true")
+	}
+
+	// Open history store (optional)
+	var st *store.Store
+	if cfg.DBPath != "" {
+		st, err = store.Open(ctx, cfg.DBPath, logger)
+		if err != nil {
+			logger.Error("failed to open store", "error", err)
+			os.Exit(1)
+		}
+		defer st.Close()
+	}
+
+	// Near-miss pairs kept for review, with operator decisions from earlier runs
+	var nearMisses *arb.NearMissRegistry
+	if cfg.NearMissMinSim > 0 {
+		nearMisses, err = loadNearMisses(ctx, st, logger)
+		if err != nil {
+			logger.Error("failed to load near-misses", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Operator notes on how far to trust each pairing, carried into alerts
+	annotations, err := loadAnnotations(ctx, cfg.PairAnnotationsFile, st, logger)
+	if err != nil {
+		logger.Error("failed to load pair annotations", "error", err)
+		os.Exit(1)
+	}
+
+	// Warm start: take over a running peer's pairs, quotes and opportunities
+	var warm *arb.WarmState
+	if cfg.WarmStartURL != "" {
+		state, err := fetchWarmState(ctx, cfg.WarmStartURL, cfg.AdminToken, cfg.WarmStartMaxAge)
+		if err != nil {
+			logger.Warn("warm start failed, bootstrapping from scratch", "peer", cfg.WarmStartURL, "error", err)
+		} else {
+			warm = &state
+		}
+	}
+
+	// Bootstrap: Fetch markets and create pairs, each market in as many
+	// pairs as allowed
+	collisions := newPairCollisions(cfg, logger)
+	var (
+		pairs           []arb.MarketPair
+		backlog         *matchBacklog
+		smarketsMatches []match.SmarketsMatch
+	)
+	if warm != nil {
+		pairs = warm.Pairs
+	} else {
+		logger.Info("bootstrapping: fetching markets and creating pairs")
+		pairs, backlog, smarketsMatches, err = bootstrap(ctx, cfg, apiBudget, nearMisses, collisions, logger)
+		if err != nil {
+			logger.Error("bootstrap failed", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Past a venue's subscription budget, monitor the best pairs that fit
+	// rather than fail to subscribe; the rest are listed at /pairs/pruned
+	var pruner *arb.SubscriptionPruner
+	if cfg.PMSubscriptionBudget > 0 || cfg.KalshiSubscriptionBudget > 0 {
+		pruner = arb.NewSubscriptionPruner(arb.SubscriptionLimits{
+			Polymarket: cfg.PMSubscriptionBudget,
+			Kalshi:     cfg.KalshiSubscriptionBudget,
+		})
+		pairs = admitPairs(pruner, pairs, logger)
+	}
+	pmTokenIDs := extractPMTokenIDs(pairs)
+	kalshiTickers := extractKalshiTickers(pairs)
 
 	logger.Info("bootstrap complete",
 		"pairs", len(pairs),
 		"pm_tokens", len(pmTokenIDs),
 		"kalshi_tickers", len(kalshiTickers),
+		"backlog_kalshi_markets", backlog.Remaining(),
+		"suppressed_matches", collisions.Status()["suppressed"],
 	)
 
+	apiBudget.SetSubscriptions(arb.VenuePolymarket, len(pmTokenIDs))
+	apiBudget.SetSubscriptions(arb.VenueKalshi, len(kalshiTickers))
+	for _, u := range apiBudget.Usage() {
+		if u.SubscriptionLimit > 0 && u.Subscriptions > u.SubscriptionLimit {
+			logger.Warn("websocket subscriptions exceed budget",
+				"exchange", u.Exchange, "subscriptions", u.Subscriptions, "budget", u.SubscriptionLimit)
+		}
+	}
+
+	pmDialer, kalshiDialer, err := newDialers(cfg)
+	if err != nil {
+		logger.Error("invalid websocket options", "error", err)
+		os.Exit(1)
+	}
+
+	// Ops alerts flag suspect data and unhealthy feeds; they have their own
+	// destinations and never reach the trade alerts
+	opsSenders, err := buildSenders(cfg.OpsNotifySenders)
+	if err != nil {
+		logger.Error("invalid ops notification configuration", "error", err)
+		os.Exit(1)
+	}
+	var opsDispatcher *notify.Dispatcher
+	if len(opsSenders) > 0 {
+		opsDispatcher = notify.NewDispatcher(opsSenders, notify.Options{
+			Timeout:        cfg.NotifyTimeout,
+			DeadLetterPath: cfg.NotifyDeadLetterPath,
+		}, logger)
+		opsDispatcher.Start(ctx)
+		logger.Info("ops notifications enabled", "senders", len(opsSenders))
+	}
+
+	// Connections dropping soon after every connect back off further and
+	// raise an ops alert
+	flapPolicy := ws.FlapPolicy{MaxReconnects: cfg.WSFlapMaxReconnects, Window: cfg.WSFlapWindow}
+	onFlap := func(f ws.Flap) {
+		logger.Warn("websocket connection flapping", "source", f.Source, "reconnects", f.Reconnects, "window", f.Window)
+		if opsDispatcher != nil {
+			notify.FlapAlerts(opsDispatcher)(f)
+		}
+	}
+
+	// Connectivity history: every connection coming up or going down,
+	// for uptime reports
+	var onConnection func(ws.ConnectionEvent)
+	if st != nil {
+		if err := st.CloseConnectivity(ctx, time.Now()); err != nil {
+			logger.Error("failed to close previous connectivity history", "error", err)
+		}
+		onConnection = st.StartConnectivityRecorder(ctx)
+	}
+
+	// Per-instrument freeze detection learns each feed's update rate
+	freezes := feedhealth.NewDetector(feedhealth.Options{
+		Factor:     cfg.FreezeFactor,
+		MinSamples: cfg.FreezeMinSamples,
+		MinQuiet:   cfg.FreezeMinQuiet,
+	}, logger)
+
+	// Updates per instrument between compute cycles set the engine's cadence
+	quotes := arb.NewConflation()
+	observe := func(id instrument.ID, at time.Time) {
+		freezes.Observe(id, at)
+		quotes.Observe(id, at)
+	}
+
+	// Tick sizes, minimum order sizes and neg-risk flags as matched, kept
+	// fresh by market status polls and tick size changes pushed on the
+	// market channel
+	pmRules := arb.NewPMRulesCache(pairs)
+
 	// Initialize Polymarket WebSocket client
-	pmClient := ws.NewPolymarketClient(ctx, pmTokenIDs, cfg.PMChunk, logger)
+	pmClient := ws.NewPolymarketClient(ctx, cfg.PMWSURL, pmTokenIDs, cfg.PMChunk, logger)
+	pmClient.SetDialer(pmDialer)
+	pmClient.SetFlapDetection(flapPolicy, onFlap)
+	pmClient.SetConnectionHook(onConnection)
+	pmClient.SetUpdateHook(observe)
+	pmClient.SetTickSizeHook(func(conditionID string, tick float64) {
+		if pmRules.SetTickSize(conditionID, tick) {
+			logger.Info("polymarket tick size changed", "market", conditionID, "tick_size", tick)
+		}
+	})
 	if err := pmClient.Start(); err != nil {
 		logger.Error("failed to start polymarket client", "error", err)
 		os.Exit(1)
@@ -65,246 +337,813 @@ func main() {
 	defer pmClient.Close()
 
 	// Initialize Kalshi WebSocket client
-	kalshiClient, err := ws.NewKalshiClient(ctx, cfg.KalshiKeyID, cfg.KalshiKeyPath, kalshiTickers, logger)
+	secretSource, err := secrets.New(ctx, cfg.SecretsProvider, secrets.Options{
+		VaultAddr:       cfg.VaultAddr,
+		VaultToken:      cfg.VaultToken,
+		AWSRegion:       cfg.AWSRegion,
+		AgeIdentityFile: cfg.AgeIdentityFile,
+	})
 	if err != nil {
-		logger.Error("failed to create kalshi client", "error", err)
+		logger.Error("failed to initialize secrets provider", "provider", cfg.SecretsProvider, "error", err)
 		os.Exit(1)
 	}
-	if err := kalshiClient.Start(); err != nil {
-		logger.Error("failed to start kalshi client", "error", err)
+
+	kalshiClient, err := ws.NewKalshiClientWithKey(ctx, cfg.KalshiWSURL, cfg.KalshiKeyID,
+		loadKalshiKey(ctx, cfg, secretSource, logger), kalshiTickers, logger)
+	if err != nil {
+		logger.Error("failed to create kalshi client", "error", err)
 		os.Exit(1)
 	}
-	defer kalshiClient.Close()
 
-	// Initialize arbitrage engine
-	engine := arb.NewEngine(ctx, pairs, pmClient, kalshiClient, cfg.EdgeMinRORPct, logger)
-	engine.Start()
+	// Scheduled Kalshi maintenance: quiet reconnect noise, pause computation,
+	// and resume with a fresh connection when the window closes
+	maintenance, err := schedule.Parse(cfg.KalshiMaintenanceWindows, cfg.KalshiMaintenanceTZ)
+	if err != nil {
+		logger.Error("invalid kalshi maintenance schedule", "error", err)
+		os.Exit(1)
+	}
+	kalshiInMaintenance := func() bool { return maintenance.Active(time.Now()) }
+	kalshiClient.SetMaintenanceCheck(kalshiInMaintenance)
+	kalshiClient.SetDialer(kalshiDialer)
+	kalshiClient.SetFlapDetection(flapPolicy, onFlap)
+	kalshiClient.SetConnectionHook(onConnection)
+	kalshiClient.SetUpdateHook(observe)
+	if !kalshiClient.IsEnabled() && cfg.KalshiPollInterval > 0 {
+		// Scan-only deployment: public quotes keep the Kalshi side priced
+		kalshiClient.EnablePolling(cfg.KalshiRESTURL, cfg.KalshiPollInterval, apiBudget.Client(arb.VenueKalshi, 10*time.Second))
+		logger.Info("kalshi credentials missing, polling public quotes instead", "interval", cfg.KalshiPollInterval)
+	}
 
-	// Initialize HTTP server
-	server := httpserver.NewServer(cfg.HTTPAddr, engine, logger)
+	// Whole-connection outages are reported by connection status, not per instrument
+	freezes.SetPauseCheck(func(venue string) bool {
+		if venue == instrument.VenueKalshi {
+			return !kalshiClient.IsConnected() || kalshiInMaintenance()
+		}
+		return !pmClient.IsConnected()
+	})
+	if cfg.FreezeScanInterval > 0 {
+		hooks.Go("feed-freeze-scan", func(ctx context.Context) { freezes.Run(ctx, cfg.FreezeScanInterval) })
+	}
+	hooks.Go("kalshi-maintenance", func(ctx context.Context) {
+		maintenance.Watch(ctx,
+			func() {
+				logger.Info("kalshi maintenance window started, pausing kalshi-dependent computation")
+				metrics.SetMaintenanceActive("kalshi", true)
+				kalshiClient.ClearPrices()
+			},
+			func() {
+				logger.Info("kalshi maintenance window ended, reconnecting")
+				metrics.SetMaintenanceActive("kalshi", false)
+				kalshiClient.Reconnect()
+			},
+		)
+	})
 
-	// Start HTTP server in goroutine
-	go func() {
-		if err := server.Start(); err != nil {
-			logger.Error("http server error", "error", err)
+	// Account activity: our own fills and cancels straight from the exchange streams
+	tracker := account.NewTracker(logger)
+	tracker.SetRealizedHook(killSwitch.RecordRealizedPnL)
+	var pmUserClient *ws.PolymarketUserClient
+	if cfg.AccountStreams {
+		if kalshiClient.IsEnabled() {
+			kalshiClient.EnableFills()
+			hooks.Go("kalshi-fills", func(ctx context.Context) { tracker.ConsumeKalshi(ctx, kalshiClient.GetFillChannel()) })
 		}
-	}()
 
-	// Wait for interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
+		creds := ws.PMAPICredentials{APIKey: cfg.PMAPIKey, Secret: cfg.PMAPISecret, Passphrase: cfg.PMAPIPassphrase}
+		if cfg.PMAPICredentialsRef != "" {
+			data, err := secretSource.Fetch(ctx, cfg.PMAPICredentialsRef)
+			if err != nil {
+				logger.Error("failed to load polymarket api credentials", "error", err)
+				os.Exit(1)
+			}
+			err = json.Unmarshal(data, &creds)
+			secrets.Zero(data)
+			if err != nil {
+				logger.Error("failed to parse polymarket api credentials", "error", err)
+				os.Exit(1)
+			}
+		}
+		if !creds.Valid() && cfg.PMWalletKeyPath != "" {
+			creds, err = derivePMCredentials(ctx, cfg, secretSource, apiBudget.Client(arb.VenuePolymarket, 10*time.Second))
+			if err != nil {
+				logger.Warn("failed to derive polymarket api credentials", "error", err)
+			}
+		}
+		if creds.Valid() {
+			pmUserClient, err = ws.NewPolymarketUserClient(ctx, cfg.PMUserWSURL, creds, nil, logger)
+			if err != nil {
+				logger.Error("failed to create polymarket user client", "error", err)
+				os.Exit(1)
+			}
+			pmUserClient.SetDialer(pmDialer)
+			pmUserClient.SetFlapDetection(flapPolicy, onFlap)
+			pmUserClient.SetConnectionHook(onConnection)
+			pmUserClient.Start()
+			defer pmUserClient.Close()
+			tracker.SetPolymarketOwner(creds.APIKey)
+			hooks.Go("polymarket-user-events", func(ctx context.Context) { tracker.ConsumePolymarket(ctx, pmUserClient.GetEventChannel()) })
+		} else {
+			logger.Warn("polymarket api credentials not provided, user channel disabled")
+		}
+	}
 
-	logger.Info("shutting down gracefully")
+	// Smarkets quotes for contracts matched to Kalshi markets, compared
+	// against Kalshi in the status report
+	var smarketsClient *ws.SmarketsClient
+	if len(smarketsMatches) > 0 {
+		smarketsClient = ws.NewSmarketsClient(ctx, cfg.SmarketsWSURL, smarketsMarketIDs(smarketsMatches), logger)
+		smarketsClient.SetSessionToken(cfg.SmarketsSessionToken)
+		smarketsClient.SetFlapDetection(flapPolicy, onFlap)
+		smarketsClient.SetConnectionHook(onConnection)
+		smarketsClient.Start()
+		defer smarketsClient.Close()
+	}
 
-	// Graceful shutdown with timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer shutdownCancel()
+	// Initialize arbitrage engine
+	engine := arb.NewEngine(ctx, pairs, pmClient, kalshiClient, cfg.EdgeMinRORPct, logger)
+	filters, err := arb.BuildFilters(cfg.Filters, filterParams(cfg))
+	if err != nil {
+		logger.Error("invalid filter configuration", "error", err)
+		os.Exit(1)
+	}
+	strategies, err := arb.BuildStrategies(cfg.Strategies, strategyParams(cfg, engine.EdgeThreshold()))
+	if err != nil {
+		logger.Error("invalid strategy configuration", "error", err)
+		os.Exit(1)
+	}
+	engine.SetStrategies(strategies)
+	engine.SetFilters(filters)
+	engine.SetConfidenceHalfLife(cfg.ConfidenceHalfLife)
+	engine.SetExecutionWindow(cfg.ExecutionWindow)
+	engine.SetUnhedgedMinEdge(cfg.UnhedgedMinEdge)
+	engine.SetFees(arb.FeeSchedule{KalshiRate: cfg.KalshiFeeRate, PMRate: cfg.PMFeeRate, PMRelayer: cfg.PMRelayerFee})
+	engine.SetCadence(arb.Cadence{
+		Interval:    cfg.ComputeInterval,
+		MinInterval: cfg.ComputeMinInterval,
+		HotUpdates:  cfg.ComputeHotUpdates,
+	}, quotes)
+	engine.SetRetention(arb.Retention{MaxOpportunities: cfg.MaxOpportunities, MaxAge: cfg.OpportunityRetention})
+	engine.SetKalshiPauseCheck(kalshiInMaintenance)
+	engine.SetAnnotations(annotations)
+	engine.SetPMRules(pmRules)
+	if cfg.NewsMovePct > 0 {
+		// Cross-venue lag around news makes for unfillable quotes and
+		// mismatched resolutions; sit out sharp moves
+		engine.SetNewsWindow(arb.NewNewsWindow(cfg.NewsMovePct, cfg.NewsWindow))
+	}
 
-	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Error("http server shutdown error", "error", err)
+	// Feeds decoding badly, quoting crossed books, going stale or flapping
+	// are trusted less, down to not at all
+	var quality *feedhealth.Quality
+	if cfg.FeedQualityInterval > 0 {
+		quality = feedhealth.NewQuality(feedhealth.QualityOptions{
+			Window:               cfg.FeedQualityWindow,
+			MaxParseFailureRate:  cfg.FeedQualityMaxParseFailurePct / 100,
+			MaxCrossedRate:       cfg.FeedQualityMaxCrossedPct / 100,
+			MaxStaleness:         cfg.FeedQualityMaxStaleness,
+			MaxReconnectsPerHour: cfg.FeedQualityMaxReconnectsPerHour,
+			DegradedBelow:        cfg.FeedQualityDegradedBelow,
+		}, logger)
+		engine.SetFeedWeights(quality.Weight)
+		hooks.Go("feed-quality", func(ctx context.Context) { quality.Run(ctx, cfg.FeedQualityInterval) })
 	}
 
-	logger.Info("shutdown complete")
-}
+	// Kalshi disconnections: hold open streaks meanwhile, then backfill the
+	// gap from the candlestick API
+	if cfg.KalshiBackfillMinGap > 0 {
+		engine.SetKalshiOutageCheck(func() bool { return !kalshiClient.IsConnected() && !kalshiInMaintenance() })
+		kalshiClient.SetRestoreHook(func(lostAt, restoredAt time.Time) {
+			if restoredAt.Sub(lostAt) < cfg.KalshiBackfillMinGap || kalshiInMaintenance() {
+				return
+			}
+			backfillKalshiGap(ctx, apiBudget.Client(arb.VenueKalshi, bootstrapTimeout), cfg.KalshiRESTURL, engine, st, lostAt, restoredAt, logger)
+		})
+	}
 
-// bootstrap fetches markets from both exchanges and creates market pairs
-func bootstrap(ctx context.Context, cfg *config.Config, logger *slog.Logger) ([]arb.MarketPair, []string, []string, error) {
-	// Fetch Polymarket markets
-	logger.Info("fetching polymarket markets")
-	pmMarkets, err := fetchPolymarketMarkets(ctx, logger)
-	if err != nil {
-		return nil, nil, nil, fmt.Errorf("fetch polymarket markets: %w", err)
+	if err := kalshiClient.Start(); err != nil {
+		logger.Error("failed to start kalshi client", "error", err)
+		os.Exit(1)
 	}
-	logger.Info("polymarket markets fetched", "count", len(pmMarkets))
+	defer kalshiClient.Close()
 
-	// Fetch Kalshi markets
-	logger.Info("fetching kalshi markets")
-	kalshiMarkets, err := fetchKalshiMarkets(ctx, logger)
+	// Venue halts: poll market statuses and keep halted pairs out of opportunities
+	halts := arb.NewHaltRegistry()
+	engine.SetHalts(halts)
+
+	// Series outside their trading hours keep stale quotes that would show
+	// as one-sided edges; their pairs are reported as venue closed instead
+	calendar, err := schedule.ParseCalendar(cfg.KalshiTradingHours, cfg.KalshiHolidays, cfg.KalshiTradingHoursTZ)
 	if err != nil {
-		return nil, nil, nil, fmt.Errorf("fetch kalshi markets: %w", err)
+		logger.Error("invalid kalshi trading hours", "error", err)
+		os.Exit(1)
 	}
-	logger.Info("kalshi markets fetched", "count", len(kalshiMarkets))
+	if calendar.Len() > 0 {
+		engine.SetTradingHours(arb.NewTradingHours(calendar.Open))
+		logger.Info("kalshi trading hours loaded", "series", calendar.Len(), "holidays", len(cfg.KalshiHolidays))
+	}
+	var poller *marketstatus.Poller
+	var texts *arb.TextWatch
+	var liquidity *arb.LiquidityBook
+	var resolutions *arb.ResolutionBook
+	if cfg.MarketStatusInterval > 0 {
+		poller = marketstatus.NewPoller(cfg.PMRESTURL, cfg.KalshiRESTURL, pairs, halts, cfg.MarketStatusInterval, logger)
+		poller.SetBudget(apiBudget)
 
-	// Create market pairs using title similarity
-	logger.Info("creating market pairs", "threshold", cfg.TitleSim)
-	pairs := createMarketPairs(pmMarkets, kalshiMarkets, cfg.TitleSim, cfg.TimeWindowH, logger)
+		// The same polls catch venues rewording questions or amending rules;
+		// pairs whose edited title no longer matches are suspended
+		texts = arb.NewTextWatch(pairs, cfg.TitleSim)
+		poller.SetTextWatch(texts)
+		engine.SetTextWatch(texts)
 
-	// Extract token IDs and tickers
-	pmTokenIDs := extractPMTokenIDs(pairs)
-	kalshiTickers := extractKalshiTickers(pairs)
+		// Volume, open interest and liquidity let /pairs rank pairs by
+		// tradability; added pairs are sampled at once
+		liquidity = arb.NewLiquidityBook()
+		poller.SetLiquidity(liquidity, cfg.PMGammaURL)
 
-	return pairs, pmTokenIDs, kalshiTickers, nil
-}
+		// Outcomes of resolved markets settle shadow positions at what the
+		// venues actually paid
+		resolutions = arb.NewResolutionBook()
+		poller.SetResolutions(resolutions)
+		poller.SetPMRules(pmRules)
 
-// fetchPolymarketMarkets fetches open markets from Polymarket REST API
-func fetchPolymarketMarkets(ctx context.Context, logger *slog.Logger) ([]ws.PolymarketMarket, error) {
-	markets := make([]ws.PolymarketMarket, 0)
-	nextCursor := ""
+		hooks.Go("market-status", poller.Run)
+		hooks.OnPairsRefreshed("market-status", func(ctx context.Context, added []arb.MarketPair) error {
+			poller.AddPairs(added)
+			texts.AddPairs(added)
+			return poller.SampleLiquidity(ctx, added)
+		})
+	}
+	if st != nil {
+		engine.OnUpdate(st.StartRecorder(ctx))
 
-	// Follow pagination
-	for {
-		url := "https://clob.polymarket.com/markets"
-		if nextCursor != "" {
-			url = fmt.Sprintf("%s?next_cursor=%s", url, nextCursor)
+		// Full-text search over the monitored pairs, rebuilt as pairs are added
+		reindexPairs := func(ctx context.Context, _ []arb.MarketPair) error {
+			return st.ReindexPairs(ctx, engine.Pairs(), annotations)
 		}
+		hooks.OnBootstrapComplete("search-index", reindexPairs)
+		hooks.OnPairsRefreshed("search-index", reindexPairs)
 
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("create request: %w", err)
+		// Venue mids over time for per-pair spread charts
+		if cfg.SpreadSampleInterval > 0 {
+			hooks.Go("spread-sampler", func(ctx context.Context) {
+				st.SampleSpreads(ctx, engine.SpreadSamples, cfg.SpreadSampleInterval, cfg.SpreadRetention)
+			})
 		}
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("http request: %w", err)
+		// Old history compacted into Parquet in object storage
+		if cfg.ArchiveBucket != "" {
+			archiver, err := newArchiver(ctx, cfg, st, logger)
+			if err != nil {
+				logger.Error("failed to set up archiving", "error", err)
+				os.Exit(1)
+			}
+			hooks.Go("archiver", func(ctx context.Context) {
+				archiver.Run(ctx, cfg.ArchiveInterval)
+			})
 		}
-		defer resp.Body.Close()
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	// Notification and publishing targets: built-in and registered kinds,
+	// plus external plugin processes
+	senders, err := notifySenders(cfg)
+	if err != nil {
+		logger.Error("invalid notification configuration", "error", err)
+		os.Exit(1)
+	}
+	pubs, err := publishers(cfg)
+	if err != nil {
+		logger.Error("invalid publisher configuration", "error", err)
+		os.Exit(1)
+	}
+	for _, p := range pubs {
+		// Publishers holding connections, e.g. to an MQTT broker
+		if c, ok := p.(io.Closer); ok {
+			defer c.Close()
 		}
-
-		var result struct {
-			Data       []ws.PolymarketMarket `json:"data"`
-			NextCursor string                `json:"next_cursor"`
+	}
+	plugins, err := launchPlugins(ctx, cfg, logger)
+	for _, p := range plugins {
+		defer p.Close()
+		if p.CanObserveLifecycle() {
+			p.RegisterLifecycle(hooks)
+		}
+		if p.CanNotify() {
+			senders = append(senders, p)
+		}
+		if p.CanPublish() {
+			pubs = append(pubs, p)
 		}
+	}
+	if err != nil {
+		logger.Error("failed to launch plugin", "error", err)
+		os.Exit(1)
+	}
 
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, fmt.Errorf("decode response: %w", err)
+	for _, p := range pubs {
+		engine.OnUpdate(notify.PublishUpdates(ctx, p, cfg.NotifyTimeout, logger))
+	}
+
+	var textAlerts func(arb.TextChange)
+	if texts != nil {
+		texts.OnChange(func(c arb.TextChange) {
+			logger.Warn("paired market text changed",
+				"venue", c.Venue,
+				"market", c.Market,
+				"field", c.Field,
+				"reason", c.Reason,
+				"similarity", fmt.Sprintf("%.2f", c.Similarity),
+				"pairs", len(c.Pairs),
+				"suspended", c.Suspended,
+			)
+			if textAlerts != nil {
+				textAlerts(c)
+			}
+		})
+	}
+
+	// Notifications are delivered on their own worker pool so slow
+	// destinations can never stall the engine's update hooks
+	if len(senders) > 0 {
+		dispatcher := notify.NewDispatcher(senders, notify.Options{
+			Workers:        cfg.NotifyWorkers,
+			QueueSize:      cfg.NotifyQueueSize,
+			Timeout:        cfg.NotifyTimeout,
+			DeadLetterPath: cfg.NotifyDeadLetterPath,
+		}, logger)
+		if cfg.NotifySuppressAcked {
+			dispatcher.SetSuppress(func(n notify.Notification) bool {
+				_, acked := engine.Acknowledged(n.OppID)
+				return n.OppID != "" && acked
+			})
 		}
+		dispatcher.Start(ctx)
 
-		// Filter for active/open markets
-		for _, m := range result.Data {
-			if m.Active && !m.Closed {
-				markets = append(markets, m)
+		// Opportunities alerted before a restart stay deduped through their
+		// cooldown, so a deploy doesn't re-fire every open alert
+		alertOpts := notify.AlertOptions{Cooldown: cfg.NotifyAlertCooldown}
+		if st != nil {
+			cutoff := time.Now().Add(-cfg.NotifyAlertCooldown)
+			if err := st.PruneAlerts(ctx, cutoff); err != nil {
+				logger.Warn("failed to prune alert history", "error", err)
 			}
+			alerted, err := st.AlertedSince(ctx, cutoff)
+			if err != nil {
+				logger.Warn("failed to load alert history", "error", err)
+			}
+			alertOpts.Alerted = alerted
+			alertOpts.Record = st.StartAlertRecorder(ctx)
+			logger.Info("alert history loaded", "alerted", len(alerted), "cooldown", cfg.NotifyAlertCooldown)
+		}
+		engine.OnUpdate(notify.OpportunityAlerts(dispatcher, alertOpts))
+		textAlerts = notify.TextChangeAlerts(dispatcher)
+		logger.Info("notifications enabled", "senders", len(senders), "workers", cfg.NotifyWorkers)
+	}
+
+	// A best edge rising too fast usually means a parsing bug, stale feed
+	// or mismatched pair
+	if cfg.EdgeJumpAlertPP > 0 {
+		jumps := arb.NewEdgeJumpDetector(cfg.EdgeJumpAlertPP)
+		jumps.OnJump(func(j arb.EdgeJump) {
+			logger.Warn("best edge jumped",
+				"from_pct", fmt.Sprintf("%.2f", j.FromPct),
+				"to_pct", fmt.Sprintf("%.2f", j.ToPct),
+				"opp_id", j.Opportunity.ID,
+				"kalshi_ticker", j.Opportunity.KalshiTicker,
+				"previous_id", j.PreviousID,
+			)
+		})
+		if opsDispatcher != nil {
+			jumps.OnJump(notify.EdgeJumpAlerts(opsDispatcher))
 		}
+		engine.OnUpdate(jumps.Observe)
+	}
 
-		nextCursor = result.NextCursor
-		if nextCursor == "" {
-			break
+	// Healthy feeds and many pairs but nothing near the threshold for long
+	// usually means a units or normalization bug, not efficient markets
+	var silence *arb.SilenceDetector
+	if cfg.SilenceAlarmAfter > 0 {
+		silence = arb.NewSilenceDetector(arb.SilenceOptions{
+			After:    cfg.SilenceAlarmAfter,
+			MarginPP: cfg.SilenceMarginPP,
+			MinPairs: cfg.SilenceMinPairs,
+		}, engine.EdgeThreshold(), func() bool {
+			return pmClient.IsConnected() && kalshiClient.IsConnected()
+		})
+		silence.OnAlarm(func(s arb.Silence) {
+			logger.Warn("no opportunity or near-threshold edge for too long",
+				"since", s.Since,
+				"evaluated", s.Evaluated,
+				"best_edge_pct", fmt.Sprintf("%.2f", s.BestEdgePct),
+				"threshold_pct", fmt.Sprintf("%.2f", s.ThresholdPct),
+			)
+		})
+		silence.OnClear(func(s arb.Silence) {
+			logger.Info("engine silence ended", "since", s.Since, "best_edge_pct", fmt.Sprintf("%.2f", s.BestEdgePct))
+		})
+		if opsDispatcher != nil {
+			silence.OnAlarm(notify.SilenceAlerts(opsDispatcher))
 		}
+		engine.OnUpdate(func([]arb.Opportunity) { silence.Observe(engine.Snapshot()) })
+	}
 
-		logger.Debug("polymarket pagination", "fetched", len(markets), "next_cursor", nextCursor)
+	// Shadow ledgers: every strategy paper-trades on the same market data
+	var shadows *shadow.Shadow
+	if cfg.ShadowTrading {
+		names := make([]string, 0, len(strategies))
+		for _, strategy := range strategies {
+			names = append(names, strategy.Name())
+		}
+		shadows = shadow.New(names, shadow.Options{
+			Size:          cfg.ShadowSize,
+			LiveStrategy:  cfg.ExecutionStrategy,
+			PMFeeRate:     cfg.PMFeeRate,
+			KalshiFeeRate: cfg.KalshiFeeRate,
+		}, logger)
+		if resolutions != nil {
+			shadows.SetResolutions(resolutions)
+		}
+		if st != nil {
+			shadows.OnSettle(func(s shadow.Settlement) {
+				if err := st.RecordSettlement(ctx, s); err != nil {
+					logger.Error("failed to record settlement", "opp_id", s.OppID, "error", err)
+				}
+			})
+		}
+		engine.OnUpdate(func(opps []arb.Opportunity) {
+			shadows.Observe(opps, engine.Prices(), time.Now())
+		})
+		logger.Info("shadow trading enabled", "strategies", names, "live_strategy", cfg.ExecutionStrategy)
 	}
 
-	return markets, nil
-}
+	// Threshold calibration: each category's minimum edge against what paper
+	// trading its past streaks realized
+	var calibrator *calibration.Calibrator
+	if st != nil && shadows != nil && cfg.CalibrationInterval > 0 {
+		calibrator = calibration.New(st.CalibrationSamples, engine.EdgeThreshold(), calibration.Options{
+			Window:     cfg.CalibrationWindow,
+			MinSamples: cfg.CalibrationMinSamples,
+			Apply:      cfg.CalibrationApply,
+		}, logger)
+		hooks.Go("threshold-calibration", func(ctx context.Context) { calibrator.Run(ctx, cfg.CalibrationInterval) })
+	}
 
-// fetchKalshiMarkets fetches open markets from Kalshi REST API
-func fetchKalshiMarkets(ctx context.Context, logger *slog.Logger) ([]ws.KalshiMarket, error) {
-	markets := make([]ws.KalshiMarket, 0)
-	cursor := ""
+	if warm != nil {
+		pmQuotes, kalshiQuotes := engine.ImportWarmState(*warm)
+		logger.Info("warm start imported",
+			"peer", cfg.WarmStartURL,
+			"exported_at", warm.ExportedAt,
+			"pairs", len(warm.Pairs),
+			"pm_quotes", pmQuotes,
+			"kalshi_quotes", kalshiQuotes,
+			"opportunities", len(warm.Opportunities),
+		)
+	}
 
-	// Follow pagination
-	for {
-		url := "https://api.elections.kalshi.com/trade-api/v2/markets?status=open&limit=1000"
-		if cursor != "" {
-			url = fmt.Sprintf("%s&cursor=%s", url, cursor)
-		}
+	engine.Start()
 
-		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("create request: %w", err)
+	// Second bootstrap phase: match the long tail while already scanning,
+	// subscribing to and monitoring each batch of pairs once confirmed
+	activatePairs := func(added []arb.MarketPair) {
+		if added = admitPairs(pruner, added, logger); len(added) == 0 {
+			return
 		}
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("http request: %w", err)
+		pmRules.AddPairs(added)
+		engine.AddPairs(added)
+		kalshiClient.AddTickers(extractKalshiTickers(added))
+		if err := pmClient.AddTokens(extractPMTokenIDs(added)); err != nil {
+			// The tokens are kept and subscribed on the next reconnect
+			logger.Warn("failed to subscribe to new polymarket tokens", "error", err)
 		}
-		defer resp.Body.Close()
+		apiBudget.SetSubscriptions(arb.VenuePolymarket, pmClient.TokenCount())
+		apiBudget.SetSubscriptions(arb.VenueKalshi, len(extractKalshiTickers(engine.Pairs())))
+		hooks.PairsRefreshed(ctx, added)
+	}
+	// Demo mode: synthetic pairs with injected mispricings
+	if cfg.Demo {
+		demo := arb.NewDemo(engine, arb.DemoOptions{
+			Pairs:    cfg.DemoPairs,
+			Interval: cfg.DemoInterval,
+			Hold:     cfg.DemoHold,
+			EdgePct:  cfg.DemoEdgePct,
+		})
+		hooks.Go("demo", demo.Run)
+		logger.Warn("demo mode: injecting synthetic mispriced quotes",
+			"pairs", len(demo.Pairs()), "interval", cfg.DemoInterval, "edge_pct", cfg.DemoEdgePct)
+	}
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	hooks.Go("match-backlog", func(ctx context.Context) { backlog.Run(ctx, cfg, activatePairs, logger) })
+
+	// Initialize HTTP server
+	server := httpserver.NewServer(cfg.HTTPAddr, engine, logger)
+	routeTimeouts, err := httpserver.ParseRouteTimeouts(cfg.HTTPRouteTimeouts)
+	if err != nil {
+		logger.Error("invalid HTTP route timeouts", "error", err)
+		os.Exit(1)
+	}
+	server.SetLimits(httpserver.Limits{
+		RequestTimeout:    cfg.HTTPRequestTimeout,
+		RouteTimeouts:     routeTimeouts,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		MaxHeaderBytes:    cfg.HTTPMaxHeaderBytes,
+		MaxBodyBytes:      cfg.HTTPMaxBodyBytes,
+	})
+	listeners := httpserver.Listeners{Admin: cfg.HTTPAdminAddr, Metrics: cfg.HTTPMetricsAddr}
+	if err := listeners.Validate(cfg.HTTPAddr); err != nil {
+		logger.Error("invalid HTTP listeners", "error", err)
+		os.Exit(1)
+	}
+	server.SetListeners(listeners)
+	if st != nil {
+		server.SetStore(st)
+	}
+	if nearMisses != nil {
+		server.SetNearMisses(nearMisses, activatePairs)
+	}
+	if texts != nil {
+		server.SetTextWatch(texts)
+	}
+	if pruner != nil {
+		server.SetPruner(pruner)
+	}
+	server.SetAnnotations(annotations)
+	server.SetLiquidity(liquidity)
+	server.SetPMRules(pmRules)
+	server.RegisterStatus("compliance", func() interface{} { return complianceStatus })
+	server.SetAccount(tracker)
+	server.SetKillSwitch(killSwitch, cfg.AdminToken)
+	server.RegisterStatus("profile", func() interface{} { return cfg.Profile })
+	server.RegisterStatus("kill_switch", func() interface{} { return killSwitch.State() })
+	server.RegisterStatus("api_budget", func() interface{} { return apiBudget.Usage() })
+	if shadows != nil {
+		server.RegisterStatus("shadow", func() interface{} { return shadows.Records() })
+		server.RegisterStatus("shadow_pnl", func() interface{} { return shadows.Attribution() })
+	}
+	server.RegisterStatus("pairs", func() interface{} { return engine.Snapshot().Pairs })
+	server.RegisterStatus("compute", func() interface{} { return engine.Conflation() })
+	if silence != nil {
+		server.RegisterStatus("silence", func() interface{} { return silence.Status() })
+	}
+	server.RegisterStatus("retention", func() interface{} {
+		return map[string]interface{}{
+			"max_opportunities": cfg.MaxOpportunities,
+			"max_age":           cfg.OpportunityRetention.String(),
+			"truncated":         engine.Truncated(),
 		}
+	})
+	server.RegisterStatus("bootstrap", func() interface{} { return backlog.Status() })
+	if calibrator != nil {
+		server.RegisterStatus("calibration", func() interface{} { return calibrator.Last() })
+	}
+	server.RegisterStatus("pair_collisions", func() interface{} { return collisions.Status() })
+	server.RegisterStatus("feed_freezes", func() interface{} { return freezes.Frozen() })
+	if quality != nil {
+		server.RegisterStatus("feed_quality", func() interface{} { return quality.Scores() })
+	}
+	server.RegisterStatus("maintenance", func() interface{} {
+		return map[string]schedule.Status{"kalshi": maintenance.StatusAt(time.Now())}
+	})
+	connections := func() interface{} {
+		conns := map[string]interface{}{
+			"polymarket": map[string]bool{"connected": pmClient.IsConnected()},
+			"kalshi":     map[string]bool{"enabled": kalshiClient.IsEnabled(), "polling": kalshiClient.IsPolling(), "connected": kalshiClient.IsConnected()},
+		}
+		if pmUserClient != nil {
+			conns["polymarket_user"] = map[string]bool{"connected": pmUserClient.IsConnected()}
+		}
+		if smarketsClient != nil {
+			conns["smarkets"] = map[string]bool{"connected": smarketsClient.IsConnected()}
+		}
+		return conns
+	}
+	server.RegisterStatus("connections", connections)
+	if smarketsClient != nil {
+		server.RegisterStatus("smarkets", func() interface{} {
+			return compareSmarkets(smarketsMatches, smarketsClient, kalshiClient)
+		})
+	}
 
-		var result struct {
-			Markets []ws.KalshiMarket `json:"markets"`
-			Cursor  string            `json:"cursor"`
+	// SIGUSR1 dumps state and goroutine stacks to a file, for postmortems
+	// when the HTTP server no longer answers
+	dumper := diag.New(cfg.DiagDumpDir)
+	dumper.Register("connections", connections)
+	dumper.Register("pairs", func() interface{} {
+		return map[string]int{
+			"monitored":         len(engine.Pairs()),
+			"halted_polymarket": halts.Count(arb.VenuePolymarket),
+			"halted_kalshi":     halts.Count(arb.VenueKalshi),
 		}
+	})
+	dumper.Register("price_cache", func() interface{} {
+		return map[string]interface{}{
+			"polymarket": quoteCacheStats(pmClient.Quotes(), func(q ws.PMPriceUpdate) time.Time { return q.UpdatedAt }),
+			"kalshi":     quoteCacheStats(kalshiClient.Quotes(), func(q ws.KalshiPriceUpdate) time.Time { return q.UpdatedAt }),
+		}
+	})
+	dumper.Register("opportunities", func() interface{} { return engine.Snapshot().Opportunities })
+	dumper.Register("status", func() interface{} { return server.Status() })
+	hooks.Go("diag", func(ctx context.Context) { dumper.Run(ctx, logger) })
 
-		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-			return nil, fmt.Errorf("decode response: %w", err)
+	hooks.Go("http", func(context.Context) {
+		if err := server.Start(); err != nil {
+			logger.Error("http server error", "error", err)
 		}
+	})
+	hooks.OnShutdown("http", server.Shutdown)
 
-		markets = append(markets, result.Markets...)
+	// Start every registered subsystem
+	if err := hooks.BootstrapComplete(ctx, engine.Pairs()); err != nil {
+		logger.Error("startup hook failed", "error", err)
+		os.Exit(1)
+	}
 
-		cursor = result.Cursor
-		if cursor == "" {
-			break
-		}
+	// Tell systemd we're up, then keep its watchdog fed only while the engine
+	// keeps completing compute cycles, so a stalled engine gets restarted
+	if ok, err := service.Notify(service.StateReady); err != nil {
+		logger.Warn("sd_notify ready failed", "error", err)
+	} else if ok {
+		logger.Info("notified service manager of readiness")
+	}
+	startedAt := time.Now()
+	hooks.Go("watchdog", func(ctx context.Context) {
+		service.RunWatchdog(ctx, func() bool {
+			last := engine.LastCompute()
+			if last.IsZero() {
+				last = startedAt
+			}
+			return time.Since(last) < cfg.EngineStallTimeout
+		}, logger)
+	})
+
+	// Wait for shutdown request
+	<-shutdown.Done()
+
+	logger.Info("shutting down gracefully")
+	service.Notify(service.StateStopping)
 
-		logger.Debug("kalshi pagination", "fetched", len(markets), "cursor", cursor)
+	// Graceful shutdown with timeout
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	if err := hooks.Shutdown(shutdownCtx); err != nil {
+		logger.Error("shutdown error", "error", err)
 	}
 
-	return markets, nil
+	logger.Info("shutdown complete")
 }
 
-// createMarketPairs matches markets between exchanges using title similarity
-func createMarketPairs(pmMarkets []ws.PolymarketMarket, kalshiMarkets []ws.KalshiMarket, threshold float64, timeWindowH int, logger *slog.Logger) []arb.MarketPair {
-	pairs := make([]arb.MarketPair, 0)
-	timeWindow := time.Duration(timeWindowH) * time.Hour
+// bootstrapTimeout bounds each catalog request during bootstrap
+const bootstrapTimeout = 60 * time.Second
 
-	for _, pm := range pmMarkets {
-		for _, k := range kalshiMarkets {
-			// Check title similarity
-			if !match.IsLikelyMatch(pm.Question, k.Title, threshold) {
-				continue
-			}
+// bootstrap fetches markets from both exchanges and creates market pairs.
+// With BOOTSTRAP_TOP_K set, only enough of the most liquid Kalshi markets are
+// matched to yield that many pairs; the rest are returned as a backlog to
+// match in the background.
+func bootstrap(ctx context.Context, cfg *config.Config, apiBudget *budget.Accountant, nearMisses *arb.NearMissRegistry, collisions *pairCollisions, logger *slog.Logger) ([]arb.MarketPair, *matchBacklog, []match.SmarketsMatch, error) {
+	// Fetch Polymarket markets
+	logger.Info("fetching polymarket markets")
+	pmMarkets, err := catalog.FetchPolymarketMarkets(ctx, apiBudget.Client(arb.VenuePolymarket, bootstrapTimeout), cfg.PMRESTURL, logger)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("fetch polymarket markets: %w", err)
+	}
+	logger.Info("polymarket markets fetched", "count", len(pmMarkets))
 
-			// Soft deadline check if timestamps available
-			if pm.EndDateISO != "" && k.ExpirationTime != "" {
-				pmEnd, err1 := time.Parse(time.RFC3339, pm.EndDateISO)
-				kEnd, err2 := time.Parse(time.RFC3339, k.ExpirationTime)
-
-				if err1 == nil && err2 == nil {
-					diff := pmEnd.Sub(kEnd)
-					if diff < 0 {
-						diff = -diff
-					}
-					if diff > timeWindow {
-						continue // Expiration times too far apart
-					}
-				}
-			}
+	// Fetch Kalshi markets
+	logger.Info("fetching kalshi markets")
+	kalshiMarkets, err := catalog.FetchKalshiMarkets(ctx, apiBudget.Client(arb.VenueKalshi, bootstrapTimeout), cfg.KalshiRESTURL, logger)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("fetch kalshi markets: %w", err)
+	}
+	logger.Info("kalshi markets fetched", "count", len(kalshiMarkets))
 
-			// Find YES and NO tokens for Polymarket
-			var yesTokenID, noTokenID string
-			for _, token := range pm.Tokens {
-				if token.Outcome == "YES" {
-					yesTokenID = token.TokenID
-				} else if token.Outcome == "NO" {
-					noTokenID = token.TokenID
-				}
-			}
+	var smarkets []match.SmarketsMatch
+	if cfg.SmarketsEnabled {
+		smarkets = bootstrapSmarkets(ctx, cfg, apiBudget, kalshiMarkets, logger)
+	}
 
-			if yesTokenID == "" || noTokenID == "" {
-				logger.Debug("skipping pm market without yes/no tokens", "question", pm.Question)
-				continue
-			}
+	// Create market pairs using title similarity
+	logger.Info("creating market pairs", "threshold", cfg.TitleSim, "top_k", cfg.BootstrapTopK)
+	if cfg.BootstrapTopK <= 0 {
+		return createMarketPairs(pmMarkets, kalshiMarkets, cfg, nearMisses, collisions, logger), nil, smarkets, nil
+	}
 
-			pair := arb.MarketPair{
-				PMTokenYes:   yesTokenID,
-				PMTokenNo:    noTokenID,
-				PMTitle:      pm.Question,
-				KalshiTicker: k.Ticker,
-				KalshiTitle:  k.Title,
-			}
+	pairs, rest := matchTopK(pmMarkets, kalshiMarkets, cfg, nearMisses, collisions, logger)
+	return pairs, newMatchBacklog(pmMarkets, rest, nearMisses, collisions), smarkets, nil
+}
 
-			pairs = append(pairs, pair)
-			logger.Debug("market pair created",
-				"pm_title", pm.Question,
-				"kalshi_title", k.Title,
-				"similarity", fmt.Sprintf("%.2f", match.TitleSimilarity(pm.Question, k.Title)),
-			)
+// createMarketPairs matches markets between exchanges using title similarity.
+// Markets matched beyond their allowance keep their best matches, see
+// pairCollisions. Pairs scoring just below the threshold are recorded as
+// near-misses; those an operator has promoted are returned alongside the
+// matches, whatever their markets' allowance.
+func createMarketPairs(pmMarkets []ws.PolymarketMarket, kalshiMarkets []ws.KalshiMarket, cfg *config.Config, nearMisses *arb.NearMissRegistry, collisions *pairCollisions, logger *slog.Logger) []arb.MarketPair {
+	opts := match.MatchOptions{
+		Threshold:  cfg.TitleSim,
+		TimeWindow: time.Duration(cfg.TimeWindowH) * time.Hour,
+	}
+	if nearMisses != nil {
+		opts.NearMissFloor = cfg.NearMissMinSim
+	}
+	matches, near := match.MatchMarketsWithNearMisses(pmMarkets, kalshiMarkets, opts)
+	matches = collisions.resolve(matches)
+
+	now := time.Now()
+	for _, m := range near {
+		if pair := newMarketPair(m); nearMisses.Observe(pair, now) {
+			matches = append(matches, m)
 		}
 	}
 
+	pairs := make([]arb.MarketPair, 0, len(matches))
+	for _, m := range matches {
+		pair := newMarketPair(m)
+		pairs = append(pairs, pair)
+		logger.Debug("market pair created",
+			"pm_title", pair.PMTitle,
+			"kalshi_title", pair.KalshiTitle,
+			"pm_instrument", pair.PMYes(),
+			"kalshi_instrument", pair.KalshiYes(),
+			"similarity", fmt.Sprintf("%.2f", m.Score),
+			"resolution_source_differs", pair.ResolutionSourceDiffers,
+		)
+	}
+
 	return pairs
 }
 
+// newMarketPair builds the monitored pair for a match
+func newMarketPair(m match.MarketMatch) arb.MarketPair {
+	pm, k := m.PM, m.Kalshi
+	pmDeadlines, kalshiDeadlines := match.PolymarketDeadlines(pm), match.KalshiDeadlines(k)
+	pmResolution, kalshiResolution := match.PolymarketResolution(pm), match.KalshiResolution(k)
+	return arb.MarketPair{
+		PMConditionID: pm.ConditionID,
+		PMTokenYes:    m.PMTokenYes,
+		PMTokenNo:     m.PMTokenNo,
+		PMTitle:       pm.Question,
+		KalshiTicker:  k.Ticker,
+		KalshiTitle:   k.Title,
+		Category:      match.Category(pm, k),
+		MatchScore:    m.Score,
+		Inverted:      m.Inverted,
+		Liquidity:     price.FromCents(k.Liquidity),
+
+		PMTickSize:     pm.MinimumTickSize,
+		PMMinOrderSize: pm.MinimumOrderSize,
+		PMNegRisk:      pm.NegRisk,
+
+		ResolutionSourceDiffers: match.ResolutionSourcesDiffer(pmResolution, kalshiResolution),
+
+		Meta: arb.MarketMetadata{
+			PMCloseTime:        timePtr(pmDeadlines.Close),
+			PMResolutionTime:   timePtr(pmDeadlines.Resolution),
+			KalshiEventTicker:  k.EventTicker,
+			KalshiStrikeType:   k.StrikeType,
+			KalshiFloorStrike:  k.FloorStrike,
+			KalshiCapStrike:    k.CapStrike,
+			KalshiCloseTime:    timePtr(kalshiDeadlines.Close),
+			KalshiVolume:       k.Volume,
+			KalshiVolume24h:    k.Volume24h,
+			KalshiOpenInterest: k.OpenInterest,
+
+			KalshiResolutionTime: timePtr(kalshiDeadlines.Resolution),
+
+			PMOracle:                pmResolution.Oracle,
+			PMResolutionSources:     pmResolution.Sources,
+			KalshiOracle:            kalshiResolution.Oracle,
+			KalshiResolutionSources: kalshiResolution.Sources,
+		},
+	}
+}
+
+// quoteCacheStats summarizes a venue's price cache: its size and the age of
+// its oldest and newest quotes
+func quoteCacheStats[Q any](quotes []Q, updatedAt func(Q) time.Time) map[string]interface{} {
+	stats := map[string]interface{}{"quotes": len(quotes)}
+	var oldest, newest time.Time
+	for _, q := range quotes {
+		at := updatedAt(q)
+		if oldest.IsZero() || at.Before(oldest) {
+			oldest = at
+		}
+		if at.After(newest) {
+			newest = at
+		}
+	}
+	if len(quotes) > 0 {
+		stats["oldest_age"] = time.Since(oldest).String()
+		stats["newest_age"] = time.Since(newest).String()
+	}
+	return stats
+}
+
+// timePtr returns a pointer to t, or nil for the zero time
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 // extractPMTokenIDs extracts all Polymarket token IDs from pairs
 func extractPMTokenIDs(pairs []arb.MarketPair) []string {
 	tokenSet := make(map[string]struct{})
@@ -320,6 +1159,31 @@ func extractPMTokenIDs(pairs []arb.MarketPair) []string {
 	return tokens
 }
 
+// admitPairs returns the pairs the pruner admits, logging each pair pruned;
+// a nil pruner admits all
+func admitPairs(pruner *arb.SubscriptionPruner, pairs []arb.MarketPair, logger *slog.Logger) []arb.MarketPair {
+	if pruner == nil {
+		return pairs
+	}
+	admitted, pruned := pruner.Admit(pairs)
+	if len(pruned) == 0 {
+		return admitted
+	}
+	for _, p := range pruned {
+		logger.Info("pair pruned to fit subscription budget",
+			"venue", p.Venue,
+			"pm_condition_id", p.PMConditionID,
+			"pm_title", p.PMTitle,
+			"kalshi_ticker", p.KalshiTicker,
+			"liquidity", p.Liquidity,
+			"match_score", fmt.Sprintf("%.2f", p.MatchScore),
+		)
+	}
+	logger.Warn("matched pairs exceed websocket subscription budget, pruned the worst",
+		"admitted", len(admitted), "pruned", len(pruned))
+	return admitted
+}
+
 // extractKalshiTickers extracts all Kalshi tickers from pairs
 func extractKalshiTickers(pairs []arb.MarketPair) []string {
 	tickerSet := make(map[string]struct{})
@@ -333,3 +1197,185 @@ func extractKalshiTickers(pairs []arb.MarketPair) []string {
 	}
 	return tickers
 }
+
+// newDialers builds the Polymarket and Kalshi websocket dialers
+func newDialers(cfg *config.Config) (pm, kalshi *websocket.Dialer, err error) {
+	pm, err = ws.NewDialer(ws.DialOptions{
+		Compression:      cfg.PMWSCompression,
+		DisableNoDelay:   !cfg.PMWSNoDelay,
+		LocalAddr:        cfg.PMWSLocalAddr,
+		Interface:        cfg.PMWSInterface,
+		HandshakeTimeout: cfg.PMWSHandshakeTimeout,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("polymarket: %w", err)
+	}
+	kalshi, err = ws.NewDialer(ws.DialOptions{
+		Compression:      cfg.KalshiWSCompression,
+		DisableNoDelay:   !cfg.KalshiWSNoDelay,
+		LocalAddr:        cfg.KalshiWSLocalAddr,
+		Interface:        cfg.KalshiWSInterface,
+		HandshakeTimeout: cfg.KalshiWSHandshakeTimeout,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("kalshi: %w", err)
+	}
+	return pm, kalshi, nil
+}
+
+// filterParams are the opportunity filter settings
+func filterParams(cfg *config.Config) arb.FilterParams {
+	return arb.FilterParams{
+		MinSize:                 cfg.FilterMinSize,
+		MinLiquidity:            cfg.FilterMinLiquidity,
+		MaxQuoteAge:             cfg.FilterMaxQuoteAge,
+		Categories:              cfg.FilterCategories,
+		MinResolutionConfidence: cfg.FilterMinResolutionConfidence,
+		MinConfidence:           cfg.FilterMinConfidence,
+		MinAnnualizedReturn:     cfg.FilterMinAnnualizedReturn,
+		Rule:                    cfg.FilterRule,
+	}
+}
+
+// strategyParams are the strategy settings, sharing the engine's adjustable
+// edge threshold
+func strategyParams(cfg *config.Config, threshold *arb.Threshold) arb.StrategyParams {
+	return arb.StrategyParams{
+		EdgeThreshold:        threshold,
+		MinDivergence:        cfg.DivergenceMin,
+		SpreadCaptureMinEdge: cfg.SpreadCaptureMinRORPct,
+	}
+}
+
+// loadKalshiKey loads the Kalshi private key from the secrets provider.
+// Returns nil (Kalshi disabled) when credentials are missing or unusable.
+func loadKalshiKey(ctx context.Context, cfg *config.Config, source secrets.Source, logger *slog.Logger) *rsa.PrivateKey {
+	if cfg.KalshiKeyID == "" || cfg.KalshiKeyPath == "" {
+		logger.Warn("kalshi credentials not provided, kalshi websocket disabled")
+		return nil
+	}
+
+	key, err := readKalshiKey(ctx, cfg, source)
+	if err != nil {
+		logger.Warn("failed to load kalshi private key, kalshi websocket disabled", "error", err)
+		return nil
+	}
+	return key
+}
+
+// readKalshiKey fetches and parses the Kalshi private key, zeroing the raw
+// PEM afterwards
+func readKalshiKey(ctx context.Context, cfg *config.Config, source secrets.Source) (*rsa.PrivateKey, error) {
+	data, err := source.Fetch(ctx, cfg.KalshiKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer secrets.Zero(data)
+
+	key, err := ws.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	return key, nil
+}
+
+// derivePMCredentials obtains the Polymarket API credentials of the wallet
+// whose key is configured
+func derivePMCredentials(ctx context.Context, cfg *config.Config, source secrets.Source, client *http.Client) (ws.PMAPICredentials, error) {
+	wallet, err := readPMWallet(ctx, cfg, source)
+	if err != nil {
+		return ws.PMAPICredentials{}, err
+	}
+	signer, err := auth.NewPolymarketL1Signer(wallet, uint64(cfg.PMAPINonce), auth.NewClock(0))
+	if err != nil {
+		return ws.PMAPICredentials{}, err
+	}
+	return ws.DerivePMAPICredentials(ctx, client, cfg.PMRESTURL, signer)
+}
+
+// readPMWallet fetches and parses the Polymarket wallet key, zeroing the raw
+// key afterwards
+func readPMWallet(ctx context.Context, cfg *config.Config, source secrets.Source) (*auth.KeyWallet, error) {
+	data, err := source.Fetch(ctx, cfg.PMWalletKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	defer secrets.Zero(data)
+
+	wallet, err := auth.ParseWalletKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse wallet key: %w", err)
+	}
+	return wallet, nil
+}
+
+// notifySenders builds a sender for every notification destination configured,
+// through the sender registry so custom kinds work the same way
+func notifySenders(cfg *config.Config) ([]notify.Sender, error) {
+	specs := append([]string(nil), cfg.NotifySenders...)
+	if cfg.NotifyWebhookURL != "" {
+		specs = append(specs, "webhook?url="+url.QueryEscape(cfg.NotifyWebhookURL))
+	}
+	if cfg.NotifySlackWebhookURL != "" {
+		specs = append(specs, "slack?webhook_url="+url.QueryEscape(cfg.NotifySlackWebhookURL))
+	}
+	if cfg.NotifyTelegramToken != "" && cfg.NotifyTelegramChatID != "" {
+		specs = append(specs, "telegram?bot_token="+url.QueryEscape(cfg.NotifyTelegramToken)+
+			"&chat_id="+url.QueryEscape(cfg.NotifyTelegramChatID))
+	}
+
+	return buildSenders(specs)
+}
+
+// buildSenders builds a sender for each "kind?key=value" spec
+func buildSenders(specs []string) ([]notify.Sender, error) {
+	senders := make([]notify.Sender, 0, len(specs))
+	for _, spec := range specs {
+		kind, settings, err := notify.ParseSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		sender, err := notify.NewSender(kind, settings)
+		if err != nil {
+			return nil, err
+		}
+		senders = append(senders, sender)
+	}
+	return senders, nil
+}
+
+// publishers builds every registered publisher kind configured in PUBLISHERS
+func publishers(cfg *config.Config) ([]notify.Publisher, error) {
+	out := make([]notify.Publisher, 0, len(cfg.Publishers))
+	for _, spec := range cfg.Publishers {
+		kind, settings, err := notify.ParseSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		p, err := notify.NewPublisher(kind, settings)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// launchPlugins starts every external plugin configured in PLUGINS
+// ("name=/path/to/plugin [args...]")
+func launchPlugins(ctx context.Context, cfg *config.Config, logger *slog.Logger) ([]*plugin.Plugin, error) {
+	plugins := make([]*plugin.Plugin, 0, len(cfg.Plugins))
+	for _, spec := range cfg.Plugins {
+		name, command, ok := strings.Cut(spec, "=")
+		fields := strings.Fields(command)
+		if !ok || name == "" || len(fields) == 0 {
+			return plugins, fmt.Errorf("invalid plugin spec %q, want name=/path/to/plugin [args...]", spec)
+		}
+		p, err := plugin.Launch(ctx, strings.TrimSpace(name), fields[0], fields[1:], logger)
+		if err != nil {
+			return plugins, err
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
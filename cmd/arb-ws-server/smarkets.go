@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/budget"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/catalog"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/match"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// smarketsComparison is a Smarkets contract's quote next to its Kalshi match
+type smarketsComparison struct {
+	ContractID    string  `json:"contract_id"`
+	Title         string  `json:"title"`
+	KalshiTicker  string  `json:"kalshi_ticker"`
+	KalshiTitle   string  `json:"kalshi_title"`
+	MatchScore    float64 `json:"match_score"`
+	YesBid        float64 `json:"smarkets_yes_bid"`
+	YesAsk        float64 `json:"smarkets_yes_ask"`
+	ImpliedYes    float64 `json:"smarkets_implied_yes"`
+	KalshiImplied float64 `json:"kalshi_implied_yes"`
+	Divergence    float64 `json:"implied_divergence"` // Smarkets minus Kalshi
+}
+
+// bootstrapSmarkets fetches the Smarkets catalog and matches its contracts to
+// Kalshi markets. Smarkets is supplementary: failures are logged and leave it
+// disabled rather than failing bootstrap.
+func bootstrapSmarkets(ctx context.Context, cfg *config.Config, apiBudget *budget.Accountant, kalshiMarkets []ws.KalshiMarket, logger *slog.Logger) []match.SmarketsMatch {
+	logger.Info("fetching smarkets contracts", "type_domains", cfg.SmarketsTypeDomains)
+	contracts, err := catalog.FetchSmarketsContracts(ctx, apiBudget.Client(instrument.VenueSmarkets, bootstrapTimeout),
+		cfg.SmarketsRESTURL, cfg.SmarketsTypeDomains, logger)
+	if err != nil {
+		logger.Warn("failed to fetch smarkets contracts, smarkets disabled", "error", err)
+		return nil
+	}
+
+	matches := match.MatchSmarkets(contracts, kalshiMarkets, cfg.TitleSim)
+	logger.Info("smarkets contracts matched", "contracts", len(contracts), "matches", len(matches))
+	return matches
+}
+
+// smarketsMarketIDs returns the distinct Smarkets markets of the matches
+func smarketsMarketIDs(matches []match.SmarketsMatch) []string {
+	seen := make(map[string]bool)
+	ids := make([]string, 0)
+	for _, m := range matches {
+		if !seen[m.Smarkets.MarketID] {
+			seen[m.Smarkets.MarketID] = true
+			ids = append(ids, m.Smarkets.MarketID)
+		}
+	}
+	return ids
+}
+
+// compareSmarkets reports the vig-free probability of each matched contract
+// on Smarkets and Kalshi, largest divergence first. Contracts without quotes
+// on both venues are left out.
+func compareSmarkets(matches []match.SmarketsMatch, smarkets *ws.SmarketsClient, kalshi *ws.KalshiClient) []smarketsComparison {
+	out := make([]smarketsComparison, 0, len(matches))
+	for _, m := range matches {
+		sq, ok := smarkets.GetQuote(m.Smarkets.Instrument())
+		if !ok {
+			continue
+		}
+		kq, ok := kalshi.GetQuote(instrument.KalshiYes(m.Kalshi.Ticker))
+		if !ok {
+			continue
+		}
+		smImplied, ok := price.ImpliedProbability(sq.YesAsk, sq.NoAsk)
+		if !ok {
+			continue
+		}
+		kImplied, ok := price.ImpliedProbability(kq.YesAsk, kq.NoAsk)
+		if !ok {
+			continue
+		}
+
+		out = append(out, smarketsComparison{
+			ContractID:    m.Smarkets.ID,
+			Title:         m.Smarkets.Title(),
+			KalshiTicker:  m.Kalshi.Ticker,
+			KalshiTitle:   m.Kalshi.Title,
+			MatchScore:    m.Score,
+			YesBid:        sq.YesBid,
+			YesAsk:        sq.YesAsk,
+			ImpliedYes:    smImplied,
+			KalshiImplied: kImplied,
+			Divergence:    smImplied - kImplied,
+		})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return math.Abs(out[i].Divergence) > math.Abs(out[j].Divergence) })
+	return out
+}
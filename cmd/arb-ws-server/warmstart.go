@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// fetchWarmState pulls a running peer's warm-start snapshot
+func fetchWarmState(ctx context.Context, peerURL, token string, maxAge time.Duration) (arb.WarmState, error) {
+	ctx, cancel := context.WithTimeout(ctx, bootstrapTimeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(peerURL, "/") + "/admin/warmstart"
+	if maxAge > 0 {
+		endpoint += "?max_age=" + url.QueryEscape(maxAge.String())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return arb.WarmState{}, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return arb.WarmState{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return arb.WarmState{}, fmt.Errorf("peer returned %s", resp.Status)
+	}
+
+	var state arb.WarmState
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return arb.WarmState{}, fmt.Errorf("decode warm state: %w", err)
+	}
+	return state, nil
+}
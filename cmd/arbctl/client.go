@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// errUsage reports invalid command arguments
+var errUsage = errors.New("invalid arguments")
+
+// client calls the server API and prints responses
+type client struct {
+	base   string
+	token  string
+	http   *http.Client
+	asJSON bool // Print raw JSON responses instead of tables
+}
+
+func newClient(base, token string, timeout time.Duration, asJSON bool) *client {
+	return &client{
+		base:   strings.TrimSuffix(base, "/"),
+		token:  token,
+		http:   &http.Client{Timeout: timeout},
+		asJSON: asJSON,
+	}
+}
+
+// get fetches path with query parameters, decoding the JSON response into out
+func (c *client) get(ctx context.Context, path string, query url.Values, out interface{}) ([]byte, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// post sends body as JSON to path, decoding the JSON response into out
+func (c *client) post(ctx context.Context, path string, body, out interface{}) ([]byte, error) {
+	return c.do(ctx, http.MethodPost, path, body, out)
+}
+
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: %s", method, path, apiError(resp.StatusCode, data))
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return nil, fmt.Errorf("decode %s response: %w", path, err)
+		}
+	}
+	return data, nil
+}
+
+// apiError extracts the message of an {"error": ...} response
+func apiError(status int, data []byte) string {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		return fmt.Sprintf("%d %s", status, body.Error)
+	}
+	return fmt.Sprintf("%d %s", status, strings.TrimSpace(string(data)))
+}
+
+// printJSON writes a raw response indented
+func printJSON(data []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(os.Stdout)
+	return err
+}
+
+// table writes tab-separated rows as aligned columns
+type table struct {
+	w *tabwriter.Writer
+}
+
+func newTable(header ...string) *table {
+	t := &table{w: tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)}
+	t.row(toAny(header)...)
+	return t
+}
+
+func (t *table) row(cells ...interface{}) {
+	parts := make([]string, len(cells))
+	for i, c := range cells {
+		switch v := c.(type) {
+		case float64:
+			parts[i] = fmt.Sprintf("%.2f", v)
+		case time.Time:
+			if v.IsZero() {
+				parts[i] = "-"
+			} else {
+				parts[i] = v.Local().Format("2006-01-02 15:04:05")
+			}
+		default:
+			parts[i] = fmt.Sprint(v)
+		}
+	}
+	fmt.Fprintln(t.w, strings.Join(parts, "\t"))
+}
+
+func (t *table) flush() error {
+	return t.w.Flush()
+}
+
+func toAny(s []string) []interface{} {
+	out := make([]interface{}, len(s))
+	for i, v := range s {
+		out[i] = v
+	}
+	return out
+}
+
+// truncate shortens s to n runes for table cells
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/killswitch"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// parseFlags parses a subcommand's flags, reporting errors as errUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(new(strings.Builder))
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
+
+func runArbs(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("arbs", flag.ContinueOnError)
+	strategy := fs.String("strategy", "", "only this strategy's opportunities")
+	tag := fs.String("tag", "", "only pairs annotated with this tag")
+	minConfidence := fs.String("min-confidence", "", "confidence floor, 0-1")
+	minAnnualized := fs.String("min-annualized-return", "", "annualized return floor, percent")
+	sortKey := fs.String("sort", "", "sort key, e.g. annualized_return or return_on_capital")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for name, v := range map[string]string{
+		"strategy": *strategy, "tag": *tag, "min_confidence": *minConfidence,
+		"min_annualized_return": *minAnnualized, "sort": *sortKey,
+	} {
+		if v != "" {
+			query.Set(name, v)
+		}
+	}
+	var opps []arb.Opportunity
+	data, err := c.get(ctx, "/arbs", query, &opps)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	t := newTable("ID", "STRATEGY", "COMBO", "EDGE %", "ANNUAL %", "COST", "SIZE", "CONFIDENCE", "KALSHI", "POLYMARKET")
+	for _, o := range opps {
+		annual := "-"
+		if o.AnnualizedReturn != nil {
+			annual = strconv.FormatFloat(*o.AnnualizedReturn, 'f', 1, 64)
+		}
+		t.row(o.ID, o.Strategy, o.Combo, o.EdgePctTurn, annual, o.TotalCost, o.MaxSize, o.Confidence, o.KalshiTicker, truncate(o.PMTitle, 50))
+	}
+	return t.flush()
+}
+
+func runPairs(ctx context.Context, c *client, args []string) error {
+	sub := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub, args = args[0], args[1:]
+	}
+
+	switch sub {
+	case "list":
+		return listPairs(ctx, c, args)
+	case "halted":
+		return listHaltedPairs(ctx, c)
+	case "pruned":
+		return listPrunedPairs(ctx, c)
+	case "near-misses":
+		return listNearMisses(ctx, c, args)
+	case "approve":
+		return decideNearMiss(ctx, c, args, arb.NearMissPromoted)
+	case "dismiss":
+		return decideNearMiss(ctx, c, args, arb.NearMissDismissed)
+	default:
+		return fmt.Errorf("%w: unknown pairs command %q", errUsage, sub)
+	}
+}
+
+func listPairs(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("pairs", flag.ContinueOnError)
+	sortKey := fs.String("sort", "", "sort key, e.g. volume_24h, liquidity or match_score")
+	tag := fs.String("tag", "", "only pairs annotated with this tag")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *sortKey != "" {
+		query.Set("sort", *sortKey)
+	}
+	if *tag != "" {
+		query.Set("tag", *tag)
+	}
+	var resp struct {
+		Pairs []arb.PairListing `json:"pairs"`
+	}
+	data, err := c.get(ctx, "/pairs", query, &resp)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	t := newTable("KALSHI", "CATEGORY", "MATCH", "VOLUME 24H", "LIQUIDITY", "POLYMARKET")
+	for _, p := range resp.Pairs {
+		volume, liquidity := "-", "-"
+		if p.Snapshot != nil {
+			volume = strconv.FormatFloat(p.Snapshot.Volume24h(), 'f', 0, 64)
+			liquidity = strconv.FormatFloat(p.Snapshot.Liquidity(), 'f', 0, 64)
+		}
+		t.row(p.KalshiTicker, p.Category, p.MatchScore, volume, liquidity, truncate(p.PMTitle, 50))
+	}
+	return t.flush()
+}
+
+func listHaltedPairs(ctx context.Context, c *client) error {
+	var resp struct {
+		Pairs []arb.HaltedPair `json:"pairs"`
+	}
+	data, err := c.get(ctx, "/pairs/halted", nil, &resp)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	t := newTable("KALSHI", "VENUE", "MARKET", "STATUS", "SINCE")
+	for _, p := range resp.Pairs {
+		for _, h := range p.Halts {
+			t.row(p.KalshiTicker, h.Venue, truncate(h.Market, 20), h.Status, h.Since)
+		}
+	}
+	return t.flush()
+}
+
+func listPrunedPairs(ctx context.Context, c *client) error {
+	var resp struct {
+		Pairs []arb.PrunedPair `json:"pairs"`
+	}
+	data, err := c.get(ctx, "/pairs/pruned", nil, &resp)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	t := newTable("KALSHI", "VENUE", "MATCH", "LIQUIDITY", "POLYMARKET")
+	for _, p := range resp.Pairs {
+		t.row(p.KalshiTicker, p.Venue, p.MatchScore, strconv.FormatFloat(p.Liquidity, 'f', 0, 64), truncate(p.PMTitle, 50))
+	}
+	return t.flush()
+}
+
+func listNearMisses(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("near-misses", flag.ContinueOnError)
+	status := fs.String("status", "", "only candidates in this status: pending, promoted or dismissed")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *status != "" {
+		query.Set("status", *status)
+	}
+	var resp struct {
+		NearMisses []arb.NearMiss `json:"near_misses"`
+	}
+	data, err := c.get(ctx, "/pairs/near-misses", query, &resp)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	t := newTable("ID", "SCORE", "STATUS", "KALSHI", "POLYMARKET")
+	for _, n := range resp.NearMisses {
+		t.row(n.ID, n.Score, n.Status, n.KalshiTicker, truncate(n.PMTitle, 50))
+	}
+	return t.flush()
+}
+
+func decideNearMiss(ctx context.Context, c *client, args []string, status string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected a near-miss ID", errUsage)
+	}
+
+	var resp struct {
+		NearMiss  arb.NearMiss `json:"near_miss"`
+		Monitored bool         `json:"monitored"`
+	}
+	data, err := c.post(ctx, "/pairs/near-misses/"+url.PathEscape(args[0]), map[string]string{"status": status}, &resp)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	fmt.Printf("%s %s: %q <> %q\n", resp.NearMiss.ID, resp.NearMiss.Status, resp.NearMiss.PMTitle, resp.NearMiss.KalshiTitle)
+	if resp.Monitored {
+		fmt.Println("pair is now monitored")
+	}
+	return nil
+}
+
+func runThreshold(ctx context.Context, c *client, args []string) error {
+	var resp struct {
+		EdgeMinRORPct float64 `json:"edge_min_ror_pct"`
+	}
+	var data []byte
+	var err error
+
+	switch {
+	case len(args) == 0 || (len(args) == 1 && args[0] == "get"):
+		data, err = c.get(ctx, "/admin/threshold", nil, &resp)
+	case len(args) == 2 && args[0] == "set":
+		pct, perr := strconv.ParseFloat(args[1], 64)
+		if perr != nil {
+			return fmt.Errorf("%w: invalid threshold %q", errUsage, args[1])
+		}
+		data, err = c.post(ctx, "/admin/threshold", map[string]float64{"edge_min_ror_pct": pct}, &resp)
+	default:
+		return errUsage
+	}
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	fmt.Printf("edge threshold: %g%% ROI on turnover\n", resp.EdgeMinRORPct)
+	return nil
+}
+
+func runPause(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("pause", flag.ContinueOnError)
+	reason := fs.String("reason", "paused via arbctl", "why execution is paused")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	return setKillSwitch(ctx, c, true, *reason)
+}
+
+func runResume(ctx context.Context, c *client, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	return setKillSwitch(ctx, c, false, "")
+}
+
+func setKillSwitch(ctx context.Context, c *client, engaged bool, reason string) error {
+	var state killswitch.State
+	body := map[string]interface{}{"engaged": engaged, "reason": reason}
+	data, err := c.post(ctx, "/admin/killswitch", body, &state)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	if !state.Engaged {
+		fmt.Println("kill switch released")
+		return nil
+	}
+	fmt.Printf("kill switch engaged (%s): %s\n", state.Source, state.Reason)
+	return nil
+}
+
+func runStatus(ctx context.Context, c *client, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	// Sections are registered by subsystems and vary, so status is JSON only
+	data, err := c.get(ctx, "/status", nil, nil)
+	return printOr(data, err)
+}
+
+func runSearch(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	limit := fs.Int("limit", 20, "maximum hits of each kind")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: expected search words", errUsage)
+	}
+
+	query := url.Values{"q": {strings.Join(fs.Args(), " ")}, "limit": {strconv.Itoa(*limit)}}
+	var resp store.SearchResults
+	data, err := c.get(ctx, "/search", query, &resp)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	fmt.Println("pairs:")
+	t := newTable("KALSHI", "CATEGORY", "TAGS", "POLYMARKET")
+	for _, p := range resp.Pairs {
+		t.row(p.KalshiTicker, p.Category, strings.Join(p.Tags, ","), truncate(p.PMTitle, 50))
+	}
+	if err := t.flush(); err != nil {
+		return err
+	}
+
+	fmt.Println("\nopportunities:")
+	t = newTable("ID", "FIRST SEEN", "LAST SEEN", "PEAK EDGE %", "AVG EDGE %", "KALSHI", "POLYMARKET")
+	for _, o := range resp.Opportunities {
+		t.row(o.OppID, o.FirstSeen, o.LastSeen, o.PeakEdgePct, o.AvgEdgePct, o.KalshiTicker, truncate(o.PMTitle, 50))
+	}
+	return t.flush()
+}
+
+func runAudit(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	window := fs.String("window", "", "only entries this recent, e.g. 24h or 7d")
+	action := fs.String("action", "", "only this action, e.g. killswitch.engage")
+	limit := fs.Int("limit", 50, "maximum entries")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	query := url.Values{"limit": {strconv.Itoa(*limit)}}
+	if *window != "" {
+		query.Set("window", *window)
+	}
+	if *action != "" {
+		query.Set("action", *action)
+	}
+	var resp struct {
+		Entries []store.AuditEntry `json:"entries"`
+	}
+	data, err := c.get(ctx, "/admin/audit", query, &resp)
+	if err != nil || c.asJSON {
+		return printOr(data, err)
+	}
+
+	t := newTable("AT", "ACTOR", "ACTION", "TARGET", "AFTER")
+	for _, e := range resp.Entries {
+		t.row(e.At, e.Actor, e.Action, truncate(e.Target, 40), truncate(string(e.After), 60))
+	}
+	return t.flush()
+}
+
+// printOr prints a JSON response, or returns the request's error
+func printOr(data []byte, err error) error {
+	if err != nil {
+		return err
+	}
+	return printJSON(data)
+}
@@ -0,0 +1,104 @@
+// Command arbctl is an operator client for the arb-ws-server HTTP API. It
+// wraps the read and admin endpoints so routine operations don't need
+// hand-crafted curl.
+//
+//	arbctl arbs -strategy arbitrage
+//	arbctl pairs -sort volume_24h
+//	arbctl pairs approve 3f9a...
+//	arbctl threshold set 4.5
+//	arbctl pause -reason "venue incident"
+//	arbctl -o json status
+//
+// The server address and admin token default to $ARBCTL_ADDR and
+// $ARBCTL_TOKEN (falling back to $ADMIN_TOKEN).
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// command is an arbctl subcommand, run with its remaining arguments
+type command struct {
+	usage string
+	run   func(ctx context.Context, c *client, args []string) error
+}
+
+var commands = map[string]command{
+	"arbs":      {"arbs [-strategy s] [-tag t] [-min-confidence c] [-min-annualized-return r] [-sort k]   list open opportunities", runArbs},
+	"pairs":     {"pairs [list|halted|pruned|near-misses|approve <id>|dismiss <id>]   monitored pairs and near-miss review", runPairs},
+	"threshold": {"threshold [get|set <pct>]   show or change the arbitrage edge threshold", runThreshold},
+	"pause":     {"pause [-reason r]   engage the kill switch", runPause},
+	"resume":    {"resume   release the kill switch", runResume},
+	"status":    {"status   show the server status", runStatus},
+	"search":    {"search <words...>   full-text search over pairs and opportunity history", runSearch},
+	"audit":     {"audit [-window d] [-action a] [-limit n]   show the admin audit log", runAudit},
+}
+
+// commandOrder is the order commands are listed in the usage
+var commandOrder = []string{"arbs", "pairs", "threshold", "pause", "resume", "status", "search", "audit"}
+
+func main() {
+	token := os.Getenv("ARBCTL_TOKEN")
+	if token == "" {
+		token = os.Getenv("ADMIN_TOKEN")
+	}
+
+	addr := flag.String("addr", envOr("ARBCTL_ADDR", "http://localhost:8080"), "server base URL")
+	flag.StringVar(&token, "token", token, "admin bearer token")
+	output := flag.String("o", "table", "output format: table or json")
+	timeout := flag.Duration("timeout", 10*time.Second, "request timeout")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "arbctl: unknown output format %q\n", *output)
+		os.Exit(2)
+	}
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "arbctl: unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	c := newClient(*addr, token, *timeout, *output == "json")
+	if err := cmd.run(ctx, c, flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "arbctl:", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "usage: arbctl", cmd.usage)
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: arbctl [flags] <command> [args]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, name := range commandOrder {
+		fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nflags:")
+	flag.PrintDefaults()
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
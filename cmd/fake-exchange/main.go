@@ -0,0 +1,402 @@
+// Command fake-exchange serves Polymarket-like and Kalshi-like REST and
+// WebSocket endpoints backed by synthetic random-walk markets, so the whole
+// stack can be developed and demoed offline.
+//
+// Point arb-ws-server at it with:
+//
+//	PM_REST_URL=http://localhost:9090/pm
+//	PM_WS_URL=ws://localhost:9090/pm/ws/
+//	KALSHI_REST_URL=http://localhost:9090/kalshi/trade-api/v2
+//	KALSHI_WS_URL=ws://localhost:9090/kalshi/trade-api/ws/v2
+//
+// The Kalshi endpoint accepts any signed handshake, so any RSA key works for
+// KALSHI_KEY_ID / KALSHI_PRIVATE_KEY_PATH.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const pmPageSize = 100
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	numMarkets := flag.Int("markets", 25, "number of synthetic markets (ignored with -markets-file)")
+	marketsFile := flag.String("markets-file", "", "JSON file with a list of market specs")
+	interval := flag.Duration("interval", time.Second, "price update interval")
+	volatility := flag.Float64("volatility", 0.01, "per-step standard deviation of the latent probability")
+	spread := flag.Float64("spread", 0.02, "bid/ask spread on each venue")
+	skew := flag.Float64("skew", 0.04, "max independent per-venue deviation from the latent probability")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	rng := rand.New(rand.NewSource(*seed))
+	specs, err := loadSpecs(*marketsFile, *numMarkets, rng)
+	if err != nil {
+		logger.Error("failed to load market specs", "error", err)
+		os.Exit(1)
+	}
+
+	ex := newExchange(specs, rng, *volatility, *spread, *skew)
+	logger.Info("fake exchange starting", "addr", *addr, "markets", len(ex.markets), "seed", *seed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		ticker := time.NewTicker(*interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ex.step()
+			}
+		}
+	}()
+
+	s := &fakeServer{ex: ex, interval: *interval, logger: logger}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pm/markets", s.handlePMMarkets)
+	mux.HandleFunc("/pm/markets/{id}", s.handlePMMarket)
+	mux.HandleFunc("/pm/ws/", s.handlePMWS)
+	mux.HandleFunc("/gamma/markets", s.handleGammaMarkets)
+	mux.HandleFunc("/kalshi/trade-api/v2/markets", s.handleKalshiMarkets)
+	mux.HandleFunc("/kalshi/trade-api/v2/series/{series}/markets/{ticker}/candlesticks", s.handleKalshiCandlesticks)
+	mux.HandleFunc("/kalshi/trade-api/ws/v2", s.handleKalshiWS)
+
+	server := &http.Server{Addr: *addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("http server error", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	<-sigChan
+
+	cancel()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	server.Shutdown(shutdownCtx)
+}
+
+type fakeServer struct {
+	ex       *exchange
+	interval time.Duration
+	logger   *slog.Logger
+	upgrader websocket.Upgrader
+}
+
+type pmToken struct {
+	TokenID string `json:"token_id"`
+	Outcome string `json:"outcome"`
+	Price   string `json:"price"`
+}
+
+type pmMarket struct {
+	ConditionID      string    `json:"condition_id"`
+	QuestionID       string    `json:"question_id"`
+	Question         string    `json:"question"`
+	Tokens           []pmToken `json:"tokens"`
+	Tags             []string  `json:"tags"`
+	Active           bool      `json:"active"`
+	Closed           bool      `json:"closed"`
+	AcceptingOrders  bool      `json:"accepting_orders"`
+	EndDateISO       string    `json:"end_date_iso"`
+	MinimumTickSize  float64   `json:"minimum_tick_size"`
+	MinimumOrderSize float64   `json:"minimum_order_size"`
+}
+
+type kalshiMarket struct {
+	Ticker         string  `json:"ticker"`
+	Title          string  `json:"title"`
+	Status         string  `json:"status"`
+	Category       string  `json:"category"`
+	YesBid         float64 `json:"yes_bid"`
+	YesAsk         float64 `json:"yes_ask"`
+	CloseTime      string  `json:"close_time"`
+	ExpirationTime string  `json:"expiration_time"`
+	EventTicker    string  `json:"event_ticker"`
+	Volume24h      float64 `json:"volume_24h"`
+	OpenInterest   float64 `json:"open_interest"`
+	Liquidity      float64 `json:"liquidity"` // Cents
+}
+
+// handlePMMarkets serves a paginated CLOB-style market list; the cursor is a plain offset
+func (s *fakeServer) handlePMMarkets(w http.ResponseWriter, r *http.Request) {
+	offset, _ := strconv.Atoi(r.URL.Query().Get("next_cursor"))
+	markets := s.ex.snapshot()
+
+	end := offset + pmPageSize
+	if end > len(markets) {
+		end = len(markets)
+	}
+	if offset > end {
+		offset = end
+	}
+
+	data := make([]pmMarket, 0, end-offset)
+	for _, m := range markets[offset:end] {
+		data = append(data, toPMMarket(m))
+	}
+
+	nextCursor := ""
+	if end < len(markets) {
+		nextCursor = strconv.Itoa(end)
+	}
+
+	writeJSON(w, map[string]interface{}{"data": data, "next_cursor": nextCursor})
+}
+
+// handlePMMarket serves a single market by condition ID
+func (s *fakeServer) handlePMMarket(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	for _, m := range s.ex.snapshot() {
+		if m.conditionID == id {
+			writeJSON(w, toPMMarket(m))
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func toPMMarket(m market) pmMarket {
+	return pmMarket{
+		ConditionID: m.conditionID,
+		QuestionID:  m.conditionID,
+		Question:    m.spec.Title,
+		Tokens: []pmToken{
+			{TokenID: m.pmYesToken, Outcome: "YES", Price: formatPrice(m.pmYesAsk)},
+			{TokenID: m.pmNoToken, Outcome: "NO", Price: formatPrice(m.pmNoAsk)},
+		},
+		Tags:            []string{m.spec.Category},
+		Active:          true,
+		AcceptingOrders: true,
+		EndDateISO:      m.expiration.Format(time.RFC3339),
+
+		MinimumTickSize:  0.01,
+		MinimumOrderSize: 5,
+	}
+}
+
+// handleKalshiMarkets serves all markets in one page
+func (s *fakeServer) handleKalshiMarkets(w http.ResponseWriter, r *http.Request) {
+	markets := s.ex.snapshot()
+
+	out := make([]kalshiMarket, 0, len(markets))
+	for _, m := range markets {
+		out = append(out, kalshiMarket{
+			Ticker:         m.kalshiTicker,
+			Title:          m.spec.KalshiTitle,
+			Status:         "open",
+			Category:       m.spec.Category,
+			YesBid:         m.kYesBid,
+			YesAsk:         m.kYesAsk,
+			CloseTime:      m.expiration.Format(time.RFC3339),
+			ExpirationTime: m.expiration.Format(time.RFC3339),
+			EventTicker:    "FAKEEVENT-" + m.spec.Category,
+			Volume24h:      m.volume24h,
+			OpenInterest:   3 * m.volume24h,
+			Liquidity:      m.volume24h * 20,
+		})
+	}
+
+	writeJSON(w, map[string]interface{}{"markets": out, "cursor": ""})
+}
+
+// handleGammaMarkets serves Gamma-style market activity for the requested
+// condition IDs. Liquidity is a fixed fifth of the daily volume.
+func (s *fakeServer) handleGammaMarkets(w http.ResponseWriter, r *http.Request) {
+	wanted := make(map[string]bool)
+	for _, id := range r.URL.Query()["condition_ids"] {
+		wanted[id] = true
+	}
+
+	out := make([]map[string]interface{}, 0, len(wanted))
+	for _, m := range s.ex.snapshot() {
+		if wanted[m.conditionID] {
+			out = append(out, map[string]interface{}{
+				"conditionId":  m.conditionID,
+				"volume24hr":   m.volume24h,
+				"liquidityNum": m.volume24h / 5,
+			})
+		}
+	}
+	writeJSON(w, out)
+}
+
+// handleKalshiCandlesticks serves one-minute candlesticks over the requested
+// range. No history is kept, so every candle carries the current quote.
+func (s *fakeServer) handleKalshiCandlesticks(w http.ResponseWriter, r *http.Request) {
+	ticker := r.PathValue("ticker")
+	start, _ := strconv.ParseInt(r.URL.Query().Get("start_ts"), 10, 64)
+	end, _ := strconv.ParseInt(r.URL.Query().Get("end_ts"), 10, 64)
+
+	candles := make([]map[string]interface{}, 0)
+	for _, m := range s.ex.snapshot() {
+		if m.kalshiTicker != ticker {
+			continue
+		}
+		for ts := (start/60 + 1) * 60; ts <= end; ts += 60 {
+			candles = append(candles, map[string]interface{}{
+				"end_period_ts": ts,
+				"yes_bid":       map[string]float64{"close": math.Round(m.kYesBid * 100)},
+				"yes_ask":       map[string]float64{"close": math.Round(m.kYesAsk * 100)},
+			})
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{"ticker": ticker, "candlesticks": candles})
+}
+
+// handlePMWS streams book snapshots for subscribed asset IDs
+func (s *fakeServer) handlePMWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Warn("pm ws upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	subscribed := make(map[string]struct{})
+	subCh := make(chan []string, 16)
+	go func() {
+		defer close(subCh)
+		for {
+			var msg struct {
+				AssetsIDs []string `json:"assets_ids"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			subCh <- msg.AssetsIDs
+		}
+	}()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ids, ok := <-subCh:
+			if !ok {
+				return
+			}
+			for _, id := range ids {
+				subscribed[id] = struct{}{}
+			}
+		case <-ticker.C:
+			for _, m := range s.ex.snapshot() {
+				quotes := []struct {
+					token    string
+					bid, ask float64
+				}{
+					{m.pmYesToken, m.pmYesBid, m.pmYesAsk},
+					{m.pmNoToken, m.pmNoBid, m.pmNoAsk},
+				}
+				for _, q := range quotes {
+					if _, ok := subscribed[q.token]; !ok {
+						continue
+					}
+					// A book snapshot per tick replaces every level, so
+					// levels the quote moved away from don't linger
+					msg := map[string]any{
+						"event_type": "book",
+						"market":     m.conditionID,
+						"asset_id":   q.token,
+						"bids":       []map[string]string{{"price": formatPrice(q.bid), "size": "100"}},
+						"asks":       []map[string]string{{"price": formatPrice(q.ask), "size": "100"}},
+					}
+					if err := conn.WriteJSON(msg); err != nil {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// handleKalshiWS streams ticker updates for every market once subscribed
+func (s *fakeServer) handleKalshiWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.logger.Warn("kalshi ws upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	subCh := make(chan struct{}, 16)
+	go func() {
+		defer close(subCh)
+		for {
+			var msg struct {
+				Type    string `json:"type"`
+				Channel string `json:"channel"`
+			}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Type == "subscribe" && msg.Channel == "ticker" {
+				subCh <- struct{}{}
+			}
+		}
+	}()
+
+	subscribed := false
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case _, ok := <-subCh:
+			if !ok {
+				return
+			}
+			subscribed = true
+		case <-ticker.C:
+			if !subscribed {
+				continue
+			}
+			for _, m := range s.ex.snapshot() {
+				msg := map[string]interface{}{
+					"type":    "ticker",
+					"channel": "ticker",
+					"ticker":  m.kalshiTicker,
+					"yes_bid": m.kYesBid,
+					"yes_ask": m.kYesAsk,
+				}
+				if err := conn.WriteJSON(msg); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func formatPrice(p float64) string {
+	return strconv.FormatFloat(p, 'f', 2, 64)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// MarketSpec describes one synthetic market listed on both fake venues
+type MarketSpec struct {
+	Title       string  `json:"title"`
+	KalshiTitle string  `json:"kalshi_title,omitempty"` // defaults to Title
+	Category    string  `json:"category,omitempty"`
+	Fair        float64 `json:"fair,omitempty"` // starting probability, random when zero
+	Days        int     `json:"days,omitempty"` // days until expiration, defaults to 30
+}
+
+// market is the simulated state of a single paired market
+type market struct {
+	spec         MarketSpec
+	conditionID  string
+	pmYesToken   string
+	pmNoToken    string
+	kalshiTicker string
+	expiration   time.Time
+	volume24h    float64 // Daily volume, in USD on Polymarket and contracts on Kalshi
+
+	fair     float64 // latent "true" probability driving both venues
+	pmYesBid float64
+	pmYesAsk float64
+	pmNoBid  float64
+	pmNoAsk  float64
+	kYesBid  float64
+	kYesAsk  float64
+}
+
+// exchange holds all simulated markets and advances their prices
+type exchange struct {
+	mu         sync.RWMutex
+	markets    []*market
+	rng        *rand.Rand
+	volatility float64
+	spread     float64
+	skew       float64
+}
+
+// Word lists for generated titles; see distinctTitles
+var (
+	adjectives = []string{"Amber", "Brisk", "Cobalt", "Dusty", "Eager", "Frosty", "Golden", "Hollow", "Ivory", "Jade", "Keen", "Lunar", "Misty", "Noble", "Onyx", "Polar"}
+	nouns      = []string{"Falcon", "Harbor", "Comet", "Lantern", "Meadow", "Raven", "Summit", "Tundra", "Beacon", "Canyon", "Glacier", "Orchid", "Quarry", "Thistle", "Willow", "Zephyr"}
+	categories = []string{"Politics", "Economics", "Sports", "Crypto", "Weather"}
+	goals      = []string{"index", "rally", "vote", "launch", "merger", "rating", "census", "tariff", "strike", "summit", "budget", "ruling", "auction", "treaty", "harvest", "derby"}
+)
+
+// loadSpecs reads market specs from a JSON file or generates n random ones
+func loadSpecs(path string, n int, rng *rand.Rand) ([]MarketSpec, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read markets file: %w", err)
+		}
+		var specs []MarketSpec
+		if err := json.Unmarshal(data, &specs); err != nil {
+			return nil, fmt.Errorf("parse markets file: %w", err)
+		}
+		return specs, nil
+	}
+
+	specs := make([]MarketSpec, 0, n)
+	for i, words := range distinctTitles(n) {
+		specs = append(specs, MarketSpec{
+			Title:    fmt.Sprintf("Will the %s %s win the %s?", words[0], words[1], words[2]),
+			Category: categories[i%len(categories)],
+			Fair:     0.1 + rng.Float64()*0.8,
+			Days:     1 + rng.Intn(90),
+		})
+	}
+	return specs, nil
+}
+
+// distinctTitles picks up to n word triples that share at most one word with
+// every other triple, keeping generated titles below the default match threshold
+func distinctTitles(n int) [][3]string {
+	chosen := make([][3]int, 0, n)
+	for a := 0; a < len(adjectives) && len(chosen) < n; a++ {
+		for b := 0; b < len(nouns) && len(chosen) < n; b++ {
+			for c := 0; c < len(goals) && len(chosen) < n; c++ {
+				ok := true
+				for _, t := range chosen {
+					shared := 0
+					if t[0] == a {
+						shared++
+					}
+					if t[1] == b {
+						shared++
+					}
+					if t[2] == c {
+						shared++
+					}
+					if shared > 1 {
+						ok = false
+						break
+					}
+				}
+				if ok {
+					chosen = append(chosen, [3]int{a, b, c})
+				}
+			}
+		}
+	}
+
+	titles := make([][3]string, len(chosen))
+	for i, t := range chosen {
+		titles[i] = [3]string{adjectives[t[0]], nouns[t[1]], goals[t[2]]}
+	}
+	return titles
+}
+
+// newExchange builds the simulated venues from market specs
+func newExchange(specs []MarketSpec, rng *rand.Rand, volatility, spread, skew float64) *exchange {
+	ex := &exchange{
+		rng:        rng,
+		volatility: volatility,
+		spread:     spread,
+		skew:       skew,
+	}
+
+	for i, spec := range specs {
+		if spec.KalshiTitle == "" {
+			spec.KalshiTitle = spec.Title
+		}
+		if spec.Fair <= 0 || spec.Fair >= 1 {
+			spec.Fair = 0.1 + rng.Float64()*0.8
+		}
+		if spec.Days <= 0 {
+			spec.Days = 30
+		}
+
+		m := &market{
+			spec:         spec,
+			conditionID:  fmt.Sprintf("0xfake%060d", i+1),
+			pmYesToken:   fmt.Sprintf("%d", 1000000+2*i),
+			pmNoToken:    fmt.Sprintf("%d", 1000000+2*i+1),
+			kalshiTicker: fmt.Sprintf("FAKE-%04d", i+1),
+			expiration:   time.Now().Add(time.Duration(spec.Days) * 24 * time.Hour).UTC().Truncate(time.Hour),
+			fair:         spec.Fair,
+			volume24h:    math.Round(1000 + ex.rng.Float64()*99000),
+		}
+		ex.quote(m)
+		ex.markets = append(ex.markets, m)
+	}
+
+	return ex
+}
+
+// step advances every market's latent probability by a bounded random walk
+func (ex *exchange) step() {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+
+	for _, m := range ex.markets {
+		m.fair += ex.rng.NormFloat64() * ex.volatility
+		m.fair = math.Min(0.97, math.Max(0.03, m.fair))
+		ex.quote(m)
+	}
+}
+
+// quote derives both venues' books from the latent probability. Each venue sees
+// independent noise of up to skew, so the two occasionally disagree enough to arb.
+func (ex *exchange) quote(m *market) {
+	half := ex.spread / 2
+
+	pmMid := clampPrice(m.fair + (ex.rng.Float64()*2-1)*ex.skew)
+	m.pmYesBid = clampPrice(pmMid - half)
+	m.pmYesAsk = clampPrice(pmMid + half)
+	m.pmNoBid = clampPrice(1 - m.pmYesAsk)
+	m.pmNoAsk = clampPrice(1 - m.pmYesBid)
+
+	kMid := clampPrice(m.fair + (ex.rng.Float64()*2-1)*ex.skew)
+	m.kYesBid = clampPrice(kMid - half)
+	m.kYesAsk = clampPrice(kMid + half)
+}
+
+// snapshot returns copies of all markets for rendering outside the lock
+func (ex *exchange) snapshot() []market {
+	ex.mu.RLock()
+	defer ex.mu.RUnlock()
+
+	out := make([]market, len(ex.markets))
+	for i, m := range ex.markets {
+		out[i] = *m
+	}
+	return out
+}
+
+// clampPrice rounds to a whole cent within the tradable range
+func clampPrice(p float64) float64 {
+	p = math.Round(p*100) / 100
+	return math.Min(0.99, math.Max(0.01, p))
+}
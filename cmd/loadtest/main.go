@@ -0,0 +1,146 @@
+// Command loadtest replays consumer traffic against a running arb-ws-server
+// and reports latency and throughput per endpoint, for capacity planning
+// before the API is opened to more bots. Pollers fetch /arbs with
+// If-None-Match as well-behaved bots do, streamers hold /fair/stream open,
+// and browsers page through /pairs; the server's own gauges are sampled from
+// /metrics meanwhile.
+//
+//	loadtest -addr http://localhost:8080 -duration 1m -pollers 200 -streams 50
+//	loadtest -pollers 500 -poll-interval 250ms -json > report.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// options is the traffic mix of one run
+type options struct {
+	addr     string
+	duration time.Duration
+	timeout  time.Duration
+
+	pollers      int
+	pollInterval time.Duration
+	etag         bool // Pollers send If-None-Match with the last ETag
+
+	streams        int
+	streamInterval time.Duration // The interval requested of /fair/stream
+
+	browsers      int
+	pairsInterval time.Duration
+
+	scrape time.Duration // How often /metrics is sampled; 0 disables it
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.addr, "addr", "http://localhost:8080", "server base URL")
+	flag.DurationVar(&opts.duration, "duration", 30*time.Second, "how long to generate traffic")
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Second, "request timeout")
+	flag.IntVar(&opts.pollers, "pollers", 50, "clients polling /arbs")
+	flag.DurationVar(&opts.pollInterval, "poll-interval", time.Second, "interval between each poller's requests")
+	flag.BoolVar(&opts.etag, "etag", true, "pollers revalidate with If-None-Match")
+	flag.IntVar(&opts.streams, "streams", 10, "clients holding /fair/stream open")
+	flag.DurationVar(&opts.streamInterval, "stream-interval", time.Second, "update interval requested of /fair/stream")
+	flag.IntVar(&opts.browsers, "browsers", 5, "clients fetching /pairs")
+	flag.DurationVar(&opts.pairsInterval, "pairs-interval", 10*time.Second, "interval between each browser's requests")
+	flag.DurationVar(&opts.scrape, "scrape", time.Second, "interval between /metrics samples; 0 disables")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+	opts.addr = strings.TrimRight(opts.addr, "/")
+
+	if opts.duration <= 0 || opts.pollInterval <= 0 || opts.pairsInterval <= 0 {
+		fmt.Fprintln(os.Stderr, "loadtest: durations and intervals must be positive")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := run(ctx, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadtest:", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, "loadtest:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	report.print(os.Stdout)
+}
+
+// run generates the traffic mix until the duration elapses or ctx is done
+func run(ctx context.Context, opts options) (*Report, error) {
+	client := newClient(opts.timeout)
+
+	// Fail fast on a wrong address rather than report a wall of errors
+	probe, err := client.Get(opts.addr + "/healthz")
+	if err != nil {
+		return nil, fmt.Errorf("server unreachable: %w", err)
+	}
+	probe.Body.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	rec := newRecorder()
+	var server serverSampler
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for i := 0; i < opts.pollers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			poll(ctx, newClient(opts.timeout), rec, "/arbs", opts.addr+"/arbs", opts.pollInterval, opts.etag)
+		}()
+	}
+	for i := 0; i < opts.browsers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			poll(ctx, newClient(opts.timeout), rec, "/pairs", opts.addr+"/pairs", opts.pairsInterval, false)
+		}()
+	}
+	streamURL := fmt.Sprintf("%s/fair/stream?interval=%s", opts.addr, opts.streamInterval)
+	for i := 0; i < opts.streams; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hold(ctx, newClient(0), rec, streamURL) // Streams outlive any request timeout
+		}()
+	}
+	if opts.scrape > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.run(ctx, client, opts.addr+"/metrics", opts.scrape)
+		}()
+	}
+
+	wg.Wait()
+	return rec.report(opts, time.Since(start), server.summary()), nil
+}
+
+// newClient returns a client with its own connection pool: every simulated
+// client is a separate bot, and sharing connections would understate the
+// server's connection load
+func newClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: &http.Transport{IdleConnTimeout: 90 * time.Second}, Timeout: timeout}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is the outcome of a run
+type Report struct {
+	Target   string           `json:"target"`
+	Seconds  float64          `json:"seconds"`
+	Clients  Clients          `json:"clients"`
+	Requests []EndpointReport `json:"requests"`
+	Streams  StreamReport     `json:"streams"`
+	Server   *ServerReport    `json:"server,omitempty"` // Nil when /metrics was not sampled
+}
+
+// Clients is the simulated traffic mix
+type Clients struct {
+	Pollers  int `json:"pollers"`
+	Streams  int `json:"streams"`
+	Browsers int `json:"browsers"`
+}
+
+// EndpointReport summarizes the requests to one endpoint. 304 responses to
+// revalidation count as successes; errors are transport failures and 5xx.
+type EndpointReport struct {
+	Endpoint   string         `json:"endpoint"`
+	Requests   int            `json:"requests"`
+	Errors     int            `json:"errors"`
+	Throughput float64        `json:"requests_per_second"`
+	Status     map[string]int `json:"status"` // "error" for transport failures
+	P50Ms      float64        `json:"p50_ms"`
+	P90Ms      float64        `json:"p90_ms"`
+	P99Ms      float64        `json:"p99_ms"`
+	MaxMs      float64        `json:"max_ms"`
+}
+
+// StreamReport summarizes the event streams held open
+type StreamReport struct {
+	Opened       int     `json:"opened"`
+	Failed       int     `json:"failed"`  // Connection attempts refused or failed
+	Dropped      int     `json:"dropped"` // Streams the server ended before the run did
+	Events       int     `json:"events"`
+	EventsPerSec float64 `json:"events_per_second"`
+	ConnectP50Ms float64 `json:"connect_p50_ms"` // Time to response headers
+	ConnectP99Ms float64 `json:"connect_p99_ms"`
+}
+
+// ServerReport is the peak of each server gauge sampled from /metrics
+type ServerReport struct {
+	Samples int                `json:"samples"`
+	Failed  int                `json:"failed"`
+	Peaks   map[string]float64 `json:"peaks"`
+}
+
+// endpointSamples accumulates one endpoint's responses
+type endpointSamples struct {
+	errors    int
+	status    map[string]int
+	latencies []time.Duration
+}
+
+// recorder accumulates the results of every client
+type recorder struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointSamples
+
+	streamsOpened, streamsFailed, streamsDropped, events int
+	connects                                             []time.Duration
+}
+
+func newRecorder() *recorder {
+	return &recorder{endpoints: make(map[string]*endpointSamples)}
+}
+
+// request records a response, or the error in its place
+func (r *recorder) request(endpoint string, status int, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.endpoints[endpoint]
+	if !ok {
+		s = &endpointSamples{status: make(map[string]int)}
+		r.endpoints[endpoint] = s
+	}
+	if err != nil {
+		s.errors++
+		s.status["error"]++
+		return
+	}
+	if status >= 500 {
+		s.errors++
+	}
+	s.status[strconv.Itoa(status)]++
+	s.latencies = append(s.latencies, latency)
+}
+
+func (r *recorder) streamOpened(connect time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.streamsOpened++
+	r.connects = append(r.connects, connect)
+}
+
+func (r *recorder) streamFailed(error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.streamsFailed++
+}
+
+func (r *recorder) streamDropped() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.streamsDropped++
+}
+
+func (r *recorder) streamEvent() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events++
+}
+
+// report summarizes the run
+func (r *recorder) report(opts options, elapsed time.Duration, server *ServerReport) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seconds := elapsed.Seconds()
+	report := &Report{
+		Target:  opts.addr,
+		Seconds: seconds,
+		Clients: Clients{Pollers: opts.pollers, Streams: opts.streams, Browsers: opts.browsers},
+		Server:  server,
+	}
+	for endpoint, s := range r.endpoints {
+		latencies := sortedDurations(s.latencies)
+		requests := len(s.latencies) + s.status["error"]
+		report.Requests = append(report.Requests, EndpointReport{
+			Endpoint:   endpoint,
+			Requests:   requests,
+			Errors:     s.errors,
+			Throughput: float64(requests) / seconds,
+			Status:     s.status,
+			P50Ms:      percentileMs(latencies, 0.50),
+			P90Ms:      percentileMs(latencies, 0.90),
+			P99Ms:      percentileMs(latencies, 0.99),
+			MaxMs:      percentileMs(latencies, 1),
+		})
+	}
+	sort.Slice(report.Requests, func(i, j int) bool { return report.Requests[i].Endpoint < report.Requests[j].Endpoint })
+
+	connects := sortedDurations(r.connects)
+	report.Streams = StreamReport{
+		Opened:       r.streamsOpened,
+		Failed:       r.streamsFailed,
+		Dropped:      r.streamsDropped,
+		Events:       r.events,
+		EventsPerSec: float64(r.events) / seconds,
+		ConnectP50Ms: percentileMs(connects, 0.50),
+		ConnectP99Ms: percentileMs(connects, 0.99),
+	}
+	return report
+}
+
+// print writes the report as tables
+func (r *Report) print(w io.Writer) {
+	fmt.Fprintf(w, "%s for %.1fs: %d pollers, %d streams, %d browsers\n\n",
+		r.Target, r.Seconds, r.Clients.Pollers, r.Clients.Streams, r.Clients.Browsers)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tREQUESTS\tREQ/S\tERRORS\tP50 MS\tP90 MS\tP99 MS\tMAX MS\tSTATUS")
+	for _, e := range r.Requests {
+		fmt.Fprintf(tw, "%s\t%d\t%.1f\t%d\t%.1f\t%.1f\t%.1f\t%.1f\t%s\n",
+			e.Endpoint, e.Requests, e.Throughput, e.Errors, e.P50Ms, e.P90Ms, e.P99Ms, e.MaxMs, formatStatus(e.Status))
+	}
+	tw.Flush()
+
+	s := r.Streams
+	fmt.Fprintf(w, "\nstreams: %d opened, %d failed, %d dropped; %d events (%.1f/s); connect p50 %.1f ms, p99 %.1f ms\n",
+		s.Opened, s.Failed, s.Dropped, s.Events, s.EventsPerSec, s.ConnectP50Ms, s.ConnectP99Ms)
+
+	if r.Server != nil {
+		fmt.Fprintf(w, "\nserver peaks over %d samples (%d failed):\n", r.Server.Samples, r.Server.Failed)
+		for _, name := range serverGauges {
+			if v, ok := r.Server.Peaks[name]; ok {
+				fmt.Fprintf(w, "  %s %.0f\n", name, v)
+			}
+		}
+	}
+}
+
+// formatStatus lists status counts as "200:120 304:880", in code order
+func formatStatus(status map[string]int) string {
+	codes := make([]string, 0, len(status))
+	for code := range status {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	out := ""
+	for i, code := range codes {
+		if i > 0 {
+			out += " "
+		}
+		out += fmt.Sprintf("%s:%d", code, status[code])
+	}
+	return out
+}
+
+func sortedDurations(d []time.Duration) []time.Duration {
+	sorted := append([]time.Duration(nil), d...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// percentileMs returns the q-quantile of sorted latencies in milliseconds,
+// by nearest rank; 0 without samples
+func percentileMs(sorted []time.Duration, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(q*float64(len(sorted))+0.5) - 1
+	i = max(0, min(i, len(sorted)-1))
+	return float64(sorted[i].Microseconds()) / 1000
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// poll requests url every interval until ctx is done, recording each
+// response under endpoint. The first request is delayed by a random part of
+// the interval so clients don't arrive in lockstep.
+func poll(ctx context.Context, client *http.Client, rec *recorder, endpoint, url string, interval time.Duration, etag bool) {
+	if !sleep(ctx, rand.N(interval)) {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastETag string
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			rec.request(endpoint, 0, 0, err)
+			return
+		}
+		if etag && lastETag != "" {
+			req.Header.Set("If-None-Match", lastETag)
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err == nil {
+			// Latency includes reading the body, as a client experiences it
+			_, err = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if tag := resp.Header.Get("ETag"); tag != "" {
+				lastETag = tag
+			}
+		}
+		if ctx.Err() != nil {
+			return // Cut off by the end of the run, not the server
+		}
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		rec.request(endpoint, status, time.Since(start), err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hold keeps a server-sent event stream open until ctx is done, counting its
+// events and reconnecting when the server drops it
+func hold(ctx context.Context, client *http.Client, rec *recorder, url string) {
+	if !sleep(ctx, rand.N(time.Second)) {
+		return
+	}
+	for ctx.Err() == nil {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			rec.streamFailed(err)
+			return
+		}
+		req.Header.Set("Accept", "text/event-stream")
+
+		start := time.Now()
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() == nil {
+				rec.streamFailed(err)
+				sleep(ctx, time.Second)
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			rec.streamFailed(statusError(resp.StatusCode))
+			sleep(ctx, time.Second)
+			continue
+		}
+		rec.streamOpened(time.Since(start))
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64<<10), 4<<20)
+		for scanner.Scan() {
+			if strings.HasPrefix(scanner.Text(), "event:") {
+				rec.streamEvent()
+			}
+		}
+		resp.Body.Close()
+		if ctx.Err() == nil {
+			rec.streamDropped()
+		}
+	}
+}
+
+// statusError is an unexpected HTTP status
+type statusError int
+
+func (e statusError) Error() string { return "unexpected status " + strconv.Itoa(int(e)) }
+
+// sleep waits for d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// serverGauges are the server metrics sampled during a run
+var serverGauges = []string{"go_goroutines", "process_resident_memory_bytes", "process_open_fds", "http_open_streams"}
+
+// serverSampler records the peak of each server gauge while traffic runs
+type serverSampler struct {
+	mu      sync.Mutex
+	samples int
+	failed  int
+	peaks   map[string]float64
+}
+
+// run scrapes url every interval until ctx is done
+func (s *serverSampler) run(ctx context.Context, client *http.Client, url string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		values, err := scrape(ctx, client, url)
+		if ctx.Err() != nil {
+			return
+		}
+		s.mu.Lock()
+		if err != nil {
+			s.failed++
+		} else {
+			s.samples++
+			if s.peaks == nil {
+				s.peaks = make(map[string]float64)
+			}
+			for name, v := range values {
+				s.peaks[name] = max(s.peaks[name], v)
+			}
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// summary returns the peaks seen, nil when nothing was sampled
+func (s *serverSampler) summary() *ServerReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.samples == 0 && s.failed == 0 {
+		return nil
+	}
+	return &ServerReport{Samples: s.samples, Failed: s.failed, Peaks: s.peaks}
+}
+
+// scrape fetches the Prometheus text exposition at url and sums the samples
+// of each server gauge across its labels
+func scrape(ctx context.Context, client *http.Client, url string) (map[string]float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode)
+	}
+
+	values := make(map[string]float64)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, rest, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, "{")
+		for _, gauge := range serverGauges {
+			if name != gauge {
+				continue
+			}
+			fields := strings.Fields(rest)
+			if len(fields) == 0 {
+				break
+			}
+			if v, err := strconv.ParseFloat(fields[len(fields)-1], 64); err == nil {
+				values[name] += v
+			}
+		}
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,162 @@
+// Command market-dump pages through the full Polymarket and Kalshi market
+// catalogs, including closed and settled markets, and writes one record per
+// market as newline-delimited JSON or Parquet. The output feeds matcher
+// training/evaluation datasets and the backtester.
+//
+//	market-dump -venue all -format ndjson -out markets.ndjson
+//	market-dump -venue kalshi -format parquet -out kalshi.parquet
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/catalog"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func main() {
+	venue := flag.String("venue", "all", "venue to dump: pm, kalshi or all")
+	format := flag.String("format", "ndjson", "output format: ndjson or parquet")
+	out := flag.String("out", "-", "output path; - writes to stdout (ndjson only)")
+	pmURL := flag.String("pm-url", ws.DefaultPolymarketRESTURL, "Polymarket CLOB REST base URL")
+	kalshiURL := flag.String("kalshi-url", ws.DefaultKalshiRESTURL, "Kalshi trade API base URL")
+	kalshiStatus := flag.String("kalshi-status", "", "only dump Kalshi markets in this status (default: all)")
+	flag.Parse()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, *venue, *format, *out, *pmURL, *kalshiURL, *kalshiStatus, logger); err != nil {
+		logger.Error("market dump failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, venue, format, out, pmURL, kalshiURL, kalshiStatus string, logger *slog.Logger) error {
+	if venue != "pm" && venue != "kalshi" && venue != "all" {
+		return fmt.Errorf("unknown venue %q", venue)
+	}
+
+	sink, err := openSink(format, out)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	start := time.Now()
+	counts := map[string]int{}
+
+	if venue == "pm" || venue == "all" {
+		err = catalog.PolymarketPages(ctx, client, pmURL, func(page []json.RawMessage) error {
+			for _, raw := range page {
+				rec, err := polymarketRecord(raw)
+				if err != nil {
+					return err
+				}
+				if err := sink.Write(rec); err != nil {
+					return err
+				}
+			}
+			counts["polymarket"] += len(page)
+			logger.Info("polymarket page written", "total", counts["polymarket"])
+			return nil
+		})
+		if err != nil {
+			sink.Close()
+			return fmt.Errorf("dump polymarket: %w", err)
+		}
+	}
+
+	if venue == "kalshi" || venue == "all" {
+		err = catalog.KalshiPages(ctx, client, kalshiURL, kalshiStatus, func(page []json.RawMessage) error {
+			for _, raw := range page {
+				rec, err := kalshiRecord(raw)
+				if err != nil {
+					return err
+				}
+				if err := sink.Write(rec); err != nil {
+					return err
+				}
+			}
+			counts["kalshi"] += len(page)
+			logger.Info("kalshi page written", "total", counts["kalshi"])
+			return nil
+		})
+		if err != nil {
+			sink.Close()
+			return fmt.Errorf("dump kalshi: %w", err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		return fmt.Errorf("close output: %w", err)
+	}
+
+	logger.Info("market dump complete",
+		"polymarket", counts["polymarket"],
+		"kalshi", counts["kalshi"],
+		"format", format,
+		"out", out,
+		"duration", time.Since(start).String(),
+	)
+	return nil
+}
+
+// polymarketRecord normalizes a raw CLOB market
+func polymarketRecord(raw json.RawMessage) (Record, error) {
+	var m ws.PolymarketMarket
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return Record{}, fmt.Errorf("decode polymarket market: %w", err)
+	}
+
+	status := "inactive"
+	switch {
+	case m.Closed:
+		status = "closed"
+	case m.Active:
+		status = "active"
+	}
+	category := ""
+	if len(m.Tags) > 0 {
+		category = m.Tags[0]
+	}
+
+	return Record{
+		Venue:     "polymarket",
+		ID:        m.ConditionID,
+		Title:     m.Question,
+		Status:    status,
+		Category:  category,
+		CloseTime: m.EndDateISO,
+		Raw:       string(raw),
+	}, nil
+}
+
+// kalshiRecord normalizes a raw Kalshi market
+func kalshiRecord(raw json.RawMessage) (Record, error) {
+	var m ws.KalshiMarket
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return Record{}, fmt.Errorf("decode kalshi market: %w", err)
+	}
+
+	return Record{
+		Venue:     "kalshi",
+		ID:        m.Ticker,
+		Title:     m.Title,
+		Status:    m.Status,
+		Category:  m.Category,
+		CloseTime: m.CloseTime,
+		Raw:       string(raw),
+	}, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Record is one market in the dump: a few normalized columns for filtering
+// plus the venue's full market object
+type Record struct {
+	Venue     string `json:"venue" parquet:"venue,dict"`
+	ID        string `json:"id" parquet:"id"`
+	Title     string `json:"title" parquet:"title"`
+	Status    string `json:"status" parquet:"status,dict"`
+	Category  string `json:"category" parquet:"category,dict"`
+	CloseTime string `json:"close_time" parquet:"close_time"`
+	Raw       string `json:"-" parquet:"raw"` // Venue market object as JSON
+}
+
+// sink writes records in one output format
+type sink interface {
+	Write(rec Record) error
+	Close() error
+}
+
+// openSink creates the writer for format at path ("-" is stdout)
+func openSink(format, path string) (sink, error) {
+	var f io.WriteCloser = os.Stdout
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("create output: %w", err)
+		}
+		f = file
+	}
+
+	switch format {
+	case "ndjson":
+		return &ndjsonSink{f: f, w: bufio.NewWriter(f)}, nil
+	case "parquet":
+		if path == "-" {
+			return nil, fmt.Errorf("parquet output requires -out")
+		}
+		return &parquetSink{f: f, w: parquet.NewGenericWriter[Record](f)}, nil
+	default:
+		if path != "-" {
+			f.Close()
+		}
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// ndjsonSink writes one JSON object per line with the raw market under "market"
+type ndjsonSink struct {
+	f io.WriteCloser
+	w *bufio.Writer
+}
+
+func (s *ndjsonSink) Write(rec Record) error {
+	line, err := json.Marshal(struct {
+		Record
+		Market json.RawMessage `json:"market"`
+	}{rec, json.RawMessage(rec.Raw)})
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	return nil
+}
+
+func (s *ndjsonSink) Close() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	if s.f == os.Stdout {
+		return nil
+	}
+	return s.f.Close()
+}
+
+// parquetSink writes records as rows of a Parquet file
+type parquetSink struct {
+	f io.WriteCloser
+	w *parquet.GenericWriter[Record]
+}
+
+func (s *parquetSink) Write(rec Record) error {
+	if _, err := s.w.Write([]Record{rec}); err != nil {
+		return fmt.Errorf("write record: %w", err)
+	}
+	return nil
+}
+
+func (s *parquetSink) Close() error {
+	if err := s.w.Close(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
@@ -0,0 +1,92 @@
+// Command match-eval runs the title matcher over a labeled dataset of
+// Polymarket/Kalshi pairs and prints precision/recall at each threshold.
+//
+//	match-eval -dataset pkg/match/eval/testdata/pairs.jsonl
+//	match-eval -thresholds 0.4,0.5,0.6 -errors 0.5
+//	match-eval -json > baseline.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/match/eval"
+)
+
+func main() {
+	dataset := flag.String("dataset", "pkg/match/eval/testdata/pairs.jsonl", "JSON-lines file of labeled pairs")
+	thresholds := flag.String("thresholds", "", "comma-separated thresholds (default 0.3..0.9)")
+	errorsAt := flag.Float64("errors", -1, "list misclassified pairs at this threshold")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	if err := run(*dataset, *thresholds, *errorsAt, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, "match-eval:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dataset, thresholdList string, errorsAt float64, asJSON bool) error {
+	thresholds, err := parseThresholds(thresholdList)
+	if err != nil {
+		return err
+	}
+
+	examples, err := eval.Load(dataset)
+	if err != nil {
+		return err
+	}
+	report := eval.Evaluate(examples, eval.DefaultScorer, thresholds)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("%d examples (%d matches) from %s\n\n", report.Examples, report.Positive, dataset)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "THRESHOLD\tPRECISION\tRECALL\tF1\tTP\tFP\tFN\tTN")
+	for _, r := range report.Results {
+		fmt.Fprintf(tw, "%.2f\t%.3f\t%.3f\t%.3f\t%d\t%d\t%d\t%d\n",
+			r.Threshold, r.Precision, r.Recall, r.F1, r.TruePositives, r.FalsePositives, r.FalseNegatives, r.TrueNegatives)
+	}
+	tw.Flush()
+
+	if best, ok := report.Best(); ok {
+		fmt.Printf("\nbest F1 %.3f at threshold %.2f\n", best.F1, best.Threshold)
+	}
+
+	if errorsAt >= 0 {
+		fmt.Printf("\nmisclassified at %.2f:\n", errorsAt)
+		for _, s := range report.Errors(errorsAt) {
+			kind := "FP"
+			if s.Match {
+				kind = "FN"
+			}
+			fmt.Printf("  %s %.3f  %q <> %q\n", kind, s.Score, s.PMTitle, s.KalshiTitle)
+		}
+	}
+	return nil
+}
+
+// parseThresholds parses a comma-separated list of thresholds in [0, 1]
+func parseThresholds(s string) ([]float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var out []float64
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || v < 0 || v > 1 {
+			return nil, fmt.Errorf("invalid threshold %q", part)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
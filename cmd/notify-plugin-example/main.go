@@ -0,0 +1,80 @@
+// Command notify-plugin-example is a minimal notification/publishing plugin.
+// It appends every notification and a summary of every published
+// opportunity list to a JSON-lines file (or stderr), and is meant as a
+// starting point for proprietary delivery targets.
+//
+//	PLUGINS=example=/usr/local/bin/notify-plugin-example arb-ws-server
+//	PLUGINS="example=/usr/local/bin/notify-plugin-example -out /var/log/arb-notify.jsonl" arb-ws-server
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/notify"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/plugin"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// filePlugin writes deliveries as JSON lines
+type filePlugin struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (p *filePlugin) write(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.out.Write(append(line, '\n'))
+	return err
+}
+
+// Notify implements plugin.Handler
+func (p *filePlugin) Notify(ctx context.Context, n notify.Notification) error {
+	return p.write(map[string]interface{}{"kind": "notification", "notification": n})
+}
+
+// Publish implements plugin.Handler
+func (p *filePlugin) Publish(ctx context.Context, opps []arb.Opportunity) error {
+	best := 0.0
+	if len(opps) > 0 {
+		best = opps[0].EdgePctTurn
+	}
+	return p.write(map[string]interface{}{
+		"kind":          "snapshot",
+		"time":          time.Now().UTC(),
+		"opportunities": len(opps),
+		"best_edge_pct": best,
+	})
+}
+
+func main() {
+	out := flag.String("out", "", "JSON-lines output file (default stderr)")
+	flag.Parse()
+
+	p := &filePlugin{out: os.Stderr}
+	if *out != "" {
+		f, err := os.OpenFile(*out, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "open output:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		p.out = f
+	}
+
+	if err := plugin.Serve(p, plugin.CapabilityNotify, plugin.CapabilityPublish); err != nil {
+		fmt.Fprintln(os.Stderr, "serve:", err)
+		os.Exit(1)
+	}
+}
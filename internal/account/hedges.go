@@ -0,0 +1,71 @@
+package account
+
+import (
+	"math"
+	"sort"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+// Hedge is a two-legged position held across both venues of a pair, priced
+// for closing now at the bids
+type Hedge struct {
+	PMTitle      string  `json:"pm_title"`
+	KalshiTicker string  `json:"kalshi_ticker"`
+	Contracts    float64 `json:"contracts"` // Hedged contracts: the smaller leg
+	Cost         float64 `json:"cost"`      // Average entry cost per contract of both legs
+	arb.ExitEdge
+	PnL float64 `json:"pnl"` // Contracts × (Proceeds − Cost), before exit fees
+	// Unwind recommends closing early: the remaining edge has collapsed or
+	// reversed, so selling now does at least as well as holding to resolution
+	Unwind bool `json:"unwind"`
+}
+
+// Hedges returns the hedges held in pairs with bids on both legs, ones to
+// unwind first, then by PnL
+func (t *Tracker) Hedges(prices []arb.PairPrices) []Hedge {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]Hedge, 0)
+	for _, pp := range prices {
+		for _, combo := range arb.HedgeCombos(pp.Inverted) {
+			pmYes, kalshiYes := arb.ComboSides(combo)
+			pmLeg, kalshiLeg := pp.PMNoInstrument, instrument.KalshiNo(pp.KalshiTicker)
+			if pmYes {
+				pmLeg = pp.PMInstrument
+			}
+			if kalshiYes {
+				kalshiLeg = pp.KalshiInstrument
+			}
+			pm, kalshi := t.positions[pmLeg], t.positions[kalshiLeg]
+			if pm == nil || kalshi == nil || pm.Contracts <= 0 || kalshi.Contracts <= 0 {
+				continue
+			}
+			exit, ok := pp.Exit(combo)
+			if !ok {
+				continue
+			}
+
+			h := Hedge{
+				PMTitle:      pp.PMTitle,
+				KalshiTicker: pp.KalshiTicker,
+				Contracts:    math.Min(pm.Contracts, kalshi.Contracts),
+				Cost:         pm.CostBasis/pm.Contracts + kalshi.CostBasis/kalshi.Contracts,
+				ExitEdge:     exit,
+				Unwind:       exit.RemainingEdge <= 0,
+			}
+			h.PnL = h.Contracts * (exit.Proceeds - h.Cost)
+			out = append(out, h)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Unwind != out[j].Unwind {
+			return out[i].Unwind
+		}
+		return out[i].PnL > out[j].PnL
+	})
+	return out
+}
@@ -0,0 +1,285 @@
+package account
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+const maxRecentEvents = 500
+
+// Event kinds
+const (
+	KindFill   = "fill"
+	KindCancel = "cancel"
+)
+
+// Event is a normalized account activity event from either venue
+type Event struct {
+	Kind       string        `json:"kind"`  // "fill" or "cancel"
+	Venue      string        `json:"venue"` // "polymarket" or "kalshi"
+	OrderID    string        `json:"order_id"`
+	TradeID    string        `json:"trade_id,omitempty"`
+	Instrument instrument.ID `json:"instrument"` // Canonical instrument ID
+	Outcome    string        `json:"outcome"`    // "yes" or "no" (Kalshi); "" for PM tokens
+	Side       string        `json:"side"`       // "buy" or "sell"
+	Price      float64       `json:"price"`      // Probability units (0-1)
+	Size       float64       `json:"size"`       // Contracts
+	Timestamp  time.Time     `json:"timestamp"`
+}
+
+// Position is the net holding in one instrument built from observed fills
+type Position struct {
+	Venue      string        `json:"venue"`
+	Instrument instrument.ID `json:"instrument"`
+	Outcome    string        `json:"outcome,omitempty"`
+	Contracts  float64       `json:"contracts"`
+	CostBasis  float64       `json:"cost_basis"` // Net cash paid, USD
+}
+
+// Tracker ingests our own fills and cancels from the exchange streams, keeps
+// running positions, and lets callers wait for an order's completion
+type Tracker struct {
+	mu        sync.RWMutex
+	positions map[instrument.ID]*Position
+	recent    []Event
+	waiters   map[string][]chan Event // order ID -> waiting callers
+	realized  func(pnl float64, at time.Time)
+	pmOwner   string // Polymarket API key our orders are placed under
+	logger    *slog.Logger
+}
+
+// NewTracker creates an empty tracker
+func NewTracker(logger *slog.Logger) *Tracker {
+	return &Tracker{
+		positions: make(map[instrument.ID]*Position),
+		waiters:   make(map[string][]chan Event),
+		logger:    logger,
+	}
+}
+
+// SetRealizedHook registers a callback invoked with the realized PnL (USD)
+// of every fill that reduces a long position, e.g. to feed the kill switch's
+// loss breaker. Call before consuming events.
+func (t *Tracker) SetRealizedHook(fn func(pnl float64, at time.Time)) {
+	t.realized = fn
+}
+
+// SetPolymarketOwner sets the Polymarket API key our orders are placed
+// under, which tells our resting orders apart from the other maker orders of
+// a trade. Without it every Polymarket trade is taken as our taker order's.
+// Call before consuming events.
+func (t *Tracker) SetPolymarketOwner(apiKey string) {
+	t.pmOwner = apiKey
+}
+
+// ConsumeKalshi ingests fills from the Kalshi private fill channel until ctx is done
+func (t *Tracker) ConsumeKalshi(ctx context.Context, fills <-chan ws.KalshiFill) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case f := <-fills:
+			price := f.YesPrice
+			if f.Side == "no" {
+				price = f.NoPrice
+			}
+			t.Record(Event{
+				Kind:       KindFill,
+				Venue:      "kalshi",
+				OrderID:    f.OrderID,
+				TradeID:    f.TradeID,
+				Instrument: instrument.Kalshi(f.MarketTicker, f.Side),
+				Outcome:    f.Side,
+				Side:       f.Action,
+				Price:      price / 100,
+				Size:       f.Count,
+				Timestamp:  f.ReceivedAt,
+			})
+		}
+	}
+}
+
+// ConsumePolymarket ingests trade and order events from the Polymarket user channel until ctx is done.
+// Trades count once they are matched; failed trades and intermediate statuses are ignored.
+func (t *Tracker) ConsumePolymarket(ctx context.Context, events <-chan ws.PMUserEvent) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-events:
+			switch {
+			case ev.EventType == "trade" && ev.Status == "MATCHED":
+				for _, fill := range t.polymarketFills(ev) {
+					t.Record(fill)
+				}
+			case ev.EventType == "order" && ev.Type == "CANCELLATION":
+				t.Record(Event{
+					Kind:       KindCancel,
+					Venue:      "polymarket",
+					OrderID:    ev.ID,
+					Instrument: instrument.PMToken(ev.AssetID),
+					Side:       strings.ToLower(ev.Side),
+					Price:      ev.Price,
+					Size:       ev.OriginalSize - ev.SizeMatched,
+					Timestamp:  ev.ReceivedAt,
+				})
+			}
+		}
+	}
+}
+
+// polymarketFills returns our fills in a matched trade. The user channel
+// reports trades our orders took part in on either side: when maker orders
+// of the trade are ours, each is a fill at its own price and matched amount,
+// otherwise the fill is our taker order's.
+func (t *Tracker) polymarketFills(ev ws.PMUserEvent) []Event {
+	takerSide := strings.ToLower(ev.Side)
+	var fills []Event
+	for _, m := range ev.MakerOrders {
+		if t.pmOwner == "" || m.Owner != t.pmOwner {
+			continue
+		}
+		// A maker on the taker's token takes the other side; one on the
+		// complementary token is matched by minting or merging, on the same
+		// side as the taker
+		asset, side := m.AssetID, takerSide
+		if asset == "" || asset == ev.AssetID {
+			asset = ev.AssetID
+			side = "buy"
+			if takerSide == "buy" {
+				side = "sell"
+			}
+		}
+		fills = append(fills, Event{
+			Kind:       KindFill,
+			Venue:      "polymarket",
+			OrderID:    m.OrderID,
+			TradeID:    ev.ID,
+			Instrument: instrument.PMToken(asset),
+			Side:       side,
+			Price:      m.Price,
+			Size:       m.MatchedAmount,
+			Timestamp:  ev.ReceivedAt,
+		})
+	}
+	if len(fills) > 0 {
+		return fills
+	}
+	return []Event{{
+		Kind:       KindFill,
+		Venue:      "polymarket",
+		OrderID:    ev.TakerOrderID,
+		TradeID:    ev.ID,
+		Instrument: instrument.PMToken(ev.AssetID),
+		Side:       takerSide,
+		Price:      ev.Price,
+		Size:       ev.Size,
+		Timestamp:  ev.ReceivedAt,
+	}}
+}
+
+// Record applies an event to positions and wakes anyone waiting on its order
+func (t *Tracker) Record(ev Event) {
+	t.mu.Lock()
+
+	realized, hasRealized := 0.0, false
+	if ev.Kind == KindFill {
+		pos, ok := t.positions[ev.Instrument]
+		if !ok {
+			pos = &Position{Venue: ev.Venue, Instrument: ev.Instrument, Outcome: ev.Outcome}
+			t.positions[ev.Instrument] = pos
+		}
+		if ev.Side == "sell" {
+			// Closing part of a long realizes the difference to average cost
+			if pos.Contracts > 0 {
+				closed := math.Min(ev.Size, pos.Contracts)
+				realized = closed * (ev.Price - pos.CostBasis/pos.Contracts)
+				hasRealized = true
+			}
+			pos.Contracts -= ev.Size
+			pos.CostBasis -= ev.Size * ev.Price
+		} else {
+			pos.Contracts += ev.Size
+			pos.CostBasis += ev.Size * ev.Price
+		}
+	}
+
+	if len(t.recent) >= maxRecentEvents {
+		t.recent = t.recent[1:]
+	}
+	t.recent = append(t.recent, ev)
+
+	waiters := t.waiters[ev.OrderID]
+	delete(t.waiters, ev.OrderID)
+	t.mu.Unlock()
+
+	for _, ch := range waiters {
+		ch <- ev
+	}
+	if hasRealized && t.realized != nil {
+		t.realized(realized, ev.Timestamp)
+	}
+
+	t.logger.Info("account activity", "kind", ev.Kind, "venue", ev.Venue, "order_id", ev.OrderID,
+		"instrument", ev.Instrument, "side", ev.Side, "price", ev.Price, "size", ev.Size)
+}
+
+// AwaitOrder blocks until the exchange stream reports a fill or cancel for the
+// order, letting an execution coordinator confirm leg completion without polling REST
+func (t *Tracker) AwaitOrder(ctx context.Context, orderID string) (Event, error) {
+	ch := make(chan Event, 1)
+
+	t.mu.Lock()
+	t.waiters[orderID] = append(t.waiters[orderID], ch)
+	t.mu.Unlock()
+
+	select {
+	case ev := <-ch:
+		return ev, nil
+	case <-ctx.Done():
+		t.mu.Lock()
+		list := t.waiters[orderID]
+		for i, w := range list {
+			if w == ch {
+				t.waiters[orderID] = append(list[:i], list[i+1:]...)
+				break
+			}
+		}
+		if len(t.waiters[orderID]) == 0 {
+			delete(t.waiters, orderID)
+		}
+		t.mu.Unlock()
+		return Event{}, ctx.Err()
+	}
+}
+
+// Positions returns a copy of all non-flat positions
+func (t *Tracker) Positions() []Position {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]Position, 0, len(t.positions))
+	for _, p := range t.positions {
+		if p.Contracts != 0 {
+			out = append(out, *p)
+		}
+	}
+	return out
+}
+
+// Recent returns a copy of the most recent account events, oldest first
+func (t *Tracker) Recent() []Event {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	out := make([]Event, len(t.recent))
+	copy(out, t.recent)
+	return out
+}
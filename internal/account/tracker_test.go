@@ -0,0 +1,172 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func newTestTracker() *Tracker {
+	return NewTracker(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestTrackerPositions(t *testing.T) {
+	tr := newTestTracker()
+
+	tr.Record(Event{Kind: KindFill, Venue: "kalshi", OrderID: "o1", Instrument: "kalshi:KX-1:no", Outcome: "no", Side: "buy", Price: 0.40, Size: 10})
+	tr.Record(Event{Kind: KindFill, Venue: "kalshi", OrderID: "o2", Instrument: "kalshi:KX-1:no", Outcome: "no", Side: "sell", Price: 0.50, Size: 4})
+	tr.Record(Event{Kind: KindCancel, Venue: "polymarket", OrderID: "o3", Instrument: "pm:token:123", Side: "buy", Price: 0.55, Size: 10})
+
+	positions := tr.Positions()
+	if len(positions) != 1 {
+		t.Fatalf("expected 1 position, got %+v", positions)
+	}
+	p := positions[0]
+	if p.Contracts != 6 || math.Abs(p.CostBasis-2.0) > 1e-9 {
+		t.Errorf("unexpected position %+v", p)
+	}
+	if n := len(tr.Recent()); n != 3 {
+		t.Errorf("expected 3 recent events, got %d", n)
+	}
+}
+
+func TestTrackerAwaitOrder(t *testing.T) {
+	tr := newTestTracker()
+
+	done := make(chan Event, 1)
+	go func() {
+		ev, err := tr.AwaitOrder(context.Background(), "leg-1")
+		if err != nil {
+			t.Errorf("AwaitOrder: %v", err)
+		}
+		done <- ev
+	}()
+
+	// Give the waiter time to register before the fill arrives
+	time.Sleep(10 * time.Millisecond)
+	tr.Record(Event{Kind: KindFill, Venue: "polymarket", OrderID: "leg-1", Instrument: "pm:token:123", Side: "buy", Price: 0.5, Size: 1})
+
+	select {
+	case ev := <-done:
+		if ev.OrderID != "leg-1" || ev.Kind != KindFill {
+			t.Errorf("unexpected event %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("AwaitOrder did not return")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := tr.AwaitOrder(ctx, "never"); err == nil {
+		t.Error("expected context error")
+	}
+	if len(tr.waiters) != 0 {
+		t.Errorf("expected waiters cleaned up, got %d", len(tr.waiters))
+	}
+}
+
+func TestTrackerPolymarketMakerFills(t *testing.T) {
+	tr := newTestTracker()
+	tr.SetPolymarketOwner("our-key")
+
+	// Someone else's taker order buys YES against two of our resting orders:
+	// a YES sell and, on the complementary token, a NO buy
+	var ev ws.PMUserEvent
+	payload := `{"event_type":"trade","id":"trade-1","status":"MATCHED","asset_id":"yes-token","side":"BUY",
+		"price":"0.57","size":"15","taker_order_id":"0xtheirs","owner":"our-key","trade_owner":"their-key",
+		"maker_orders":[
+			{"order_id":"0xours-1","owner":"our-key","asset_id":"yes-token","outcome":"YES","price":"0.56","matched_amount":"10"},
+			{"order_id":"0xother","owner":"other-key","asset_id":"yes-token","outcome":"YES","price":"0.57","matched_amount":"2"},
+			{"order_id":"0xours-2","owner":"our-key","asset_id":"no-token","outcome":"NO","price":"0.43","matched_amount":"3"}]}`
+	if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan Event, 1)
+	go func() {
+		fill, err := tr.AwaitOrder(context.Background(), "0xours-1")
+		if err != nil {
+			t.Errorf("AwaitOrder: %v", err)
+		}
+		done <- fill
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan ws.PMUserEvent, 1)
+	events <- ev
+	go tr.ConsumePolymarket(ctx, events)
+	defer cancel()
+
+	select {
+	case fill := <-done:
+		if fill.Side != "sell" || fill.Price != 0.56 || fill.Size != 10 || fill.Instrument != "pm:token:yes-token" || fill.TradeID != "trade-1" {
+			t.Errorf("resting order fill = %+v, want a 10 contract YES sell at 0.56", fill)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("AwaitOrder did not wake for our maker order")
+	}
+
+	// Both of our orders filled, the others' did not
+	deadline := time.Now().Add(time.Second)
+	for len(tr.Recent()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	recent := tr.Recent()
+	if len(recent) != 2 {
+		t.Fatalf("recent = %+v, want our two maker fills", recent)
+	}
+	if no := recent[1]; no.OrderID != "0xours-2" || no.Side != "buy" || no.Price != 0.43 || no.Size != 3 || no.Instrument != "pm:token:no-token" {
+		t.Errorf("complementary fill = %+v, want a 3 contract NO buy at 0.43", no)
+	}
+}
+
+func TestTrackerRealizedHook(t *testing.T) {
+	tr := newTestTracker()
+
+	var realized []float64
+	tr.SetRealizedHook(func(pnl float64, at time.Time) { realized = append(realized, pnl) })
+
+	tr.Record(Event{Kind: KindFill, Venue: "kalshi", OrderID: "o1", Instrument: "kalshi:KX-1:yes", Side: "buy", Price: 0.60, Size: 10})
+	tr.Record(Event{Kind: KindFill, Venue: "kalshi", OrderID: "o2", Instrument: "kalshi:KX-1:yes", Side: "sell", Price: 0.45, Size: 4})
+
+	if len(realized) != 1 || math.Abs(realized[0]-(-0.60)) > 1e-9 {
+		t.Errorf("expected one realized loss of 0.60, got %v", realized)
+	}
+}
+
+func TestTrackerHedges(t *testing.T) {
+	tr := newTestTracker()
+	tr.Record(Event{Kind: KindFill, Venue: "polymarket", OrderID: "o1", Instrument: "pm:token:y", Side: "buy", Price: 0.40, Size: 10})
+	tr.Record(Event{Kind: KindFill, Venue: "kalshi", OrderID: "o2", Instrument: "kalshi:KX-1:no", Outcome: "no", Side: "buy", Price: 0.55, Size: 8})
+
+	prices := arb.PairPrices{
+		PMInstrument:     "pm:token:y",
+		PMNoInstrument:   "pm:token:n",
+		KalshiInstrument: "kalshi:KX-1:yes",
+		KalshiTicker:     "KX-1",
+		PMYesBid:         0.45,
+		KalshiNoBid:      0.52,
+	}
+	hedges := tr.Hedges([]arb.PairPrices{prices})
+	if len(hedges) != 1 {
+		t.Fatalf("expected the PM-YES + K-NO hedge, got %+v", hedges)
+	}
+	h := hedges[0]
+	if h.Combo != arb.ComboPMYesKalshiNo || h.Contracts != 8 || math.Abs(h.Cost-0.95) > 1e-9 || math.Abs(h.RemainingEdge-0.03) > 1e-9 || h.Unwind {
+		t.Errorf("unexpected hedge %+v", h)
+	}
+
+	// The venues converged: selling both legs now beats the $1 payout
+	prices.PMYesBid, prices.KalshiNoBid = 0.60, 0.42
+	if h := tr.Hedges([]arb.PairPrices{prices})[0]; !h.Unwind || math.Abs(h.PnL-8*0.07) > 1e-9 {
+		t.Errorf("expected an unwind recommendation, got %+v", h)
+	}
+}
@@ -0,0 +1,183 @@
+// Package archive moves old persisted history out of the local database:
+// whole UTC days of opportunity streaks and spread samples older than a
+// configured age are written to zstd-compressed Parquet files, uploaded to
+// S3-compatible object storage and only then deleted locally. Objects are
+// laid out as <prefix>/<table>/date=YYYY-MM-DD/<unixnano>.parquet, so
+// query engines read them as a date-partitioned dataset.
+package archive
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// Tables are the archived tables, each under its own prefix
+var Tables = []string{store.TableOpportunityHistory, store.TablePairSpreads}
+
+// Uploader stores a local file as an object
+type Uploader interface {
+	Put(ctx context.Context, key, path string) error
+}
+
+// Archiver moves rows older than its age from the store to an Uploader
+type Archiver struct {
+	store  *store.Store
+	up     Uploader
+	prefix string
+	age    time.Duration
+	logger *slog.Logger
+}
+
+// New creates an archiver for rows older than age. Age is rounded up to
+// whole days; a day is archived once all of it is older than age.
+func New(st *store.Store, up Uploader, prefix string, age time.Duration, logger *slog.Logger) *Archiver {
+	return &Archiver{store: st, up: up, prefix: prefix, age: age, logger: logger}
+}
+
+// TablePrefix is the object key prefix a table's files are uploaded under,
+// for lifecycle rules
+func (a *Archiver) TablePrefix(table string) string {
+	return path.Join(a.prefix, table) + "/"
+}
+
+// Run archives every interval until ctx is done, starting at once
+func (a *Archiver) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.ArchiveOnce(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			metrics.RecordArchiveFailure()
+			a.logger.Error("archive pass failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ArchiveOnce archives every whole day older than the archiver's age as of
+// now, oldest first. A day's rows are deleted only after its file is
+// uploaded; a failed day stops the pass and is retried by the next one,
+// so a day can occasionally span more than one file with overlapping rows.
+func (a *Archiver) ArchiveOnce(ctx context.Context, now time.Time) error {
+	cutoff := now.Add(-a.age).UTC().Truncate(24 * time.Hour)
+	for _, table := range Tables {
+		for {
+			oldest, ok, err := a.store.Oldest(ctx, table, cutoff)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			day := oldest.Truncate(24 * time.Hour)
+			if err := a.archiveDay(ctx, table, day, now); err != nil {
+				return fmt.Errorf("archive %s %s: %w", table, day.Format(time.DateOnly), err)
+			}
+		}
+	}
+	return nil
+}
+
+// archiveDay exports, uploads and deletes one day of a table's rows
+func (a *Archiver) archiveDay(ctx context.Context, table string, day, now time.Time) error {
+	from, to := day, day.Add(24*time.Hour)
+
+	f, err := os.CreateTemp("", "arb-archive-*.parquet")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	rows, err := a.export(ctx, table, f, from, to)
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	key := path.Join(a.prefix, table, "date="+day.Format(time.DateOnly), fmt.Sprintf("%d.parquet", now.UnixNano()))
+	if err := a.up.Put(ctx, key, f.Name()); err != nil {
+		return fmt.Errorf("upload %s: %w", key, err)
+	}
+	if err := a.store.DeleteArchived(ctx, table, from, to, rows); err != nil {
+		// Rows written into the day during the upload, e.g. by a backfill,
+		// keep it in the database; the next pass uploads it again in full
+		return err
+	}
+
+	metrics.RecordArchivedRows(table, rows)
+	a.logger.Info("archived history", "table", table, "day", day.Format(time.DateOnly), "rows", rows, "key", key)
+	return nil
+}
+
+// export writes a table's rows in [from, to) to f as Parquet
+func (a *Archiver) export(ctx context.Context, table string, f *os.File, from, to time.Time) (int, error) {
+	var rows int
+	switch table {
+	case store.TableOpportunityHistory:
+		w := parquet.NewGenericWriter[historyRecord](f, parquet.Compression(&parquet.Zstd))
+		err := a.store.ExportHistory(ctx, from, to, func(r store.HistoryRow) error {
+			rows++
+			_, err := w.Write([]historyRecord{historyRecord(r)})
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		return rows, w.Close()
+	case store.TablePairSpreads:
+		w := parquet.NewGenericWriter[spreadRecord](f, parquet.Compression(&parquet.Zstd))
+		err := a.store.ExportSpreads(ctx, from, to, func(s arb.SpreadSample) error {
+			rows++
+			_, err := w.Write([]spreadRecord{{PairID: s.PairID, At: s.At, PMMid: s.PMMid, KalshiMid: s.KalshiMid, Spread: s.Spread}})
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		return rows, w.Close()
+	}
+	return 0, fmt.Errorf("unknown table %s", table)
+}
+
+// historyRecord is an opportunity_history row in Parquet
+type historyRecord struct {
+	OppID            string    `parquet:"opp_id"`
+	FirstSeen        time.Time `parquet:"first_seen,timestamp(millisecond)"`
+	LastSeen         time.Time `parquet:"last_seen,timestamp(millisecond)"`
+	Combo            string    `parquet:"combo,dict"`
+	Category         string    `parquet:"category,dict"`
+	PMTitle          string    `parquet:"pm_title,dict"`
+	KalshiTicker     string    `parquet:"kalshi_ticker,dict"`
+	KalshiTitle      string    `parquet:"kalshi_title,dict"`
+	PMInstrument     string    `parquet:"pm_instrument,dict"`
+	KalshiInstrument string    `parquet:"kalshi_instrument,dict"`
+	PeakEdgePct      float64   `parquet:"peak_edge_pct"`
+	SumEdgePct       float64   `parquet:"sum_edge_pct"`
+	Samples          int       `parquet:"samples"`
+}
+
+// spreadRecord is a pair_spreads row in Parquet
+type spreadRecord struct {
+	PairID    string    `parquet:"pair_id,dict"`
+	At        time.Time `parquet:"at,timestamp(millisecond)"`
+	PMMid     float64   `parquet:"pm_mid"`
+	KalshiMid float64   `parquet:"kalshi_mid"`
+	Spread    float64   `parquet:"spread"`
+}
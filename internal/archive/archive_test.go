@@ -0,0 +1,159 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// fakeUploader reads uploaded spread files back, optionally failing
+type fakeUploader struct {
+	keys    []string
+	spreads []spreadRecord
+	err     error
+}
+
+func (u *fakeUploader) Put(ctx context.Context, key, path string) error {
+	if u.err != nil {
+		return u.err
+	}
+	u.keys = append(u.keys, key)
+	if strings.Contains(key, store.TablePairSpreads) {
+		rows, err := parquet.ReadFile[spreadRecord](path)
+		if err != nil {
+			return err
+		}
+		u.spreads = append(u.spreads, rows...)
+	}
+	return nil
+}
+
+func TestArchiveOnce(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	st, err := store.Open(ctx, ":memory:", logger)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var samples []arb.SpreadSample
+	for _, at := range []time.Time{day.Add(time.Hour), day.Add(2 * time.Hour), day.Add(25 * time.Hour), day.Add(49 * time.Hour)} {
+		samples = append(samples, arb.SpreadSample{PairID: "p1", At: at, PMMid: 0.5, KalshiMid: 0.45})
+	}
+	if err := st.RecordSpreads(ctx, samples); err != nil {
+		t.Fatalf("RecordSpreads: %v", err)
+	}
+
+	// With a one-day age at 01:00 on day 4, days 1 and 2 ended long enough ago
+	now := day.Add(73 * time.Hour)
+	up := &fakeUploader{err: errors.New("bucket unreachable")}
+	a := New(st, up, "research", 24*time.Hour, logger)
+	if err := a.ArchiveOnce(ctx, now); err == nil {
+		t.Fatal("expected the failed upload to fail the pass")
+	}
+	if got, _ := st.Spreads(ctx, "p1", day); len(got) != 4 {
+		t.Fatalf("rows deleted despite the failed upload: %d left", len(got))
+	}
+
+	up.err = nil
+	if err := a.ArchiveOnce(ctx, now); err != nil {
+		t.Fatalf("ArchiveOnce: %v", err)
+	}
+	if len(up.keys) != 2 || !strings.HasPrefix(up.keys[0], "research/pair_spreads/date=2026-03-01/") ||
+		!strings.HasPrefix(up.keys[1], "research/pair_spreads/date=2026-03-02/") {
+		t.Fatalf("unexpected keys %v", up.keys)
+	}
+	if len(up.spreads) != 3 || up.spreads[0].PairID != "p1" || !up.spreads[0].At.Equal(day.Add(time.Hour)) {
+		t.Fatalf("unexpected archived rows %+v", up.spreads)
+	}
+	if got, _ := st.Spreads(ctx, "p1", day); len(got) != 1 || !got[0].At.Equal(day.Add(49*time.Hour)) {
+		t.Errorf("expected only the third day left, got %+v", got)
+	}
+	if a.TablePrefix(store.TablePairSpreads) != "research/pair_spreads/" {
+		t.Errorf("unexpected prefix %q", a.TablePrefix(store.TablePairSpreads))
+	}
+}
+
+func TestObjectStore(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+
+	defer func(p backoff.Policy) { uploadRetry = p }(uploadRetry)
+	uploadRetry.Base = time.Millisecond
+
+	var mu sync.Mutex
+	var requests []*http.Request
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r)
+		bodies = append(bodies, string(body))
+		if len(requests) == 1 {
+			http.Error(w, "slow down", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	s, err := NewObjectStore(ctx, StoreConfig{Bucket: "arb", Endpoint: srv.URL, StorageClass: "STANDARD_IA"})
+	if err != nil {
+		t.Fatalf("NewObjectStore: %v", err)
+	}
+	s.client = srv.Client()
+
+	path := t.TempDir() + "/day.parquet"
+	if err := os.WriteFile(path, []byte("PAR1"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, "research/pair_spreads/date=2026-03-01/1.parquet", path); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("expected a retry after the 503, got %d requests", len(requests))
+	}
+	r := requests[1]
+	if r.Method != http.MethodPut || r.URL.EscapedPath() != "/arb/research/pair_spreads/date%3D2026-03-01/1.parquet" || bodies[1] != "PAR1" {
+		t.Errorf("unexpected upload %s %s %q", r.Method, r.URL.EscapedPath(), bodies[1])
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(r.Header.Get("Authorization"), "/auto/s3/aws4_request") {
+		t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+	}
+	if r.Header.Get("X-Amz-Storage-Class") != "STANDARD_IA" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		t.Errorf("missing headers %v", r.Header)
+	}
+
+	err = s.PutLifecycle(ctx, []LifecycleRule{{Prefix: "research/pair_spreads/", TransitionDays: 30, TransitionClass: "GLACIER_IR", ExpireDays: 365}})
+	if err != nil {
+		t.Fatalf("PutLifecycle: %v", err)
+	}
+	r, body := requests[2], bodies[2]
+	if r.URL.Path != "/arb" || r.URL.RawQuery != "lifecycle=" || r.Header.Get("Content-MD5") == "" {
+		t.Errorf("unexpected lifecycle request %s?%s", r.URL.Path, r.URL.RawQuery)
+	}
+	for _, want := range []string{"<Prefix>research/pair_spreads/</Prefix>", "<StorageClass>GLACIER_IR</StorageClass>", "<Expiration><Days>365</Days></Expiration>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("lifecycle body missing %s: %s", want, body)
+		}
+	}
+}
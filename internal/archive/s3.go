@@ -0,0 +1,246 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+)
+
+// GCSEndpoint is Google Cloud Storage's S3-compatible XML API; it takes
+// HMAC keys in place of AWS credentials
+const GCSEndpoint = "https://storage.googleapis.com"
+
+// uploadRetry retries uploads failing with network errors or 5xx responses
+var uploadRetry = backoff.Policy{Base: time.Second, Max: 30 * time.Second, MaxAttempts: 5}
+
+// ObjectStore uploads objects to an S3-compatible bucket: AWS S3, GCS
+// through its XML API, MinIO or R2. Requests are path-style and signed with
+// SigV4 using the AWS SDK's default credential chain.
+type ObjectStore struct {
+	endpoint     string
+	bucket       string
+	region       string
+	storageClass string
+	creds        aws.CredentialsProvider
+	signer       *v4.Signer
+	client       *http.Client
+}
+
+// StoreConfig configures an ObjectStore
+type StoreConfig struct {
+	Bucket string
+	// Endpoint defaults to https://s3.<region>.amazonaws.com; GCSEndpoint
+	// for Google Cloud Storage
+	Endpoint string
+	// Region defaults to the SDK's configured region, or "auto" for
+	// non-AWS endpoints
+	Region string
+	// StorageClass is sent as x-amz-storage-class when set, e.g.
+	// STANDARD_IA or, on GCS, NEARLINE
+	StorageClass string
+}
+
+// NewObjectStore creates an ObjectStore, resolving credentials and region
+// through the AWS SDK's default configuration
+func NewObjectStore(ctx context.Context, cfg StoreConfig) (*ObjectStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("archive bucket is required")
+	}
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+
+	region := awsCfg.Region
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	switch {
+	case endpoint == "" && region == "":
+		return nil, fmt.Errorf("archive region is required for S3")
+	case endpoint == "":
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	case region == "":
+		region = "auto"
+	}
+	return &ObjectStore{
+		endpoint:     endpoint,
+		bucket:       cfg.Bucket,
+		region:       region,
+		storageClass: cfg.StorageClass,
+		creds:        awsCfg.Credentials,
+		signer:       v4.NewSigner(func(o *v4.SignerOptions) { o.DisableURIPathEscaping = true }),
+		client:       &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// Put uploads the file at path as key, retrying transient failures
+func (s *ObjectStore) Put(ctx context.Context, key, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return fmt.Errorf("hash %s: %w", path, err)
+	}
+	payloadHash := hex.EncodeToString(h.Sum(nil))
+
+	return backoff.Retry(ctx, uploadRetry, func(ctx context.Context) error {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return backoff.Permanent(err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key, ""), io.NopCloser(f))
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/vnd.apache.parquet")
+		if s.storageClass != "" {
+			req.Header.Set("X-Amz-Storage-Class", s.storageClass)
+		}
+		return s.do(ctx, req, payloadHash)
+	})
+}
+
+// LifecycleRule expires, and optionally first transitions, the objects
+// under a prefix
+type LifecycleRule struct {
+	Prefix          string
+	TransitionDays  int    // 0 keeps objects in their upload storage class
+	TransitionClass string // e.g. GLACIER_IR
+	ExpireDays      int    // 0 keeps objects forever
+}
+
+// PutLifecycle replaces the bucket's lifecycle configuration with rules.
+// It uses the S3 lifecycle API; GCS buckets configure lifecycle through
+// their own API instead.
+func (s *ObjectStore) PutLifecycle(ctx context.Context, rules []LifecycleRule) error {
+	type transition struct {
+		Days         int    `xml:"Days"`
+		StorageClass string `xml:"StorageClass"`
+	}
+	type expiration struct {
+		Days int `xml:"Days"`
+	}
+	type rule struct {
+		ID         string      `xml:"ID"`
+		Prefix     string      `xml:"Filter>Prefix"`
+		Status     string      `xml:"Status"`
+		Transition *transition `xml:"Transition,omitempty"`
+		Expiration *expiration `xml:"Expiration,omitempty"`
+	}
+	doc := struct {
+		XMLName xml.Name `xml:"LifecycleConfiguration"`
+		Rules   []rule   `xml:"Rule"`
+	}{}
+	for _, r := range rules {
+		x := rule{ID: "arb-archive-" + strings.ReplaceAll(strings.Trim(r.Prefix, "/"), "/", "-"), Prefix: r.Prefix, Status: "Enabled"}
+		if r.TransitionDays > 0 && r.TransitionClass != "" {
+			x.Transition = &transition{Days: r.TransitionDays, StorageClass: r.TransitionClass}
+		}
+		if r.ExpireDays > 0 {
+			x.Expiration = &expiration{Days: r.ExpireDays}
+		}
+		if x.Transition == nil && x.Expiration == nil {
+			continue
+		}
+		doc.Rules = append(doc.Rules, x)
+	}
+	if len(doc.Rules) == 0 {
+		return nil
+	}
+
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("encode lifecycle: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	md := md5.Sum(body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL("", "lifecycle="), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(md[:]))
+	return s.do(ctx, req, hex.EncodeToString(sum[:]))
+}
+
+// do signs and sends req. 5xx responses and network errors are returned
+// as retryable; other failures are permanent.
+func (s *ObjectStore) do(ctx context.Context, req *http.Request, payloadHash string) error {
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("retrieve credentials: %w", err))
+	}
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if err := s.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+		return backoff.Permanent(fmt.Errorf("sign request: %w", err))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("%s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return err
+	}
+	return backoff.Permanent(err)
+}
+
+// objectURL is the path-style URL of key in the bucket, or of the bucket
+// itself when key is empty. Path bytes outside
+// the unreserved set are percent-encoded, as SigV4's canonical URI for S3
+// requires.
+func (s *ObjectStore) objectURL(key, query string) string {
+	u, _ := url.Parse(s.endpoint)
+	p := strings.TrimSuffix(u.Path, "/") + "/" + s.bucket
+	if key != "" {
+		p += "/" + key
+	}
+	u.Path, u.RawPath = p, escapePath(p)
+	u.RawQuery = query
+	return u.String()
+}
+
+func escapePath(p string) string {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
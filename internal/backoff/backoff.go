@@ -0,0 +1,123 @@
+// Package backoff computes retry delays: exponential growth capped at a
+// maximum, with full jitter so clients that failed together do not retry
+// together. It backs the WebSocket clients' reconnects and REST retries.
+package backoff
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy describes a retry schedule. Attempt n waits a uniformly random
+// delay in [0, min(Max, Base*2^(n-1))], the "full jitter" scheme.
+type Policy struct {
+	Base        time.Duration // Delay bound after the first failure
+	Max         time.Duration // Cap on the delay bound; 0 means no cap
+	MaxAttempts int           // Attempts before giving up; 0 retries forever
+}
+
+// Bound returns the largest delay before retry attempt n (1-based)
+func (p Policy) Bound(attempt int) time.Duration {
+	bound := p.Base
+	for i := 1; i < attempt && (p.Max <= 0 || bound < p.Max); i++ {
+		bound *= 2
+	}
+	if p.Max > 0 && bound > p.Max {
+		bound = p.Max
+	}
+	return bound
+}
+
+// Backoff tracks the failures of one retried operation. It is not safe for
+// concurrent use.
+type Backoff struct {
+	policy   Policy
+	attempts int
+	jitter   func(bound time.Duration) time.Duration
+}
+
+// New creates a backoff following p
+func New(p Policy) *Backoff {
+	return &Backoff{policy: p, jitter: fullJitter}
+}
+
+// fullJitter returns a uniformly random duration in [0, bound]
+func fullJitter(bound time.Duration) time.Duration {
+	if bound <= 0 {
+		return 0
+	}
+	return rand.N(bound + 1)
+}
+
+// Next records a failure and returns the delay before retrying. ok is false
+// once the policy's attempts are exhausted.
+func (b *Backoff) Next() (delay time.Duration, ok bool) {
+	b.attempts++
+	if b.policy.MaxAttempts > 0 && b.attempts >= b.policy.MaxAttempts {
+		return 0, false
+	}
+	return b.jitter(b.policy.Bound(b.attempts)), true
+}
+
+// Reset clears the failures, e.g. after a successful connection
+func (b *Backoff) Reset() {
+	b.attempts = 0
+}
+
+// Attempts returns the failures recorded since the last Reset
+func (b *Backoff) Attempts() int {
+	return b.attempts
+}
+
+// Wait records a failure and sleeps the next delay. It returns false without
+// waiting once attempts are exhausted, or early when ctx is done.
+func (b *Backoff) Wait(ctx context.Context) bool {
+	delay, ok := b.Next()
+	if !ok {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// permanentError marks an error not worth retrying
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Retry returns it at once instead of retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// Retry calls fn until it succeeds, returns a Permanent error, the policy's
+// attempts run out or ctx is done, and returns fn's last error. Permanent
+// errors are returned unwrapped.
+func Retry(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	b := New(p)
+	for {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if !b.Wait(ctx) {
+			return err
+		}
+	}
+}
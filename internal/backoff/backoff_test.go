@@ -0,0 +1,94 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBound(t *testing.T) {
+	p := Policy{Base: time.Second, Max: 5 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 60: 5 * time.Second} {
+		if got := p.Bound(attempt); got != want {
+			t.Errorf("Bound(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+	if got := (Policy{Base: time.Second}).Bound(4); got != 8*time.Second {
+		t.Errorf("uncapped Bound(4) = %v", got)
+	}
+}
+
+func TestNextJittersWithinBound(t *testing.T) {
+	b := New(Policy{Base: 100 * time.Millisecond, Max: time.Second, MaxAttempts: 4})
+	var bounds []time.Duration
+	b.jitter = func(bound time.Duration) time.Duration { bounds = append(bounds, bound); return bound / 2 }
+
+	for i := 0; i < 3; i++ {
+		if delay, ok := b.Next(); !ok || delay != bounds[i]/2 {
+			t.Fatalf("attempt %d: delay %v, ok %v", i+1, delay, ok)
+		}
+	}
+	if _, ok := b.Next(); ok {
+		t.Error("expected attempts to run out after the fourth failure")
+	}
+	if want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}; len(bounds) != 3 || bounds[2] != want[2] {
+		t.Errorf("bounds = %v, want %v", bounds, want)
+	}
+
+	b.Reset()
+	if b.Attempts() != 0 {
+		t.Error("Reset kept attempts")
+	}
+
+	// Real jitter is spread over [0, bound]
+	spread := map[bool]bool{}
+	for i := 0; i < 200; i++ {
+		d := fullJitter(time.Second)
+		if d < 0 || d > time.Second {
+			t.Fatalf("jitter %v out of range", d)
+		}
+		spread[d < 500*time.Millisecond] = true
+	}
+	if len(spread) != 2 {
+		t.Error("jitter not spread across the bound")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	p := Policy{Base: time.Millisecond, MaxAttempts: 3}
+	ctx := context.Background()
+
+	calls := 0
+	err := Retry(ctx, p, func(context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("Retry = %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = Retry(ctx, p, func(context.Context) error { calls++; return errors.New("down") })
+	if err == nil || err.Error() != "down" || calls != 3 {
+		t.Errorf("exhausted Retry = %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	notFound := errors.New("not found")
+	err = Retry(ctx, p, func(context.Context) error { calls++; return Permanent(notFound) })
+	if err != notFound || calls != 1 {
+		t.Errorf("permanent Retry = %v after %d calls", err, calls)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	calls = 0
+	err = Retry(cancelled, Policy{Base: time.Hour}, func(context.Context) error { calls++; return errors.New("down") })
+	if err == nil || calls != 1 {
+		t.Errorf("cancelled Retry = %v after %d calls", err, calls)
+	}
+}
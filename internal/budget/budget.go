@@ -0,0 +1,252 @@
+// Package budget accounts for metered API usage per exchange: REST calls in
+// a sliding window and concurrent WebSocket subscriptions, each against an
+// optional budget. Non-critical work (status polling, pair refresh,
+// reconciliation) asks Allow before running and is throttled when usage
+// nears the budget, leaving headroom for trading-critical calls.
+package budget
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+const (
+	// DefaultWindow is the default REST budget window
+	DefaultWindow = time.Hour
+	// DefaultThrottleAt is the default fraction of a budget at which
+	// non-critical work is throttled
+	DefaultThrottleAt = 0.8
+
+	// windowBuckets is the resolution of the sliding REST window
+	windowBuckets = 60
+)
+
+// Priority classifies metered work
+type Priority int
+
+const (
+	// Critical work (bootstrap, order placement) always runs
+	Critical Priority = iota
+	// NonCritical work is skipped while usage is near the budget
+	NonCritical
+)
+
+// Limits is one exchange's budget; zero values are unlimited
+type Limits struct {
+	RESTCalls     int           // REST calls allowed per Window
+	Window        time.Duration // Defaults to DefaultWindow
+	Subscriptions int           // Concurrent WebSocket subscriptions
+}
+
+// Usage is a snapshot of one exchange's consumption
+type Usage struct {
+	Exchange          string `json:"exchange"`
+	RESTCalls         int    `json:"rest_calls"`
+	RESTLimit         int    `json:"rest_limit,omitempty"`
+	Window            string `json:"window"`
+	Subscriptions     int    `json:"subscriptions"`
+	SubscriptionLimit int    `json:"subscription_limit,omitempty"`
+	Throttled         bool   `json:"throttled"`
+}
+
+// meter tracks one exchange's usage
+type meter struct {
+	limits        Limits
+	bucketWidth   time.Duration
+	buckets       [windowBuckets]int
+	bucketStart   [windowBuckets]time.Time
+	subscriptions int
+}
+
+// Accountant tracks usage for every exchange; it is safe for concurrent use
+type Accountant struct {
+	mu         sync.Mutex
+	meters     map[string]*meter
+	throttleAt float64
+	now        func() time.Time
+}
+
+// NewAccountant creates an accountant with per-exchange limits. Exchanges
+// without limits are still metered. throttleAt <= 0 uses DefaultThrottleAt.
+func NewAccountant(limits map[string]Limits, throttleAt float64) *Accountant {
+	if throttleAt <= 0 || throttleAt > 1 {
+		throttleAt = DefaultThrottleAt
+	}
+	a := &Accountant{
+		meters:     make(map[string]*meter),
+		throttleAt: throttleAt,
+		now:        time.Now,
+	}
+	for exchange, l := range limits {
+		a.meters[exchange] = newMeter(l)
+	}
+	return a
+}
+
+func newMeter(l Limits) *meter {
+	if l.Window <= 0 {
+		l.Window = DefaultWindow
+	}
+	return &meter{limits: l, bucketWidth: l.Window / windowBuckets}
+}
+
+// meter returns the exchange's meter, creating an unlimited one if needed
+func (a *Accountant) meter(exchange string) *meter {
+	m, ok := a.meters[exchange]
+	if !ok {
+		m = newMeter(Limits{})
+		a.meters[exchange] = m
+	}
+	return m
+}
+
+// RecordREST counts one REST call against the exchange
+func (a *Accountant) RecordREST(exchange string) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	m := a.meter(exchange)
+	now := a.now()
+	slot := now.UnixNano() / int64(m.bucketWidth)
+	i := int(slot % windowBuckets)
+	start := time.Unix(0, slot*int64(m.bucketWidth))
+	if !m.bucketStart[i].Equal(start) {
+		m.bucketStart[i] = start
+		m.buckets[i] = 0
+	}
+	m.buckets[i]++
+	calls, limit := m.restCalls(now), m.limits.RESTCalls
+	a.mu.Unlock()
+
+	metrics.RecordAPICall(exchange)
+	metrics.SetAPIBudgetUsed(exchange, "rest", ratio(calls, limit))
+}
+
+// SetSubscriptions records the exchange's current WebSocket subscription count
+func (a *Accountant) SetSubscriptions(exchange string, n int) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	m := a.meter(exchange)
+	m.subscriptions = n
+	limit := m.limits.Subscriptions
+	a.mu.Unlock()
+
+	metrics.SetAPISubscriptions(exchange, n)
+	metrics.SetAPIBudgetUsed(exchange, "subscriptions", ratio(n, limit))
+}
+
+// SubscriptionsAvailable returns how many more subscriptions fit in the
+// exchange's budget, or -1 if it is unlimited
+func (a *Accountant) SubscriptionsAvailable(exchange string) int {
+	if a == nil {
+		return -1
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	m := a.meter(exchange)
+	if m.limits.Subscriptions <= 0 {
+		return -1
+	}
+	if free := m.limits.Subscriptions - m.subscriptions; free > 0 {
+		return free
+	}
+	return 0
+}
+
+// Allow reports whether work of the given priority may run against the
+// exchange now. Critical work is always allowed; non-critical work is
+// refused once REST or subscription usage reaches the throttle fraction.
+func (a *Accountant) Allow(exchange string, p Priority) bool {
+	if a == nil || p == Critical {
+		return true
+	}
+	a.mu.Lock()
+	throttled := a.throttled(a.meter(exchange), a.now())
+	a.mu.Unlock()
+
+	if throttled {
+		metrics.RecordAPIThrottled(exchange)
+	}
+	return !throttled
+}
+
+// throttled reports whether the meter is near its budget; caller holds mu
+func (a *Accountant) throttled(m *meter, now time.Time) bool {
+	if l := m.limits.RESTCalls; l > 0 && float64(m.restCalls(now)) >= a.throttleAt*float64(l) {
+		return true
+	}
+	if l := m.limits.Subscriptions; l > 0 && float64(m.subscriptions) >= a.throttleAt*float64(l) {
+		return true
+	}
+	return false
+}
+
+// Usage returns a snapshot for every known exchange, sorted by name
+func (a *Accountant) Usage() []Usage {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	usage := make([]Usage, 0, len(a.meters))
+	for exchange, m := range a.meters {
+		usage = append(usage, Usage{
+			Exchange:          exchange,
+			RESTCalls:         m.restCalls(now),
+			RESTLimit:         m.limits.RESTCalls,
+			Window:            m.limits.Window.String(),
+			Subscriptions:     m.subscriptions,
+			SubscriptionLimit: m.limits.Subscriptions,
+			Throttled:         a.throttled(m, now),
+		})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Exchange < usage[j].Exchange })
+	return usage
+}
+
+// restCalls sums the buckets that fall within the window ending at now
+func (m *meter) restCalls(now time.Time) int {
+	cutoff := now.Add(-m.limits.Window)
+	total := 0
+	for i, n := range m.buckets {
+		if m.bucketStart[i].After(cutoff) {
+			total += n
+		}
+	}
+	return total
+}
+
+// Client returns an HTTP client that counts every request against the exchange
+func (a *Accountant) Client(exchange string, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &transport{a: a, exchange: exchange, base: chaos.Transport(http.DefaultTransport)}}
+}
+
+// transport is an http.RoundTripper that records REST calls
+type transport struct {
+	a        *Accountant
+	exchange string
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.a.RecordREST(t.exchange)
+	return t.base.RoundTrip(req)
+}
+
+func ratio(n, limit int) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return float64(n) / float64(limit)
+}
@@ -0,0 +1,89 @@
+package budget
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAllowThrottlesNonCritical(t *testing.T) {
+	a := NewAccountant(map[string]Limits{"kalshi": {RESTCalls: 10, Window: time.Minute}}, 0.8)
+	now := time.Unix(1_700_000_000, 0)
+	a.now = func() time.Time { return now }
+
+	for i := 0; i < 7; i++ {
+		a.RecordREST("kalshi")
+	}
+	if !a.Allow("kalshi", NonCritical) {
+		t.Fatal("7/10 calls should not throttle at 0.8")
+	}
+
+	a.RecordREST("kalshi")
+	if a.Allow("kalshi", NonCritical) {
+		t.Error("8/10 calls should throttle non-critical work")
+	}
+	if !a.Allow("kalshi", Critical) {
+		t.Error("critical work must always be allowed")
+	}
+	if !a.Allow("polymarket", NonCritical) {
+		t.Error("exchanges without a budget must not throttle")
+	}
+
+	// Calls age out of the sliding window
+	now = now.Add(time.Minute + time.Second)
+	if !a.Allow("kalshi", NonCritical) {
+		t.Error("usage should reset once calls leave the window")
+	}
+}
+
+func TestSubscriptionBudget(t *testing.T) {
+	a := NewAccountant(map[string]Limits{"polymarket": {Subscriptions: 100}}, 0.9)
+
+	a.SetSubscriptions("polymarket", 40)
+	if got := a.SubscriptionsAvailable("polymarket"); got != 60 {
+		t.Errorf("expected 60 subscriptions available, got %d", got)
+	}
+	if got := a.SubscriptionsAvailable("kalshi"); got != -1 {
+		t.Errorf("unlimited exchange should report -1, got %d", got)
+	}
+
+	a.SetSubscriptions("polymarket", 95)
+	if a.Allow("polymarket", NonCritical) {
+		t.Error("95/100 subscriptions should throttle at 0.9")
+	}
+
+	usage := a.Usage()
+	if len(usage) != 2 || usage[0].Exchange != "kalshi" || !usage[1].Throttled {
+		t.Errorf("unexpected usage snapshot: %+v", usage)
+	}
+}
+
+func TestClientRecordsCalls(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	a := NewAccountant(nil, 0)
+	client := a.Client("kalshi", time.Second)
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("get: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	usage := a.Usage()
+	if len(usage) != 1 || usage[0].RESTCalls != 3 {
+		t.Errorf("expected 3 recorded calls, got %+v", usage)
+	}
+}
+
+func TestNilAccountant(t *testing.T) {
+	var a *Accountant
+	a.RecordREST("kalshi")
+	a.SetSubscriptions("kalshi", 5)
+	if !a.Allow("kalshi", NonCritical) {
+		t.Error("nil accountant must allow everything")
+	}
+}
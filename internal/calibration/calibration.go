@@ -0,0 +1,202 @@
+// Package calibration recommends a minimum edge per category from the
+// opportunities detected so far and what paper trading them realized.
+package calibration
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// Sample is one detected opportunity streak and the realized PnL of the
+// paper trades opened during it. A streak nothing was traded on could not be
+// filled, whether it was too short or too thin: it counts for nothing.
+type Sample struct {
+	Category string
+	EdgePct  float64 // The streak's peak edge, in percent ROI on turnover
+	Duration time.Duration
+	Trades   int
+	PnL      float64 // Net of fees
+}
+
+// Recommendation is the calibrated threshold of one category
+type Recommendation struct {
+	Category       string  `json:"category"`
+	CurrentPct     float64 `json:"current_pct"`
+	RecommendedPct float64 `json:"recommended_pct"`
+	Samples        int     `json:"samples"`
+	Trades         int     `json:"trades"` // Traded streaks at or above the recommendation
+	// ExpectedPnL is the PnL realized on streaks at or above the
+	// recommendation; CurrentPnL at or above the current threshold
+	ExpectedPnL float64 `json:"expected_pnl"`
+	CurrentPnL  float64 `json:"current_pnl"`
+	// MedianDurationSeconds is how long streaks at or above the
+	// recommendation lasted
+	MedianDurationSeconds float64 `json:"median_duration_seconds"`
+	Applied               bool    `json:"applied,omitempty"`
+}
+
+// Recommend picks, for each category with at least minSamples streaks and a
+// trade, the threshold among its streaks' edges and its current threshold
+// that maximizes the PnL of the streaks it admits. Ties go to the higher
+// threshold, which trades less for the same profit. Streaks are only
+// recorded above the threshold in force when detected, so a threshold is
+// only lowered as far as history reaches.
+func Recommend(samples []Sample, current func(category string) float64, minSamples int) []Recommendation {
+	byCategory := make(map[string][]Sample)
+	for _, s := range samples {
+		byCategory[s.Category] = append(byCategory[s.Category], s)
+	}
+
+	out := make([]Recommendation, 0, len(byCategory))
+	for category, samples := range byCategory {
+		if len(samples) < minSamples {
+			continue
+		}
+		// Highest edge first: each candidate admits a prefix
+		sort.Slice(samples, func(i, j int) bool { return samples[i].EdgePct > samples[j].EdgePct })
+
+		r := Recommendation{Category: category, CurrentPct: current(category), Samples: len(samples)}
+		r.CurrentPnL = admitted(samples, r.CurrentPct)
+		r.RecommendedPct, r.ExpectedPnL = r.CurrentPct, r.CurrentPnL
+		traded := false
+		for i, s := range samples {
+			traded = traded || s.Trades > 0
+			// Wait for the last streak at this edge before scoring it
+			if i+1 < len(samples) && samples[i+1].EdgePct == s.EdgePct {
+				continue
+			}
+			if pnl := admitted(samples, s.EdgePct); pnl > r.ExpectedPnL || (pnl == r.ExpectedPnL && s.EdgePct > r.RecommendedPct) {
+				r.RecommendedPct, r.ExpectedPnL = s.EdgePct, pnl
+			}
+		}
+		if !traded {
+			continue
+		}
+
+		var durations []time.Duration
+		for _, s := range samples {
+			if s.EdgePct >= r.RecommendedPct {
+				durations = append(durations, s.Duration)
+				if s.Trades > 0 {
+					r.Trades++
+				}
+			}
+		}
+		if len(durations) > 0 {
+			sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+			r.MedianDurationSeconds = durations[len(durations)/2].Seconds()
+		}
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Category < out[j].Category })
+	return out
+}
+
+// admitted sums the PnL of the streaks reaching pct
+func admitted(samples []Sample, pct float64) float64 {
+	var pnl float64
+	for _, s := range samples {
+		if s.EdgePct >= pct {
+			pnl += s.PnL
+		}
+	}
+	return pnl
+}
+
+// Options tunes a Calibrator
+type Options struct {
+	Window     time.Duration // History considered
+	MinSamples int           // Fewest streaks a category needs
+	Apply      bool          // Set the recommendations as category thresholds
+}
+
+// Report is the outcome of one calibration run
+type Report struct {
+	At              time.Time        `json:"at"`
+	Window          string           `json:"window"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
+
+// Calibrator periodically recommends, and optionally applies, per-category
+// thresholds from persisted history
+type Calibrator struct {
+	samples   func(ctx context.Context, since time.Time) ([]Sample, error)
+	threshold *arb.Threshold
+	opts      Options
+	logger    *slog.Logger
+
+	mu   sync.Mutex
+	last *Report
+}
+
+// New creates a calibrator reading history from samples and calibrating
+// threshold
+func New(samples func(ctx context.Context, since time.Time) ([]Sample, error), threshold *arb.Threshold, opts Options, logger *slog.Logger) *Calibrator {
+	return &Calibrator{samples: samples, threshold: threshold, opts: opts, logger: logger}
+}
+
+// Run calibrates every interval until ctx is done
+func (c *Calibrator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := c.Calibrate(ctx, now); err != nil {
+				c.logger.Error("threshold calibration failed", "error", err)
+			}
+		}
+	}
+}
+
+// Calibrate recommends thresholds from the history within the window
+// before now, applying them if enabled
+func (c *Calibrator) Calibrate(ctx context.Context, now time.Time) (Report, error) {
+	samples, err := c.samples(ctx, now.Add(-c.opts.Window))
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{At: now, Window: c.opts.Window.String(), Recommendations: Recommend(samples, c.threshold.For, c.opts.MinSamples)}
+	for i := range report.Recommendations {
+		r := &report.Recommendations[i]
+		if r.RecommendedPct == r.CurrentPct {
+			continue
+		}
+		c.logger.Info("edge threshold recommendation",
+			"category", r.Category,
+			"current_pct", r.CurrentPct,
+			"recommended_pct", r.RecommendedPct,
+			"expected_pnl", r.ExpectedPnL,
+			"current_pnl", r.CurrentPnL,
+			"samples", r.Samples,
+			"apply", c.opts.Apply,
+		)
+		if c.opts.Apply {
+			if err := c.threshold.SetCategory(r.Category, r.RecommendedPct); err != nil {
+				return Report{}, err
+			}
+			r.Applied = true
+		}
+	}
+
+	c.mu.Lock()
+	c.last = &report
+	c.mu.Unlock()
+	return report, nil
+}
+
+// Last returns the latest report, nil before the first run
+func (c *Calibrator) Last() *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
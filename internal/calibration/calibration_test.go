@@ -0,0 +1,86 @@
+package calibration
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+func TestRecommend(t *testing.T) {
+	samples := []Sample{
+		// Politics: thin edges lose money, 4% and up pays
+		{Category: "politics", EdgePct: 2, Trades: 1, PnL: -0.5},
+		{Category: "politics", EdgePct: 3, Trades: 1, PnL: -0.2},
+		{Category: "politics", EdgePct: 4, Trades: 1, PnL: 0.3, Duration: time.Minute},
+		{Category: "politics", EdgePct: 8, Trades: 1, PnL: 0.6, Duration: 3 * time.Minute},
+		{Category: "politics", EdgePct: 9, Duration: time.Second}, // Gone before it could fill
+		// Sports: never traded
+		{Category: "sports", EdgePct: 5},
+		{Category: "sports", EdgePct: 6},
+		{Category: "sports", EdgePct: 7},
+		// Crypto: too few streaks
+		{Category: "crypto", EdgePct: 5, Trades: 1, PnL: 1},
+	}
+	got := Recommend(samples, func(string) float64 { return 2 }, 3)
+	if len(got) != 1 {
+		t.Fatalf("expected a politics recommendation only, got %+v", got)
+	}
+	r := got[0]
+	if r.Category != "politics" || r.RecommendedPct != 4 || r.CurrentPct != 2 {
+		t.Errorf("unexpected recommendation %+v", r)
+	}
+	if diff := r.ExpectedPnL - 0.9; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected PnL = %v, want 0.9", r.ExpectedPnL)
+	}
+	if diff := r.CurrentPnL - 0.2; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("current PnL = %v, want 0.2", r.CurrentPnL)
+	}
+	if r.Trades != 2 || r.Samples != 5 || r.MedianDurationSeconds != 60 {
+		t.Errorf("unexpected counts %+v", r)
+	}
+}
+
+func TestRecommendTiesKeepHigherThreshold(t *testing.T) {
+	samples := []Sample{
+		{Category: "politics", EdgePct: 3},
+		{Category: "politics", EdgePct: 5, Trades: 1, PnL: 1},
+	}
+	got := Recommend(samples, func(string) float64 { return 3 }, 1)
+	if len(got) != 1 || got[0].RecommendedPct != 5 {
+		t.Errorf("expected 5%% for the same PnL with fewer trades, got %+v", got)
+	}
+}
+
+func TestCalibratorApply(t *testing.T) {
+	samples := []Sample{
+		{Category: "politics", EdgePct: 2, Trades: 1, PnL: -1},
+		{Category: "politics", EdgePct: 6, Trades: 1, PnL: 1},
+	}
+	source := func(context.Context, time.Time) ([]Sample, error) { return samples, nil }
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	threshold := arb.NewThreshold(2)
+	c := New(source, threshold, Options{Window: time.Hour, MinSamples: 1}, logger)
+	if c.Last() != nil {
+		t.Fatal("expected no report before the first run")
+	}
+	if _, err := c.Calibrate(context.Background(), time.Now()); err != nil {
+		t.Fatalf("Calibrate: %v", err)
+	}
+	if threshold.For("politics") != 2 || c.Last() == nil || c.Last().Recommendations[0].Applied {
+		t.Errorf("recommendation applied without Apply: %+v", c.Last())
+	}
+
+	c = New(source, threshold, Options{Window: time.Hour, MinSamples: 1, Apply: true}, logger)
+	report, err := c.Calibrate(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Calibrate: %v", err)
+	}
+	if threshold.For("politics") != 6 || threshold.Pct() != 2 || !report.Recommendations[0].Applied {
+		t.Errorf("expected politics raised to 6%%, got %v (default %v)", threshold.For("politics"), threshold.Pct())
+	}
+}
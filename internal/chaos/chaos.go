@@ -0,0 +1,272 @@
+// Package chaos injects faults into exchange traffic for staging builds:
+// WebSocket frames and REST responses are randomly delayed, dropped,
+// corrupted or, for frames, delivered out of order. The hooks the exchange
+// clients call (Frames, Transport) only act in binaries built with
+// -tags chaos and configured with Configure; in every other build they
+// compile to pass-throughs.
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Faults is the fault mix. Each rate is the probability, 0-1, that a frame
+// or response suffers the fault.
+type Faults struct {
+	Delay    float64
+	MaxDelay time.Duration // Delays are uniform up to this
+	Drop     float64
+	Corrupt  float64
+	Reorder  float64  // A frame is held back and delivered after the next one
+	Sources  []string // Frame sources faulted, e.g. "pm"; empty is every source
+	Seed     uint64   // 0 seeds from the clock
+}
+
+// ParseFaults parses a comma-separated spec such as
+// "delay=0.1,max_delay=500ms,drop=0.01,corrupt=0.05,reorder=0.02,sources=pm|kalshi,seed=7"
+func ParseFaults(spec string) (Faults, error) {
+	f := Faults{MaxDelay: time.Second}
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return Faults{}, fmt.Errorf("%q: want key=value", field)
+		}
+		var err error
+		switch key {
+		case "delay":
+			f.Delay, err = parseRate(value)
+		case "drop":
+			f.Drop, err = parseRate(value)
+		case "corrupt":
+			f.Corrupt, err = parseRate(value)
+		case "reorder":
+			f.Reorder, err = parseRate(value)
+		case "max_delay":
+			f.MaxDelay, err = time.ParseDuration(value)
+			if err == nil && f.MaxDelay <= 0 {
+				err = errors.New("must be positive")
+			}
+		case "sources":
+			f.Sources = strings.Split(value, "|")
+		case "seed":
+			f.Seed, err = strconv.ParseUint(value, 10, 64)
+		default:
+			return Faults{}, fmt.Errorf("unknown fault %q", key)
+		}
+		if err != nil {
+			return Faults{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return f, nil
+}
+
+func parseRate(s string) (float64, error) {
+	r, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if !(r >= 0 && r <= 1) {
+		return 0, fmt.Errorf("rate %v outside 0-1", r)
+	}
+	return r, nil
+}
+
+// Injector applies Faults to frames and responses. It is safe for
+// concurrent use.
+type Injector struct {
+	faults Faults
+
+	mu   sync.Mutex
+	rng  *rand.Rand
+	held map[string][]byte // Frame held back per source, for reordering
+}
+
+// NewInjector returns an injector applying f
+func NewInjector(f Faults) *Injector {
+	seed := f.Seed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	return &Injector{faults: f, rng: rand.New(rand.NewPCG(seed, seed)), held: make(map[string][]byte)}
+}
+
+// covers reports whether frames from source are faulted
+func (i *Injector) covers(source string) bool {
+	if len(i.faults.Sources) == 0 {
+		return true
+	}
+	for _, s := range i.faults.Sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
+// Frames passes frame, from the connection named source, to handle after
+// applying faults: it may sleep first, skip the frame, damage it, or hold
+// it back and hand it over after the source's next frame
+func (i *Injector) Frames(source string, frame []byte, handle func([]byte)) {
+	if !i.covers(source) {
+		handle(frame)
+		return
+	}
+
+	i.mu.Lock()
+	delay := i.delay()
+	if i.hit(i.faults.Drop) {
+		i.mu.Unlock()
+		sleep(context.Background(), delay)
+		return
+	}
+	if i.hit(i.faults.Corrupt) {
+		frame = i.corrupt(frame)
+	}
+	out := [][]byte{frame}
+	if held, ok := i.held[source]; ok {
+		delete(i.held, source)
+		out = append(out, held)
+	} else if i.hit(i.faults.Reorder) {
+		// Copied: readers may reuse the frame's buffer
+		i.held[source] = bytes.Clone(frame)
+		out = nil
+	}
+	i.mu.Unlock()
+
+	sleep(context.Background(), delay)
+	for _, f := range out {
+		handle(f)
+	}
+}
+
+// Transport wraps rt so its responses suffer the faults; reordering does not
+// apply to request-response traffic
+func (i *Injector) Transport(rt http.RoundTripper) http.RoundTripper {
+	return roundTripper{injector: i, next: rt}
+}
+
+type roundTripper struct {
+	injector *Injector
+	next     http.RoundTripper
+}
+
+// errDropped stands in for a response lost on the way
+var errDropped = errors.New("chaos: response dropped")
+
+func (t roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := t.injector
+	i.mu.Lock()
+	delay := i.delay()
+	drop := i.hit(i.faults.Drop)
+	corrupt := i.hit(i.faults.Corrupt)
+	i.mu.Unlock()
+
+	if !sleep(req.Context(), delay) {
+		return nil, req.Context().Err()
+	}
+	if drop {
+		return nil, errDropped
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !corrupt {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	i.mu.Lock()
+	body = i.corrupt(body)
+	i.mu.Unlock()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// hit draws whether a fault of the given rate strikes; i.mu must be held
+func (i *Injector) hit(rate float64) bool {
+	return rate > 0 && i.rng.Float64() < rate
+}
+
+// delay draws a delay, 0 when none strikes; i.mu must be held
+func (i *Injector) delay() time.Duration {
+	if !i.hit(i.faults.Delay) || i.faults.MaxDelay <= 0 {
+		return 0
+	}
+	return time.Duration(i.rng.Int64N(int64(i.faults.MaxDelay)))
+}
+
+// number matches a JSON number, bare or inside a string as venues quote prices
+var number = regexp.MustCompile(`-?\d+(\.\d+)?([eE][-+]?\d+)?`)
+
+// invalidValues replace a number when corrupting; none is a valid price,
+// size or timestamp
+var invalidValues = []string{"-7", "-0.5", "1e400", "NaN"}
+
+// garbage overwrites a byte when corrupting; each breaks the JSON or the
+// value it lands in
+var garbage = []byte{0, '"', '{'}
+
+// corrupt returns a damaged copy of data: a byte overwritten with garbage,
+// the tail cut off, or a number replaced by a value no field accepts; i.mu
+// must be held. Damage is kept detectable: a digit turned into another
+// plausible digit is indistinguishable from a real quote.
+func (i *Injector) corrupt(data []byte) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	out := bytes.Clone(data)
+	switch i.rng.IntN(3) {
+	case 0:
+		at, g := i.rng.IntN(len(out)), i.rng.IntN(len(garbage))
+		if out[at] == garbage[g] {
+			g = (g + 1) % len(garbage)
+		}
+		out[at] = garbage[g]
+	case 1:
+		out = out[:i.rng.IntN(len(out))]
+	default:
+		matches := number.FindAllIndex(out, -1)
+		if len(matches) == 0 {
+			out = out[:len(out)/2]
+			break
+		}
+		m := matches[i.rng.IntN(len(matches))]
+		value := invalidValues[i.rng.IntN(len(invalidValues))]
+		out = append(out[:m[0]:m[0]], append([]byte(value), out[m[1]:]...)...)
+	}
+	return out
+}
+
+// sleep waits for d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,85 @@
+package chaos
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseFaults(t *testing.T) {
+	f, err := ParseFaults("delay=0.1, max_delay=250ms,drop=0.01,corrupt=0.05,reorder=0.5,sources=pm|kalshi,seed=7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Delay != 0.1 || f.MaxDelay != 250*time.Millisecond || f.Drop != 0.01 || f.Corrupt != 0.05 ||
+		f.Reorder != 0.5 || len(f.Sources) != 2 || f.Seed != 7 {
+		t.Errorf("ParseFaults = %+v", f)
+	}
+
+	for _, spec := range []string{"drop=2", "corrupt=-0.1", "delay", "max_delay=0s", "jitter=0.1", "seed=x"} {
+		if _, err := ParseFaults(spec); err == nil {
+			t.Errorf("ParseFaults(%q) accepted", spec)
+		}
+	}
+}
+
+// collect runs frames through the injector and returns what reaches the handler
+func collect(i *Injector, source string, frames ...string) []string {
+	var got []string
+	for _, f := range frames {
+		i.Frames(source, []byte(f), func(b []byte) { got = append(got, string(b)) })
+	}
+	return got
+}
+
+func TestInjectorFrames(t *testing.T) {
+	if got := collect(NewInjector(Faults{Drop: 1, Seed: 1}), "pm", "a", "b"); len(got) != 0 {
+		t.Errorf("drop=1 delivered %q", got)
+	}
+
+	got := collect(NewInjector(Faults{Reorder: 1, Seed: 1}), "pm", "1", "2", "3", "4")
+	if want := []string{"2", "1", "4", "3"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] || got[3] != want[3] {
+		t.Errorf("reorder=1 delivered %q, want %q", got, want)
+	}
+
+	frame := `{"event_type":"price_change","asset":"t1","price":"0.45","size":"100"}`
+	corrupt := NewInjector(Faults{Corrupt: 1, Seed: 1})
+	for n := 0; n < 100; n++ {
+		if got := collect(corrupt, "pm", frame); len(got) != 1 || got[0] == frame {
+			t.Fatalf("corrupt=1 delivered %q unchanged", got)
+		}
+	}
+
+	scoped := NewInjector(Faults{Drop: 1, Sources: []string{"kalshi"}, Seed: 1})
+	if got := collect(scoped, "pm", "a"); len(got) != 1 {
+		t.Errorf("frames from a source not listed were faulted: %q", got)
+	}
+}
+
+func TestInjectorTransport(t *testing.T) {
+	const body = `{"markets":[{"ticker":"K1","yes_bid":0.43,"yes_ask":0.45}]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer srv.Close()
+
+	dropped := &http.Client{Transport: NewInjector(Faults{Drop: 1, Seed: 1}).Transport(http.DefaultTransport)}
+	if _, err := dropped.Get(srv.URL); !errors.Is(err, errDropped) {
+		t.Errorf("drop=1 returned %v, want a dropped response", err)
+	}
+
+	corrupted := &http.Client{Transport: NewInjector(Faults{Corrupt: 1, Seed: 1}).Transport(http.DefaultTransport)}
+	resp, err := corrupted.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || bytes.Equal(got, []byte(body)) {
+		t.Errorf("corrupt=1 returned %q, %v", got, err)
+	}
+}
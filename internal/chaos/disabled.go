@@ -0,0 +1,26 @@
+//go:build !chaos
+
+package chaos
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Enabled reports whether the hooks are compiled in
+const Enabled = false
+
+// Configure fails: the hooks are only compiled in with -tags chaos
+func Configure(spec string) error {
+	return errors.New("chaos hooks are not compiled in; build with -tags chaos")
+}
+
+// Frames hands frame to handle unchanged
+func Frames(source string, frame []byte, handle func([]byte)) {
+	handle(frame)
+}
+
+// Transport returns rt unchanged
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return rt
+}
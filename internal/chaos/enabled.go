@@ -0,0 +1,51 @@
+//go:build chaos
+
+package chaos
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Enabled reports whether the hooks are compiled in
+const Enabled = true
+
+// active is the injector the hooks apply; nil until Configure
+var active atomic.Pointer[Injector]
+
+// Configure starts injecting the faults spec describes (see ParseFaults)
+func Configure(spec string) error {
+	f, err := ParseFaults(spec)
+	if err != nil {
+		return err
+	}
+	active.Store(NewInjector(f))
+	return nil
+}
+
+// Frames hands frame to handle through the configured faults
+func Frames(source string, frame []byte, handle func([]byte)) {
+	if i := active.Load(); i != nil {
+		i.Frames(source, frame, handle)
+		return
+	}
+	handle(frame)
+}
+
+// Transport wraps rt so its responses suffer the configured faults
+func Transport(rt http.RoundTripper) http.RoundTripper {
+	return hookTransport{next: rt}
+}
+
+// hookTransport looks the injector up per request, so clients built before
+// Configure are faulted too
+type hookTransport struct {
+	next http.RoundTripper
+}
+
+func (t hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if i := active.Load(); i != nil {
+		return i.Transport(t.next).RoundTrip(req)
+	}
+	return t.next.RoundTrip(req)
+}
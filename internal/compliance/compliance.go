@@ -0,0 +1,156 @@
+package compliance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+)
+
+const geoCheckTimeout = 5 * time.Second
+
+// Feature names reported in the compliance status
+const (
+	FeatureScanning  = "scanning"
+	FeatureExecution = "execution"
+)
+
+// FeatureState describes whether a feature is active and, if not, why
+type FeatureState struct {
+	Feature string `json:"feature"`
+	Venue   string `json:"venue,omitempty"`
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Status is the result of the startup region check
+type Status struct {
+	Region       string         `json:"region"`
+	RegionSource string         `json:"region_source"` // "config", "geo_check" or "unknown"
+	CheckedAt    time.Time      `json:"checked_at"`
+	Features     []FeatureState `json:"features"`
+}
+
+// ExecutionAllowed reports whether trading on the given venue is permitted
+func (s *Status) ExecutionAllowed(venue string) bool {
+	for _, f := range s.Features {
+		if f.Feature == FeatureExecution && f.Venue == venue {
+			return f.Enabled
+		}
+	}
+	return false
+}
+
+// Check determines the operating region and which features must be disabled there.
+// Read-only scanning is always kept enabled; only execution is gated by region.
+func Check(ctx context.Context, cfg *config.Config, logger *slog.Logger) *Status {
+	status := &Status{
+		Region:       strings.ToUpper(strings.TrimSpace(cfg.Region)),
+		RegionSource: "config",
+		CheckedAt:    time.Now(),
+	}
+
+	if status.Region == "" {
+		region, err := detectRegion(ctx, cfg.GeoCheckURL)
+		if err != nil {
+			logger.Warn("region detection failed", "error", err)
+			status.RegionSource = "unknown"
+		} else {
+			status.Region = region
+			status.RegionSource = "geo_check"
+		}
+	}
+
+	status.Features = append(status.Features, FeatureState{Feature: FeatureScanning, Enabled: true})
+
+	venues := []struct {
+		name       string
+		restricted []string
+	}{
+		{"polymarket", cfg.PMRestrictedRegions},
+		{"kalshi", cfg.KalshiRestrictedRegions},
+	}
+
+	for _, v := range venues {
+		state := FeatureState{Feature: FeatureExecution, Venue: v.name, Enabled: true}
+		switch {
+		case !cfg.ExecutionEnabled:
+			state.Enabled = false
+			state.Reason = "execution disabled in config (EXECUTION_ENABLED=false)"
+		case status.Region == "":
+			state.Enabled = false
+			state.Reason = "operating region could not be determined; set REGION explicitly"
+		case regionRestricted(status.Region, v.restricted):
+			state.Enabled = false
+			state.Reason = fmt.Sprintf("trading on %s is not permitted from region %s", v.name, status.Region)
+		}
+		status.Features = append(status.Features, state)
+	}
+
+	for _, f := range status.Features {
+		if !f.Enabled {
+			logger.Warn("feature disabled", "feature", f.Feature, "venue", f.Venue, "reason", f.Reason)
+		}
+	}
+
+	return status
+}
+
+// regionRestricted matches a region against a restriction list. An entry "US"
+// matches both "US" and any subdivision such as "US-NY"; "US-NY" only matches itself.
+func regionRestricted(region string, restricted []string) bool {
+	for _, r := range restricted {
+		r = strings.ToUpper(r)
+		if region == r || strings.HasPrefix(region, r+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// detectRegion queries a geoblock-style endpoint returning {"country": "..", "region": ".."}
+func detectRegion(ctx context.Context, url string) (string, error) {
+	if url == "" {
+		return "", fmt.Errorf("no geo check url configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, geoCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Country string `json:"country"`
+		Region  string `json:"region"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	country := strings.ToUpper(strings.TrimSpace(result.Country))
+	if country == "" {
+		return "", fmt.Errorf("empty country in geo check response")
+	}
+	if sub := strings.ToUpper(strings.TrimSpace(result.Region)); sub != "" {
+		return country + "-" + sub, nil
+	}
+	return country, nil
+}
@@ -0,0 +1,62 @@
+package compliance
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/config"
+)
+
+func TestRegionRestricted(t *testing.T) {
+	tests := []struct {
+		name       string
+		region     string
+		restricted []string
+		expected   bool
+	}{
+		{name: "exact country", region: "US", restricted: []string{"US"}, expected: true},
+		{name: "subdivision of country", region: "US-NY", restricted: []string{"us"}, expected: true},
+		{name: "other subdivision", region: "US-CA", restricted: []string{"US-NY"}, expected: false},
+		{name: "prefix is not a match", region: "USA", restricted: []string{"US"}, expected: false},
+		{name: "empty list", region: "DE", restricted: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := regionRestricted(tt.region, tt.restricted); got != tt.expected {
+				t.Errorf("regionRestricted(%q, %v) = %v, want %v", tt.region, tt.restricted, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	cfg := &config.Config{
+		Region:                  "us-ny",
+		PMRestrictedRegions:     []string{"US"},
+		KalshiRestrictedRegions: nil,
+		ExecutionEnabled:        true,
+	}
+
+	status := Check(context.Background(), cfg, logger)
+
+	if status.Region != "US-NY" || status.RegionSource != "config" {
+		t.Fatalf("unexpected region %q from %q", status.Region, status.RegionSource)
+	}
+	if status.ExecutionAllowed("polymarket") {
+		t.Error("expected polymarket execution to be disabled in US")
+	}
+	if !status.ExecutionAllowed("kalshi") {
+		t.Error("expected kalshi execution to be enabled in US")
+	}
+
+	cfg.ExecutionEnabled = false
+	status = Check(context.Background(), cfg, logger)
+	if status.ExecutionAllowed("kalshi") {
+		t.Error("expected execution to be disabled when not enabled in config")
+	}
+}
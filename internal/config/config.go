@@ -1,43 +1,646 @@
 package config
 
 import (
-	"os"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 )
 
-// Config holds all application configuration loaded from environment variables.
+// Config holds all application configuration. Each setting is named by an
+// environment variable; see LoadArgs for the other ways to set it.
 type Config struct {
-	HTTPAddr       string
-	EdgeMinRORPct  float64
-	TitleSim       float64
-	TimeWindowH    int
-	PMChunk        int
-	KalshiKeyID    string
-	KalshiKeyPath  string
+	HTTPAddr      string
+	EdgeMinRORPct float64
+	TitleSim      float64
+	TimeWindowH   int
+	PMChunk       int
+	KalshiKeyID   string
+	KalshiKeyPath string // File path, or a reference in the configured secrets provider
+
+	// KalshiPollInterval spaces polls of Kalshi's public markets endpoint for
+	// quotes when no Kalshi credentials are configured, in place of the
+	// authenticated WebSocket; 0 leaves Kalshi disabled without credentials
+	KalshiPollInterval time.Duration
+
+	// HTTPRequestTimeout caps handler run time; HTTPRouteTimeouts overrides
+	// it per route as "pattern=duration" (0 disables). Headers and request
+	// bodies larger than HTTPMaxHeaderBytes and HTTPMaxBodyBytes are refused.
+	HTTPRequestTimeout    time.Duration
+	HTTPRouteTimeouts     []string
+	HTTPReadHeaderTimeout time.Duration
+	HTTPMaxHeaderBytes    int
+	HTTPMaxBodyBytes      int64
+
+	// HTTPAdminAddr and HTTPMetricsAddr serve the admin API (/admin/*, /ops,
+	// pair review decisions, annotation and acknowledgment POSTs) and
+	// /metrics on listeners of their own, removing them from HTTPAddr. Each is a TCP host:port or
+	// "unix:/path/to.sock"; empty keeps the routes on HTTPAddr. With a
+	// separate admin listener, point a peer's WarmStartURL at it.
+	HTTPAdminAddr   string
+	HTTPMetricsAddr string
+
+	// MatchAbbreviations adds or overrides matcher abbreviations as
+	// "abbr=expansion"; MatchAbbreviationsDisable removes entries ("*" drops
+	// every default). See match.ParseAbbreviations.
+	MatchAbbreviations        []string
+	MatchAbbreviationsDisable []string
+	// MatchCategoryStopWords ("category:word") and MatchCategoryAliases
+	// ("category:alias=expansion") tune the matcher within one category, as
+	// match.Category detects it. See match.ParseVocabularies.
+	MatchCategoryStopWords []string
+	MatchCategoryAliases   []string
+
+	// NearMissMinSim is the lowest title similarity kept as a near-miss for
+	// review at /pairs/near-misses; pairs between it and TitleSim are not
+	// monitored unless promoted. 0 disables near-miss tracking.
+	NearMissMinSim float64
+
+	// MatchMaxPerPM and MatchMaxPerKalshi cap how many pairs one market may
+	// be in, keeping its best-scoring matches; the rest are reported at
+	// /status as collisions. Raise them for legitimate one-to-many mappings,
+	// 0 is unlimited.
+	MatchMaxPerPM     int
+	MatchMaxPerKalshi int
+
+	// PairAnnotationsFile is a JSON mapping file of operator notes and
+	// verified flags per pair (see arb.LoadAnnotationsFile). Annotations set
+	// through POST /pairs/annotations are persisted and take precedence.
+	PairAnnotationsFile string
+
+	// BootstrapTopK enables a two-phase bootstrap: startup matches only the
+	// most liquid Kalshi markets until this many pairs are found, and the long
+	// tail is matched in the background, BootstrapBatch markets at a time,
+	// activating pairs as they are confirmed. 0 matches everything up front.
+	BootstrapTopK  int
+	BootstrapBatch int
+
+	// SecretsProvider selects where credentials are loaded from: "file" (default),
+	// "vault", "aws" (Secrets Manager) or "age" (age-encrypted files)
+	SecretsProvider string
+	VaultAddr       string
+	VaultToken      string
+	AWSRegion       string
+	AgeIdentityFile string
+
+	// Exchange endpoints; override to point at cmd/fake-exchange or a demo environment
+	PMRESTURL     string
+	PMWSURL       string
+	PMGammaURL    string // Market activity (volume, liquidity); empty disables it
+	KalshiRESTURL string
+	KalshiWSURL   string
+
+	// WebSocket dialer options per exchange (see ws.DialOptions). Compression
+	// negotiates permessage-deflate; NoDelay=false re-enables Nagle's
+	// algorithm; LocalAddr or Interface bind outgoing connections.
+	PMWSCompression          bool
+	PMWSNoDelay              bool
+	PMWSLocalAddr            string
+	PMWSInterface            string
+	PMWSHandshakeTimeout     time.Duration
+	KalshiWSCompression      bool
+	KalshiWSNoDelay          bool
+	KalshiWSLocalAddr        string
+	KalshiWSInterface        string
+	KalshiWSHandshakeTimeout time.Duration
+
+	// A WebSocket connection is flapping once it connects more than
+	// WSFlapMaxReconnects times within WSFlapWindow: it raises an ops alert
+	// and keeps its reconnect backoff growing. 0 disables detection.
+	WSFlapMaxReconnects int
+	WSFlapWindow        time.Duration
+
+	// Region is the ISO country code (optionally "CC-SUB") the service runs from.
+	// When empty, the region is detected at startup via GeoCheckURL.
+	Region                  string
+	GeoCheckURL             string
+	PMRestrictedRegions     []string
+	KalshiRestrictedRegions []string
+	ExecutionEnabled        bool
+
+	// KillSwitch starts with execution disabled by the kill switch. It trips
+	// automatically when realized losses (USD) or failed unwinds within
+	// KillSwitchWindow exceed their maximums; 0 disables a check.
+	KillSwitch                 bool
+	KillSwitchMaxLoss          float64
+	KillSwitchMaxFailedUnwinds int
+	KillSwitchWindow           time.Duration
+	// AdminToken authorizes admin endpoints (Authorization: Bearer)
+	AdminToken string
+
+	// WarmStartURL is a running peer's base URL. When set, the pair set,
+	// quotes no older than WarmStartMaxAge and open opportunities are imported
+	// from its /admin/warmstart instead of bootstrapping from scratch, for
+	// blue/green deploys. The peer is called with AdminToken.
+	WarmStartURL    string
+	WarmStartMaxAge time.Duration
+
+	// AccountStreams ingests our own fills/cancels from Kalshi's fill channel and
+	// Polymarket's authenticated user channel
+	AccountStreams  bool
+	PMUserWSURL     string
+	PMAPIKey        string
+	PMAPISecret     string
+	PMAPIPassphrase string
+	// PMAPICredentialsRef loads the credentials above as a JSON object
+	// ({"apiKey","secret","passphrase"}) from the secrets provider instead
+	PMAPICredentialsRef string
+	// PMWalletKeyPath derives the credentials at startup when they are not
+	// set, from the wallet's hex private key: a file path, or a reference in
+	// the configured secrets provider. PMAPINonce selects which of the
+	// wallet's credentials.
+	PMWalletKeyPath string
+	PMAPINonce      int
+
+	// KalshiMaintenanceWindows lists recurring Kalshi maintenance windows, e.g.
+	// "thu 03:00-05:00"; see schedule.Parse. Times are in KalshiMaintenanceTZ.
+	KalshiMaintenanceWindows string
+	KalshiMaintenanceTZ      string
+
+	// KalshiTradingHours lists the trading hours of Kalshi series that do not
+	// trade around the clock, e.g. "KXINX=mon/tue/wed/thu/fri 09:30-16:00";
+	// see schedule.ParseCalendar. Outside them, and on KalshiHolidays, their
+	// pairs are reported as venue closed. Times are in KalshiTradingHoursTZ.
+	KalshiTradingHours   string
+	KalshiHolidays       []string // YYYY-MM-DD
+	KalshiTradingHoursTZ string
+
+	// KalshiBackfillMinGap is the shortest Kalshi disconnection backfilled
+	// from the candlestick API once reconnected; 0 disables backfill
+	KalshiBackfillMinGap time.Duration
+
+	// FreezeScanInterval is how often instrument feeds are checked for
+	// anomalous silence relative to their learned update rate; 0 disables.
+	// See feedhealth.Options for the other settings.
+	FreezeScanInterval time.Duration
+	FreezeFactor       float64
+	FreezeMinSamples   int
+	FreezeMinQuiet     time.Duration
+
+	// FeedQualityInterval is how often each venue feed's data quality is
+	// scored; 0 disables scoring. A feed reaching one of the thresholds is
+	// excluded from opportunity generation, and one scoring below
+	// FeedQualityDegradedBelow is down-weighted; see feedhealth.QualityOptions.
+	FeedQualityInterval             time.Duration
+	FeedQualityWindow               time.Duration
+	FeedQualityMaxParseFailurePct   float64
+	FeedQualityMaxCrossedPct        float64
+	FeedQualityMaxStaleness         time.Duration
+	FeedQualityMaxReconnectsPerHour float64
+	FeedQualityDegradedBelow        float64
+
+	// MarketStatusInterval is how often venue market statuses are polled for
+	// halts; 0 disables polling
+	MarketStatusInterval time.Duration
+
+	// API budgets for exchanges that meter usage; 0 is unlimited. REST
+	// budgets count calls per APIBudgetWindow; non-critical work is throttled
+	// once usage reaches APIBudgetThrottleAt of a budget. Subscription
+	// budgets are also hard limits: matched pairs past them are pruned, best
+	// pairs kept (see arb.SubscriptionPruner).
+	PMRESTBudget             int
+	KalshiRESTBudget         int
+	PMSubscriptionBudget     int
+	KalshiSubscriptionBudget int
+	APIBudgetWindow          time.Duration
+	APIBudgetThrottleAt      float64
+
+	// DBPath is the SQLite database for persisted history; empty disables persistence
+	DBPath string
+
+	// Filters lists opportunity filters in evaluation order; see arb.BuildFilters
+	Filters                       []string
+	FilterMinSize                 float64
+	FilterMinLiquidity            float64
+	FilterMaxQuoteAge             time.Duration
+	FilterCategories              []string
+	FilterMinResolutionConfidence float64
+	FilterMinConfidence           float64
+	FilterMinAnnualizedReturn     float64
+	// FilterRule is the rule filter's CEL predicate, e.g.
+	// "edge_pct > 4 && category == 'economics'"; see arb.CompileRule
+	FilterRule string
+
+	// Strategies lists the engine strategies run every cycle; see
+	// arb.BuildStrategies. DivergenceMin is the implied probability gap the
+	// divergence strategy needs (0-1); SpreadCaptureMinRORPct is the edge the
+	// spread_capture strategy needs with the Kalshi leg at its bid.
+	Strategies             []string
+	DivergenceMin          float64
+	SpreadCaptureMinRORPct float64
+
+	// Trading fees, netted out of opportunities' edges and charged by POST
+	// /evaluate, see arb.FeeSchedule. KalshiFeeRate and PMFeeRate are taker
+	// fee rates (see arb.TakerFee), also charged on shadow fills;
+	// PMRelayerFee is USD per Polymarket order. The rates fall back to
+	// SHADOW_KALSHI_FEE_RATE and SHADOW_PM_FEE_RATE, their earlier names.
+	KalshiFeeRate float64
+	PMFeeRate     float64
+	PMRelayerFee  float64
+
+	// ShadowTrading paper-trades every strategy in its own virtual ledger,
+	// ShadowSize contracts per trade. ExecutionStrategy names the strategy
+	// served by live execution, for comparison against the shadows. Settled
+	// PnL is kept for /pnl when DB_PATH is set.
+	ShadowTrading     bool
+	ShadowSize        float64
+	ExecutionStrategy string
+
+	// CalibrationInterval is how often each category's minimum edge is
+	// calibrated against the streaks detected and the paper trades settled
+	// over CalibrationWindow; 0 disables. It needs DB_PATH and shadow
+	// trading. Categories with fewer than CalibrationMinSamples streaks are
+	// left alone. Recommendations are logged and shown under /status, and
+	// replace the category's threshold until restart when CalibrationApply
+	// is set.
+	CalibrationInterval   time.Duration
+	CalibrationWindow     time.Duration
+	CalibrationMinSamples int
+	CalibrationApply      bool
+
+	// Smarkets adapter: when enabled, contracts in SmarketsTypeDomains are
+	// matched to Kalshi markets at bootstrap and their quotes streamed for
+	// comparison. SmarketsSessionToken authenticates the quote stream.
+	SmarketsEnabled      bool
+	SmarketsRESTURL      string
+	SmarketsWSURL        string
+	SmarketsSessionToken string
+	SmarketsTypeDomains  []string
+
+	// EngineStallTimeout is how long the engine may go without completing a
+	// compute cycle before the systemd watchdog heartbeat is withheld
+	EngineStallTimeout time.Duration
+
+	// Compute cadence: opportunities are computed every ComputeInterval.
+	// While an instrument receives more than ComputeHotUpdates price updates
+	// between cycles, the interval halves down to ComputeMinInterval and that
+	// instrument's updates trigger a cycle at once. 0 hot updates disables
+	// adaptation.
+	ComputeInterval    time.Duration
+	ComputeMinInterval time.Duration
+	ComputeHotUpdates  int
+
+	// DiagDumpDir receives the diagnostic dumps written on SIGUSR1; empty
+	// uses the system temporary directory
+	DiagDumpDir string
+
+	// ConfidenceHalfLife is the quote age at which an opportunity leg's confidence halves
+	ConfidenceHalfLife time.Duration
+
+	// ExecutionWindow is how long after detection an opportunity can be
+	// claimed, executed or shadow-traded; past it the opportunity is marked
+	// expired. 0 disables the window.
+	ExecutionWindow time.Duration
+
+	// UnhedgedMinEdge is how far below the other venue's implied
+	// probability, in probability points, a leg on a one-sided book must be
+	// offered to raise an unhedged signal. 0 disables the signals.
+	UnhedgedMinEdge float64
+
+	// MaxOpportunities caps the published opportunity list; OpportunityRetention
+	// is how long the edge history of a vanished opportunity stays queryable
+	MaxOpportunities     int
+	OpportunityRetention time.Duration
+
+	// Notification destinations; each is enabled when its settings are present
+	NotifyWebhookURL      string
+	NotifySlackWebhookURL string
+	NotifyTelegramToken   string
+	NotifyTelegramChatID  string
+	NotifyWorkers         int
+	NotifyQueueSize       int
+	NotifyTimeout         time.Duration
+	NotifyDeadLetterPath  string
+	// NotifySuppressAcked drops pending alerts for opportunities a consumer
+	// has acknowledged via POST /arbs/{id}/ack
+	NotifySuppressAcked bool
+	// NotifyAlertCooldown is how long an alerted opportunity must be gone
+	// before its return alerts again. With DBPath set, alerts are remembered
+	// across restarts for as long.
+	NotifyAlertCooldown time.Duration
+
+	// NotifySenders and Publishers build additional registered sender and
+	// publisher kinds, each as "kind?key=value&key=value" (see
+	// notify.RegisterSender), e.g. "mqtt?broker=tcp://localhost:1883&qos=1"
+	// to stream opportunities to an MQTT broker. Plugins launches external
+	// plugin processes, each as "name=/path/to/plugin [args...]".
+	NotifySenders []string
+	Publishers    []string
+	Plugins       []string
+
+	// OpsNotifySenders receive operational alerts, which point at bad data
+	// rather than opportunities; same spec format as NotifySenders. Without
+	// any, ops alerts are only logged.
+	OpsNotifySenders []string
+
+	// NewsMovePct holds a pair out of opportunities while either venue's
+	// implied probability has moved more than this many percentage points
+	// within NewsWindow; 0 disables
+	NewsMovePct float64
+	NewsWindow  time.Duration
+
+	// EdgeJumpAlertPP raises an ops alert when the best edge rises by more
+	// than this many percentage points within one compute cycle; 0 disables
+	EdgeJumpAlertPP float64
+
+	// SilenceAlarmAfter raises an ops alert and the arb_silent_failure gauge
+	// when, with both feeds connected and at least SilenceMinPairs pairs
+	// evaluated, no opportunity and no edge within SilenceMarginPP points of
+	// the threshold has been seen for this long; 0 disables
+	SilenceAlarmAfter time.Duration
+	SilenceMarginPP   float64
+	SilenceMinPairs   int
+
+	// SpreadSampleInterval is how often each pair's venue mids are persisted
+	// for /pairs/{id}/spread (requires DB_PATH); 0 disables. Samples older
+	// than SpreadRetention are deleted.
+	SpreadSampleInterval time.Duration
+	SpreadRetention      time.Duration
+
+	// ArchiveBucket enables archiving (requires DB_PATH): every
+	// ArchiveInterval, whole days of opportunity history and spread samples
+	// older than ArchiveAge are uploaded as Parquet under ArchivePrefix and
+	// deleted locally. ArchiveEndpoint selects an S3-compatible service
+	// (https://storage.googleapis.com with HMAC keys for GCS); credentials
+	// come from the AWS SDK's default chain. ArchiveAge should stay below
+	// SpreadRetention so samples are archived before they are pruned.
+	ArchiveBucket       string
+	ArchiveEndpoint     string
+	ArchiveRegion       string
+	ArchivePrefix       string
+	ArchiveAge          time.Duration
+	ArchiveInterval     time.Duration
+	ArchiveStorageClass string
+
+	// ArchiveTransitionDays and ArchiveExpireDays, when set, replace the
+	// bucket's lifecycle configuration at startup with rules moving archived
+	// files to ArchiveTransitionClass and deleting them after that many
+	// days. S3 only; configure GCS lifecycle on the bucket itself.
+	ArchiveTransitionDays  int
+	ArchiveTransitionClass string
+	ArchiveExpireDays      int
+
+	// Chaos injects faults into exchange traffic, as a chaos.ParseFaults
+	// spec, e.g. "drop=0.01,corrupt=0.05,reorder=0.02". Only binaries built
+	// with -tags chaos, for staging, accept it.
+	Chaos string
+
+	// Demo (--demo) adds DemoPairs synthetic pairs and misprices one in
+	// turn every DemoInterval, for DemoHold, at DemoEdgePct on turnover, so
+	// dashboards and alert routes can be shown without real edges. See
+	// arb.Demo; zero values use its defaults.
+	Demo         bool
+	DemoPairs    int
+	DemoInterval time.Duration
+	DemoHold     time.Duration
+	DemoEdgePct  float64
+
+	// Check is set by --check: validate the configuration, probe every
+	// external dependency, print a report and exit. It has no environment
+	// variable.
+	Check bool
+
+	// Profile is the settings profile selected by --profile or PROFILE, see
+	// Profiles
+	Profile ProfileInfo
 }
 
 // Load reads configuration from environment variables with default values.
 func Load() *Config {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+	return build()
+}
+
+// build reads every setting through the current source
+func build() *Config {
 	return &Config{
-		HTTPAddr:       getEnv("HTTP_ADDR", ":8080"),
-		EdgeMinRORPct:  getEnvFloat("EDGE_MIN_ROR_PCT", 3.0),
-		TitleSim:       getEnvFloat("TITLE_SIM", 0.60),
-		TimeWindowH:    getEnvInt("TIME_WINDOW_H", 168),
-		PMChunk:        getEnvInt("PM_CHUNK", 400),
-		KalshiKeyID:    getEnv("KALSHI_KEY_ID", ""),
-		KalshiKeyPath:  getEnv("KALSHI_PRIVATE_KEY_PATH", ""),
+		HTTPAddr:      getEnv("HTTP_ADDR", ":8080"),
+		EdgeMinRORPct: getEnvFloat("EDGE_MIN_ROR_PCT", 3.0),
+		TitleSim:      getEnvFloat("TITLE_SIM", 0.60),
+		TimeWindowH:   getEnvInt("TIME_WINDOW_H", 168),
+		PMChunk:       getEnvInt("PM_CHUNK", 400),
+		KalshiKeyID:   getEnv("KALSHI_KEY_ID", ""),
+		KalshiKeyPath: getEnv("KALSHI_PRIVATE_KEY_PATH", ""),
+
+		KalshiPollInterval: getEnvDuration("KALSHI_POLL_INTERVAL", 2*time.Second),
+
+		HTTPRequestTimeout:    getEnvDuration("HTTP_REQUEST_TIMEOUT", 5*time.Second),
+		HTTPRouteTimeouts:     getEnvList("HTTP_ROUTE_TIMEOUTS", []string{"/arbs/heatmap=30s"}),
+		HTTPReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		HTTPMaxHeaderBytes:    getEnvInt("HTTP_MAX_HEADER_BYTES", 64<<10),
+		HTTPMaxBodyBytes:      int64(getEnvInt("HTTP_MAX_BODY_BYTES", 1<<20)),
+
+		HTTPAdminAddr:   getEnv("HTTP_ADMIN_ADDR", ""),
+		HTTPMetricsAddr: getEnv("HTTP_METRICS_ADDR", ""),
+
+		MatchAbbreviations:        getEnvList("MATCH_ABBREVIATIONS", nil),
+		MatchAbbreviationsDisable: getEnvList("MATCH_ABBREVIATIONS_DISABLE", nil),
+		MatchCategoryStopWords:    getEnvList("MATCH_CATEGORY_STOP_WORDS", nil),
+		MatchCategoryAliases:      getEnvList("MATCH_CATEGORY_ALIASES", nil),
+
+		NearMissMinSim: getEnvFloat("NEAR_MISS_MIN_SIM", 0.45),
+
+		MatchMaxPerPM:     getEnvInt("MATCH_MAX_PER_PM", 1),
+		MatchMaxPerKalshi: getEnvInt("MATCH_MAX_PER_KALSHI", 1),
+
+		PairAnnotationsFile: getEnv("PAIR_ANNOTATIONS_FILE", ""),
+
+		BootstrapTopK:  getEnvInt("BOOTSTRAP_TOP_K", 0),
+		BootstrapBatch: getEnvInt("BOOTSTRAP_BATCH", 200),
+
+		SecretsProvider: getEnv("SECRETS_PROVIDER", "file"),
+		VaultAddr:       getEnv("VAULT_ADDR", ""),
+		VaultToken:      getEnv("VAULT_TOKEN", ""),
+		AWSRegion:       getEnv("AWS_REGION", ""),
+		AgeIdentityFile: getEnv("AGE_IDENTITY_FILE", ""),
+
+		PMRESTURL:     getEnv("PM_REST_URL", "https://clob.polymarket.com"),
+		PMWSURL:       getEnv("PM_WS_URL", "wss://ws-subscriptions-clob.polymarket.com/ws/"),
+		PMGammaURL:    getEnv("PM_GAMMA_URL", "https://gamma-api.polymarket.com"),
+		KalshiRESTURL: getEnv("KALSHI_REST_URL", "https://api.elections.kalshi.com/trade-api/v2"),
+		KalshiWSURL:   getEnv("KALSHI_WS_URL", "wss://api.elections.kalshi.com/trade-api/ws/v2"),
+
+		PMWSCompression:          getEnvBool("PM_WS_COMPRESSION", false),
+		PMWSNoDelay:              getEnvBool("PM_WS_NO_DELAY", true),
+		PMWSLocalAddr:            getEnv("PM_WS_LOCAL_ADDR", ""),
+		PMWSInterface:            getEnv("PM_WS_INTERFACE", ""),
+		PMWSHandshakeTimeout:     getEnvDuration("PM_WS_HANDSHAKE_TIMEOUT", 10*time.Second),
+		KalshiWSCompression:      getEnvBool("KALSHI_WS_COMPRESSION", false),
+		KalshiWSNoDelay:          getEnvBool("KALSHI_WS_NO_DELAY", true),
+		KalshiWSLocalAddr:        getEnv("KALSHI_WS_LOCAL_ADDR", ""),
+		KalshiWSInterface:        getEnv("KALSHI_WS_INTERFACE", ""),
+		KalshiWSHandshakeTimeout: getEnvDuration("KALSHI_WS_HANDSHAKE_TIMEOUT", 10*time.Second),
+		WSFlapMaxReconnects:      getEnvInt("WS_FLAP_MAX_RECONNECTS", 5),
+		WSFlapWindow:             getEnvDuration("WS_FLAP_WINDOW", 10*time.Minute),
+
+		Region:                  getEnv("REGION", ""),
+		GeoCheckURL:             getEnv("GEO_CHECK_URL", "https://polymarket.com/api/geoblock"),
+		PMRestrictedRegions:     getEnvList("PM_RESTRICTED_REGIONS", []string{"US", "FR", "BE", "PL", "SG", "TH", "TW"}),
+		KalshiRestrictedRegions: getEnvList("KALSHI_RESTRICTED_REGIONS", nil),
+		ExecutionEnabled:        getEnvBool("EXECUTION_ENABLED", false),
+
+		KillSwitch:                 getEnvBool("KILL_SWITCH", false),
+		KillSwitchMaxLoss:          getEnvFloat("KILL_SWITCH_MAX_LOSS", 0),
+		KillSwitchMaxFailedUnwinds: getEnvInt("KILL_SWITCH_MAX_FAILED_UNWINDS", 0),
+		KillSwitchWindow:           getEnvDuration("KILL_SWITCH_WINDOW", time.Hour),
+		AdminToken:                 getEnv("ADMIN_TOKEN", ""),
+
+		WarmStartURL:    getEnv("WARM_START_URL", ""),
+		WarmStartMaxAge: getEnvDuration("WARM_START_MAX_AGE", 5*time.Minute),
+
+		AccountStreams:  getEnvBool("ACCOUNT_STREAMS", false),
+		PMUserWSURL:     getEnv("PM_USER_WS_URL", "wss://ws-subscriptions-clob.polymarket.com/ws/user"),
+		PMAPIKey:        getEnv("PM_API_KEY", ""),
+		PMAPISecret:     getEnv("PM_API_SECRET", ""),
+		PMAPIPassphrase: getEnv("PM_API_PASSPHRASE", ""),
+
+		PMAPICredentialsRef: getEnv("PM_API_CREDENTIALS_REF", ""),
+		PMWalletKeyPath:     getEnv("PM_WALLET_KEY_PATH", ""),
+		PMAPINonce:          getEnvInt("PM_API_NONCE", 0),
+
+		KalshiMaintenanceWindows: getEnv("KALSHI_MAINTENANCE_WINDOWS", ""),
+		KalshiMaintenanceTZ:      getEnv("KALSHI_MAINTENANCE_TZ", "America/New_York"),
+
+		KalshiTradingHours:   getEnv("KALSHI_TRADING_HOURS", ""),
+		KalshiHolidays:       getEnvList("KALSHI_HOLIDAYS", nil),
+		KalshiTradingHoursTZ: getEnv("KALSHI_TRADING_HOURS_TZ", "America/New_York"),
+
+		KalshiBackfillMinGap: getEnvDuration("KALSHI_BACKFILL_MIN_GAP", 10*time.Second),
+
+		FreezeScanInterval: getEnvDuration("FREEZE_SCAN_INTERVAL", 5*time.Second),
+		FreezeFactor:       getEnvFloat("FREEZE_FACTOR", 10),
+		FreezeMinSamples:   getEnvInt("FREEZE_MIN_SAMPLES", 20),
+		FreezeMinQuiet:     getEnvDuration("FREEZE_MIN_QUIET", 30*time.Second),
+
+		FeedQualityInterval:             getEnvDuration("FEED_QUALITY_INTERVAL", 10*time.Second),
+		FeedQualityWindow:               getEnvDuration("FEED_QUALITY_WINDOW", 5*time.Minute),
+		FeedQualityMaxParseFailurePct:   getEnvFloat("FEED_QUALITY_MAX_PARSE_FAILURE_PCT", 5),
+		FeedQualityMaxCrossedPct:        getEnvFloat("FEED_QUALITY_MAX_CROSSED_PCT", 5),
+		FeedQualityMaxStaleness:         getEnvDuration("FEED_QUALITY_MAX_STALENESS", 5*time.Minute),
+		FeedQualityMaxReconnectsPerHour: getEnvFloat("FEED_QUALITY_MAX_RECONNECTS_PER_HOUR", 12),
+		FeedQualityDegradedBelow:        getEnvFloat("FEED_QUALITY_DEGRADED_BELOW", 0.5),
+
+		MarketStatusInterval: getEnvDuration("MARKET_STATUS_INTERVAL", 30*time.Second),
+
+		PMRESTBudget:             getEnvInt("PM_REST_BUDGET", 0),
+		KalshiRESTBudget:         getEnvInt("KALSHI_REST_BUDGET", 0),
+		PMSubscriptionBudget:     getEnvInt("PM_SUBSCRIPTION_BUDGET", 0),
+		KalshiSubscriptionBudget: getEnvInt("KALSHI_SUBSCRIPTION_BUDGET", 0),
+		APIBudgetWindow:          getEnvDuration("API_BUDGET_WINDOW", time.Hour),
+		APIBudgetThrottleAt:      getEnvFloat("API_BUDGET_THROTTLE_AT", 0.8),
+
+		DBPath: getEnv("DB_PATH", ""),
+
+		Filters:                       getEnvList("FILTERS", nil),
+		FilterMinSize:                 getEnvFloat("FILTER_MIN_SIZE", 10),
+		FilterMinLiquidity:            getEnvFloat("FILTER_MIN_LIQUIDITY", 500),
+		FilterMaxQuoteAge:             getEnvDuration("FILTER_MAX_QUOTE_AGE", 30*time.Second),
+		FilterCategories:              getEnvList("FILTER_CATEGORIES", nil),
+		FilterMinResolutionConfidence: getEnvFloat("FILTER_MIN_RESOLUTION_CONFIDENCE", 0.75),
+		FilterMinConfidence:           getEnvFloat("FILTER_MIN_CONFIDENCE", 0.5),
+		FilterMinAnnualizedReturn:     getEnvFloat("FILTER_MIN_ANNUALIZED_RETURN", 0),
+		FilterRule:                    getEnv("FILTER_RULE", ""),
+
+		Strategies:             getEnvList("STRATEGIES", []string{"arbitrage"}),
+		DivergenceMin:          getEnvFloat("DIVERGENCE_MIN", 0.05),
+		SpreadCaptureMinRORPct: getEnvFloat("SPREAD_CAPTURE_MIN_ROR_PCT", 3.0),
+
+		KalshiFeeRate: getEnvFloat("KALSHI_FEE_RATE", getEnvFloat("SHADOW_KALSHI_FEE_RATE", 0.07)),
+		PMFeeRate:     getEnvFloat("PM_FEE_RATE", getEnvFloat("SHADOW_PM_FEE_RATE", 0)),
+		PMRelayerFee:  getEnvFloat("PM_RELAYER_FEE", 0),
+
+		ShadowTrading:     getEnvBool("SHADOW_TRADING", false),
+		ShadowSize:        getEnvFloat("SHADOW_SIZE", 10),
+		ExecutionStrategy: getEnv("EXECUTION_STRATEGY", "arbitrage"),
+
+		CalibrationInterval:   getEnvDuration("CALIBRATION_INTERVAL", 24*time.Hour),
+		CalibrationWindow:     getEnvDuration("CALIBRATION_WINDOW", 30*24*time.Hour),
+		CalibrationMinSamples: getEnvInt("CALIBRATION_MIN_SAMPLES", 20),
+		CalibrationApply:      getEnvBool("CALIBRATION_APPLY", false),
+
+		SmarketsEnabled:      getEnvBool("SMARKETS_ENABLED", false),
+		SmarketsRESTURL:      getEnv("SMARKETS_REST_URL", "https://api.smarkets.com/v3"),
+		SmarketsWSURL:        getEnv("SMARKETS_WS_URL", "wss://api.smarkets.com/v3/streaming/"),
+		SmarketsSessionToken: getEnv("SMARKETS_SESSION_TOKEN", ""),
+		SmarketsTypeDomains:  getEnvList("SMARKETS_TYPE_DOMAINS", []string{"politics", "current_affairs"}),
+
+		EngineStallTimeout: getEnvDuration("ENGINE_STALL_TIMEOUT", 15*time.Second),
+		ComputeInterval:    getEnvDuration("COMPUTE_INTERVAL", time.Second),
+		ComputeMinInterval: getEnvDuration("COMPUTE_MIN_INTERVAL", 100*time.Millisecond),
+		ComputeHotUpdates:  getEnvInt("COMPUTE_HOT_UPDATES", 5),
+
+		DiagDumpDir: getEnv("DIAG_DUMP_DIR", ""),
+
+		ConfidenceHalfLife: getEnvDuration("CONFIDENCE_HALF_LIFE", 15*time.Second),
+
+		ExecutionWindow: getEnvDuration("EXECUTION_WINDOW", time.Minute),
+
+		UnhedgedMinEdge: getEnvFloat("UNHEDGED_MIN_EDGE", 0.2),
+
+		MaxOpportunities:     getEnvInt("OPP_MAX", 1000),
+		OpportunityRetention: getEnvDuration("OPP_RETENTION_AGE", 10*time.Minute),
+
+		NotifyWebhookURL:      getEnv("NOTIFY_WEBHOOK_URL", ""),
+		NotifySlackWebhookURL: getEnv("NOTIFY_SLACK_WEBHOOK_URL", ""),
+		NotifyTelegramToken:   getEnv("NOTIFY_TELEGRAM_BOT_TOKEN", ""),
+		NotifyTelegramChatID:  getEnv("NOTIFY_TELEGRAM_CHAT_ID", ""),
+		NotifyWorkers:         getEnvInt("NOTIFY_WORKERS", 4),
+		NotifyQueueSize:       getEnvInt("NOTIFY_QUEUE_SIZE", 256),
+		NotifyTimeout:         getEnvDuration("NOTIFY_TIMEOUT", 10*time.Second),
+		NotifyDeadLetterPath:  getEnv("NOTIFY_DEAD_LETTER_PATH", ""),
+		NotifySuppressAcked:   getEnvBool("NOTIFY_SUPPRESS_ACKED", false),
+		NotifyAlertCooldown:   getEnvDuration("NOTIFY_ALERT_COOLDOWN", 15*time.Minute),
+
+		NotifySenders: getEnvList("NOTIFY_SENDERS", nil),
+		Publishers:    getEnvList("PUBLISHERS", nil),
+		Plugins:       getEnvList("PLUGINS", nil),
+
+		OpsNotifySenders: getEnvList("OPS_NOTIFY_SENDERS", nil),
+
+		NewsMovePct: getEnvFloat("NEWS_MOVE_PCT", 0),
+		NewsWindow:  getEnvDuration("NEWS_WINDOW", time.Minute),
+
+		EdgeJumpAlertPP: getEnvFloat("EDGE_JUMP_ALERT_PP", 10),
+
+		SilenceAlarmAfter: getEnvDuration("SILENCE_ALARM_AFTER", time.Hour),
+		SilenceMarginPP:   getEnvFloat("SILENCE_MARGIN_PP", 1),
+		SilenceMinPairs:   getEnvInt("SILENCE_MIN_PAIRS", 10),
+
+		SpreadSampleInterval: getEnvDuration("SPREAD_SAMPLE_INTERVAL", time.Minute),
+		SpreadRetention:      getEnvDuration("SPREAD_RETENTION", 7*24*time.Hour),
+
+		ArchiveBucket:       getEnv("ARCHIVE_BUCKET", ""),
+		ArchiveEndpoint:     getEnv("ARCHIVE_ENDPOINT", ""),
+		ArchiveRegion:       getEnv("ARCHIVE_REGION", ""),
+		ArchivePrefix:       getEnv("ARCHIVE_PREFIX", "arb-ws"),
+		ArchiveAge:          getEnvDuration("ARCHIVE_AGE", 72*time.Hour),
+		ArchiveInterval:     getEnvDuration("ARCHIVE_INTERVAL", time.Hour),
+		ArchiveStorageClass: getEnv("ARCHIVE_STORAGE_CLASS", ""),
+
+		ArchiveTransitionDays:  getEnvInt("ARCHIVE_TRANSITION_DAYS", 0),
+		ArchiveTransitionClass: getEnv("ARCHIVE_TRANSITION_CLASS", "GLACIER_IR"),
+		ArchiveExpireDays:      getEnvInt("ARCHIVE_EXPIRE_DAYS", 0),
+
+		Chaos: getEnv("CHAOS", ""),
+
+		Demo:         getEnvBool("DEMO", false),
+		DemoPairs:    getEnvInt("DEMO_PAIRS", 3),
+		DemoInterval: getEnvDuration("DEMO_INTERVAL", time.Minute),
+		DemoHold:     getEnvDuration("DEMO_HOLD", 20*time.Second),
+		DemoEdgePct:  getEnvFloat("DEMO_EDGE_PCT", 5),
 	}
 }
 
 func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+	if value := lookup(key, defaultValue); value != "" {
 		return value
 	}
 	return defaultValue
 }
 
 func getEnvFloat(key string, defaultValue float64) float64 {
-	if value := os.Getenv(key); value != "" {
+	if value := lookup(key, fmt.Sprint(defaultValue)); value != "" {
 		if f, err := strconv.ParseFloat(value, 64); err == nil {
 			return f
 		}
@@ -46,10 +649,49 @@ func getEnvFloat(key string, defaultValue float64) float64 {
 }
 
 func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := lookup(key, fmt.Sprint(defaultValue)); value != "" {
 		if i, err := strconv.Atoi(value); err == nil {
 			return i
 		}
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := lookup(key, fmt.Sprint(defaultValue)); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := lookup(key, defaultValue.String()); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList parses a comma-separated list, trimming whitespace and dropping empty entries.
+// Setting the variable to "-" yields an empty list.
+func getEnvList(key string, defaultValue []string) []string {
+	value := lookup(key, strings.Join(defaultValue, ","))
+	if value == "" {
+		return defaultValue
+	}
+	if value == "-" {
+		return nil
+	}
+
+	parts := strings.Split(value, ",")
+	list := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ConfigFileEnv names the config file when --config is not given
+const ConfigFileEnv = "CONFIG_FILE"
+
+// flagAliases are short flag names for frequently set options
+var flagAliases = map[string]string{
+	"edge-min": "EDGE_MIN_ROR_PCT",
+	"addr":     "HTTP_ADDR",
+	"db":       "DB_PATH",
+}
+
+// source layers flags, a config file and a profile over the process
+// environment while a Config is built. The zero value reads the environment
+// alone.
+type source struct {
+	flags   map[string]string
+	file    map[string]string
+	profile map[string]string
+	record  func(key, defaultValue string) // Called for every setting read, if set
+}
+
+var (
+	sourceMu sync.Mutex // Held while building; guards current
+	current  source
+)
+
+// lookup returns a setting's raw value from the highest-precedence layer that
+// sets it, or "" for the default
+func lookup(key, defaultValue string) string {
+	if current.record != nil {
+		current.record(key, defaultValue)
+	}
+	if value, ok := current.flags[key]; ok {
+		return value
+	}
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	if value, ok := current.file[key]; ok {
+		return value
+	}
+	return current.profile[key]
+}
+
+// LoadArgs reads configuration from command-line arguments, environment
+// variables and a config file. Every setting has a flag named after its
+// environment variable in lower case with dashes (HTTP_ADDR is --http-addr),
+// plus a few short aliases such as --edge-min. The config file, named by
+// --config or CONFIG_FILE, holds KEY=value lines with the environment
+// variable names; blank lines and lines starting with # are ignored.
+// --profile or PROFILE selects a bundle of settings for a deployment
+// environment, see Profiles and loadProfile; --profile-dir or PROFILE_DIR
+// (default ./profiles) holds profile files.
+//
+// Precedence, highest first: flags, environment variables, the config file,
+// the profile, built-in defaults. Flags of boolean settings may be given
+// bare, --demo being --demo=true, but not as --demo false. --check sets
+// Config.Check. It returns flag.ErrHelp when
+// -h or --help is given.
+func LoadArgs(name string, args []string, output io.Writer) (*Config, error) {
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+
+	// Discover every setting and its default by building once
+	defaults := make(map[string]string)
+	current = source{record: func(key, def string) { defaults[key] = def }}
+	build()
+	current = source{}
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	configPath := fs.String("config", os.Getenv(ConfigFileEnv), "config file of KEY=value lines (env "+ConfigFileEnv+")")
+	profileName := fs.String("profile", os.Getenv(ProfileEnv), "settings profile, e.g. dev, staging or prod (env "+ProfileEnv+")")
+	profileDir := fs.String("profile-dir", profileDirDefault(), "directory of <profile>.env files (env "+ProfileDirEnv+")")
+	check := fs.Bool("check", false, "validate configuration and probe exchanges, database and notifiers, then exit")
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	flagKeys := make(map[string]string, len(keys)+len(flagAliases))
+	for _, key := range keys {
+		name := FlagName(key)
+		flagKeys[name] = key
+		usage := "env " + key
+		if def := defaults[key]; def != "" {
+			usage += fmt.Sprintf(" (default %q)", def)
+		}
+		if def := defaults[key]; def == "true" || def == "false" {
+			fs.Var(new(switchValue), name, usage)
+			continue
+		}
+		fs.String(name, "", usage)
+	}
+	for alias, key := range flagAliases {
+		flagKeys[alias] = key
+		fs.String(alias, "", "alias for --"+FlagName(key))
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(output, "Usage of %s:\n\nPrecedence: flags > environment variables > config file > profile > defaults.\n\n", name)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if key, ok := flagKeys[f.Name]; ok {
+			flags[key] = f.Value.String()
+		}
+	})
+
+	var file map[string]string
+	if *configPath != "" {
+		var err error
+		if file, err = readConfigFile(*configPath, defaults); err != nil {
+			return nil, err
+		}
+	}
+
+	var profile map[string]string
+	var info ProfileInfo
+	if *profileName != "" {
+		var err error
+		if profile, info.File, err = loadProfile(*profileName, *profileDir, defaults); err != nil {
+			return nil, err
+		}
+		info.Name = *profileName
+	}
+
+	current = source{flags: flags, file: file, profile: profile}
+	defer func() { current = source{} }()
+	cfg := build()
+	cfg.Check = *check
+	cfg.Profile = info
+	return cfg, nil
+}
+
+// FlagName returns the command-line flag for an environment variable
+func FlagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// switchValue is the flag of a boolean setting, which may be given bare
+type switchValue struct{ value string }
+
+func (v *switchValue) String() string { return v.value }
+
+func (v *switchValue) Set(value string) error {
+	v.value = value
+	return nil
+}
+
+func (v *switchValue) IsBoolFlag() bool { return true }
+
+// readConfigFile parses a file of KEY=value lines. Values may be quoted;
+// unknown keys are rejected so typos don't go unnoticed.
+func readConfigFile(path string, known map[string]string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: want KEY=value", path, n)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if _, ok := known[key]; !ok {
+			return nil, fmt.Errorf("%s:%d: unknown setting %s", path, n, key)
+		}
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	return values, nil
+}
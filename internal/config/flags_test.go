@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadArgsPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arb.env")
+	content := "# local run\nEDGE_MIN_ROR_PCT=7\nTITLE_SIM=\"0.7\"\nexport PM_CHUNK=100\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDGE_MIN_ROR_PCT", "")
+	t.Setenv("TITLE_SIM", "0.8")
+	t.Setenv("HTTP_ADDR", ":7000")
+
+	cfg, err := LoadArgs("test", []string{"--config", path, "--edge-min", "5", "--http-addr=:9000"}, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if cfg.EdgeMinRORPct != 5 {
+		t.Errorf("edge min = %v, want the flag's 5", cfg.EdgeMinRORPct)
+	}
+	if cfg.HTTPAddr != ":9000" {
+		t.Errorf("http addr = %q, want the flag's :9000", cfg.HTTPAddr)
+	}
+	if cfg.TitleSim != 0.8 {
+		t.Errorf("title sim = %v, want the environment's 0.8", cfg.TitleSim)
+	}
+	if cfg.PMChunk != 100 {
+		t.Errorf("pm chunk = %v, want the file's 100", cfg.PMChunk)
+	}
+	if cfg.TimeWindowH != 168 {
+		t.Errorf("time window = %v, want the default 168", cfg.TimeWindowH)
+	}
+
+	// Layers don't leak into later loads
+	if Load().PMChunk != 400 {
+		t.Error("config file still applied after LoadArgs")
+	}
+}
+
+func TestLoadArgsCheck(t *testing.T) {
+	cfg, err := LoadArgs("test", []string{"--check"}, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if !cfg.Check {
+		t.Error("--check not set")
+	}
+
+	cfg, err = LoadArgs("test", nil, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if cfg.Check {
+		t.Error("check mode set without --check")
+	}
+}
+
+func TestLoadArgsBoolFlags(t *testing.T) {
+	t.Setenv("DEMO", "")
+	cfg, err := LoadArgs("test", []string{"--demo", "--edge-min", "5"}, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if !cfg.Demo {
+		t.Error("bare --demo not set")
+	}
+	if cfg.EdgeMinRORPct != 5 {
+		t.Errorf("edge min = %v, want 5 after a bare flag", cfg.EdgeMinRORPct)
+	}
+
+	cfg, err = LoadArgs("test", []string{"--demo=false"}, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if cfg.Demo {
+		t.Error("--demo=false set demo mode")
+	}
+}
+
+func TestLoadArgsErrors(t *testing.T) {
+	if _, err := LoadArgs("test", []string{"-h"}, io.Discard); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("-h: got %v, want flag.ErrHelp", err)
+	}
+	if _, err := LoadArgs("test", []string{"--no-such-setting", "1"}, io.Discard); err == nil {
+		t.Error("unknown flag accepted")
+	}
+
+	path := filepath.Join(t.TempDir(), "bad.env")
+	if err := os.WriteFile(path, []byte("EDGE_MIN_ROR_PCTT=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadArgs("test", []string{"--config", path}, io.Discard); err == nil {
+		t.Error("misspelled config file setting accepted")
+	}
+}
+
+func TestLoadArgsProfile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "staging.env"), []byte("EDGE_MIN_ROR_PCT=1.5\nNOTIFY_SENDERS=webhook:url=http://hooks.staging\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "qa.env"), []byte("PM_CHUNK=50\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "arb.env")
+	if err := os.WriteFile(path, []byte("EDGE_MIN_ROR_PCT=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PROFILE", "")
+	t.Setenv("PROFILE_DIR", dir)
+	t.Setenv("EDGE_MIN_ROR_PCT", "")
+
+	// The profile file overlays the built-in profile of the same name
+	cfg, err := LoadArgs("test", []string{"--profile", "staging"}, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if cfg.Profile.Name != "staging" || cfg.Profile.File != filepath.Join(dir, "staging.env") {
+		t.Errorf("unexpected profile %+v", cfg.Profile)
+	}
+	if cfg.KalshiRESTURL != kalshiDemoRESTURL || !cfg.ShadowTrading {
+		t.Errorf("built-in staging settings not applied: %s, shadow %v", cfg.KalshiRESTURL, cfg.ShadowTrading)
+	}
+	if cfg.EdgeMinRORPct != 1.5 || len(cfg.NotifySenders) != 1 {
+		t.Errorf("profile file not applied: edge %v, senders %v", cfg.EdgeMinRORPct, cfg.NotifySenders)
+	}
+
+	// The config file overrides the profile
+	cfg, err = LoadArgs("test", []string{"--profile=staging", "--config", path}, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if cfg.EdgeMinRORPct != 2 {
+		t.Errorf("edge min = %v, want the config file's 2", cfg.EdgeMinRORPct)
+	}
+
+	// Profiles may exist as files alone
+	t.Setenv("PROFILE", "qa")
+	cfg, err = LoadArgs("test", nil, io.Discard)
+	if err != nil {
+		t.Fatalf("LoadArgs: %v", err)
+	}
+	if cfg.Profile.Name != "qa" || cfg.PMChunk != 50 || cfg.KalshiRESTURL == kalshiDemoRESTURL {
+		t.Errorf("unexpected qa profile %+v, chunk %d, %s", cfg.Profile, cfg.PMChunk, cfg.KalshiRESTURL)
+	}
+
+	for _, name := range []string{"nope", "../staging"} {
+		if _, err := LoadArgs("test", []string{"--profile", name}, io.Discard); err == nil {
+			t.Errorf("profile %q accepted", name)
+		}
+	}
+	if Load().KalshiRESTURL == kalshiDemoRESTURL {
+		t.Error("profile still applied after LoadArgs")
+	}
+}
+
+func TestBuiltinProfilesKnown(t *testing.T) {
+	known := make(map[string]string)
+	current = source{record: func(key, def string) { known[key] = def }}
+	build()
+	current = source{}
+	for name, settings := range Profiles {
+		for key := range settings {
+			if _, ok := known[key]; !ok {
+				t.Errorf("profile %s sets unknown setting %s", name, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Profile selection when --profile and --profile-dir are not given
+const (
+	ProfileEnv    = "PROFILE"
+	ProfileDirEnv = "PROFILE_DIR"
+)
+
+// Kalshi's demo environment, trading play money against the production API
+const (
+	kalshiDemoRESTURL = "https://demo-api.kalshi.co/trade-api/v2"
+	kalshiDemoWSURL   = "wss://demo-api.kalshi.co/trade-api/ws/v2"
+)
+
+// Profiles are the built-in bundles of settings per deployment environment,
+// keyed by environment variable. The defaults are production's, so prod
+// only pins what must never differ from them. Polymarket has no demo
+// environment; every profile reads its production feeds.
+var Profiles = map[string]map[string]string{
+	"dev": {
+		"KALSHI_REST_URL":     kalshiDemoRESTURL,
+		"KALSHI_WS_URL":       kalshiDemoWSURL,
+		"EDGE_MIN_ROR_PCT":    "0.5",
+		"SHADOW_TRADING":      "true",
+		"SILENCE_ALARM_AFTER": "0",
+	},
+	"staging": {
+		"KALSHI_REST_URL": kalshiDemoRESTURL,
+		"KALSHI_WS_URL":   kalshiDemoWSURL,
+		"SHADOW_TRADING":  "true",
+	},
+	"prod": {
+		"KALSHI_REST_URL": "https://api.elections.kalshi.com/trade-api/v2",
+		"KALSHI_WS_URL":   "wss://api.elections.kalshi.com/trade-api/ws/v2",
+	},
+}
+
+// ProfileInfo describes the active profile, as reported on /status
+type ProfileInfo struct {
+	Name string `json:"name"`           // Empty when none is active
+	File string `json:"file,omitempty"` // Profile file applied, if any
+}
+
+// loadProfile returns a profile's settings: the built-in profile of that
+// name overlaid with <dir>/<name>.env, a file of KEY=value lines like the
+// config file, where deployments keep their endpoints, thresholds and
+// notifier targets. A name with neither is an error.
+func loadProfile(name, dir string, known map[string]string) (map[string]string, string, error) {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, "", fmt.Errorf("invalid profile name %q", name)
+	}
+	builtin, ok := Profiles[name]
+	values := maps.Clone(builtin)
+	if values == nil {
+		values = make(map[string]string)
+	}
+
+	var file string
+	if dir != "" {
+		path := filepath.Join(dir, name+".env")
+		overlay, err := readConfigFile(path, known)
+		switch {
+		case err == nil:
+			maps.Copy(values, overlay)
+			file = path
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, "", fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+	if !ok && file == "" {
+		return nil, "", fmt.Errorf("unknown profile %q: not built in (%s) and no %s.env in %q",
+			name, strings.Join(slices.Sorted(maps.Keys(Profiles)), ", "), name, dir)
+	}
+	return values, file, nil
+}
+
+// profileDirDefault is the profile directory when none is configured
+func profileDirDefault() string {
+	if dir := os.Getenv(ProfileDirEnv); dir != "" {
+		return dir
+	}
+	return "profiles"
+}
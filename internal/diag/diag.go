@@ -0,0 +1,157 @@
+// Package diag writes diagnostic dumps: the state of registered subsystems
+// plus every goroutine's stack, to a timestamped file. Dumps are triggered
+// by a signal (SIGUSR1 outside Windows) rather than over HTTP, so they work
+// when the HTTP server itself is wedged.
+package diag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultSectionTimeout bounds how long a dump waits for one section. A
+// section blocked on a stuck lock is reported as timed out, which is itself
+// the finding.
+const DefaultSectionTimeout = 2 * time.Second
+
+// Section returns one part of a dump, encoded as JSON
+type Section func() interface{}
+
+// Dumper collects registered sections into dump files
+type Dumper struct {
+	dir     string
+	timeout time.Duration
+
+	mu       sync.Mutex
+	sections map[string]Section
+}
+
+// New creates a dumper writing to dir, or the system temporary directory
+// when dir is empty
+func New(dir string) *Dumper {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return &Dumper{dir: dir, timeout: DefaultSectionTimeout, sections: make(map[string]Section)}
+}
+
+// Register adds a named section. Registering the same name twice replaces
+// the previous section.
+func (d *Dumper) Register(name string, fn Section) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sections[name] = fn
+}
+
+// Write dumps the sections and goroutine stacks to a new file named after
+// now, returning its path
+func (d *Dumper) Write(now time.Time) (string, error) {
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return "", fmt.Errorf("create dump directory: %w", err)
+	}
+	path := filepath.Join(d.dir, "arb-ws-dump-"+now.UTC().Format("20060102T150405.000Z")+".txt")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("create dump file: %w", err)
+	}
+	if err := d.writeTo(f, now); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("close dump file: %w", err)
+	}
+	return path, nil
+}
+
+// writeTo writes the dump: a JSON document of the sections, then the
+// goroutine stacks
+func (d *Dumper) writeTo(w io.Writer, now time.Time) error {
+	doc := map[string]interface{}{
+		"time":       now.UTC(),
+		"goroutines": runtime.NumGoroutine(),
+		"sections":   d.collect(),
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode dump: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "%s\n\n== goroutines ==\n\n", data); err != nil {
+		return fmt.Errorf("write dump: %w", err)
+	}
+	if err := pprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+		return fmt.Errorf("write goroutine stacks: %w", err)
+	}
+	return nil
+}
+
+// collect runs every section, each bounded by the section timeout
+func (d *Dumper) collect() map[string]interface{} {
+	d.mu.Lock()
+	names := make([]string, 0, len(d.sections))
+	for name := range d.sections {
+		names = append(names, name)
+	}
+	sections := make(map[string]Section, len(d.sections))
+	for name, fn := range d.sections {
+		sections[name] = fn
+	}
+	d.mu.Unlock()
+	sort.Strings(names)
+
+	out := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		out[name] = d.run(sections[name])
+	}
+	return out
+}
+
+// run evaluates a section, reporting a panic or timeout in its place
+func (d *Dumper) run(fn Section) interface{} {
+	result := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- map[string]string{"error": fmt.Sprint("panic: ", r)}
+			}
+		}()
+		result <- fn()
+	}()
+
+	select {
+	case v := <-result:
+		return v
+	case <-time.After(d.timeout):
+		return map[string]string{"error": "timed out after " + d.timeout.String()}
+	}
+}
+
+// Run writes a dump each time the dump signal arrives, until ctx is done
+func (d *Dumper) Run(ctx context.Context, logger *slog.Logger) {
+	signals, stop := notifyDump()
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			path, err := d.Write(time.Now())
+			if err != nil {
+				logger.Error("diagnostic dump failed", "error", err)
+				continue
+			}
+			logger.Info("diagnostic dump written", "path", path)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package diag
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteDump(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dumps")
+	d := New(dir)
+	d.timeout = 50 * time.Millisecond
+
+	stuck := make(chan struct{})
+	defer close(stuck)
+	d.Register("pairs", func() interface{} { return map[string]int{"monitored": 3} })
+	d.Register("engine", func() interface{} { <-stuck; return nil })
+	d.Register("broken", func() interface{} { panic("boom") })
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	path, err := d.Write(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "arb-ws-dump-20260301T120000.000Z.txt" {
+		t.Errorf("unexpected dump file %s", path)
+	}
+	if _, err := d.Write(now); err == nil {
+		t.Error("an existing dump must not be overwritten")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, stacks, ok := strings.Cut(string(data), "\n\n== goroutines ==\n\n")
+	if !ok || !strings.Contains(stacks, "goroutine ") {
+		t.Fatalf("goroutine stacks missing from dump:\n%s", data)
+	}
+
+	var parsed struct {
+		Goroutines int                               `json:"goroutines"`
+		Sections   map[string]map[string]interface{} `json:"sections"`
+	}
+	if err := json.Unmarshal([]byte(doc), &parsed); err != nil {
+		t.Fatalf("sections are not JSON: %v", err)
+	}
+	if parsed.Goroutines == 0 || parsed.Sections["pairs"]["monitored"] != float64(3) {
+		t.Errorf("unexpected dump %+v", parsed)
+	}
+	if e, _ := parsed.Sections["engine"]["error"].(string); !strings.HasPrefix(e, "timed out") {
+		t.Errorf("stuck section not reported as timed out: %v", parsed.Sections["engine"])
+	}
+	if e, _ := parsed.Sections["broken"]["error"].(string); e != "panic: boom" {
+		t.Errorf("panicking section not reported: %v", parsed.Sections["broken"])
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package diag
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyDump subscribes to SIGUSR1
+func notifyDump() (<-chan os.Signal, func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+	return ch, func() { signal.Stop(ch) }
+}
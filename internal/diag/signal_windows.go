@@ -0,0 +1,10 @@
+//go:build windows
+
+package diag
+
+import "os"
+
+// notifyDump never fires: Windows has no SIGUSR1
+func notifyDump() (<-chan os.Signal, func()) {
+	return nil, func() {}
+}
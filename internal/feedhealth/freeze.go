@@ -0,0 +1,222 @@
+// Package feedhealth detects per-instrument feed failures. Each instrument's
+// typical update interval is learned from its own recent history, and an
+// instrument is flagged as frozen when it has been quiet for much longer than
+// that baseline, which catches a single market's feed dying while the
+// connection as a whole stays healthy. Quality scores each venue feed as a
+// whole on its rolling parse failure, crossed quote, staleness and reconnect
+// rates, weighting the feeds for opportunity generation.
+package feedhealth
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+// Defaults for zero Options fields
+const (
+	DefaultFactor     = 10.0
+	DefaultMinSamples = 20
+	DefaultMinQuiet   = 30 * time.Second
+	DefaultAlpha      = 0.1
+)
+
+// Options tunes freeze detection; zero values fall back to the defaults
+type Options struct {
+	// Factor is how many baseline intervals an instrument may stay quiet
+	Factor float64
+	// MinSamples is the number of intervals observed before an instrument's
+	// baseline is trusted
+	MinSamples int
+	// MinQuiet is the shortest silence ever flagged, so naturally busy
+	// instruments aren't flagged over a few seconds' lull
+	MinQuiet time.Duration
+	// Alpha is the EWMA weight of the newest interval
+	Alpha float64
+}
+
+// Freeze describes an instrument whose feed has gone anomalously quiet
+type Freeze struct {
+	Instrument instrument.ID `json:"instrument"`
+	LastUpdate time.Time     `json:"last_update"`
+	Quiet      string        `json:"quiet"`    // Time since the last update
+	Baseline   string        `json:"baseline"` // Learned typical update interval
+	Since      time.Time     `json:"since"`    // When the freeze was first flagged
+}
+
+// feed is the learned update pattern of one instrument
+type feed struct {
+	last     time.Time
+	mean     time.Duration // EWMA of update intervals
+	samples  int
+	frozenAt time.Time // Zero while not frozen
+}
+
+// Detector learns per-instrument update rates and flags frozen feeds
+type Detector struct {
+	mu     sync.Mutex
+	opts   Options
+	feeds  map[instrument.ID]*feed
+	paused func(venue string) bool
+	logger *slog.Logger
+}
+
+// NewDetector creates a detector with no history
+func NewDetector(opts Options, logger *slog.Logger) *Detector {
+	if opts.Factor <= 1 {
+		opts.Factor = DefaultFactor
+	}
+	if opts.MinSamples <= 0 {
+		opts.MinSamples = DefaultMinSamples
+	}
+	if opts.MinQuiet <= 0 {
+		opts.MinQuiet = DefaultMinQuiet
+	}
+	if opts.Alpha <= 0 || opts.Alpha > 1 {
+		opts.Alpha = DefaultAlpha
+	}
+	return &Detector{opts: opts, feeds: make(map[instrument.ID]*feed), logger: logger}
+}
+
+// SetPauseCheck installs a predicate reporting venues whose whole connection
+// is down or in maintenance; their instruments aren't flagged individually.
+// Call before Run.
+func (d *Detector) SetPauseCheck(fn func(venue string) bool) {
+	d.paused = fn
+}
+
+// Observe records an update for an instrument
+func (d *Detector) Observe(id instrument.ID, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f, ok := d.feeds[id]
+	if !ok {
+		d.feeds[id] = &feed{last: at}
+		return
+	}
+
+	interval := at.Sub(f.last)
+	if interval < 0 {
+		return
+	}
+	f.last = at
+
+	if !f.frozenAt.IsZero() {
+		d.logger.Info("instrument feed resumed", "instrument", id, "quiet", interval.Round(time.Second).String())
+		metrics.RecordFeedResumed(id.Venue())
+		f.frozenAt = time.Time{}
+	}
+
+	// A gap far beyond the baseline is an outage, not the instrument's rhythm
+	if f.samples >= d.opts.MinSamples && float64(interval) > d.opts.Factor*float64(f.mean) {
+		return
+	}
+
+	if f.samples == 0 {
+		f.mean = interval
+	} else {
+		f.mean += time.Duration(d.opts.Alpha * float64(interval-f.mean))
+	}
+	f.samples++
+}
+
+// threshold returns how long f may stay quiet before it counts as frozen
+func (d *Detector) threshold(f *feed) time.Duration {
+	t := time.Duration(d.opts.Factor * float64(f.mean))
+	if t < d.opts.MinQuiet {
+		t = d.opts.MinQuiet
+	}
+	return t
+}
+
+// Scan flags instruments that have been quiet past their threshold as of now
+// and returns every currently frozen instrument, quietest first
+func (d *Detector) Scan(now time.Time) []Freeze {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	frozen := make([]Freeze, 0)
+	counts := make(map[string]int)
+	for id, f := range d.feeds {
+		venue := id.Venue()
+		if _, ok := counts[venue]; !ok {
+			counts[venue] = 0 // Report zero rather than a stale count
+		}
+		if d.paused != nil && d.paused(venue) {
+			f.frozenAt = time.Time{} // Connection-level health covers this venue
+			continue
+		}
+		if f.samples < d.opts.MinSamples {
+			continue
+		}
+
+		quiet := now.Sub(f.last)
+		if quiet <= d.threshold(f) {
+			continue
+		}
+		if f.frozenAt.IsZero() {
+			f.frozenAt = now
+			d.logger.Warn("instrument feed frozen",
+				"instrument", id, "quiet", quiet.Round(time.Second).String(), "baseline", f.mean.Round(time.Millisecond).String())
+			metrics.RecordFeedFreeze(venue)
+		}
+
+		counts[venue]++
+		frozen = append(frozen, Freeze{
+			Instrument: id,
+			LastUpdate: f.last,
+			Quiet:      quiet.Round(time.Second).String(),
+			Baseline:   f.mean.Round(time.Millisecond).String(),
+			Since:      f.frozenAt,
+		})
+	}
+
+	for venue, n := range counts {
+		metrics.SetFrozenInstruments(venue, n)
+	}
+	sort.Slice(frozen, func(i, j int) bool { return frozen[i].LastUpdate.Before(frozen[j].LastUpdate) })
+	return frozen
+}
+
+// Frozen returns the instruments flagged by the latest Scan, quietest first
+func (d *Detector) Frozen() []Freeze {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([]Freeze, 0)
+	for id, f := range d.feeds {
+		if f.frozenAt.IsZero() {
+			continue
+		}
+		out = append(out, Freeze{
+			Instrument: id,
+			LastUpdate: f.last,
+			Quiet:      time.Since(f.last).Round(time.Second).String(),
+			Baseline:   f.mean.Round(time.Millisecond).String(),
+			Since:      f.frozenAt,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].LastUpdate.Before(out[j].LastUpdate) })
+	return out
+}
+
+// Run scans every interval until ctx is done
+func (d *Detector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.Scan(now)
+		}
+	}
+}
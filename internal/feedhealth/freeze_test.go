@@ -0,0 +1,63 @@
+package feedhealth
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+func testDetector() *Detector {
+	return NewDetector(Options{Factor: 5, MinSamples: 5, MinQuiet: 10 * time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestScanFlagsQuietInstrumentsAgainstOwnBaseline(t *testing.T) {
+	d := testDetector()
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	busy := instrument.PMToken("busy")     // updates every second
+	sleepy := instrument.KalshiYes("SLOW") // updates every 30s
+	fresh := instrument.PMToken("fresh")   // too little history to judge
+
+	for i := 0; i <= 10; i++ {
+		d.Observe(busy, start.Add(time.Duration(i)*time.Second))
+		d.Observe(sleepy, start.Add(time.Duration(i)*30*time.Second))
+	}
+	d.Observe(fresh, start)
+
+	last := start.Add(300 * time.Second)
+	if frozen := d.Scan(last.Add(60 * time.Second)); len(frozen) != 1 || frozen[0].Instrument != busy {
+		t.Fatalf("expected only the busy instrument frozen after 60s quiet, got %+v", frozen)
+	}
+
+	// 60s is two intervals for the sleepy feed, 200s is well past its baseline
+	if frozen := d.Scan(last.Add(200 * time.Second)); len(frozen) != 2 {
+		t.Fatalf("expected both instruments frozen, got %+v", frozen)
+	}
+
+	// An update clears the freeze without skewing the baseline
+	d.Observe(busy, last.Add(200*time.Second))
+	for _, f := range d.Frozen() {
+		if f.Instrument == busy {
+			t.Error("busy instrument should have resumed")
+		}
+	}
+	if mean := d.feeds[busy].mean; mean != time.Second {
+		t.Errorf("outage gap must not enter the baseline, mean %v", mean)
+	}
+}
+
+func TestScanSkipsPausedVenues(t *testing.T) {
+	d := testDetector()
+	d.SetPauseCheck(func(venue string) bool { return venue == instrument.VenueKalshi })
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	id := instrument.KalshiYes("K")
+	for i := 0; i <= 10; i++ {
+		d.Observe(id, start.Add(time.Duration(i)*time.Second))
+	}
+
+	if frozen := d.Scan(start.Add(time.Hour)); len(frozen) != 0 {
+		t.Errorf("paused venue must not be flagged, got %+v", frozen)
+	}
+}
@@ -0,0 +1,230 @@
+package feedhealth
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// Feed quality statuses
+const (
+	QualityHealthy  = "healthy"
+	QualityDegraded = "degraded" // Its opportunities' confidence is scaled down
+	QualityExcluded = "excluded" // Its opportunities are dropped
+)
+
+// Defaults for zero QualityOptions fields
+const (
+	DefaultQualityWindow = 5 * time.Minute
+	DefaultDegradedBelow = 0.5
+	DefaultMinFrames     = 20
+)
+
+// QualityOptions tunes feed quality scoring. A measure reaching its
+// threshold excludes the feed; zero thresholds ignore their measure, other
+// zero values fall back to the defaults.
+type QualityOptions struct {
+	// Window is the span rates are measured over
+	Window time.Duration
+	// MaxParseFailureRate is the fraction of frames failing to decode
+	MaxParseFailureRate float64
+	// MaxCrossedRate is the fraction of frames carrying quotes refused as
+	// crossed or out of range
+	MaxCrossedRate float64
+	// MaxStaleness is the time since the feed's last price update
+	MaxStaleness time.Duration
+	// MaxReconnectsPerHour is the reconnection rate over the window
+	MaxReconnectsPerHour float64
+	// DegradedBelow is the score under which a feed is down-weighted
+	DegradedBelow float64
+	// MinFrames is the number of frames in the window before its rates are
+	// judged, so a handful of frames can't exclude a feed
+	MinFrames int
+}
+
+// FeedQuality is a feed's rolling data quality. Its score is 1 less the
+// largest measure as a fraction of its threshold, so 0 once any measure
+// reaches its threshold.
+type FeedQuality struct {
+	Source            string    `json:"source"` // Metrics source label, e.g. "pm" or "kalshi"
+	ParseFailureRate  float64   `json:"parse_failure_rate"`
+	CrossedRate       float64   `json:"crossed_rate"`
+	Staleness         string    `json:"staleness"` // Since the last price update, "" before the first
+	ReconnectsPerHour float64   `json:"reconnects_per_hour"`
+	Frames            uint64    `json:"frames"` // Received in the window
+	Score             float64   `json:"score"`
+	Weight            float64   `json:"weight"` // Scales opportunity confidence; 0 excludes
+	Status            string    `json:"status"` // QualityHealthy, QualityDegraded or QualityExcluded
+	Since             time.Time `json:"since"`  // When the status last changed
+}
+
+// qualitySample is every feed's cumulative counts at one time
+type qualitySample struct {
+	at     time.Time
+	counts map[string]metrics.FeedCounts
+}
+
+// Quality scores each feed's data quality over a rolling window from the
+// counts the metrics package keeps, and weights the feeds for opportunity
+// generation: healthy feeds fully, degraded ones by their score, excluded
+// ones not at all
+type Quality struct {
+	mu      sync.Mutex
+	opts    QualityOptions
+	samples []qualitySample // Oldest first, spanning the window
+	feeds   map[string]*FeedQuality
+	logger  *slog.Logger
+}
+
+// NewQuality creates a scorer with no history
+func NewQuality(opts QualityOptions, logger *slog.Logger) *Quality {
+	if opts.Window <= 0 {
+		opts.Window = DefaultQualityWindow
+	}
+	if opts.DegradedBelow <= 0 || opts.DegradedBelow > 1 {
+		opts.DegradedBelow = DefaultDegradedBelow
+	}
+	if opts.MinFrames <= 0 {
+		opts.MinFrames = DefaultMinFrames
+	}
+	return &Quality{opts: opts, feeds: make(map[string]*FeedQuality), logger: logger}
+}
+
+// Sample rescores every feed from its cumulative counts as of now
+func (q *Quality) Sample(now time.Time, counts map[string]metrics.FeedCounts) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.samples = append(q.samples, qualitySample{at: now, counts: counts})
+	cutoff := now.Add(-q.opts.Window)
+	drop := 0
+	for drop < len(q.samples)-1 && q.samples[drop+1].at.Before(cutoff) {
+		drop++
+	}
+	q.samples = q.samples[drop:]
+	base := q.samples[0].counts
+
+	for source, c := range counts {
+		b := base[source]
+		frames := c.Frames - b.Frames
+		fq := FeedQuality{Source: source, Frames: frames}
+		if frames > 0 {
+			fq.ParseFailureRate = float64(c.ParseFailures-b.ParseFailures) / float64(frames)
+			fq.CrossedRate = float64(c.Invalid-b.Invalid) / float64(frames)
+		}
+		fq.ReconnectsPerHour = float64(c.Reconnects-b.Reconnects) / q.opts.Window.Hours()
+
+		// The share of its threshold each judged measure has used up
+		var worst float64
+		use := func(value, threshold float64) {
+			if threshold > 0 {
+				worst = math.Max(worst, value/threshold)
+			}
+		}
+		if frames >= uint64(q.opts.MinFrames) {
+			use(fq.ParseFailureRate, q.opts.MaxParseFailureRate)
+			use(fq.CrossedRate, q.opts.MaxCrossedRate)
+		}
+		if !c.LastUpdate.IsZero() {
+			stale := now.Sub(c.LastUpdate)
+			fq.Staleness = stale.Round(time.Second).String()
+			use(stale.Seconds(), q.opts.MaxStaleness.Seconds())
+		}
+		use(fq.ReconnectsPerHour, q.opts.MaxReconnectsPerHour)
+
+		fq.Score = math.Max(0, 1-worst)
+		switch {
+		case fq.Score == 0:
+			fq.Status, fq.Weight = QualityExcluded, 0
+		case fq.Score < q.opts.DegradedBelow:
+			fq.Status, fq.Weight = QualityDegraded, fq.Score
+		default:
+			fq.Status, fq.Weight = QualityHealthy, 1
+		}
+		q.transition(&fq, now)
+		q.feeds[source] = &fq
+
+		metrics.SetFeedQuality(source, "parse_failure_rate", fq.ParseFailureRate)
+		metrics.SetFeedQuality(source, "crossed_rate", fq.CrossedRate)
+		if !c.LastUpdate.IsZero() {
+			metrics.SetFeedQuality(source, "staleness_seconds", now.Sub(c.LastUpdate).Seconds())
+		}
+		metrics.SetFeedQuality(source, "reconnects_per_hour", fq.ReconnectsPerHour)
+		metrics.SetFeedQuality(source, "score", fq.Score)
+		metrics.SetFeedQuality(source, "weight", fq.Weight)
+	}
+}
+
+// transition carries a feed's status start over from its previous score,
+// logging status changes
+func (q *Quality) transition(fq *FeedQuality, now time.Time) {
+	prev, ok := q.feeds[fq.Source]
+	if ok && prev.Status == fq.Status {
+		fq.Since = prev.Since
+		return
+	}
+	fq.Since = now
+	if !ok && fq.Status == QualityHealthy {
+		return
+	}
+
+	attrs := []any{"source", fq.Source, "score", fq.Score, "parse_failure_rate", fq.ParseFailureRate,
+		"crossed_rate", fq.CrossedRate, "staleness", fq.Staleness, "reconnects_per_hour", fq.ReconnectsPerHour}
+	switch fq.Status {
+	case QualityHealthy:
+		q.logger.Info("feed quality recovered", attrs...)
+	case QualityDegraded:
+		q.logger.Warn("feed quality degraded, down-weighting its opportunities", attrs...)
+	case QualityExcluded:
+		q.logger.Warn("feed quality too low, excluding its opportunities", attrs...)
+	}
+}
+
+// Weight returns how much opportunities priced by a feed are trusted, from
+// 1 for a healthy or unscored feed down to 0 for an excluded one. Feeds are
+// named by their metrics source label, which is the instrument venue.
+func (q *Quality) Weight(source string) float64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if fq, ok := q.feeds[source]; ok {
+		return fq.Weight
+	}
+	return 1
+}
+
+// Scores returns every feed's latest quality, by source
+func (q *Quality) Scores() []FeedQuality {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	out := make([]FeedQuality, 0, len(q.feeds))
+	for _, fq := range q.feeds {
+		out = append(out, *fq)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out
+}
+
+// Run samples the metrics package's feed counts every interval until ctx
+// is done
+func (q *Quality) Run(ctx context.Context, interval time.Duration) {
+	q.Sample(time.Now(), metrics.Feeds())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			q.Sample(now, metrics.Feeds())
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package feedhealth
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+func TestQualityScoresFeeds(t *testing.T) {
+	q := NewQuality(QualityOptions{
+		Window:               time.Minute,
+		MaxParseFailureRate:  0.10,
+		MaxCrossedRate:       0.10,
+		MaxStaleness:         time.Minute,
+		MaxReconnectsPerHour: 60,
+		MinFrames:            10,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	q.Sample(start, map[string]metrics.FeedCounts{
+		"pm":     {Frames: 1000, ParseFailures: 50, LastUpdate: start},
+		"kalshi": {Frames: 1000, LastUpdate: start},
+	})
+	if w := q.Weight("pm"); w != 1 {
+		t.Errorf("pm weight = %v before any window, want 1", w)
+	}
+	if w := q.Weight("smarkets"); w != 1 {
+		t.Errorf("unscored feed weight = %v, want 1", w)
+	}
+
+	// Over the next window pm fails to parse 3% of frames, kalshi quotes
+	// crossed books in 8% of them
+	now := start.Add(time.Minute)
+	q.Sample(now, map[string]metrics.FeedCounts{
+		"pm":     {Frames: 1100, ParseFailures: 53, LastUpdate: now},
+		"kalshi": {Frames: 1100, Invalid: 8, LastUpdate: now},
+	})
+	scores := q.Scores()
+	if len(scores) != 2 || scores[0].Source != "kalshi" || scores[1].Source != "pm" {
+		t.Fatalf("scores = %+v, want kalshi and pm", scores)
+	}
+	if pm := scores[1]; pm.Status != QualityHealthy || pm.Weight != 1 || pm.ParseFailureRate != 0.03 || pm.Frames != 100 {
+		t.Errorf("pm = %+v, want healthy at a 3%% parse failure rate", pm)
+	}
+	if k := scores[0]; k.Status != QualityDegraded || k.CrossedRate != 0.08 || k.Weight < 0.19 || k.Weight > 0.21 {
+		t.Errorf("kalshi = %+v, want degraded to about 0.2 at an 8%% crossed rate", k)
+	}
+
+	// A feed gone quiet for its staleness threshold is excluded, and
+	// reconnects count over the window
+	later := now.Add(time.Minute)
+	q.Sample(later, map[string]metrics.FeedCounts{
+		"pm":     {Frames: 1200, ParseFailures: 53, Reconnects: 30, LastUpdate: later},
+		"kalshi": {Frames: 1100, Invalid: 8, LastUpdate: now},
+	})
+	if w := q.Weight("kalshi"); w != 0 {
+		t.Errorf("kalshi weight = %v after a minute's silence, want 0", w)
+	}
+	scores = q.Scores()
+	if pm := scores[1]; pm.ReconnectsPerHour != 1800 || pm.Status != QualityExcluded {
+		t.Errorf("pm = %+v, want excluded at 1800 reconnects per hour", pm)
+	}
+	if k := scores[0]; !k.Since.Equal(later) {
+		t.Errorf("kalshi since = %v, want the exclusion at %v", k.Since, later)
+	}
+}
+
+func TestQualityNeedsFramesToJudgeRates(t *testing.T) {
+	q := NewQuality(QualityOptions{Window: time.Minute, MaxParseFailureRate: 0.10, MinFrames: 10},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	q.Sample(start, map[string]metrics.FeedCounts{"pm": {}})
+	q.Sample(start.Add(time.Minute), map[string]metrics.FeedCounts{"pm": {Frames: 3, ParseFailures: 2}})
+	if w := q.Weight("pm"); w != 1 {
+		t.Errorf("weight = %v from 3 frames, want 1", w)
+	}
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// ackRequest is the body of POST /arbs/{id}/ack
+type ackRequest struct {
+	Status string `json:"status"` // claimed, executed or skipped
+	Reason string `json:"reason"`
+	By     string `json:"by"` // Consumer identifier, defaults to the remote address
+}
+
+// handleArbAck records (POST) or reports (GET) a downstream consumer's
+// acknowledgment of an open opportunity. When ADMIN_TOKEN is set, POST
+// requires it as a bearer token.
+func (s *Server) handleArbAck(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		var current *arb.Acknowledgment
+		if a, ok := s.engine.Acknowledged(id); ok {
+			current = &a
+		}
+		resp := map[string]interface{}{"current": current}
+		if s.store != nil {
+			history, err := s.store.Acks(r.Context(), id)
+			if err != nil {
+				s.logger.Error("failed to load acknowledgments", "opp_id", id, "error", err)
+				writeError(w, r, http.StatusInternalServerError, "failed to load acknowledgments")
+				return
+			}
+			resp["history"] = history
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case http.MethodPost:
+		if s.adminToken != "" && !s.validAdminToken(r) {
+			writeError(w, r, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+
+		var req ackRequest
+		if !decodeJSON(w, r, 4096, &req) {
+			return
+		}
+		if req.By == "" {
+			req.By = r.RemoteAddr
+		}
+
+		a, err := s.engine.Acknowledge(id, req.Status, req.Reason, req.By)
+		switch {
+		case errors.Is(err, arb.ErrOpportunityNotOpen):
+			writeError(w, r, http.StatusNotFound, err.Error())
+			return
+		case errors.Is(err, arb.ErrOpportunityExpired):
+			writeError(w, r, http.StatusGone, err.Error())
+			return
+		case err != nil:
+			writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if s.store != nil {
+			if err := s.store.RecordAck(r.Context(), a); err != nil {
+				// The acknowledgment is live in memory; only the audit row is lost
+				s.logger.Error("failed to persist acknowledgment", "opp_id", id, "error", err)
+			}
+		}
+		s.logger.Info("opportunity acknowledged", "opp_id", id, "status", a.Status, "by", a.By, "reason", a.Reason)
+		writeJSON(w, http.StatusOK, a)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
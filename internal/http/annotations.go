@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// annotationRequest is the body of POST /pairs/annotations
+type annotationRequest struct {
+	PMConditionID string   `json:"pm_condition_id"`
+	KalshiTicker  string   `json:"kalshi_ticker"`
+	Verified      bool     `json:"verified"`
+	Note          string   `json:"note"`
+	Tags          []string `json:"tags"`
+}
+
+// handleAnnotations lists pair annotations on GET and sets one on POST,
+// replacing any earlier annotation of the pair. Setting requires ADMIN_TOKEN
+// when it is configured.
+func (s *Server) handleAnnotations(w http.ResponseWriter, r *http.Request) {
+	if s.annotations == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "pair annotations are not configured")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		annotations := s.annotations.List()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"count":       len(annotations),
+			"annotations": annotations,
+		})
+	case http.MethodPost:
+		s.handleAnnotate(w, r)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleAnnotate sets the annotation of a pair
+func (s *Server) handleAnnotate(w http.ResponseWriter, r *http.Request) {
+	if s.adminToken != "" && !s.validAdminToken(r) {
+		writeError(w, r, http.StatusUnauthorized, "invalid admin token")
+		return
+	}
+
+	var req annotationRequest
+	if !decodeJSON(w, r, 4096, &req) {
+		return
+	}
+
+	a := arb.Annotation{
+		PMConditionID: req.PMConditionID,
+		KalshiTicker:  req.KalshiTicker,
+		Verified:      req.Verified,
+		Note:          req.Note,
+		Tags:          arb.NormalizeTags(req.Tags),
+		By:            s.adminActor(r),
+		At:            time.Now().UTC(),
+	}
+	before, existed, err := s.annotations.Set(a)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var prev interface{}
+	if existed {
+		prev = before
+	}
+	s.audit(r, "pair.annotate", a.PMConditionID+"|"+a.KalshiTicker, prev, a)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"annotation": a})
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+)
+
+// adminActor identifies who made an admin request: a fingerprint of the
+// bearer token, never the token itself, or "anonymous" without a valid one
+func (s *Server) adminActor(r *http.Request) string {
+	if s.adminToken == "" || !s.validAdminToken(r) {
+		return "anonymous"
+	}
+	sum := sha256.Sum256([]byte(s.adminToken))
+	return "key:" + hex.EncodeToString(sum[:4])
+}
+
+// audit records an admin mutation with the target's state before and after.
+// Failures are logged; the mutation itself has already happened.
+func (s *Server) audit(r *http.Request, action, target string, before, after interface{}) {
+	actor := s.adminActor(r)
+	s.logger.Info("admin action", "action", action, "target", target, "actor", actor, "remote_addr", r.RemoteAddr)
+	if s.store == nil {
+		return
+	}
+
+	entry := store.AuditEntry{
+		At:         time.Now(),
+		Actor:      actor,
+		RemoteAddr: r.RemoteAddr,
+		Action:     action,
+		Target:     target,
+	}
+	var err error
+	if entry.Before, err = json.Marshal(before); err == nil {
+		entry.After, err = json.Marshal(after)
+	}
+	if err == nil {
+		// Recorded even if the client has gone away
+		err = s.store.RecordAudit(context.WithoutCancel(r.Context()), entry)
+	}
+	if err != nil {
+		s.logger.Error("failed to record audit entry", "action", action, "error", err)
+	}
+}
+
+// handleAdminAudit lists recorded admin mutations, newest first, filtered by
+// ?window, ?action, ?actor and ?limit. When ADMIN_TOKEN is set it is required.
+func (s *Server) handleAdminAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.adminToken != "" && !s.validAdminToken(r) {
+		writeError(w, r, http.StatusUnauthorized, "invalid admin token")
+		return
+	}
+	if s.store == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "history persistence is disabled (set DB_PATH)")
+		return
+	}
+
+	query := r.URL.Query()
+	q := store.AuditQuery{Action: query.Get("action"), Actor: query.Get("actor")}
+	if v := query.Get("window"); v != "" {
+		window, err := parseWindow(v, 0)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		q.Since = time.Now().Add(-window)
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 1000 {
+			writeError(w, r, http.StatusBadRequest, "limit must be between 1 and 1000")
+			return
+		}
+		q.Limit = limit
+	}
+
+	entries, err := s.store.ListAudit(r.Context(), q)
+	if err != nil {
+		s.logger.Error("failed to list audit log", "error", err)
+		writeError(w, r, http.StatusInternalServerError, "failed to list audit log")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":   len(entries),
+		"entries": entries,
+	})
+}
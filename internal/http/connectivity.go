@@ -0,0 +1,37 @@
+package http
+
+import (
+	"net/http"
+	"time"
+)
+
+// handleConnectivity reports each WebSocket connection's uptime and its
+// connected and disconnected periods over ?window (default 7d), from the
+// recorded connection events
+func (s *Server) handleConnectivity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.store == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "history persistence is disabled (set DB_PATH)")
+		return
+	}
+
+	window, err := parseWindow(r.URL.Query().Get("window"), 7*24*time.Hour)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	now := time.Now()
+	sources, err := s.store.Connectivity(r.Context(), now.Add(-window), now)
+	if err != nil {
+		s.logger.Error("failed to query connectivity", "error", err)
+		writeError(w, r, http.StatusInternalServerError, "failed to query connectivity")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"window":  window.String(),
+		"sources": sources,
+	})
+}
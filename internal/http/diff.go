@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// handleArbsDiff returns the opportunities added, updated and removed since
+// revision ?since_rev, for pollers that cannot stream. Revisions start over
+// when the server restarts: clients pass back the epoch of their last
+// response as ?epoch, and get a reset listing every opportunity when it no
+// longer matches. Opportunities are in the negotiated payload version, as
+// on /arbs.
+func (s *Server) handleArbsDiff(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	version, err := negotiateSchemaVersion(r)
+	if err != nil {
+		writeError(w, r, http.StatusNotAcceptable, err.Error())
+		return
+	}
+
+	since, err := strconv.ParseUint(r.URL.Query().Get("since_rev"), 10, 64)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, "since_rev must be a revision number")
+		return
+	}
+	base := since
+	if epoch := r.URL.Query().Get("epoch"); epoch != "" && epoch != s.etagEpoch {
+		// No revision of this run matches the client's copy
+		base = ^uint64(0)
+	}
+
+	diff := s.engine.OpportunitiesSince(base)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Vary", schemaVersionHeader)
+	w.Header().Set(schemaVersionHeader, strconv.Itoa(version))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"epoch":          s.etagEpoch,
+		"revision":       diff.Revision,
+		"since_revision": since,
+		"reset":          diff.Reset,
+		"added":          opportunitiesAsVersion(diff.Added, version),
+		"updated":        opportunitiesAsVersion(diff.Updated, version),
+		"removed":        diff.Removed,
+	})
+}
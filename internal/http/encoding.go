@@ -0,0 +1,106 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// protobufContentType is the media type of protobuf responses
+const protobufContentType = "application/x-protobuf"
+
+// zstdEncoder is shared; EncodeAll is safe for concurrent use
+var zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+
+// wantsProtobuf reports whether the client asked for a protobuf response
+func wantsProtobuf(r *http.Request) bool {
+	for _, mediaType := range acceptedTokens(r.Header.Get("Accept")) {
+		if mediaType == protobufContentType || mediaType == "application/protobuf" {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateCompression picks "zstd" or "gzip" from Accept-Encoding,
+// preferring zstd, or "" to send the body uncompressed
+func negotiateCompression(r *http.Request) string {
+	var gz bool
+	for _, coding := range acceptedTokens(r.Header.Get("Accept-Encoding")) {
+		switch coding {
+		case "zstd":
+			return "zstd"
+		case "gzip":
+			gz = true
+		}
+	}
+	if gz {
+		return "gzip"
+	}
+	return ""
+}
+
+// acceptedTokens returns the lower-cased values of an Accept-style header,
+// dropping parameters and values refused with q=0
+func acceptedTokens(header string) []string {
+	var out []string
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(part, ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(q, 64); err == nil && f == 0 {
+				continue
+			}
+		}
+		out = append(out, value)
+	}
+	return out
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using
+// the weak comparison RFC 9110 prescribes for conditional GETs
+func etagMatches(header, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBody writes an encoded response body, compressed as the client accepts
+func writeBody(w http.ResponseWriter, r *http.Request, statusCode int, contentType string, body []byte) error {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept, Accept-Encoding, "+schemaVersionHeader)
+
+	switch negotiateCompression(r) {
+	case "zstd":
+		body = zstdEncoder.EncodeAll(body, nil)
+		w.Header().Set("Content-Encoding", "zstd")
+	case "gzip":
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(statusCode)
+	_, err := w.Write(body)
+	return err
+}
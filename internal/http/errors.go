@@ -0,0 +1,134 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+)
+
+// ErrorResponse is the body of every error response. Code is stable for
+// clients to branch on; Message is for humans and may change. RequestID is
+// also sent as the X-Request-ID header and logged with the request, so a
+// failure reported by a client can be found in the server logs.
+//
+// Status codes and their codes:
+//
+//	400 bad_request          malformed query parameter or body
+//	401 unauthorized         missing or wrong admin token
+//	403 forbidden            the operation is not allowed with this configuration
+//	404 not_found            unknown route, opportunity, pair or record
+//	405 method_not_allowed   wrong HTTP method for the route
+//	406 not_acceptable       unsupported X-Schema-Version requested
+//	410 expired              opportunity past its execution window
+//	413 payload_too_large    request body over the configured limit
+//	500 internal             unexpected failure, including handler panics
+//	503 unavailable          the feature is disabled or a dependency is down
+//	503 timeout              the handler exceeded the route's timeout
+type ErrorResponse struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"`
+
+	// Error repeats Message for clients of the original {"error": ...} body
+	Error string `json:"error"`
+}
+
+// errorCodes maps status codes to ErrorResponse codes
+var errorCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusNotAcceptable:         "not_acceptable",
+	http.StatusGone:                  "expired",
+	http.StatusRequestEntityTooLarge: "payload_too_large",
+	http.StatusInternalServerError:   "internal",
+	http.StatusServiceUnavailable:    "unavailable",
+}
+
+// errorCode returns the code of a status, falling back to its class
+func errorCode(statusCode int) string {
+	if code, ok := errorCodes[statusCode]; ok {
+		return code
+	}
+	if statusCode >= 500 {
+		return "internal"
+	}
+	return "bad_request"
+}
+
+// writeError writes an error envelope with the status's code
+func writeError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	writeErrorCode(w, r, statusCode, errorCode(statusCode), message)
+}
+
+// writeErrorCode writes an error envelope with an explicit code
+func writeErrorCode(w http.ResponseWriter, r *http.Request, statusCode int, code, message string) {
+	writeJSON(w, statusCode, ErrorResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: RequestID(r.Context()),
+		Error:     message,
+	})
+}
+
+// requestIDHeader carries the request's correlation ID; a client's own ID
+// is kept when well-formed
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID bounds client-supplied IDs, which end up in logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+type requestIDKey struct{}
+
+// RequestID returns the correlation ID of the request ctx belongs to, or ""
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// withRequestID assigns the request its correlation ID and echoes it in
+// the response headers
+func withRequestID(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := r.Header.Get(requestIDHeader)
+	if !validRequestID.MatchString(id) {
+		var b [8]byte
+		rand.Read(b[:])
+		id = hex.EncodeToString(b[:])
+	}
+	w.Header().Set(requestIDHeader, id)
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+}
+
+// timeoutEnvelope rewrites http.TimeoutHandler's plain-text timeout
+// response as an error envelope. The timeout is the only 503 written
+// without a Content-Type, as every handler error goes through writeError.
+type timeoutEnvelope struct {
+	http.ResponseWriter
+	r        *http.Request
+	timedOut bool
+}
+
+func (t *timeoutEnvelope) WriteHeader(code int) {
+	if code == http.StatusServiceUnavailable && t.Header().Get("Content-Type") == "" {
+		t.timedOut = true
+		writeErrorCode(t.ResponseWriter, t.r, code, "timeout", "request timed out")
+		return
+	}
+	t.ResponseWriter.WriteHeader(code)
+}
+
+func (t *timeoutEnvelope) Write(b []byte) (int, error) {
+	if t.timedOut {
+		return len(b), nil // TimeoutHandler's own body
+	}
+	return t.ResponseWriter.Write(b)
+}
+
+// handleNotFound answers requests matching no route
+func (s *Server) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusNotFound, "no such endpoint: "+r.URL.Path)
+}
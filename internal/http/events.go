@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+// handleEvents lists the monitored pairs grouped by underlying event, with
+// each event's opportunity count, total net edge and best opportunity;
+// events with the best edge come first. ?category= keeps one category and
+// ?open=true only events with an open opportunity.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	category := query.Get("category")
+	open := query.Get("open") == "true"
+	events := s.engine.Events()
+	kept := events[:0]
+	for _, ev := range events {
+		if category != "" && !strings.EqualFold(ev.Category, category) {
+			continue
+		}
+		if open && ev.OpportunityCount == 0 {
+			continue
+		}
+		kept = append(kept, ev)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":  len(kept),
+		"events": kept,
+	})
+}
+
+// handleEvent returns one event with its pairs and open opportunities
+func (s *Server) handleEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	event, ok := s.engine.Event(r.PathValue("id"))
+	if !ok {
+		writeError(w, r, http.StatusNotFound, "no monitored pair on this event")
+		return
+	}
+	writeJSON(w, http.StatusOK, event)
+}
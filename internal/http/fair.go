@@ -0,0 +1,166 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+// Fair value stream pacing
+const (
+	defaultFairInterval = time.Second
+	minFairInterval     = 100 * time.Millisecond
+	fairKeepalive       = 15 * time.Second
+)
+
+// handleFair returns the cross-venue fair value of every quotable
+// instrument, for maker bots quoting around it. ?venue (pm, kalshi) and
+// ?instrument narrow the list.
+func (s *Server) handleFair(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	keep, err := fairFilter(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	values := filterFairValues(s.engine.FairValues(), keep)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":       len(values),
+		"fair_values": values,
+	})
+}
+
+// handleFairStream streams fair values as server-sent events: a "fair"
+// event with every value at once, then one with the values that changed
+// every ?interval (default 1s), and a "removed" event with the keys of
+// values no longer derivable. Takes the same filters as /fair.
+func (s *Server) handleFairStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	keep, err := fairFilter(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	interval := defaultFairInterval
+	if v := r.URL.Query().Get("interval"); v != "" {
+		if interval, err = time.ParseDuration(v); err != nil || interval < minFairInterval {
+			writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid interval, want a duration of at least %s", minFairInterval))
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// Streams outlive the server's write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		writeError(w, r, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	sent := make(map[string]arb.FairValue)
+	lastWrite := time.Now()
+	for {
+		values := filterFairValues(s.engine.FairValues(), keep)
+		var changed []arb.FairValue
+		current := make(map[string]arb.FairValue, len(values))
+		for _, v := range values {
+			current[v.Key()] = v
+			if prev, ok := sent[v.Key()]; !ok || !prev.UpdatedAt.Equal(v.UpdatedAt) ||
+				prev.ReferenceBid != v.ReferenceBid || prev.ReferenceAsk != v.ReferenceAsk {
+				changed = append(changed, v)
+			}
+		}
+		var removed []string
+		for key := range sent {
+			if _, ok := current[key]; !ok {
+				removed = append(removed, key)
+			}
+		}
+		sent = current
+
+		var err error
+		switch {
+		case len(changed) > 0 || len(removed) > 0:
+			if len(changed) > 0 {
+				err = writeEvent(w, "fair", changed)
+			}
+			if err == nil && len(removed) > 0 {
+				err = writeEvent(w, "removed", removed)
+			}
+			lastWrite = time.Now()
+		case time.Since(lastWrite) >= fairKeepalive:
+			// Keeps proxies from closing an idle stream
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+			lastWrite = time.Now()
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.closing:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeEvent writes one server-sent event with a JSON payload
+func writeEvent(w http.ResponseWriter, event string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, body)
+	return err
+}
+
+// fairFilter parses the /fair filters
+func fairFilter(r *http.Request) (func(arb.FairValue) bool, error) {
+	q := r.URL.Query()
+	venue := q.Get("venue")
+	if venue != "" && venue != instrument.VenuePolymarket && venue != instrument.VenueKalshi {
+		return nil, fmt.Errorf("invalid venue %q, want %s or %s", venue, instrument.VenuePolymarket, instrument.VenueKalshi)
+	}
+	var id instrument.ID
+	if v := q.Get("instrument"); v != "" {
+		var err error
+		if id, err = instrument.Parse(v); err != nil {
+			return nil, err
+		}
+	}
+	return func(f arb.FairValue) bool {
+		return (venue == "" || f.Instrument.Venue() == venue) && (id == "" || f.Instrument == id)
+	}, nil
+}
+
+// filterFairValues keeps the values matching keep, in place
+func filterFairValues(values []arb.FairValue, keep func(arb.FairValue) bool) []arb.FairValue {
+	kept := values[:0]
+	for _, v := range values {
+		if keep(v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// unixPrefix marks a listen address as a unix domain socket path
+const unixPrefix = "unix:"
+
+// Listeners moves the admin API and /metrics off the public listener. Each
+// address is a TCP host:port or "unix:" followed by a socket path; empty
+// serves those routes on the public listener. A unix socket is created
+// readable and writable by the service's user and group only, so a local
+// admin socket is unreachable from the network altogether.
+type Listeners struct {
+	Admin   string
+	Metrics string
+}
+
+// adminRoutes are served on the admin listener when one is configured, and
+// are then absent from the public one
+var adminRoutes = map[string]bool{
+	"/admin/killswitch":               true,
+	"/admin/audit":                    true,
+	"/admin/warmstart":                true,
+	"/admin/threshold":                true,
+	"/ops":                            true,
+	"/pairs/near-misses/{id}":         true,
+	"/pairs/text-changes/{id}/accept": true,
+}
+
+// adminMethods are the mutating methods of otherwise public routes. With an
+// admin listener configured, each is served there and the route's GET stays
+// on the public listener.
+var adminMethods = map[string]string{
+	"/arbs/{id}/ack":     http.MethodPost,
+	"/pairs/annotations": http.MethodPost,
+}
+
+// Validate checks every address parses and no two listeners share one
+func (l Listeners) Validate(public string) error {
+	seen := make(map[string]string)
+	var errs []error
+	for _, a := range []struct{ name, addr string }{{"public", public}, {"admin", l.Admin}, {"metrics", l.Metrics}} {
+		if a.addr == "" {
+			continue
+		}
+		network, address, err := parseListenAddr(a.addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s listener: %w", a.name, err))
+			continue
+		}
+		key := network + " " + address
+		if other, ok := seen[key]; ok {
+			errs = append(errs, fmt.Errorf("%s listener: %q is already the %s listener", a.name, a.addr, other))
+			continue
+		}
+		seen[key] = a.name
+	}
+	return errors.Join(errs...)
+}
+
+// parseListenAddr splits addr into the network and address net.Listen takes
+func parseListenAddr(addr string) (network, address string, err error) {
+	if path, ok := strings.CutPrefix(addr, unixPrefix); ok {
+		if path == "" {
+			return "", "", fmt.Errorf("%q: missing socket path", addr)
+		}
+		return "unix", path, nil
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", "", fmt.Errorf("%q: %w", addr, err)
+	}
+	return "tcp", addr, nil
+}
+
+// listen opens addr. A stale socket file left by an unclean exit is removed
+// first; the listener removes its own socket when closed.
+func listen(addr string) (net.Listener, error) {
+	network, address, err := parseListenAddr(addr)
+	if err != nil {
+		return nil, err
+	}
+	if network != "unix" {
+		return net.Listen(network, address)
+	}
+
+	if fi, err := os.Stat(address); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", address)
+		}
+		if err := os.Remove(address); err != nil {
+			return nil, err
+		}
+	}
+	ln, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(address, 0o660); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// Limits bounds how much time and memory a single request may consume
+type Limits struct {
+	// RequestTimeout caps handler run time; routes listed in RouteTimeouts
+	// (keyed by mux pattern) override it. Zero disables the timeout.
+	RequestTimeout time.Duration
+	RouteTimeouts  map[string]time.Duration
+
+	ReadHeaderTimeout time.Duration
+	MaxHeaderBytes    int
+	MaxBodyBytes      int64 // Reading past this fails; see decodeJSON
+}
+
+// DefaultLimits are applied unless SetLimits is called
+var DefaultLimits = Limits{
+	RequestTimeout:    5 * time.Second,
+	RouteTimeouts:     map[string]time.Duration{"/arbs/heatmap": 30 * time.Second},
+	ReadHeaderTimeout: 5 * time.Second,
+	MaxHeaderBytes:    64 << 10,
+	MaxBodyBytes:      1 << 20,
+}
+
+// ParseRouteTimeouts parses per-route timeouts given as "pattern=duration"
+func ParseRouteTimeouts(entries []string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		pattern, value, ok := strings.Cut(entry, "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("route timeout %q: want pattern=duration", entry)
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("route timeout %q: %w", entry, err)
+		}
+		timeouts[pattern] = d
+	}
+	return timeouts, nil
+}
+
+// timeoutFor returns the handler timeout of a route
+func (l Limits) timeoutFor(pattern string) time.Duration {
+	if d, ok := l.RouteTimeouts[pattern]; ok {
+		return d
+	}
+	return l.RequestTimeout
+}
+
+// writeTimeout is the connection write deadline: long enough for the slowest
+// route to time out and still send its response
+func (l Limits) writeTimeout() time.Duration {
+	longest := l.RequestTimeout
+	for _, d := range l.RouteTimeouts {
+		longest = max(longest, d)
+	}
+	return max(10*time.Second, longest+5*time.Second)
+}
+
+// handle registers a handler behind logging, latency metrics, panic
+// recovery, the body size limit and the route's timeout, outermost first. A
+// method-qualified pattern shares its path's timeout and metrics label.
+func (s *Server) handle(mux *http.ServeMux, pattern string, h http.HandlerFunc) {
+	path := pattern
+	if _, p, ok := strings.Cut(pattern, " "); ok {
+		path = p
+	}
+	var next http.Handler = h
+	if d := s.limits.timeoutFor(path); d > 0 {
+		timeout := http.TimeoutHandler(next, d, "Request timed out")
+		next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout.ServeHTTP(&timeoutEnvelope{ResponseWriter: w, r: r}, r)
+		})
+	} else {
+		// Without a route timeout the request still can't outlive the
+		// connection's write deadline, so queries give up with it
+		deadline := s.limits.writeTimeout()
+		inner := next
+		next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), deadline)
+			defer cancel()
+			inner.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+	mux.HandleFunc(pattern, s.loggingMiddleware(timed(path, s.recoverMiddleware(s.limitBody(next)))))
+}
+
+// handleStream registers a streaming handler behind logging and panic
+// recovery; streams run until the client leaves or the server shuts down,
+// so no timeout applies and they are counted while open instead of timed
+func (s *Server) handleStream(mux *http.ServeMux, pattern string, h http.HandlerFunc) {
+	mux.HandleFunc(pattern, s.loggingMiddleware(counted(pattern, s.recoverMiddleware(s.limitBody(h)))))
+}
+
+// timed records the handling time of a route's requests
+func timed(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	if pattern == "/" {
+		pattern = "unmatched"
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		defer func() { metrics.ObserveHTTPRequestDuration(pattern, time.Since(start)) }()
+		next(w, r)
+	}
+}
+
+// counted tracks a streaming route's open responses
+func counted(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metrics.AddHTTPOpenStreams(pattern, 1)
+		defer metrics.AddHTTPOpenStreams(pattern, -1)
+		next(w, r)
+	}
+}
+
+// limitBody caps the request body at MaxBodyBytes
+func (s *Server) limitBody(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.limits.MaxBodyBytes > 0 && r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, s.limits.MaxBodyBytes)
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// decodeJSON decodes a request body of at most limit bytes into dst. On
+// failure it writes 413 or 400 and returns false.
+func decodeJSON(w http.ResponseWriter, r *http.Request, limit int64, dst interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit)).Decode(dst)
+	if err == nil {
+		return true
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, r, http.StatusRequestEntityTooLarge, "request body too large")
+	} else {
+		writeError(w, r, http.StatusBadRequest, "invalid request body")
+	}
+	return false
+}
+
+// recoverMiddleware turns a handler panic into a 500 envelope instead of
+// dropping the connection, and logs the stack under the request's ID
+func (s *Server) recoverMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			s.logger.Error("http handler panic",
+				"request_id", RequestID(r.Context()),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"panic", fmt.Sprint(rec),
+				"stack", string(debug.Stack()),
+			)
+			writeError(w, r, http.StatusInternalServerError, "internal server error, see request "+RequestID(r.Context()))
+		}()
+		next(w, r)
+	}
+}
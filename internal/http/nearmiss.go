@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// nearMissDecision is the body of POST /pairs/near-misses/{id}
+type nearMissDecision struct {
+	Status string `json:"status"` // promoted, dismissed or pending
+}
+
+// handleNearMisses lists candidate pairs that scored just below the matching
+// threshold, highest score first, optionally filtered by ?status
+func (s *Server) handleNearMisses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.nearMisses == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "near-miss tracking is disabled (set NEAR_MISS_MIN_SIM)")
+		return
+	}
+
+	nearMisses := s.nearMisses.List(r.URL.Query().Get("status"))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":       len(nearMisses),
+		"near_misses": nearMisses,
+	})
+}
+
+// handleNearMissDecision promotes or dismisses a near-miss. A promoted pair
+// is monitored immediately and on every later bootstrap that finds it;
+// dismissing a promoted pair takes effect at the next restart. When
+// ADMIN_TOKEN is set it is required.
+func (s *Server) handleNearMissDecision(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.adminToken != "" && !s.validAdminToken(r) {
+		writeError(w, r, http.StatusUnauthorized, "invalid admin token")
+		return
+	}
+	if s.nearMisses == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "near-miss tracking is disabled (set NEAR_MISS_MIN_SIM)")
+		return
+	}
+
+	var req nearMissDecision
+	if !decodeJSON(w, r, 4096, &req) {
+		return
+	}
+
+	id := r.PathValue("id")
+	before, _ := s.nearMisses.Get(id)
+	n, pair, activate, err := s.nearMisses.Decide(id, req.Status, s.adminActor(r), time.Now())
+	switch {
+	case errors.Is(err, arb.ErrNearMissNotFound):
+		writeError(w, r, http.StatusNotFound, err.Error())
+		return
+	case err != nil:
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if activate && s.activatePairs != nil {
+		s.activatePairs([]arb.MarketPair{pair})
+	}
+	s.audit(r, "near_miss."+n.Status, id, before, n)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"near_miss": n,
+		"monitored": activate,
+	})
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"crypto/subtle"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/killswitch"
+)
+
+// killSwitchRequest is the body of POST /admin/killswitch
+type killSwitchRequest struct {
+	Engaged bool   `json:"engaged"`
+	Reason  string `json:"reason"`
+}
+
+// authorizeAdmin checks the bearer token against ADMIN_TOKEN. With no token
+// configured only engaging is allowed, since that can only make things safer.
+func (s *Server) authorizeAdmin(r *http.Request, engaging bool) (int, string, bool) {
+	if s.adminToken == "" {
+		if engaging {
+			return 0, "", true
+		}
+		return http.StatusForbidden, "set ADMIN_TOKEN to release the kill switch remotely", false
+	}
+
+	if !s.validAdminToken(r) {
+		return http.StatusUnauthorized, "invalid admin token", false
+	}
+	return 0, "", true
+}
+
+// validAdminToken reports whether the request carries ADMIN_TOKEN as a bearer token
+func (s *Server) validAdminToken(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
+// handleKillSwitch reports (GET) or changes (POST) the execution kill switch
+func (s *Server) handleKillSwitch(w http.ResponseWriter, r *http.Request) {
+	if s.killSwitch == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "kill switch is not configured")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.killSwitch.State())
+	case http.MethodPost:
+		var req killSwitchRequest
+		if !decodeJSON(w, r, 4096, &req) {
+			return
+		}
+		if code, msg, ok := s.authorizeAdmin(r, req.Engaged); !ok {
+			writeError(w, r, code, msg)
+			return
+		}
+
+		before := s.killSwitch.State()
+		action := "killswitch.release"
+		if req.Engaged {
+			reason := req.Reason
+			if reason == "" {
+				reason = "engaged via admin endpoint"
+			}
+			s.killSwitch.Engage(killswitch.SourceManual, reason)
+			action = "killswitch.engage"
+		} else {
+			s.killSwitch.Release(r.RemoteAddr)
+		}
+		after := s.killSwitch.State()
+		s.audit(r, action, "killswitch", before, after)
+		writeJSON(w, http.StatusOK, after)
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleOps serves a minimal operations page showing the kill switch state
+// with engage/release controls
+func (s *Server) handleOps(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.killSwitch == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "kill switch is not configured")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := opsPage.Execute(w, s.killSwitch.State()); err != nil {
+		s.logger.Error("failed to render ops page", "error", err)
+	}
+}
+
+var opsPage = template.Must(template.New("ops").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>arb-ws ops</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.banner { padding: 1.5em; font-size: 1.6em; font-weight: bold; color: #fff; border-radius: 6px; }
+.engaged { background: #c0392b; }
+.released { background: #27ae60; }
+table { margin-top: 1.5em; border-collapse: collapse; }
+td { padding: 0.3em 1em 0.3em 0; }
+button { font-size: 1.1em; padding: 0.5em 1.2em; margin-right: 1em; }
+</style>
+</head>
+<body>
+{{if .Engaged}}
+<div class="banner engaged">EXECUTION DISABLED &mdash; kill switch engaged ({{.Source}})</div>
+{{else}}
+<div class="banner released">Execution kill switch released</div>
+{{end}}
+<table>
+{{if .Engaged}}<tr><td>Reason</td><td>{{.Reason}}</td></tr>
+<tr><td>Since</td><td>{{.Since}}</td></tr>{{end}}
+<tr><td>Realized loss in window</td><td>{{printf "%.2f" .WindowLoss}}{{if .MaxLoss}} / {{printf "%.2f" .MaxLoss}}{{end}} USD</td></tr>
+<tr><td>Failed unwinds in window</td><td>{{.FailedUnwinds}}{{if .MaxFailedUnwinds}} / {{.MaxFailedUnwinds}}{{end}}</td></tr>
+<tr><td>Window</td><td>{{.Window}}</td></tr>
+</table>
+<p>
+<input id="reason" placeholder="reason" size="40">
+<input id="token" type="password" placeholder="admin token">
+</p>
+<button onclick="setSwitch(true)">Engage</button>
+<button onclick="setSwitch(false)">Release</button>
+<p id="result"></p>
+<script>
+async function setSwitch(engaged) {
+  const headers = {"Content-Type": "application/json"};
+  const token = document.getElementById("token").value;
+  if (token) headers["Authorization"] = "Bearer " + token;
+  const resp = await fetch("/admin/killswitch", {
+    method: "POST", headers: headers,
+    body: JSON.stringify({engaged: engaged, reason: document.getElementById("reason").value}),
+  });
+  if (resp.ok) { location.reload(); return; }
+  document.getElementById("result").textContent = (await resp.json()).error;
+}
+</script>
+</body>
+</html>
+`))
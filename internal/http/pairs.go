@@ -0,0 +1,130 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// handlePairs lists the monitored pairs with their latest liquidity
+// snapshots. ?sort= orders them by a key such as volume_24h, liquidity or
+// open_interest (descending; default: monitoring order),
+// ?min_volume_24h=, ?min_liquidity= and ?min_open_interest= prune thin pairs,
+// and ?tag= keeps pairs annotated with a tag.
+func (s *Server) handlePairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	var floor arb.ListingFloor
+	for name, dst := range map[string]*float64{
+		"min_volume_24h":    &floor.MinVolume24h,
+		"min_liquidity":     &floor.MinLiquidity,
+		"min_open_interest": &floor.MinOpenInterest,
+	} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			writeError(w, r, http.StatusBadRequest, "invalid "+name)
+			return
+		}
+		*dst = f
+	}
+
+	pairs := s.engine.Pairs()
+	if s.pmRules != nil {
+		fresh := make([]arb.MarketPair, len(pairs))
+		for i, pair := range pairs {
+			fresh[i] = s.pmRules.Apply(pair)
+		}
+		pairs = fresh
+	}
+	listings := s.liquidity.Listings(pairs)
+	if key := query.Get("sort"); key != "" {
+		if err := arb.SortListings(listings, key); err != nil {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	listings = floor.Apply(listings)
+	if tag := query.Get("tag"); tag != "" {
+		tagged := listings[:0]
+		for _, l := range listings {
+			if a, ok := s.annotations.Get(l.PMConditionID, l.KalshiTicker); ok && a.HasTag(tag) {
+				tagged = append(tagged, l)
+			}
+		}
+		listings = tagged
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count": len(listings),
+		"pairs": listings,
+	})
+}
+
+// handlePairView serves per-pair views under /pairs/{id}/. A wildcard view
+// segment keeps the route from conflicting with /pairs/near-misses/{id}.
+func (s *Server) handlePairView(w http.ResponseWriter, r *http.Request) {
+	switch r.PathValue("view") {
+	case "spread":
+		s.handlePairSpread(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handlePairSpread returns a pair's sampled YES mids on both venues and the
+// cross-venue spread over ?window= (default 24h), oldest first. The pair is
+// identified by the id listed in /pairs.
+func (s *Server) handlePairSpread(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.store == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "history persistence is disabled (set DB_PATH)")
+		return
+	}
+
+	id := r.PathValue("id")
+	var pair *arb.MarketPair
+	for _, p := range s.engine.Pairs() {
+		if p.ID() == id {
+			pair = &p
+			break
+		}
+	}
+	if pair == nil {
+		writeError(w, r, http.StatusNotFound, "pair not monitored")
+		return
+	}
+
+	window, err := parseWindow(r.URL.Query().Get("window"), 24*time.Hour)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	points, err := s.store.Spreads(r.Context(), id, time.Now().Add(-window))
+	if err != nil {
+		s.logger.Error("failed to load spread history", "pair", id, "error", err)
+		writeError(w, r, http.StatusInternalServerError, "failed to load spread history")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"id":     id,
+		"pair":   pair,
+		"window": window.String(),
+		"count":  len(points),
+		"points": points,
+	})
+}
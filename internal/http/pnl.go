@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/shadow"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+)
+
+// maxPnLSettlements caps the settlements listed by /pnl; the aggregates
+// cover the whole window
+const maxPnLSettlements = 100
+
+// handlePnL reports the settled PnL of paper trades over ?window (default
+// 30d), in total and by strategy, category and pair confidence, with the
+// most recent settlements. ?opp_id narrows both to one opportunity.
+func (s *Server) handlePnL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.store == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "history persistence is disabled (set DB_PATH)")
+		return
+	}
+
+	window, err := parseWindow(r.URL.Query().Get("window"), 30*24*time.Hour)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	settlements, err := s.store.Settlements(r.Context(), store.SettlementQuery{
+		Since: time.Now().Add(-window),
+		OppID: r.URL.Query().Get("opp_id"),
+	})
+	if err != nil {
+		s.logger.Error("failed to query settlements", "error", err)
+		writeError(w, r, http.StatusInternalServerError, "failed to query settlements")
+		return
+	}
+
+	attribution := shadow.NewAttribution()
+	for _, st := range settlements {
+		attribution.Add(st)
+	}
+	recent := settlements[:min(len(settlements), maxPnLSettlements)]
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"window":      window.String(),
+		"attribution": attribution,
+		"count":       len(recent),
+		"settlements": recent,
+	})
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+)
+
+// defaultSearchLimit caps each kind of hit unless ?limit= is given
+const defaultSearchLimit = 20
+
+// handleSearch runs a full-text search over the monitored pairs (titles,
+// category and tags) and the opportunity history, e.g. /search?q=fed+cut.
+// Every word must match; the last also matches as a prefix. ?limit= caps
+// each kind of hit, at most 200.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.store == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "history persistence is disabled (set DB_PATH)")
+		return
+	}
+
+	query := r.URL.Query()
+	q := query.Get("q")
+	limit := defaultSearchLimit
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 200 {
+			writeError(w, r, http.StatusBadRequest, "limit must be between 1 and 200")
+			return
+		}
+		limit = n
+	}
+
+	results, err := s.store.Search(r.Context(), q, limit)
+	if errors.Is(err, store.ErrEmptyQuery) {
+		writeError(w, r, http.StatusBadRequest, "q must contain at least one word")
+		return
+	}
+	if err != nil {
+		s.logger.Error("search failed", "query", q, "error", err)
+		writeError(w, r, http.StatusInternalServerError, "search failed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"query":         q,
+		"pairs":         results.Pairs,
+		"opportunities": results.Opportunities,
+	})
+}
@@ -3,69 +3,307 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/artemgubar/prediction-markets/arb-ws/internal/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/account"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/killswitch"
 	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/store"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// StatusFunc returns one section of the /status response
+type StatusFunc func() interface{}
+
 // Server provides HTTP endpoints for the arbitrage service
 type Server struct {
-	addr   string
-	engine *arb.Engine
-	logger *slog.Logger
-	server *http.Server
+	addr    string
+	engine  *arb.Engine
+	store   *store.Store // nil when persistence is disabled
+	account *account.Tracker
+	logger  *slog.Logger
+
+	listeners Listeners
+	serversMu sync.Mutex
+	servers   []*http.Server // One per listener, once started
+
+	limits    Limits
+	etagEpoch string // Distinguishes ETags across restarts, when revisions start over
+
+	killSwitch *killswitch.Switch
+	adminToken string // Bearer token for admin endpoints; empty allows only engaging the kill switch
+
+	nearMisses    *arb.NearMissRegistry
+	activatePairs func([]arb.MarketPair) // Starts monitoring promoted near-misses
+	texts         *arb.TextWatch
+	annotations   *arb.AnnotationRegistry
+	liquidity     *arb.LiquidityBook // nil lists pairs without snapshots
+	pmRules       *arb.PMRulesCache  // nil lists pairs with their constraints as matched
+	pruner        *arb.SubscriptionPruner
+
+	statusMu       sync.RWMutex
+	statusSections map[string]StatusFunc
+
+	closing   chan struct{} // Closed on Shutdown to end streams
+	closeOnce sync.Once
 }
 
 // NewServer creates a new HTTP server
 func NewServer(addr string, engine *arb.Engine, logger *slog.Logger) *Server {
 	return &Server{
-		addr:   addr,
-		engine: engine,
-		logger: logger,
+		addr:           addr,
+		engine:         engine,
+		logger:         logger,
+		limits:         DefaultLimits,
+		etagEpoch:      strconv.FormatInt(time.Now().UnixNano(), 36),
+		statusSections: make(map[string]StatusFunc),
+		closing:        make(chan struct{}),
 	}
 }
 
-// Start starts the HTTP server
+// SetLimits replaces the default request timeouts and size limits
+func (s *Server) SetLimits(l Limits) {
+	s.limits = l
+}
+
+// SetListeners serves the admin API and metrics on listeners of their own
+func (s *Server) SetListeners(l Listeners) {
+	s.listeners = l
+}
+
+// SetStore enables endpoints backed by persisted history
+func (s *Server) SetStore(st *store.Store) {
+	s.store = st
+}
+
+// SetNearMisses enables the near-miss review endpoints; activate starts
+// monitoring a promoted pair
+func (s *Server) SetNearMisses(registry *arb.NearMissRegistry, activate func([]arb.MarketPair)) {
+	s.nearMisses = registry
+	s.activatePairs = activate
+}
+
+// SetTextWatch enables the market text change review endpoints
+func (s *Server) SetTextWatch(w *arb.TextWatch) {
+	s.texts = w
+}
+
+// SetAnnotations enables the pair annotation endpoints
+func (s *Server) SetAnnotations(r *arb.AnnotationRegistry) {
+	s.annotations = r
+}
+
+// SetLiquidity attaches market liquidity snapshots to the pairs list
+func (s *Server) SetLiquidity(book *arb.LiquidityBook) {
+	s.liquidity = book
+}
+
+// SetPMRules lists pairs with their markets' cached Polymarket order
+// constraints
+func (s *Server) SetPMRules(c *arb.PMRulesCache) {
+	s.pmRules = c
+}
+
+// SetPruner enables the list of pairs pruned to fit subscription budgets
+func (s *Server) SetPruner(p *arb.SubscriptionPruner) {
+	s.pruner = p
+}
+
+// SetAccount enables the account activity endpoint
+func (s *Server) SetAccount(tracker *account.Tracker) {
+	s.account = tracker
+}
+
+// SetKillSwitch enables the kill switch admin endpoint and ops page
+func (s *Server) SetKillSwitch(ks *killswitch.Switch, adminToken string) {
+	s.killSwitch = ks
+	s.adminToken = adminToken
+}
+
+// RegisterStatus adds a named section to the /status response.
+// Registering the same name twice replaces the previous section.
+func (s *Server) RegisterStatus(name string, fn StatusFunc) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.statusSections[name] = fn
+}
+
+// Start opens every listener and serves until Shutdown. It returns once
+// all listeners are closed, or with the first listener's error after
+// closing the rest.
 func (s *Server) Start() error {
 	mux := http.NewServeMux()
+	admin, metricsMux := mux, mux
+	if s.listeners.Admin != "" {
+		admin = http.NewServeMux()
+		s.handle(admin, "/", s.handleNotFound)
+	}
+	if s.listeners.Metrics != "" {
+		metricsMux = http.NewServeMux()
+	}
+	route := func(pattern string, h http.HandlerFunc) {
+		if adminRoutes[pattern] {
+			s.handle(admin, pattern, h)
+			return
+		}
+		if method, ok := adminMethods[pattern]; ok && admin != mux {
+			s.handle(admin, method+" "+pattern, h)
+			s.handle(mux, http.MethodGet+" "+pattern, h)
+			return
+		}
+		s.handle(mux, pattern, h)
+	}
 
 	// Register routes
-	mux.HandleFunc("/healthz", s.loggingMiddleware(s.handleHealthz))
-	mux.HandleFunc("/arbs", s.loggingMiddleware(s.handleArbs))
-	mux.Handle("/metrics", promhttp.Handler())
+	route("/healthz", s.handleHealthz)
+	route("/arbs", s.handleArbs)
+	route("/arbs/heatmap", s.handleArbsHeatmap)
+	route("/arbs/diff", s.handleArbsDiff)
+	route("/arbs/{id}/history", s.handleArbHistory)
+	route("/arbs/{id}/ack", s.handleArbAck)
+	route("/schema/opportunity.json", s.handleOpportunitySchema)
+	route("/schema/opportunity.proto", s.handleOpportunityProto)
+	route("/pairs", s.handlePairs)
+	route("/pairs/halted", s.handleHaltedPairs)
+	route("/pairs/news-held", s.handleNewsHeldPairs)
+	route("/pairs/near-misses", s.handleNearMisses)
+	route("/pairs/pruned", s.handlePrunedPairs)
+	route("/pairs/near-misses/{id}", s.handleNearMissDecision)
+	route("/pairs/text-changes", s.handleTextChanges)
+	route("/pairs/annotations", s.handleAnnotations)
+	route("/pairs/{id}/{view}", s.handlePairView)
+	route("/pairs/text-changes/{id}/accept", s.handleTextChangeAccept)
+	route("/events", s.handleEvents)
+	route("/events/{id}", s.handleEvent)
+	route("/search", s.handleSearch)
+	route("/prices", s.handlePrices)
+	route("/fair", s.handleFair)
+	route("/instruments/{id}", s.handleInstrument)
+	route("/evaluate", s.handleEvaluate)
+	route("/signals/unhedged", s.handleUnhedgedSignals)
+	s.handleStream(mux, "/fair/stream", s.handleFairStream)
+	route("/status", s.handleStatus)
+	route("/status/connectivity", s.handleConnectivity)
+	route("/account", s.handleAccount)
+	route("/pnl", s.handlePnL)
+	route("/admin/killswitch", s.handleKillSwitch)
+	route("/admin/audit", s.handleAdminAudit)
+	route("/admin/warmstart", s.handleWarmStart)
+	route("/admin/threshold", s.handleThreshold)
+	route("/ops", s.handleOps)
+	route("/", s.handleNotFound)
+	metricsMux.Handle("/metrics", promhttp.Handler())
+
+	type served struct {
+		name, addr string
+		handler    http.Handler
+	}
+	all := []served{{"public", s.addr, mux}}
+	if s.listeners.Admin != "" {
+		all = append(all, served{"admin", s.listeners.Admin, admin})
+	}
+	if s.listeners.Metrics != "" {
+		all = append(all, served{"metrics", s.listeners.Metrics, metricsMux})
+	}
 
-	s.server = &http.Server{
-		Addr:         s.addr,
-		Handler:      mux,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	// Open every listener before serving any, so a bad address fails
+	// startup rather than leaving some routes unreachable
+	listeners := make([]net.Listener, 0, len(all))
+	for _, l := range all {
+		ln, err := listen(l.addr)
+		if err != nil {
+			for _, open := range listeners {
+				open.Close()
+			}
+			return fmt.Errorf("%s listener: %w", l.name, err)
+		}
+		listeners = append(listeners, ln)
 	}
 
-	s.logger.Info("http server starting", "addr", s.addr)
+	servers := make([]*http.Server, len(all))
+	for i, l := range all {
+		servers[i] = &http.Server{
+			Handler:           l.handler,
+			ReadHeaderTimeout: s.limits.ReadHeaderTimeout,
+			ReadTimeout:       10 * time.Second,
+			WriteTimeout:      s.limits.writeTimeout(),
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    s.limits.MaxHeaderBytes,
+		}
+	}
+	s.serversMu.Lock()
+	select {
+	case <-s.closing: // Shut down before starting
+		s.serversMu.Unlock()
+		for _, ln := range listeners {
+			ln.Close()
+		}
+		return nil
+	default:
+	}
+	s.servers = servers
+	s.serversMu.Unlock()
 
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return err
+	errc := make(chan error, len(all))
+	for i, l := range all {
+		s.logger.Info("http server starting", "listener", l.name, "addr", l.addr)
+		go func() {
+			err := servers[i].Serve(listeners[i])
+			if err == http.ErrServerClosed {
+				err = nil
+			} else if err != nil {
+				err = fmt.Errorf("%s listener: %w", l.name, err)
+			}
+			errc <- err
+		}()
 	}
 
-	return nil
+	var first error
+	for range all {
+		if err := <-errc; err != nil && first == nil {
+			first = err
+			for _, srv := range servers {
+				srv.Close()
+			}
+		}
+	}
+	return first
 }
 
-// Shutdown gracefully shuts down the HTTP server
+// Shutdown gracefully shuts down every listener
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("http server shutting down")
-	return s.server.Shutdown(ctx)
+	s.closeOnce.Do(func() { close(s.closing) })
+	s.serversMu.Lock()
+	servers := s.servers
+	s.serversMu.Unlock()
+
+	var errs []error
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// loggingMiddleware logs HTTP requests and records metrics
+// loggingMiddleware assigns each request its correlation ID, logs it and
+// records metrics
 func (s *Server) loggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
+		r = withRequestID(w, r)
 
 		// Create a response writer wrapper to capture status code
 		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
@@ -76,8 +314,13 @@ func (s *Server) loggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		// Log and record metrics
 		duration := time.Since(start)
 		statusCode := strconv.Itoa(rw.statusCode)
+		path := r.URL.Path
+		if r.Pattern == "/" {
+			path = "unmatched" // Arbitrary paths would make unbounded metric labels
+		}
 
 		s.logger.Info("http request",
+			"request_id", RequestID(r.Context()),
 			"method", r.Method,
 			"path", r.URL.Path,
 			"status", statusCode,
@@ -85,7 +328,7 @@ func (s *Server) loggingMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			"remote_addr", r.RemoteAddr,
 		)
 
-		metrics.RecordHTTPRequest(r.URL.Path, statusCode)
+		metrics.RecordHTTPRequest(path, statusCode)
 	}
 }
 
@@ -100,10 +343,15 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // handleHealthz returns a simple health check response
 func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
@@ -112,30 +360,401 @@ func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("ok"))
 }
 
-// handleArbs returns the current list of arbitrage opportunities
+// handleArbs returns the current list of arbitrage opportunities. ?sort=
+// orders them by another key, e.g. annualized_return (see
+// arb.SortOpportunities), and ?min_annualized_return= sets a floor on it.
 func (s *Server) handleArbs(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	// Get opportunities from engine
-	opportunities := s.engine.GetOpportunities()
+	version, err := negotiateSchemaVersion(r)
+	if err != nil {
+		writeError(w, r, http.StatusNotAcceptable, err.Error())
+		return
+	}
 
-	// Return JSON
-	w.Header().Set("Content-Type", "application/json")
+	// Serve from the engine's published snapshot, so slow clients never
+	// hold the engine lock; pollers whose copy is current get a 304 before
+	// anything is serialized. Each payload version has tags of its own.
+	snap := s.engine.Snapshot()
+	etag := fmt.Sprintf(`W/"%s-%d-v%d"`, s.etagEpoch, snap.Revision, version)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set(truncatedHeader, strconv.Itoa(snap.Truncated))
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("Vary", "Accept, Accept-Encoding, "+schemaVersionHeader)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	opportunities := snap.Opportunities
+
+	// Optional client-side confidence floor
+	if v := r.URL.Query().Get("min_confidence"); v != "" {
+		minConfidence, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid min_confidence")
+			return
+		}
+		opportunities = filterOpportunities(opportunities, func(o arb.Opportunity) bool {
+			return o.Confidence >= minConfidence
+		})
+	}
+
+	// Optional floor on return per year until resolution; opportunities
+	// without a resolution time pass, as in the annualized_return filter
+	if v := r.URL.Query().Get("min_annualized_return"); v != "" {
+		minAnnualized, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid min_annualized_return")
+			return
+		}
+		opportunities = filterOpportunities(opportunities, func(o arb.Opportunity) bool {
+			return o.AnnualizedReturn == nil || *o.AnnualizedReturn >= minAnnualized
+		})
+	}
+
+	// Optional per-strategy stream
+	if strategy := r.URL.Query().Get("strategy"); strategy != "" {
+		opportunities = filterOpportunities(opportunities, func(o arb.Opportunity) bool {
+			return o.Strategy == strategy
+		})
+	}
+
+	// Optional operator tag, from the pair's annotation
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		opportunities = filterOpportunities(opportunities, func(o arb.Opportunity) bool {
+			return o.Annotation != nil && o.Annotation.HasTag(tag)
+		})
+	}
+
+	// Optional order other than the engine's, by edge
+	if key := r.URL.Query().Get("sort"); key != "" {
+		sorted := append([]arb.Opportunity(nil), opportunities...)
+		if err := arb.SortOpportunities(sorted, key); err != nil {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		opportunities = sorted
+	}
+
+	opportunities = opportunitiesAsVersion(opportunities, version)
+
+	// JSON by default; protobuf for clients that ask for it
+	contentType := "application/json"
+	var body []byte
+	if wantsProtobuf(r) {
+		contentType = protobufContentType
+		body = arb.MarshalOpportunitiesProto(opportunities)
+	} else {
+		body, err = json.Marshal(opportunities)
+		if err != nil {
+			s.logger.Error("failed to encode opportunities", "error", err)
+			writeError(w, r, http.StatusInternalServerError, "internal server error")
+			return
+		}
+		body = append(body, '\n')
+	}
+
+	w.Header().Set(schemaVersionHeader, strconv.Itoa(version))
+	if err := writeBody(w, r, http.StatusOK, contentType, body); err != nil {
+		s.logger.Debug("failed to write opportunities", "error", err)
+	}
+}
+
+// filterOpportunities returns the opportunities keep accepts in a new
+// slice; snapshot slices are shared and must not be filtered in place
+func filterOpportunities(opps []arb.Opportunity, keep func(arb.Opportunity) bool) []arb.Opportunity {
+	filtered := make([]arb.Opportunity, 0, len(opps))
+	for _, o := range opps {
+		if keep(o) {
+			filtered = append(filtered, o)
+		}
+	}
+	return filtered
+}
+
+// handleOpportunityProto serves the protobuf schema of the Opportunity payload
+func (s *Server) handleOpportunityProto(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(arb.OpportunityProto)
+}
+
+// handleOpportunitySchema serves the JSON Schema of the Opportunity payload,
+// the current version by default or ?version=N
+func (s *Server) handleOpportunitySchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	version := arb.OpportunitySchemaVersion
+	if v := r.URL.Query().Get("version"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid version")
+			return
+		}
+		version = n
+	}
+
+	doc, err := arb.OpportunitySchema(version)
+	if err != nil {
+		writeError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Header().Set(schemaVersionHeader, strconv.Itoa(version))
 	w.WriteHeader(http.StatusOK)
+	w.Write(doc)
+}
+
+// handleArbsHeatmap returns opportunity counts and average edges bucketed by
+// category and UTC hour of day over persisted history (?window=30d by default)
+func (s *Server) handleArbsHeatmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.store == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "history persistence is disabled (set DB_PATH)")
+		return
+	}
+
+	window, err := parseWindow(r.URL.Query().Get("window"), 30*24*time.Hour)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cells, err := s.store.Heatmap(r.Context(), time.Now().Add(-window))
+	if err != nil {
+		s.logger.Error("failed to build heatmap", "error", err)
+		writeError(w, r, http.StatusInternalServerError, "failed to build heatmap")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"window": window.String(),
+		"cells":  cells,
+	})
+}
 
-	if err := json.NewEncoder(w).Encode(opportunities); err != nil {
-		s.logger.Error("failed to encode opportunities", "error", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+// handleArbHistory returns the sampled edge trajectory of an open or recently
+// ended opportunity
+func (s *Server) handleArbHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
+
+	history, err := s.engine.GetOpportunityHistory(r.Context(), r.PathValue("id"))
+	if errors.Is(err, arb.ErrHistoryNotFound) {
+		writeError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusServiceUnavailable, "request timed out")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, history)
 }
 
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error"`
+// handleHaltedPairs lists pairs excluded from opportunities because a venue
+// halted one of their markets or it is outside trading hours
+func (s *Server) handleHaltedPairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	pairs := s.engine.HaltedPairs()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count": len(pairs),
+		"pairs": pairs,
+	})
+}
+
+// handleNewsHeldPairs lists pairs held out of opportunities after a sharp
+// price move on one of their markets
+func (s *Server) handleNewsHeldPairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	pairs := s.engine.NewsHeldPairs()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count": len(pairs),
+		"pairs": pairs,
+	})
+}
+
+// handlePrunedPairs lists matched pairs left unmonitored because they did
+// not fit in a venue's subscription budget, best first
+func (s *Server) handlePrunedPairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	pairs := []arb.PrunedPair{}
+	if s.pruner != nil {
+		pairs = s.pruner.Pruned()
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count": len(pairs),
+		"pairs": pairs,
+	})
+}
+
+// handlePrices returns current asks and vig-free implied probabilities for
+// every quoted pair, largest cross-venue divergence first. ?min_divergence
+// keeps pairs whose absolute divergence is at least the given probability.
+func (s *Server) handlePrices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	prices := s.engine.Prices()
+	if v := r.URL.Query().Get("min_divergence"); v != "" {
+		minDivergence, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "invalid min_divergence")
+			return
+		}
+		filtered := prices[:0]
+		for _, p := range prices {
+			if math.Abs(p.Divergence) >= minDivergence {
+				filtered = append(filtered, p)
+			}
+		}
+		prices = filtered
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":  len(prices),
+		"prices": prices,
+	})
+}
+
+// handleInstrument resolves a canonical instrument ID to its market,
+// outcome, current quote and the pairs trading it
+func (s *Server) handleInstrument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	id, err := instrument.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	info, err := s.engine.Instrument(id)
+	if err != nil {
+		writeError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+// evaluateRequest is the body of POST /evaluate
+type evaluateRequest struct {
+	Positions []arb.Position `json:"positions"`
+}
+
+// handleEvaluate prices hypothetical positions, given leg prices or
+// instruments to take live asks from, with the engine's edge and fee model
+func (s *Server) handleEvaluate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req evaluateRequest
+	if !decodeJSON(w, r, 1<<20, &req) {
+		return
+	}
+	evaluations, err := s.engine.EvaluatePositions(req.Positions)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":       len(evaluations),
+		"evaluations": evaluations,
+	})
+}
+
+// handleUnhedgedSignals returns legs offered far below the other venue's
+// implied probability on pairs quoted one-sided. They are directional
+// signals, kept apart from /arbs and its streams.
+func (s *Server) handleUnhedgedSignals(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	signals := s.engine.UnhedgedSignals()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":    len(signals),
+		"min_edge": s.engine.UnhedgedMinEdge(),
+		"signals":  signals,
+	})
+}
+
+// handleAccount returns positions, the hedges among them priced for an
+// unwind, and recent fills/cancels ingested from the exchange streams
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.account == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "account tracking is disabled")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"positions": s.account.Positions(),
+		"hedges":    s.account.Hedges(s.engine.Prices()),
+		"recent":    s.account.Recent(),
+	})
+}
+
+// Status assembles the registered status sections, as served on /status
+func (s *Server) Status() map[string]interface{} {
+	s.statusMu.RLock()
+	status := make(map[string]interface{}, len(s.statusSections)+1)
+	for name, fn := range s.statusSections {
+		status[name] = fn()
+	}
+	s.statusMu.RUnlock()
+	status["time"] = time.Now().UTC()
+	return status
+}
+
+// handleStatus returns the service status assembled from registered sections
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if err := writeJSON(w, http.StatusOK, s.Status()); err != nil {
+		s.logger.Error("failed to encode status", "error", err)
+	}
 }
 
 // writeJSON writes a JSON response
@@ -145,7 +764,66 @@ func writeJSON(w http.ResponseWriter, statusCode int, data interface{}) error {
 	return json.NewEncoder(w).Encode(data)
 }
 
-// writeError writes an error response
-func writeError(w http.ResponseWriter, statusCode int, message string) {
-	writeJSON(w, statusCode, ErrorResponse{Error: message})
+// schemaVersionHeader carries the Opportunity payload version on responses
+// and may be sent by clients to request one
+const schemaVersionHeader = "X-Schema-Version"
+
+// truncatedHeader reports how many opportunities the retention cap left out
+// of /arbs, so consumers can tell a capped view from a complete one
+const truncatedHeader = "X-Opportunities-Truncated"
+
+// negotiateSchemaVersion selects the Opportunity payload version from
+// ?schema_version=N or the X-Schema-Version request header, defaulting to the
+// current version. Requests for an unsupported version are rejected rather
+// than silently served a different shape.
+func negotiateSchemaVersion(r *http.Request) (int, error) {
+	requested := r.URL.Query().Get("schema_version")
+	if requested == "" {
+		requested = r.Header.Get(schemaVersionHeader)
+	}
+	if requested == "" {
+		return arb.OpportunitySchemaVersion, nil
+	}
+
+	version, err := strconv.Atoi(requested)
+	if err == nil {
+		if _, err = arb.OpportunitySchema(version); err == nil {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported schema_version %q, supported: %v", requested, arb.SupportedOpportunitySchemaVersions())
+}
+
+// opportunitiesAsVersion converts opportunities to an earlier payload
+// version, as its clients read them
+func opportunitiesAsVersion(opportunities []arb.Opportunity, version int) []arb.Opportunity {
+	if version == arb.OpportunitySchemaVersion {
+		return opportunities
+	}
+	converted := make([]arb.Opportunity, len(opportunities))
+	for i, o := range opportunities {
+		converted[i] = arb.OpportunityAsVersion(o, version)
+	}
+	return converted
+}
+
+// parseWindow parses a lookback window such as "24h" or "7d", returning def when empty
+func parseWindow(value string, def time.Duration) (time.Duration, error) {
+	if value == "" {
+		return def, nil
+	}
+
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(value)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q", value)
+	}
+	return d, nil
 }
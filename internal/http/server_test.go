@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// newTestServer returns a server over an engine with unstarted clients
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	pm := ws.NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kalshi, err := ws.NewKalshiClientWithKey(context.Background(), "", "test", key, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pm.Close(); kalshi.Close() })
+	return NewServer("", arb.NewEngine(context.Background(), nil, pm, kalshi, 1, logger), logger)
+}
+
+func TestArbsETagPerSchemaVersion(t *testing.T) {
+	s := newTestServer(t)
+
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		s.handleArbs(w, r)
+		return w
+	}
+
+	v2 := get("/arbs", "")
+	etag := v2.Header().Get("ETag")
+	if v2.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status %d, ETag %q", v2.Code, etag)
+	}
+	if !strings.Contains(v2.Header().Get("Vary"), schemaVersionHeader) {
+		t.Errorf("Vary %q does not list %s", v2.Header().Get("Vary"), schemaVersionHeader)
+	}
+
+	if w := get("/arbs", etag); w.Code != http.StatusNotModified {
+		t.Errorf("same version: status %d, want 304", w.Code)
+	} else if !strings.Contains(w.Header().Get("Vary"), schemaVersionHeader) {
+		t.Errorf("304 Vary %q does not list %s", w.Header().Get("Vary"), schemaVersionHeader)
+	}
+
+	v1 := get("/arbs?schema_version=1", etag)
+	if v1.Code != http.StatusOK {
+		t.Fatalf("v1 with a v2 ETag: status %d, want 200", v1.Code)
+	}
+	if v1.Header().Get("ETag") == etag || v1.Header().Get(schemaVersionHeader) != "1" {
+		t.Errorf("v1 response: ETag %q, version %q", v1.Header().Get("ETag"), v1.Header().Get(schemaVersionHeader))
+	}
+}
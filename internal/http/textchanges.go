@@ -0,0 +1,55 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// handleTextChanges lists material edits to paired markets' questions and
+// rules, newest first, with the re-validation of each affected pair
+func (s *Server) handleTextChanges(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.texts == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "text change detection is disabled (set MARKET_STATUS_INTERVAL)")
+		return
+	}
+
+	changes := s.texts.Changes()
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":        len(changes),
+		"text_changes": changes,
+	})
+}
+
+// handleTextChangeAccept records an operator's review of a text change,
+// returning any pairs it suspended to monitoring. When ADMIN_TOKEN is set it
+// is required.
+func (s *Server) handleTextChangeAccept(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.adminToken != "" && !s.validAdminToken(r) {
+		writeError(w, r, http.StatusUnauthorized, "invalid admin token")
+		return
+	}
+	if s.texts == nil {
+		writeError(w, r, http.StatusServiceUnavailable, "text change detection is disabled (set MARKET_STATUS_INTERVAL)")
+		return
+	}
+
+	id := r.PathValue("id")
+	c, err := s.texts.Accept(id, s.adminActor(r), time.Now())
+	if errors.Is(err, arb.ErrTextChangeNotFound) {
+		writeError(w, r, http.StatusNotFound, err.Error())
+		return
+	}
+	s.audit(r, "text_change.accepted", id, nil, c)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"text_change": c})
+}
@@ -0,0 +1,47 @@
+package http
+
+import "net/http"
+
+// thresholdRequest is the body of POST /admin/threshold
+type thresholdRequest struct {
+	EdgeMinRORPct *float64 `json:"edge_min_ror_pct"`
+}
+
+// handleThreshold reports (GET) or changes (POST) the minimum edge of the
+// arbitrage strategy, in percent ROI on turnover. The change applies from the
+// next compute cycle and lasts until restart. Changing it requires
+// ADMIN_TOKEN when it is configured. Both list the per-category overrides
+// set by threshold calibration, which POST leaves in place.
+func (s *Server) handleThreshold(w http.ResponseWriter, r *http.Request) {
+	threshold := s.engine.EdgeThreshold()
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"edge_min_ror_pct": threshold.Pct(), "categories": threshold.Categories()})
+	case http.MethodPost:
+		if s.adminToken != "" && !s.validAdminToken(r) {
+			writeError(w, r, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+
+		var req thresholdRequest
+		if !decodeJSON(w, r, 4096, &req) {
+			return
+		}
+		if req.EdgeMinRORPct == nil {
+			writeError(w, r, http.StatusBadRequest, "edge_min_ror_pct is required")
+			return
+		}
+
+		before := threshold.Pct()
+		if err := threshold.Set(*req.EdgeMinRORPct); err != nil {
+			writeError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.audit(r, "threshold.set", "edge_min_ror_pct", before, *req.EdgeMinRORPct)
+		s.logger.Info("edge threshold changed", "from", before, "to", *req.EdgeMinRORPct, "by", s.adminActor(r))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"edge_min_ror_pct": threshold.Pct(), "categories": threshold.Categories()})
+	default:
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+	"time"
+)
+
+// defaultWarmStartAge is how far back quotes are exported when ?max_age is unset
+const defaultWarmStartAge = 5 * time.Minute
+
+// handleWarmStart exports the engine's pair set, quotes no older than
+// ?max_age and open opportunities, for a peer starting up with
+// WARM_START_URL pointed here. When ADMIN_TOKEN is set it is required.
+func (s *Server) handleWarmStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.adminToken != "" && !s.validAdminToken(r) {
+		writeError(w, r, http.StatusUnauthorized, "invalid admin token")
+		return
+	}
+
+	maxAge, err := parseWindow(r.URL.Query().Get("max_age"), defaultWarmStartAge)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.engine.ExportWarmState(maxAge))
+}
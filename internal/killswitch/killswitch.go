@@ -0,0 +1,221 @@
+// Package killswitch is the global execution kill switch. Operators engage it
+// by config or the admin endpoint; it also trips automatically when realized
+// losses or failed unwinds within a rolling window exceed their thresholds.
+// While engaged, every execution path must refuse to place orders.
+package killswitch
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// DefaultWindow is the default rolling window for automatic trips
+const DefaultWindow = time.Hour
+
+// Sources of an engagement
+const (
+	SourceConfig = "config"
+	SourceManual = "manual"
+	SourceLosses = "losses"
+	SourceUnwind = "failed_unwinds"
+)
+
+// ErrEngaged is returned by Check while the switch is engaged
+var ErrEngaged = errors.New("execution kill switch engaged")
+
+// Thresholds configure automatic tripping; zero values disable a check
+type Thresholds struct {
+	MaxLoss          float64       // Realized losses in USD within Window
+	MaxFailedUnwinds int           // Failed unwinds within Window
+	Window           time.Duration // Defaults to DefaultWindow
+}
+
+// State is a snapshot of the switch for /status and the ops page
+type State struct {
+	Engaged       bool       `json:"engaged"`
+	Source        string     `json:"source,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+	Since         *time.Time `json:"since,omitempty"`
+	WindowLoss    float64    `json:"window_loss"`
+	FailedUnwinds int        `json:"window_failed_unwinds"`
+
+	MaxLoss          float64 `json:"max_loss,omitempty"`
+	MaxFailedUnwinds int     `json:"max_failed_unwinds,omitempty"`
+	Window           string  `json:"window"`
+}
+
+// lossSample is one realized loss inside the rolling window
+type lossSample struct {
+	at     time.Time
+	amount float64 // Positive USD lost
+}
+
+// Switch is the kill switch; it is safe for concurrent use
+type Switch struct {
+	mu         sync.Mutex
+	engaged    bool
+	source     string
+	reason     string
+	since      time.Time
+	thresholds Thresholds
+	losses     []lossSample
+	unwinds    []time.Time
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// New creates a released switch with the given automatic-trip thresholds
+func New(th Thresholds, logger *slog.Logger) *Switch {
+	if th.Window <= 0 {
+		th.Window = DefaultWindow
+	}
+	metrics.SetKillSwitchEngaged(false)
+	return &Switch{thresholds: th, now: time.Now, logger: logger}
+}
+
+// Engage disables execution. Engaging an engaged switch keeps the original
+// source and reason.
+func (s *Switch) Engage(source, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.engageLocked(source, reason)
+}
+
+// engageLocked engages the switch; caller holds mu
+func (s *Switch) engageLocked(source, reason string) {
+	if s.engaged {
+		return
+	}
+	s.engaged = true
+	s.source = source
+	s.reason = reason
+	s.since = s.now()
+
+	metrics.SetKillSwitchEngaged(true)
+	metrics.RecordKillSwitchTrip(source)
+	s.logger.Error("execution kill switch engaged", "source", source, "reason", reason)
+}
+
+// Release re-enables execution and clears the rolling window so the same
+// losses cannot immediately trip the switch again
+func (s *Switch) Release(by string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.engaged {
+		return
+	}
+	s.logger.Warn("execution kill switch released", "by", by, "was_source", s.source, "was_reason", s.reason)
+	s.engaged = false
+	s.source, s.reason = "", ""
+	s.since = time.Time{}
+	s.losses = nil
+	s.unwinds = nil
+	metrics.SetKillSwitchEngaged(false)
+}
+
+// Engaged reports whether execution is disabled
+func (s *Switch) Engaged() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.engaged
+}
+
+// Check returns ErrEngaged while the switch is engaged; execution paths call
+// it immediately before placing every order
+func (s *Switch) Check() error {
+	if s.Engaged() {
+		return ErrEngaged
+	}
+	return nil
+}
+
+// RecordRealizedPnL feeds one realized profit (positive) or loss (negative)
+// and trips the switch when losses within the window exceed MaxLoss
+func (s *Switch) RecordRealizedPnL(pnl float64, at time.Time) {
+	if pnl >= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.losses = append(s.losses, lossSample{at: at, amount: -pnl})
+	s.pruneLocked()
+
+	if s.thresholds.MaxLoss > 0 {
+		if loss := s.windowLossLocked(); loss > s.thresholds.MaxLoss {
+			s.engageLocked(SourceLosses, "realized losses exceeded threshold within "+s.thresholds.Window.String())
+		}
+	}
+}
+
+// RecordFailedUnwind counts an unwind that could not flatten a hung leg and
+// trips the switch when the count within the window exceeds MaxFailedUnwinds
+func (s *Switch) RecordFailedUnwind(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unwinds = append(s.unwinds, at)
+	s.pruneLocked()
+
+	if s.thresholds.MaxFailedUnwinds > 0 && len(s.unwinds) > s.thresholds.MaxFailedUnwinds {
+		s.engageLocked(SourceUnwind, "failed unwinds exceeded threshold within "+s.thresholds.Window.String())
+	}
+}
+
+// State returns a snapshot of the switch
+func (s *Switch) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked()
+	st := State{
+		Engaged:       s.engaged,
+		Source:        s.source,
+		Reason:        s.reason,
+		WindowLoss:    s.windowLossLocked(),
+		FailedUnwinds: len(s.unwinds),
+
+		MaxLoss:          s.thresholds.MaxLoss,
+		MaxFailedUnwinds: s.thresholds.MaxFailedUnwinds,
+		Window:           s.thresholds.Window.String(),
+	}
+	if s.engaged {
+		since := s.since
+		st.Since = &since
+	}
+	return st
+}
+
+// pruneLocked drops samples older than the window; caller holds mu
+func (s *Switch) pruneLocked() {
+	cutoff := s.now().Add(-s.thresholds.Window)
+
+	i := 0
+	for i < len(s.losses) && s.losses[i].at.Before(cutoff) {
+		i++
+	}
+	s.losses = s.losses[i:]
+
+	j := 0
+	for j < len(s.unwinds) && s.unwinds[j].Before(cutoff) {
+		j++
+	}
+	s.unwinds = s.unwinds[j:]
+}
+
+// windowLossLocked sums losses in the window; caller holds mu
+func (s *Switch) windowLossLocked() float64 {
+	total := 0.0
+	for _, l := range s.losses {
+		total += l.amount
+	}
+	return total
+}
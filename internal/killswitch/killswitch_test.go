@@ -0,0 +1,87 @@
+package killswitch
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func newTestSwitch(th Thresholds) (*Switch, *time.Time) {
+	s := New(th, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestManualEngageRelease(t *testing.T) {
+	s, _ := newTestSwitch(Thresholds{})
+
+	if err := s.Check(); err != nil {
+		t.Fatalf("new switch should be released, got %v", err)
+	}
+	s.Engage(SourceManual, "incident")
+	if err := s.Check(); !errors.Is(err, ErrEngaged) {
+		t.Errorf("expected ErrEngaged, got %v", err)
+	}
+
+	s.Engage(SourceConfig, "second")
+	if st := s.State(); st.Source != SourceManual || st.Reason != "incident" || st.Since == nil {
+		t.Errorf("re-engaging must keep the original cause, got %+v", st)
+	}
+
+	s.Release("test")
+	if s.Engaged() {
+		t.Error("switch should be released")
+	}
+}
+
+func TestTripsOnLosses(t *testing.T) {
+	s, now := newTestSwitch(Thresholds{MaxLoss: 100, Window: time.Hour})
+
+	s.RecordRealizedPnL(-60, *now)
+	s.RecordRealizedPnL(+500, *now) // Profits don't offset the loss breaker
+	if s.Engaged() {
+		t.Fatal("60 lost should not trip a 100 threshold")
+	}
+
+	// Losses outside the window no longer count
+	*now = now.Add(2 * time.Hour)
+	s.RecordRealizedPnL(-60, *now)
+	if s.Engaged() {
+		t.Fatal("expired losses must not count toward the threshold")
+	}
+
+	s.RecordRealizedPnL(-50, *now)
+	st := s.State()
+	if !st.Engaged || st.Source != SourceLosses {
+		t.Errorf("110 lost within the window should trip, got %+v", st)
+	}
+
+	s.Release("test")
+	if st := s.State(); st.WindowLoss != 0 {
+		t.Errorf("release should clear the window, got loss %.2f", st.WindowLoss)
+	}
+}
+
+func TestTripsOnFailedUnwinds(t *testing.T) {
+	s, now := newTestSwitch(Thresholds{MaxFailedUnwinds: 2})
+
+	s.RecordFailedUnwind(*now)
+	s.RecordFailedUnwind(*now)
+	if s.Engaged() {
+		t.Fatal("2 failed unwinds should not trip a threshold of 2")
+	}
+	s.RecordFailedUnwind(*now)
+	if st := s.State(); !st.Engaged || st.Source != SourceUnwind {
+		t.Errorf("3 failed unwinds should trip, got %+v", st)
+	}
+}
+
+func TestNilSwitchAllows(t *testing.T) {
+	var s *Switch
+	if err := s.Check(); err != nil {
+		t.Errorf("nil switch should allow execution, got %v", err)
+	}
+}
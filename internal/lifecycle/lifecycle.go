@@ -0,0 +1,173 @@
+// Package lifecycle is a registry of startup and shutdown hooks. Subsystems
+// register what they need to run when bootstrap completes, when pairs are
+// added later and at shutdown, so adding one doesn't mean threading it
+// through main by hand. External plugins take part through the plugin
+// package's lifecycle capability.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// Lifecycle events, as reported to plugins and in logs
+const (
+	EventBootstrapComplete = "bootstrap_complete"
+	EventPairsRefreshed    = "pairs_refreshed"
+	EventShutdown          = "shutdown"
+)
+
+// PairsHook is run with the monitored pairs when bootstrap completes, or
+// with the newly added pairs when they are refreshed
+type PairsHook func(ctx context.Context, pairs []arb.MarketPair) error
+
+// ShutdownHook is run at shutdown; it should return by ctx's deadline
+type ShutdownHook func(ctx context.Context) error
+
+type named[T any] struct {
+	name string
+	fn   T
+}
+
+// Hooks holds the registered hooks and background subsystems
+type Hooks struct {
+	mu        sync.Mutex
+	bootstrap []named[PairsHook]
+	refreshed []named[PairsHook]
+	shutdown  []named[ShutdownHook]
+	workers   []named[func(ctx context.Context)]
+	ctx       context.Context // Subsystems' context, set when bootstrap completes
+	cancel    context.CancelFunc
+	running   sync.WaitGroup
+	logger    *slog.Logger
+}
+
+// New creates an empty registry
+func New(logger *slog.Logger) *Hooks {
+	return &Hooks{logger: logger}
+}
+
+// OnBootstrapComplete registers a hook run once the initial pair set is
+// monitored. An error aborts startup.
+func (h *Hooks) OnBootstrapComplete(name string, fn PairsHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.bootstrap = append(h.bootstrap, named[PairsHook]{name, fn})
+}
+
+// OnPairsRefreshed registers a hook run with each batch of pairs added after
+// bootstrap. Errors are logged.
+func (h *Hooks) OnPairsRefreshed(name string, fn PairsHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.refreshed = append(h.refreshed, named[PairsHook]{name, fn})
+}
+
+// OnShutdown registers a hook run at shutdown. Hooks run in reverse
+// registration order, so later subsystems stop before those they depend on.
+func (h *Hooks) OnShutdown(name string, fn ShutdownHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.shutdown = append(h.shutdown, named[ShutdownHook]{name, fn})
+}
+
+// Go registers a background subsystem. It is started on its own goroutine
+// when bootstrap completes, or at once if it already has, and must return
+// when its context is cancelled, which Shutdown does after the shutdown
+// hooks have run.
+func (h *Hooks) Go(name string, fn func(ctx context.Context)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	w := named[func(ctx context.Context)]{name, fn}
+	h.workers = append(h.workers, w)
+	if h.ctx != nil {
+		h.start(h.ctx, w)
+	}
+}
+
+// start runs a background subsystem on its own goroutine
+func (h *Hooks) start(ctx context.Context, w named[func(ctx context.Context)]) {
+	h.running.Add(1)
+	go func() {
+		defer h.running.Done()
+		w.fn(ctx)
+		h.logger.Debug("subsystem stopped", "subsystem", w.name)
+	}()
+}
+
+// BootstrapComplete runs the bootstrap hooks in registration order, then
+// starts the background subsystems with a context derived from ctx. It stops
+// at the first failing hook.
+func (h *Hooks) BootstrapComplete(ctx context.Context, pairs []arb.MarketPair) error {
+	h.mu.Lock()
+	hooks := append([]named[PairsHook](nil), h.bootstrap...)
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook.fn(ctx, pairs); err != nil {
+			return fmt.Errorf("%s hook %s: %w", EventBootstrapComplete, hook.name, err)
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ctx, h.cancel = context.WithCancel(ctx)
+	for _, w := range h.workers {
+		h.start(h.ctx, w)
+	}
+	h.logger.Info("lifecycle hooks run", "event", EventBootstrapComplete, "hooks", len(hooks), "subsystems", len(h.workers))
+	return nil
+}
+
+// PairsRefreshed runs the refresh hooks with newly added pairs
+func (h *Hooks) PairsRefreshed(ctx context.Context, added []arb.MarketPair) {
+	h.mu.Lock()
+	hooks := append([]named[PairsHook](nil), h.refreshed...)
+	h.mu.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook.fn(ctx, added); err != nil {
+			h.logger.Warn("lifecycle hook failed", "event", EventPairsRefreshed, "hook", hook.name, "error", err)
+		}
+	}
+}
+
+// Shutdown runs the shutdown hooks in reverse registration order, then
+// stops the background subsystems and waits for them until ctx is done.
+// Every hook runs; their errors are joined.
+func (h *Hooks) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	hooks := append([]named[ShutdownHook](nil), h.shutdown...)
+	cancel := h.cancel
+	h.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		start := time.Now()
+		if err := hooks[i].fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s hook %s: %w", EventShutdown, hooks[i].name, err))
+		}
+		h.logger.Debug("shutdown hook done", "hook", hooks[i].name, "took", time.Since(start))
+	}
+	if cancel != nil {
+		cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("waiting for subsystems: %w", ctx.Err()))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+func TestHooksRunInOrder(t *testing.T) {
+	h := New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	var calls []string
+	record := func(name string) PairsHook {
+		return func(ctx context.Context, pairs []arb.MarketPair) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	h.OnBootstrapComplete("a", record("boot-a"))
+	h.OnBootstrapComplete("b", record("boot-b"))
+	h.OnPairsRefreshed("a", record("refresh-a"))
+	h.OnPairsRefreshed("failing", func(context.Context, []arb.MarketPair) error { return errors.New("boom") })
+	h.OnPairsRefreshed("b", record("refresh-b"))
+	h.OnShutdown("first", func(context.Context) error { calls = append(calls, "stop-first"); return nil })
+	h.OnShutdown("second", func(context.Context) error { calls = append(calls, "stop-second"); return errors.New("stuck") })
+
+	ctx := context.Background()
+	stopped := make(chan struct{})
+	h.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	if err := h.BootstrapComplete(ctx, nil); err != nil {
+		t.Fatalf("BootstrapComplete: %v", err)
+	}
+	h.PairsRefreshed(ctx, nil) // A failing hook doesn't stop the rest
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Second)
+	defer shutdownCancel()
+	err := h.Shutdown(shutdownCtx)
+	if err == nil || !strings.Contains(err.Error(), "second") {
+		t.Errorf("Shutdown error = %v, want the failing hook's", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("Shutdown returned before the subsystem stopped")
+	}
+
+	want := "boot-a boot-b refresh-a refresh-b stop-second stop-first"
+	if got := strings.Join(calls, " "); got != want {
+		t.Errorf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestBootstrapHookErrorAbortsStartup(t *testing.T) {
+	h := New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	h.OnBootstrapComplete("bad", func(context.Context, []arb.MarketPair) error { return errors.New("no") })
+	started := false
+	h.Go("worker", func(context.Context) { started = true })
+
+	if err := h.BootstrapComplete(context.Background(), nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if started {
+		t.Error("subsystem started despite the failed hook")
+	}
+}
+
+func TestGoAfterBootstrapStartsImmediately(t *testing.T) {
+	h := New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := h.BootstrapComplete(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ran := make(chan struct{})
+	h.Go("late", func(context.Context) { close(ran) })
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("late subsystem not started")
+	}
+	if err := h.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+}
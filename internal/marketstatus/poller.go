@@ -0,0 +1,365 @@
+// Package marketstatus polls both venues for trading-status changes (halts,
+// pauses, closures) on monitored markets and records them in an
+// arb.HaltRegistry so the engine stops computing edges on frozen prices. The
+// same responses feed an optional arb.TextWatch, which catches venues
+// rewording questions or amending rules after listing, an optional
+// arb.LiquidityBook of each market's volume, open interest and liquidity,
+// an optional arb.ResolutionBook of resolved markets' outcomes, and an
+// optional arb.PMRulesCache of Polymarket markets' order constraints.
+package marketstatus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/budget"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// DefaultInterval is the default polling interval
+const DefaultInterval = 30 * time.Second
+
+// kalshiTickerBatch is the number of tickers requested per Kalshi markets call
+const kalshiTickerBatch = 100
+
+// gammaBatch is the number of condition IDs requested per Gamma markets call
+const gammaBatch = 50
+
+// requestTimeout bounds a single status request
+const requestTimeout = 15 * time.Second
+
+// Poller periodically refreshes market statuses for the monitored pairs
+type Poller struct {
+	pmRESTURL     string
+	kalshiRESTURL string
+	mu            sync.Mutex
+	conditionIDs  []string
+	tickers       []string
+	seenPM        map[string]struct{}
+	seenKalshi    map[string]struct{}
+	registry      *arb.HaltRegistry
+	texts         *arb.TextWatch
+	liquidity     *arb.LiquidityBook
+	resolutions   *arb.ResolutionBook
+	pmRules       *arb.PMRulesCache
+	gammaURL      string // Polymarket Gamma API, for market activity
+	interval      time.Duration
+	pmClient      *http.Client
+	kalshiClient  *http.Client
+	budget        *budget.Accountant
+	logger        *slog.Logger
+}
+
+// NewPoller creates a poller covering the markets of the given pairs
+func NewPoller(pmRESTURL, kalshiRESTURL string, pairs []arb.MarketPair, registry *arb.HaltRegistry, interval time.Duration, logger *slog.Logger) *Poller {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	p := &Poller{
+		pmRESTURL:     strings.TrimRight(pmRESTURL, "/"),
+		kalshiRESTURL: strings.TrimRight(kalshiRESTURL, "/"),
+		registry:      registry,
+		interval:      interval,
+		pmClient:      &http.Client{Timeout: requestTimeout, Transport: chaos.Transport(http.DefaultTransport)},
+		kalshiClient:  &http.Client{Timeout: requestTimeout, Transport: chaos.Transport(http.DefaultTransport)},
+		seenPM:        make(map[string]struct{}),
+		seenKalshi:    make(map[string]struct{}),
+		logger:        logger,
+	}
+	p.AddPairs(pairs)
+	return p
+}
+
+// AddPairs extends polling to the markets of additional pairs. Safe to call
+// while the poller is running.
+func (p *Poller) AddPairs(pairs []arb.MarketPair) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, pair := range pairs {
+		if _, ok := p.seenPM[pair.PMConditionID]; !ok && pair.PMConditionID != "" {
+			p.seenPM[pair.PMConditionID] = struct{}{}
+			p.conditionIDs = append(p.conditionIDs, pair.PMConditionID)
+		}
+		if _, ok := p.seenKalshi[pair.KalshiTicker]; !ok && pair.KalshiTicker != "" {
+			p.seenKalshi[pair.KalshiTicker] = struct{}{}
+			p.tickers = append(p.tickers, pair.KalshiTicker)
+		}
+	}
+}
+
+// markets returns the monitored condition IDs and tickers
+func (p *Poller) markets() (conditionIDs, tickers []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.conditionIDs, p.tickers
+}
+
+// SetBudget meters the poller's requests and skips a venue's poll while its
+// API usage is near budget; status polling is non-critical work
+func (p *Poller) SetBudget(a *budget.Accountant) {
+	p.budget = a
+	p.pmClient = a.Client(arb.VenuePolymarket, requestTimeout)
+	p.kalshiClient = a.Client(arb.VenueKalshi, requestTimeout)
+}
+
+// SetTextWatch passes each polled market's title and rules to w. Call before Run.
+func (p *Poller) SetTextWatch(w *arb.TextWatch) {
+	p.texts = w
+}
+
+// SetLiquidity records each polled market's activity in book. Kalshi reports
+// it with the market status; Polymarket's comes from the Gamma API at
+// gammaURL, skipped when empty. Call before Run.
+func (p *Poller) SetLiquidity(book *arb.LiquidityBook, gammaURL string) {
+	p.liquidity = book
+	p.gammaURL = strings.TrimRight(gammaURL, "/")
+}
+
+// SetResolutions records the outcomes of polled markets that have resolved
+// in book. Call before Run.
+func (p *Poller) SetResolutions(book *arb.ResolutionBook) {
+	p.resolutions = book
+}
+
+// SetPMRules refreshes each polled Polymarket market's tick size, minimum
+// order size and neg-risk flag in cache. Call before Run.
+func (p *Poller) SetPMRules(cache *arb.PMRulesCache) {
+	p.pmRules = cache
+}
+
+// SampleLiquidity refreshes the status and activity of the given pairs'
+// markets at once, e.g. when pair refresh adds them, instead of waiting for
+// the next poll
+func (p *Poller) SampleLiquidity(ctx context.Context, pairs []arb.MarketPair) error {
+	conditionIDs := make([]string, 0, len(pairs))
+	tickers := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair.PMConditionID != "" {
+			conditionIDs = append(conditionIDs, pair.PMConditionID)
+		}
+		if pair.KalshiTicker != "" {
+			tickers = append(tickers, pair.KalshiTicker)
+		}
+	}
+	return errors.Join(p.pollKalshi(ctx, tickers), p.sampleGamma(ctx, conditionIDs))
+}
+
+// Run polls until ctx is done, starting immediately
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll refreshes both venues once
+func (p *Poller) poll(ctx context.Context) {
+	conditionIDs, tickers := p.markets()
+	if !p.budget.Allow(arb.VenueKalshi, budget.NonCritical) {
+		p.logger.Info("kalshi market status poll skipped, api budget nearly exhausted")
+	} else if err := p.pollKalshi(ctx, tickers); err != nil {
+		p.logger.Warn("kalshi market status poll failed", "error", err)
+	}
+	if !p.budget.Allow(arb.VenuePolymarket, budget.NonCritical) {
+		p.logger.Info("polymarket market status poll skipped, api budget nearly exhausted")
+	} else {
+		if err := p.pollPolymarket(ctx, conditionIDs); err != nil {
+			p.logger.Warn("polymarket market status poll failed", "error", err)
+		}
+		if err := p.sampleGamma(ctx, conditionIDs); err != nil {
+			p.logger.Warn("polymarket market activity poll failed", "error", err)
+		}
+	}
+
+	metrics.SetHaltedMarkets(arb.VenueKalshi, p.registry.Count(arb.VenueKalshi))
+	metrics.SetHaltedMarkets(arb.VenuePolymarket, p.registry.Count(arb.VenuePolymarket))
+}
+
+// pollKalshi fetches Kalshi markets in ticker batches
+func (p *Poller) pollKalshi(ctx context.Context, tickers []string) error {
+	for start := 0; start < len(tickers); start += kalshiTickerBatch {
+		end := start + kalshiTickerBatch
+		if end > len(tickers) {
+			end = len(tickers)
+		}
+		batch := tickers[start:end]
+
+		query := url.Values{}
+		query.Set("tickers", strings.Join(batch, ","))
+		query.Set("limit", fmt.Sprint(len(batch)))
+
+		var resp struct {
+			Markets []ws.KalshiMarket `json:"markets"`
+		}
+		if err := p.getJSON(ctx, p.kalshiClient, p.kalshiRESTURL+"/markets?"+query.Encode(), &resp); err != nil {
+			return err
+		}
+
+		wanted := make(map[string]struct{}, len(batch))
+		for _, t := range batch {
+			wanted[t] = struct{}{}
+		}
+		for _, m := range resp.Markets {
+			if _, ok := wanted[m.Ticker]; !ok {
+				continue
+			}
+			p.record(arb.VenueKalshi, m.Ticker, m.Status, KalshiHalted(m.Status))
+			p.texts.Observe(arb.VenueKalshi, m.Ticker, m.Title, m.Rules(), time.Now())
+			if p.liquidity != nil {
+				p.liquidity.SetKalshi(m.Ticker, m.Volume24h, m.OpenInterest, price.FromCents(m.Liquidity), time.Now())
+			}
+			if result := strings.ToLower(m.Result); result == "yes" || result == "no" {
+				p.resolutions.SetKalshi(m.Ticker, result == "yes")
+			}
+		}
+	}
+	return nil
+}
+
+// pollPolymarket fetches each Polymarket market
+func (p *Poller) pollPolymarket(ctx context.Context, conditionIDs []string) error {
+	var firstErr error
+	for _, id := range conditionIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var m ws.PolymarketMarket
+		if err := p.getJSON(ctx, p.pmClient, p.pmRESTURL+"/markets/"+url.PathEscape(id), &m); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		status, halted := PolymarketStatus(m)
+		p.record(arb.VenuePolymarket, id, status, halted)
+		p.texts.Observe(arb.VenuePolymarket, id, m.Question, m.Description, time.Now())
+		if p.pmRules != nil {
+			rules := arb.PMMarketRules{TickSize: m.MinimumTickSize, MinOrderSize: m.MinimumOrderSize, NegRisk: m.NegRisk}
+			if p.pmRules.Set(id, rules) {
+				p.logger.Info("polymarket order constraints changed", "market", id, "tick_size", rules.TickSize, "min_order_size", rules.MinOrderSize, "neg_risk", rules.NegRisk)
+			}
+		}
+		if m.Closed {
+			for _, t := range m.Tokens {
+				if t.Winner {
+					p.resolutions.SetPolymarket(id, t.TokenID)
+				}
+			}
+		}
+	}
+	return firstErr
+}
+
+// sampleGamma fetches Polymarket markets' activity from the Gamma API in
+// condition ID batches
+func (p *Poller) sampleGamma(ctx context.Context, conditionIDs []string) error {
+	if p.liquidity == nil || p.gammaURL == "" {
+		return nil
+	}
+	for start := 0; start < len(conditionIDs); start += gammaBatch {
+		end := start + gammaBatch
+		if end > len(conditionIDs) {
+			end = len(conditionIDs)
+		}
+		batch := conditionIDs[start:end]
+
+		query := url.Values{"condition_ids": batch}
+		query.Set("limit", fmt.Sprint(len(batch)))
+
+		var stats []ws.PolymarketMarketStats
+		if err := p.getJSON(ctx, p.pmClient, p.gammaURL+"/markets?"+query.Encode(), &stats); err != nil {
+			return err
+		}
+		wanted := make(map[string]struct{}, len(batch))
+		for _, id := range batch {
+			wanted[id] = struct{}{}
+		}
+		for _, m := range stats {
+			if _, ok := wanted[m.ConditionID]; !ok {
+				continue
+			}
+			p.liquidity.SetPolymarket(m.ConditionID, m.Volume24h, m.Liquidity, time.Now())
+		}
+	}
+	return nil
+}
+
+// record updates the registry and logs transitions
+func (p *Poller) record(venue, market, status string, halted bool) {
+	if !p.registry.Update(venue, market, status, halted, time.Now()) {
+		return
+	}
+	if halted {
+		p.logger.Warn("market halted, excluding from opportunities", "venue", venue, "market", market, "status", status)
+	} else {
+		p.logger.Info("market trading resumed", "venue", venue, "market", market, "status", status)
+	}
+}
+
+// getJSON performs a GET and decodes the JSON response into out
+func (p *Poller) getJSON(ctx context.Context, client *http.Client, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, req.URL.Path)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	return nil
+}
+
+// KalshiHalted reports whether a Kalshi market status means trading is stopped
+func KalshiHalted(status string) bool {
+	switch strings.ToLower(status) {
+	case "open", "active", "":
+		return false
+	default: // paused, closed, settled, determined, unopened, ...
+		return true
+	}
+}
+
+// PolymarketStatus summarizes a Polymarket market's trading status
+func PolymarketStatus(m ws.PolymarketMarket) (status string, halted bool) {
+	switch {
+	case m.Closed:
+		return "closed", true
+	case !m.Active:
+		return "inactive", true
+	case !m.AcceptingOrders:
+		return "not_accepting_orders", true
+	default:
+		return "active", false
+	}
+}
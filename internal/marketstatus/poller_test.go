@@ -0,0 +1,141 @@
+package marketstatus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+func TestPollRecordsHalts(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/kalshi/markets", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("tickers") != "K1,K2" {
+			t.Errorf("unexpected tickers query %q", r.URL.Query().Get("tickers"))
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"markets": []map[string]interface{}{
+			{"ticker": "K1", "status": "open", "volume_24h": 1200, "open_interest": 5000, "liquidity": 250000},
+			{"ticker": "K2", "status": "paused"},
+			{"ticker": "OTHER", "status": "closed"},
+		}})
+	})
+	mux.HandleFunc("/pm/markets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		accepting := r.PathValue("id") != "c1"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"condition_id": r.PathValue("id"), "active": true, "closed": false, "accepting_orders": accepting,
+		})
+	})
+	mux.HandleFunc("/gamma/markets", func(w http.ResponseWriter, r *http.Request) {
+		if ids := r.URL.Query()["condition_ids"]; len(ids) != 2 {
+			t.Errorf("unexpected condition_ids %v", ids)
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"conditionId": "c1", "volume24hr": 8000.5, "liquidityNum": 1500},
+			{"conditionId": "other", "volume24hr": 1},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	pairs := []arb.MarketPair{
+		{PMConditionID: "c1", KalshiTicker: "K1"},
+		{PMConditionID: "c2", KalshiTicker: "K2"},
+		{PMConditionID: "c2", KalshiTicker: "K2"}, // Duplicate markets are polled once
+	}
+	registry := arb.NewHaltRegistry()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	p := NewPoller(srv.URL+"/pm", srv.URL+"/kalshi", pairs, registry, 0, logger)
+	liquidity := arb.NewLiquidityBook()
+	p.SetLiquidity(liquidity, srv.URL+"/gamma/")
+
+	p.poll(context.Background())
+
+	if _, ok := registry.Halted(arb.VenueKalshi, "K1"); ok {
+		t.Error("K1 is open and should not be halted")
+	}
+	if h, ok := registry.Halted(arb.VenueKalshi, "K2"); !ok || h.Status != "paused" {
+		t.Errorf("K2 should be halted as paused, got %+v, %v", h, ok)
+	}
+	if _, ok := registry.Halted(arb.VenueKalshi, "OTHER"); ok {
+		t.Error("unmonitored markets must be ignored")
+	}
+	if h, ok := registry.Halted(arb.VenuePolymarket, "c1"); !ok || h.Status != "not_accepting_orders" {
+		t.Errorf("c1 should be halted, got %+v, %v", h, ok)
+	}
+	if _, ok := registry.Halted(arb.VenuePolymarket, "c2"); ok {
+		t.Error("c2 accepts orders and should not be halted")
+	}
+
+	s, ok := liquidity.ForPair(pairs[0])
+	if !ok || s.PMVolume24h != 8000.5 || s.PMLiquidity != 1500 || s.KalshiVolume24h != 1200 || s.KalshiOpenInterest != 5000 || s.KalshiLiquidity != 2500 {
+		t.Errorf("unexpected liquidity snapshot %+v", s)
+	}
+	if _, ok := liquidity.ForPair(arb.MarketPair{PMConditionID: "other"}); ok {
+		t.Error("unmonitored markets must not be sampled")
+	}
+}
+
+func TestPollRecordsResolutions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/kalshi/markets", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"markets": []map[string]interface{}{
+			{"ticker": "K1", "status": "finalized", "result": "yes"},
+		}})
+	})
+	mux.HandleFunc("/pm/markets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"condition_id": r.PathValue("id"), "active": true, "closed": true,
+			"tokens": []map[string]interface{}{
+				{"token_id": "y1", "outcome": "Yes", "price": "1", "winner": true},
+				{"token_id": "n1", "outcome": "No", "price": "0", "winner": false},
+			},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	p := NewPoller(srv.URL+"/pm", srv.URL+"/kalshi", []arb.MarketPair{{PMConditionID: "c1", KalshiTicker: "K1"}}, arb.NewHaltRegistry(), 0, logger)
+	book := arb.NewResolutionBook()
+	p.SetResolutions(book)
+
+	p.poll(context.Background())
+
+	pmYesWon, kalshiYesWon, ok := book.Outcome("c1", "y1", "K1")
+	if !ok || !pmYesWon || !kalshiYesWon {
+		t.Errorf("Outcome = %v, %v, %v; want both YES", pmYesWon, kalshiYesWon, ok)
+	}
+}
+
+func TestPollRefreshesPMRules(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/kalshi/markets", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"markets": []map[string]interface{}{}})
+	})
+	mux.HandleFunc("/pm/markets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"condition_id": r.PathValue("id"), "active": true, "accepting_orders": true,
+			"minimum_tick_size": 0.001, "minimum_order_size": 5, "neg_risk": true,
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	pairs := []arb.MarketPair{{PMConditionID: "c1", KalshiTicker: "K1"}}
+	p := NewPoller(srv.URL+"/pm", srv.URL+"/kalshi", pairs, arb.NewHaltRegistry(), 0, logger)
+	cache := arb.NewPMRulesCache(pairs)
+	p.SetPMRules(cache)
+
+	p.poll(context.Background())
+
+	want := arb.PMMarketRules{TickSize: 0.001, MinOrderSize: 5, NegRisk: true}
+	if got, ok := cache.Get("c1"); !ok || got != want {
+		t.Errorf("Get = %+v, %v; want %+v", got, ok, want)
+	}
+}
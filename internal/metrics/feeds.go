@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// FeedCounts are a feed's cumulative counts since start, kept in process
+// alongside the Prometheus counters for subsystems acting on them, such as
+// data-quality scoring
+type FeedCounts struct {
+	Frames        uint64    // Raw frames received
+	ParseFailures uint64    // Frames that failed to decode
+	Invalid       uint64    // Quote updates refused as out of range or crossed
+	Reconnects    uint64    // Reconnection attempts
+	LastUpdate    time.Time // Latest applied price update, zero before the first
+}
+
+var (
+	feedMu     sync.Mutex
+	feedCounts = make(map[string]*FeedCounts) // source -> counts
+)
+
+// countFeed applies fn to a source's counts
+func countFeed(source string, fn func(*FeedCounts)) {
+	feedMu.Lock()
+	c, ok := feedCounts[source]
+	if !ok {
+		c = &FeedCounts{}
+		feedCounts[source] = c
+	}
+	fn(c)
+	feedMu.Unlock()
+}
+
+// Feeds returns a copy of every source's counts
+func Feeds() map[string]FeedCounts {
+	feedMu.Lock()
+	defer feedMu.Unlock()
+
+	out := make(map[string]FeedCounts, len(feedCounts))
+	for source, c := range feedCounts {
+		out[source] = *c
+	}
+	return out
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
@@ -24,18 +26,132 @@ var (
 		Help: "Total number of arbitrage opportunities found",
 	})
 
+	// OpportunitiesFilteredTotal tracks opportunities rejected by the filter chain
+	OpportunitiesFilteredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_opps_filtered_total",
+		Help: "Total number of detected opportunities rejected by a filter",
+	}, []string{"filter"})
+
+	// StrategyOpportunities tracks opportunities each strategy found in the last cycle
+	StrategyOpportunities = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_strategy_opportunities",
+		Help: "Opportunities found by each engine strategy in the last compute cycle, before filtering",
+	}, []string{"strategy"})
+
+	// StrategyEvaluationDuration tracks how long each strategy takes per cycle
+	StrategyEvaluationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "arb_strategy_evaluation_seconds",
+		Help:    "Duration of one engine strategy's evaluation of all pairs",
+		Buckets: []float64{.0001, .0005, .001, .005, .01, .05, .1, .5},
+	}, []string{"strategy"})
+
+	// ShadowPnL tracks each strategy's virtual PnL in the shadow ledgers
+	ShadowPnL = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_shadow_pnl_usd",
+		Help: "Virtual PnL of each strategy's shadow ledger, by kind (realized, unrealized)",
+	}, []string{"strategy", "kind"})
+
 	// HTTPRequestsTotal tracks HTTP requests by path and status code
 	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "http_requests_total",
 		Help: "Total number of HTTP requests",
 	}, []string{"path", "code"})
 
+	// HTTPRequestDuration tracks handler latency by route pattern; streams
+	// are counted by HTTPOpenStreams instead
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by route pattern, streams excluded",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"route"})
+
+	// HTTPOpenStreams tracks streaming responses in progress by route pattern
+	HTTPOpenStreams = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_open_streams",
+		Help: "Streaming HTTP responses in progress by route pattern",
+	}, []string{"route"})
+
 	// WSConnectionStatus tracks current WebSocket connection status
 	WSConnectionStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "arb_ws_connection_status",
 		Help: "WebSocket connection status (1 = connected, 0 = disconnected)",
 	}, []string{"source"})
 
+	// WSFlapping flags connections reconnecting too often, see ws.FlapPolicy
+	WSFlapping = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_ws_flapping",
+		Help: "WebSocket connection flapping (1 = reconnecting too often)",
+	}, []string{"source"})
+
+	// WSFramesReceivedTotal tracks raw WebSocket frames read, before parsing
+	WSFramesReceivedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_ws_frames_received_total",
+		Help: "Total number of raw WebSocket frames received",
+	}, []string{"source"})
+
+	// WSFramesParsedTotal tracks frames decoded successfully by event type
+	WSFramesParsedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_ws_frames_parsed_total",
+		Help: "Total number of WebSocket frames parsed successfully, by event type (batched frames count once per event)",
+	}, []string{"source", "event_type"})
+
+	// WSFrameParseFailuresTotal tracks frames that could not be decoded
+	WSFrameParseFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_ws_frame_parse_failures_total",
+		Help: "Total number of WebSocket frames that failed to parse",
+	}, []string{"source"})
+
+	// WSFramesRejectedTotal tracks decoded frames whose quote was refused:
+	// "invalid" prices outside 0-1 or crossed, "out_of_order" older than the
+	// instrument's last update by the exchange's timestamp
+	WSFramesRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_ws_frames_rejected_total",
+		Help: "Total number of decoded quote updates refused, by reason",
+	}, []string{"source", "reason"})
+
+	// JSONFieldsToleratedTotal tracks malformed numeric fields decoded as 0
+	// instead of failing the whole object
+	JSONFieldsToleratedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_json_fields_tolerated_total",
+		Help: "Total number of numeric JSON fields in an unexpected form, by field and result (coerced: empty, null or unquoted; failed: not a number, decoded as 0)",
+	}, []string{"field", "result"})
+
+	// FrozenInstruments tracks instruments whose feed is anomalously quiet
+	FrozenInstruments = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_frozen_instruments",
+		Help: "Number of instruments quiet for far longer than their learned update interval",
+	}, []string{"source"})
+
+	// FeedQuality tracks each feed's rolling data-quality measures and score
+	FeedQuality = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_feed_quality",
+		Help: "Rolling data quality of each feed by measure (parse_failure_rate, crossed_rate, staleness_seconds, reconnects_per_hour, score, weight)",
+	}, []string{"source", "measure"})
+
+	// FeedFreezesTotal tracks instrument freeze and resume transitions
+	FeedFreezesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_feed_freezes_total",
+		Help: "Total number of instrument feed freeze transitions by event (frozen, resumed)",
+	}, []string{"source", "event"})
+
+	// MaintenanceActive tracks whether a venue's scheduled maintenance window is in progress
+	MaintenanceActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_maintenance_active",
+		Help: "Scheduled maintenance window in progress (1 = active, 0 = inactive)",
+	}, []string{"source"})
+
+	// HaltedMarkets tracks monitored markets currently halted by their venue
+	HaltedMarkets = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_halted_markets",
+		Help: "Number of monitored markets currently halted, paused or closed by the venue",
+	}, []string{"source"})
+
+	// MarketTextChangesTotal counts material edits to paired markets' questions and rules
+	MarketTextChangesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_market_text_changes_total",
+		Help: "Total number of material edits detected to a paired market's question or rules",
+	}, []string{"source", "field"})
+
 	// PriceUpdatesTotal tracks total price updates received
 	PriceUpdatesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "arb_price_updates_total",
@@ -48,16 +164,145 @@ var (
 		Help: "Current number of active arbitrage opportunities",
 	})
 
+	// NewsHeldPairsGauge tracks pairs held back after a sharp price move
+	NewsHeldPairsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_news_held_pairs",
+		Help: "Number of pairs excluded from opportunities after a sharp price move",
+	})
+
+	// BestEdgeJumpsTotal counts sudden rises in the best edge between cycles
+	BestEdgeJumpsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arb_best_edge_jumps_total",
+		Help: "Total number of sudden rises in the best edge within one compute cycle",
+	})
+
+	// TruncatedOpportunitiesGauge tracks opportunities left out of the capped list
+	TruncatedOpportunitiesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_truncated_opportunities",
+		Help: "Opportunities found beyond the retention cap and left out of the published list",
+	})
+
 	// BestEdgeGauge tracks the best current edge percentage
 	BestEdgeGauge = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "arb_best_edge_pct",
 		Help: "Best current arbitrage edge percentage",
 	})
+
+	// NotificationsTotal tracks notification deliveries by sender and result
+	NotificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_notifications_total",
+		Help: "Total number of notification deliveries by result (sent, failed, dropped, suppressed)",
+	}, []string{"sender", "result"})
+
+	// NotificationLatency tracks the duration of individual delivery attempts
+	NotificationLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "arb_notification_duration_seconds",
+		Help:    "Duration of notification delivery attempts",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"sender"})
+
+	// APICallsTotal tracks REST calls made to each exchange
+	APICallsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_api_calls_total",
+		Help: "Total number of REST calls made to an exchange",
+	}, []string{"source"})
+
+	// APISubscriptions tracks current WebSocket subscriptions per exchange
+	APISubscriptions = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_api_subscriptions",
+		Help: "Current number of WebSocket subscriptions per exchange",
+	}, []string{"source"})
+
+	// APIBudgetUsed tracks usage as a fraction of the configured budget
+	APIBudgetUsed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "arb_api_budget_used_ratio",
+		Help: "API usage as a fraction of the exchange budget (0 when unlimited)",
+	}, []string{"source", "kind"})
+
+	// APIThrottledTotal tracks non-critical work skipped to stay within budget
+	APIThrottledTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_api_throttled_total",
+		Help: "Total number of times non-critical work was skipped to stay within an API budget",
+	}, []string{"source"})
+
+	// KillSwitchEngaged tracks whether the execution kill switch is engaged
+	KillSwitchEngaged = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_kill_switch_engaged",
+		Help: "Execution kill switch state (1 = engaged, execution disabled)",
+	})
+
+	// KillSwitchTripsTotal tracks kill switch engagements by source
+	KillSwitchTripsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_kill_switch_trips_total",
+		Help: "Total number of kill switch engagements by source (config, manual, losses, failed_unwinds)",
+	}, []string{"source"})
+
+	// NotifyQueueDepth tracks deliveries waiting for a worker
+	NotifyQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_notify_queue_depth",
+		Help: "Number of notification deliveries waiting for a worker",
+	})
+
+	// ArchivedRowsTotal tracks history rows moved to the object store archive
+	ArchivedRowsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_archived_rows_total",
+		Help: "Total number of persisted rows archived to object storage by table",
+	}, []string{"table"})
+
+	// ArchiveFailuresTotal tracks failed archive passes
+	ArchiveFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "arb_archive_failures_total",
+		Help: "Total number of archive passes that failed to export or upload",
+	})
+
+	// QuoteConflation tracks how many price updates each instrument received between compute cycles
+	QuoteConflation = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "arb_quote_conflation",
+		Help:    "Price updates per updated instrument between two compute cycles; all but the last are never evaluated",
+		Buckets: []float64{1, 2, 3, 5, 10, 20, 50, 100},
+	})
+
+	// ComputeInterval tracks the engine's current compute interval
+	ComputeInterval = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_compute_interval_seconds",
+		Help: "Current interval between engine compute cycles, shortened while quotes conflate",
+	})
+
+	// HotInstruments tracks instruments evaluated on update
+	HotInstruments = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_hot_instruments",
+		Help: "Number of instruments whose updates trigger a compute cycle, as of the last cycle",
+	})
+
+	// QuietSeconds tracks how long the engine has found nothing near the threshold
+	QuietSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_quiet_seconds",
+		Help: "Time since the engine last found an opportunity or near-threshold edge with healthy feeds, 0 while active",
+	})
+
+	// SilentFailure tracks the silent-failure alarm
+	SilentFailure = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "arb_silent_failure",
+		Help: "Silent-failure alarm (1 = healthy feeds but no opportunity or near-threshold edge for too long)",
+	})
+
+	// PairCollisionsTotal tracks matches dropped because a market was already paired
+	PairCollisionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_pair_collisions_total",
+		Help: "Total number of market matches suppressed because the contested market (polymarket, kalshi) was already paired",
+	}, []string{"side"})
+
+	// OpportunitiesExpiredTotal tracks opportunities outliving their execution window
+	OpportunitiesExpiredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "arb_opportunities_expired_total",
+		Help: "Total number of opportunities still open past their execution window, by strategy",
+	}, []string{"strategy"})
 )
 
 // RecordWSReconnect increments the reconnect counter for a source
 func RecordWSReconnect(source string) {
 	WSReconnectsTotal.WithLabelValues(source).Inc()
+	countFeed(source, func(c *FeedCounts) { c.Reconnects++ })
 }
 
 // RecordHTTPRequest increments the HTTP request counter
@@ -65,6 +310,16 @@ func RecordHTTPRequest(path, code string) {
 	HTTPRequestsTotal.WithLabelValues(path, code).Inc()
 }
 
+// ObserveHTTPRequestDuration records a request's handling time
+func ObserveHTTPRequestDuration(route string, d time.Duration) {
+	HTTPRequestDuration.WithLabelValues(route).Observe(d.Seconds())
+}
+
+// AddHTTPOpenStreams adjusts the number of open streams on a route by delta
+func AddHTTPOpenStreams(route string, delta float64) {
+	HTTPOpenStreams.WithLabelValues(route).Add(delta)
+}
+
 // SetWSConnectionStatus sets the connection status for a source
 func SetWSConnectionStatus(source string, connected bool) {
 	val := 0.0
@@ -74,9 +329,82 @@ func SetWSConnectionStatus(source string, connected bool) {
 	WSConnectionStatus.WithLabelValues(source).Set(val)
 }
 
+// RecordWSFrame increments the raw frame counter for a source
+func RecordWSFrame(source string) {
+	WSFramesReceivedTotal.WithLabelValues(source).Inc()
+	countFeed(source, func(c *FeedCounts) { c.Frames++ })
+}
+
+// RecordWSFrameParsed increments the parsed frame counter for a source and event type
+func RecordWSFrameParsed(source, eventType string) {
+	WSFramesParsedTotal.WithLabelValues(source, eventType).Inc()
+}
+
+// RecordWSFrameParseFailure increments the parse failure counter for a source
+func RecordWSFrameParseFailure(source string) {
+	WSFrameParseFailuresTotal.WithLabelValues(source).Inc()
+	countFeed(source, func(c *FeedCounts) { c.ParseFailures++ })
+}
+
+// RecordWSFrameRejected increments the refused quote update counter for a
+// source and reason
+func RecordWSFrameRejected(source, reason string) {
+	WSFramesRejectedTotal.WithLabelValues(source, reason).Inc()
+	if reason == "invalid" {
+		countFeed(source, func(c *FeedCounts) { c.Invalid++ })
+	}
+}
+
+// RecordJSONFieldTolerated increments the tolerated field counter for a
+// field and result
+func RecordJSONFieldTolerated(field, result string) {
+	JSONFieldsToleratedTotal.WithLabelValues(field, result).Inc()
+}
+
+// SetFeedQuality sets one of a source's data-quality measures
+func SetFeedQuality(source, measure string, value float64) {
+	FeedQuality.WithLabelValues(source, measure).Set(value)
+}
+
+// SetFrozenInstruments sets the number of frozen instruments for a source
+func SetFrozenInstruments(source string, count int) {
+	FrozenInstruments.WithLabelValues(source).Set(float64(count))
+}
+
+// RecordFeedFreeze increments the freeze counter for a source
+func RecordFeedFreeze(source string) {
+	FeedFreezesTotal.WithLabelValues(source, "frozen").Inc()
+}
+
+// RecordFeedResumed increments the resume counter for a source
+func RecordFeedResumed(source string) {
+	FeedFreezesTotal.WithLabelValues(source, "resumed").Inc()
+}
+
+// SetMaintenanceActive sets the maintenance state for a source
+func SetMaintenanceActive(source string, active bool) {
+	val := 0.0
+	if active {
+		val = 1.0
+	}
+	MaintenanceActive.WithLabelValues(source).Set(val)
+}
+
+// SetHaltedMarkets sets the number of halted markets for a source
+func SetHaltedMarkets(source string, count int) {
+	HaltedMarkets.WithLabelValues(source).Set(float64(count))
+}
+
+// RecordMarketTextChange increments the text change counter for a source and field
+func RecordMarketTextChange(source, field string) {
+	MarketTextChangesTotal.WithLabelValues(source, field).Inc()
+}
+
 // RecordPriceUpdate increments the price update counter for a source
 func RecordPriceUpdate(source string) {
 	PriceUpdatesTotal.WithLabelValues(source).Inc()
+	now := time.Now()
+	countFeed(source, func(c *FeedCounts) { c.LastUpdate = now })
 }
 
 // RecordOpportunityFound increments the opportunities found counter
@@ -84,11 +412,43 @@ func RecordOpportunityFound() {
 	OpportunitiesFoundTotal.Inc()
 }
 
+// RecordOpportunityFiltered increments the filtered counter for a filter
+func RecordOpportunityFiltered(filter string) {
+	OpportunitiesFilteredTotal.WithLabelValues(filter).Inc()
+}
+
+// RecordStrategyEvaluation records one strategy's result count and run time for a cycle
+func RecordStrategyEvaluation(strategy string, found int, duration time.Duration) {
+	StrategyOpportunities.WithLabelValues(strategy).Set(float64(found))
+	StrategyEvaluationDuration.WithLabelValues(strategy).Observe(duration.Seconds())
+}
+
+// SetShadowPnL sets a strategy's shadow ledger PnL
+func SetShadowPnL(strategy string, realized, unrealized float64) {
+	ShadowPnL.WithLabelValues(strategy, "realized").Set(realized)
+	ShadowPnL.WithLabelValues(strategy, "unrealized").Set(unrealized)
+}
+
 // UpdateCurrentOpportunities updates the gauge for current opportunities
 func UpdateCurrentOpportunities(count int) {
 	CurrentOpportunitiesGauge.Set(float64(count))
 }
 
+// SetNewsHeldPairs sets the number of pairs held after a sharp price move
+func SetNewsHeldPairs(count int) {
+	NewsHeldPairsGauge.Set(float64(count))
+}
+
+// RecordBestEdgeJump records a sudden rise in the best edge
+func RecordBestEdgeJump() {
+	BestEdgeJumpsTotal.Inc()
+}
+
+// SetTruncatedOpportunities sets the number of opportunities cut by the retention cap
+func SetTruncatedOpportunities(count int) {
+	TruncatedOpportunitiesGauge.Set(float64(count))
+}
+
 // UpdateBestEdge updates the best edge gauge
 func UpdateBestEdge(edgePct float64) {
 	BestEdgeGauge.Set(edgePct)
@@ -98,3 +458,106 @@ func UpdateBestEdge(edgePct float64) {
 func SetArbPairs(count int) {
 	ArbPairsTotal.Set(float64(count))
 }
+
+// RecordNotification increments the notification counter for a sender and result
+func RecordNotification(sender, result string) {
+	NotificationsTotal.WithLabelValues(sender, result).Inc()
+}
+
+// ObserveNotificationLatency records the duration of one delivery attempt
+func ObserveNotificationLatency(sender string, seconds float64) {
+	NotificationLatency.WithLabelValues(sender).Observe(seconds)
+}
+
+// SetNotifyQueueDepth sets the notification queue depth gauge
+func SetNotifyQueueDepth(depth int) {
+	NotifyQueueDepth.Set(float64(depth))
+}
+
+// RecordAPICall increments the REST call counter for an exchange
+func RecordAPICall(source string) {
+	APICallsTotal.WithLabelValues(source).Inc()
+}
+
+// SetAPISubscriptions sets the subscription gauge for an exchange
+func SetAPISubscriptions(source string, count int) {
+	APISubscriptions.WithLabelValues(source).Set(float64(count))
+}
+
+// SetAPIBudgetUsed sets the budget usage ratio for an exchange and kind ("rest" or "subscriptions")
+func SetAPIBudgetUsed(source, kind string, ratio float64) {
+	APIBudgetUsed.WithLabelValues(source, kind).Set(ratio)
+}
+
+// RecordAPIThrottled increments the throttled work counter for an exchange
+func RecordAPIThrottled(source string) {
+	APIThrottledTotal.WithLabelValues(source).Inc()
+}
+
+// SetKillSwitchEngaged sets the kill switch gauge
+func SetKillSwitchEngaged(engaged bool) {
+	val := 0.0
+	if engaged {
+		val = 1.0
+	}
+	KillSwitchEngaged.Set(val)
+}
+
+// RecordKillSwitchTrip increments the kill switch trip counter for a source
+func RecordKillSwitchTrip(source string) {
+	KillSwitchTripsTotal.WithLabelValues(source).Inc()
+}
+
+// RecordArchivedRows adds rows archived from a table
+func RecordArchivedRows(table string, rows int) {
+	ArchivedRowsTotal.WithLabelValues(table).Add(float64(rows))
+}
+
+// RecordArchiveFailure increments the failed archive pass counter
+func RecordArchiveFailure() {
+	ArchiveFailuresTotal.Inc()
+}
+
+// ObserveQuoteConflation records one instrument's updates in a compute cycle
+func ObserveQuoteConflation(updates int) {
+	QuoteConflation.Observe(float64(updates))
+}
+
+// SetComputeInterval sets the current compute interval gauge
+func SetComputeInterval(d time.Duration) {
+	ComputeInterval.Set(d.Seconds())
+}
+
+// SetHotInstruments sets the number of hot instruments
+func SetHotInstruments(count int) {
+	HotInstruments.Set(float64(count))
+}
+
+// SetSilence sets the quiet period and silent-failure alarm gauges
+func SetSilence(quiet time.Duration, alarmed bool) {
+	QuietSeconds.Set(quiet.Seconds())
+	val := 0.0
+	if alarmed {
+		val = 1.0
+	}
+	SilentFailure.Set(val)
+}
+
+// RecordPairCollision increments the suppressed match counter for the contested side
+func RecordPairCollision(side string) {
+	PairCollisionsTotal.WithLabelValues(side).Inc()
+}
+
+// RecordOpportunityExpired increments the expired opportunity counter for a strategy
+func RecordOpportunityExpired(strategy string) {
+	OpportunitiesExpiredTotal.WithLabelValues(strategy).Inc()
+}
+
+// SetWSFlapping sets whether a source's connection is flapping
+func SetWSFlapping(source string, flapping bool) {
+	val := 0.0
+	if flapping {
+		val = 1.0
+	}
+	WSFlapping.WithLabelValues(source).Set(val)
+}
@@ -0,0 +1,315 @@
+// Package mqtt is a minimal MQTT 3.1.1 client for publishing: it connects,
+// publishes at QoS 0 or 1 and disconnects. Subscriptions, QoS 2 and session
+// persistence are not supported.
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Control packet types
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+// Options configure a connection
+type Options struct {
+	// Broker is the broker URL: tcp://host:1883, or ssl://, tls:// or
+	// mqtts://host:8883 for TLS. The port defaults to 1883 or 8883.
+	Broker    string
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration // Defaults to 60s
+	TLS       *tls.Config   // Optional TLS settings for TLS brokers
+}
+
+// Client is a connection to a broker. It is safe for concurrent use;
+// publishes are serialized.
+type Client struct {
+	mu        sync.Mutex
+	conn      net.Conn
+	r         *bufio.Reader
+	nextID    uint16
+	keepAlive time.Duration
+	lastSent  time.Time
+}
+
+// Dial connects to the broker and completes the MQTT handshake with a clean
+// session
+func Dial(ctx context.Context, opts Options) (*Client, error) {
+	u, err := url.Parse(opts.Broker)
+	if err != nil {
+		return nil, fmt.Errorf("parse broker url: %w", err)
+	}
+	useTLS := false
+	port := "1883"
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		useTLS, port = true, "8883"
+	default:
+		return nil, fmt.Errorf("unsupported broker scheme %q", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var conn net.Conn
+	if useTLS {
+		cfg := opts.TLS
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		if cfg.ServerName == "" {
+			cfg = cfg.Clone()
+			cfg.ServerName = u.Hostname()
+		}
+		conn, err = (&tls.Dialer{Config: cfg}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+
+	c, err := NewClient(ctx, conn, opts)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient performs the MQTT handshake over an established connection
+func NewClient(ctx context.Context, conn net.Conn, opts Options) (*Client, error) {
+	keepAlive := opts.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = 60 * time.Second
+	}
+	c := &Client{conn: conn, r: bufio.NewReader(conn), keepAlive: keepAlive}
+
+	setDeadline(ctx, conn)
+	defer conn.SetDeadline(time.Time{})
+
+	if err := c.write(connectPacket(opts, keepAlive)); err != nil {
+		return nil, fmt.Errorf("send connect: %w", err)
+	}
+	typ, body, err := c.read()
+	if err != nil {
+		return nil, fmt.Errorf("read connack: %w", err)
+	}
+	if typ != packetConnack || len(body) != 2 {
+		return nil, fmt.Errorf("expected connack, got packet type %d", typ)
+	}
+	if code := body[1]; code != 0 {
+		return nil, fmt.Errorf("connection refused: %s", connackReason(code))
+	}
+	return c, nil
+}
+
+// Publish sends a message. At QoS 1 it waits for the broker's
+// acknowledgment; QoS 0 returns once the message is written.
+func (c *Client) Publish(ctx context.Context, topic string, payload []byte, qos byte, retain bool) error {
+	if qos > 1 {
+		return fmt.Errorf("unsupported qos %d", qos)
+	}
+	if topic == "" {
+		return errors.New("empty topic")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	setDeadline(ctx, c.conn)
+	defer c.conn.SetDeadline(time.Time{})
+
+	// Publishing is activity enough unless the client sat idle
+	if time.Since(c.lastSent) > c.keepAlive/2 {
+		if err := c.ping(); err != nil {
+			return err
+		}
+	}
+
+	flags := qos << 1
+	if retain {
+		flags |= 1
+	}
+	var vh []byte
+	vh = appendString(vh, topic)
+	var id uint16
+	if qos == 1 {
+		c.nextID++
+		if c.nextID == 0 {
+			c.nextID = 1
+		}
+		id = c.nextID
+		vh = binary.BigEndian.AppendUint16(vh, id)
+	}
+	if err := c.write(packet(packetPublish<<4|flags, append(vh, payload...))); err != nil {
+		return fmt.Errorf("publish %s: %w", topic, err)
+	}
+	if qos == 0 {
+		return nil
+	}
+
+	for {
+		typ, body, err := c.read()
+		if err != nil {
+			return fmt.Errorf("await puback for %s: %w", topic, err)
+		}
+		if typ == packetPuback && len(body) == 2 && binary.BigEndian.Uint16(body) == id {
+			return nil
+		}
+		// Late PINGRESPs and acknowledgments of abandoned publishes are skipped
+	}
+}
+
+// ping round-trips a PINGREQ, keeping an idle connection alive and
+// detecting a dead one
+func (c *Client) ping() error {
+	if err := c.write(packet(packetPingreq<<4, nil)); err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	for {
+		typ, _, err := c.read()
+		if err != nil {
+			return fmt.Errorf("await pingresp: %w", err)
+		}
+		if typ == packetPingresp {
+			return nil
+		}
+	}
+}
+
+// Close sends DISCONNECT and closes the connection
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.write(packet(packetDisconnect<<4, nil))
+	return c.conn.Close()
+}
+
+func (c *Client) write(p []byte) error {
+	if _, err := c.conn.Write(p); err != nil {
+		return err
+	}
+	c.lastSent = time.Now()
+	return nil
+}
+
+// read reads one control packet, returning its type and the bytes after
+// the fixed header
+func (c *Client) read() (byte, []byte, error) {
+	header, err := c.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var length, shift int
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+		b, err := c.r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		shift += 7
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, err
+	}
+	return header >> 4, body, nil
+}
+
+// connectPacket builds a CONNECT packet with a clean session
+func connectPacket(opts Options, keepAlive time.Duration) []byte {
+	flags := byte(0x02) // Clean session
+	var b []byte
+	b = appendString(b, "MQTT")
+	b = append(b, 4) // Protocol level 3.1.1
+	if opts.Username != "" {
+		flags |= 0x80
+		if opts.Password != "" {
+			flags |= 0x40
+		}
+	}
+	b = append(b, flags)
+	b = binary.BigEndian.AppendUint16(b, uint16(min(keepAlive/time.Second, 65535)))
+	b = appendString(b, opts.ClientID)
+	if opts.Username != "" {
+		b = appendString(b, opts.Username)
+		if opts.Password != "" {
+			b = appendString(b, opts.Password)
+		}
+	}
+	return packet(packetConnect<<4, b)
+}
+
+// packet prefixes body with the fixed header
+func packet(header byte, body []byte) []byte {
+	b := []byte{header}
+	n := len(body)
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if n == 0 {
+			break
+		}
+	}
+	return append(b, body...)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// setDeadline applies ctx's deadline, if any, to conn
+func setDeadline(ctx context.Context, conn net.Conn) {
+	if d, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(d)
+	}
+}
+
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	}
+	return fmt.Sprintf("return code %d", code)
+}
@@ -0,0 +1,128 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// message is a PUBLISH received by the test broker
+type message struct {
+	topic   string
+	payload string
+	qos     byte
+	retain  bool
+}
+
+// testBroker accepts one connection, answers CONNECT with returnCode and
+// acknowledges publishes and pings
+func testBroker(t *testing.T, returnCode byte) (addr string, connect chan []byte, received chan message) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	connect, received = make(chan []byte, 1), make(chan message, 10)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		c := &Client{conn: conn, r: bufio.NewReader(conn)}
+		for {
+			typ, body, err := c.read()
+			if err != nil {
+				return
+			}
+			switch typ {
+			case packetConnect:
+				connect <- body
+				c.write(packet(packetConnack<<4, []byte{0, returnCode}))
+			case packetPublish:
+				// The flags are not passed up by read; QoS 1 is told by the
+				// packet identifier after the topic
+				n := int(binary.BigEndian.Uint16(body))
+				topic, rest := string(body[2:2+n]), body[2+n:]
+				m := message{topic: topic, payload: string(rest)}
+				if strings.HasSuffix(topic, "/qos1") {
+					m.qos, m.payload = 1, string(rest[2:])
+					c.write(packet(packetPuback<<4, rest[:2]))
+				}
+				received <- m
+			case packetPingreq:
+				c.write(packet(packetPingresp<<4, nil))
+			case packetDisconnect:
+				return
+			}
+		}
+	}()
+	return "tcp://" + ln.Addr().String(), connect, received
+}
+
+func TestPublish(t *testing.T) {
+	addr, connect, received := testBroker(t, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := Dial(ctx, Options{Broker: addr, ClientID: "arb-test", Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer c.Close()
+
+	body := <-connect
+	if string(body[2:6]) != "MQTT" || body[6] != 4 || body[7] != 0xc2 {
+		t.Errorf("unexpected connect header % x", body[:8])
+	}
+	if !strings.Contains(string(body), "arb-test") {
+		t.Error("client ID missing from connect")
+	}
+
+	if err := c.Publish(ctx, "arb/opportunities/qos0", []byte(`[]`), 0, true); err != nil {
+		t.Fatalf("Publish qos 0: %v", err)
+	}
+	if m := <-received; m.topic != "arb/opportunities/qos0" || m.payload != "[]" {
+		t.Errorf("unexpected message %+v", m)
+	}
+
+	if err := c.Publish(ctx, "arb/opportunities/qos1", []byte(`[{"id":"a"}]`), 1, false); err != nil {
+		t.Fatalf("Publish qos 1: %v", err)
+	}
+	if m := <-received; m.qos != 1 || m.payload != `[{"id":"a"}]` {
+		t.Errorf("unexpected message %+v", m)
+	}
+
+	if err := c.Publish(ctx, "t", nil, 2, false); err == nil {
+		t.Error("qos 2 accepted")
+	}
+}
+
+func TestConnectRefused(t *testing.T) {
+	addr, _, _ := testBroker(t, 5)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := Dial(ctx, Options{Broker: addr})
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Fatalf("expected a refused connection, got %v", err)
+	}
+	if _, err := Dial(ctx, Options{Broker: "http://localhost"}); err == nil {
+		t.Error("http broker url accepted")
+	}
+}
+
+func TestRemainingLength(t *testing.T) {
+	for n, want := range map[int][]byte{0: {0}, 127: {0x7f}, 128: {0x80, 0x01}, 16383: {0xff, 0x7f}, 16384: {0x80, 0x80, 0x01}} {
+		p := packet(packetPublish<<4, make([]byte, n))
+		if got := p[1 : 1+len(want)]; string(got) != string(want) {
+			t.Errorf("length %d encoded as % x, want % x", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// AlertOptions configures OpportunityAlerts
+type AlertOptions struct {
+	// Cooldown is how long an alerted opportunity must have been gone before
+	// its return alerts again; zero alerts on every return
+	Cooldown time.Duration
+
+	// Alerted seeds the dedupe with opportunities alerted by an earlier
+	// process, by ID, with when each was last seen open. Those still within
+	// the cooldown do not alert again after a restart.
+	Alerted map[string]time.Time
+
+	// Record, when set, is called after every update with the open
+	// opportunities already alerted and when they were seen, e.g. to persist
+	// them for the next process's Alerted
+	Record func(alerted map[string]time.Time)
+}
+
+// OpportunityAlerts returns an arb.Engine.OnUpdate hook that enqueues one
+// notification each time an opportunity appears. An opportunity that
+// disappears alerts again when it returns after the cooldown.
+func OpportunityAlerts(d *Dispatcher, opts AlertOptions) func([]arb.Opportunity) {
+	var mu sync.Mutex
+	seen := make(map[string]struct{})
+	lastSeen := make(map[string]time.Time, len(opts.Alerted)) // Alerted opportunities -> when last seen open
+	for id, at := range opts.Alerted {
+		lastSeen[id] = at
+	}
+
+	return func(opps []arb.Opportunity) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		current := make(map[string]struct{}, len(opps))
+		for _, o := range opps {
+			current[o.ID] = struct{}{}
+			_, open := seen[o.ID]
+			last, alerted := lastSeen[o.ID]
+			lastSeen[o.ID] = now
+			if open || alerted && now.Sub(last) <= opts.Cooldown {
+				continue
+			}
+			label := "Arb"
+			if o.Strategy != "" && o.Strategy != arb.StrategyArbitrage {
+				label = o.Strategy
+			}
+			d.Enqueue(Notification{
+				Title: fmt.Sprintf("%s %.2f%%: %s", label, o.EdgePctTurn, o.Combo),
+				Text: fmt.Sprintf("%s\n%s (%s)\ncost %.3f, max size %.0f\n%s",
+					o.PMTitle, o.KalshiTitle, o.KalshiTicker, o.TotalCost, o.MaxSize, pairingLine(o.Annotation)),
+				OppID:       o.ID,
+				Timestamp:   o.Timestamp,
+				Opportunity: &o,
+			})
+		}
+		seen = current
+
+		for id, at := range lastSeen {
+			if now.Sub(at) > opts.Cooldown {
+				delete(lastSeen, id)
+			}
+		}
+		if opts.Record != nil && len(current) > 0 {
+			alerted := make(map[string]time.Time, len(current))
+			for id := range current {
+				alerted[id] = now
+			}
+			opts.Record(alerted)
+		}
+	}
+}
+
+// pairingLine describes how far an operator has vouched for a pairing
+func pairingLine(a *arb.Annotation) string {
+	switch {
+	case a == nil:
+		return "pairing unverified"
+	case a.Verified && a.Note != "":
+		return "pairing verified: " + a.Note
+	case a.Verified:
+		return "pairing verified"
+	case a.Note != "":
+		return "pairing unverified: " + a.Note
+	default:
+		return "pairing unverified"
+	}
+}
+
+// TextChangeAlerts returns an arb.TextWatch.OnChange hook that enqueues a
+// notification for each material edit to a paired market's text
+func TextChangeAlerts(d *Dispatcher) func(arb.TextChange) {
+	return func(c arb.TextChange) {
+		title := fmt.Sprintf("%s %s %s changed: %s", c.Venue, c.Market, c.Field, c.Reason)
+		if c.Suspended {
+			title += ", pair suspended"
+		}
+		text := fmt.Sprintf("Before: %s\nAfter: %s", c.Before, c.After)
+		for _, p := range c.Pairs {
+			text += fmt.Sprintf("\n%s / %s (%s): score %.2f -> %.2f", p.PMTitle, p.KalshiTitle, p.KalshiTicker, p.MatchScore, p.NewScore)
+		}
+		d.Enqueue(Notification{Title: title, Text: text, Timestamp: c.DetectedAt})
+	}
+}
+
+// EdgeJumpAlerts returns an arb.EdgeJumpDetector.OnJump hook that enqueues an
+// ops notification for each sudden rise in the best edge. These are
+// diagnostics, not trade signals: check the feeds and the pairing first.
+func EdgeJumpAlerts(d *Dispatcher) func(arb.EdgeJump) {
+	return func(j arb.EdgeJump) {
+		o := j.Opportunity
+		d.Enqueue(Notification{
+			Title: fmt.Sprintf("Best edge jumped %.1fpp in one cycle: %.2f%% -> %.2f%%", j.RisePP, j.FromPct, j.ToPct),
+			Text: fmt.Sprintf("%s\n%s (%s), %s\nquote times: pm %s, kalshi %s\nlikely a parsing bug, stale feed or mismatched pair; %s",
+				o.PMTitle, o.KalshiTitle, o.KalshiTicker, o.Combo,
+				o.PMQuoteTime.Format(time.RFC3339), o.KalshiQuoteTime.Format(time.RFC3339), pairingLine(o.Annotation)),
+			OppID:     o.ID,
+			Timestamp: j.DetectedAt,
+		})
+	}
+}
+
+// SilenceAlerts returns an arb.SilenceDetector.OnAlarm hook that enqueues an
+// ops notification when the engine has found nothing for too long
+func SilenceAlerts(d *Dispatcher) func(arb.Silence) {
+	return func(s arb.Silence) {
+		d.Enqueue(Notification{
+			Title: fmt.Sprintf("No opportunity or near-threshold edge for %s", s.DetectedAt.Sub(s.Since).Round(time.Minute)),
+			Text: fmt.Sprintf("%d pairs evaluated, best edge %.2f%% against a %.2f%% threshold, feeds connected\nlikely a units or normalization bug rather than efficient markets",
+				s.Evaluated, s.BestEdgePct, s.ThresholdPct),
+			Timestamp: s.DetectedAt,
+		})
+	}
+}
+
+// FlapAlerts returns a ws client flap hook that enqueues an ops notification
+// when a WebSocket connection keeps dropping soon after connecting
+func FlapAlerts(d *Dispatcher) func(ws.Flap) {
+	return func(f ws.Flap) {
+		d.Enqueue(Notification{
+			Title:     fmt.Sprintf("%s websocket flapping", f.Source),
+			Text:      fmt.Sprintf("%d connections within %s\nreconnect backoff keeps growing until it settles", f.Reconnects, f.Window),
+			Timestamp: f.DetectedAt,
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/mqtt"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// DefaultMQTTTopic is the topic prefix opportunities are published under
+const DefaultMQTTTopic = "arb/opportunities"
+
+// MQTTPublisher publishes each strategy's current opportunities as a JSON
+// array to <topic>/<strategy>, retained by default so a consumer that
+// subscribes later (Node-RED, Home Assistant) gets the current list at once.
+// A strategy whose opportunities all disappeared gets an empty array.
+type MQTTPublisher struct {
+	Options mqtt.Options
+	Topic   string // Topic prefix, DefaultMQTTTopic when empty
+	QoS     byte   // 0 or 1
+	Retain  bool
+
+	mu     sync.Mutex
+	client *mqtt.Client
+	last   map[string]bool // Strategies published with opportunities last time
+}
+
+// Name implements Publisher
+func (p *MQTTPublisher) Name() string { return "mqtt" }
+
+// Publish implements Publisher. The connection is opened on first use and
+// reopened on the next publish after a failure.
+func (p *MQTTPublisher) Publish(ctx context.Context, opps []arb.Opportunity) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	byStrategy := make(map[string][]arb.Opportunity)
+	for _, o := range opps {
+		byStrategy[o.Strategy] = append(byStrategy[o.Strategy], o)
+	}
+	for s := range p.last {
+		if _, ok := byStrategy[s]; !ok {
+			byStrategy[s] = []arb.Opportunity{}
+		}
+	}
+	strategies := make([]string, 0, len(byStrategy))
+	for s := range byStrategy {
+		strategies = append(strategies, s)
+	}
+	sort.Strings(strategies)
+
+	if p.client == nil {
+		client, err := mqtt.Dial(ctx, p.Options)
+		if err != nil {
+			return err
+		}
+		p.client = client
+	}
+
+	prefix := strings.TrimSuffix(p.Topic, "/")
+	if prefix == "" {
+		prefix = DefaultMQTTTopic
+	}
+	for _, s := range strategies {
+		payload, err := json.Marshal(byStrategy[s])
+		if err != nil {
+			return fmt.Errorf("marshal %s opportunities: %w", s, err)
+		}
+		if err := p.client.Publish(ctx, prefix+"/"+s, payload, p.QoS, p.Retain); err != nil {
+			p.client.Close()
+			p.client = nil
+			return err
+		}
+	}
+
+	p.last = make(map[string]bool, len(byStrategy))
+	for s, list := range byStrategy {
+		if len(list) > 0 {
+			p.last[s] = true
+		}
+	}
+	return nil
+}
+
+// Close disconnects from the broker
+func (p *MQTTPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == nil {
+		return nil
+	}
+	err := p.client.Close()
+	p.client = nil
+	return err
+}
+
+// Check implements Checker by connecting to the broker under a separate
+// client ID, so a running instance's session is not taken over
+func (p *MQTTPublisher) Check(ctx context.Context) error {
+	opts := p.Options
+	opts.ClientID += "-check"
+	client, err := mqtt.Dial(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
+
+// newMQTTPublisher builds an MQTTPublisher from spec settings: broker
+// (required), topic, qos (0 or 1, default 0), retain (default true),
+// client_id, username and password
+func newMQTTPublisher(settings map[string]string) (Publisher, error) {
+	if settings["broker"] == "" {
+		return nil, fmt.Errorf("mqtt publisher requires broker")
+	}
+	p := &MQTTPublisher{
+		Options: mqtt.Options{
+			Broker:   settings["broker"],
+			ClientID: settings["client_id"],
+			Username: settings["username"],
+			Password: settings["password"],
+		},
+		Topic:  settings["topic"],
+		Retain: true,
+	}
+	if p.Options.ClientID == "" {
+		host, _ := os.Hostname()
+		p.Options.ClientID = "arb-ws-" + host
+	}
+	if v := settings["qos"]; v != "" {
+		qos, err := strconv.Atoi(v)
+		if err != nil || qos < 0 || qos > 1 {
+			return nil, fmt.Errorf("mqtt publisher qos must be 0 or 1, got %q", v)
+		}
+		p.QoS = byte(qos)
+	}
+	if v := settings["retain"]; v != "" {
+		retain, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("mqtt publisher retain: %w", err)
+		}
+		p.Retain = retain
+	}
+	return p, nil
+}
+
+func init() {
+	RegisterPublisher("mqtt", newMQTTPublisher)
+}
@@ -0,0 +1,242 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+const (
+	// DefaultWorkers is the default number of concurrent delivery goroutines
+	DefaultWorkers = 4
+	// DefaultQueueSize is the default number of pending deliveries before new ones are dropped
+	DefaultQueueSize = 256
+	// DefaultTimeout bounds a single delivery attempt
+	DefaultTimeout = 10 * time.Second
+
+	maxAttempts = 3
+)
+
+// retryPolicy spaces a delivery's attempts
+var retryPolicy = backoff.Policy{Base: 500 * time.Millisecond, MaxAttempts: maxAttempts}
+
+// Notification is a single message to deliver to every configured sender
+type Notification struct {
+	Title     string    `json:"title"`
+	Text      string    `json:"text"`
+	OppID     string    `json:"opp_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Opportunity is the full payload (carrying its schema_version) for
+	// machine consumers such as webhooks
+	Opportunity *arb.Opportunity `json:"opportunity,omitempty"`
+}
+
+// Sender delivers notifications to one destination (Telegram, Slack, webhook, ...)
+type Sender interface {
+	Name() string
+	Send(ctx context.Context, n Notification) error
+}
+
+// Checker is implemented by senders and publishers that can verify their
+// destination is reachable without delivering anything
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// Options configures the dispatcher; zero values fall back to the defaults
+type Options struct {
+	Workers        int
+	QueueSize      int
+	Timeout        time.Duration
+	DeadLetterPath string // JSON-lines file for permanently failed deliveries; empty logs only
+}
+
+// delivery is one notification bound for one sender
+type delivery struct {
+	sender Sender
+	n      Notification
+}
+
+// deadLetter is the record written for a delivery that exhausted its retries
+type deadLetter struct {
+	FailedAt     time.Time    `json:"failed_at"`
+	Sender       string       `json:"sender"`
+	Attempts     int          `json:"attempts"`
+	Error        string       `json:"error"`
+	Notification Notification `json:"notification"`
+}
+
+// Dispatcher fans notifications out to senders on a bounded worker pool so
+// slow or unreachable destinations never block the caller
+type Dispatcher struct {
+	senders []Sender
+	opts    Options
+	queue   chan delivery
+	wg      sync.WaitGroup
+	dlMu    sync.Mutex
+	logger  *slog.Logger
+
+	suppress func(Notification) bool // Reports notifications no longer worth delivering
+}
+
+// NewDispatcher creates a dispatcher for the given senders
+func NewDispatcher(senders []Sender, opts Options, logger *slog.Logger) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	return &Dispatcher{
+		senders: senders,
+		opts:    opts,
+		queue:   make(chan delivery, opts.QueueSize),
+		logger:  logger,
+	}
+}
+
+// SetSuppress installs a predicate checked before every delivery attempt;
+// notifications it reports are dropped instead of delivered, e.g. alerts for
+// opportunities a consumer has already acknowledged. Call before Start.
+func (d *Dispatcher) SetSuppress(fn func(Notification) bool) {
+	d.suppress = fn
+}
+
+// Start launches the worker pool; workers exit when ctx is done
+func (d *Dispatcher) Start(ctx context.Context) {
+	for i := 0; i < d.opts.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker(ctx)
+	}
+}
+
+// Wait blocks until all workers have exited
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+// Enqueue schedules n for delivery to every sender without blocking.
+// Deliveries that don't fit in the queue are dropped and dead-lettered.
+func (d *Dispatcher) Enqueue(n Notification) {
+	if n.Timestamp.IsZero() {
+		n.Timestamp = time.Now()
+	}
+
+	for _, s := range d.senders {
+		select {
+		case d.queue <- delivery{sender: s, n: n}:
+		default:
+			metrics.RecordNotification(s.Name(), "dropped")
+			d.deadLetter(delivery{sender: s, n: n}, 0, fmt.Errorf("notification queue full"))
+		}
+	}
+	metrics.SetNotifyQueueDepth(len(d.queue))
+}
+
+// worker delivers queued notifications until ctx is done
+func (d *Dispatcher) worker(ctx context.Context) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-d.queue:
+			metrics.SetNotifyQueueDepth(len(d.queue))
+			d.deliver(ctx, job)
+		}
+	}
+}
+
+// deliver attempts a delivery with retries, each attempt bounded by the timeout
+func (d *Dispatcher) deliver(ctx context.Context, job delivery) {
+	name := job.sender.Name()
+	retry := backoff.New(retryPolicy)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if d.suppress != nil && d.suppress(job.n) {
+			metrics.RecordNotification(name, "suppressed")
+			return
+		}
+
+		start := time.Now()
+		attemptCtx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+		err = job.sender.Send(attemptCtx, job.n)
+		cancel()
+		metrics.ObserveNotificationLatency(name, time.Since(start).Seconds())
+
+		if err == nil {
+			metrics.RecordNotification(name, "sent")
+			return
+		}
+
+		d.logger.Warn("notification delivery failed", "sender", name, "attempt", attempt, "error", err)
+		delay, ok := retry.Next()
+		if !ok {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	metrics.RecordNotification(name, "failed")
+	d.deadLetter(job, maxAttempts, err)
+}
+
+// deadLetter records a permanently failed delivery in the log and, if
+// configured, appends it to the dead-letter file
+func (d *Dispatcher) deadLetter(job delivery, attempts int, cause error) {
+	d.logger.Error("notification dead-lettered",
+		"sender", job.sender.Name(),
+		"attempts", attempts,
+		"error", cause,
+		"title", job.n.Title,
+		"opp_id", job.n.OppID,
+	)
+
+	if d.opts.DeadLetterPath == "" {
+		return
+	}
+
+	line, err := json.Marshal(deadLetter{
+		FailedAt:     time.Now(),
+		Sender:       job.sender.Name(),
+		Attempts:     attempts,
+		Error:        cause.Error(),
+		Notification: job.n,
+	})
+	if err != nil {
+		return
+	}
+
+	d.dlMu.Lock()
+	defer d.dlMu.Unlock()
+
+	f, err := os.OpenFile(d.opts.DeadLetterPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		d.logger.Error("failed to open dead-letter file", "path", d.opts.DeadLetterPath, "error", err)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		d.logger.Error("failed to write dead-letter file", "path", d.opts.DeadLetterPath, "error", err)
+	}
+}
@@ -0,0 +1,348 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+type fakeSender struct {
+	mu    sync.Mutex
+	calls int
+	sent  []Notification
+	send  func(ctx context.Context) error
+}
+
+func (s *fakeSender) Name() string { return "fake" }
+
+func (s *fakeSender) Send(ctx context.Context, n Notification) error {
+	s.mu.Lock()
+	s.calls++
+	s.mu.Unlock()
+
+	if err := s.send(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.sent = append(s.sent, n)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *fakeSender) snapshot() (int, []Notification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls, append([]Notification(nil), s.sent...)
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition not met before deadline")
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &fakeSender{send: func(context.Context) error { return nil }}
+	d := NewDispatcher([]Sender{s}, Options{Workers: 2}, testLogger())
+	d.Start(ctx)
+
+	d.Enqueue(Notification{Title: "a"})
+	d.Enqueue(Notification{Title: "b"})
+
+	waitFor(t, func() bool { _, sent := s.snapshot(); return len(sent) == 2 })
+}
+
+func TestDispatcherSuppress(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &fakeSender{send: func(context.Context) error { return nil }}
+	d := NewDispatcher([]Sender{s}, Options{Workers: 1}, testLogger())
+	d.SetSuppress(func(n Notification) bool { return n.OppID == "acked" })
+	d.Start(ctx)
+
+	d.Enqueue(Notification{Title: "skip", OppID: "acked"})
+	d.Enqueue(Notification{Title: "keep", OppID: "open"})
+
+	waitFor(t, func() bool { _, sent := s.snapshot(); return len(sent) == 1 })
+	if calls, sent := s.snapshot(); calls != 1 || sent[0].OppID != "open" {
+		t.Errorf("expected only the unacknowledged alert, got %d calls %+v", calls, sent)
+	}
+}
+
+func TestDispatcherTimeoutAndDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+
+	// A destination that hangs until the per-delivery timeout fires
+	s := &fakeSender{send: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	d := NewDispatcher([]Sender{s}, Options{Workers: 1, Timeout: 10 * time.Millisecond, DeadLetterPath: path}, testLogger())
+	d.Start(ctx)
+
+	d.Enqueue(Notification{Title: "stuck", OppID: "abc"})
+
+	waitFor(t, func() bool {
+		info, err := os.Stat(path)
+		return err == nil && info.Size() > 0
+	})
+
+	if calls, _ := s.snapshot(); calls != maxAttempts {
+		t.Errorf("expected %d attempts, got %d", maxAttempts, calls)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open dead-letter file: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("dead-letter file is empty")
+	}
+	var rec deadLetter
+	if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+		t.Fatalf("decode dead letter: %v", err)
+	}
+	if rec.Sender != "fake" || rec.Notification.OppID != "abc" || rec.Attempts != maxAttempts {
+		t.Errorf("unexpected dead letter %+v", rec)
+	}
+	if rec.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("expected deadline error in dead letter, got %q", rec.Error)
+	}
+}
+
+func TestEnqueueNeverBlocks(t *testing.T) {
+	// No workers started: the queue fills and further deliveries are dropped
+	s := &fakeSender{send: func(context.Context) error { return nil }}
+	d := NewDispatcher([]Sender{s}, Options{QueueSize: 2}, testLogger())
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			d.Enqueue(Notification{Title: "x"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Enqueue blocked on a full queue")
+	}
+	if len(d.queue) != 2 {
+		t.Errorf("expected queue depth 2, got %d", len(d.queue))
+	}
+}
+
+func TestOpportunityAlertsOnlyNew(t *testing.T) {
+	d := NewDispatcher([]Sender{&fakeSender{send: func(context.Context) error { return nil }}}, Options{QueueSize: 16}, testLogger())
+	hook := OpportunityAlerts(d, AlertOptions{})
+
+	hook([]arb.Opportunity{{ID: "a"}, {ID: "b"}})
+	hook([]arb.Opportunity{{ID: "a"}})            // b gone, nothing new
+	hook([]arb.Opportunity{{ID: "a"}, {ID: "b"}}) // b returns: alert again
+
+	if len(d.queue) != 3 {
+		t.Errorf("expected 3 queued alerts, got %d", len(d.queue))
+	}
+}
+
+func TestOpportunityAlertsCooldown(t *testing.T) {
+	d := NewDispatcher([]Sender{&fakeSender{send: func(context.Context) error { return nil }}}, Options{QueueSize: 16}, testLogger())
+	var recorded map[string]time.Time
+	hook := OpportunityAlerts(d, AlertOptions{
+		Cooldown: time.Hour,
+		// Alerted before a restart: a was open moments ago, b long ago
+		Alerted: map[string]time.Time{"a": time.Now().Add(-time.Minute), "b": time.Now().Add(-2 * time.Hour)},
+		Record:  func(alerted map[string]time.Time) { recorded = alerted },
+	})
+
+	hook([]arb.Opportunity{{ID: "a"}, {ID: "b"}}) // only b alerts
+	hook([]arb.Opportunity{{ID: "a"}})            // b gone
+	hook([]arb.Opportunity{{ID: "a"}, {ID: "b"}}) // b returns within the cooldown
+
+	if len(d.queue) != 1 {
+		t.Fatalf("expected 1 queued alert, got %d", len(d.queue))
+	}
+	if job := <-d.queue; job.n.OppID != "b" {
+		t.Errorf("alerted %q, want b", job.n.OppID)
+	}
+	if len(recorded) != 2 || recorded["a"].IsZero() || recorded["b"].IsZero() {
+		t.Errorf("unexpected recorded alerts %v", recorded)
+	}
+}
+
+func TestOpportunityAlertsCarryAnnotation(t *testing.T) {
+	d := NewDispatcher([]Sender{&fakeSender{send: func(context.Context) error { return nil }}}, Options{QueueSize: 16}, testLogger())
+	hook := OpportunityAlerts(d, AlertOptions{})
+
+	hook([]arb.Opportunity{
+		{ID: "a", Annotation: &arb.Annotation{Verified: true, Note: "same FOMC source"}},
+		{ID: "b"},
+	})
+
+	first, second := <-d.queue, <-d.queue
+	if !strings.HasSuffix(first.n.Text, "pairing verified: same FOMC source") {
+		t.Errorf("annotated alert text %q", first.n.Text)
+	}
+	if !strings.HasSuffix(second.n.Text, "pairing unverified") {
+		t.Errorf("unannotated alert text %q", second.n.Text)
+	}
+}
+
+// namedSender discards notifications; it only exercises the registry
+type namedSender string
+
+func (s namedSender) Name() string { return string(s) }
+
+func (s namedSender) Send(ctx context.Context, n Notification) error { return nil }
+
+func TestParseSpecAndRegistry(t *testing.T) {
+	kind, settings, err := ParseSpec("webhook?url=https%3A%2F%2Fexample.com%2Fhook&x=1")
+	if err != nil || kind != "webhook" || settings["url"] != "https://example.com/hook" || settings["x"] != "1" {
+		t.Fatalf("unexpected parse: %q %v %v", kind, settings, err)
+	}
+	if _, _, err := ParseSpec("?url=x"); err == nil {
+		t.Error("expected error for missing kind")
+	}
+
+	s, err := NewSender(kind, settings)
+	if err != nil || s.Name() != "webhook" {
+		t.Fatalf("NewSender: %v, %v", s, err)
+	}
+	if _, err := NewSender("webhook", nil); err == nil {
+		t.Error("expected error for missing url")
+	}
+	if _, err := NewSender("carrier-pigeon", nil); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+
+	RegisterSender("test-custom", func(settings map[string]string) (Sender, error) {
+		return namedSender(settings["name"]), nil
+	})
+	custom, err := NewSender("test-custom", map[string]string{"name": "mine"})
+	if err != nil || custom.Name() != "mine" {
+		t.Errorf("custom sender not built: %v, %v", custom, err)
+	}
+}
+
+// slowPublisher blocks until released and records the lists it publishes
+type slowPublisher struct {
+	release   chan struct{}
+	published chan int
+}
+
+func (p *slowPublisher) Name() string { return "slow" }
+
+func (p *slowPublisher) Publish(ctx context.Context, opps []arb.Opportunity) error {
+	<-p.release
+	p.published <- len(opps)
+	return nil
+}
+
+func TestPublishUpdatesKeepsLatest(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := &slowPublisher{release: make(chan struct{}), published: make(chan int, 10)}
+	hook := PublishUpdates(ctx, p, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	hook(make([]arb.Opportunity, 1)) // Picked up, blocks in Publish
+	time.Sleep(20 * time.Millisecond)
+	hook(make([]arb.Opportunity, 2)) // Superseded before it is published
+	hook(make([]arb.Opportunity, 3))
+
+	close(p.release)
+	if got := <-p.published; got != 1 {
+		t.Fatalf("first publish should carry 1 opportunity, got %d", got)
+	}
+	if got := <-p.published; got != 3 {
+		t.Errorf("second publish should carry the latest list (3), got %d", got)
+	}
+}
+
+func TestMQTTPublisherSettings(t *testing.T) {
+	p, err := NewPublisher("mqtt", map[string]string{"broker": "tcp://localhost:1883", "qos": "1", "retain": "false", "topic": "home/arb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := p.(*MQTTPublisher)
+	if m.QoS != 1 || m.Retain || m.Topic != "home/arb" || !strings.HasPrefix(m.Options.ClientID, "arb-ws-") {
+		t.Errorf("unexpected publisher %+v", m)
+	}
+
+	for _, settings := range []map[string]string{
+		{},
+		{"broker": "tcp://localhost:1883", "qos": "2"},
+		{"broker": "tcp://localhost:1883", "retain": "maybe"},
+	} {
+		if _, err := NewPublisher("mqtt", settings); err == nil {
+			t.Errorf("settings %v accepted", settings)
+		}
+	}
+}
+
+func TestSenderChecks(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if strings.Contains(r.URL.Path, "revoked") {
+			http.Error(w, `{"ok":false,"description":"Unauthorized"}`, http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if err := (&WebhookSender{URL: srv.URL + "/hook"}).Check(ctx); err != nil {
+		t.Errorf("webhook check: %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("webhook check must not post, got %v", paths)
+	}
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	if err := (&SlackSender{WebhookURL: closed.URL}).Check(ctx); err == nil {
+		t.Error("unreachable webhook passed the check")
+	}
+
+	tg := &TelegramSender{BotToken: "123:abc", BaseURL: srv.URL, Client: srv.Client()}
+	if err := tg.Check(ctx); err != nil || paths[0] != "/bot123:abc/getMe" {
+		t.Errorf("telegram check: %v, paths %v", err, paths)
+	}
+	tg.BotToken = "revoked"
+	if err := tg.Check(ctx); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected the rejected token to fail, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// Publisher receives the full opportunity list after every engine cycle,
+// for delivery targets that mirror state (message buses, dashboards) rather
+// than alert on new opportunities
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, opps []arb.Opportunity) error
+}
+
+// SenderFactory builds a Sender from deployment settings
+type SenderFactory func(settings map[string]string) (Sender, error)
+
+// PublisherFactory builds a Publisher from deployment settings
+type PublisherFactory func(settings map[string]string) (Publisher, error)
+
+var (
+	registryMu sync.RWMutex
+	senders    = make(map[string]SenderFactory)
+	publishers = make(map[string]PublisherFactory)
+)
+
+// RegisterSender makes a sender kind available to NewSender. Builds add
+// proprietary destinations by registering them from an init function in
+// their own file; registering a kind twice replaces it.
+func RegisterSender(kind string, f SenderFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	senders[kind] = f
+}
+
+// RegisterPublisher makes a publisher kind available to NewPublisher
+func RegisterPublisher(kind string, f PublisherFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	publishers[kind] = f
+}
+
+// NewSender builds a registered sender kind
+func NewSender(kind string, settings map[string]string) (Sender, error) {
+	registryMu.RLock()
+	f, ok := senders[kind]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown sender kind %q (registered: %v)", kind, SenderKinds())
+	}
+	return f(settings)
+}
+
+// NewPublisher builds a registered publisher kind
+func NewPublisher(kind string, settings map[string]string) (Publisher, error) {
+	registryMu.RLock()
+	f, ok := publishers[kind]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown publisher kind %q", kind)
+	}
+	return f(settings)
+}
+
+// ParseSpec splits a "kind?key=value&key=value" target spec into its kind
+// and settings; values are URL-query encoded
+func ParseSpec(spec string) (string, map[string]string, error) {
+	kind, query, _ := strings.Cut(strings.TrimSpace(spec), "?")
+	if kind == "" {
+		return "", nil, fmt.Errorf("invalid target spec %q: missing kind", spec)
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid target spec %q: %w", spec, err)
+	}
+	settings := make(map[string]string, len(values))
+	for k := range values {
+		settings[k] = values.Get(k)
+	}
+	return kind, settings, nil
+}
+
+// SenderKinds lists the registered sender kinds
+func SenderKinds() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	kinds := make([]string, 0, len(senders))
+	for k := range senders {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// The built-in senders register like any other
+func init() {
+	client := &http.Client{}
+
+	RegisterSender("webhook", func(settings map[string]string) (Sender, error) {
+		if settings["url"] == "" {
+			return nil, fmt.Errorf("webhook sender requires url")
+		}
+		return &WebhookSender{URL: settings["url"], Client: client}, nil
+	})
+	RegisterSender("slack", func(settings map[string]string) (Sender, error) {
+		if settings["webhook_url"] == "" {
+			return nil, fmt.Errorf("slack sender requires webhook_url")
+		}
+		return &SlackSender{WebhookURL: settings["webhook_url"], Client: client}, nil
+	})
+	RegisterSender("telegram", func(settings map[string]string) (Sender, error) {
+		if settings["bot_token"] == "" || settings["chat_id"] == "" {
+			return nil, fmt.Errorf("telegram sender requires bot_token and chat_id")
+		}
+		return &TelegramSender{BotToken: settings["bot_token"], ChatID: settings["chat_id"], Client: client}, nil
+	})
+}
+
+// PublishUpdates returns an arb.Engine.OnUpdate hook that hands each
+// opportunity list to p on its own goroutine. Only the latest list is kept:
+// if p is still publishing when new cycles complete, intermediate lists are
+// skipped rather than queued, so a slow publisher never stalls the engine.
+func PublishUpdates(ctx context.Context, p Publisher, timeout time.Duration, logger *slog.Logger) func([]arb.Opportunity) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	latest := make(chan []arb.Opportunity, 1)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case opps := <-latest:
+				pubCtx, cancel := context.WithTimeout(ctx, timeout)
+				start := time.Now()
+				err := p.Publish(pubCtx, opps)
+				cancel()
+				metrics.ObserveNotificationLatency(p.Name(), time.Since(start).Seconds())
+				if err != nil {
+					metrics.RecordNotification(p.Name(), "failed")
+					logger.Warn("publish failed", "publisher", p.Name(), "error", err)
+					continue
+				}
+				metrics.RecordNotification(p.Name(), "sent")
+			}
+		}
+	}()
+
+	return func(opps []arb.Opportunity) {
+		// Hooks must not retain the engine's slice
+		snapshot := make([]arb.Opportunity, len(opps))
+		copy(snapshot, opps)
+
+		// Single producer (the compute goroutine), so drain-then-send can't block
+		select {
+		case <-latest:
+		default:
+		}
+		latest <- snapshot
+	}
+}
@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// WebhookSender POSTs the notification as JSON to an arbitrary URL
+type WebhookSender struct {
+	URL    string
+	Client *http.Client
+}
+
+// Name implements Sender
+func (s *WebhookSender) Name() string { return "webhook" }
+
+// Send implements Sender
+func (s *WebhookSender) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, s.Client, s.URL, n)
+}
+
+// Check implements Checker by connecting to the webhook's host
+func (s *WebhookSender) Check(ctx context.Context) error {
+	return dialURL(ctx, s.URL)
+}
+
+// SlackSender posts to a Slack incoming webhook
+type SlackSender struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+// Name implements Sender
+func (s *SlackSender) Name() string { return "slack" }
+
+// Send implements Sender
+func (s *SlackSender) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, s.Client, s.WebhookURL, map[string]string{
+		"text": "*" + n.Title + "*\n" + n.Text,
+	})
+}
+
+// Check implements Checker by connecting to the webhook's host
+func (s *SlackSender) Check(ctx context.Context) error {
+	return dialURL(ctx, s.WebhookURL)
+}
+
+// TelegramSender sends messages through the Telegram Bot API
+type TelegramSender struct {
+	BotToken string
+	ChatID   string
+	BaseURL  string // Defaults to https://api.telegram.org
+	Client   *http.Client
+}
+
+// Name implements Sender
+func (s *TelegramSender) Name() string { return "telegram" }
+
+// Send implements Sender
+func (s *TelegramSender) Send(ctx context.Context, n Notification) error {
+	return postJSON(ctx, s.Client, s.methodURL("sendMessage"), map[string]string{
+		"chat_id": s.ChatID,
+		"text":    n.Title + "\n" + n.Text,
+	})
+}
+
+// Check implements Checker by calling getMe, which also verifies the token
+func (s *TelegramSender) Check(ctx context.Context) error {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.methodURL("getMe"), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The URL carries the bot token; keep it out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("get me: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// methodURL is the Bot API URL of a method
+func (s *TelegramSender) methodURL(method string) string {
+	base := s.BaseURL
+	if base == "" {
+		base = "https://api.telegram.org"
+	}
+	return base + "/bot" + s.BotToken + "/" + method
+}
+
+// dialURL opens and closes a connection to rawURL's host, completing the
+// TLS handshake for https
+func dialURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	addr := net.JoinHostPort(u.Hostname(), port)
+
+	var conn net.Conn
+	if u.Scheme == "https" {
+		conn, err = (&tls.Dialer{}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect %s: %w", addr, err)
+	}
+	return conn.Close()
+}
+
+// postJSON sends body as JSON and treats any non-2xx response as an error
+func postJSON(ctx context.Context, client *http.Client, url string, body interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
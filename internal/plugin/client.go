@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/lifecycle"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/notify"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+const (
+	// handshakeTimeout bounds how long a plugin may take to print its handshake
+	handshakeTimeout = 10 * time.Second
+	// lifecycleTimeout bounds each lifecycle call
+	lifecycleTimeout = 10 * time.Second
+)
+
+// Plugin is a running plugin process. It implements notify.Sender and
+// notify.Publisher; check CanNotify/CanPublish before registering it as either.
+type Plugin struct {
+	name         string
+	cmd          *exec.Cmd
+	stdin        io.WriteCloser
+	conn         *grpc.ClientConn
+	capabilities []string
+	logger       *slog.Logger
+}
+
+// Launch starts the plugin executable at path and connects to it. The
+// process is killed when ctx is done.
+func Launch(ctx context.Context, name, path string, args []string, logger *slog.Logger) (*Plugin, error) {
+	cmd := exec.CommandContext(ctx, path, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("stderr pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start plugin %s: %w", name, err)
+	}
+
+	logger = logger.With("plugin", name)
+	go forwardLog(stderr, logger)
+
+	p := &Plugin{name: name, cmd: cmd, stdin: stdin, logger: logger}
+	addr, err := p.handshake(stdout)
+	if err != nil {
+		p.abort()
+		return nil, err
+	}
+
+	p.conn, err = grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	)
+	if err != nil {
+		p.abort()
+		return nil, fmt.Errorf("connect plugin %s: %w", name, err)
+	}
+
+	logger.Info("plugin started", "addr", addr, "capabilities", p.capabilities, "pid", cmd.Process.Pid)
+	return p, nil
+}
+
+// handshake reads and validates the plugin's handshake line, returning its address
+func (p *Plugin) handshake(stdout io.Reader) (string, error) {
+	lines := make(chan string, 1)
+	reader := bufio.NewReader(stdout)
+	go func() {
+		line, _ := reader.ReadString('\n')
+		lines <- strings.TrimSpace(line)
+		// Anything else the plugin prints to stdout is logged
+		forwardLog(reader, p.logger)
+	}()
+
+	var line string
+	select {
+	case line = <-lines:
+	case <-time.After(handshakeTimeout):
+		return "", fmt.Errorf("plugin %s: no handshake within %s", p.name, handshakeTimeout)
+	}
+
+	parts := strings.Split(line, "|")
+	if len(parts) != 5 || parts[0] != handshakeMagic {
+		return "", fmt.Errorf("plugin %s: invalid handshake %q", p.name, line)
+	}
+	if parts[1] != ProtocolVersion {
+		return "", fmt.Errorf("plugin %s: unsupported protocol version %s (want %s)", p.name, parts[1], ProtocolVersion)
+	}
+	if parts[2] != "tcp" {
+		return "", fmt.Errorf("plugin %s: unsupported network %q", p.name, parts[2])
+	}
+	for _, c := range strings.Split(parts[4], ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			p.capabilities = append(p.capabilities, c)
+		}
+	}
+	return parts[3], nil
+}
+
+// Name implements notify.Sender and notify.Publisher
+func (p *Plugin) Name() string { return "plugin:" + p.name }
+
+// CanNotify reports whether the plugin accepts notifications
+func (p *Plugin) CanNotify() bool { return slices.Contains(p.capabilities, CapabilityNotify) }
+
+// CanPublish reports whether the plugin accepts opportunity lists
+func (p *Plugin) CanPublish() bool { return slices.Contains(p.capabilities, CapabilityPublish) }
+
+// CanObserveLifecycle reports whether the plugin takes lifecycle events
+func (p *Plugin) CanObserveLifecycle() bool {
+	return slices.Contains(p.capabilities, CapabilityLifecycle)
+}
+
+// Lifecycle reports a lifecycle event to the plugin
+func (p *Plugin) Lifecycle(ctx context.Context, event string, pairs []arb.MarketPair) error {
+	ctx, cancel := context.WithTimeout(ctx, lifecycleTimeout)
+	defer cancel()
+	return p.conn.Invoke(ctx, "/"+serviceName+"/Lifecycle", &LifecycleRequest{Event: event, Pairs: pairs}, &Empty{})
+}
+
+// RegisterLifecycle registers the plugin for every lifecycle event. A
+// plugin failing bootstrap_complete aborts startup like any other hook.
+func (p *Plugin) RegisterLifecycle(hooks *lifecycle.Hooks) {
+	hooks.OnBootstrapComplete(p.Name(), func(ctx context.Context, pairs []arb.MarketPair) error {
+		return p.Lifecycle(ctx, lifecycle.EventBootstrapComplete, pairs)
+	})
+	hooks.OnPairsRefreshed(p.Name(), func(ctx context.Context, pairs []arb.MarketPair) error {
+		return p.Lifecycle(ctx, lifecycle.EventPairsRefreshed, pairs)
+	})
+	hooks.OnShutdown(p.Name(), func(ctx context.Context) error {
+		return p.Lifecycle(ctx, lifecycle.EventShutdown, nil)
+	})
+}
+
+// Send implements notify.Sender
+func (p *Plugin) Send(ctx context.Context, n notify.Notification) error {
+	return p.conn.Invoke(ctx, "/"+serviceName+"/Notify", &NotifyRequest{Notification: n}, &Empty{})
+}
+
+// Publish implements notify.Publisher
+func (p *Plugin) Publish(ctx context.Context, opps []arb.Opportunity) error {
+	return p.conn.Invoke(ctx, "/"+serviceName+"/Publish", &PublishRequest{Opportunities: opps}, &Empty{})
+}
+
+// Close disconnects and asks the plugin to exit by closing its stdin,
+// killing it if it hasn't exited within five seconds
+func (p *Plugin) Close() error {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- p.cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		p.kill()
+		return <-done
+	}
+}
+
+// kill terminates the process immediately
+func (p *Plugin) kill() {
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+}
+
+// abort kills a plugin that failed to start and reaps it
+func (p *Plugin) abort() {
+	p.kill()
+	p.cmd.Wait()
+}
+
+// forwardLog relays a plugin's output lines to the server log
+func forwardLog(r io.Reader, logger *slog.Logger) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		logger.Info("plugin output", "line", scanner.Text())
+	}
+}
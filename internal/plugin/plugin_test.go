@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/notify"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// echoHandler fails notifications whose title contains "fail" and writes
+// everything else to stderr, which the host forwards to its log
+type echoHandler struct{}
+
+func (echoHandler) Notify(ctx context.Context, n notify.Notification) error {
+	if strings.Contains(n.Title, "fail") {
+		return errors.New("rejected " + n.Title)
+	}
+	os.Stderr.WriteString("notify " + n.Title + "\n")
+	return nil
+}
+
+func (echoHandler) Publish(ctx context.Context, opps []arb.Opportunity) error {
+	os.Stderr.WriteString("publish " + opps[0].ID + "\n")
+	return nil
+}
+
+func (echoHandler) Lifecycle(ctx context.Context, event string, pairs []arb.MarketPair) error {
+	if event == "" {
+		return errors.New("missing event")
+	}
+	os.Stderr.WriteString("lifecycle " + event + "\n")
+	return nil
+}
+
+// TestMain lets the test binary act as the plugin process
+func TestMain(m *testing.M) {
+	if os.Getenv("ARBWS_TEST_PLUGIN") == "1" {
+		if err := Serve(echoHandler{}, CapabilityNotify, CapabilityLifecycle); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestLaunchAndCall(t *testing.T) {
+	t.Setenv("ARBWS_TEST_PLUGIN", "1")
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	p, err := Launch(ctx, "echo", os.Args[0], nil, logger)
+	if err != nil {
+		t.Fatalf("Launch: %v", err)
+	}
+	defer p.Close()
+
+	if !p.CanNotify() || p.CanPublish() {
+		t.Errorf("unexpected capabilities %v", p.capabilities)
+	}
+	if p.Name() != "plugin:echo" {
+		t.Errorf("unexpected name %q", p.Name())
+	}
+
+	if err := p.Send(ctx, notify.Notification{Title: "hello"}); err != nil {
+		t.Errorf("Send: %v", err)
+	}
+	err = p.Send(ctx, notify.Notification{Title: "please fail"})
+	if err == nil || !strings.Contains(err.Error(), "rejected please fail") {
+		t.Errorf("expected plugin error to propagate, got %v", err)
+	}
+	if err := p.Publish(ctx, []arb.Opportunity{{ID: "abc"}}); err != nil {
+		t.Errorf("Publish: %v", err)
+	}
+
+	if !p.CanObserveLifecycle() {
+		t.Error("expected lifecycle capability")
+	}
+	if err := p.Lifecycle(ctx, "bootstrap_complete", []arb.MarketPair{{KalshiTicker: "K"}}); err != nil {
+		t.Errorf("Lifecycle: %v", err)
+	}
+	if err := p.Lifecycle(ctx, "", nil); err == nil {
+		t.Error("expected plugin error to propagate")
+	}
+}
+
+func TestLaunchRejectsBadHandshake(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	_, err := Launch(ctx, "echo", "/bin/echo", []string{"not a handshake"}, logger)
+	if err == nil || !strings.Contains(err.Error(), "invalid handshake") {
+		t.Errorf("expected invalid handshake error, got %v", err)
+	}
+}
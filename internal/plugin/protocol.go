@@ -0,0 +1,70 @@
+// Package plugin runs notification and publishing targets as external
+// processes, so proprietary delivery code can live outside this repository.
+//
+// A plugin is an executable launched by the server. On start it listens for
+// gRPC on a loopback address and writes one handshake line to stdout:
+//
+//	ARBWS-PLUGIN|1|tcp|127.0.0.1:40123|notify,publish
+//
+// (magic, protocol version, network, address, capabilities). The server then
+// calls the arbws.plugin.v1.Plugin service with the "json" gRPC codec, so a
+// plugin needs no generated code in any language:
+//
+//	/arbws.plugin.v1.Plugin/Notify   {"notification": {...}}            → {}
+//	/arbws.plugin.v1.Plugin/Publish  {"opportunities": [{...}, ...]}    → {}
+//	/arbws.plugin.v1.Plugin/Lifecycle {"event": "...", "pairs": [...]}  → {}
+//
+// Lifecycle is called only on plugins declaring the "lifecycle" capability,
+// with the events of the lifecycle package: bootstrap_complete with every
+// monitored pair, pairs_refreshed with pairs added later, and shutdown.
+//
+// The plugin should exit when its stdin is closed. Go plugins can use Serve.
+package plugin
+
+import (
+	"encoding/json"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/notify"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+const (
+	// ProtocolVersion is the handshake protocol version
+	ProtocolVersion = "1"
+
+	handshakeMagic = "ARBWS-PLUGIN"
+	serviceName    = "arbws.plugin.v1.Plugin"
+)
+
+// Capabilities a plugin declares in its handshake
+const (
+	CapabilityNotify    = "notify"
+	CapabilityPublish   = "publish"
+	CapabilityLifecycle = "lifecycle"
+)
+
+// NotifyRequest carries one notification to a plugin
+type NotifyRequest struct {
+	Notification notify.Notification `json:"notification"`
+}
+
+// PublishRequest carries the current opportunity list to a plugin
+type PublishRequest struct {
+	Opportunities []arb.Opportunity `json:"opportunities"`
+}
+
+// LifecycleRequest reports a server lifecycle event to a plugin
+type LifecycleRequest struct {
+	Event string           `json:"event"`
+	Pairs []arb.MarketPair `json:"pairs,omitempty"`
+}
+
+// Empty is the response of every plugin call
+type Empty struct{}
+
+// jsonCodec is the gRPC codec for plugin calls
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return "json" }
@@ -0,0 +1,107 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/notify"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+// Handler implements a plugin. A plugin that only notifies or only publishes
+// returns nil from the other method and omits its capability from Serve.
+type Handler interface {
+	Notify(ctx context.Context, n notify.Notification) error
+	Publish(ctx context.Context, opps []arb.Opportunity) error
+}
+
+// LifecycleHandler is implemented by plugins declaring CapabilityLifecycle
+type LifecycleHandler interface {
+	Lifecycle(ctx context.Context, event string, pairs []arb.MarketPair) error
+}
+
+// Serve runs h as a plugin process: it listens on loopback, writes the
+// handshake to stdout and serves until stdin is closed by the host
+func Serve(h Handler, capabilities ...string) error {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	srv := grpc.NewServer(grpc.ForceServerCodec(jsonCodec{}))
+	srv.RegisterService(&serviceDesc, h)
+
+	fmt.Fprintf(os.Stdout, "%s|%s|tcp|%s|%s\n", handshakeMagic, ProtocolVersion, lis.Addr(), strings.Join(capabilities, ","))
+
+	// The host closes stdin on shutdown (or dies); stop with it
+	go func() {
+		io.Copy(io.Discard, os.Stdin)
+		srv.GracefulStop()
+	}()
+
+	return srv.Serve(lis)
+}
+
+// serviceDesc describes the plugin service without generated code
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*Handler)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Notify",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var req NotifyRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				if err := srv.(Handler).Notify(ctx, req.Notification); err != nil {
+					return nil, status.Error(codes.Unknown, err.Error())
+				}
+				return &Empty{}, nil
+			},
+		},
+		{
+			MethodName: "Publish",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var req PublishRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				if err := srv.(Handler).Publish(ctx, req.Opportunities); err != nil {
+					return nil, status.Error(codes.Unknown, err.Error())
+				}
+				return &Empty{}, nil
+			},
+		},
+		{
+			MethodName: "Lifecycle",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+				var req LifecycleRequest
+				if err := dec(&req); err != nil {
+					return nil, err
+				}
+				h, ok := srv.(LifecycleHandler)
+				if !ok {
+					return nil, status.Error(codes.Unimplemented, "plugin does not handle lifecycle events")
+				}
+				if err := h.Lifecycle(ctx, req.Event, req.Pairs); err != nil {
+					return nil, status.Error(codes.Unknown, err.Error())
+				}
+				return &Empty{}, nil
+			},
+		},
+	},
+}
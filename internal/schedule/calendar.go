@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar holds the trading hours of Kalshi series that do not trade around
+// the clock, such as those on financial indices, and the exchange holidays
+// on which they do not trade at all. Series without hours always trade.
+type Calendar struct {
+	hours    map[string]*Schedule // series ticker -> trading windows
+	holidays map[string]bool      // "2006-01-02" dates in location
+	location *time.Location
+}
+
+// ParseCalendar builds a calendar from a semicolon-separated list of series
+// hours such as "KXINX=mon/tue/wed/thu/fri 09:30-16:00;KXBTCD=..." (windows
+// as in Parse) and a list of holidays as YYYY-MM-DD dates, both in tz.
+// Empty specs yield a calendar on which every series always trades.
+func ParseCalendar(hours string, holidays []string, tz string) (*Calendar, error) {
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Calendar{hours: make(map[string]*Schedule), holidays: make(map[string]bool), location: loc}
+	for _, part := range strings.Split(hours, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		series, spec, ok := strings.Cut(part, "=")
+		series = strings.ToUpper(strings.TrimSpace(series))
+		if !ok || series == "" {
+			return nil, fmt.Errorf("invalid trading hours %q: expected SERIES=windows", part)
+		}
+		if _, dup := c.hours[series]; dup {
+			return nil, fmt.Errorf("trading hours for %s given twice", series)
+		}
+		s, err := parse(spec, loc)
+		if err != nil {
+			return nil, fmt.Errorf("trading hours for %s: %w", series, err)
+		}
+		if len(s.windows) == 0 {
+			return nil, fmt.Errorf("trading hours for %s: no windows", series)
+		}
+		c.hours[series] = s
+	}
+	for _, d := range holidays {
+		day, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(d), loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: expected YYYY-MM-DD", d)
+		}
+		c.holidays[day.Format(time.DateOnly)] = true
+	}
+	return c, nil
+}
+
+// Open reports whether series trades at t: always for series without
+// trading hours, otherwise inside their windows on days that are not
+// holidays. A nil calendar is always open.
+func (c *Calendar) Open(series string, t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	s, ok := c.hours[strings.ToUpper(series)]
+	if !ok {
+		return true
+	}
+	if c.holidays[t.In(c.location).Format(time.DateOnly)] {
+		return false
+	}
+	return s.Active(t)
+}
+
+// Len returns the number of series with trading hours
+func (c *Calendar) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.hours)
+}
@@ -0,0 +1,65 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarOpen(t *testing.T) {
+	c, err := ParseCalendar("KXINX=mon/tue/wed/thu/fri 09:30-16:00; kxnasdaq100=mon/tue/wed/thu/fri 09:30-16:00",
+		[]string{"2026-11-26"}, "America/New_York")
+	if err != nil {
+		t.Fatalf("ParseCalendar: %v", err)
+	}
+
+	ny, _ := time.LoadLocation("America/New_York")
+	at := func(day, hour, min int) time.Time {
+		// 2026-11-25 is a Wednesday, 2026-11-26 Thanksgiving
+		return time.Date(2026, 11, day, hour, min, 0, 0, ny)
+	}
+
+	tests := []struct {
+		name   string
+		series string
+		t      time.Time
+		want   bool
+	}{
+		{name: "session", series: "KXINX", t: at(25, 10, 0), want: true},
+		{name: "overnight", series: "KXINX", t: at(25, 20, 0), want: false},
+		{name: "weekend", series: "KXINX", t: at(28, 12, 0), want: false},
+		{name: "holiday", series: "KXINX", t: at(26, 12, 0), want: false},
+		{name: "series case", series: "KXNASDAQ100", t: at(25, 10, 0), want: true},
+		{name: "round the clock", series: "KXBTC", t: at(26, 3, 0), want: true},
+		{name: "utc instant converted", series: "KXINX", t: time.Date(2026, 11, 25, 15, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Open(tt.series, tt.t); got != tt.want {
+				t.Errorf("Open(%s, %s) = %v, want %v", tt.series, tt.t, got, tt.want)
+			}
+		})
+	}
+
+	var none *Calendar
+	if !none.Open("KXINX", at(25, 20, 0)) {
+		t.Error("nil calendar should always be open")
+	}
+}
+
+func TestParseCalendarErrors(t *testing.T) {
+	for _, tt := range []struct {
+		hours    string
+		holidays []string
+	}{
+		{hours: "KXINX"},
+		{hours: "=09:30-16:00"},
+		{hours: "KXINX="},
+		{hours: "KXINX=fun 09:30-16:00"},
+		{hours: "KXINX=09:30-16:00;kxinx=10:00-11:00"},
+		{holidays: []string{"11/26/2026"}},
+	} {
+		if _, err := ParseCalendar(tt.hours, tt.holidays, ""); err == nil {
+			t.Errorf("ParseCalendar(%q, %q) expected error", tt.hours, tt.holidays)
+		}
+	}
+}
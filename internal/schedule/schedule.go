@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const watchInterval = 5 * time.Second
+
+// Window is a recurring time-of-day range, optionally limited to certain weekdays.
+// Windows whose end is before their start wrap past midnight.
+type Window struct {
+	Days  []time.Weekday // Empty means every day; refers to the day the window starts
+	Start time.Duration  // Offset from local midnight
+	End   time.Duration
+}
+
+// Schedule is a set of recurring windows in one time zone
+type Schedule struct {
+	windows  []Window
+	location *time.Location
+	spec     string
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Parse builds a schedule from a comma-separated list of windows such as
+// "thu 03:00-05:00" or "mon/wed 23:30-00:30" or "02:00-02:15" (daily).
+// An empty spec yields a schedule that is never active.
+func Parse(spec, tz string) (*Schedule, error) {
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+	return parse(spec, loc)
+}
+
+func loadLocation(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("load location %q: %w", tz, err)
+	}
+	return loc, nil
+}
+
+func parse(spec string, loc *time.Location) (*Schedule, error) {
+	s := &Schedule{location: loc, spec: spec}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		w, err := parseWindow(part)
+		if err != nil {
+			return nil, err
+		}
+		s.windows = append(s.windows, w)
+	}
+	return s, nil
+}
+
+func parseWindow(s string) (Window, error) {
+	var w Window
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 2 {
+		for _, d := range strings.Split(fields[0], "/") {
+			day, ok := weekdays[d]
+			if !ok {
+				return w, fmt.Errorf("invalid weekday %q in window %q", d, s)
+			}
+			w.Days = append(w.Days, day)
+		}
+		fields = fields[1:]
+	}
+	if len(fields) != 1 {
+		return w, fmt.Errorf("invalid window %q", s)
+	}
+
+	start, end, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return w, fmt.Errorf("invalid window %q: expected HH:MM-HH:MM", s)
+	}
+	var err error
+	if w.Start, err = parseClock(start); err != nil {
+		return w, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	if w.End, err = parseClock(end); err != nil {
+		return w, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	return w, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// String returns the spec the schedule was parsed from
+func (s *Schedule) String() string {
+	if s == nil {
+		return ""
+	}
+	return s.spec
+}
+
+// Active reports whether t falls inside any window. A nil schedule is never active.
+func (s *Schedule) Active(t time.Time) bool {
+	_, ok := s.current(t)
+	return ok
+}
+
+// current returns the end of the window containing t, if any
+func (s *Schedule) current(t time.Time) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+
+	t = t.In(s.location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location)
+
+	for _, w := range s.windows {
+		// A wrapping window may have started yesterday
+		for _, dayStart := range []time.Time{midnight, midnight.AddDate(0, 0, -1)} {
+			if !w.onDay(dayStart.Weekday()) {
+				continue
+			}
+			start := dayStart.Add(w.Start)
+			end := dayStart.Add(w.End)
+			if w.End <= w.Start {
+				end = end.AddDate(0, 0, 1)
+			}
+			if !t.Before(start) && t.Before(end) {
+				return end, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (w Window) onDay(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if day == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Status describes the schedule at a point in time
+type Status struct {
+	Schedule string     `json:"schedule"`
+	Active   bool       `json:"active"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+// StatusAt reports whether the schedule is active at t and when the current window ends
+func (s *Schedule) StatusAt(t time.Time) Status {
+	st := Status{Schedule: s.String()}
+	if end, ok := s.current(t); ok {
+		st.Active = true
+		st.EndsAt = &end
+	}
+	return st
+}
+
+// Watch polls the schedule until ctx is done, calling onStart when a window
+// opens and onEnd when it closes. If a window is already active when Watch
+// starts, onStart is called immediately.
+func (s *Schedule) Watch(ctx context.Context, onStart, onEnd func()) {
+	if s == nil || len(s.windows) == 0 {
+		return
+	}
+
+	active := false
+	check := func() {
+		now := s.Active(time.Now())
+		switch {
+		case now && !active:
+			onStart()
+		case !now && active:
+			onEnd()
+		}
+		active = now
+	}
+
+	check()
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
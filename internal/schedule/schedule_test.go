@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleActive(t *testing.T) {
+	s, err := Parse("thu 03:00-05:00, 23:30-00:15", "America/New_York")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	ny, _ := time.LoadLocation("America/New_York")
+	at := func(day, hour, min int) time.Time {
+		// 2026-03-05 is a Thursday
+		return time.Date(2026, 3, day, hour, min, 0, 0, ny)
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		expected bool
+	}{
+		{name: "thursday inside", t: at(5, 4, 0), expected: true},
+		{name: "thursday start inclusive", t: at(5, 3, 0), expected: true},
+		{name: "thursday end exclusive", t: at(5, 5, 0), expected: false},
+		{name: "wednesday same time", t: at(4, 4, 0), expected: false},
+		{name: "daily wrap before midnight", t: at(2, 23, 45), expected: true},
+		{name: "daily wrap after midnight", t: at(3, 0, 10), expected: true},
+		{name: "daily wrap ended", t: at(3, 0, 20), expected: false},
+		{name: "utc instant converted", t: time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Active(tt.t); got != tt.expected {
+				t.Errorf("Active(%s) = %v, want %v", tt.t, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"fun 03:00-04:00", "03:00", "3am-4am", "thu fri 03:00-04:00"} {
+		if _, err := Parse(spec, ""); err == nil {
+			t.Errorf("Parse(%q) expected error", spec)
+		}
+	}
+
+	s, err := Parse("", "")
+	if err != nil || s.Active(time.Now()) {
+		t.Errorf("empty schedule should parse and never be active")
+	}
+}
@@ -0,0 +1,68 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// AgeSource decrypts age-encrypted files with the identities in an identity
+// file. The reference is the encrypted file's path, optionally with "#field";
+// both binary and ASCII-armored files are accepted.
+type AgeSource struct {
+	identities []age.Identity
+}
+
+// NewAgeSource loads identities from identityFile
+func NewAgeSource(identityFile string) (*AgeSource, error) {
+	if identityFile == "" {
+		return nil, fmt.Errorf("age identity file is required")
+	}
+
+	f, err := os.Open(identityFile)
+	if err != nil {
+		return nil, fmt.Errorf("open age identity file: %w", err)
+	}
+	defer f.Close()
+
+	identities, err := age.ParseIdentities(f)
+	if err != nil {
+		return nil, fmt.Errorf("parse age identities: %w", err)
+	}
+	return &AgeSource{identities: identities}, nil
+}
+
+// Fetch implements Source
+func (s *AgeSource) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	path, field := splitRef(ref)
+
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read encrypted secret: %w", err)
+	}
+
+	var src io.Reader = bytes.NewReader(ciphertext)
+	if bytes.HasPrefix(bytes.TrimSpace(ciphertext), []byte(armor.Header)) {
+		src = armor.NewReader(src)
+	}
+
+	r, err := age.Decrypt(src, s.identities...)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", path, err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		Zero(data)
+		return nil, fmt.Errorf("decrypt %s: %w", path, err)
+	}
+
+	if field != "" {
+		return selectField(data, field)
+	}
+	return data, nil
+}
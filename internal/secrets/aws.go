@@ -0,0 +1,56 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSSource reads secrets from AWS Secrets Manager using the SDK's default
+// credential chain (env, shared config, IRSA, instance role). The reference
+// is a secret name or ARN, optionally with "#field".
+type AWSSource struct {
+	client *secretsmanager.Client
+}
+
+// NewAWSSource creates a Secrets Manager source
+func NewAWSSource(ctx context.Context, region string) (*AWSSource, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	return &AWSSource{client: secretsmanager.NewFromConfig(cfg)}, nil
+}
+
+// Fetch implements Source
+func (s *AWSSource) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	id, field := splitRef(ref)
+
+	out, err := s.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(id)})
+	if err != nil {
+		return nil, fmt.Errorf("get secret %s: %w", id, err)
+	}
+
+	var data []byte
+	switch {
+	case out.SecretBinary != nil:
+		data = out.SecretBinary
+	case out.SecretString != nil:
+		data = []byte(*out.SecretString)
+	default:
+		return nil, fmt.Errorf("secret %s has no value", id)
+	}
+
+	if field != "" {
+		return selectField(data, field)
+	}
+	return data, nil
+}
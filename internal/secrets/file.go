@@ -0,0 +1,23 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// FileSource reads secrets from plain files; the reference is a path
+type FileSource struct{}
+
+// Fetch implements Source
+func (FileSource) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	path, field := splitRef(ref)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read secret file: %w", err)
+	}
+	if field != "" {
+		return selectField(data, field)
+	}
+	return data, nil
+}
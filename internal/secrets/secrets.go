@@ -0,0 +1,97 @@
+// Package secrets loads credentials such as the Kalshi private key and
+// Polymarket API secrets from a pluggable backend: plain files, HashiCorp
+// Vault, AWS Secrets Manager, or age-encrypted files.
+//
+// A secret reference is backend-specific (a file path, a Vault path, a
+// Secrets Manager secret ID) and may end in "#field" to select one field of
+// a JSON object secret. Callers should Zero returned buffers once the secret
+// has been parsed.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Provider names accepted by New
+const (
+	ProviderFile  = "file"
+	ProviderVault = "vault"
+	ProviderAWS   = "aws"
+	ProviderAge   = "age"
+)
+
+// Source fetches raw secret material by reference
+type Source interface {
+	Fetch(ctx context.Context, ref string) ([]byte, error)
+}
+
+// Options carries backend-specific settings for New
+type Options struct {
+	VaultAddr       string
+	VaultToken      string
+	AWSRegion       string // Empty uses the SDK's default resolution
+	AgeIdentityFile string
+}
+
+// New creates the Source for the named provider; an empty name means plain files
+func New(ctx context.Context, provider string, opts Options) (Source, error) {
+	switch strings.ToLower(provider) {
+	case "", ProviderFile:
+		return FileSource{}, nil
+	case ProviderVault:
+		return NewVaultSource(opts.VaultAddr, opts.VaultToken)
+	case ProviderAWS:
+		return NewAWSSource(ctx, opts.AWSRegion)
+	case ProviderAge:
+		return NewAgeSource(opts.AgeIdentityFile)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q", provider)
+	}
+}
+
+// Zero overwrites b in place so secret material doesn't linger in memory
+func Zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// splitRef separates a "location#field" reference
+func splitRef(ref string) (location, field string) {
+	if i := strings.LastIndexByte(ref, '#'); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// selectField extracts a string field from a JSON object secret. The input is
+// zeroed; the returned buffer is a fresh copy owned by the caller.
+func selectField(data []byte, field string) ([]byte, error) {
+	defer Zero(data)
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	raw, ok := obj[field]
+	if !ok {
+		return nil, fmt.Errorf("secret has no field %q", field)
+	}
+	defer func() {
+		for _, v := range obj {
+			Zero(v)
+		}
+	}()
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		// Not a string: hand back the raw JSON value (e.g. a nested object)
+		out := make([]byte, len(raw))
+		copy(out, raw)
+		return out, nil
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,133 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+func TestFileSource(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "key.pem")
+	obj := filepath.Join(dir, "creds.json")
+	os.WriteFile(plain, []byte("PEM DATA"), 0o600)
+	os.WriteFile(obj, []byte(`{"apiKey":"k","secret":"s"}`), 0o600)
+
+	ctx := context.Background()
+	src := FileSource{}
+
+	got, err := src.Fetch(ctx, plain)
+	if err != nil || string(got) != "PEM DATA" {
+		t.Errorf("Fetch(plain) = %q, %v", got, err)
+	}
+
+	got, err = src.Fetch(ctx, obj+"#secret")
+	if err != nil || string(got) != "s" {
+		t.Errorf("Fetch(field) = %q, %v", got, err)
+	}
+
+	if _, err := src.Fetch(ctx, obj+"#missing"); err == nil {
+		t.Error("expected error for missing field")
+	}
+}
+
+func TestVaultSourceKVv2(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "tok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/arb" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"kalshi_key":"PEM"},"metadata":{"version":3}}}`))
+	}))
+	defer srv.Close()
+
+	src, err := NewVaultSource(srv.URL, "tok")
+	if err != nil {
+		t.Fatalf("NewVaultSource: %v", err)
+	}
+
+	got, err := src.Fetch(context.Background(), "secret/data/arb#kalshi_key")
+	if err != nil || string(got) != "PEM" {
+		t.Errorf("Fetch = %q, %v", got, err)
+	}
+
+	if _, err := src.Fetch(context.Background(), "secret/data/other#kalshi_key"); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestAgeSource(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("generate identity: %v", err)
+	}
+
+	dir := t.TempDir()
+	idFile := filepath.Join(dir, "identity.txt")
+	os.WriteFile(idFile, []byte(identity.String()+"\n"), 0o600)
+
+	encrypt := func(armored bool) string {
+		var buf bytes.Buffer
+		var dst io.Writer = &buf
+		var armorWriter io.WriteCloser
+		if armored {
+			armorWriter = armor.NewWriter(&buf)
+			dst = armorWriter
+		}
+
+		w, err := age.Encrypt(dst, identity.Recipient())
+		if err != nil {
+			t.Fatalf("encrypt: %v", err)
+		}
+		w.Write([]byte("SECRET KEY"))
+		w.Close()
+		if armorWriter != nil {
+			armorWriter.Close()
+		}
+
+		path := filepath.Join(dir, "secret.age")
+		if armored {
+			path += ".asc"
+		}
+		os.WriteFile(path, buf.Bytes(), 0o600)
+		return path
+	}
+
+	src, err := NewAgeSource(idFile)
+	if err != nil {
+		t.Fatalf("NewAgeSource: %v", err)
+	}
+
+	for _, armored := range []bool{false, true} {
+		got, err := src.Fetch(context.Background(), encrypt(armored))
+		if err != nil || string(got) != "SECRET KEY" {
+			t.Errorf("Fetch(armored=%v) = %q, %v", armored, got, err)
+		}
+	}
+}
+
+func TestZero(t *testing.T) {
+	b := []byte("secret")
+	Zero(b)
+	if !bytes.Equal(b, make([]byte, 6)) {
+		t.Errorf("Zero left %q", b)
+	}
+}
+
+func TestNewUnknownProvider(t *testing.T) {
+	if _, err := New(context.Background(), "keychain", Options{}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
@@ -0,0 +1,82 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultSource reads secrets from a HashiCorp Vault KV engine over its HTTP API.
+// The reference is the API path below /v1, e.g. "secret/data/arb#kalshi_key";
+// both KV v1 and v2 responses are understood.
+type VaultSource struct {
+	addr   string
+	token  string
+	client *http.Client
+}
+
+// NewVaultSource creates a Vault source; addr and token are required
+func NewVaultSource(addr, token string) (*VaultSource, error) {
+	if addr == "" || token == "" {
+		return nil, fmt.Errorf("vault address and token are required")
+	}
+	return &VaultSource{
+		addr:   strings.TrimRight(addr, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Fetch implements Source
+func (s *VaultSource) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	path, field := splitRef(ref)
+	if field == "" {
+		return nil, fmt.Errorf("vault reference %q must select a field with #name", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read vault response: %w", err)
+	}
+	defer Zero(body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d for %s", resp.StatusCode, path)
+	}
+
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("decode vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data alongside metadata
+	data := envelope.Data
+	var v2 struct {
+		Data     json.RawMessage `json:"data"`
+		Metadata json.RawMessage `json:"metadata"`
+	}
+	if json.Unmarshal(data, &v2) == nil && v2.Data != nil && v2.Metadata != nil {
+		Zero(data)
+		data = v2.Data
+	}
+
+	return selectField(data, field)
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// IsWindowsService reports whether the process was started by the Windows
+// service control manager; always false on this platform
+func IsWindowsService() bool {
+	return false
+}
+
+// RunWindowsService is only supported on Windows
+func RunWindowsService(name string, run func(ctx context.Context)) error {
+	return errors.New("windows services are not supported on this platform")
+}
@@ -0,0 +1,90 @@
+// Package service integrates the process with init-system supervision:
+// systemd's sd_notify readiness and watchdog protocol, and the Windows
+// service control manager.
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// sd_notify states
+const (
+	StateReady    = "READY=1"
+	StateStopping = "STOPPING=1"
+	StateWatchdog = "WATCHDOG=1"
+)
+
+// Notify sends a state to the service manager via $NOTIFY_SOCKET.
+// Returns false without error when not running under a notify-aware manager.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+
+	// Abstract namespace sockets are written with a leading '@'
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, fmt.Errorf("dial notify socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, fmt.Errorf("write notify socket: %w", err)
+	}
+	return true, nil
+}
+
+// WatchdogInterval returns the watchdog timeout the service manager expects
+// heartbeats within ($WATCHDOG_USEC), or false when the watchdog is disabled
+// or addressed to another process ($WATCHDOG_PID).
+func WatchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+// RunWatchdog sends watchdog heartbeats at half the configured timeout for as
+// long as healthy reports true. When healthy fails, heartbeats are withheld so
+// the service manager restarts the process. Returns immediately if the
+// watchdog is not enabled.
+func RunWatchdog(ctx context.Context, healthy func() bool, logger *slog.Logger) {
+	timeout, ok := WatchdogInterval()
+	if !ok {
+		return
+	}
+
+	logger.Info("systemd watchdog enabled", "timeout", timeout)
+	ticker := time.NewTicker(timeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !healthy() {
+				logger.Warn("health check failed, withholding watchdog heartbeat")
+				continue
+			}
+			if _, err := Notify(StateWatchdog); err != nil {
+				logger.Warn("watchdog notify failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func listenNotify(t *testing.T) *net.UnixConn {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+func readState(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 256)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read notify socket: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestNotify(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+	if ok, err := Notify(StateReady); ok || err != nil {
+		t.Fatalf("Notify without socket = %v, %v; want false, nil", ok, err)
+	}
+
+	conn := listenNotify(t)
+	ok, err := Notify(StateReady)
+	if !ok || err != nil {
+		t.Fatalf("Notify = %v, %v", ok, err)
+	}
+	if got := readState(t, conn); got != StateReady {
+		t.Errorf("received %q, want %q", got, StateReady)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_USEC", "")
+	if _, ok := WatchdogInterval(); ok {
+		t.Error("expected watchdog disabled without WATCHDOG_USEC")
+	}
+
+	t.Setenv("WATCHDOG_USEC", "30000000")
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()))
+	if d, ok := WatchdogInterval(); !ok || d != 30*time.Second {
+		t.Errorf("WatchdogInterval() = %v, %v; want 30s, true", d, ok)
+	}
+
+	t.Setenv("WATCHDOG_PID", "1")
+	if _, ok := WatchdogInterval(); ok {
+		t.Error("expected watchdog disabled for another pid")
+	}
+}
+
+func TestRunWatchdogWithholdsWhenUnhealthy(t *testing.T) {
+	conn := listenNotify(t)
+	t.Setenv("WATCHDOG_USEC", "40000") // 40ms: heartbeat every 20ms
+	t.Setenv("WATCHDOG_PID", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	healthy := make(chan bool, 1)
+	healthy <- true
+	var state bool
+	check := func() bool {
+		select {
+		case state = <-healthy:
+		default:
+		}
+		return state
+	}
+	go RunWatchdog(ctx, check, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if got := readState(t, conn); got != StateWatchdog {
+		t.Fatalf("received %q, want %q", got, StateWatchdog)
+	}
+
+	// Once unhealthy, no further heartbeats arrive
+	healthy <- false
+	time.Sleep(60 * time.Millisecond)
+	drain := make([]byte, 256)
+	for {
+		conn.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+		if _, err := conn.Read(drain); err != nil {
+			break
+		}
+	}
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if n, err := conn.Read(drain); err == nil {
+		t.Errorf("unexpected heartbeat while unhealthy: %q", drain[:n])
+	}
+}
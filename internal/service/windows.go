@@ -0,0 +1,61 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+
+	"golang.org/x/sys/windows/svc"
+)
+
+// IsWindowsService reports whether the process was started by the Windows
+// service control manager
+func IsWindowsService() bool {
+	ok, err := svc.IsWindowsService()
+	return err == nil && ok
+}
+
+// RunWindowsService runs run under the service control manager. The context
+// passed to run is cancelled when the service is asked to stop or the system
+// shuts down; the service reports stopped once run returns.
+func RunWindowsService(name string, run func(ctx context.Context)) error {
+	return svc.Run(name, &windowsHandler{run: run})
+}
+
+type windowsHandler struct {
+	run func(ctx context.Context)
+}
+
+// Execute implements svc.Handler
+func (h *windowsHandler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	status <- svc.Status{State: svc.StartPending}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.run(ctx)
+	}()
+	status <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for {
+		select {
+		case <-done:
+			status <- svc.Status{State: svc.StopPending}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
@@ -0,0 +1,432 @@
+// Package shadow paper-trades every engine strategy side by side on the same
+// market data. Each strategy gets its own virtual ledger: it "fills" each
+// published opportunity at the quoted prices, marks open positions against
+// current quotes, unwinds when selling both legs beats holding to
+// resolution, and settles once both markets have resolved: at the venues'
+// reported outcomes when a resolution book is set, otherwise at $1 a
+// contract after both close. Every closed position is attributed to its
+// opportunity as a Settlement, net of trading fees, and aggregated by
+// strategy, category and pair confidence. Live execution can serve a single
+// strategy while the rest build comparable track records here.
+package shadow
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+// DefaultSize is the virtual trade size in contracts when Options.Size is unset
+const DefaultSize = 10
+
+// Close reasons
+const (
+	ReasonUnwound = "unwound" // Both legs sold for more than the $1 resolution payout
+	ReasonSettled = "settled" // Held to resolution
+)
+
+// Options configures the shadow ledgers
+type Options struct {
+	Size          float64 // Contracts per virtual trade, capped by the quoted size
+	LiveStrategy  string  // Strategy served by live execution, reported for comparison
+	MaxClosedKept int     // Closed positions kept per strategy for the report; 0 keeps 100
+
+	// Taker fee rates charged on each fill, see arb.TakerFee. 0 charges no
+	// fees.
+	PMFeeRate     float64
+	KalshiFeeRate float64
+}
+
+// Position is one virtual hedged position
+type Position struct {
+	OppID       string     `json:"opp_id"`
+	Strategy    string     `json:"strategy"`
+	Combo       string     `json:"combo"`
+	Category    string     `json:"category"`
+	MatchScore  float64    `json:"match_score"` // Pair confidence
+	PMTitle     string     `json:"pm_title"`
+	Contracts   float64    `json:"contracts"`
+	PMPrice     float64    `json:"pm_price"`     // Entry price of the Polymarket leg
+	KalshiPrice float64    `json:"kalshi_price"` // Entry price of the Kalshi leg
+	OpenedAt    time.Time  `json:"opened_at"`
+	MarkValue   float64    `json:"mark_value"` // Per-contract value of selling both legs now
+	ClosedAt    *time.Time `json:"closed_at,omitempty"`
+	CloseReason string     `json:"close_reason,omitempty"`
+	Fees        float64    `json:"fees"` // Entry fees, plus exit fees once unwound
+	PnL         float64    `json:"pnl"`  // Net of fees; realized once closed, otherwise marked
+
+	pmYes         instrument.ID // Pair key for marking
+	kalshiYes     instrument.ID
+	pmConditionID string // Market keys for resolution outcomes
+	pmTokenYes    string
+	kalshiTicker  string
+	closesAt      time.Time // When both markets have resolved; zero when unknown
+}
+
+// Cost returns the position's entry cost per contract
+func (p *Position) Cost() float64 {
+	return p.PMPrice + p.KalshiPrice
+}
+
+// Record is one strategy's virtual track record
+type Record struct {
+	Strategy      string             `json:"strategy"`
+	Live          bool               `json:"live"` // Served by live execution
+	Trades        int                `json:"trades"`
+	Open          int                `json:"open"`
+	Capital       float64            `json:"capital"`       // USD tied up in open positions
+	VenueCapital  map[string]float64 `json:"venue_capital"` // Capital by venue
+	LockedPnL     float64            `json:"locked_pnl"`    // Open positions' PnL if held to resolution
+	UnrealizedPnL float64            `json:"unrealized_pnl"`
+	RealizedPnL   float64            `json:"realized_pnl"`
+	Positions     []Position         `json:"positions"` // Open, then most recently closed
+}
+
+// Settlement is the final PnL of one closed position, attributed to the
+// opportunity it was opened on
+type Settlement struct {
+	OppID      string    `json:"opp_id"`
+	Strategy   string    `json:"strategy"`
+	Category   string    `json:"category"`
+	MatchScore float64   `json:"match_score"`
+	Combo      string    `json:"combo"`
+	Reason     string    `json:"reason"` // ReasonUnwound or ReasonSettled
+	Contracts  float64   `json:"contracts"`
+	Cost       float64   `json:"cost"`   // Entry cost per contract
+	Payout     float64   `json:"payout"` // Exit value per contract: unwind proceeds or resolution payout
+	Fees       float64   `json:"fees"`
+	PnL        float64   `json:"pnl"` // Contracts × (payout − cost) − fees
+	OpenedAt   time.Time `json:"opened_at"`
+	ClosedAt   time.Time `json:"closed_at"`
+}
+
+// PnLSummary aggregates settlements
+type PnLSummary struct {
+	Trades   int     `json:"trades"`
+	Wins     int     `json:"wins"`      // Settlements with positive PnL
+	GrossPnL float64 `json:"gross_pnl"` // Before fees
+	Fees     float64 `json:"fees"`
+	PnL      float64 `json:"pnl"`
+}
+
+// add folds a settlement into the summary
+func (p *PnLSummary) add(st Settlement) {
+	p.Trades++
+	if st.PnL > 0 {
+		p.Wins++
+	}
+	p.GrossPnL += st.PnL + st.Fees
+	p.Fees += st.Fees
+	p.PnL += st.PnL
+}
+
+// Attribution is the settled PnL since start, in total and grouped by
+// strategy, category and pair confidence bucket (see ConfidenceBucket)
+type Attribution struct {
+	Total        PnLSummary            `json:"total"`
+	ByStrategy   map[string]PnLSummary `json:"by_strategy"`
+	ByCategory   map[string]PnLSummary `json:"by_category"`
+	ByConfidence map[string]PnLSummary `json:"by_confidence"`
+}
+
+// NewAttribution creates empty aggregates
+func NewAttribution() Attribution {
+	return Attribution{
+		ByStrategy:   make(map[string]PnLSummary),
+		ByCategory:   make(map[string]PnLSummary),
+		ByConfidence: make(map[string]PnLSummary),
+	}
+}
+
+// Add folds a settlement into the aggregates
+func (a *Attribution) Add(st Settlement) {
+	a.Total.add(st)
+	addTo(a.ByStrategy, st.Strategy, st)
+	addTo(a.ByCategory, st.Category, st)
+	addTo(a.ByConfidence, ConfidenceBucket(st.MatchScore), st)
+}
+
+func addTo(groups map[string]PnLSummary, key string, st Settlement) {
+	sum := groups[key]
+	sum.add(st)
+	groups[key] = sum
+}
+
+// ConfidenceBucket groups a pair's match score into tenths, e.g. "0.8-0.9"
+func ConfidenceBucket(score float64) string {
+	tenth := min(max(int(score*10), 0), 9)
+	return fmt.Sprintf("%.1f-%.1f", float64(tenth)/10, float64(tenth+1)/10)
+}
+
+// ledger is one strategy's virtual book
+type ledger struct {
+	strategy string
+	open     map[string]*Position // opportunity ID -> position
+	closed   []Position
+	trades   int
+	realized float64
+}
+
+// Shadow runs one ledger per strategy; it is safe for concurrent use
+type Shadow struct {
+	mu          sync.Mutex
+	opts        Options
+	ledgers     map[string]*ledger
+	resolutions *arb.ResolutionBook
+	attribution Attribution
+	onSettle    func(Settlement)
+	logger      *slog.Logger
+}
+
+// New creates empty ledgers for the given strategies
+func New(strategies []string, opts Options, logger *slog.Logger) *Shadow {
+	if opts.Size <= 0 {
+		opts.Size = DefaultSize
+	}
+	if opts.MaxClosedKept <= 0 {
+		opts.MaxClosedKept = 100
+	}
+	s := &Shadow{
+		opts:        opts,
+		ledgers:     make(map[string]*ledger, len(strategies)),
+		attribution: NewAttribution(),
+		logger:      logger,
+	}
+	for _, name := range strategies {
+		s.ledgers[name] = &ledger{strategy: name, open: make(map[string]*Position)}
+	}
+	return s
+}
+
+// SetResolutions settles positions at the venues' reported outcomes once
+// both markets of their pair are in book, instead of at $1 a contract after
+// both close. Call before Observe.
+func (s *Shadow) SetResolutions(book *arb.ResolutionBook) {
+	s.resolutions = book
+}
+
+// OnSettle registers a callback invoked with every settlement, e.g. to
+// persist it. It runs outside the ledger lock. Call before Observe.
+func (s *Shadow) OnSettle(fn func(Settlement)) {
+	s.onSettle = fn
+}
+
+// Observe advances every ledger by one engine cycle: opportunities not yet
+// held and within their execution window are opened in their strategy's
+// ledger, then open positions are
+// marked against prices and unwound or settled when due
+func (s *Shadow) Observe(opps []arb.Opportunity, prices []arb.PairPrices, now time.Time) {
+	byPair := make(map[instrument.ID]arb.PairPrices, len(prices))
+	for _, p := range prices {
+		byPair[p.PMInstrument] = p
+	}
+
+	s.mu.Lock()
+	var settled []Settlement
+	defer func() {
+		s.mu.Unlock()
+		if s.onSettle != nil {
+			for _, st := range settled {
+				s.onSettle(st)
+			}
+		}
+	}()
+
+	for i := range opps {
+		o := &opps[i]
+		l, ok := s.ledgers[o.Strategy]
+		if !ok {
+			continue
+		}
+		if _, held := l.open[o.ID]; held || !o.Executable(now) {
+			continue
+		}
+		l.open[o.ID] = s.openPosition(o, now)
+		l.trades++
+	}
+
+	for _, l := range s.ledgers {
+		for id, p := range l.open {
+			var exit arb.ExitEdge
+			pp, marked := byPair[p.pmYes]
+			if marked {
+				exit, marked = pp.Exit(p.Combo)
+			}
+			if marked {
+				p.MarkValue = exit.Proceeds
+			}
+			p.PnL = p.Contracts*(p.MarkValue-p.Cost()) - p.Fees
+
+			if payout, ok := s.resolved(p, now); ok {
+				settled = append(settled, s.close(l, id, p, ReasonSettled, payout, 0, now))
+				continue
+			}
+			if !marked {
+				continue
+			}
+			// Unwind when the sale, after its fees, beats the $1 resolution payout
+			exitFees := arb.TakerFee(s.opts.PMFeeRate, p.Contracts, exit.PMBid) + arb.TakerFee(s.opts.KalshiFeeRate, p.Contracts, exit.KalshiBid)
+			if p.Contracts*p.MarkValue-exitFees > p.Contracts {
+				settled = append(settled, s.close(l, id, p, ReasonUnwound, p.MarkValue, exitFees, now))
+			}
+		}
+		s.recordMetrics(l)
+	}
+}
+
+// resolved returns a position's per-contract resolution payout once both
+// of its markets have resolved
+func (s *Shadow) resolved(p *Position, now time.Time) (float64, bool) {
+	if s.resolutions != nil {
+		pmYesWon, kalshiYesWon, ok := s.resolutions.Outcome(p.pmConditionID, p.pmTokenYes, p.kalshiTicker)
+		if !ok {
+			return 0, false
+		}
+		return arb.Payout(p.Combo, pmYesWon, kalshiYesWon), true
+	}
+	if !p.closesAt.IsZero() && now.After(p.closesAt) {
+		return 1, true
+	}
+	return 0, false
+}
+
+// openPosition fills an opportunity at its quoted prices
+func (s *Shadow) openPosition(o *arb.Opportunity, now time.Time) *Position {
+	contracts := s.opts.Size
+	if o.MaxSize > 0 && o.MaxSize < contracts {
+		contracts = o.MaxSize
+	}
+
+	p := &Position{
+		OppID:         o.ID,
+		Strategy:      o.Strategy,
+		Combo:         o.Combo,
+		Category:      o.Category,
+		MatchScore:    o.MatchScore,
+		PMTitle:       o.PMTitle,
+		Contracts:     contracts,
+		OpenedAt:      now,
+		pmYes:         instrument.PMToken(o.Market.PMTokenYes),
+		kalshiYes:     instrument.KalshiYes(o.KalshiTicker),
+		pmConditionID: o.Market.PMConditionID,
+		pmTokenYes:    o.Market.PMTokenYes,
+		kalshiTicker:  o.KalshiTicker,
+	}
+	// Legs are priced from the opportunity's own cost, so strategies that
+	// enter at the bid are credited with the price they assumed
+	if pmYes, _ := arb.ComboSides(o.Combo); pmYes {
+		p.PMPrice = o.PMYesAsk
+	} else {
+		p.PMPrice = o.PMNoAsk
+	}
+	p.KalshiPrice = o.TotalCost - o.Fees - p.PMPrice
+	p.MarkValue = p.Cost()
+	p.Fees = arb.TakerFee(s.opts.PMFeeRate, contracts, p.PMPrice) + arb.TakerFee(s.opts.KalshiFeeRate, contracts, p.KalshiPrice)
+	p.PnL = -p.Fees
+
+	if settles, ok := o.Market.SettlesAt(); ok {
+		p.closesAt = settles
+	}
+	return p
+}
+
+// close realizes a position at a per-contract exit value less exit fees,
+// and attributes the result to its opportunity
+func (s *Shadow) close(l *ledger, id string, p *Position, reason string, exit, exitFees float64, now time.Time) Settlement {
+	p.MarkValue = exit
+	p.Fees += exitFees
+	p.PnL = p.Contracts*(exit-p.Cost()) - p.Fees
+	p.ClosedAt = &now
+	p.CloseReason = reason
+
+	l.realized += p.PnL
+	l.closed = append(l.closed, *p)
+	if len(l.closed) > s.opts.MaxClosedKept {
+		l.closed = l.closed[len(l.closed)-s.opts.MaxClosedKept:]
+	}
+	delete(l.open, id)
+
+	st := Settlement{
+		OppID:      p.OppID,
+		Strategy:   l.strategy,
+		Category:   p.Category,
+		MatchScore: p.MatchScore,
+		Combo:      p.Combo,
+		Reason:     reason,
+		Contracts:  p.Contracts,
+		Cost:       p.Cost(),
+		Payout:     exit,
+		Fees:       p.Fees,
+		PnL:        p.PnL,
+		OpenedAt:   p.OpenedAt,
+		ClosedAt:   now,
+	}
+	s.attribution.Add(st)
+
+	s.logger.Debug("shadow position closed", "strategy", l.strategy, "opp_id", id, "reason", reason, "pnl", p.PnL)
+	return st
+}
+
+// Attribution returns the settled PnL since start
+func (s *Shadow) Attribution() Attribution {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Attribution{
+		Total:        s.attribution.Total,
+		ByStrategy:   maps.Clone(s.attribution.ByStrategy),
+		ByCategory:   maps.Clone(s.attribution.ByCategory),
+		ByConfidence: maps.Clone(s.attribution.ByConfidence),
+	}
+}
+
+// recordMetrics exports a ledger's PnL
+func (s *Shadow) recordMetrics(l *ledger) {
+	var unrealized float64
+	for _, p := range l.open {
+		unrealized += p.PnL
+	}
+	metrics.SetShadowPnL(l.strategy, l.realized, unrealized)
+}
+
+// Records returns every strategy's track record, sorted by strategy name
+func (s *Shadow) Records() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]Record, 0, len(s.ledgers))
+	for _, l := range s.ledgers {
+		r := Record{
+			Strategy:     l.strategy,
+			Live:         l.strategy == s.opts.LiveStrategy,
+			Trades:       l.trades,
+			Open:         len(l.open),
+			VenueCapital: map[string]float64{arb.VenuePolymarket: 0, arb.VenueKalshi: 0},
+			RealizedPnL:  l.realized,
+			Positions:    make([]Position, 0, len(l.open)+len(l.closed)),
+		}
+		for _, p := range l.open {
+			r.VenueCapital[arb.VenuePolymarket] += p.Contracts * p.PMPrice
+			r.VenueCapital[arb.VenueKalshi] += p.Contracts * p.KalshiPrice
+			r.LockedPnL += p.Contracts * (1 - p.Cost())
+			r.UnrealizedPnL += p.PnL
+			r.Positions = append(r.Positions, *p)
+		}
+		r.Capital = r.VenueCapital[arb.VenuePolymarket] + r.VenueCapital[arb.VenueKalshi]
+		sort.Slice(r.Positions, func(i, j int) bool { return r.Positions[i].OpenedAt.Before(r.Positions[j].OpenedAt) })
+		for i := len(l.closed) - 1; i >= 0; i-- {
+			r.Positions = append(r.Positions, l.closed[i])
+		}
+		records = append(records, r)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].Strategy < records[j].Strategy })
+	return records
+}
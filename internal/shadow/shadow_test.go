@@ -0,0 +1,153 @@
+package shadow
+
+import (
+	"io"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+func testOpp(id, strategy string, kalshiCost float64) arb.Opportunity {
+	return arb.Opportunity{
+		ID:           id,
+		Strategy:     strategy,
+		Combo:        arb.ComboPMYesKalshiNo,
+		KalshiTicker: "K",
+		PMYesAsk:     0.40,
+		TotalCost:    0.40 + kalshiCost,
+		MaxSize:      100,
+		Market:       arb.MarketMetadata{PMTokenYes: "y"},
+	}
+}
+
+func testPrices(pmNoAsk, kalshiYesAsk float64) []arb.PairPrices {
+	return []arb.PairPrices{{PMInstrument: instrument.PMToken("y"), PMNoAsk: pmNoAsk, KalshiYesAsk: kalshiYesAsk, PMYesBid: 1 - pmNoAsk, KalshiNoBid: 1 - kalshiYesAsk}}
+}
+
+func TestShadowLedgersPerStrategy(t *testing.T) {
+	s := New([]string{"arbitrage", "spread_capture"}, Options{Size: 10, LiveStrategy: "arbitrage"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Same pair seen by both strategies; spread capture assumes the Kalshi bid
+	opps := []arb.Opportunity{testOpp("a", "arbitrage", 0.55), testOpp("b", "spread_capture", 0.50), testOpp("c", "divergence", 0.55)}
+	s.Observe(opps, testPrices(0.62, 0.47), now)
+	s.Observe(opps, testPrices(0.62, 0.47), now.Add(time.Second)) // Still held, not reopened
+
+	records := s.Records()
+	if len(records) != 2 {
+		t.Fatalf("expected 2 ledgers, got %+v", records)
+	}
+	arbRec, spread := records[0], records[1]
+	if !arbRec.Live || spread.Live || arbRec.Trades != 1 || arbRec.Open != 1 {
+		t.Errorf("unexpected arbitrage record %+v", arbRec)
+	}
+	if math.Abs(arbRec.LockedPnL-0.5) > 1e-9 || math.Abs(spread.LockedPnL-1.0) > 1e-9 {
+		t.Errorf("locked PnL = %v, %v; want 0.5, 1.0", arbRec.LockedPnL, spread.LockedPnL)
+	}
+	if math.Abs(arbRec.VenueCapital[arb.VenueKalshi]-5.5) > 1e-9 || math.Abs(arbRec.Capital-9.5) > 1e-9 {
+		t.Errorf("unexpected capital %+v", arbRec)
+	}
+	// Mark: (1 - 0.62) + (1 - 0.47) = 0.91 against a cost of 0.95
+	if math.Abs(arbRec.UnrealizedPnL-(-0.4)) > 1e-9 {
+		t.Errorf("unrealized PnL = %v, want -0.4", arbRec.UnrealizedPnL)
+	}
+
+	// Selling both legs now fetches 1.05 a contract, more than holding pays
+	s.Observe(nil, testPrices(0.50, 0.45), now.Add(2*time.Second))
+	arbRec = s.Records()[0]
+	if arbRec.Open != 0 || arbRec.Positions[0].CloseReason != ReasonUnwound {
+		t.Fatalf("expected an unwound position, got %+v", arbRec)
+	}
+	if math.Abs(arbRec.RealizedPnL-1.0) > 1e-9 {
+		t.Errorf("realized PnL = %v, want 1.0", arbRec.RealizedPnL)
+	}
+}
+
+func TestShadowSkipsExpired(t *testing.T) {
+	s := New([]string{"arbitrage"}, Options{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	o := testOpp("a", "arbitrage", 0.55)
+	o.ExpiresAt = &now
+	s.Observe([]arb.Opportunity{o}, nil, now)
+	if r := s.Records()[0]; r.Trades != 0 {
+		t.Errorf("an opportunity past its execution window must not be traded: %+v", r)
+	}
+}
+
+func TestShadowSettlesAtClose(t *testing.T) {
+	s := New([]string{"arbitrage"}, Options{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	closes := now.Add(time.Hour)
+
+	o := testOpp("a", "arbitrage", 0.55)
+	o.MaxSize = 4 // Caps the default size
+	o.Market.PMCloseTime, o.Market.KalshiCloseTime = &now, &closes
+	s.Observe([]arb.Opportunity{o}, nil, now)
+	s.Observe(nil, nil, closes.Add(time.Second))
+
+	r := s.Records()[0]
+	if r.Open != 0 || r.Positions[0].CloseReason != ReasonSettled || r.Positions[0].Contracts != 4 {
+		t.Fatalf("expected a settled 4-contract position, got %+v", r)
+	}
+	if math.Abs(r.RealizedPnL-0.2) > 1e-9 {
+		t.Errorf("realized PnL = %v, want 0.2", r.RealizedPnL)
+	}
+}
+
+func TestShadowSettlesAtResolution(t *testing.T) {
+	s := New([]string{"arbitrage"}, Options{KalshiFeeRate: 0.07}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	book := arb.NewResolutionBook()
+	s.SetResolutions(book)
+	var settled []Settlement
+	s.OnSettle(func(st Settlement) { settled = append(settled, st) })
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	o := testOpp("a", "arbitrage", 0.55)
+	o.Category, o.MatchScore = "politics", 0.83
+	o.Market.PMConditionID = "c"
+	o.Market.PMCloseTime, o.Market.KalshiCloseTime = &now, &now // Closing alone does not settle
+	s.Observe([]arb.Opportunity{o}, nil, now)
+
+	// Entry fee on the Kalshi leg: 0.07 × 10 × 0.55 × 0.45 = 0.173, rounded up
+	if p := s.Records()[0].Positions[0]; math.Abs(p.Fees-0.18) > 1e-9 || math.Abs(p.PnL+0.18) > 1e-9 {
+		t.Errorf("unexpected entry fees %+v", p)
+	}
+
+	book.SetKalshi("K", true)
+	s.Observe(nil, nil, now.Add(time.Hour))
+	if r := s.Records()[0]; r.Open != 1 || len(settled) != 0 {
+		t.Fatalf("settled before both markets resolved: %+v", r)
+	}
+
+	// Both YES: the PM leg pays, the Kalshi NO leg expires worthless
+	book.SetPolymarket("c", "y")
+	s.Observe(nil, nil, now.Add(2*time.Hour))
+	if len(settled) != 1 {
+		t.Fatalf("expected one settlement, got %+v", settled)
+	}
+	st := settled[0]
+	if st.OppID != "a" || st.Reason != ReasonSettled || st.Payout != 1 || math.Abs(st.PnL-0.32) > 1e-9 {
+		t.Errorf("unexpected settlement %+v", st)
+	}
+
+	a := s.Attribution()
+	if a.Total.Trades != 1 || a.Total.Wins != 1 || math.Abs(a.Total.GrossPnL-0.5) > 1e-9 || math.Abs(a.Total.Fees-0.18) > 1e-9 {
+		t.Errorf("unexpected total %+v", a.Total)
+	}
+	if a.ByCategory["politics"].Trades != 1 || a.ByStrategy["arbitrage"].Trades != 1 || a.ByConfidence["0.8-0.9"].Trades != 1 {
+		t.Errorf("unexpected attribution %+v", a)
+	}
+}
+
+func TestConfidenceBucket(t *testing.T) {
+	for score, want := range map[float64]string{1: "0.9-1.0", 0.83: "0.8-0.9", 0.05: "0.0-0.1"} {
+		if got := ConfidenceBucket(score); got != want {
+			t.Errorf("ConfidenceBucket(%v) = %s, want %s", score, got, want)
+		}
+	}
+}
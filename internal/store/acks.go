@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// RecordAck appends an opportunity acknowledgment; every status change is kept
+func (s *Store) RecordAck(ctx context.Context, a arb.Acknowledgment) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO opportunity_acks (opp_id, first_seen, status, reason, acked_by, acked_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		a.OppID, a.FirstSeen.UnixMilli(), a.Status, a.Reason, a.By, a.At.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("insert ack %s: %w", a.OppID, err)
+	}
+	return nil
+}
+
+// Acks returns the persisted acknowledgments of an opportunity, oldest first
+func (s *Store) Acks(ctx context.Context, oppID string) ([]arb.Acknowledgment, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT first_seen, status, reason, acked_by, acked_at
+		FROM opportunity_acks
+		WHERE opp_id = ?
+		ORDER BY acked_at, rowid`, oppID)
+	if err != nil {
+		return nil, fmt.Errorf("query acks: %w", err)
+	}
+	defer rows.Close()
+
+	acks := make([]arb.Acknowledgment, 0)
+	for rows.Next() {
+		a := arb.Acknowledgment{OppID: oppID}
+		var firstSeen, at int64
+		if err := rows.Scan(&firstSeen, &a.Status, &a.Reason, &a.By, &at); err != nil {
+			return nil, fmt.Errorf("scan ack row: %w", err)
+		}
+		a.FirstSeen = time.UnixMilli(firstSeen).UTC()
+		a.At = time.UnixMilli(at).UTC()
+		acks = append(acks, a)
+	}
+	return acks, rows.Err()
+}
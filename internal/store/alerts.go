@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const alertRecorderQueueSize = 64
+
+// StartAlertRecorder launches a background writer that persists which open
+// opportunities have been alerted and returns a hook suitable for
+// notify.AlertOptions.Record. Updates are dropped rather than blocking the
+// engine when the writer falls behind; the next one refreshes them.
+func (s *Store) StartAlertRecorder(ctx context.Context) func(map[string]time.Time) {
+	queue := make(chan map[string]time.Time, alertRecorderQueueSize)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case alerted := <-queue:
+				if err := s.RecordAlerts(ctx, alerted); err != nil {
+					s.logger.Error("failed to record alerts", "error", err)
+				}
+			}
+		}
+	}()
+
+	return func(alerted map[string]time.Time) {
+		select {
+		case queue <- alerted:
+		default:
+			s.logger.Warn("alert recorder queue full, dropping update")
+		}
+	}
+}
+
+// RecordAlerts upserts when each alerted opportunity, by ID, was last seen open
+func (s *Store) RecordAlerts(ctx context.Context, alerted map[string]time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO alert_history (opp_id, last_seen) VALUES (?, ?)
+		ON CONFLICT (opp_id) DO UPDATE SET last_seen = max(last_seen, excluded.last_seen)`)
+	if err != nil {
+		return fmt.Errorf("prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for id, at := range alerted {
+		if _, err := stmt.ExecContext(ctx, id, at.UnixMilli()); err != nil {
+			return fmt.Errorf("upsert alert %s: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AlertedSince returns the alerted opportunities last seen open at or after
+// since, by ID, for notify.AlertOptions.Alerted
+func (s *Store) AlertedSince(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT opp_id, last_seen FROM alert_history WHERE last_seen >= ?`, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("query alert history: %w", err)
+	}
+	defer rows.Close()
+
+	alerted := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var at int64
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, fmt.Errorf("scan alert history row: %w", err)
+		}
+		alerted[id] = time.UnixMilli(at).UTC()
+	}
+	return alerted, rows.Err()
+}
+
+// PruneAlerts deletes alert history last seen before cutoff
+func (s *Store) PruneAlerts(ctx context.Context, cutoff time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM alert_history WHERE last_seen < ?`, cutoff.UnixMilli()); err != nil {
+		return fmt.Errorf("prune alert history: %w", err)
+	}
+	return nil
+}
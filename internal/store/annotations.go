@@ -0,0 +1,53 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// SaveAnnotation inserts or replaces an operator's annotation of a pair
+func (s *Store) SaveAnnotation(ctx context.Context, a arb.Annotation) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pair_annotations (pm_condition_id, kalshi_ticker, verified, note, tags, annotated_by, annotated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (pm_condition_id, kalshi_ticker) DO UPDATE SET
+			verified = excluded.verified,
+			note = excluded.note,
+			tags = excluded.tags,
+			annotated_by = excluded.annotated_by,
+			annotated_at = excluded.annotated_at`,
+		a.PMConditionID, a.KalshiTicker, a.Verified, a.Note, strings.Join(a.Tags, " "), a.By, a.At.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("save annotation %s/%s: %w", a.PMConditionID, a.KalshiTicker, err)
+	}
+	return nil
+}
+
+// Annotations returns every persisted pair annotation
+func (s *Store) Annotations(ctx context.Context) ([]arb.Annotation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pm_condition_id, kalshi_ticker, verified, note, tags, annotated_by, annotated_at
+		FROM pair_annotations`)
+	if err != nil {
+		return nil, fmt.Errorf("query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]arb.Annotation, 0)
+	for rows.Next() {
+		var a arb.Annotation
+		var tags string
+		var at int64
+		if err := rows.Scan(&a.PMConditionID, &a.KalshiTicker, &a.Verified, &a.Note, &tags, &a.By, &at); err != nil {
+			return nil, fmt.Errorf("scan annotation row: %w", err)
+		}
+		a.Tags = strings.Fields(tags)
+		a.At = time.UnixMilli(at).UTC()
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// Archivable tables, drained by day into the archive
+const (
+	TableOpportunityHistory = "opportunity_history"
+	TablePairSpreads        = "pair_spreads"
+)
+
+// archiveColumns is the time column each archivable table is partitioned
+// by. History rows are archived by when their streak was last seen, so
+// open streaks are never taken.
+var archiveColumns = map[string]string{
+	TableOpportunityHistory: "last_seen",
+	TablePairSpreads:        "at",
+}
+
+// HistoryRow is one persisted opportunity streak
+type HistoryRow struct {
+	OppID            string
+	FirstSeen        time.Time
+	LastSeen         time.Time
+	Combo            string
+	Category         string
+	PMTitle          string
+	KalshiTicker     string
+	KalshiTitle      string
+	PMInstrument     string
+	KalshiInstrument string
+	PeakEdgePct      float64
+	SumEdgePct       float64
+	Samples          int
+}
+
+// Oldest returns the earliest time in an archivable table's partition
+// column before cutoff; ok is false when there is none
+func (s *Store) Oldest(ctx context.Context, table string, before time.Time) (oldest time.Time, ok bool, err error) {
+	column, known := archiveColumns[table]
+	if !known {
+		return time.Time{}, false, fmt.Errorf("table %s is not archivable", table)
+	}
+	var ms sql.NullInt64
+	err = s.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT min(%s) FROM %s WHERE %s < ?`, column, table, column), before.UnixMilli()).Scan(&ms)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("query oldest %s: %w", table, err)
+	}
+	if !ms.Valid {
+		return time.Time{}, false, nil
+	}
+	return time.UnixMilli(ms.Int64).UTC(), true, nil
+}
+
+// ErrArchiveChanged is returned by DeleteArchived when rows were added to
+// or removed from the range after it was exported
+var ErrArchiveChanged = errors.New("archived range changed since export")
+
+// DeleteArchived deletes an archivable table's rows in [from, to), provided
+// there are still exactly exported of them
+func (s *Store) DeleteArchived(ctx context.Context, table string, from, to time.Time, exported int) error {
+	column, known := archiveColumns[table]
+	if !known {
+		return fmt.Errorf("table %s is not archivable", table)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin delete: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE %s >= ? AND %s < ?`, table, column, column), from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("delete archived %s: %w", table, err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n != int64(exported) {
+		return ErrArchiveChanged
+	}
+	return tx.Commit()
+}
+
+// ExportHistory streams the opportunity streaks last seen in [from, to) to fn
+func (s *Store) ExportHistory(ctx context.Context, from, to time.Time, fn func(HistoryRow) error) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT opp_id, first_seen, last_seen, combo, category, pm_title, kalshi_ticker, kalshi_title,
+		       pm_instrument, kalshi_instrument, peak_edge_pct, sum_edge_pct, samples
+		FROM opportunity_history
+		WHERE last_seen >= ? AND last_seen < ?
+		ORDER BY last_seen`, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("query history: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r HistoryRow
+		var first, last int64
+		if err := rows.Scan(&r.OppID, &first, &last, &r.Combo, &r.Category, &r.PMTitle, &r.KalshiTicker, &r.KalshiTitle,
+			&r.PMInstrument, &r.KalshiInstrument, &r.PeakEdgePct, &r.SumEdgePct, &r.Samples); err != nil {
+			return fmt.Errorf("scan history row: %w", err)
+		}
+		r.FirstSeen, r.LastSeen = time.UnixMilli(first).UTC(), time.UnixMilli(last).UTC()
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ExportSpreads streams the spread samples taken in [from, to) to fn
+func (s *Store) ExportSpreads(ctx context.Context, from, to time.Time, fn func(arb.SpreadSample) error) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pair_id, at, pm_mid, kalshi_mid FROM pair_spreads
+		WHERE at >= ? AND at < ?
+		ORDER BY at`, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("query spreads: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sm arb.SpreadSample
+		var at int64
+		if err := rows.Scan(&sm.PairID, &at, &sm.PMMid, &sm.KalshiMid); err != nil {
+			return fmt.Errorf("scan spread row: %w", err)
+		}
+		sm.At = time.UnixMilli(at).UTC()
+		sm.Spread = sm.PMMid - sm.KalshiMid
+		if err := fn(sm); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
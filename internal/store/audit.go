@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultAuditLimit caps an audit query that sets no limit
+const defaultAuditLimit = 100
+
+// AuditEntry is one recorded admin mutation. Entries are append-only: undoing
+// a change records a new entry rather than removing the old one.
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	At         time.Time       `json:"at"`
+	Actor      string          `json:"actor"` // Identifies the API key used
+	RemoteAddr string          `json:"remote_addr"`
+	Action     string          `json:"action"`
+	Target     string          `json:"target"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+}
+
+// AuditQuery filters ListAudit; zero fields match everything
+type AuditQuery struct {
+	Since  time.Time
+	Action string
+	Actor  string
+	Limit  int // Defaults to defaultAuditLimit
+}
+
+// RecordAudit appends an admin mutation to the audit log
+func (s *Store) RecordAudit(ctx context.Context, e AuditEntry) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO admin_audit (at, actor, remote_addr, action, target, before_json, after_json)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		e.At.UnixMilli(), e.Actor, e.RemoteAddr, e.Action, e.Target, rawOrNull(e.Before), rawOrNull(e.After))
+	if err != nil {
+		return fmt.Errorf("insert audit entry %s: %w", e.Action, err)
+	}
+	return nil
+}
+
+// ListAudit returns audit entries matching q, newest first
+func (s *Store) ListAudit(ctx context.Context, q AuditQuery) ([]AuditEntry, error) {
+	var where []string
+	var args []interface{}
+	if !q.Since.IsZero() {
+		where = append(where, "at >= ?")
+		args = append(args, q.Since.UnixMilli())
+	}
+	if q.Action != "" {
+		where = append(where, "action = ?")
+		args = append(args, q.Action)
+	}
+	if q.Actor != "" {
+		where = append(where, "actor = ?")
+		args = append(args, q.Actor)
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	query := `SELECT id, at, actor, remote_addr, action, target, before_json, after_json FROM admin_audit`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]AuditEntry, 0)
+	for rows.Next() {
+		var e AuditEntry
+		var at int64
+		var before, after string
+		if err := rows.Scan(&e.ID, &at, &e.Actor, &e.RemoteAddr, &e.Action, &e.Target, &before, &after); err != nil {
+			return nil, fmt.Errorf("scan audit row: %w", err)
+		}
+		e.At = time.UnixMilli(at).UTC()
+		e.Before = json.RawMessage(before)
+		e.After = json.RawMessage(after)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// rawOrNull stores a missing value as JSON null
+func rawOrNull(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return "null"
+	}
+	return string(raw)
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/calibration"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// CalibrationSamples returns the opportunity streaks first seen since since,
+// each with the settled arbitrage paper trades opened during it
+func (s *Store) CalibrationSamples(ctx context.Context, since time.Time) ([]calibration.Sample, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT h.category, h.peak_edge_pct, h.last_seen - h.first_seen, COUNT(t.id), COALESCE(SUM(t.pnl), 0)
+		FROM opportunity_history h
+		LEFT JOIN trade_settlements t
+			ON t.opp_id = h.opp_id AND t.strategy = ? AND t.opened_at BETWEEN h.first_seen AND h.last_seen
+		WHERE h.first_seen >= ?
+		GROUP BY h.opp_id, h.first_seen`,
+		arb.StrategyArbitrage, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("query calibration samples: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]calibration.Sample, 0)
+	for rows.Next() {
+		var c calibration.Sample
+		var durationMs int64
+		if err := rows.Scan(&c.Category, &c.EdgePct, &durationMs, &c.Trades, &c.PnL); err != nil {
+			return nil, fmt.Errorf("scan calibration row: %w", err)
+		}
+		c.Duration = time.Duration(durationMs) * time.Millisecond
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+const connectivityQueueSize = 64
+
+// ConnectivityPeriod is a stretch of time a connection stayed up or down
+type ConnectivityPeriod struct {
+	Connected bool      `json:"connected"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"` // The window's end while still ongoing
+	Seconds   float64   `json:"seconds"`
+	Ongoing   bool      `json:"ongoing,omitempty"`
+}
+
+// SourceConnectivity is one connection's history over a window. Time before
+// the first recorded event is unknown and left out of Uptime.
+type SourceConnectivity struct {
+	Source           string               `json:"source"`
+	Uptime           float64              `json:"uptime"` // Fraction of the known time connected
+	ConnectedSeconds float64              `json:"connected_seconds"`
+	KnownSeconds     float64              `json:"known_seconds"`
+	Disconnects      int                  `json:"disconnects"`
+	Periods          []ConnectivityPeriod `json:"periods"` // Oldest first
+}
+
+// StartConnectivityRecorder launches a background writer that persists
+// connection events and returns a hook suitable for a ws client's
+// SetConnectionHook. Events are dropped rather than blocking the client when
+// the writer falls behind.
+func (s *Store) StartConnectivityRecorder(ctx context.Context) func(ws.ConnectionEvent) {
+	queue := make(chan ws.ConnectionEvent, connectivityQueueSize)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-queue:
+				if err := s.RecordConnectivity(ctx, e); err != nil {
+					s.logger.Error("failed to record connection event", "source", e.Source, "error", err)
+				}
+			}
+		}
+	}()
+
+	return func(e ws.ConnectionEvent) {
+		select {
+		case queue <- e:
+		default:
+			s.logger.Warn("connectivity recorder queue full, dropping event", "source", e.Source)
+		}
+	}
+}
+
+// RecordConnectivity persists a connection event
+func (s *Store) RecordConnectivity(ctx context.Context, e ws.ConnectionEvent) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO ws_connectivity (source, at, connected) VALUES (?, ?, ?)`,
+		e.Source, e.At.UnixMilli(), e.Connected)
+	if err != nil {
+		return fmt.Errorf("record connection event %s: %w", e.Source, err)
+	}
+	return nil
+}
+
+// CloseConnectivity records a disconnection at at for every source last
+// recorded as connected. Call on startup: a process that stopped without
+// recording its disconnections would otherwise count as connected until
+// its successor connects again. The downtime itself is only known from
+// startup on, so an unclean stop still counts as connected until then.
+func (s *Store) CloseConnectivity(ctx context.Context, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO ws_connectivity (source, at, connected)
+		SELECT c.source, ?, 0 FROM ws_connectivity c
+		WHERE c.rowid = (SELECT rowid FROM ws_connectivity WHERE source = c.source ORDER BY at DESC, rowid DESC LIMIT 1)
+			AND c.connected = 1`,
+		at.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("close connectivity: %w", err)
+	}
+	return nil
+}
+
+// Connectivity returns each source's connection history between since and
+// until, by source
+func (s *Store) Connectivity(ctx context.Context, since, until time.Time) ([]SourceConnectivity, error) {
+	// Each source's state when the window opens, then its events within it
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT source, at, connected FROM (
+			SELECT source, MAX(at) AS at, connected FROM ws_connectivity WHERE at < ? GROUP BY source
+		)
+		UNION ALL
+		SELECT source, at, connected FROM ws_connectivity WHERE at >= ? AND at < ?
+		ORDER BY source, at`,
+		since.UnixMilli(), since.UnixMilli(), until.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("query connectivity: %w", err)
+	}
+	defer rows.Close()
+
+	events := make(map[string][]ws.ConnectionEvent)
+	for rows.Next() {
+		var e ws.ConnectionEvent
+		var at int64
+		if err := rows.Scan(&e.Source, &at, &e.Connected); err != nil {
+			return nil, fmt.Errorf("scan connectivity row: %w", err)
+		}
+		e.At = time.UnixMilli(at).UTC()
+		if e.At.Before(since) {
+			e.At = since // The state the window opens with
+		}
+		events[e.Source] = append(events[e.Source], e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]SourceConnectivity, 0, len(events))
+	for source, evs := range events {
+		out = append(out, connectivityOf(source, evs, until))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out, nil
+}
+
+// connectivityOf folds a source's events, oldest first, into periods ending
+// at until. Repeated events of the same state extend the current period.
+func connectivityOf(source string, events []ws.ConnectionEvent, until time.Time) SourceConnectivity {
+	c := SourceConnectivity{Source: source, Periods: make([]ConnectivityPeriod, 0)}
+	for i, e := range events {
+		if i > 0 && e.Connected == events[i-1].Connected {
+			continue
+		}
+		if n := len(c.Periods); n > 0 {
+			c.Periods[n-1].To = e.At
+		}
+		if !e.Connected && len(c.Periods) > 0 {
+			c.Disconnects++
+		}
+		c.Periods = append(c.Periods, ConnectivityPeriod{Connected: e.Connected, From: e.At})
+	}
+	if n := len(c.Periods); n > 0 {
+		c.Periods[n-1].To, c.Periods[n-1].Ongoing = until, true
+	}
+
+	for i := range c.Periods {
+		p := &c.Periods[i]
+		p.Seconds = p.To.Sub(p.From).Seconds()
+		c.KnownSeconds += p.Seconds
+		if p.Connected {
+			c.ConnectedSeconds += p.Seconds
+		}
+	}
+	if c.KnownSeconds > 0 {
+		c.Uptime = c.ConnectedSeconds / c.KnownSeconds
+	}
+	return c
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// SaveNearMiss inserts or updates a near-miss candidate and its review status
+func (s *Store) SaveNearMiss(ctx context.Context, n arb.NearMiss) error {
+	var decidedAt sql.NullInt64
+	if n.DecidedAt != nil {
+		decidedAt = sql.NullInt64{Int64: n.DecidedAt.UnixMilli(), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pair_near_misses (id, pm_condition_id, pm_token_yes, pm_title, kalshi_ticker, kalshi_title,
+			score, first_seen, status, decided_by, decided_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			score = excluded.score,
+			status = excluded.status,
+			decided_by = excluded.decided_by,
+			decided_at = excluded.decided_at`,
+		n.ID, n.PMConditionID, n.PMTokenYes, n.PMTitle, n.KalshiTicker, n.KalshiTitle,
+		n.Score, n.FirstSeen.UnixMilli(), n.Status, n.DecidedBy, decidedAt)
+	if err != nil {
+		return fmt.Errorf("save near-miss %s: %w", n.ID, err)
+	}
+	return nil
+}
+
+// NearMisses returns every persisted near-miss
+func (s *Store) NearMisses(ctx context.Context) ([]arb.NearMiss, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, pm_condition_id, pm_token_yes, pm_title, kalshi_ticker, kalshi_title,
+			score, first_seen, status, decided_by, decided_at
+		FROM pair_near_misses`)
+	if err != nil {
+		return nil, fmt.Errorf("query near-misses: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]arb.NearMiss, 0)
+	for rows.Next() {
+		var n arb.NearMiss
+		var firstSeen int64
+		var decidedAt sql.NullInt64
+		if err := rows.Scan(&n.ID, &n.PMConditionID, &n.PMTokenYes, &n.PMTitle, &n.KalshiTicker, &n.KalshiTitle,
+			&n.Score, &firstSeen, &n.Status, &n.DecidedBy, &decidedAt); err != nil {
+			return nil, fmt.Errorf("scan near-miss row: %w", err)
+		}
+		n.FirstSeen = time.UnixMilli(firstSeen).UTC()
+		if decidedAt.Valid {
+			t := time.UnixMilli(decidedAt.Int64).UTC()
+			n.DecidedAt = &t
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+const recorderQueueSize = 64
+
+// StartRecorder launches a background writer that persists every compute
+// cycle's opportunities and returns a hook suitable for arb.Engine.OnUpdate.
+// Each streak of an opportunity (same ID and first-seen time) is one history row.
+// Cycles are dropped rather than blocking the engine when the writer falls behind.
+func (s *Store) StartRecorder(ctx context.Context) func([]arb.Opportunity) {
+	queue := make(chan []arb.Opportunity, recorderQueueSize)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case opps := <-queue:
+				if err := s.recordOpportunities(ctx, opps); err != nil {
+					s.logger.Error("failed to record opportunities", "error", err)
+				}
+			}
+		}
+	}()
+
+	return func(opps []arb.Opportunity) {
+		if len(opps) == 0 {
+			return
+		}
+		batch := make([]arb.Opportunity, len(opps))
+		copy(batch, opps)
+
+		select {
+		case queue <- batch:
+		default:
+			s.logger.Warn("opportunity recorder queue full, dropping cycle")
+		}
+	}
+}
+
+// recordOpportunities upserts one sample per opportunity into its streak row
+func (s *Store) recordOpportunities(ctx context.Context, opps []arb.Opportunity) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO opportunity_history
+			(opp_id, first_seen, last_seen, combo, category, pm_title, kalshi_ticker, kalshi_title,
+			 pm_instrument, kalshi_instrument, peak_edge_pct, sum_edge_pct, samples)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)
+		ON CONFLICT (opp_id, first_seen) DO UPDATE SET
+			last_seen     = excluded.last_seen,
+			peak_edge_pct = max(peak_edge_pct, excluded.peak_edge_pct),
+			sum_edge_pct  = sum_edge_pct + excluded.sum_edge_pct,
+			samples       = samples + 1`)
+	if err != nil {
+		return fmt.Errorf("prepare upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, o := range opps {
+		if _, err := stmt.ExecContext(ctx,
+			o.ID, o.FirstSeen.UnixMilli(), o.Timestamp.UnixMilli(), o.Combo, o.Category,
+			o.PMTitle, o.KalshiTicker, o.KalshiTitle, string(o.PMInstrument), string(o.KalshiInstrument),
+			o.EdgePctTurn, o.EdgePctTurn,
+		); err != nil {
+			return fmt.Errorf("upsert %s: %w", o.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// HeatmapCell aggregates opportunity streaks for one category and UTC hour of day
+type HeatmapCell struct {
+	Category   string  `json:"category"`
+	Hour       int     `json:"hour"`
+	Count      int     `json:"count"`
+	AvgEdgePct float64 `json:"avg_edge_pct"`
+	MaxEdgePct float64 `json:"max_edge_pct"`
+}
+
+// Heatmap buckets opportunity streaks that started after since by category and
+// the UTC hour in which they were first detected
+func (s *Store) Heatmap(ctx context.Context, since time.Time) ([]HeatmapCell, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT category,
+		       CAST(strftime('%H', first_seen / 1000, 'unixepoch') AS INTEGER) AS hour,
+		       COUNT(*),
+		       AVG(sum_edge_pct / samples),
+		       MAX(peak_edge_pct)
+		FROM opportunity_history
+		WHERE first_seen >= ?
+		GROUP BY category, hour
+		ORDER BY category, hour`, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("query heatmap: %w", err)
+	}
+	defer rows.Close()
+
+	cells := make([]HeatmapCell, 0)
+	for rows.Next() {
+		var c HeatmapCell
+		if err := rows.Scan(&c.Category, &c.Hour, &c.Count, &c.AvgEdgePct, &c.MaxEdgePct); err != nil {
+			return nil, fmt.Errorf("scan heatmap row: %w", err)
+		}
+		cells = append(cells, c)
+	}
+	return cells, rows.Err()
+}
+
+// RecordBackfill adds edge samples recovered after a feed gap to their
+// streaks' history rows. Streaks without a row yet are skipped; the recorder
+// creates it on the next cycle.
+func (s *Store) RecordBackfill(ctx context.Context, streaks []arb.BackfilledStreak) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		UPDATE opportunity_history SET
+			peak_edge_pct = max(peak_edge_pct, ?),
+			sum_edge_pct  = sum_edge_pct + ?,
+			samples       = samples + ?
+		WHERE opp_id = ? AND first_seen = ?`)
+	if err != nil {
+		return fmt.Errorf("prepare backfill: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, streak := range streaks {
+		var peak, sum float64
+		for _, sample := range streak.Samples {
+			peak = max(peak, sample.EdgePctTurn)
+			sum += sample.EdgePctTurn
+		}
+		o := streak.Opportunity
+		if _, err := stmt.ExecContext(ctx, peak, sum, len(streak.Samples), o.ID, o.FirstSeen.UnixMilli()); err != nil {
+			return fmt.Errorf("backfill %s: %w", o.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
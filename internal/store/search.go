@@ -0,0 +1,216 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// searchSchema holds the SQLite FTS5 full-text indexes. opportunity_search
+// indexes opportunity_history in place, kept current by triggers; the
+// recorder's per-sample upserts leave the indexed columns alone and so cost
+// no index writes. pair_search is rebuilt from the monitored pairs.
+var searchSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS opportunity_search USING fts5(
+		pm_title, kalshi_title, kalshi_ticker, category,
+		content = 'opportunity_history', tokenize = 'porter unicode61'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS opportunity_search_insert AFTER INSERT ON opportunity_history BEGIN
+		INSERT INTO opportunity_search (rowid, pm_title, kalshi_title, kalshi_ticker, category)
+		VALUES (new.rowid, new.pm_title, new.kalshi_title, new.kalshi_ticker, new.category);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS opportunity_search_delete AFTER DELETE ON opportunity_history BEGIN
+		INSERT INTO opportunity_search (opportunity_search, rowid, pm_title, kalshi_title, kalshi_ticker, category)
+		VALUES ('delete', old.rowid, old.pm_title, old.kalshi_title, old.kalshi_ticker, old.category);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS opportunity_search_update
+	AFTER UPDATE OF pm_title, kalshi_title, kalshi_ticker, category ON opportunity_history BEGIN
+		INSERT INTO opportunity_search (opportunity_search, rowid, pm_title, kalshi_title, kalshi_ticker, category)
+		VALUES ('delete', old.rowid, old.pm_title, old.kalshi_title, old.kalshi_ticker, old.category);
+		INSERT INTO opportunity_search (rowid, pm_title, kalshi_title, kalshi_ticker, category)
+		VALUES (new.rowid, new.pm_title, new.kalshi_title, new.kalshi_ticker, new.category);
+	END`,
+	`CREATE VIRTUAL TABLE IF NOT EXISTS pair_search USING fts5(
+		pm_condition_id UNINDEXED, kalshi_ticker UNINDEXED, pm_title, kalshi_title, category, tags,
+		tokenize = 'porter unicode61'
+	)`,
+}
+
+// applySearchSchema creates the full-text indexes, indexing the history
+// already recorded when opportunity_search is new
+func applySearchSchema(ctx context.Context, db *sql.DB) error {
+	var existed int
+	err := db.QueryRowContext(ctx,
+		`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'opportunity_search'`).Scan(&existed)
+	if err != nil {
+		return fmt.Errorf("inspect search index: %w", err)
+	}
+
+	for _, stmt := range searchSchema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create search index: %w", err)
+		}
+	}
+
+	if existed == 0 {
+		if _, err := db.ExecContext(ctx, `INSERT INTO opportunity_search (opportunity_search) VALUES ('rebuild')`); err != nil {
+			return fmt.Errorf("build search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReindexPairs replaces the pair search index with pairs, tagged from their
+// annotations. annotations may be nil.
+func (s *Store) ReindexPairs(ctx context.Context, pairs []arb.MarketPair, annotations *arb.AnnotationRegistry) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM pair_search`); err != nil {
+		return fmt.Errorf("clear pair index: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO pair_search (pm_condition_id, kalshi_ticker, pm_title, kalshi_title, category, tags)
+		VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare pair index: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, p := range pairs {
+		a, _ := annotations.Get(p.PMConditionID, p.KalshiTicker)
+		if _, err := stmt.ExecContext(ctx,
+			p.PMConditionID, p.KalshiTicker, p.PMTitle, p.KalshiTitle, p.Category, strings.Join(a.Tags, " "),
+		); err != nil {
+			return fmt.Errorf("index pair %s: %w", p.Key(), err)
+		}
+	}
+	return tx.Commit()
+}
+
+// SetPairTags updates the indexed tags of an annotated pair, if indexed
+func (s *Store) SetPairTags(ctx context.Context, a arb.Annotation) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE pair_search SET tags = ? WHERE pm_condition_id = ? AND kalshi_ticker = ?`,
+		strings.Join(a.Tags, " "), a.PMConditionID, a.KalshiTicker)
+	if err != nil {
+		return fmt.Errorf("index tags of %s/%s: %w", a.PMConditionID, a.KalshiTicker, err)
+	}
+	return nil
+}
+
+// PairHit is a monitored pair matching a search
+type PairHit struct {
+	PMConditionID string   `json:"pm_condition_id"`
+	KalshiTicker  string   `json:"kalshi_ticker"`
+	PMTitle       string   `json:"pm_title"`
+	KalshiTitle   string   `json:"kalshi_title"`
+	Category      string   `json:"category"`
+	Tags          []string `json:"tags"`
+}
+
+// OpportunityHit is an opportunity streak matching a search
+type OpportunityHit struct {
+	OppID        string    `json:"opp_id"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+	Combo        string    `json:"combo"`
+	Category     string    `json:"category"`
+	PMTitle      string    `json:"pm_title"`
+	KalshiTicker string    `json:"kalshi_ticker"`
+	KalshiTitle  string    `json:"kalshi_title"`
+	PeakEdgePct  float64   `json:"peak_edge_pct"`
+	AvgEdgePct   float64   `json:"avg_edge_pct"`
+}
+
+// SearchResults are the best matches of a query, most relevant first
+type SearchResults struct {
+	Pairs         []PairHit        `json:"pairs"`
+	Opportunities []OpportunityHit `json:"opportunities"`
+}
+
+// ErrEmptyQuery is returned by Search for a query without any words
+var ErrEmptyQuery = errors.New("search query has no words")
+
+// Search returns up to limit pairs and limit opportunity streaks matching
+// every word of query; the last word also matches as a prefix, so
+// "fed cut" finds "Fed cuts rates". Streaks are searched by their markets'
+// titles, Kalshi ticker and category, pairs also by tags.
+func (s *Store) Search(ctx context.Context, query string, limit int) (SearchResults, error) {
+	match := ftsQuery(query)
+	if match == "" {
+		return SearchResults{}, ErrEmptyQuery
+	}
+
+	results := SearchResults{Pairs: make([]PairHit, 0), Opportunities: make([]OpportunityHit, 0)}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pm_condition_id, kalshi_ticker, pm_title, kalshi_title, category, tags
+		FROM pair_search WHERE pair_search MATCH ?
+		ORDER BY rank LIMIT ?`, match, limit)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("search pairs: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h PairHit
+		var tags string
+		if err := rows.Scan(&h.PMConditionID, &h.KalshiTicker, &h.PMTitle, &h.KalshiTitle, &h.Category, &tags); err != nil {
+			return SearchResults{}, fmt.Errorf("scan pair hit: %w", err)
+		}
+		h.Tags = strings.Fields(tags)
+		results.Pairs = append(results.Pairs, h)
+	}
+	if err := rows.Err(); err != nil {
+		return SearchResults{}, err
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT h.opp_id, h.first_seen, h.last_seen, h.combo, h.category, h.pm_title, h.kalshi_ticker,
+		       h.kalshi_title, h.peak_edge_pct, h.sum_edge_pct / h.samples
+		FROM opportunity_search
+		JOIN opportunity_history h ON h.rowid = opportunity_search.rowid
+		WHERE opportunity_search MATCH ?
+		ORDER BY opportunity_search.rank, h.first_seen DESC LIMIT ?`, match, limit)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("search opportunities: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h OpportunityHit
+		var first, last int64
+		if err := rows.Scan(&h.OppID, &first, &last, &h.Combo, &h.Category, &h.PMTitle, &h.KalshiTicker,
+			&h.KalshiTitle, &h.PeakEdgePct, &h.AvgEdgePct); err != nil {
+			return SearchResults{}, fmt.Errorf("scan opportunity hit: %w", err)
+		}
+		h.FirstSeen = time.UnixMilli(first).UTC()
+		h.LastSeen = time.UnixMilli(last).UTC()
+		results.Opportunities = append(results.Opportunities, h)
+	}
+	return results, rows.Err()
+}
+
+// ftsQuery turns free text into an FTS5 query matching all of its words,
+// the last as a prefix. Words are quoted so operators and punctuation in
+// user input are never parsed as query syntax.
+func ftsQuery(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) })
+	if len(words) == 0 {
+		return ""
+	}
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + w + `"`
+	}
+	terms[len(terms)-1] += "*"
+	return strings.Join(terms, " ")
+}
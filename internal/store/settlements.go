@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/shadow"
+)
+
+// RecordSettlement persists a closed position's final PnL
+func (s *Store) RecordSettlement(ctx context.Context, st shadow.Settlement) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO trade_settlements (opp_id, strategy, category, match_score, combo, reason,
+			contracts, cost, payout, fees, pnl, opened_at, closed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		st.OppID, st.Strategy, st.Category, st.MatchScore, st.Combo, st.Reason,
+		st.Contracts, st.Cost, st.Payout, st.Fees, st.PnL, st.OpenedAt.UnixMilli(), st.ClosedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("record settlement %s: %w", st.OppID, err)
+	}
+	return nil
+}
+
+// SettlementQuery filters settlements; zero values match everything
+type SettlementQuery struct {
+	Since time.Time
+	OppID string
+	Limit int
+}
+
+// Settlements returns settlements closed since q.Since, newest first
+func (s *Store) Settlements(ctx context.Context, q SettlementQuery) ([]shadow.Settlement, error) {
+	where := []string{"closed_at >= ?"}
+	args := []interface{}{q.Since.UnixMilli()}
+	if q.OppID != "" {
+		where = append(where, "opp_id = ?")
+		args = append(args, q.OppID)
+	}
+	query := `
+		SELECT opp_id, strategy, category, match_score, combo, reason,
+			contracts, cost, payout, fees, pnl, opened_at, closed_at
+		FROM trade_settlements
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY closed_at DESC, id DESC`
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query settlements: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]shadow.Settlement, 0)
+	for rows.Next() {
+		var st shadow.Settlement
+		var opened, closed int64
+		if err := rows.Scan(&st.OppID, &st.Strategy, &st.Category, &st.MatchScore, &st.Combo, &st.Reason,
+			&st.Contracts, &st.Cost, &st.Payout, &st.Fees, &st.PnL, &opened, &closed); err != nil {
+			return nil, fmt.Errorf("scan settlement row: %w", err)
+		}
+		st.OpenedAt, st.ClosedAt = time.UnixMilli(opened).UTC(), time.UnixMilli(closed).UTC()
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+)
+
+// SampleSpreads records sample's spread samples every interval until ctx is
+// done, deleting samples older than retention as it goes
+func (s *Store) SampleSpreads(ctx context.Context, sample func(time.Time) []arb.SpreadSample, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.RecordSpreads(ctx, sample(now)); err != nil {
+				s.logger.Error("failed to record spread samples", "error", err)
+			}
+			if err := s.PruneSpreads(ctx, now.Add(-retention)); err != nil {
+				s.logger.Error("failed to prune spread samples", "error", err)
+			}
+		}
+	}
+}
+
+// RecordSpreads persists spread samples
+func (s *Store) RecordSpreads(ctx context.Context, samples []arb.SpreadSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT OR REPLACE INTO pair_spreads (pair_id, at, pm_mid, kalshi_mid) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, sm := range samples {
+		if _, err := stmt.ExecContext(ctx, sm.PairID, sm.At.UnixMilli(), sm.PMMid, sm.KalshiMid); err != nil {
+			return fmt.Errorf("insert spread of %s: %w", sm.PairID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// PruneSpreads deletes spread samples taken before cutoff
+func (s *Store) PruneSpreads(ctx context.Context, cutoff time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM pair_spreads WHERE at < ?`, cutoff.UnixMilli()); err != nil {
+		return fmt.Errorf("prune spreads: %w", err)
+	}
+	return nil
+}
+
+// Spreads returns a pair's spread samples taken since then, oldest first
+func (s *Store) Spreads(ctx context.Context, pairID string, since time.Time) ([]arb.SpreadSample, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT at, pm_mid, kalshi_mid FROM pair_spreads
+		WHERE pair_id = ? AND at >= ?
+		ORDER BY at`, pairID, since.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("query spreads: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]arb.SpreadSample, 0)
+	for rows.Next() {
+		sm := arb.SpreadSample{PairID: pairID}
+		var at int64
+		if err := rows.Scan(&at, &sm.PMMid, &sm.KalshiMid); err != nil {
+			return nil, fmt.Errorf("scan spread row: %w", err)
+		}
+		sm.At = time.UnixMilli(at).UTC()
+		sm.Spread = sm.PMMid - sm.KalshiMid
+		out = append(out, sm)
+	}
+	return out, rows.Err()
+}
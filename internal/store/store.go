@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	_ "modernc.org/sqlite" // Pure-Go SQLite driver, keeps CGO_ENABLED=0 builds working
+)
+
+// schema is applied on every Open; statements must be idempotent
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS opportunity_history (
+		opp_id        TEXT    NOT NULL,
+		first_seen    INTEGER NOT NULL, -- unix milliseconds
+		last_seen     INTEGER NOT NULL,
+		combo         TEXT    NOT NULL,
+		category      TEXT    NOT NULL,
+		pm_title      TEXT    NOT NULL,
+		kalshi_ticker TEXT    NOT NULL,
+		kalshi_title  TEXT    NOT NULL,
+		pm_instrument     TEXT NOT NULL DEFAULT '', -- canonical instrument IDs of the combo's legs
+		kalshi_instrument TEXT NOT NULL DEFAULT '',
+		peak_edge_pct REAL    NOT NULL,
+		sum_edge_pct  REAL    NOT NULL,
+		samples       INTEGER NOT NULL,
+		PRIMARY KEY (opp_id, first_seen)
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_opportunity_history_first_seen ON opportunity_history (first_seen)`,
+	`CREATE TABLE IF NOT EXISTS opportunity_acks (
+		opp_id     TEXT    NOT NULL,
+		first_seen INTEGER NOT NULL, -- unix milliseconds, the acknowledged streak
+		status     TEXT    NOT NULL,
+		reason     TEXT    NOT NULL,
+		acked_by   TEXT    NOT NULL,
+		acked_at   INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_opportunity_acks_opp_id ON opportunity_acks (opp_id, acked_at)`,
+	`CREATE TABLE IF NOT EXISTS admin_audit (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		at          INTEGER NOT NULL, -- unix milliseconds
+		actor       TEXT    NOT NULL,
+		remote_addr TEXT    NOT NULL,
+		action      TEXT    NOT NULL,
+		target      TEXT    NOT NULL,
+		before_json TEXT    NOT NULL, -- JSON, "null" when the target did not exist
+		after_json  TEXT    NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_admin_audit_at ON admin_audit (at)`,
+	`CREATE TABLE IF NOT EXISTS pair_near_misses (
+		id              TEXT    PRIMARY KEY,
+		pm_condition_id TEXT    NOT NULL,
+		pm_token_yes    TEXT    NOT NULL,
+		pm_title        TEXT    NOT NULL,
+		kalshi_ticker   TEXT    NOT NULL,
+		kalshi_title    TEXT    NOT NULL,
+		score           REAL    NOT NULL,
+		first_seen      INTEGER NOT NULL, -- unix milliseconds
+		status          TEXT    NOT NULL,
+		decided_by      TEXT    NOT NULL,
+		decided_at      INTEGER           -- unix milliseconds, NULL until decided
+	)`,
+	`CREATE TABLE IF NOT EXISTS pair_annotations (
+		pm_condition_id TEXT    NOT NULL,
+		kalshi_ticker   TEXT    NOT NULL,
+		verified        INTEGER NOT NULL, -- 0 or 1
+		note            TEXT    NOT NULL,
+		tags            TEXT    NOT NULL DEFAULT '', -- normalized tags, space-separated
+		annotated_by    TEXT    NOT NULL,
+		annotated_at    INTEGER NOT NULL, -- unix milliseconds
+		PRIMARY KEY (pm_condition_id, kalshi_ticker)
+	)`,
+	`CREATE TABLE IF NOT EXISTS pair_spreads (
+		pair_id    TEXT    NOT NULL, -- arb.MarketPair.ID
+		at         INTEGER NOT NULL, -- unix milliseconds
+		pm_mid     REAL    NOT NULL,
+		kalshi_mid REAL    NOT NULL,
+		PRIMARY KEY (pair_id, at)
+	) WITHOUT ROWID`,
+	`CREATE INDEX IF NOT EXISTS idx_pair_spreads_at ON pair_spreads (at)`,
+	`CREATE TABLE IF NOT EXISTS trade_settlements (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		opp_id      TEXT    NOT NULL,
+		strategy    TEXT    NOT NULL,
+		category    TEXT    NOT NULL,
+		match_score REAL    NOT NULL,
+		combo       TEXT    NOT NULL,
+		reason      TEXT    NOT NULL, -- unwound or settled
+		contracts   REAL    NOT NULL,
+		cost        REAL    NOT NULL, -- per contract
+		payout      REAL    NOT NULL, -- per contract
+		fees        REAL    NOT NULL,
+		pnl         REAL    NOT NULL, -- net of fees
+		opened_at   INTEGER NOT NULL, -- unix milliseconds
+		closed_at   INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_trade_settlements_closed_at ON trade_settlements (closed_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_trade_settlements_opp_id ON trade_settlements (opp_id)`,
+	`CREATE TABLE IF NOT EXISTS ws_connectivity (
+		source    TEXT    NOT NULL, -- ws.ConnectionEvent.Source
+		at        INTEGER NOT NULL, -- unix milliseconds
+		connected INTEGER NOT NULL  -- 0 or 1
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_ws_connectivity_source_at ON ws_connectivity (source, at)`,
+	`CREATE TABLE IF NOT EXISTS alert_history (
+		opp_id    TEXT    PRIMARY KEY,
+		last_seen INTEGER NOT NULL -- unix milliseconds, last seen open after alerting
+	) WITHOUT ROWID`,
+	`CREATE INDEX IF NOT EXISTS idx_alert_history_last_seen ON alert_history (last_seen)`,
+}
+
+// addedColumns are columns introduced after a table was first created; each is
+// added to existing databases on Open if missing
+var addedColumns = []struct {
+	table, column, decl string
+}{
+	{"opportunity_history", "pm_instrument", "TEXT NOT NULL DEFAULT ''"},
+	{"opportunity_history", "kalshi_instrument", "TEXT NOT NULL DEFAULT ''"},
+	{"pair_annotations", "tags", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// Store persists opportunity history and other state to a SQLite database
+type Store struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+// Open opens (creating if needed) the SQLite database at path and applies the schema
+func Open(ctx context.Context, path string, logger *slog.Logger) (*Store, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	// SQLite allows a single writer; serialising through one connection avoids SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("apply schema: %w", err)
+		}
+	}
+
+	for _, c := range addedColumns {
+		if err := addColumnIfMissing(ctx, db, c.table, c.column, c.decl); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("apply schema: %w", err)
+		}
+	}
+
+	if err := applySearchSchema(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("apply schema: %w", err)
+	}
+
+	logger.Info("store opened", "path", path)
+	return &Store{db: db, logger: logger}, nil
+}
+
+// Close closes the underlying database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// addColumnIfMissing adds a column to an existing table unless it is already present
+func addColumnIfMissing(ctx context.Context, db *sql.DB, table, column, decl string) error {
+	var n int
+	err := db.QueryRowContext(ctx,
+		`SELECT count(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&n)
+	if err != nil {
+		return fmt.Errorf("inspect %s: %w", table, err)
+	}
+	if n > 0 {
+		return nil
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl)); err != nil {
+		return fmt.Errorf("add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
@@ -0,0 +1,548 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/shadow"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/arb"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	st, err := Open(context.Background(), ":memory:", logger)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+func TestHeatmap(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	first := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	opp := arb.Opportunity{ID: "a", FirstSeen: first, Timestamp: first, Category: "politics", EdgePctTurn: 4}
+
+	// Two samples of the same streak
+	if err := st.recordOpportunities(ctx, []arb.Opportunity{opp}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	opp.Timestamp = first.Add(time.Second)
+	opp.EdgePctTurn = 6
+	if err := st.recordOpportunities(ctx, []arb.Opportunity{opp}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	// A separate streak in another hour and category
+	other := arb.Opportunity{ID: "b", FirstSeen: first.Add(2 * time.Hour), Timestamp: first.Add(2 * time.Hour), Category: "sports", EdgePctTurn: 3}
+	if err := st.recordOpportunities(ctx, []arb.Opportunity{other}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	cells, err := st.Heatmap(ctx, first.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Heatmap: %v", err)
+	}
+	if len(cells) != 2 {
+		t.Fatalf("expected 2 cells, got %d: %+v", len(cells), cells)
+	}
+
+	c := cells[0]
+	if c.Category != "politics" || c.Hour != 14 || c.Count != 1 {
+		t.Errorf("unexpected first cell %+v", c)
+	}
+	if math.Abs(c.AvgEdgePct-5) > 1e-9 || c.MaxEdgePct != 6 {
+		t.Errorf("expected avg 5 / max 6, got %+v", c)
+	}
+	if cells[1].Category != "sports" || cells[1].Hour != 16 {
+		t.Errorf("unexpected second cell %+v", cells[1])
+	}
+}
+
+func TestRecordBackfill(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	first := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	opp := arb.Opportunity{ID: "a", FirstSeen: first, Timestamp: first, Category: "politics", EdgePctTurn: 4}
+	if err := st.recordOpportunities(ctx, []arb.Opportunity{opp}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	streaks := []arb.BackfilledStreak{
+		{Opportunity: opp, Samples: []arb.EdgeSample{{EdgePctTurn: 8}, {EdgePctTurn: 6}}},
+		{Opportunity: arb.Opportunity{ID: "unrecorded", FirstSeen: first}, Samples: []arb.EdgeSample{{EdgePctTurn: 1}}},
+	}
+	if err := st.RecordBackfill(ctx, streaks); err != nil {
+		t.Fatalf("RecordBackfill: %v", err)
+	}
+
+	cells, err := st.Heatmap(ctx, first.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Heatmap: %v", err)
+	}
+	if len(cells) != 1 || cells[0].Count != 1 {
+		t.Fatalf("expected the one recorded streak, got %+v", cells)
+	}
+	if math.Abs(cells[0].AvgEdgePct-6) > 1e-9 || cells[0].MaxEdgePct != 8 {
+		t.Errorf("expected avg 6 / max 8 over 3 samples, got %+v", cells[0])
+	}
+}
+
+func TestOpenAddsColumnsToExistingDatabase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "old.db")
+
+	// A database created before the instrument columns existed
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE opportunity_history (
+		opp_id TEXT NOT NULL, first_seen INTEGER NOT NULL, last_seen INTEGER NOT NULL,
+		combo TEXT NOT NULL, category TEXT NOT NULL, pm_title TEXT NOT NULL,
+		kalshi_ticker TEXT NOT NULL, kalshi_title TEXT NOT NULL,
+		peak_edge_pct REAL NOT NULL, sum_edge_pct REAL NOT NULL, samples INTEGER NOT NULL,
+		PRIMARY KEY (opp_id, first_seen))`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("create old schema: %v", err)
+	}
+
+	st, err := Open(ctx, path, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	opp := arb.Opportunity{ID: "a", FirstSeen: time.Now(), Timestamp: time.Now(), PMInstrument: "pm:token:1", KalshiInstrument: "kalshi:K:no"}
+	if err := st.recordOpportunities(ctx, []arb.Opportunity{opp}); err != nil {
+		t.Fatalf("record after migration: %v", err)
+	}
+
+	var got string
+	if err := st.db.QueryRow(`SELECT kalshi_instrument FROM opportunity_history`).Scan(&got); err != nil || got != "kalshi:K:no" {
+		t.Errorf("kalshi_instrument = %q, %v", got, err)
+	}
+}
+
+func TestRecordAndListAcks(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	first := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	claimed := arb.Acknowledgment{OppID: "a", FirstSeen: first, Status: arb.AckClaimed, By: "bot-1", At: first.Add(time.Second)}
+	executed := claimed
+	executed.Status, executed.Reason, executed.At = arb.AckExecuted, "filled 10", first.Add(2*time.Second)
+
+	for _, a := range []arb.Acknowledgment{executed, claimed, {OppID: "b", FirstSeen: first, Status: arb.AckSkipped, At: first}} {
+		if err := st.RecordAck(ctx, a); err != nil {
+			t.Fatalf("RecordAck: %v", err)
+		}
+	}
+
+	acks, err := st.Acks(ctx, "a")
+	if err != nil {
+		t.Fatalf("Acks: %v", err)
+	}
+	if len(acks) != 2 || acks[0] != claimed || acks[1] != executed {
+		t.Errorf("unexpected acks %+v", acks)
+	}
+}
+
+func TestRecordAndListAudit(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	entries := []AuditEntry{
+		{At: at, Actor: "key:aaaa", Action: "killswitch.engage", Target: "killswitch", Before: []byte(`{"engaged":false}`), After: []byte(`{"engaged":true}`)},
+		{At: at.Add(time.Minute), Actor: "key:bbbb", Action: "killswitch.release", Target: "killswitch"},
+		{At: at.Add(2 * time.Minute), Actor: "key:aaaa", Action: "killswitch.release", Target: "killswitch"},
+	}
+	for _, e := range entries {
+		if err := st.RecordAudit(ctx, e); err != nil {
+			t.Fatalf("RecordAudit: %v", err)
+		}
+	}
+
+	all, err := st.ListAudit(ctx, AuditQuery{})
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(all) != 3 || all[0].At != entries[2].At || all[2].Action != "killswitch.engage" {
+		t.Fatalf("expected newest first, got %+v", all)
+	}
+	if string(all[2].After) != `{"engaged":true}` || string(all[1].Before) != "null" {
+		t.Errorf("unexpected before/after values %s %s", all[2].After, all[1].Before)
+	}
+
+	filtered, err := st.ListAudit(ctx, AuditQuery{Since: at.Add(time.Minute), Action: "killswitch.release", Actor: "key:aaaa"})
+	if err != nil {
+		t.Fatalf("ListAudit: %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].ID != all[0].ID {
+		t.Errorf("unexpected filtered entries %+v", filtered)
+	}
+
+	limited, err := st.ListAudit(ctx, AuditQuery{Limit: 1})
+	if err != nil || len(limited) != 1 {
+		t.Errorf("expected 1 entry with limit, got %d (%v)", len(limited), err)
+	}
+}
+
+func TestSaveAndLoadNearMisses(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	seen := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	n := arb.NearMiss{ID: "abc", PMTitle: "Fed cut in March?", KalshiTicker: "KXFED", Score: 0.52, FirstSeen: seen, Status: arb.NearMissPending}
+	if err := st.SaveNearMiss(ctx, n); err != nil {
+		t.Fatalf("SaveNearMiss: %v", err)
+	}
+
+	decided := seen.Add(time.Hour)
+	n.Status, n.DecidedBy, n.DecidedAt, n.Score = arb.NearMissPromoted, "key:aaaa", &decided, 0.55
+	if err := st.SaveNearMiss(ctx, n); err != nil {
+		t.Fatalf("SaveNearMiss update: %v", err)
+	}
+
+	got, err := st.NearMisses(ctx)
+	if err != nil {
+		t.Fatalf("NearMisses: %v", err)
+	}
+	if len(got) != 1 || got[0].Status != arb.NearMissPromoted || got[0].Score != 0.55 || !got[0].FirstSeen.Equal(seen) {
+		t.Fatalf("unexpected near-misses %+v", got)
+	}
+	if got[0].DecidedAt == nil || !got[0].DecidedAt.Equal(decided) {
+		t.Errorf("decided_at = %v, want %v", got[0].DecidedAt, decided)
+	}
+}
+
+func TestSaveAndLoadAnnotations(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	a := arb.Annotation{PMConditionID: "C1", KalshiTicker: "KXFED", Note: "check tie rules", By: "key:aaaa", At: at}
+	if err := st.SaveAnnotation(ctx, a); err != nil {
+		t.Fatalf("SaveAnnotation: %v", err)
+	}
+	a.Verified, a.Note, a.Tags = true, "same FOMC source", []string{"fed", "rates"}
+	if err := st.SaveAnnotation(ctx, a); err != nil {
+		t.Fatalf("SaveAnnotation update: %v", err)
+	}
+
+	got, err := st.Annotations(ctx)
+	if err != nil {
+		t.Fatalf("Annotations: %v", err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], a) {
+		t.Fatalf("unexpected annotations %+v", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	first := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	opps := []arb.Opportunity{
+		{ID: "a", FirstSeen: first, Timestamp: first, Category: "economics", PMTitle: "Fed cuts rates in March?", KalshiTicker: "KXFED-26MAR", KalshiTitle: "Fed rate cut in March", EdgePctTurn: 4},
+		{ID: "b", FirstSeen: first, Timestamp: first, Category: "sports", PMTitle: "Lakers win the title?", KalshiTicker: "KXNBA", KalshiTitle: "NBA champion: Lakers", EdgePctTurn: 3},
+	}
+	if err := st.recordOpportunities(ctx, opps); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	// Further samples of a streak must not duplicate its index entry
+	opps[0].Timestamp = first.Add(time.Second)
+	if err := st.recordOpportunities(ctx, opps[:1]); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	pairs := []arb.MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTitle: "Fed cuts rates in March?", KalshiTicker: "KXFED-26MAR", KalshiTitle: "Fed rate cut in March", Category: "economics"},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTitle: "Lakers win the title?", KalshiTicker: "KXNBA", KalshiTitle: "NBA champion: Lakers", Category: "sports"},
+	}
+	annotations := arb.NewAnnotationRegistry([]arb.Annotation{{PMConditionID: "C2", KalshiTicker: "KXNBA", Tags: []string{"Playoffs"}}})
+	if err := st.ReindexPairs(ctx, pairs, annotations); err != nil {
+		t.Fatalf("ReindexPairs: %v", err)
+	}
+
+	got, err := st.Search(ctx, "fed cut", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(got.Pairs) != 1 || got.Pairs[0].KalshiTicker != "KXFED-26MAR" {
+		t.Errorf("unexpected pair hits %+v", got.Pairs)
+	}
+	if len(got.Opportunities) != 1 || got.Opportunities[0].OppID != "a" || !got.Opportunities[0].LastSeen.Equal(first.Add(time.Second)) {
+		t.Errorf("unexpected opportunity hits %+v", got.Opportunities)
+	}
+
+	// Tags are searchable and follow annotation changes
+	if got, _ := st.Search(ctx, "playoffs", 10); len(got.Pairs) != 1 || got.Pairs[0].Tags[0] != "playoffs" {
+		t.Errorf("tag search = %+v", got.Pairs)
+	}
+	if err := st.SetPairTags(ctx, arb.Annotation{PMConditionID: "C2", KalshiTicker: "KXNBA", Tags: []string{"finals"}}); err != nil {
+		t.Fatalf("SetPairTags: %v", err)
+	}
+	if got, _ := st.Search(ctx, "playoffs", 10); len(got.Pairs) != 0 {
+		t.Errorf("stale tag still matches: %+v", got.Pairs)
+	}
+
+	// Query syntax in user input is matched literally
+	if _, err := st.Search(ctx, `lakers" OR NEAR(`, 10); err != nil {
+		t.Errorf("search with operators: %v", err)
+	}
+	if _, err := st.Search(ctx, " -- ", 10); err != ErrEmptyQuery {
+		t.Errorf("expected ErrEmptyQuery, got %v", err)
+	}
+}
+
+func TestSpreads(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	var samples []arb.SpreadSample
+	for i := 0; i < 3; i++ {
+		samples = append(samples,
+			arb.SpreadSample{PairID: "p1", At: at.Add(time.Duration(i) * time.Minute), PMMid: 0.50 + float64(i)/100, KalshiMid: 0.48},
+			arb.SpreadSample{PairID: "p2", At: at.Add(time.Duration(i) * time.Minute), PMMid: 0.20, KalshiMid: 0.25})
+	}
+	if err := st.RecordSpreads(ctx, samples); err != nil {
+		t.Fatalf("RecordSpreads: %v", err)
+	}
+
+	got, err := st.Spreads(ctx, "p1", at.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Spreads: %v", err)
+	}
+	if len(got) != 2 || !got[0].At.Equal(at.Add(time.Minute)) || math.Abs(got[1].Spread-0.04) > 1e-9 {
+		t.Fatalf("unexpected spreads %+v", got)
+	}
+
+	if err := st.PruneSpreads(ctx, at.Add(2*time.Minute)); err != nil {
+		t.Fatalf("PruneSpreads: %v", err)
+	}
+	if got, _ := st.Spreads(ctx, "p2", at); len(got) != 1 {
+		t.Errorf("expected one sample left after pruning, got %+v", got)
+	}
+}
+
+func TestArchiveExport(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{day.Add(time.Hour), day.Add(30 * time.Hour)} {
+		opp := arb.Opportunity{ID: fmt.Sprint("opp-", i), FirstSeen: at, Timestamp: at, Category: "politics", EdgePctTurn: 4}
+		if err := st.recordOpportunities(ctx, []arb.Opportunity{opp}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+
+	oldest, ok, err := st.Oldest(ctx, TableOpportunityHistory, day.Add(48*time.Hour))
+	if err != nil || !ok || !oldest.Equal(day.Add(time.Hour)) {
+		t.Fatalf("Oldest = %v, %v, %v", oldest, ok, err)
+	}
+	if _, ok, _ := st.Oldest(ctx, TableOpportunityHistory, day); ok {
+		t.Error("Oldest found a row before the cutoff")
+	}
+
+	var rows []HistoryRow
+	err = st.ExportHistory(ctx, day, day.Add(24*time.Hour), func(r HistoryRow) error {
+		rows = append(rows, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportHistory: %v", err)
+	}
+	if len(rows) != 1 || rows[0].OppID != "opp-0" || rows[0].Category != "politics" || rows[0].PeakEdgePct != 4 {
+		t.Fatalf("unexpected export %+v", rows)
+	}
+
+	if err := st.DeleteArchived(ctx, TableOpportunityHistory, day, day.Add(48*time.Hour), 1); !errors.Is(err, ErrArchiveChanged) {
+		t.Errorf("expected ErrArchiveChanged, got %v", err)
+	}
+	if err := st.DeleteArchived(ctx, TableOpportunityHistory, day, day.Add(24*time.Hour), 1); err != nil {
+		t.Fatalf("DeleteArchived: %v", err)
+	}
+	if oldest, _, _ := st.Oldest(ctx, TableOpportunityHistory, day.Add(48*time.Hour)); !oldest.Equal(day.Add(30 * time.Hour)) {
+		t.Errorf("expected the second day to remain, oldest is %v", oldest)
+	}
+	if _, _, err := st.Oldest(ctx, "admin_audit", day); err == nil {
+		t.Error("Oldest accepted a table that is not archivable")
+	}
+}
+
+func TestSettlements(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	closed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, oppID := range []string{"a", "b", "a"} {
+		s := shadow.Settlement{
+			OppID: oppID, Strategy: "arbitrage", Category: "politics", MatchScore: 0.8, Combo: arb.ComboPMYesKalshiNo,
+			Reason: shadow.ReasonSettled, Contracts: 10, Cost: 0.95, Payout: 1, Fees: 0.18, PnL: 0.32,
+			OpenedAt: closed.Add(-time.Hour), ClosedAt: closed.Add(time.Duration(i) * time.Hour),
+		}
+		if err := st.RecordSettlement(ctx, s); err != nil {
+			t.Fatalf("RecordSettlement: %v", err)
+		}
+	}
+
+	got, err := st.Settlements(ctx, SettlementQuery{OppID: "a"})
+	if err != nil {
+		t.Fatalf("Settlements: %v", err)
+	}
+	if len(got) != 2 || !got[0].ClosedAt.Equal(closed.Add(2*time.Hour)) || got[0].Fees != 0.18 || got[0].Category != "politics" {
+		t.Fatalf("unexpected settlements %+v", got)
+	}
+	if got, _ := st.Settlements(ctx, SettlementQuery{Since: closed.Add(time.Hour)}); len(got) != 2 {
+		t.Errorf("expected 2 settlements since the cutoff, got %d", len(got))
+	}
+}
+
+func TestConnectivity(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, e := range []ws.ConnectionEvent{
+		{Source: "pm", Connected: true, At: start.Add(-time.Hour)}, // Before the window
+		{Source: "pm", Connected: false, At: start.Add(6 * time.Hour)},
+		{Source: "pm", Connected: true, At: start.Add(7 * time.Hour)},
+		{Source: "kalshi", Connected: true, At: start.Add(2 * time.Hour)},
+	} {
+		if err := st.RecordConnectivity(ctx, e); err != nil {
+			t.Fatalf("RecordConnectivity: %v", err)
+		}
+	}
+	// A restart closes kalshi's session, and pm's too
+	restart := start.Add(8 * time.Hour)
+	if err := st.CloseConnectivity(ctx, restart); err != nil {
+		t.Fatalf("CloseConnectivity: %v", err)
+	}
+
+	got, err := st.Connectivity(ctx, start, start.Add(10*time.Hour))
+	if err != nil {
+		t.Fatalf("Connectivity: %v", err)
+	}
+	if len(got) != 2 || got[0].Source != "kalshi" || got[1].Source != "pm" {
+		t.Fatalf("unexpected sources %+v", got)
+	}
+
+	kalshi := got[0]
+	if kalshi.KnownSeconds != 8*3600 || kalshi.ConnectedSeconds != 6*3600 || kalshi.Disconnects != 1 || len(kalshi.Periods) != 2 {
+		t.Errorf("unexpected kalshi connectivity %+v", kalshi)
+	}
+
+	pm := got[1]
+	if pm.KnownSeconds != 10*3600 || pm.ConnectedSeconds != 7*3600 || pm.Disconnects != 2 {
+		t.Errorf("unexpected pm connectivity %+v", pm)
+	}
+	if math.Abs(pm.Uptime-0.7) > 1e-9 {
+		t.Errorf("pm uptime = %v, want 0.7", pm.Uptime)
+	}
+	want := []bool{true, false, true, false}
+	if len(pm.Periods) != len(want) || !pm.Periods[0].From.Equal(start) || !pm.Periods[3].Ongoing {
+		t.Fatalf("unexpected pm periods %+v", pm.Periods)
+	}
+	for i, p := range pm.Periods {
+		if p.Connected != want[i] {
+			t.Errorf("period %d connected = %v, want %v", i, p.Connected, want[i])
+		}
+	}
+}
+
+func TestAlertHistory(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	at := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	if err := st.RecordAlerts(ctx, map[string]time.Time{"a": at, "b": at}); err != nil {
+		t.Fatalf("RecordAlerts: %v", err)
+	}
+	// b is still open a minute later; an out-of-order earlier write never rewinds it
+	later := at.Add(time.Minute)
+	if err := st.RecordAlerts(ctx, map[string]time.Time{"b": later}); err != nil {
+		t.Fatalf("RecordAlerts: %v", err)
+	}
+	if err := st.RecordAlerts(ctx, map[string]time.Time{"b": at}); err != nil {
+		t.Fatalf("RecordAlerts: %v", err)
+	}
+
+	got, err := st.AlertedSince(ctx, at)
+	if err != nil {
+		t.Fatalf("AlertedSince: %v", err)
+	}
+	if len(got) != 2 || !got["a"].Equal(at) || !got["b"].Equal(later) {
+		t.Fatalf("unexpected alert history %v", got)
+	}
+
+	if err := st.PruneAlerts(ctx, later); err != nil {
+		t.Fatalf("PruneAlerts: %v", err)
+	}
+	if got, _ := st.AlertedSince(ctx, at); len(got) != 1 || got["b"].IsZero() {
+		t.Errorf("expected only b left after pruning, got %v", got)
+	}
+}
+
+func TestCalibrationSamples(t *testing.T) {
+	st := openTestStore(t)
+	ctx := context.Background()
+
+	first := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	last := first.Add(time.Minute)
+	if err := st.recordOpportunities(ctx, []arb.Opportunity{
+		{ID: "a", FirstSeen: first, Timestamp: first, Category: "politics", EdgePctTurn: 4},
+		{ID: "b", FirstSeen: first, Timestamp: first, Category: "sports", EdgePctTurn: 2},
+	}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := st.recordOpportunities(ctx, []arb.Opportunity{
+		{ID: "a", FirstSeen: first, Timestamp: last, Category: "politics", EdgePctTurn: 6},
+	}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	for _, s := range []shadow.Settlement{
+		{OppID: "a", Strategy: arb.StrategyArbitrage, PnL: 0.5, OpenedAt: first.Add(time.Second), ClosedAt: last},
+		{OppID: "a", Strategy: arb.StrategyDivergence, PnL: 9, OpenedAt: first.Add(time.Second), ClosedAt: last},
+		{OppID: "a", Strategy: arb.StrategyArbitrage, PnL: 9, OpenedAt: last.Add(time.Hour), ClosedAt: last.Add(2 * time.Hour)},
+	} {
+		if err := st.RecordSettlement(ctx, s); err != nil {
+			t.Fatalf("RecordSettlement: %v", err)
+		}
+	}
+
+	got, err := st.CalibrationSamples(ctx, first.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("CalibrationSamples: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 streaks, got %+v", got)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Category < got[j].Category })
+	if a := got[0]; a.EdgePct != 6 || a.Duration != time.Minute || a.Trades != 1 || a.PnL != 0.5 {
+		t.Errorf("expected the arbitrage trade opened during the streak, got %+v", a)
+	}
+	if b := got[1]; b.Trades != 0 || b.PnL != 0 {
+		t.Errorf("expected an untraded streak, got %+v", b)
+	}
+}
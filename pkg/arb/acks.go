@@ -0,0 +1,133 @@
+package arb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Acknowledgment statuses a consumer may report for an opportunity
+const (
+	AckClaimed  = "claimed"
+	AckExecuted = "executed"
+	AckSkipped  = "skipped"
+)
+
+// ErrOpportunityNotOpen is returned when acknowledging an opportunity that is
+// not in the current list
+var ErrOpportunityNotOpen = errors.New("opportunity not open")
+
+// Acknowledgment records a downstream consumer claiming, executing or
+// skipping an opportunity. It applies to one streak of the opportunity: once
+// the opportunity disappears, a later return is unacknowledged.
+type Acknowledgment struct {
+	OppID     string    `json:"opp_id"`
+	FirstSeen time.Time `json:"first_seen"` // Streak the acknowledgment applies to
+	Status    string    `json:"status"`     // claimed, executed or skipped
+	Reason    string    `json:"reason,omitempty"`
+	By        string    `json:"by,omitempty"` // Consumer that acknowledged
+	At        time.Time `json:"at"`
+}
+
+// ValidAckStatus reports whether status is a known acknowledgment status
+func ValidAckStatus(status string) bool {
+	switch status {
+	case AckClaimed, AckExecuted, AckSkipped:
+		return true
+	}
+	return false
+}
+
+// ackRegistry holds the latest acknowledgment of each open opportunity streak
+type ackRegistry struct {
+	mu   sync.RWMutex
+	acks map[string]Acknowledgment // opportunity ID -> latest acknowledgment
+}
+
+func newAckRegistry() *ackRegistry {
+	return &ackRegistry{acks: make(map[string]Acknowledgment)}
+}
+
+// get returns the acknowledgment of an opportunity's current streak
+func (r *ackRegistry) get(id string, firstSeen time.Time) (Acknowledgment, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.acks[id]
+	if !ok || !a.FirstSeen.Equal(firstSeen) {
+		return Acknowledgment{}, false
+	}
+	return a, true
+}
+
+// set records a, replacing any earlier acknowledgment of the opportunity
+func (r *ackRegistry) set(a Acknowledgment) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.acks[a.OppID] = a
+}
+
+// retain drops acknowledgments whose streak has ended
+func (r *ackRegistry) retain(tracks map[string]*edgeTrack) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, a := range r.acks {
+		if track, ok := tracks[id]; !ok || !track.firstSeen.Equal(a.FirstSeen) {
+			delete(r.acks, id)
+		}
+	}
+}
+
+// Acknowledge records a consumer's acknowledgment of an open opportunity. The
+// acknowledgment is attached to the opportunity immediately and on every
+// later cycle of the same streak. Claims and executions of an opportunity
+// past its execution window return ErrOpportunityExpired; skips are still
+// recorded.
+func (e *Engine) Acknowledge(id, status, reason, by string) (Acknowledgment, error) {
+	if !ValidAckStatus(status) {
+		return Acknowledgment{}, fmt.Errorf("invalid status %q, want %s, %s or %s", status, AckClaimed, AckExecuted, AckSkipped)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.opportunities {
+		if e.opportunities[i].ID != id {
+			continue
+		}
+		now := time.Now()
+		if status != AckSkipped && !e.opportunities[i].Executable(now) {
+			return Acknowledgment{}, ErrOpportunityExpired
+		}
+		a := Acknowledgment{
+			OppID:     id,
+			FirstSeen: e.opportunities[i].FirstSeen,
+			Status:    status,
+			Reason:    reason,
+			By:        by,
+			At:        now,
+		}
+		e.acks.set(a)
+
+		// Hooks may still hold the published slice; replace it rather than write through
+		opps := make([]Opportunity, len(e.opportunities))
+		copy(opps, e.opportunities)
+		opps[i].Ack = &a
+		e.opportunities = opps
+		e.revision++
+		e.publish()
+		return a, nil
+	}
+	return Acknowledgment{}, ErrOpportunityNotOpen
+}
+
+// Acknowledged returns the acknowledgment of an open opportunity, if any
+func (e *Engine) Acknowledged(id string) (Acknowledgment, bool) {
+	e.mu.RLock()
+	track, ok := e.tracks[id]
+	e.mu.RUnlock()
+	if !ok {
+		return Acknowledgment{}, false
+	}
+	return e.acks.get(id, track.firstSeen)
+}
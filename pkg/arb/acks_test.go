@@ -0,0 +1,65 @@
+package arb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcknowledgeAppliesToCurrentStreak(t *testing.T) {
+	first := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	published := []Opportunity{{ID: "a", FirstSeen: first}, {ID: "b", FirstSeen: first}}
+	e := &Engine{
+		opportunities: published,
+		tracks:        map[string]*edgeTrack{"a": {firstSeen: first}, "b": {firstSeen: first}},
+		acks:          newAckRegistry(),
+	}
+
+	if _, err := e.Acknowledge("a", "done", "", ""); err == nil {
+		t.Error("expected error for invalid status")
+	}
+	if _, err := e.Acknowledge("missing", AckClaimed, "", ""); !errors.Is(err, ErrOpportunityNotOpen) {
+		t.Errorf("expected ErrOpportunityNotOpen, got %v", err)
+	}
+
+	a, err := e.Acknowledge("a", AckClaimed, "taking it", "bot-1")
+	if err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	if a.FirstSeen != first || a.By != "bot-1" {
+		t.Errorf("unexpected acknowledgment %+v", a)
+	}
+	if published[0].Ack != nil {
+		t.Error("the previously published slice must not be modified")
+	}
+	if got := e.Snapshot().Opportunities; got[0].Ack == nil || got[0].Ack.Status != AckClaimed || got[1].Ack != nil {
+		t.Errorf("ack not attached to current opportunities: %+v", got)
+	}
+	if _, ok := e.Acknowledged("a"); !ok {
+		t.Error("a should be acknowledged")
+	}
+
+	// The opportunity disappears and returns as a new streak
+	e.acks.retain(map[string]*edgeTrack{"b": {firstSeen: first}})
+	e.tracks = map[string]*edgeTrack{"a": {firstSeen: first.Add(time.Minute)}}
+	if _, ok := e.Acknowledged("a"); ok {
+		t.Error("a new streak must not inherit the acknowledgment")
+	}
+}
+
+func TestAcknowledgeBumpsRevision(t *testing.T) {
+	first := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	e := &Engine{
+		opportunities: []Opportunity{{ID: "a", FirstSeen: first}},
+		tracks:        map[string]*edgeTrack{"a": {firstSeen: first}},
+		acks:          newAckRegistry(),
+	}
+
+	before := e.Snapshot().Revision
+	if _, err := e.Acknowledge("a", AckSkipped, "", ""); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	if after := e.Snapshot().Revision; after == before {
+		t.Error("acknowledging must change the revision")
+	}
+}
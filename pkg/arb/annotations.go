@@ -0,0 +1,161 @@
+package arb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Annotation is an operator's note on how far to trust a pairing. It is
+// carried in every opportunity on the pair and in its alerts.
+type Annotation struct {
+	PMConditionID string    `json:"pm_condition_id"`
+	KalshiTicker  string    `json:"kalshi_ticker"`
+	Verified      bool      `json:"verified"` // An operator confirmed both markets resolve alike
+	Note          string    `json:"note"`
+	Tags          []string  `json:"tags,omitempty"` // Operator-defined labels for filtering, see NormalizeTags
+	By            string    `json:"by,omitempty"`
+	At            time.Time `json:"at"` // Zero for annotations from the mapping file
+}
+
+// HasTag reports whether the annotation carries tag, compared as normalized
+func (a Annotation) HasTag(tag string) bool {
+	tag = normalizeTag(tag)
+	for _, t := range a.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTags lowercases and trims tags, joins words with dashes and drops
+// empty and repeated ones, keeping the first-seen order
+func NormalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = normalizeTag(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+func normalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+}
+
+// annotationKey identifies the pair an annotation applies to
+func annotationKey(pmConditionID, kalshiTicker string) string {
+	return pmConditionID + "|" + kalshiTicker
+}
+
+// AnnotationRegistry holds operator annotations of pairs
+type AnnotationRegistry struct {
+	mu       sync.RWMutex
+	items    map[string]Annotation
+	onChange func(Annotation)
+}
+
+// NewAnnotationRegistry creates a registry seeded with annotations, later
+// ones replacing earlier ones for the same pair
+func NewAnnotationRegistry(seed ...[]Annotation) *AnnotationRegistry {
+	r := &AnnotationRegistry{items: make(map[string]Annotation)}
+	for _, annotations := range seed {
+		for _, a := range annotations {
+			a.Tags = NormalizeTags(a.Tags)
+			r.items[annotationKey(a.PMConditionID, a.KalshiTicker)] = a
+		}
+	}
+	return r
+}
+
+// OnChange installs a callback invoked with every annotation set, e.g. to
+// persist it. Call before Set.
+func (r *AnnotationRegistry) OnChange(fn func(Annotation)) {
+	r.onChange = fn
+}
+
+// Set adds or replaces the annotation of a pair, returning the previous one.
+// Its tags are normalized.
+func (r *AnnotationRegistry) Set(a Annotation) (before Annotation, existed bool, err error) {
+	if a.PMConditionID == "" || a.KalshiTicker == "" {
+		return Annotation{}, false, errors.New("pm_condition_id and kalshi_ticker are required")
+	}
+
+	a.Tags = NormalizeTags(a.Tags)
+	key := annotationKey(a.PMConditionID, a.KalshiTicker)
+	r.mu.Lock()
+	before, existed = r.items[key]
+	r.items[key] = a
+	r.mu.Unlock()
+
+	if r.onChange != nil {
+		r.onChange(a)
+	}
+	return before, existed, nil
+}
+
+// Get returns the annotation of a pair, if any
+func (r *AnnotationRegistry) Get(pmConditionID, kalshiTicker string) (Annotation, bool) {
+	if r == nil {
+		return Annotation{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.items[annotationKey(pmConditionID, kalshiTicker)]
+	return a, ok
+}
+
+// List returns every annotation ordered by Kalshi ticker
+func (r *AnnotationRegistry) List() []Annotation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Annotation, 0, len(r.items))
+	for _, a := range r.items {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].KalshiTicker != out[j].KalshiTicker {
+			return out[i].KalshiTicker < out[j].KalshiTicker
+		}
+		return out[i].PMConditionID < out[j].PMConditionID
+	})
+	return out
+}
+
+// LoadAnnotationsFile reads a mapping file: a JSON array of annotations, e.g.
+//
+//	[{"pm_condition_id": "0xabc...", "kalshi_ticker": "KXFED-26MAR", "verified": true, "note": "same FOMC source", "tags": ["fed"]}]
+func LoadAnnotationsFile(path string) ([]Annotation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read annotations file: %w", err)
+	}
+	var annotations []Annotation
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, fmt.Errorf("parse annotations file: %w", err)
+	}
+	for i, a := range annotations {
+		if a.PMConditionID == "" || a.KalshiTicker == "" {
+			return nil, fmt.Errorf("annotations file entry %d: pm_condition_id and kalshi_ticker are required", i)
+		}
+	}
+	return annotations, nil
+}
+
+// SetAnnotations installs the registry of pair annotations attached to each
+// opportunity. Call before Start.
+func (e *Engine) SetAnnotations(r *AnnotationRegistry) {
+	e.annotations = r
+}
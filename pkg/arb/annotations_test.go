@@ -0,0 +1,83 @@
+package arb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestLoadAnnotationsFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pairs.json")
+	content := `[{"pm_condition_id": "C1", "kalshi_ticker": "K1", "verified": true, "note": "same FOMC source"}]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadAnnotationsFile(path)
+	if err != nil || len(got) != 1 || !got[0].Verified || got[0].Note != "same FOMC source" {
+		t.Fatalf("LoadAnnotationsFile = %+v, %v", got, err)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`[{"kalshi_ticker": "K1"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAnnotationsFile(bad); err == nil {
+		t.Error("entry without a condition ID accepted")
+	}
+}
+
+func TestAnnotationsCarriedIntoOpportunities(t *testing.T) {
+	pair := MarketPair{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"}
+	e := newTestEngine(t, []MarketPair{pair})
+
+	// API edits, seeded last, override the mapping file
+	file := []Annotation{{PMConditionID: "C1", KalshiTicker: "K1", Note: "from file"}}
+	persisted := []Annotation{{PMConditionID: "C1", KalshiTicker: "K1", Verified: true, Note: "checked rules", By: "ops"}}
+	r := NewAnnotationRegistry(file, persisted)
+	var saved []Annotation
+	r.OnChange(func(a Annotation) { saved = append(saved, a) })
+	e.SetAnnotations(r)
+
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "Y1", Ask: 0.40, UpdatedAt: now},
+		{TokenID: "N1", Ask: 0.62, UpdatedAt: now},
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now}})
+	e.computeOpportunities()
+	opps := e.Snapshot().Opportunities
+	if len(opps) != 1 || opps[0].Annotation == nil || !opps[0].Annotation.Verified || opps[0].Annotation.Note != "checked rules" {
+		t.Fatalf("expected the persisted annotation on the opportunity, got %+v", opps)
+	}
+
+	if _, _, err := r.Set(Annotation{KalshiTicker: "K1"}); err == nil {
+		t.Error("annotation without a condition ID accepted")
+	}
+	before, existed, err := r.Set(Annotation{PMConditionID: "C1", KalshiTicker: "K1", Note: "rules differ on ties", At: now})
+	if err != nil || !existed || before.Note != "checked rules" || len(saved) != 1 {
+		t.Fatalf("Set = %+v, %v, %v; saved %d", before, existed, err, len(saved))
+	}
+	e.computeOpportunities()
+	if a := e.Snapshot().Opportunities[0].Annotation; a == nil || a.Verified {
+		t.Errorf("expected the updated unverified annotation, got %+v", a)
+	}
+	if got := r.List(); len(got) != 1 {
+		t.Errorf("expected one annotation, got %+v", got)
+	}
+
+	if _, _, err := r.Set(Annotation{PMConditionID: "C1", KalshiTicker: "K1", Tags: []string{" Rate Cuts ", "fed", "FED", ""}}); err != nil {
+		t.Fatal(err)
+	}
+	e.computeOpportunities()
+	a := e.Snapshot().Opportunities[0].Annotation
+	if a == nil || len(a.Tags) != 2 || a.Tags[0] != "rate-cuts" || a.Tags[1] != "fed" {
+		t.Fatalf("expected normalized tags on the opportunity, got %+v", a)
+	}
+	if !a.HasTag("Rate cuts") || a.HasTag("ecb") {
+		t.Error("HasTag does not compare normalized tags")
+	}
+}
@@ -0,0 +1,93 @@
+package arb
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MinAnnualizationHorizon is the shortest time to resolution returns are
+// annualized over. Markets resolving within it, or already past their
+// expected resolution and awaiting settlement, count as a day away, so a
+// minutes-long horizon does not multiply an edge into nonsense.
+const MinAnnualizationHorizon = 24 * time.Hour
+
+// year annualizes returns, simple rather than compounded
+const year = 365 * 24 * time.Hour
+
+// resolvesAt returns when the capital of an opportunity is released: the
+// later of the two markets' expected resolutions, or the one reported
+func (o *Opportunity) resolvesAt() (time.Time, bool) {
+	var at time.Time
+	for _, t := range []*time.Time{o.Market.PMResolutionTime, o.Market.KalshiResolutionTime} {
+		if t != nil && t.After(at) {
+			at = *t
+		}
+	}
+	return at, !at.IsZero()
+}
+
+// annualize returns an opportunity's return on capital per year until
+// resolution, in percent, or nil when neither venue reports when the
+// markets resolve. Returns after fees are used when Capital is set, ROI on
+// turnover otherwise.
+func annualize(o *Opportunity, now time.Time) *float64 {
+	at, ok := o.resolvesAt()
+	if !ok {
+		return nil
+	}
+	horizon := max(at.Sub(now), MinAnnualizationHorizon)
+	ret := o.EdgePctTurn
+	if o.Capital != nil {
+		ret = o.Capital.ReturnOnCapital
+	}
+	annual := ret * float64(year) / float64(horizon)
+	return &annual
+}
+
+// Opportunity sort keys, see SortOpportunities
+const (
+	SortEdge             = "edge_pct_turn"
+	SortAnnualizedReturn = "annualized_return"
+	SortReturnOnCapital  = "return_on_capital"
+	SortConfidence       = "confidence"
+)
+
+// opportunitySortKeys maps each sort key to the value it orders by and
+// whether the opportunity has one
+var opportunitySortKeys = map[string]func(Opportunity) (float64, bool){
+	SortEdge: func(o Opportunity) (float64, bool) { return o.EdgePctTurn, true },
+	SortAnnualizedReturn: func(o Opportunity) (float64, bool) {
+		if o.AnnualizedReturn == nil {
+			return 0, false
+		}
+		return *o.AnnualizedReturn, true
+	},
+	SortReturnOnCapital: func(o Opportunity) (float64, bool) {
+		if o.Capital == nil {
+			return 0, false
+		}
+		return o.Capital.ReturnOnCapital, true
+	},
+	SortConfidence: func(o Opportunity) (float64, bool) { return o.Confidence, true },
+}
+
+// SortOpportunities sorts opportunities by a key in descending order:
+// edge_pct_turn (the engine's own order), annualized_return,
+// return_on_capital or confidence. Opportunities without a value for the
+// key go last, in their current order.
+func SortOpportunities(opps []Opportunity, key string) error {
+	value, ok := opportunitySortKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown sort key %q", key)
+	}
+	sort.SliceStable(opps, func(i, j int) bool {
+		vi, iok := value(opps[i])
+		vj, jok := value(opps[j])
+		if iok != jok {
+			return iok
+		}
+		return vi > vj
+	})
+	return nil
+}
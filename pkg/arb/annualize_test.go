@@ -0,0 +1,65 @@
+package arb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnnualize(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	tests := []struct {
+		name   string
+		opp    Opportunity
+		want   float64
+		wantOK bool
+	}{
+		{name: "no resolution time", opp: Opportunity{EdgePctTurn: 5}},
+		{name: "six months on turnover", opp: Opportunity{EdgePctTurn: 5,
+			Market: MarketMetadata{PMResolutionTime: at(year / 2)}}, want: 10, wantOK: true},
+		{name: "later venue counts", opp: Opportunity{EdgePctTurn: 5,
+			Market: MarketMetadata{PMResolutionTime: at(year / 4), KalshiResolutionTime: at(year / 2)}}, want: 10, wantOK: true},
+		{name: "capital after fees", opp: Opportunity{EdgePctTurn: 5, Capital: &Capital{ReturnOnCapital: 2},
+			Market: MarketMetadata{KalshiResolutionTime: at(year / 2)}}, want: 4, wantOK: true},
+		{name: "resolving in an hour counts as a day", opp: Opportunity{EdgePctTurn: 2,
+			Market: MarketMetadata{KalshiResolutionTime: at(time.Hour)}}, want: 730, wantOK: true},
+		{name: "awaiting settlement", opp: Opportunity{EdgePctTurn: 2,
+			Market: MarketMetadata{KalshiResolutionTime: at(-time.Hour)}}, want: 730, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := annualize(&tt.opp, now)
+			if (got != nil) != tt.wantOK || (got != nil && !near(*got, tt.want)) {
+				t.Errorf("annualize = %v, want %v (known %v)", got, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSortOpportunities(t *testing.T) {
+	annual := func(v float64) *float64 { return &v }
+	opps := []Opportunity{
+		{ID: "unknown", EdgePctTurn: 9},
+		{ID: "slow", EdgePctTurn: 5, AnnualizedReturn: annual(10)},
+		{ID: "fast", EdgePctTurn: 2, AnnualizedReturn: annual(730)},
+	}
+	if err := SortOpportunities(opps, SortAnnualizedReturn); err != nil {
+		t.Fatal(err)
+	}
+	if opps[0].ID != "fast" || opps[1].ID != "slow" || opps[2].ID != "unknown" {
+		t.Errorf("sorted by annualized return: %s, %s, %s", opps[0].ID, opps[1].ID, opps[2].ID)
+	}
+	if err := SortOpportunities(opps, "volume"); err == nil {
+		t.Error("unknown sort key accepted")
+	}
+
+	chain, err := BuildFilters([]string{"annualized_return"}, FilterParams{MinAnnualizedReturn: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := chain.Apply(opps, time.Now(), nil)
+	if len(kept) != 2 || kept[0].ID != "fast" || kept[1].ID != "unknown" {
+		t.Errorf("annualized_return filter kept %+v", kept)
+	}
+}
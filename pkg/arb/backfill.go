@@ -0,0 +1,82 @@
+package arb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// BackfilledStreak is an open opportunity streak with the edge samples
+// recovered for a feed gap
+type BackfilledStreak struct {
+	Opportunity Opportunity // Latest state; ID and FirstSeen identify the streak
+	Samples     []EdgeSample
+}
+
+// SetKalshiOutageCheck installs a predicate reporting whether the Kalshi
+// feed is down. While it is, streaks of opportunities that drop out are held
+// rather than ended, so edges still there on reconnect keep their first-seen
+// time and BackfillKalshi can fill the gap. Call before Start.
+func (e *Engine) SetKalshiOutageCheck(fn func() bool) {
+	e.kalshiOutage = fn
+}
+
+// BackfillKalshi fills the from-to gap in the trajectories of open
+// opportunities from historical Kalshi quotes, keyed by ticker (e.g. from the
+// candlestick API after a disconnect). Each historical quote is evaluated
+// against the pair's current Polymarket quotes, which stayed live through the
+// Kalshi outage. Filters are not applied, the quotes being old by design,
+// and samples from before a streak's first-seen time are ignored. It returns
+// the streaks that gained samples.
+func (e *Engine) BackfillKalshi(from, to time.Time, history map[string][]ws.KalshiPriceUpdate) []BackfilledStreak {
+	samples := make(map[string][]EdgeSample)
+	for _, pair := range e.Pairs() {
+		quotes := history[pair.KalshiTicker]
+		if len(quotes) == 0 {
+			continue
+		}
+		pmYes, pmOk := e.pmClient.GetQuote(pair.PMYes())
+		pmNo, pmNoOk := e.pmClient.GetQuote(pair.PMNo())
+		if !pmOk || !pmNoOk || pmYes.Ask == 0 || pmNo.Ask == 0 {
+			continue
+		}
+
+		for _, q := range quotes {
+			if q.UpdatedAt.Before(from) || q.UpdatedAt.After(to) {
+				continue
+			}
+			state := newPairState(e.pmRules.Apply(pair), pmYes, pmNo, q)
+			state.Fees = e.fees
+			for _, strategy := range e.strategies {
+				for _, o := range strategy.Evaluate(state) {
+					samples[o.ID] = append(samples[o.ID], EdgeSample{Timestamp: q.UpdatedAt, EdgeAbs: o.EdgeAbs, EdgePctTurn: o.EdgePctTurn})
+				}
+			}
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	streaks := make([]BackfilledStreak, 0)
+	for _, o := range e.opportunities {
+		track, ok := e.tracks[o.ID]
+		if !ok {
+			continue
+		}
+		filled := make([]EdgeSample, 0, len(samples[o.ID]))
+		for _, s := range samples[o.ID] {
+			if !s.Timestamp.Before(track.firstSeen) {
+				filled = append(filled, s)
+			}
+		}
+		if len(filled) == 0 {
+			continue
+		}
+		sort.Slice(filled, func(i, j int) bool { return filled[i].Timestamp.Before(filled[j].Timestamp) })
+		track.fill(from, to, filled)
+		streaks = append(streaks, BackfilledStreak{Opportunity: o, Samples: filled})
+	}
+	return streaks
+}
@@ -0,0 +1,81 @@
+package arb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestStreakHeldThroughKalshiOutageAndBackfilled(t *testing.T) {
+	pair := MarketPair{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"}
+	e := newTestEngine(t, []MarketPair{pair})
+	outage := false
+	e.SetKalshiOutageCheck(func() bool { return outage })
+
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "Y1", Ask: 0.40, UpdatedAt: now},
+		{TokenID: "N1", Ask: 0.62, UpdatedAt: now},
+	})
+	kalshi := func(yesBid float64, at time.Time) ws.KalshiPriceUpdate {
+		return ws.KalshiPriceUpdate{Ticker: "K1", YesBid: yesBid, YesAsk: yesBid + 0.02, NoBid: 1 - yesBid - 0.02, NoAsk: 1 - yesBid, UpdatedAt: at}
+	}
+	edge, noEdge := 0.43, 0.36 // PM-YES + K-NO costs 0.97 and 1.04
+
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{kalshi(edge, now)})
+	e.computeOpportunities()
+	opps := e.Snapshot().Opportunities
+	if len(opps) != 1 {
+		t.Fatalf("expected one opportunity, got %d", len(opps))
+	}
+	id, firstSeen := opps[0].ID, opps[0].FirstSeen
+
+	// The edge drops out while Kalshi is down: the streak is held
+	outage = true
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{kalshi(noEdge, now.Add(time.Second))})
+	e.computeOpportunities()
+	if len(e.Snapshot().Opportunities) != 0 {
+		t.Fatal("expected no opportunity during the outage")
+	}
+
+	// Back after reconnect: same streak
+	outage = false
+	from, to := now.Add(time.Second), now.Add(3*time.Minute)
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{kalshi(edge, to.Add(time.Second))})
+	e.computeOpportunities()
+	opps = e.Snapshot().Opportunities
+	if len(opps) != 1 || !opps[0].FirstSeen.Equal(firstSeen) {
+		t.Fatalf("expected the streak to continue from %v, got %+v", firstSeen, opps)
+	}
+
+	history := map[string][]ws.KalshiPriceUpdate{"K1": {
+		kalshi(edge, from.Add(time.Minute)),
+		kalshi(noEdge, from.Add(2*time.Minute)),
+		kalshi(edge, to.Add(time.Hour)), // Outside the gap
+	}}
+	streaks := e.BackfillKalshi(from, to, history)
+	if len(streaks) != 1 || streaks[0].Opportunity.ID != id || len(streaks[0].Samples) != 1 {
+		t.Fatalf("expected one backfilled sample for %s, got %+v", id, streaks)
+	}
+
+	h, _ := e.GetOpportunityHistory(context.Background(), id)
+	var found bool
+	for i, s := range h.Samples {
+		if i > 0 && s.Timestamp.Before(h.Samples[i-1].Timestamp) {
+			t.Error("history out of order after backfill")
+		}
+		found = found || s.Timestamp.Equal(from.Add(time.Minute))
+	}
+	if !found {
+		t.Error("backfilled sample missing from history")
+	}
+
+	// Outside an outage, edges that drop out end their streak
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{kalshi(noEdge, to.Add(2*time.Second))})
+	e.computeOpportunities()
+	if h, err := e.GetOpportunityHistory(context.Background(), id); err != nil || h.EndedAt == nil {
+		t.Error("streak not ended after the outage")
+	}
+}
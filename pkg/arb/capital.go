@@ -0,0 +1,54 @@
+package arb
+
+// LegCapital is the cash one leg of an opportunity ties up
+type LegCapital struct {
+	Price float64 `json:"price"` // Per contract, at the venue's tick
+	Cost  float64 `json:"cost"`  // Contracts × price, USD
+	Fee   float64 `json:"fee"`   // Taker and relayer fees, USD, see FeeSchedule
+
+	// Collateral is the cash locked until resolution, USD. Polymarket is
+	// prepaid in full; Kalshi margins a bought contract at its maximum loss,
+	// which is its price, so both lock the cost. Fees are paid on top.
+	Collateral float64 `json:"collateral"`
+}
+
+// Capital is what executing an opportunity at its size ties up and returns,
+// fees included: ROI on the cash actually locked rather than on turnover
+type Capital struct {
+	Contracts       float64    `json:"contracts"` // MaxSize, or 1 when the size is unknown
+	PM              LegCapital `json:"pm"`
+	Kalshi          LegCapital `json:"kalshi"`
+	Required        float64    `json:"required"`          // Both legs' collateral, USD
+	Payout          float64    `json:"payout"`            // $1 per contract at resolution, USD
+	NetEdge         float64    `json:"net_edge"`          // Payout − Required, USD
+	ReturnOnCapital float64    `json:"return_on_capital"` // NetEdge / Required × 100
+}
+
+// newCapital sizes an opportunity's legs at the given per-contract prices,
+// before fees
+func newCapital(contracts, pmPrice, kalshiPrice float64) *Capital {
+	if contracts <= 0 {
+		contracts = 1
+	}
+	c := &Capital{
+		Contracts: contracts,
+		PM:        LegCapital{Price: pmPrice, Cost: contracts * pmPrice},
+		Kalshi:    LegCapital{Price: kalshiPrice, Cost: contracts * kalshiPrice},
+	}
+	c.charge(FeeSchedule{})
+	return c
+}
+
+// charge prices each leg's fees and totals the capital; nil is left alone
+func (c *Capital) charge(fees FeeSchedule) {
+	if c == nil {
+		return
+	}
+	c.PM.Fee, c.Kalshi.Fee = fees.legFees(c.Contracts, c.PM.Price, c.Kalshi.Price)
+	c.PM.Collateral = c.PM.Cost + c.PM.Fee
+	c.Kalshi.Collateral = c.Kalshi.Cost + c.Kalshi.Fee
+	c.Required = c.PM.Collateral + c.Kalshi.Collateral
+	c.Payout = c.Contracts
+	c.NetEdge = c.Payout - c.Required
+	c.ReturnOnCapital = ComputeROI(c.NetEdge, c.Required)
+}
@@ -0,0 +1,55 @@
+package arb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestOpportunityCapital(t *testing.T) {
+	pair := MarketPair{PMConditionID: "c1", PMTokenYes: "y1", PMTokenNo: "n1", KalshiTicker: "K1"}
+	e := newTestEngine(t, []MarketPair{pair})
+	e.SetFeeRates(0, 0.07)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "y1", Ask: 0.40, AskSize: 100.6, UpdatedAt: now},
+		{TokenID: "n1", Ask: 0.62, UpdatedAt: now},
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now}})
+
+	e.computeOpportunities()
+	opps := e.Snapshot().Opportunities
+	if len(opps) != 1 || opps[0].Capital == nil {
+		t.Fatalf("opportunities = %+v, want one with capital", opps)
+	}
+	c := opps[0].Capital
+
+	// 100 contracts: $40 prepaid on Polymarket, $57 margined on Kalshi plus
+	// a fee of 0.07 × 100 × 0.57 × 0.43 = $1.7157, rounded up to $1.72
+	if c.Contracts != 100 || !near(c.PM.Cost, 40) || c.PM.Fee != 0 || !near(c.PM.Collateral, 40) {
+		t.Errorf("pm leg = %+v at %v contracts", c.PM, c.Contracts)
+	}
+	if c.Kalshi.Price != 0.57 || !near(c.Kalshi.Cost, 57) || !near(c.Kalshi.Fee, 1.72) || !near(c.Kalshi.Collateral, 58.72) {
+		t.Errorf("kalshi leg = %+v", c.Kalshi)
+	}
+	if !near(c.Required, 98.72) || c.Payout != 100 || !near(c.NetEdge, 1.28) || !near(c.ReturnOnCapital, 1.28/98.72*100) {
+		t.Errorf("capital = %+v", c)
+	}
+	// The edge is net of the same fees
+	if o := opps[0]; !near(o.Fees, 0.0172) || !near(o.TotalCost, 0.9872) || !near(o.EdgeAbs, 0.0128) || !near(o.EdgePctTurn, c.ReturnOnCapital) {
+		t.Errorf("opportunity fees = %v, total cost = %v, edge = %v (%v%%), want the capital's", o.Fees, o.TotalCost, o.EdgeAbs, o.EdgePctTurn)
+	}
+}
+
+func TestCapitalUnknownSize(t *testing.T) {
+	c := newCapital(0, 0.40, 0.57)
+	if c.Contracts != 1 || !near(c.Required, 0.97) || !near(c.NetEdge, 0.03) {
+		t.Errorf("capital = %+v, want one contract", c)
+	}
+	var none *Capital
+	none.charge(FeeSchedule{PMRate: 0.07, KalshiRate: 0.07}) // Strategies may leave capital unset
+}
+
+func near(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
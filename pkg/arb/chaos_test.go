@@ -0,0 +1,161 @@
+package arb
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// chaosPair's Kalshi quote leaves no arbitrage against Polymarket asks of
+// 0.60; a NO ask of 0.30 or any negative ask would be one
+var (
+	chaosPair   = MarketPair{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"}
+	chaosKalshi = ws.KalshiPriceUpdate{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57}
+)
+
+func TestEngineSkipsUnusableQuotes(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		yes    ws.PMPriceUpdate
+		no     ws.PMPriceUpdate
+		kalshi ws.KalshiPriceUpdate
+		want   int
+	}{
+		{"usable", ws.PMPriceUpdate{Ask: 0.40}, ws.PMPriceUpdate{Ask: 0.62}, chaosKalshi, 1},
+		{"negative ask", ws.PMPriceUpdate{Ask: -0.5}, ws.PMPriceUpdate{Ask: 0.62}, chaosKalshi, 0},
+		{"NaN ask", ws.PMPriceUpdate{Ask: math.NaN()}, ws.PMPriceUpdate{Ask: 0.62}, chaosKalshi, 0},
+		{"crossed polymarket", ws.PMPriceUpdate{Ask: 0.40, Bid: 0.48}, ws.PMPriceUpdate{Ask: 0.62}, chaosKalshi, 0},
+		{"kalshi above 1", ws.PMPriceUpdate{Ask: 0.40}, ws.PMPriceUpdate{Ask: 0.62},
+			ws.KalshiPriceUpdate{Ticker: "K1", YesBid: 0.43, YesAsk: 1.45, NoBid: -0.45, NoAsk: 0.57}, 0},
+		{"crossed kalshi", ws.PMPriceUpdate{Ask: 0.40}, ws.PMPriceUpdate{Ask: 0.62},
+			ws.KalshiPriceUpdate{Ticker: "K1", YesBid: 0.45, YesAsk: 0.43, NoBid: 0.57, NoAsk: 0.55}, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEngine(t, []MarketPair{chaosPair})
+			now := time.Now()
+			tt.yes.TokenID, tt.yes.UpdatedAt = "Y1", now
+			tt.no.TokenID, tt.no.UpdatedAt = "N1", now
+			tt.kalshi.UpdatedAt = now
+			e.pmClient.SeedQuotes([]ws.PMPriceUpdate{tt.yes, tt.no})
+			e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{tt.kalshi})
+
+			e.computeOpportunities()
+			if got := e.Snapshot().Opportunities; len(got) != tt.want {
+				t.Errorf("got %d opportunities, want %d: %+v", len(got), tt.want, got)
+			}
+		})
+	}
+}
+
+// TestEngineUnderChaoticFeed streams a Polymarket feed with no arbitrage in
+// it through corruption, drops and reordering, interleaved with stale frames
+// that would be an arbitrage, and checks the engine finds none
+func TestEngineUnderChaoticFeed(t *testing.T) {
+	frame := func(asset string, ask float64, ms int64) []byte {
+		return []byte(fmt.Sprintf(`{"event_type":"price_change","market":"C1","asset":"%s","price":"%.2f","side":"sell","size":"100","timestamp":"%d"}`, asset, ask, ms))
+	}
+
+	// The first frames go through clean, so every stale frame is older than
+	// a quote already taken
+	frames := [][]byte{frame("Y1", 0.60, 1000), frame("N1", 0.60, 1000)}
+	injector := chaos.NewInjector(chaos.Faults{Corrupt: 0.3, Drop: 0.05, Reorder: 0.2, Seed: 7})
+	keep := func(f []byte) { frames = append(frames, f) }
+	for n := int64(2); n <= 1000; n++ {
+		ask := 0.60 + float64(n%3)/100
+		injector.Frames("pm", frame("Y1", ask, n*1000), keep)
+		injector.Frames("pm", frame("N1", ask, n*1000), keep)
+		if n%10 == 0 {
+			injector.Frames("pm", frame("N1", 0.30, 500), keep)
+		}
+	}
+	// Whatever the injector held back, a stale frame arrives last, then the
+	// sentinel showing everything before it was read
+	frames = append(frames, frame("N1", 0.30, 500), frame("S", 0.50, 0))
+
+	url := servePMFeed(t, frames)
+	e := newFeedEngine(t, url)
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: time.Now()}})
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if _, ok := e.pmClient.GetQuote(instrument.PMToken("S")); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("feed not read within 10s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	e.computeOpportunities()
+	if opps := e.Snapshot().Opportunities; len(opps) != 0 {
+		t.Fatalf("opportunities from a feed without arbitrage: %+v", opps)
+	}
+	for _, id := range []instrument.ID{chaosPair.PMYes(), chaosPair.PMNo()} {
+		if q, _ := e.pmClient.GetQuote(id); q.Ask < 0.60 || q.Ask > 0.62 {
+			t.Errorf("%s ask = %v, want one the feed sent in order", id, q.Ask)
+		}
+	}
+}
+
+// servePMFeed serves frames to the first Polymarket connection and returns
+// its URL
+func servePMFeed(t *testing.T, frames [][]byte) string {
+	t.Helper()
+	var upgrader websocket.Upgrader
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, f := range frames {
+			if err := conn.WriteMessage(websocket.TextMessage, f); err != nil {
+				return
+			}
+		}
+		for { // Until the client leaves
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// newFeedEngine returns an engine over chaosPair with a Polymarket client
+// streaming from url and an unstarted Kalshi client
+func newFeedEngine(t *testing.T, url string) *Engine {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	pm := ws.NewPolymarketClient(context.Background(), url, nil, 0, logger)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kalshi, err := ws.NewKalshiClientWithKey(context.Background(), "", "test", key, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pm.Close(); kalshi.Close() })
+	if err := pm.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return NewEngine(context.Background(), []MarketPair{chaosPair}, pm, kalshi, 1, logger)
+}
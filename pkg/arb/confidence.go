@@ -0,0 +1,27 @@
+package arb
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultConfidenceHalfLife is the quote age at which a leg's confidence halves
+const DefaultConfidenceHalfLife = 15 * time.Second
+
+// QuoteConfidence scores how much an edge can be trusted given the age of the
+// quotes on each leg. Each leg decays exponentially with its own age and the
+// score is the product of both, so one stale leg is enough to drag it down.
+// Returns a value in [0, 1]; a non-positive half-life disables decay.
+func QuoteConfidence(now, pmQuoteTime, kalshiQuoteTime time.Time, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return 1
+	}
+	return legConfidence(now.Sub(pmQuoteTime), halfLife) * legConfidence(now.Sub(kalshiQuoteTime), halfLife)
+}
+
+func legConfidence(age, halfLife time.Duration) float64 {
+	if age <= 0 {
+		return 1
+	}
+	return math.Exp2(-age.Seconds() / halfLife.Seconds())
+}
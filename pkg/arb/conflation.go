@@ -0,0 +1,171 @@
+package arb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+// DefaultComputeInterval is the engine's compute cycle when no cadence is set
+const DefaultComputeInterval = time.Second
+
+// Cadence sets how often the engine computes opportunities. Quotes arriving
+// between two cycles are conflated: only the last one is evaluated. While
+// any instrument receives more than HotUpdates updates between cycles, the
+// interval halves down to MinInterval, and an update to such a hot
+// instrument starts a cycle at once, so fast markets are evaluated at a finer
+// grain. Once every instrument calms down, the interval doubles back up to
+// Interval.
+type Cadence struct {
+	Interval    time.Duration // Slowest cycle, used while no instrument is hot
+	MinInterval time.Duration // Fastest cycle; Interval or more disables adaptation
+	HotUpdates  int           // Updates per cycle above which an instrument is hot; 0 disables adaptation
+}
+
+// adaptive reports whether the interval may shorten
+func (c Cadence) adaptive() bool {
+	return c.HotUpdates > 0 && c.MinInterval > 0 && c.MinInterval < c.Interval
+}
+
+// ConflationStatus is the engine's current cadence and its hottest
+// instruments as of the last cycle
+type ConflationStatus struct {
+	Interval   string          `json:"interval"`
+	MaxUpdates int             `json:"max_updates"` // Most updates to one instrument between the last two cycles
+	Hot        []instrument.ID `json:"hot"`         // Hot instruments, busiest first
+}
+
+// Conflation counts price updates per instrument between compute cycles.
+// Create it before the venue clients and install Observe as their update
+// hook; see Engine.SetCadence.
+type Conflation struct {
+	mu     sync.Mutex
+	counts map[instrument.ID]int
+	hot    map[instrument.ID]int // Hot instruments of the last cycle and their counts
+	kick   chan struct{}         // Signalled when a hot instrument updates
+}
+
+// NewConflation creates an empty update counter
+func NewConflation() *Conflation {
+	return &Conflation{
+		counts: make(map[instrument.ID]int),
+		hot:    make(map[instrument.ID]int),
+		kick:   make(chan struct{}, 1),
+	}
+}
+
+// Observe counts a price update, starting an early compute cycle when the
+// instrument is hot
+func (c *Conflation) Observe(id instrument.ID, at time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.counts[id]++
+	_, hot := c.hot[id]
+	c.mu.Unlock()
+	if hot {
+		select {
+		case c.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// cycle ends a counting period: it records the per-instrument counts,
+// marks instruments above hotUpdates (when positive) as hot and returns the
+// largest count
+func (c *Conflation) cycle(hotUpdates int) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	counts := c.counts
+	c.counts = make(map[instrument.ID]int, len(counts))
+	c.hot = make(map[instrument.ID]int)
+	var max int
+	for id, n := range counts {
+		if n > max {
+			max = n
+		}
+		if hotUpdates > 0 && n > hotUpdates {
+			c.hot[id] = n
+		}
+	}
+	hot := len(c.hot)
+	c.mu.Unlock()
+
+	for _, n := range counts {
+		metrics.ObserveQuoteConflation(n)
+	}
+	metrics.SetHotInstruments(hot)
+	return max
+}
+
+// hottest returns the hot instruments, busiest first
+func (c *Conflation) hottest() []instrument.ID {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]instrument.ID, 0, len(c.hot))
+	for id := range c.hot {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if c.hot[ids[i]] != c.hot[ids[j]] {
+			return c.hot[ids[i]] > c.hot[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// SetCadence configures the compute cycle and the update counter it adapts
+// to; a nil counter keeps the interval fixed. Call before Start.
+func (e *Engine) SetCadence(c Cadence, quotes *Conflation) {
+	if c.Interval <= 0 {
+		c.Interval = DefaultComputeInterval
+	}
+	e.cadence = c
+	e.conflation = quotes
+	e.interval.Store(int64(c.Interval))
+}
+
+// Conflation returns the current compute cadence and hot instruments
+func (e *Engine) Conflation() ConflationStatus {
+	return ConflationStatus{
+		Interval:   time.Duration(e.interval.Load()).String(),
+		MaxUpdates: int(e.maxUpdates.Load()),
+		Hot:        e.conflation.hottest(),
+	}
+}
+
+// nextInterval adapts the compute interval to the last cycle's conflation:
+// halved while it exceeded the hot threshold, doubled back towards the
+// configured interval once below half of it
+func (c Cadence) nextInterval(current time.Duration, maxUpdates int) time.Duration {
+	if !c.adaptive() {
+		return c.Interval
+	}
+	switch {
+	case maxUpdates > c.HotUpdates:
+		current /= 2
+	case maxUpdates*2 <= c.HotUpdates:
+		current *= 2
+	}
+	return min(max(current, c.MinInterval), c.Interval)
+}
+
+// hotUpdates is the threshold instruments are marked hot above, 0 when the
+// cadence does not adapt
+func (e *Engine) hotUpdates() int {
+	if e.conflation == nil || !e.cadence.adaptive() {
+		return 0
+	}
+	return e.cadence.HotUpdates
+}
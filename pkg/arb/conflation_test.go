@@ -0,0 +1,129 @@
+package arb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+func TestConflationCycle(t *testing.T) {
+	c := NewConflation()
+	busy, quiet := instrument.PMToken("busy"), instrument.PMToken("quiet")
+	now := time.Now()
+	for range 7 {
+		c.Observe(busy, now)
+	}
+	c.Observe(quiet, now)
+
+	if max := c.cycle(5); max != 7 {
+		t.Fatalf("expected 7 updates at most, got %d", max)
+	}
+	if hot := c.hottest(); len(hot) != 1 || hot[0] != busy {
+		t.Fatalf("expected only the busy instrument hot, got %v", hot)
+	}
+	select {
+	case <-c.kick:
+		t.Fatal("no kick expected before a hot instrument updates")
+	default:
+	}
+
+	// Updates to hot instruments start a cycle; counts restart every cycle
+	c.Observe(quiet, now)
+	c.Observe(busy, now)
+	c.Observe(busy, now)
+	select {
+	case <-c.kick:
+	default:
+		t.Fatal("expected a kick from the hot instrument")
+	}
+	if max := c.cycle(5); max != 2 {
+		t.Errorf("expected counts reset by the cycle, got %d", max)
+	}
+	if hot := c.hottest(); len(hot) != 0 {
+		t.Errorf("expected no hot instruments once calm, got %v", hot)
+	}
+
+	var none *Conflation
+	none.Observe(busy, now)
+	if none.cycle(5) != 0 || none.hottest() != nil {
+		t.Error("a nil counter must be inert")
+	}
+}
+
+func TestCadenceNextInterval(t *testing.T) {
+	c := Cadence{Interval: time.Second, MinInterval: 200 * time.Millisecond, HotUpdates: 4}
+	steps := []struct {
+		updates int
+		want    time.Duration
+	}{
+		{5, 500 * time.Millisecond},
+		{9, 250 * time.Millisecond},
+		{5, 200 * time.Millisecond}, // Floored at MinInterval
+		{3, 200 * time.Millisecond}, // Above half the threshold: held
+		{2, 400 * time.Millisecond},
+		{0, 800 * time.Millisecond},
+		{1, time.Second}, // Capped at Interval
+	}
+	interval := c.Interval
+	for i, s := range steps {
+		interval = c.nextInterval(interval, s.updates)
+		if interval != s.want {
+			t.Fatalf("step %d: %d updates: expected %s, got %s", i, s.updates, s.want, interval)
+		}
+	}
+
+	fixed := Cadence{Interval: time.Second, MinInterval: 200 * time.Millisecond}
+	if got := fixed.nextInterval(time.Second, 100); got != time.Second {
+		t.Errorf("expected no adaptation without a hot threshold, got %s", got)
+	}
+}
+
+func TestComputeLoopAdaptsToConflation(t *testing.T) {
+	e := newTestEngine(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	e.ctx = ctx
+
+	quotes := NewConflation()
+	e.SetCadence(Cadence{Interval: 80 * time.Millisecond, MinInterval: 10 * time.Millisecond, HotUpdates: 3}, quotes)
+	e.Start()
+
+	waitFor := func(what string, cond func(ConflationStatus) bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond(e.Conflation()) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s: %+v", what, e.Conflation())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// A steady stream of updates to one instrument makes it hot
+	id := instrument.PMToken("busy")
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				quotes.Observe(id, time.Now())
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+	waitFor("the fastest cycle", func(s ConflationStatus) bool {
+		return s.Interval == "10ms" && len(s.Hot) == 1 && s.Hot[0] == id
+	})
+
+	close(stop)
+	<-done
+	waitFor("the configured cycle", func(s ConflationStatus) bool {
+		return s.Interval == "80ms" && len(s.Hot) == 0
+	})
+}
@@ -0,0 +1,170 @@
+package arb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// DemoCategory is the category of demo mode's synthetic pairs
+const DemoCategory = "demo"
+
+// Demo mode defaults, see DemoOptions
+const (
+	DefaultDemoPairs    = 3
+	DefaultDemoInterval = time.Minute
+	DefaultDemoHold     = 20 * time.Second
+	DefaultDemoEdgePct  = 5.0
+
+	// demoRefresh is how often the synthetic quotes are re-stamped, keeping
+	// them fresh for the staleness filter
+	demoRefresh = time.Second
+)
+
+// DemoOptions configures demo mode; zero values fall back to the defaults
+type DemoOptions struct {
+	Pairs    int           // Synthetic pairs added to the engine
+	Interval time.Duration // Between injected mispricings
+	Hold     time.Duration // How long each mispricing lasts
+	EdgePct  float64       // ROI on turnover of each mispricing, before fees
+}
+
+// Demo runs demo mode: it adds synthetic pairs to the engine, quoted fairly,
+// and misprices one of them on a schedule, so dashboards, alert routes and
+// consumers can be exercised without waiting for real edges. The synthetic
+// markets exist on neither venue; their pairs are in DemoCategory and
+// titled as demos. Kalshi must be quoting, over its WebSocket or polling.
+type Demo struct {
+	engine *Engine
+	opts   DemoOptions
+	pairs  []MarketPair
+
+	next      int                  // Pair mispriced next
+	mispriced map[string]time.Time // Pair key -> when its mispricing ends
+	quoted    time.Time            // Stamp of the latest quotes
+}
+
+// NewDemo creates demo mode for an engine
+func NewDemo(e *Engine, opts DemoOptions) *Demo {
+	if opts.Pairs <= 0 {
+		opts.Pairs = DefaultDemoPairs
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultDemoInterval
+	}
+	if opts.Hold <= 0 {
+		opts.Hold = DefaultDemoHold
+	}
+	if opts.EdgePct <= 0 {
+		opts.EdgePct = DefaultDemoEdgePct
+	}
+
+	pairs := make([]MarketPair, opts.Pairs)
+	for i := range pairs {
+		n := i + 1
+		pairs[i] = MarketPair{
+			PMConditionID: fmt.Sprintf("demo-%d", n),
+			PMTokenYes:    fmt.Sprintf("demo-%d-yes", n),
+			PMTokenNo:     fmt.Sprintf("demo-%d-no", n),
+			PMTitle:       fmt.Sprintf("[DEMO] Synthetic market %d", n),
+			KalshiTicker:  fmt.Sprintf("DEMO-SYNTH-%d", n),
+			KalshiTitle:   fmt.Sprintf("[DEMO] Synthetic market %d", n),
+			Category:      DemoCategory,
+			MatchScore:    1,
+		}
+	}
+	return &Demo{engine: e, opts: opts, pairs: pairs, mispriced: make(map[string]time.Time)}
+}
+
+// Pairs returns the synthetic pairs
+func (d *Demo) Pairs() []MarketPair {
+	return d.pairs
+}
+
+// Run adds the synthetic pairs to the engine and quotes them until ctx is
+// done, mispricing the next pair in turn every interval, the first at once
+func (d *Demo) Run(ctx context.Context) {
+	d.engine.AddPairs(d.pairs)
+	d.inject(time.Now())
+	d.quote(time.Now())
+
+	injections := time.NewTicker(d.opts.Interval)
+	defer injections.Stop()
+	refresh := time.NewTicker(demoRefresh)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-injections.C:
+			d.inject(now)
+			d.quote(now)
+		case now := <-refresh.C:
+			d.quote(now)
+		}
+	}
+}
+
+// inject misprices the next pair in turn for the hold period
+func (d *Demo) inject(now time.Time) {
+	pair := d.pairs[d.next%len(d.pairs)]
+	d.next++
+	d.mispriced[pair.Key()] = now.Add(d.opts.Hold)
+	d.engine.logger.Info("demo mispricing injected",
+		"kalshi_ticker", pair.KalshiTicker, "edge_pct", d.opts.EdgePct, "hold", d.opts.Hold)
+}
+
+// quote seeds every synthetic pair's quotes as of now, mispriced while its
+// hold lasts and fair otherwise
+func (d *Demo) quote(now time.Time) {
+	// Cached quotes are only replaced by newer ones
+	if !now.After(d.quoted) {
+		now = d.quoted.Add(time.Nanosecond)
+	}
+	d.quoted = now
+
+	var pm []ws.PMPriceUpdate
+	var kalshi []ws.KalshiPriceUpdate
+	for _, pair := range d.pairs {
+		until, ok := d.mispriced[pair.Key()]
+		if ok && !now.Before(until) {
+			delete(d.mispriced, pair.Key())
+			ok = false
+		}
+		yes, no, k := demoQuotes(pair, ok, d.opts.EdgePct, now)
+		pm = append(pm, yes, no)
+		kalshi = append(kalshi, k)
+	}
+	d.engine.pmClient.SeedQuotes(pm)
+	d.engine.kalshiClient.SeedQuotes(kalshi)
+}
+
+// demoQuotes prices a synthetic pair. Fair quotes cost more than $1 both
+// ways; a mispricing buys PM YES and Kalshi NO for less, at an edge of
+// about edgePct on turnover, in whole cents and at most about 110%.
+func demoQuotes(pair MarketPair, mispriced bool, edgePct float64, now time.Time) (yes, no ws.PMPriceUpdate, kalshi ws.KalshiPriceUpdate) {
+	const pmYesAsk = 0.45
+	kalshiYesBid := 0.49
+	if mispriced {
+		cost := 1 / (1 + edgePct/100)
+		kalshiYesBid = min(math.Round((1-(cost-pmYesAsk))*100)/100, 0.97)
+	}
+	kalshiYesAsk := kalshiYesBid + 0.02
+
+	yes = ws.PMPriceUpdate{TokenID: pair.PMTokenYes, Outcome: "YES", Bid: 0.44, Ask: pmYesAsk, BidSize: 500, AskSize: 500, UpdatedAt: now}
+	if !mispriced {
+		yes.Bid, yes.Ask = 0.50, 0.52
+	}
+	no = ws.PMPriceUpdate{TokenID: pair.PMTokenNo, Outcome: "NO", Bid: 0.49, Ask: 0.51, BidSize: 500, AskSize: 500, UpdatedAt: now}
+	kalshi = ws.KalshiPriceUpdate{
+		Ticker: pair.KalshiTicker,
+		YesBid: kalshiYesBid, YesAsk: kalshiYesAsk,
+		NoBid: 1 - kalshiYesAsk, NoAsk: 1 - kalshiYesBid,
+		UpdatedAt: now,
+	}
+	return yes, no, kalshi
+}
@@ -0,0 +1,43 @@
+package arb
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDemoInjectsMispricings(t *testing.T) {
+	e := newTestEngine(t, nil)
+	d := NewDemo(e, DemoOptions{Pairs: 2, Hold: time.Minute})
+	e.AddPairs(d.Pairs())
+
+	// Fairly quoted, nothing to find
+	now := time.Now()
+	d.quote(now)
+	e.computeOpportunities()
+	if got := e.Snapshot().Opportunities; len(got) != 0 {
+		t.Fatalf("fair demo quotes produced %+v", got)
+	}
+
+	d.inject(now)
+	d.quote(now)
+	e.computeOpportunities()
+	got := e.Snapshot().Opportunities
+	if len(got) != 1 || got[0].KalshiTicker != "DEMO-SYNTH-1" || got[0].Category != DemoCategory {
+		t.Fatalf("opportunities after injection = %+v, want one on DEMO-SYNTH-1", got)
+	}
+	if math.Abs(got[0].EdgePctTurn-DefaultDemoEdgePct) > 0.5 {
+		t.Errorf("edge = %.2f%%, want about %.0f%%", got[0].EdgePctTurn, DefaultDemoEdgePct)
+	}
+
+	// The next injection moves on to the second pair; the first reverts
+	// once its hold is over
+	later := now.Add(time.Minute)
+	d.inject(later)
+	d.quote(later)
+	e.computeOpportunities()
+	got = e.Snapshot().Opportunities
+	if len(got) != 1 || got[0].KalshiTicker != "DEMO-SYNTH-2" {
+		t.Fatalf("opportunities after the second injection = %+v, want one on DEMO-SYNTH-2", got)
+	}
+}
@@ -0,0 +1,92 @@
+package arb
+
+// diffRevisions is how many past revisions OpportunitiesSince can diff
+// against
+const diffRevisions = 128
+
+// OpportunityDiff is how the opportunities changed between two revisions
+type OpportunityDiff struct {
+	Revision      uint64 `json:"revision"`
+	SinceRevision uint64 `json:"since_revision"`
+	// Reset is set when SinceRevision is too old to diff against, or was
+	// never published: Added then holds every current opportunity, and the
+	// client must replace its copy rather than apply the diff
+	Reset   bool          `json:"reset"`
+	Added   []Opportunity `json:"added"`   // In the current order, best edge first
+	Updated []Opportunity `json:"updated"` // Changed in more than their computation time
+	Removed []string      `json:"removed"` // IDs
+}
+
+// OpportunitiesSince returns the changes to the opportunities since
+// revision since, as reported by Snapshot, so pollers can sync without
+// downloading the whole list
+func (e *Engine) OpportunitiesSince(since uint64) OpportunityDiff {
+	current := e.Snapshot()
+	diff := OpportunityDiff{
+		Revision:      current.Revision,
+		SinceRevision: since,
+		Added:         make([]Opportunity, 0),
+		Updated:       make([]Opportunity, 0),
+		Removed:       make([]string, 0),
+	}
+
+	base := e.revisionSnapshot(since)
+	if base == nil {
+		diff.Reset = true
+		diff.Added = append(diff.Added, current.Opportunities...)
+		return diff
+	}
+
+	old := make(map[string]Opportunity, len(base.Opportunities))
+	for _, o := range base.Opportunities {
+		old[o.ID] = o
+	}
+	for _, o := range current.Opportunities {
+		prev, ok := old[o.ID]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, o)
+		case !sameOpportunity(prev, o):
+			diff.Updated = append(diff.Updated, o)
+		}
+		delete(old, o.ID)
+	}
+	for _, o := range base.Opportunities {
+		if _, removed := old[o.ID]; removed {
+			diff.Removed = append(diff.Removed, o.ID)
+		}
+	}
+	return diff
+}
+
+// revisionSnapshot returns the snapshot published at a revision, nil when
+// it is no longer retained
+func (e *Engine) revisionSnapshot(revision uint64) *Snapshot {
+	if s := e.Snapshot(); s.Revision == revision {
+		return s
+	}
+	e.historyMu.Lock()
+	defer e.historyMu.Unlock()
+	for _, s := range e.history {
+		if s.Revision == revision {
+			return s
+		}
+	}
+	return nil
+}
+
+// remember retains a published snapshot for OpportunitiesSince, once per
+// revision
+func (e *Engine) remember(s *Snapshot) {
+	e.historyMu.Lock()
+	defer e.historyMu.Unlock()
+	if n := len(e.history); n > 0 && e.history[n-1].Revision == s.Revision {
+		e.history[n-1] = s
+		return
+	}
+	if len(e.history) == diffRevisions {
+		copy(e.history, e.history[1:])
+		e.history = e.history[:diffRevisions-1]
+	}
+	e.history = append(e.history, s)
+}
@@ -0,0 +1,81 @@
+package arb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestOpportunitiesSince(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTokenNo: "N2", KalshiTicker: "K2"},
+	}
+	e := newTestEngine(t, pairs)
+	id1, id2 := OpportunityID(pairs[0], ComboPMYesKalshiNo), OpportunityID(pairs[1], ComboPMYesKalshiNo)
+	quote := func(n string, pmYesAsk, kalshiYesBid float64) {
+		now := time.Now()
+		e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+			{TokenID: "Y" + n, Bid: pmYesAsk - 0.02, Ask: pmYesAsk, UpdatedAt: now},
+			{TokenID: "N" + n, Bid: 0.60, Ask: 0.62, UpdatedAt: now},
+		})
+		e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{
+			Ticker: "K" + n, YesBid: kalshiYesBid, YesAsk: kalshiYesBid + 0.02,
+			NoBid: 0.98 - kalshiYesBid, NoAsk: 1 - kalshiYesBid, UpdatedAt: now,
+		}})
+	}
+
+	quote("1", 0.40, 0.43)
+	e.computeOpportunities()
+	first := e.Snapshot().Revision
+
+	// Pair 1's edge widens and pair 2 opens
+	quote("1", 0.38, 0.43)
+	quote("2", 0.40, 0.45)
+	e.computeOpportunities()
+	second := e.Snapshot().Revision
+
+	d := e.OpportunitiesSince(first)
+	if d.Reset || d.Revision != second || d.SinceRevision != first {
+		t.Fatalf("unexpected diff header %+v", d)
+	}
+	if len(d.Added) != 1 || d.Added[0].ID != id2 || len(d.Updated) != 1 || d.Updated[0].ID != id1 || len(d.Removed) != 0 {
+		t.Errorf("expected pair 2 added and pair 1 updated, got %+v", d)
+	}
+
+	// Pair 1 closes
+	quote("1", 0.60, 0.43)
+	e.computeOpportunities()
+	if d := e.OpportunitiesSince(second); len(d.Added) != 0 || len(d.Removed) != 1 || d.Removed[0] != id1 {
+		t.Errorf("expected pair 1 removed, got %+v", d)
+	}
+	if d := e.OpportunitiesSince(first); len(d.Added) != 1 || len(d.Removed) != 1 {
+		t.Errorf("expected diffs to span several revisions, got %+v", d)
+	}
+
+	current := e.Snapshot().Revision
+	if d := e.OpportunitiesSince(current); d.Reset || len(d.Added)+len(d.Updated)+len(d.Removed) != 0 {
+		t.Errorf("expected an empty diff since the current revision, got %+v", d)
+	}
+
+	if d := e.OpportunitiesSince(current + 100); !d.Reset || len(d.Added) != 1 || d.Added[0].ID != id2 {
+		t.Errorf("expected a reset with every opportunity for an unknown revision, got %+v", d)
+	}
+}
+
+func TestOpportunitiesSinceForgetsOldRevisions(t *testing.T) {
+	e := newTestEngine(t, nil)
+	for i := 0; i < diffRevisions+5; i++ {
+		e.mu.Lock()
+		e.revision++
+		e.publish()
+		e.mu.Unlock()
+	}
+	if d := e.OpportunitiesSince(1); !d.Reset {
+		t.Error("expected a reset for a revision past retention")
+	}
+	if d := e.OpportunitiesSince(10); d.Reset {
+		t.Error("expected a diff for a retained revision")
+	}
+}
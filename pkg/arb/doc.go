@@ -0,0 +1,10 @@
+// Package arb detects cross-venue arbitrage between Polymarket and Kalshi.
+// An Engine monitors MarketPairs (typically built from match.MarketMatch),
+// reads both venues' quotes from the ws clients, runs its Strategies every
+// cycle and publishes the Opportunities found through Snapshot and OnUpdate
+// hooks. Optional registries (halts, text edits, news moves, annotations,
+// acknowledgments) are installed with the engine's Set methods before Start.
+//
+// The Opportunity JSON payload is versioned, see OpportunitySchemaVersion.
+// The engine records Prometheus metrics on the default registry.
+package arb
@@ -0,0 +1,71 @@
+package arb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// EdgeJump is a sudden rise in the best published edge from one compute
+// cycle to the next. Real edges rarely appear that fast; a jump usually
+// means a parsing bug, a stale feed or a mismatched pair.
+type EdgeJump struct {
+	FromPct     float64     `json:"from_pct"` // Best edge_pct_turn of the previous cycle, 0 when there was none
+	ToPct       float64     `json:"to_pct"`
+	RisePP      float64     `json:"rise_pp"`     // Percentage points
+	PreviousID  string      `json:"previous_id"` // Previous best opportunity, empty when there was none
+	Opportunity Opportunity `json:"opportunity"` // New best opportunity
+	DetectedAt  time.Time   `json:"detected_at"`
+}
+
+// EdgeJumpDetector watches the best edge across compute cycles for rises
+// beyond a threshold. Falls are not jumps: opportunities get taken.
+type EdgeJumpDetector struct {
+	threshold float64 // Percentage points
+
+	mu       sync.Mutex
+	primed   bool // Set once a cycle has been observed
+	prevPct  float64
+	prevID   string
+	handlers []func(EdgeJump)
+}
+
+// NewEdgeJumpDetector creates a detector flagging rises of more than
+// thresholdPP percentage points of edge_pct_turn within one cycle
+func NewEdgeJumpDetector(thresholdPP float64) *EdgeJumpDetector {
+	return &EdgeJumpDetector{threshold: thresholdPP}
+}
+
+// OnJump registers a handler called for each jump. Handlers run on the
+// engine's compute goroutine and must not block. Call before the detector
+// observes updates.
+func (d *EdgeJumpDetector) OnJump(fn func(EdgeJump)) {
+	d.handlers = append(d.handlers, fn)
+}
+
+// Observe compares a cycle's best edge with the previous cycle's; register
+// it with Engine.OnUpdate. opps must be sorted best first, as published.
+func (d *EdgeJumpDetector) Observe(opps []Opportunity) {
+	var bestPct float64
+	var bestID string
+	if len(opps) > 0 {
+		bestPct, bestID = opps[0].EdgePctTurn, opps[0].ID
+	}
+
+	d.mu.Lock()
+	jump := EdgeJump{FromPct: d.prevPct, ToPct: bestPct, RisePP: bestPct - d.prevPct, PreviousID: d.prevID}
+	jumped := d.primed && jump.RisePP > d.threshold
+	d.primed, d.prevPct, d.prevID = true, bestPct, bestID
+	d.mu.Unlock()
+
+	if !jumped {
+		return
+	}
+	jump.Opportunity = opps[0]
+	jump.DetectedAt = time.Now()
+	metrics.RecordBestEdgeJump()
+	for _, fn := range d.handlers {
+		fn(jump)
+	}
+}
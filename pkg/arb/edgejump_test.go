@@ -0,0 +1,27 @@
+package arb
+
+import "testing"
+
+func TestEdgeJumpDetector(t *testing.T) {
+	d := NewEdgeJumpDetector(10)
+	var jumps []EdgeJump
+	d.OnJump(func(j EdgeJump) { jumps = append(jumps, j) })
+
+	// The first cycle only primes the detector
+	d.Observe([]Opportunity{{ID: "a", EdgePctTurn: 25}})
+	d.Observe([]Opportunity{{ID: "a", EdgePctTurn: 30}, {ID: "b", EdgePctTurn: 2}})
+	d.Observe(nil) // Falls are not jumps
+	if len(jumps) != 0 {
+		t.Fatalf("unexpected jumps %+v", jumps)
+	}
+
+	d.Observe([]Opportunity{{ID: "c", EdgePctTurn: 9}})
+	d.Observe([]Opportunity{{ID: "d", EdgePctTurn: 21.5}, {ID: "c", EdgePctTurn: 9}})
+	if len(jumps) != 1 {
+		t.Fatalf("expected one jump, got %+v", jumps)
+	}
+	j := jumps[0]
+	if j.FromPct != 9 || j.ToPct != 21.5 || j.RisePP != 12.5 || j.PreviousID != "c" || j.Opportunity.ID != "d" || j.DetectedAt.IsZero() {
+		t.Errorf("unexpected jump %+v", j)
+	}
+}
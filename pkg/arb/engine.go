@@ -0,0 +1,674 @@
+package arb
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// MarketPair represents a matched market pair between Polymarket and Kalshi
+type MarketPair struct {
+	PMConditionID string  `json:"pm_condition_id"`
+	PMTokenYes    string  `json:"pm_token_yes"`
+	PMTokenNo     string  `json:"pm_token_no"`
+	PMTitle       string  `json:"pm_title"`
+	KalshiTicker  string  `json:"kalshi_ticker"`
+	KalshiTitle   string  `json:"kalshi_title"`
+	Category      string  `json:"category"`
+	MatchScore    float64 `json:"match_score"` // Title similarity between the two markets
+	Liquidity     float64 `json:"liquidity"`   // Market liquidity in USD, 0 when unknown
+
+	// Inverted marks a Polymarket question that negates the Kalshi one, so
+	// PM YES pays out when Kalshi NO does and the hedges pair like sides
+	Inverted bool `json:"inverted,omitempty"`
+
+	// PMTickSize is the Polymarket market's price increment; 0 means
+	// price.DefaultPolymarketTick, see PMTick
+	PMTickSize float64 `json:"pm_tick_size,omitempty"`
+
+	// PMMinOrderSize is the smallest Polymarket order in shares, 0 when
+	// unknown; PMNegRisk marks a market traded through the neg-risk
+	// exchange. See PMRules.
+	PMMinOrderSize float64 `json:"pm_min_order_size,omitempty"`
+	PMNegRisk      bool    `json:"pm_neg_risk,omitempty"`
+
+	// ResolutionSourceDiffers warns that the markets' rules cite different
+	// resolution sources, so they may settle apart, see
+	// match.ResolutionSourcesDiffer
+	ResolutionSourceDiffers bool `json:"resolution_source_differs,omitempty"`
+
+	// Meta holds venue details passed through to opportunities, see Metadata
+	Meta MarketMetadata `json:"meta"`
+}
+
+// Key returns a stable identifier for the pair
+func (p MarketPair) Key() string {
+	return p.PMTokenYes + "|" + p.KalshiTicker
+}
+
+// PMYes returns the canonical instrument ID of the Polymarket YES token
+func (p MarketPair) PMYes() instrument.ID { return instrument.PMToken(p.PMTokenYes) }
+
+// PMNo returns the canonical instrument ID of the Polymarket NO token
+func (p MarketPair) PMNo() instrument.ID { return instrument.PMToken(p.PMTokenNo) }
+
+// KalshiYes returns the canonical instrument ID of the Kalshi YES side
+func (p MarketPair) KalshiYes() instrument.ID { return instrument.KalshiYes(p.KalshiTicker) }
+
+// KalshiNo returns the canonical instrument ID of the Kalshi NO side
+func (p MarketPair) KalshiNo() instrument.ID { return instrument.KalshiNo(p.KalshiTicker) }
+
+// PMTick returns the Polymarket market's price increment
+func (p MarketPair) PMTick() float64 {
+	if p.PMTickSize > 0 {
+		return p.PMTickSize
+	}
+	return price.DefaultPolymarketTick
+}
+
+// Opportunity represents an arbitrage opportunity
+type Opportunity struct {
+	ID            string    `json:"id"`             // Stable per pair and combo
+	SchemaVersion int       `json:"schema_version"` // Payload version, see OpportunitySchemaVersion
+	Timestamp     time.Time `json:"timestamp"`
+	FirstSeen     time.Time `json:"first_seen"` // When this edge was first detected in the current streak
+	Category      string    `json:"category"`
+	Combo         string    `json:"combo"`         // One of the Combo constants
+	Strategy      string    `json:"strategy"`      // Engine strategy that found it, e.g. "arbitrage"
+	EdgeAbs       float64   `json:"edge_abs"`      // Absolute edge: 1 - total_cost
+	EdgePctTurn   float64   `json:"edge_pct_turn"` // ROI on turnover: edge_abs / total_cost * 100
+	PMTitle       string    `json:"pm_title"`
+	PMYesAsk      float64   `json:"pm_yes_ask"`
+	PMNoAsk       float64   `json:"pm_no_ask"`
+	KalshiTicker  string    `json:"kalshi_ticker"`
+	KalshiTitle   string    `json:"kalshi_title"`
+	KalshiYesBid  float64   `json:"kalshi_yes_bid"`
+	KalshiYesAsk  float64   `json:"kalshi_yes_ask"`
+	KalshiNoBid   float64   `json:"kalshi_no_bid"`
+	KalshiNoAsk   float64   `json:"kalshi_no_ask"`
+	TotalCost     float64   `json:"total_cost"` // Per contract, fees included
+	Fees          float64   `json:"fees"`       // Per contract at MaxSize, see FeeSchedule
+
+	// Canonical IDs of the two instruments bought by the combo
+	PMInstrument     instrument.ID `json:"pm_instrument"`
+	KalshiInstrument instrument.ID `json:"kalshi_instrument"`
+
+	MaxSize         float64   `json:"max_size"`  // Contracts available at quoted prices, 0 when unknown
+	Liquidity       float64   `json:"liquidity"` // Pair liquidity in USD, 0 when unknown
+	MatchScore      float64   `json:"match_score"`
+	PMQuoteTime     time.Time `json:"pm_quote_time"`
+	KalshiQuoteTime time.Time `json:"kalshi_quote_time"`
+	Confidence      float64   `json:"confidence"` // 0-1, decays with the age of either leg's quote
+
+	// Vig-free probability of YES on each venue, see price.ImpliedProbability
+	PMImpliedYes      float64 `json:"pm_implied_yes"`
+	KalshiImpliedYes  float64 `json:"kalshi_implied_yes"`
+	ImpliedDivergence float64 `json:"implied_divergence"` // pm_implied_yes - kalshi_implied_yes
+
+	// Ack is a downstream consumer's acknowledgment of this streak, nil when none
+	Ack *Acknowledgment `json:"ack"`
+
+	// Market describes both venue markets of the pair
+	Market MarketMetadata `json:"market"`
+
+	// Annotation is an operator's note on the pairing, nil when none
+	Annotation *Annotation `json:"annotation"`
+
+	// ResolutionSourceDiffers warns that the pair may settle apart, see
+	// MarketPair
+	ResolutionSourceDiffers bool `json:"resolution_source_differs"`
+
+	// ExpiresAt ends the opportunity's execution window, nil when unlimited;
+	// once Expired it stays listed but is no longer executable, see
+	// Engine.SetExecutionWindow
+	ExpiresAt *time.Time `json:"expires_at"`
+	Expired   bool       `json:"expired"`
+
+	// Capital breaks down the cash each leg ties up at MaxSize, fees
+	// included; nil from strategies that do not size their legs
+	Capital *Capital `json:"capital"`
+
+	// AnnualizedReturn is the return on capital per year until both markets
+	// resolve, in percent (simple, not compounded); nil when neither venue
+	// reports a resolution time. See MinAnnualizationHorizon.
+	AnnualizedReturn *float64 `json:"annualized_return"`
+}
+
+// Engine monitors market pairs and detects arbitrage opportunities
+type Engine struct {
+	mu              sync.RWMutex
+	ctx             context.Context
+	pairs           []MarketPair
+	pmClient        *ws.PolymarketClient
+	kalshiClient    *ws.KalshiClient
+	edgeThreshold   *Threshold // Minimum edge percentage for ROI on turnover
+	strategies      []Strategy
+	opportunities   []Opportunity
+	revision        uint64 // Bumped whenever opportunities change; guarded by mu
+	maxOpps         int
+	truncated       int                      // Opportunities beyond maxOpps in the last cycle; guarded by mu
+	computedAt      time.Time                // When opportunities were last computed; guarded by mu
+	stats           PairStats                // Pair counts of the last cycle; guarded by mu
+	snapshot        atomic.Pointer[Snapshot] // Published state for lock-free readers
+	historyMu       sync.Mutex
+	history         []*Snapshot           // Recently published revisions, oldest first; see OpportunitiesSince
+	retentionAge    time.Duration         // How long ended streaks' histories are kept
+	ended           map[string]endedTrack // opportunity ID -> trajectory of its last, ended streak
+	tracks          map[string]*edgeTrack // opportunity ID -> edge trajectory of the current streak
+	filters         FilterChain
+	halfLife        time.Duration // Quote-age half-life for confidence scoring
+	window          time.Duration // How long after detection opportunities stay executable; 0 is unlimited
+	unhedgedMinEdge float64       // Edge raising an unhedged signal; 0 disables them
+	fees            FeeSchedule   // Netted out of edges, charged by EvaluatePositions and Capital
+	feedWeight      func(venue string) float64
+	kalshiPaused    func() bool   // Reports a Kalshi maintenance window in progress
+	kalshiOutage    func() bool   // Reports the Kalshi feed down; streaks are held meanwhile
+	halts           *HaltRegistry // Venue-halted markets; their pairs are skipped
+	hours           *TradingHours // Kalshi series outside trading hours; their pairs are skipped
+	pmRules         *PMRulesCache // Fresh Polymarket order constraints, overriding the pairs'
+	texts           *TextWatch    // Market text edits; pairs failing re-validation are skipped
+	news            *NewsWindow   // Recent sharp price moves; held pairs are skipped
+	acks            *ackRegistry  // Consumer acknowledgments of open opportunities
+	annotations     *AnnotationRegistry
+	updateHooks     []func([]Opportunity)
+	lastCompute     atomic.Int64 // Unix nanos at the end of the last completed compute cycle
+	cadence         Cadence
+	conflation      *Conflation  // Price updates per instrument since the last cycle
+	interval        atomic.Int64 // Current compute interval in nanoseconds
+	maxUpdates      atomic.Int64 // Most updates to one instrument in the last cycle
+	logger          *slog.Logger
+}
+
+// NewEngine creates a new arbitrage engine
+func NewEngine(ctx context.Context, pairs []MarketPair, pmClient *ws.PolymarketClient, kalshiClient *ws.KalshiClient, edgeThreshold float64, logger *slog.Logger) *Engine {
+	threshold := NewThreshold(edgeThreshold)
+	e := &Engine{
+		ctx:           ctx,
+		pairs:         pairs,
+		pmClient:      pmClient,
+		kalshiClient:  kalshiClient,
+		edgeThreshold: threshold,
+		strategies:    []Strategy{arbitrageStrategy{threshold: threshold}},
+		opportunities: make([]Opportunity, 0),
+		maxOpps:       DefaultMaxOpportunities,
+		retentionAge:  DefaultRetentionAge,
+		tracks:        make(map[string]*edgeTrack),
+		acks:          newAckRegistry(),
+		halfLife:      DefaultConfidenceHalfLife,
+		logger:        logger,
+	}
+	e.SetCadence(Cadence{Interval: DefaultComputeInterval}, nil)
+	e.publish()
+	return e
+}
+
+// OnUpdate registers a callback invoked after every compute cycle with the new
+// opportunity list. Callbacks run on the compute goroutine, must not block and
+// must not modify the slice. Register hooks before calling Start.
+func (e *Engine) OnUpdate(fn func([]Opportunity)) {
+	e.updateHooks = append(e.updateHooks, fn)
+}
+
+// SetStrategies replaces the default strict arbitrage strategy with the
+// given strategies, run concurrently every cycle. Call before Start.
+func (e *Engine) SetStrategies(strategies []Strategy) {
+	e.strategies = strategies
+}
+
+// SetFilters installs the filter chain applied before publication.
+// Call before Start.
+func (e *Engine) SetFilters(filters FilterChain) {
+	e.filters = filters
+}
+
+// SetConfidenceHalfLife sets the quote age at which a leg's confidence halves.
+// Call before Start.
+func (e *Engine) SetConfidenceHalfLife(halfLife time.Duration) {
+	e.halfLife = halfLife
+}
+
+// SetFeedWeights installs the data-quality weight of each venue's feed, by
+// instrument venue: opportunities' confidence is scaled by the lower of
+// their legs' weights, and dropped when it is 0. Call before Start.
+func (e *Engine) SetFeedWeights(fn func(venue string) float64) {
+	e.feedWeight = fn
+}
+
+// SetKalshiPauseCheck installs a predicate that pauses all Kalshi-dependent
+// computation while it returns true (e.g. during scheduled maintenance).
+// Call before Start.
+func (e *Engine) SetKalshiPauseCheck(fn func() bool) {
+	e.kalshiPaused = fn
+}
+
+// SetHalts installs the registry of venue-halted markets. Pairs with a halted
+// market on either venue are excluded from opportunities. Call before Start.
+func (e *Engine) SetHalts(halts *HaltRegistry) {
+	e.halts = halts
+}
+
+// SetTextWatch installs the watch over market text edits. Pairs suspended by
+// an edit are excluded from opportunities until it is accepted. Call before
+// Start.
+func (e *Engine) SetTextWatch(w *TextWatch) {
+	e.texts = w
+}
+
+// AddPairs starts monitoring additional pairs, e.g. as background matching
+// confirms them. Safe to call while the engine is running.
+func (e *Engine) AddPairs(pairs []MarketPair) {
+	e.mu.Lock()
+	e.pairs = append(e.pairs, pairs...)
+	n := len(e.pairs)
+	e.publish()
+	e.mu.Unlock()
+
+	metrics.SetArbPairs(n)
+}
+
+// Pairs returns the monitored pairs; callers must not modify the slice
+func (e *Engine) Pairs() []MarketPair {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.pairs
+}
+
+// OpportunityID derives the stable opportunity identifier for a pair and combo
+func OpportunityID(pair MarketPair, combo string) string {
+	sum := sha1.Sum([]byte(pair.Key() + "|" + combo))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Start begins monitoring for arbitrage opportunities
+func (e *Engine) Start() {
+	pairs := e.Pairs()
+	e.logger.Info("arbitrage engine starting", "pairs", len(pairs), "threshold", e.edgeThreshold.Pct())
+	metrics.SetArbPairs(len(pairs))
+
+	// Start continuous computation in a goroutine
+	go e.computeLoop()
+}
+
+// computeLoop continuously computes arbitrage opportunities at the
+// engine's cadence, early when a hot instrument updates
+func (e *Engine) computeLoop() {
+	interval := e.cadence.Interval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	last := time.Now()
+	metrics.SetComputeInterval(interval)
+	var kick <-chan struct{} // nil, never ready, without an update counter
+	if e.conflation != nil {
+		kick = e.conflation.kick
+	}
+
+	for {
+		select {
+		case <-e.ctx.Done():
+			e.logger.Info("arbitrage engine stopping")
+			return
+		case <-kick:
+			// Hot instruments are evaluated on update, but never more often
+			// than the fastest cycle allows
+			if wait := e.cadence.MinInterval - time.Since(last); wait > 0 {
+				timer.Reset(wait)
+				continue
+			}
+		case <-timer.C:
+		}
+
+		maxUpdates := e.conflation.cycle(e.hotUpdates())
+		e.computeOpportunities()
+		last = time.Now()
+
+		e.maxUpdates.Store(int64(maxUpdates))
+		if next := e.cadence.nextInterval(interval, maxUpdates); next != interval {
+			e.logger.Debug("compute interval adapted", "from", interval, "to", next, "max_updates", maxUpdates)
+			interval = next
+			e.interval.Store(int64(interval))
+			metrics.SetComputeInterval(interval)
+		}
+		timer.Reset(interval)
+	}
+}
+
+// weighFeeds scales opportunities' confidence by their feeds' data quality,
+// dropping those priced by an excluded feed
+func (e *Engine) weighFeeds(opps []Opportunity) []Opportunity {
+	if e.feedWeight == nil {
+		return opps
+	}
+	kept := opps[:0]
+	for _, o := range opps {
+		w := min(e.feedWeight(o.PMInstrument.Venue()), e.feedWeight(o.KalshiInstrument.Venue()))
+		if w <= 0 {
+			metrics.RecordOpportunityFiltered("feed_quality")
+			continue
+		}
+		o.Confidence *= w
+		kept = append(kept, o)
+	}
+	return kept
+}
+
+// computeOpportunities scans all pairs and identifies arbitrage opportunities
+func (e *Engine) computeOpportunities() {
+	states := e.pairStates()
+	screened := e.news.Screen(states, time.Now())
+	newOpps := e.evaluateStrategies(screened)
+
+	// Score quote freshness, then apply deployment policy filters before publication
+	scoredAt := time.Now()
+	for i := range newOpps {
+		newOpps[i].Confidence = QuoteConfidence(scoredAt, newOpps[i].PMQuoteTime, newOpps[i].KalshiQuoteTime, e.halfLife)
+		newOpps[i].Capital.charge(e.fees)
+		newOpps[i].AnnualizedReturn = annualize(&newOpps[i], scoredAt)
+	}
+	newOpps = e.weighFeeds(newOpps)
+	newOpps = e.filters.Apply(newOpps, scoredAt, metrics.RecordOpportunityFiltered)
+
+	// Sort by edge percentage descending
+	sort.Slice(newOpps, func(i, j int) bool {
+		return newOpps[i].EdgePctTurn > newOpps[j].EdgePctTurn
+	})
+
+	e.mu.Lock()
+
+	// Extend each edge's trajectory; IDs absent this cycle start a new streak next time
+	now := time.Now()
+	tracks := make(map[string]*edgeTrack, len(newOpps))
+	for i := range newOpps {
+		track, ok := e.tracks[newOpps[i].ID]
+		if !ok {
+			track = &edgeTrack{firstSeen: now}
+		}
+		track.add(EdgeSample{Timestamp: now, EdgeAbs: newOpps[i].EdgeAbs, EdgePctTurn: newOpps[i].EdgePctTurn})
+		tracks[newOpps[i].ID] = track
+		newOpps[i].FirstSeen = track.firstSeen
+		if a, ok := e.acks.get(newOpps[i].ID, track.firstSeen); ok {
+			newOpps[i].Ack = &a
+		}
+		if a, ok := e.annotations.Get(newOpps[i].Market.PMConditionID, newOpps[i].KalshiTicker); ok {
+			newOpps[i].Annotation = &a
+		}
+		e.stampExpiry(&newOpps[i], track, now)
+	}
+	if e.kalshiOutage != nil && e.kalshiOutage() {
+		// Edges dropping out as Kalshi quotes go stale may well still be
+		// there; hold their streaks for the reconnect and backfill
+		for id, track := range e.tracks {
+			if _, ok := tracks[id]; !ok {
+				tracks[id] = track
+			}
+		}
+	}
+	e.retire(tracks, now)
+	e.tracks = tracks
+	e.acks.retain(tracks)
+
+	// Update opportunities with limit
+	e.truncated = 0
+	if len(newOpps) > e.maxOpps {
+		e.truncated = len(newOpps) - e.maxOpps
+		newOpps = newOpps[:e.maxOpps]
+	}
+	truncated := e.truncated
+	if !sameOpportunities(e.opportunities, newOpps) {
+		e.revision++
+	}
+	e.opportunities = newOpps
+	e.computedAt = now
+	e.stats = PairStats{Quoted: len(states), Evaluated: len(screened), BestEdgePct: bestArbitrageEdge(screened)}
+	e.publish()
+	e.mu.Unlock()
+
+	// Update metrics
+	metrics.UpdateCurrentOpportunities(len(newOpps))
+	metrics.SetTruncatedOpportunities(truncated)
+	if len(newOpps) > 0 {
+		metrics.UpdateBestEdge(newOpps[0].EdgePctTurn)
+	} else {
+		metrics.UpdateBestEdge(0)
+	}
+
+	for _, hook := range e.updateHooks {
+		hook(newOpps)
+	}
+
+	e.lastCompute.Store(time.Now().UnixNano())
+}
+
+// pairStates collects the current quotes of every pair quoted on both venues
+func (e *Engine) pairStates() []PairState {
+	// Kalshi prices are only used if quoted and not in maintenance
+	if !e.kalshiClient.IsQuoting() || (e.kalshiPaused != nil && e.kalshiPaused()) {
+		return nil
+	}
+
+	pairs := e.Pairs()
+	states := make([]PairState, 0, len(pairs))
+	for _, pair := range pairs {
+		// Prices on a halted or closed market are frozen, not tradable
+		if len(e.pairHalts(pair)) > 0 {
+			continue
+		}
+		// A reworded market may no longer be the same question
+		if e.texts.Suspended(pair) {
+			continue
+		}
+
+		pmYes, pmOk := e.pmClient.GetQuote(pair.PMYes())
+		pmNo, pmNoOk := e.pmClient.GetQuote(pair.PMNo())
+		if !pmOk || !pmNoOk || pmYes.Ask == 0 || pmNo.Ask == 0 {
+			continue // Missing Polymarket prices
+		}
+
+		kalshiQuote, kalshiOk := e.kalshiClient.GetQuote(pair.KalshiYes())
+		if !kalshiOk || kalshiQuote.YesBid == 0 || kalshiQuote.YesAsk == 0 {
+			continue // Missing Kalshi prices
+		}
+
+		// The clients refuse corrupt updates, but seeded quotes and a
+		// Polymarket book whose sides were updated apart can still be
+		// unusable; nothing is priced off them
+		if !price.Valid(pmYes.Bid, pmYes.Ask) || !price.Valid(pmNo.Bid, pmNo.Ask) ||
+			!price.Valid(kalshiQuote.YesBid, kalshiQuote.YesAsk) || !price.Valid(kalshiQuote.NoBid, kalshiQuote.NoAsk) {
+			continue
+		}
+
+		state := newPairState(e.pmRules.Apply(pair), pmYes, pmNo, kalshiQuote)
+		state.Fees = e.fees
+		if book, ok := e.pmClient.GetBook(pair.PMYes()); ok {
+			state.PMYesBook = &book
+		}
+		if book, ok := e.pmClient.GetBook(pair.PMNo()); ok {
+			state.PMNoBook = &book
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// bestArbitrageEdge returns the best edge_pct_turn of buying both legs at
+// the ask across states, whether or not it clears the threshold
+func bestArbitrageEdge(states []PairState) float64 {
+	best := math.Inf(-1)
+	for _, s := range states {
+		for _, cost := range []float64{s.PMYes.Ask + s.Kalshi.NoAsk, s.PMNo.Ask + s.Kalshi.YesAsk} {
+			if cost > 0 {
+				best = math.Max(best, ComputeROI(1-cost, cost))
+			}
+		}
+	}
+	if math.IsInf(best, -1) {
+		return 0
+	}
+	return best
+}
+
+// evaluateStrategies runs every strategy over the pair states concurrently
+// and merges their opportunities in strategy order
+func (e *Engine) evaluateStrategies(states []PairState) []Opportunity {
+	results := make([][]Opportunity, len(e.strategies))
+	var wg sync.WaitGroup
+	for i, strategy := range e.strategies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			var opps []Opportunity
+			for _, state := range states {
+				opps = append(opps, strategy.Evaluate(state)...)
+			}
+			results[i] = opps
+			metrics.RecordStrategyEvaluation(strategy.Name(), len(opps), time.Since(start))
+		}()
+	}
+	wg.Wait()
+
+	merged := make([]Opportunity, 0, 100)
+	for _, opps := range results {
+		for _, o := range opps {
+			merged = append(merged, o)
+			metrics.RecordOpportunityFound()
+		}
+	}
+	return merged
+}
+
+// LastCompute returns when the last compute cycle (including update hooks)
+// completed, or the zero time if none has yet. Liveness probes use it to
+// detect a stalled engine.
+func (e *Engine) LastCompute() time.Time {
+	ns := e.lastCompute.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// GetOpportunities returns a copy of the current list of arbitrage
+// opportunities, or ctx's error if it is already done
+func (e *Engine) GetOpportunities(ctx context.Context) ([]Opportunity, error) {
+	opps, _, err := e.GetOpportunitiesWithRevision(ctx)
+	return opps, err
+}
+
+// GetOpportunitiesWithRevision returns a copy of the current opportunities
+// and their revision, which changes whenever the list does. Clients use it
+// to detect that nothing changed since their last read.
+func (e *Engine) GetOpportunitiesWithRevision(ctx context.Context) ([]Opportunity, uint64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	snap := e.Snapshot()
+	result := make([]Opportunity, len(snap.Opportunities))
+	copy(result, snap.Opportunities)
+	return result, snap.Revision, nil
+}
+
+// sameOpportunities reports whether two lists differ only in their
+// computation timestamps, which change every cycle
+func sameOpportunities(a, b []Opportunity) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !sameOpportunity(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameOpportunity reports whether two opportunities differ only in their
+// computation timestamps
+func sameOpportunity(a, b Opportunity) bool {
+	a.Timestamp, b.Timestamp = time.Time{}, time.Time{}
+	return reflect.DeepEqual(a, b)
+}
+
+// GetOpportunitiesTop returns the top N arbitrage opportunities
+func (e *Engine) GetOpportunitiesTop(ctx context.Context, n int) ([]Opportunity, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	opps := e.Snapshot().Opportunities
+	n = min(n, len(opps))
+	result := make([]Opportunity, n)
+	copy(result, opps[:n])
+	return result, nil
+}
+
+// GetOpportunityHistory returns the edge trajectory of an open opportunity,
+// or of one that ended within the retention age. It returns
+// ErrHistoryNotFound for any other id, and ctx's error if ctx is done before
+// a compute cycle in progress lets go of the engine.
+func (e *Engine) GetOpportunityHistory(ctx context.Context, id string) (EdgeHistory, error) {
+	unlock, err := e.rlockContext(ctx)
+	if err != nil {
+		return EdgeHistory{}, err
+	}
+	defer unlock()
+
+	var endedAt *time.Time
+	track, ok := e.tracks[id]
+	if !ok {
+		ended, found := e.ended[id]
+		if !found {
+			return EdgeHistory{}, ErrHistoryNotFound
+		}
+		track, endedAt = ended.edgeTrack, &ended.endedAt
+	}
+
+	samples := make([]EdgeSample, len(track.samples))
+	copy(samples, track.samples)
+
+	recent := samples
+	if len(recent) > trendWindow {
+		recent = recent[len(recent)-trendWindow:]
+	}
+	slope := EdgeSlope(recent)
+
+	return EdgeHistory{
+		ID:             id,
+		FirstSeen:      track.firstSeen,
+		EndedAt:        endedAt,
+		Trend:          ClassifyTrend(slope),
+		SlopePctPerMin: slope,
+		Samples:        samples,
+	}, nil
+}
+
+// latest returns the later of two timestamps
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// ComputeROI calculates ROI on turnover for a given edge and total cost
+func ComputeROI(edge, totalCost float64) float64 {
+	if totalCost <= 0 {
+		return 0
+	}
+	return (edge / totalCost) * 100.0
+}
+
+// ComputeEdge calculates the absolute edge (1 - total cost)
+func ComputeEdge(totalCost float64) float64 {
+	return 1.0 - totalCost
+}
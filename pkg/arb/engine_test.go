@@ -3,6 +3,9 @@ package arb
 import (
 	"math"
 	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
 )
 
 const floatTolerance = 1e-9
@@ -187,3 +190,96 @@ func BenchmarkComputeEdge(b *testing.B) {
 		ComputeEdge(0.95)
 	}
 }
+
+func TestEdgeSlopeAndTrend(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	samplesFor := func(edges ...float64) []EdgeSample {
+		out := make([]EdgeSample, len(edges))
+		for i, e := range edges {
+			out[i] = EdgeSample{Timestamp: t0.Add(time.Duration(i) * 30 * time.Second), EdgePctTurn: e}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		samples  []EdgeSample
+		slope    float64
+		expected string
+	}{
+		{name: "widening", samples: samplesFor(3, 3.5, 4, 4.5), slope: 1.0, expected: TrendWidening},
+		{name: "collapsing", samples: samplesFor(6, 5, 4), slope: -2.0, expected: TrendCollapsing},
+		{name: "flat", samples: samplesFor(4, 4, 4), slope: 0, expected: TrendFlat},
+		{name: "single sample", samples: samplesFor(4), slope: 0, expected: TrendFlat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slope := EdgeSlope(tt.samples)
+			if math.Abs(slope-tt.slope) > floatTolerance {
+				t.Errorf("EdgeSlope() = %.6f, want %.6f", slope, tt.slope)
+			}
+			if trend := ClassifyTrend(slope); trend != tt.expected {
+				t.Errorf("ClassifyTrend(%.4f) = %q, want %q", slope, trend, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEdgeTrackCap(t *testing.T) {
+	track := &edgeTrack{}
+	for i := 0; i < maxEdgeSamples+10; i++ {
+		track.add(EdgeSample{EdgePctTurn: float64(i)})
+	}
+	if len(track.samples) != maxEdgeSamples {
+		t.Fatalf("expected %d samples, got %d", maxEdgeSamples, len(track.samples))
+	}
+	if track.samples[0].EdgePctTurn != 10 {
+		t.Errorf("expected oldest samples dropped, first = %.0f", track.samples[0].EdgePctTurn)
+	}
+}
+
+func TestSameOpportunitiesIgnoresTimestamp(t *testing.T) {
+	now := time.Now()
+	a := []Opportunity{{ID: "x", Timestamp: now, EdgeAbs: 0.02}}
+	b := []Opportunity{{ID: "x", Timestamp: now.Add(time.Second), EdgeAbs: 0.02}}
+	if !sameOpportunities(a, b) {
+		t.Error("lists differing only in timestamp should be the same")
+	}
+
+	b[0].EdgeAbs = 0.03
+	if sameOpportunities(a, b) {
+		t.Error("a changed edge must be detected")
+	}
+	if sameOpportunities(a, nil) {
+		t.Error("lists of different length must differ")
+	}
+}
+
+func TestWeighFeeds(t *testing.T) {
+	e := newTestEngine(t, nil)
+	opps := []Opportunity{
+		{ID: "a", PMInstrument: instrument.PMToken("Y1"), KalshiInstrument: instrument.KalshiNo("K1"), Confidence: 0.8},
+		{ID: "b", PMInstrument: instrument.PMToken("Y2"), Confidence: 1},
+	}
+	if got := e.weighFeeds(append([]Opportunity(nil), opps...)); len(got) != 2 || got[0].Confidence != 0.8 {
+		t.Fatalf("without weights = %+v, want the opportunities unchanged", got)
+	}
+
+	weights := map[string]float64{instrument.VenuePolymarket: 1, instrument.VenueKalshi: 0.5}
+	e.SetFeedWeights(func(venue string) float64 {
+		if w, ok := weights[venue]; ok {
+			return w
+		}
+		return 1
+	})
+	got := e.weighFeeds(append([]Opportunity(nil), opps...))
+	if len(got) != 2 || got[0].Confidence != 0.4 || got[1].Confidence != 1 {
+		t.Errorf("degraded kalshi = %+v, want the kalshi leg's confidence halved", got)
+	}
+
+	weights[instrument.VenueKalshi] = 0
+	if got := e.weighFeeds(append([]Opportunity(nil), opps...)); len(got) != 1 || got[0].ID != "b" {
+		t.Errorf("excluded kalshi = %+v, want only the opportunity without a kalshi leg", got)
+	}
+}
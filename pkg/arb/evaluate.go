@@ -0,0 +1,159 @@
+package arb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+// MaxEvaluations caps the hypothetical positions priced by one
+// EvaluatePositions call
+const MaxEvaluations = 1000
+
+// ErrNoQuote is returned when a leg names an instrument without a live ask
+var ErrNoQuote = errors.New("no live ask for the instrument")
+
+// TakerFee is a taker fee of rate × contracts × price × (1 − price),
+// rounded up to the cent: Kalshi's fee schedule, which Polymarket's
+// fee-enabled markets follow too
+func TakerFee(rate, contracts, price float64) float64 {
+	if rate <= 0 || price <= 0 || price >= 1 {
+		return 0
+	}
+	return math.Ceil(rate*contracts*price*(1-price)*100-1e-9) / 100
+}
+
+// SetFeeRates sets the taker fee rates of each venue's leg, see TakerFee
+// and SetFees. Call before Start.
+func (e *Engine) SetFeeRates(pm, kalshi float64) {
+	e.fees.PMRate, e.fees.KalshiRate = pm, kalshi
+}
+
+// PositionLeg is one leg of a hypothetical hedged position: a price, or an
+// instrument whose live ask prices it. A price set alongside an instrument
+// overrides the ask.
+type PositionLeg struct {
+	Instrument instrument.ID `json:"instrument,omitempty"`
+	Price      float64       `json:"price,omitempty"`
+}
+
+// Position is a hypothetical position bought at its legs' prices
+type Position struct {
+	PM        PositionLeg `json:"pm"`
+	Kalshi    PositionLeg `json:"kalshi"`
+	Contracts float64     `json:"contracts,omitempty"` // 0 prices one contract
+}
+
+// Evaluation is a position priced by the engine's edge and fee model
+type Evaluation struct {
+	PMInstrument     instrument.ID `json:"pm_instrument,omitempty"`
+	KalshiInstrument instrument.ID `json:"kalshi_instrument,omitempty"`
+	PMPrice          float64       `json:"pm_price"`
+	KalshiPrice      float64       `json:"kalshi_price"`
+	Contracts        float64       `json:"contracts"`
+	TotalCost        float64       `json:"total_cost"`    // Per contract, fees included
+	EdgeAbs          float64       `json:"edge_abs"`      // Per contract, see ComputeEdge
+	EdgePctTurn      float64       `json:"edge_pct_turn"` // See ComputeROI
+	Fees             float64       `json:"fees"`          // Fees on both legs, USD, see FeeSchedule
+	NetEdge          float64       `json:"net_edge"`      // Contracts × EdgeAbs, USD
+	NetEdgePctTurn   float64       `json:"net_edge_pct_turn"`
+	Error            string        `json:"error,omitempty"` // Why the position could not be priced
+}
+
+// EvaluatePositions prices hypothetical positions exactly as the engine
+// prices opportunities, charging the fees set by SetFees. Each
+// evaluation carries its own error, so one bad position does not fail the
+// batch.
+func (e *Engine) EvaluatePositions(positions []Position) ([]Evaluation, error) {
+	if len(positions) > MaxEvaluations {
+		return nil, fmt.Errorf("at most %d positions per evaluation", MaxEvaluations)
+	}
+	out := make([]Evaluation, len(positions))
+	for i, p := range positions {
+		ev, err := e.evaluatePosition(p)
+		if err != nil {
+			ev = Evaluation{PMInstrument: p.PM.Instrument, KalshiInstrument: p.Kalshi.Instrument, Error: err.Error()}
+		}
+		out[i] = ev
+	}
+	return out, nil
+}
+
+// evaluatePosition prices one position
+func (e *Engine) evaluatePosition(p Position) (Evaluation, error) {
+	contracts := p.Contracts
+	if contracts == 0 {
+		contracts = 1
+	}
+	if contracts < 0 {
+		return Evaluation{}, errors.New("contracts must not be negative")
+	}
+	pm, err := e.legPrice(p.PM, instrument.VenuePolymarket)
+	if err != nil {
+		return Evaluation{}, fmt.Errorf("pm leg: %w", err)
+	}
+	kalshi, err := e.legPrice(p.Kalshi, instrument.VenueKalshi)
+	if err != nil {
+		return Evaluation{}, fmt.Errorf("kalshi leg: %w", err)
+	}
+
+	fees := e.fees.perContract(contracts, pm, kalshi)
+	totalCost := pm + kalshi + fees
+	edgeAbs := ComputeEdge(totalCost)
+	netEdge := contracts * edgeAbs
+	return Evaluation{
+		PMInstrument:     p.PM.Instrument,
+		KalshiInstrument: p.Kalshi.Instrument,
+		PMPrice:          pm,
+		KalshiPrice:      kalshi,
+		Contracts:        contracts,
+		TotalCost:        totalCost,
+		EdgeAbs:          edgeAbs,
+		EdgePctTurn:      ComputeROI(edgeAbs, totalCost),
+		Fees:             contracts * fees,
+		NetEdge:          netEdge,
+		NetEdgePctTurn:   ComputeROI(netEdge, contracts*totalCost),
+	}, nil
+}
+
+// legPrice returns a leg's price: its own, or the live ask of its
+// instrument on the given venue
+func (e *Engine) legPrice(leg PositionLeg, venue string) (float64, error) {
+	if leg.Instrument != "" {
+		if _, err := instrument.Parse(string(leg.Instrument)); err != nil {
+			return 0, err
+		}
+		if leg.Instrument.Venue() != venue {
+			return 0, fmt.Errorf("instrument %s is not on %s", leg.Instrument, venue)
+		}
+	}
+	switch {
+	case leg.Price != 0:
+		if leg.Price < 0 || leg.Price >= 1 {
+			return 0, fmt.Errorf("price %v outside (0, 1)", leg.Price)
+		}
+		return leg.Price, nil
+	case leg.Instrument == "":
+		return 0, errors.New("a price or an instrument is required")
+	case venue == instrument.VenuePolymarket:
+		if q, ok := e.pmClient.GetQuote(leg.Instrument); ok && q.Ask > 0 {
+			return q.Ask, nil
+		}
+	default:
+		q, ok := e.kalshiClient.GetQuote(instrument.KalshiYes(leg.Instrument.Key()))
+		ask := q.YesAsk
+		if leg.Instrument.Side() == instrument.SideNo {
+			// Kalshi's NO ask is derived from the YES bid
+			ask = 0
+			if q.YesBid > 0 {
+				ask = q.NoAsk
+			}
+		}
+		if ok && ask > 0 {
+			return ask, nil
+		}
+	}
+	return 0, ErrNoQuote
+}
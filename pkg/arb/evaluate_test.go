@@ -0,0 +1,71 @@
+package arb
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestTakerFee(t *testing.T) {
+	for _, c := range []struct{ rate, contracts, price, want float64 }{
+		{0.07, 10, 0.50, 0.18}, // 0.175 rounds up
+		{0.07, 100, 0.45, 1.74},
+		{0, 10, 0.50, 0},
+		{0.07, 10, 1, 0},
+	} {
+		if got := TakerFee(c.rate, c.contracts, c.price); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("TakerFee(%v, %v, %v) = %v, want %v", c.rate, c.contracts, c.price, got, c.want)
+		}
+	}
+}
+
+func TestEvaluatePositions(t *testing.T) {
+	e := newTestEngine(t, nil)
+	e.SetFeeRates(0, 0.07)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{{TokenID: "Y1", Bid: 0.38, Ask: 0.40, UpdatedAt: now}})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now}})
+
+	got, err := e.EvaluatePositions([]Position{
+		{PM: PositionLeg{Price: 0.40}, Kalshi: PositionLeg{Price: 0.55}, Contracts: 100},
+		{PM: PositionLeg{Instrument: instrument.PMToken("Y1")}, Kalshi: PositionLeg{Instrument: instrument.KalshiNo("K1")}},
+		{PM: PositionLeg{Instrument: instrument.PMToken("Y1"), Price: 0.35}, Kalshi: PositionLeg{Instrument: instrument.KalshiYes("K1")}},
+		{PM: PositionLeg{Instrument: instrument.PMToken("unquoted")}, Kalshi: PositionLeg{Price: 0.5}},
+		{PM: PositionLeg{Instrument: instrument.KalshiYes("K1")}, Kalshi: PositionLeg{Price: 0.5}},
+		{PM: PositionLeg{Price: 1.2}, Kalshi: PositionLeg{Price: 0.5}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Same edge as an opportunity at these prices: net of the Kalshi fee
+	if ev := got[0]; math.Abs(ev.TotalCost-0.9674) > 1e-9 || math.Abs(ev.EdgeAbs-0.0326) > 1e-9 ||
+		math.Abs(ev.EdgePctTurn-ComputeROI(0.0326, 0.9674)) > 1e-9 ||
+		math.Abs(ev.Fees-1.74) > 1e-9 || math.Abs(ev.NetEdge-(5-1.74)) > 1e-9 || ev.Error != "" {
+		t.Errorf("unexpected evaluation %+v", ev)
+	}
+	if ev := got[1]; ev.PMPrice != 0.40 || ev.KalshiPrice != 0.57 || ev.Contracts != 1 {
+		t.Errorf("live asks not used: %+v", ev)
+	}
+	if ev := got[2]; ev.PMPrice != 0.35 || ev.KalshiPrice != 0.45 {
+		t.Errorf("price must override the live ask: %+v", ev)
+	}
+	if ev := got[3]; !strings.Contains(ev.Error, ErrNoQuote.Error()) {
+		t.Errorf("unquoted instrument: %+v", ev)
+	}
+	if got[4].Error == "" || got[5].Error == "" {
+		t.Errorf("invalid legs priced: %+v, %+v", got[4], got[5])
+	}
+
+	if _, err := e.EvaluatePositions(make([]Position, MaxEvaluations+1)); err == nil {
+		t.Error("oversized batch accepted")
+	}
+	if _, err := e.legPrice(PositionLeg{Instrument: instrument.PMToken("unquoted")}, instrument.VenuePolymarket); !errors.Is(err, ErrNoQuote) {
+		t.Errorf("legPrice: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package arb
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/catalog"
+)
+
+// Event groups the monitored pairs on one underlying real-world event: the
+// markets of a Kalshi event, such as every strike of the same CPI print
+type Event struct {
+	ID        string     `json:"id"` // Kalshi event ticker
+	Series    string     `json:"series"`
+	Category  string     `json:"category"`
+	CloseTime *time.Time `json:"close_time"` // Earliest Kalshi close of its markets, nil when unknown
+	PairCount int        `json:"pair_count"`
+
+	// Opportunities open on the event's pairs, and their combined net edge
+	// after fees at quoted sizes, USD (see Capital)
+	OpportunityCount int     `json:"opportunity_count"`
+	TotalNetEdge     float64 `json:"total_net_edge"`
+
+	// Best is the event's opportunity with the highest edge, nil when none
+	Best *Opportunity `json:"best"`
+}
+
+// EventDetail is an event with its pairs and open opportunities, best first
+type EventDetail struct {
+	Event
+	Pairs         []MarketPair  `json:"pairs"`
+	Opportunities []Opportunity `json:"opportunities"`
+}
+
+// PairEventID returns the Kalshi event a pair's market belongs to: the
+// event ticker the catalog reported, or the market ticker without its
+// final segment (event tickers are extended by "-" and the market's own
+// suffix)
+func PairEventID(pair MarketPair) string {
+	if pair.Meta.KalshiEventTicker != "" {
+		return pair.Meta.KalshiEventTicker
+	}
+	if i := strings.LastIndexByte(pair.KalshiTicker, '-'); i > 0 {
+		return pair.KalshiTicker[:i]
+	}
+	return pair.KalshiTicker
+}
+
+// Events groups the monitored pairs by event with the current
+// opportunities' aggregates, events with the best edge first
+func (e *Engine) Events() []Event {
+	details := e.eventDetails()
+	out := make([]Event, 0, len(details))
+	for _, d := range details {
+		out = append(out, d.Event)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		bi, bj := out[i].Best, out[j].Best
+		switch {
+		case bi == nil || bj == nil:
+			if (bi == nil) != (bj == nil) {
+				return bi != nil
+			}
+		case bi.EdgePctTurn != bj.EdgePctTurn:
+			return bi.EdgePctTurn > bj.EdgePctTurn
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Event returns one event with its pairs and opportunities
+func (e *Engine) Event(id string) (EventDetail, bool) {
+	d, ok := e.eventDetails()[id]
+	if !ok {
+		return EventDetail{}, false
+	}
+	return *d, true
+}
+
+// eventDetails groups the monitored pairs and published opportunities by event
+func (e *Engine) eventDetails() map[string]*EventDetail {
+	details := make(map[string]*EventDetail)
+	byTicker := make(map[string]*EventDetail)
+	for _, pair := range e.Pairs() {
+		id := PairEventID(pair)
+		d, ok := details[id]
+		if !ok {
+			d = &EventDetail{
+				Event:         Event{ID: id, Series: catalog.KalshiSeriesTicker(id), Category: pair.Category},
+				Opportunities: []Opportunity{},
+			}
+			details[id] = d
+		}
+		d.PairCount++
+		d.Pairs = append(d.Pairs, pair)
+		if t := pair.Meta.KalshiCloseTime; t != nil && (d.CloseTime == nil || t.Before(*d.CloseTime)) {
+			d.CloseTime = t
+		}
+		byTicker[pair.KalshiTicker] = d
+	}
+
+	// Snapshot opportunities are sorted best first
+	for _, o := range e.Snapshot().Opportunities {
+		d, ok := byTicker[o.KalshiTicker]
+		if !ok {
+			continue
+		}
+		d.Opportunities = append(d.Opportunities, o)
+		d.OpportunityCount++
+		if o.Capital != nil {
+			d.TotalNetEdge += o.Capital.NetEdge
+		}
+		if d.Best == nil {
+			best := o
+			d.Best = &best
+		}
+	}
+	return details
+}
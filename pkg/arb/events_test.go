@@ -0,0 +1,60 @@
+package arb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestEngineEvents(t *testing.T) {
+	early, late := time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)
+	pairs := []MarketPair{
+		{PMConditionID: "c1", PMTokenYes: "y1", PMTokenNo: "n1", KalshiTicker: "KXCPI-26NOV-T3.0", Category: "economics",
+			Meta: MarketMetadata{KalshiEventTicker: "KXCPI-26NOV", KalshiCloseTime: &late}},
+		{PMConditionID: "c2", PMTokenYes: "y2", PMTokenNo: "n2", KalshiTicker: "KXCPI-26NOV-T3.1", Category: "economics",
+			Meta: MarketMetadata{KalshiCloseTime: &early}},
+		{PMConditionID: "c3", PMTokenYes: "y3", PMTokenNo: "n3", KalshiTicker: "KXFED-26DEC-H0", Category: "economics"},
+	}
+	e := newTestEngine(t, pairs)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "y1", Ask: 0.40, AskSize: 10, UpdatedAt: now}, {TokenID: "n1", Ask: 0.62, UpdatedAt: now},
+		{TokenID: "y2", Ask: 0.38, AskSize: 10, UpdatedAt: now}, {TokenID: "n2", Ask: 0.64, UpdatedAt: now},
+		{TokenID: "y3", Ask: 0.60, UpdatedAt: now}, {TokenID: "n3", Ask: 0.60, UpdatedAt: now},
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{
+		{Ticker: "KXCPI-26NOV-T3.0", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now},
+		{Ticker: "KXCPI-26NOV-T3.1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now},
+		{Ticker: "KXFED-26DEC-H0", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now},
+	})
+	e.computeOpportunities()
+
+	events := e.Events()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+	cpi, fed := events[0], events[1]
+	if cpi.ID != "KXCPI-26NOV" || cpi.Series != "KXCPI" || cpi.PairCount != 2 || cpi.OpportunityCount != 2 {
+		t.Errorf("cpi event = %+v", cpi)
+	}
+	if cpi.Best == nil || cpi.Best.KalshiTicker != "KXCPI-26NOV-T3.1" || cpi.CloseTime == nil || !cpi.CloseTime.Equal(early) {
+		t.Errorf("cpi event best %+v, closing %v; want T3.1 closing first", cpi.Best, cpi.CloseTime)
+	}
+	// 10 contracts at 0.97 and 0.95
+	if !near(cpi.TotalNetEdge, 0.8) {
+		t.Errorf("cpi total net edge = %v, want 0.8", cpi.TotalNetEdge)
+	}
+	if fed.ID != "KXFED-26DEC" || fed.OpportunityCount != 0 || fed.Best != nil {
+		t.Errorf("fed event = %+v, want no opportunities", fed)
+	}
+
+	detail, ok := e.Event("KXCPI-26NOV")
+	if !ok || len(detail.Pairs) != 2 || len(detail.Opportunities) != 2 ||
+		detail.Opportunities[0].EdgePctTurn < detail.Opportunities[1].EdgePctTurn {
+		t.Errorf("cpi detail = %+v, %v", detail, ok)
+	}
+	if _, ok := e.Event("KXGDP-26Q4"); ok {
+		t.Error("event without monitored pairs found")
+	}
+}
@@ -0,0 +1,63 @@
+package arb
+
+// ExitEdge is what closing a held hedged position would fetch now, selling
+// each leg at its bid
+type ExitEdge struct {
+	Combo     string  `json:"combo"`
+	PMBid     float64 `json:"pm_bid"`
+	KalshiBid float64 `json:"kalshi_bid"`
+	Proceeds  float64 `json:"proceeds"` // PMBid + KalshiBid per contract
+	// RemainingEdge is 1 - Proceeds: what holding to resolution still earns
+	// per contract over selling now. At or below 0 the edge has collapsed or
+	// reversed and unwinding does at least as well as holding.
+	RemainingEdge float64 `json:"remaining_edge"`
+}
+
+// HedgeCombos returns the combos that hedge a pair: buying one outcome on
+// each venue
+func HedgeCombos(inverted bool) []string {
+	if inverted {
+		return []string{ComboPMYesKalshiYes, ComboKalshiNoPMNo}
+	}
+	return []string{ComboPMYesKalshiNo, ComboKalshiYesPMNo}
+}
+
+// LegBids returns what selling each leg of a combo would fetch per contract
+func (p PairPrices) LegBids(combo string) (pm, kalshi float64) {
+	pmYes, kalshiYes := ComboSides(combo)
+	pm, kalshi = p.PMNoBid, p.KalshiNoBid
+	if pmYes {
+		pm = p.PMYesBid
+	}
+	if kalshiYes {
+		kalshi = p.KalshiYesBid
+	}
+	return pm, kalshi
+}
+
+// Exit returns the exit edge of a position held in combo. It reports false
+// when either leg has no bid to sell into.
+func (p PairPrices) Exit(combo string) (ExitEdge, bool) {
+	pm, kalshi := p.LegBids(combo)
+	if pm <= 0 || kalshi <= 0 {
+		return ExitEdge{}, false
+	}
+	return ExitEdge{
+		Combo:         combo,
+		PMBid:         pm,
+		KalshiBid:     kalshi,
+		Proceeds:      pm + kalshi,
+		RemainingEdge: 1 - (pm + kalshi),
+	}, true
+}
+
+// exits returns the exit edges of both hedges of a pair
+func (p PairPrices) exits() []ExitEdge {
+	out := make([]ExitEdge, 0, 2)
+	for _, combo := range HedgeCombos(p.Inverted) {
+		if x, ok := p.Exit(combo); ok {
+			out = append(out, x)
+		}
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package arb
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPairPricesExit(t *testing.T) {
+	pp := PairPrices{PMYesBid: 0.45, PMNoBid: 0.53, KalshiYesBid: 0.44, KalshiNoBid: 0.52}
+
+	x, ok := pp.Exit(ComboPMYesKalshiNo)
+	if !ok || x.PMBid != 0.45 || x.KalshiBid != 0.52 || math.Abs(x.RemainingEdge-0.03) > 1e-9 {
+		t.Errorf("PM-YES + K-NO exit = %+v, %v", x, ok)
+	}
+	if x, _ := pp.Exit(ComboKalshiYesPMNo); x.PMBid != 0.53 || x.KalshiBid != 0.44 {
+		t.Errorf("K-YES + PM-NO sells the wrong sides: %+v", x)
+	}
+
+	if exits := pp.exits(); len(exits) != 2 || exits[0].Combo != ComboPMYesKalshiNo {
+		t.Errorf("expected both hedges of a straight pair, got %+v", exits)
+	}
+	pp.Inverted = true
+	if exits := pp.exits(); len(exits) != 2 || exits[0].Combo != ComboPMYesKalshiYes || exits[1].Combo != ComboKalshiNoPMNo {
+		t.Errorf("expected both hedges of an inverted pair, got %+v", exits)
+	}
+
+	pp.KalshiNoBid = 0
+	if _, ok := pp.Exit(ComboKalshiNoPMNo); ok {
+		t.Error("exit priced without a bid to sell into")
+	}
+}
@@ -0,0 +1,86 @@
+package arb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+// FairValue is an instrument's cross-venue fair price: the mid of the same
+// outcome's book on the other venue. A maker quoting the instrument around
+// it is hedgeable at the reference's touch; Width is how uncertain the fair
+// price is and the least a quote must clear it by to cover the hedge.
+type FairValue struct {
+	Instrument   instrument.ID `json:"instrument"` // Instrument to quote
+	Reference    instrument.ID `json:"reference"`  // Same outcome on the other venue
+	KalshiTicker string        `json:"kalshi_ticker"`
+	PMTitle      string        `json:"pm_title"`
+	Fair         float64       `json:"fair"` // Reference mid
+	ReferenceBid float64       `json:"reference_bid"`
+	ReferenceAsk float64       `json:"reference_ask"`
+	Width        float64       `json:"width"`      // Reference spread
+	UpdatedAt    time.Time     `json:"updated_at"` // Reference quote time
+}
+
+// Key identifies the fair value of an instrument from one reference; an
+// instrument paired more than once has one per pair
+func (f FairValue) Key() string {
+	return string(f.Instrument) + "|" + string(f.Reference)
+}
+
+// FairValues derives a fair value for all four instruments of every pair
+// whose reference is quoted two-sided, ordered by instrument. Halted and
+// suspended pairs are skipped, as are Kalshi references while Kalshi is
+// disabled or in maintenance.
+func (e *Engine) FairValues() []FairValue {
+	kalshiUp := e.kalshiClient.IsQuoting() && (e.kalshiPaused == nil || !e.kalshiPaused())
+	pairs := e.Pairs()
+	out := make([]FairValue, 0, 4*len(pairs))
+	for _, pair := range pairs {
+		if (e.halts != nil && len(e.halts.pairHalts(pair)) > 0) || e.texts.Suspended(pair) {
+			continue
+		}
+		pmYes, pmYesOK := e.pmClient.GetQuote(pair.PMYes())
+		pmNo, pmNoOK := e.pmClient.GetQuote(pair.PMNo())
+		k, kOK := e.kalshiClient.GetQuote(pair.KalshiYes())
+		kOK = kOK && kalshiUp
+
+		// The Kalshi instrument carrying each Polymarket token's outcome
+		sameAsYes, sameAsNo := pair.KalshiYes(), pair.KalshiNo()
+		kYesBid, kYesAsk, kNoBid, kNoAsk := k.YesBid, k.YesAsk, k.NoBid, k.NoAsk
+		if pair.Inverted {
+			sameAsYes, sameAsNo = sameAsNo, sameAsYes
+			kYesBid, kYesAsk, kNoBid, kNoAsk = kNoBid, kNoAsk, kYesBid, kYesAsk
+		}
+
+		add := func(id, ref instrument.ID, ok bool, bid, ask float64, at time.Time) {
+			if !ok || bid <= 0 || ask <= 0 || bid > ask {
+				return
+			}
+			out = append(out, FairValue{
+				Instrument:   id,
+				Reference:    ref,
+				KalshiTicker: pair.KalshiTicker,
+				PMTitle:      pair.PMTitle,
+				Fair:         (bid + ask) / 2,
+				ReferenceBid: bid,
+				ReferenceAsk: ask,
+				Width:        ask - bid,
+				UpdatedAt:    at,
+			})
+		}
+		add(pair.PMYes(), sameAsYes, kOK, kYesBid, kYesAsk, k.UpdatedAt)
+		add(pair.PMNo(), sameAsNo, kOK, kNoBid, kNoAsk, k.UpdatedAt)
+		add(sameAsYes, pair.PMYes(), pmYesOK, pmYes.Bid, pmYes.Ask, pmYes.UpdatedAt)
+		add(sameAsNo, pair.PMNo(), pmNoOK, pmNo.Bid, pmNo.Ask, pmNo.UpdatedAt)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Instrument != out[j].Instrument {
+			return out[i].Instrument < out[j].Instrument
+		}
+		return out[i].Reference < out[j].Reference
+	})
+	return out
+}
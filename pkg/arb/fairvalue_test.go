@@ -0,0 +1,64 @@
+package arb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestFairValues(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTokenNo: "N2", KalshiTicker: "K2", Inverted: true},
+	}
+	e := newTestEngine(t, pairs)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "Y1", Bid: 0.38, Ask: 0.40, UpdatedAt: now},
+		{TokenID: "N1", Bid: 0.60, Ask: 0.62, UpdatedAt: now},
+		{TokenID: "Y2", Bid: 0.30, Ask: 0.34, UpdatedAt: now},
+		{TokenID: "N2", Ask: 0.70, UpdatedAt: now}, // One-sided: no fair value from it
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{
+		{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now},
+		{Ticker: "K2", YesBid: 0.64, YesAsk: 0.68, NoBid: 0.32, NoAsk: 0.36, UpdatedAt: now},
+	})
+
+	got := make(map[instrument.ID]FairValue)
+	for _, f := range e.FairValues() {
+		got[f.Instrument] = f
+	}
+	want := []struct {
+		id, ref   instrument.ID
+		fair, wid float64
+	}{
+		{instrument.PMToken("Y1"), instrument.KalshiYes("K1"), 0.44, 0.02},
+		{instrument.PMToken("N1"), instrument.KalshiNo("K1"), 0.56, 0.02},
+		{instrument.KalshiYes("K1"), instrument.PMToken("Y1"), 0.39, 0.02},
+		{instrument.KalshiNo("K1"), instrument.PMToken("N1"), 0.61, 0.02},
+		// Inverted: PM YES is the Kalshi NO outcome
+		{instrument.PMToken("Y2"), instrument.KalshiNo("K2"), 0.34, 0.04},
+		{instrument.PMToken("N2"), instrument.KalshiYes("K2"), 0.66, 0.04},
+		{instrument.KalshiNo("K2"), instrument.PMToken("Y2"), 0.32, 0.04},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d fair values, got %+v", len(want), got)
+	}
+	for _, w := range want {
+		f, ok := got[w.id]
+		if !ok || f.Reference != w.ref || math.Abs(f.Fair-w.fair) > 1e-9 || math.Abs(f.Width-w.wid) > 1e-9 {
+			t.Errorf("%s: expected fair %.2f from %s, got %+v", w.id, w.fair, w.ref, f)
+		}
+	}
+
+	// Without Kalshi, only Kalshi instruments are priced, from Polymarket
+	e.SetKalshiPauseCheck(func() bool { return true })
+	for _, f := range e.FairValues() {
+		if f.Instrument.Venue() != instrument.VenueKalshi {
+			t.Errorf("unexpected fair value from a paused Kalshi: %+v", f)
+		}
+	}
+}
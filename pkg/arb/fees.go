@@ -0,0 +1,42 @@
+package arb
+
+// DefaultKalshiFeeRate is the taker fee rate of Kalshi's general fee
+// schedule, see TakerFee
+const DefaultKalshiFeeRate = 0.07
+
+// FeeSchedule is the trading costs of buying an opportunity's legs. The
+// engine nets them out of opportunities' edges, so an edge smaller than
+// its fees is not reported as an arb, and charges them in Capital and
+// EvaluatePositions.
+type FeeSchedule struct {
+	KalshiRate float64 // Kalshi taker fee rate, see TakerFee
+	PMRate     float64 // Polymarket taker fee rate, on fee-enabled markets
+	PMRelayer  float64 // Polymarket relayer cost per order, USD
+}
+
+// legFees returns the fees of buying contracts of each leg at the given
+// prices, USD. Taker fees are rounded up per order, so the fee per contract
+// falls with the order's size.
+func (f FeeSchedule) legFees(contracts, pmPrice, kalshiPrice float64) (pm, kalshi float64) {
+	pm = TakerFee(f.PMRate, contracts, pmPrice)
+	if f.PMRelayer > 0 {
+		pm += f.PMRelayer
+	}
+	return pm, TakerFee(f.KalshiRate, contracts, kalshiPrice)
+}
+
+// perContract returns the fees of buying contracts at the legs' prices,
+// per contract; a size of 0 prices a single contract
+func (f FeeSchedule) perContract(contracts, pmPrice, kalshiPrice float64) float64 {
+	if contracts <= 0 {
+		contracts = 1
+	}
+	pm, kalshi := f.legFees(contracts, pmPrice, kalshiPrice)
+	return (pm + kalshi) / contracts
+}
+
+// SetFees sets the trading costs netted out of opportunities' edges and
+// charged by Capital and EvaluatePositions. Call before Start.
+func (e *Engine) SetFees(fees FeeSchedule) {
+	e.fees = fees
+}
@@ -0,0 +1,172 @@
+package arb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Filter decides whether a detected opportunity is published. Filters run in
+// order between detection and publication; the first rejection wins.
+type Filter interface {
+	Name() string
+	Allow(opp *Opportunity, now time.Time) bool
+}
+
+// FilterParams holds the tunables for the built-in filters
+type FilterParams struct {
+	MinSize                 float64       // Minimum executable size in contracts
+	MinLiquidity            float64       // Minimum pair liquidity in USD
+	MaxQuoteAge             time.Duration // Maximum age of either leg's quote
+	Categories              []string      // Allowed categories (case-insensitive)
+	MinResolutionConfidence float64       // Minimum match score between the two markets
+	MinConfidence           float64       // Minimum quote-age confidence
+	MinAnnualizedReturn     float64       // Minimum annualized return in percent
+	Rule                    string        // Inclusion predicate, see CompileRule
+}
+
+// FilterChain is an ordered list of filters
+type FilterChain []Filter
+
+// Apply returns the opportunities accepted by every filter, reporting each
+// rejection to onReject with the name of the filter that rejected it
+func (c FilterChain) Apply(opps []Opportunity, now time.Time, onReject func(filter string)) []Opportunity {
+	if len(c) == 0 {
+		return opps
+	}
+
+	kept := opps[:0]
+	for i := range opps {
+		allowed := true
+		for _, f := range c {
+			if !f.Allow(&opps[i], now) {
+				allowed = false
+				if onReject != nil {
+					onReject(f.Name())
+				}
+				break
+			}
+		}
+		if allowed {
+			kept = append(kept, opps[i])
+		}
+	}
+	return kept
+}
+
+// BuildFilters constructs a chain from filter names in the configured order.
+// Known names: min_size, liquidity, staleness, category, resolution_confidence,
+// confidence, annualized_return, rule.
+func BuildFilters(names []string, params FilterParams) (FilterChain, error) {
+	chain := make(FilterChain, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "min_size":
+			chain = append(chain, minSizeFilter{min: params.MinSize})
+		case "liquidity":
+			chain = append(chain, liquidityFilter{min: params.MinLiquidity})
+		case "staleness":
+			chain = append(chain, stalenessFilter{maxAge: params.MaxQuoteAge})
+		case "category":
+			allowed := make(map[string]struct{}, len(params.Categories))
+			for _, c := range params.Categories {
+				allowed[strings.ToLower(c)] = struct{}{}
+			}
+			chain = append(chain, categoryFilter{allowed: allowed})
+		case "resolution_confidence":
+			chain = append(chain, resolutionConfidenceFilter{min: params.MinResolutionConfidence})
+		case "confidence":
+			chain = append(chain, confidenceFilter{min: params.MinConfidence})
+		case "annualized_return":
+			chain = append(chain, annualizedReturnFilter{min: params.MinAnnualizedReturn})
+		case "rule":
+			rule, err := CompileRule(params.Rule)
+			if err != nil {
+				return nil, fmt.Errorf("rule filter: %w", err)
+			}
+			chain = append(chain, ruleFilter{rule: rule})
+		default:
+			return nil, fmt.Errorf("unknown filter %q", name)
+		}
+	}
+	return chain, nil
+}
+
+// minSizeFilter rejects opportunities whose known executable size is too small.
+// Unknown sizes (zero) pass, since not every feed reports depth.
+type minSizeFilter struct{ min float64 }
+
+func (f minSizeFilter) Name() string { return "min_size" }
+
+func (f minSizeFilter) Allow(opp *Opportunity, _ time.Time) bool {
+	return opp.MaxSize == 0 || opp.MaxSize >= f.min
+}
+
+// liquidityFilter rejects pairs whose market liquidity is below the floor
+type liquidityFilter struct{ min float64 }
+
+func (f liquidityFilter) Name() string { return "liquidity" }
+
+func (f liquidityFilter) Allow(opp *Opportunity, _ time.Time) bool {
+	return opp.Liquidity >= f.min
+}
+
+// stalenessFilter rejects opportunities built on an old quote on either leg
+type stalenessFilter struct{ maxAge time.Duration }
+
+func (f stalenessFilter) Name() string { return "staleness" }
+
+func (f stalenessFilter) Allow(opp *Opportunity, now time.Time) bool {
+	if f.maxAge <= 0 {
+		return true
+	}
+	return now.Sub(opp.PMQuoteTime) <= f.maxAge && now.Sub(opp.KalshiQuoteTime) <= f.maxAge
+}
+
+// categoryFilter only allows opportunities in the listed categories
+type categoryFilter struct{ allowed map[string]struct{} }
+
+func (f categoryFilter) Name() string { return "category" }
+
+func (f categoryFilter) Allow(opp *Opportunity, _ time.Time) bool {
+	_, ok := f.allowed[strings.ToLower(opp.Category)]
+	return ok
+}
+
+// resolutionConfidenceFilter rejects pairs whose markets may not resolve identically,
+// using the title match score as the confidence measure
+type resolutionConfidenceFilter struct{ min float64 }
+
+func (f resolutionConfidenceFilter) Name() string { return "resolution_confidence" }
+
+func (f resolutionConfidenceFilter) Allow(opp *Opportunity, _ time.Time) bool {
+	return opp.MatchScore >= f.min
+}
+
+// confidenceFilter rejects opportunities whose quote-age confidence is too low
+type confidenceFilter struct{ min float64 }
+
+func (f confidenceFilter) Name() string { return "confidence" }
+
+func (f confidenceFilter) Allow(opp *Opportunity, _ time.Time) bool {
+	return opp.Confidence >= f.min
+}
+
+// annualizedReturnFilter rejects opportunities whose return per year until
+// resolution is too low. Unknown resolution times pass, like unknown sizes.
+type annualizedReturnFilter struct{ min float64 }
+
+func (f annualizedReturnFilter) Name() string { return "annualized_return" }
+
+func (f annualizedReturnFilter) Allow(opp *Opportunity, _ time.Time) bool {
+	return opp.AnnualizedReturn == nil || *opp.AnnualizedReturn >= f.min
+}
+
+// ruleFilter only allows opportunities matching a user-defined rule
+type ruleFilter struct{ rule *Rule }
+
+func (f ruleFilter) Name() string { return "rule" }
+
+func (f ruleFilter) Allow(opp *Opportunity, now time.Time) bool {
+	return f.rule.Match(opp, now)
+}
@@ -0,0 +1,102 @@
+package arb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildFiltersUnknown(t *testing.T) {
+	if _, err := BuildFilters([]string{"min_size", "bogus"}, FilterParams{}); err == nil {
+		t.Fatal("expected error for unknown filter")
+	}
+}
+
+func TestFilterChainApply(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	fresh := now.Add(-2 * time.Second)
+	stale := now.Add(-2 * time.Minute)
+
+	chain, err := BuildFilters(
+		[]string{"category", "staleness", "min_size", "liquidity", "resolution_confidence"},
+		FilterParams{
+			MinSize:                 10,
+			MinLiquidity:            1000,
+			MaxQuoteAge:             30 * time.Second,
+			Categories:              []string{"Politics"},
+			MinResolutionConfidence: 0.7,
+		},
+	)
+	if err != nil {
+		t.Fatalf("BuildFilters: %v", err)
+	}
+
+	base := Opportunity{Category: "politics", MaxSize: 50, Liquidity: 5000, MatchScore: 0.9, PMQuoteTime: fresh, KalshiQuoteTime: fresh}
+
+	tests := []struct {
+		name     string
+		mutate   func(o *Opportunity)
+		rejectBy string
+	}{
+		{name: "passes all", mutate: func(o *Opportunity) {}},
+		{name: "unknown size passes", mutate: func(o *Opportunity) { o.MaxSize = 0 }},
+		{name: "wrong category", mutate: func(o *Opportunity) { o.Category = "sports" }, rejectBy: "category"},
+		{name: "stale kalshi quote", mutate: func(o *Opportunity) { o.KalshiQuoteTime = stale }, rejectBy: "staleness"},
+		{name: "too small", mutate: func(o *Opportunity) { o.MaxSize = 5 }, rejectBy: "min_size"},
+		{name: "illiquid", mutate: func(o *Opportunity) { o.Liquidity = 100 }, rejectBy: "liquidity"},
+		{name: "weak match", mutate: func(o *Opportunity) { o.MatchScore = 0.6 }, rejectBy: "resolution_confidence"},
+		{name: "first rejection wins", mutate: func(o *Opportunity) { o.Category = "sports"; o.MaxSize = 1 }, rejectBy: "category"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opp := base
+			tt.mutate(&opp)
+
+			var rejectedBy []string
+			kept := chain.Apply([]Opportunity{opp}, now, func(f string) { rejectedBy = append(rejectedBy, f) })
+
+			if tt.rejectBy == "" {
+				if len(kept) != 1 || len(rejectedBy) != 0 {
+					t.Fatalf("expected opportunity kept, rejected by %v", rejectedBy)
+				}
+				return
+			}
+			if len(kept) != 0 {
+				t.Fatal("expected opportunity rejected")
+			}
+			if len(rejectedBy) != 1 || rejectedBy[0] != tt.rejectBy {
+				t.Errorf("rejected by %v, want [%s]", rejectedBy, tt.rejectBy)
+			}
+		})
+	}
+}
+
+func TestQuoteConfidence(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	halfLife := 15 * time.Second
+
+	tests := []struct {
+		name     string
+		pmAge    time.Duration
+		kAge     time.Duration
+		expected float64
+	}{
+		{name: "fresh quotes", pmAge: 0, kAge: 0, expected: 1},
+		{name: "one half-life on one leg", pmAge: 0, kAge: 15 * time.Second, expected: 0.5},
+		{name: "both legs one half-life", pmAge: 15 * time.Second, kAge: 15 * time.Second, expected: 0.25},
+		{name: "45s kalshi quote", pmAge: time.Second, kAge: 45 * time.Second, expected: 0.125 * 0.9548416039104165},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := QuoteConfidence(now, now.Add(-tt.pmAge), now.Add(-tt.kAge), halfLife)
+			if diff := got - tt.expected; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("QuoteConfidence() = %.10f, want %.10f", got, tt.expected)
+			}
+		})
+	}
+
+	if got := QuoteConfidence(now, now.Add(-time.Hour), now, 0); got != 1 {
+		t.Errorf("expected decay disabled with zero half-life, got %f", got)
+	}
+}
@@ -0,0 +1,119 @@
+package arb
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Venue names used in halt records
+const (
+	VenuePolymarket = "polymarket"
+	VenueKalshi     = "kalshi"
+)
+
+// Halt records a market that its venue has paused, halted or closed
+type Halt struct {
+	Venue  string    `json:"venue"`
+	Market string    `json:"market"` // PM condition ID or Kalshi ticker
+	Status string    `json:"status"` // Venue-reported status, e.g. "paused"
+	Since  time.Time `json:"since"`
+}
+
+// HaltedPair is a monitored pair excluded from opportunities because one of
+// its markets is halted or, with StatusVenueClosed, outside trading hours
+type HaltedPair struct {
+	PMTitle      string `json:"pm_title"`
+	KalshiTicker string `json:"kalshi_ticker"`
+	KalshiTitle  string `json:"kalshi_title"`
+	Halts        []Halt `json:"halts"`
+}
+
+// HaltRegistry holds the current set of halted markets on both venues
+type HaltRegistry struct {
+	mu    sync.RWMutex
+	halts map[string]Halt // venue|market -> halt
+}
+
+// NewHaltRegistry creates an empty registry
+func NewHaltRegistry() *HaltRegistry {
+	return &HaltRegistry{halts: make(map[string]Halt)}
+}
+
+// Update records a market's latest status. It returns true when the market
+// transitioned between halted and tradable.
+func (r *HaltRegistry) Update(venue, market, status string, halted bool, now time.Time) bool {
+	key := venue + "|" + market
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, wasHalted := r.halts[key]
+	switch {
+	case halted && !wasHalted:
+		r.halts[key] = Halt{Venue: venue, Market: market, Status: status, Since: now}
+		return true
+	case halted && existing.Status != status:
+		existing.Status = status
+		r.halts[key] = existing
+	case !halted && wasHalted:
+		delete(r.halts, key)
+		return true
+	}
+	return false
+}
+
+// Halted returns the halt record for a market, if it is halted
+func (r *HaltRegistry) Halted(venue, market string) (Halt, bool) {
+	if r == nil || market == "" {
+		return Halt{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.halts[venue+"|"+market]
+	return h, ok
+}
+
+// Count returns the number of halted markets on a venue
+func (r *HaltRegistry) Count(venue string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n := 0
+	for _, h := range r.halts {
+		if h.Venue == venue {
+			n++
+		}
+	}
+	return n
+}
+
+// pairHalts returns the halts affecting either market of a pair
+func (r *HaltRegistry) pairHalts(pair MarketPair) []Halt {
+	var halts []Halt
+	if h, ok := r.Halted(VenuePolymarket, pair.PMConditionID); ok {
+		halts = append(halts, h)
+	}
+	if h, ok := r.Halted(VenueKalshi, pair.KalshiTicker); ok {
+		halts = append(halts, h)
+	}
+	return halts
+}
+
+// HaltedPairs returns the monitored pairs currently excluded because a market
+// is halted or outside its trading hours
+func (e *Engine) HaltedPairs() []HaltedPair {
+	out := make([]HaltedPair, 0)
+	for _, pair := range e.Pairs() {
+		if halts := e.pairHalts(pair); len(halts) > 0 {
+			out = append(out, HaltedPair{
+				PMTitle:      pair.PMTitle,
+				KalshiTicker: pair.KalshiTicker,
+				KalshiTitle:  pair.KalshiTitle,
+				Halts:        halts,
+			})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].KalshiTicker < out[j].KalshiTicker })
+	return out
+}
@@ -0,0 +1,62 @@
+package arb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHaltRegistryTransitions(t *testing.T) {
+	r := NewHaltRegistry()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if r.Update(VenueKalshi, "K1", "open", false, t0) {
+		t.Error("open market should not be a transition")
+	}
+	if !r.Update(VenueKalshi, "K1", "paused", true, t0) {
+		t.Error("expected halt transition")
+	}
+	if r.Update(VenueKalshi, "K1", "closed", true, t0.Add(time.Minute)) {
+		t.Error("status change while halted should not be a transition")
+	}
+
+	h, ok := r.Halted(VenueKalshi, "K1")
+	if !ok || h.Status != "closed" || !h.Since.Equal(t0) {
+		t.Errorf("unexpected halt %+v, %v", h, ok)
+	}
+	if r.Count(VenueKalshi) != 1 || r.Count(VenuePolymarket) != 0 {
+		t.Errorf("unexpected counts")
+	}
+
+	if !r.Update(VenueKalshi, "K1", "open", false, t0.Add(2*time.Minute)) {
+		t.Error("expected resume transition")
+	}
+	if _, ok := r.Halted(VenueKalshi, "K1"); ok {
+		t.Error("market should no longer be halted")
+	}
+}
+
+func TestEngineHaltedPairs(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "c1", KalshiTicker: "K1"},
+		{PMConditionID: "c2", KalshiTicker: "K2"},
+		{PMConditionID: "c3", KalshiTicker: "K3"},
+	}
+	e := &Engine{pairs: pairs}
+	if got := e.HaltedPairs(); len(got) != 0 {
+		t.Fatalf("expected no halted pairs without registry, got %d", len(got))
+	}
+
+	r := NewHaltRegistry()
+	r.Update(VenuePolymarket, "c2", "closed", true, time.Now())
+	r.Update(VenueKalshi, "K2", "paused", true, time.Now())
+	r.Update(VenueKalshi, "K3", "paused", true, time.Now())
+	e.SetHalts(r)
+
+	got := e.HaltedPairs()
+	if len(got) != 2 {
+		t.Fatalf("expected 2 halted pairs, got %d", len(got))
+	}
+	if got[0].KalshiTicker != "K2" || len(got[0].Halts) != 2 {
+		t.Errorf("unexpected first halted pair %+v", got[0])
+	}
+}
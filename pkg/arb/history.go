@@ -0,0 +1,110 @@
+package arb
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// maxEdgeSamples caps the per-opportunity trajectory (~10 minutes at one cycle per second)
+	maxEdgeSamples = 600
+	// trendWindow is the number of most recent samples used to classify the trend
+	trendWindow = 30
+	// trendFlatPctPerMin is the slope below which an edge is considered flat
+	trendFlatPctPerMin = 0.05
+)
+
+// Edge trend classifications
+const (
+	TrendWidening   = "widening"
+	TrendCollapsing = "collapsing"
+	TrendFlat       = "flat"
+)
+
+// EdgeSample is one compute-cycle observation of an opportunity's edge
+type EdgeSample struct {
+	Timestamp   time.Time `json:"timestamp"`
+	EdgeAbs     float64   `json:"edge_abs"`
+	EdgePctTurn float64   `json:"edge_pct_turn"`
+}
+
+// EdgeHistory is the edge trajectory of an opportunity's current streak, or
+// of its last one when the opportunity has since disappeared
+type EdgeHistory struct {
+	ID             string       `json:"id"`
+	FirstSeen      time.Time    `json:"first_seen"`
+	EndedAt        *time.Time   `json:"ended_at"` // nil while the opportunity is open
+	Trend          string       `json:"trend"`
+	SlopePctPerMin float64      `json:"slope_pct_per_min"` // Least-squares slope over the trend window
+	Samples        []EdgeSample `json:"samples"`
+}
+
+// edgeTrack accumulates samples for one opportunity streak
+type edgeTrack struct {
+	firstSeen time.Time
+	samples   []EdgeSample
+	expired   bool // Past its execution window
+}
+
+func (t *edgeTrack) add(s EdgeSample) {
+	if len(t.samples) >= maxEdgeSamples {
+		// Drop the oldest sample, reusing the backing array
+		copy(t.samples, t.samples[1:])
+		t.samples = t.samples[:len(t.samples)-1]
+	}
+	t.samples = append(t.samples, s)
+}
+
+// fill replaces the samples between from and to with backfilled ones,
+// keeping the trajectory in time order and within maxEdgeSamples
+func (t *edgeTrack) fill(from, to time.Time, backfilled []EdgeSample) {
+	merged := make([]EdgeSample, 0, len(t.samples)+len(backfilled))
+	for _, s := range t.samples {
+		if s.Timestamp.Before(from) || s.Timestamp.After(to) {
+			merged = append(merged, s)
+		}
+	}
+	merged = append(merged, backfilled...)
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].Timestamp.Before(merged[j].Timestamp) })
+	if len(merged) > maxEdgeSamples {
+		merged = merged[len(merged)-maxEdgeSamples:]
+	}
+	t.samples = merged
+}
+
+// EdgeSlope returns the least-squares slope of edge percentage over time in
+// percentage points per minute. Fewer than two samples yield zero.
+func EdgeSlope(samples []EdgeSample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	t0 := samples[0].Timestamp
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.Timestamp.Sub(t0).Minutes()
+		sumX += x
+		sumY += s.EdgePctTurn
+		sumXY += x * s.EdgePctTurn
+		sumXX += x * x
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
+// ClassifyTrend maps a slope to widening, collapsing or flat
+func ClassifyTrend(slopePctPerMin float64) string {
+	switch {
+	case slopePctPerMin > trendFlatPctPerMin:
+		return TrendWidening
+	case slopePctPerMin < -trendFlatPctPerMin:
+		return TrendCollapsing
+	default:
+		return TrendFlat
+	}
+}
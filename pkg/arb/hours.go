@@ -0,0 +1,70 @@
+package arb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/catalog"
+)
+
+// StatusVenueClosed is the halt status of a market outside its venue's
+// trading hours
+const StatusVenueClosed = "venue_closed"
+
+// TradingHours tracks which Kalshi series are outside their trading hours.
+// Their quotes stay cached overnight and over holidays but cannot be traded,
+// so pairs on them are excluded like halted ones.
+type TradingHours struct {
+	open func(series string, t time.Time) bool
+
+	mu          sync.Mutex
+	closedSince map[string]time.Time // series -> when first seen closed
+}
+
+// NewTradingHours creates a tracker over open, which reports whether a
+// Kalshi series trades at t
+func NewTradingHours(open func(series string, t time.Time) bool) *TradingHours {
+	return &TradingHours{open: open, closedSince: make(map[string]time.Time)}
+}
+
+// closed returns a halt record for a Kalshi market outside its series'
+// trading hours at now
+func (h *TradingHours) closed(ticker string, now time.Time) (Halt, bool) {
+	if h == nil || ticker == "" {
+		return Halt{}, false
+	}
+	series := catalog.KalshiSeriesTicker(ticker) // Market tickers extend their event's
+	open := h.open(series, now)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if open {
+		delete(h.closedSince, series)
+		return Halt{}, false
+	}
+	since, ok := h.closedSince[series]
+	if !ok {
+		since = now
+		h.closedSince[series] = since
+	}
+	return Halt{Venue: VenueKalshi, Market: ticker, Status: StatusVenueClosed, Since: since}, true
+}
+
+// SetTradingHours installs the Kalshi trading-hours tracker. Pairs whose
+// Kalshi market is outside its series' hours are excluded from opportunities
+// and listed by HaltedPairs as venue closed. Call before Start.
+func (e *Engine) SetTradingHours(h *TradingHours) {
+	e.hours = h
+}
+
+// pairHalts returns the halts and closures affecting either market of a pair
+func (e *Engine) pairHalts(pair MarketPair) []Halt {
+	var halts []Halt
+	if e.halts != nil {
+		halts = e.halts.pairHalts(pair)
+	}
+	if h, ok := e.hours.closed(pair.KalshiTicker, time.Now()); ok {
+		halts = append(halts, h)
+	}
+	return halts
+}
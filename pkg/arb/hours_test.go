@@ -0,0 +1,56 @@
+package arb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestEngineSkipsClosedSeries(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "c1", PMTokenYes: "y1", PMTokenNo: "n1", KalshiTicker: "KXINX-26OCT17-B5800"},
+		{PMConditionID: "c2", PMTokenYes: "y2", PMTokenNo: "n2", KalshiTicker: "KXBTC-26OCT17-B60000"},
+	}
+	e := newTestEngine(t, pairs)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "y1", Ask: 0.40, UpdatedAt: now}, {TokenID: "n1", Ask: 0.62, UpdatedAt: now},
+		{TokenID: "y2", Ask: 0.40, UpdatedAt: now}, {TokenID: "n2", Ask: 0.62, UpdatedAt: now},
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{
+		{Ticker: pairs[0].KalshiTicker, YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now},
+		{Ticker: pairs[1].KalshiTicker, YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now},
+	})
+
+	indexOpen := true
+	e.SetTradingHours(NewTradingHours(func(series string, _ time.Time) bool {
+		return series != "KXINX" || indexOpen
+	}))
+
+	e.computeOpportunities()
+	if got := e.Snapshot().Opportunities; len(got) != 2 {
+		t.Fatalf("got %d opportunities during trading hours, want 2", len(got))
+	}
+
+	indexOpen = false
+	e.computeOpportunities()
+	got := e.Snapshot().Opportunities
+	if len(got) != 1 || got[0].KalshiTicker != pairs[1].KalshiTicker {
+		t.Fatalf("opportunities after the index closed = %+v, want only %s", got, pairs[1].KalshiTicker)
+	}
+	closed := e.HaltedPairs()
+	if len(closed) != 1 || closed[0].KalshiTicker != pairs[0].KalshiTicker ||
+		len(closed[0].Halts) != 1 || closed[0].Halts[0].Status != StatusVenueClosed {
+		t.Fatalf("HaltedPairs = %+v, want %s venue closed", closed, pairs[0].KalshiTicker)
+	}
+	since := closed[0].Halts[0].Since
+	if again := e.HaltedPairs(); !again[0].Halts[0].Since.Equal(since) {
+		t.Errorf("closure since moved from %v to %v", since, again[0].Halts[0].Since)
+	}
+
+	indexOpen = true
+	if got := e.HaltedPairs(); len(got) != 0 {
+		t.Errorf("HaltedPairs after reopening = %+v", got)
+	}
+}
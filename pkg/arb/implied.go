@@ -0,0 +1,103 @@
+package arb
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+)
+
+// PairPrices is one pair's current quotes with vig-free implied
+// probabilities, and what closing a held hedge would fetch at the bids
+type PairPrices struct {
+	PMInstrument     instrument.ID `json:"pm_instrument"`
+	PMNoInstrument   instrument.ID `json:"pm_no_instrument"`
+	KalshiInstrument instrument.ID `json:"kalshi_instrument"`
+	PMTitle          string        `json:"pm_title"`
+	KalshiTicker     string        `json:"kalshi_ticker"`
+	KalshiTitle      string        `json:"kalshi_title"`
+	Category         string        `json:"category"`
+	PMYesAsk         float64       `json:"pm_yes_ask"`
+	PMNoAsk          float64       `json:"pm_no_ask"`
+	KalshiYesAsk     float64       `json:"kalshi_yes_ask"`
+	KalshiNoAsk      float64       `json:"kalshi_no_ask"`
+	PMYesBid         float64       `json:"pm_yes_bid"`
+	PMNoBid          float64       `json:"pm_no_bid"`
+	KalshiYesBid     float64       `json:"kalshi_yes_bid"`
+	KalshiNoBid      float64       `json:"kalshi_no_bid"`
+	PMOverround      float64       `json:"pm_overround"`
+	KalshiOverround  float64       `json:"kalshi_overround"`
+	PMImpliedYes     float64       `json:"pm_implied_yes"`
+	KalshiImpliedYes float64       `json:"kalshi_implied_yes"` // Of the Polymarket question: Kalshi NO for an inverted pair
+	Inverted         bool          `json:"inverted,omitempty"`
+	// Divergence is PMImpliedYes - KalshiImpliedYes: how far the venues
+	// disagree on the outcome once each venue's vig is removed
+	Divergence float64   `json:"divergence"`
+	Halted     bool      `json:"halted"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// Exits is the exit edge of each hedge of the pair with bids on both legs
+	Exits []ExitEdge `json:"exits"`
+}
+
+// Prices returns the current prices of every pair quoted on both venues,
+// ordered by absolute implied-probability divergence, largest first
+func (e *Engine) Prices() []PairPrices {
+	pairs := e.Pairs()
+	out := make([]PairPrices, 0, len(pairs))
+	if !e.kalshiClient.IsQuoting() {
+		return out
+	}
+
+	for _, pair := range pairs {
+		pmYes, ok1 := e.pmClient.GetQuote(pair.PMYes())
+		pmNo, ok2 := e.pmClient.GetQuote(pair.PMNo())
+		k, ok3 := e.kalshiClient.GetQuote(pair.KalshiYes())
+		if !ok1 || !ok2 || !ok3 {
+			continue
+		}
+
+		pmImplied, ok1 := price.ImpliedProbability(pmYes.Ask, pmNo.Ask)
+		kImplied, ok2 := price.ImpliedProbability(k.YesAsk, k.NoAsk)
+		if !ok1 || !ok2 {
+			continue
+		}
+		if pair.Inverted {
+			kImplied = 1 - kImplied
+		}
+
+		pp := PairPrices{
+			PMInstrument:     pair.PMYes(),
+			PMNoInstrument:   pair.PMNo(),
+			KalshiInstrument: pair.KalshiYes(),
+			PMTitle:          pair.PMTitle,
+			KalshiTicker:     pair.KalshiTicker,
+			KalshiTitle:      pair.KalshiTitle,
+			Category:         pair.Category,
+			PMYesAsk:         pmYes.Ask,
+			PMNoAsk:          pmNo.Ask,
+			KalshiYesAsk:     k.YesAsk,
+			KalshiNoAsk:      k.NoAsk,
+			PMYesBid:         pmYes.Bid,
+			PMNoBid:          pmNo.Bid,
+			KalshiYesBid:     k.YesBid,
+			KalshiNoBid:      k.NoBid,
+			PMOverround:      price.Overround(pmYes.Ask, pmNo.Ask),
+			KalshiOverround:  price.Overround(k.YesAsk, k.NoAsk),
+			PMImpliedYes:     pmImplied,
+			KalshiImpliedYes: kImplied,
+			Divergence:       pmImplied - kImplied,
+			Inverted:         pair.Inverted,
+			Halted:           e.halts != nil && len(e.halts.pairHalts(pair)) > 0,
+			UpdatedAt:        latest(latest(pmYes.UpdatedAt, pmNo.UpdatedAt), k.UpdatedAt),
+		}
+		pp.Exits = pp.exits()
+		out = append(out, pp)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return math.Abs(out[i].Divergence) > math.Abs(out[j].Divergence)
+	})
+	return out
+}
@@ -0,0 +1,128 @@
+package arb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// ErrInstrumentNotFound is returned when looking up an instrument that no
+// monitored pair trades
+var ErrInstrumentNotFound = errors.New("instrument not in any monitored pair")
+
+// InstrumentInfo is everything the engine knows about one instrument: its
+// market, the outcome it pays on, its current quote and the pairs it is
+// traded in. Fields a venue's catalog does not report are zero or nil.
+type InstrumentInfo struct {
+	ID       instrument.ID `json:"id"`
+	Venue    string        `json:"venue"`  // VenuePolymarket or VenueKalshi
+	Market   string        `json:"market"` // Polymarket condition ID or Kalshi ticker
+	Title    string        `json:"title"`
+	Outcome  string        `json:"outcome"` // "yes" or "no" of its own market
+	Category string        `json:"category"`
+
+	StrikeType     string     `json:"strike_type,omitempty"` // Kalshi only, see MarketMetadata
+	FloorStrike    *float64   `json:"floor_strike,omitempty"`
+	CapStrike      *float64   `json:"cap_strike,omitempty"`
+	CloseTime      *time.Time `json:"close_time"`
+	ResolutionTime *time.Time `json:"resolution_time"`
+
+	Quote *InstrumentQuote `json:"quote"`          // nil until the venue quotes it
+	Book  *ws.PMOrderBook  `json:"book,omitempty"` // Polymarket depth, once a book arrived
+	Pairs []PairMembership `json:"pairs"`
+}
+
+// InstrumentQuote is an instrument's top of book
+type InstrumentQuote struct {
+	Bid       float64   `json:"bid"`
+	Ask       float64   `json:"ask"`
+	BidSize   float64   `json:"bid_size"` // 0 when unknown, as on Kalshi
+	AskSize   float64   `json:"ask_size"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PairMembership is a pair an instrument is traded in, with the instrument
+// paying on the same outcome across the pair
+type PairMembership struct {
+	PairID      string        `json:"pair_id"` // See MarketPair.ID
+	Counterpart instrument.ID `json:"counterpart"`
+	Title       string        `json:"title"` // The counterpart market's title
+	MatchScore  float64       `json:"match_score"`
+	Inverted    bool          `json:"inverted,omitempty"`
+}
+
+// Instrument resolves a canonical instrument ID to its metadata, from the
+// pairs that trade it
+func (e *Engine) Instrument(id instrument.ID) (InstrumentInfo, error) {
+	info := InstrumentInfo{ID: id, Pairs: make([]PairMembership, 0)}
+	for _, pair := range e.Pairs() {
+		// The instruments on each side of the pair paying on the same outcome
+		sameAsYes, sameAsNo := pair.KalshiYes(), pair.KalshiNo()
+		if pair.Inverted {
+			sameAsYes, sameAsNo = sameAsNo, sameAsYes
+		}
+
+		var counterpart instrument.ID
+		var title string
+		switch id {
+		case pair.PMYes():
+			counterpart, title = sameAsYes, pair.KalshiTitle
+		case pair.PMNo():
+			counterpart, title = sameAsNo, pair.KalshiTitle
+		case sameAsYes:
+			counterpart, title = pair.PMYes(), pair.PMTitle
+		case sameAsNo:
+			counterpart, title = pair.PMNo(), pair.PMTitle
+		default:
+			continue
+		}
+
+		// The market is described by the first pair trading it
+		if len(info.Pairs) == 0 {
+			meta := pair.Metadata()
+			info.Category = pair.Category
+			if id.Venue() == instrument.VenuePolymarket {
+				info.Venue, info.Market, info.Title = VenuePolymarket, pair.PMConditionID, pair.PMTitle
+				info.Outcome = instrument.SideYes
+				if id == pair.PMNo() {
+					info.Outcome = instrument.SideNo
+				}
+				info.CloseTime, info.ResolutionTime = meta.PMCloseTime, meta.PMResolutionTime
+			} else {
+				info.Venue, info.Market, info.Title, info.Outcome = VenueKalshi, pair.KalshiTicker, pair.KalshiTitle, id.Side()
+				info.StrikeType, info.FloorStrike, info.CapStrike = meta.KalshiStrikeType, meta.KalshiFloorStrike, meta.KalshiCapStrike
+				info.CloseTime, info.ResolutionTime = meta.KalshiCloseTime, meta.KalshiResolutionTime
+			}
+		}
+		info.Pairs = append(info.Pairs, PairMembership{
+			PairID:      pair.ID(),
+			Counterpart: counterpart,
+			Title:       title,
+			MatchScore:  pair.MatchScore,
+			Inverted:    pair.Inverted,
+		})
+	}
+	if len(info.Pairs) == 0 {
+		return InstrumentInfo{}, ErrInstrumentNotFound
+	}
+
+	switch info.Venue {
+	case VenuePolymarket:
+		if q, ok := e.pmClient.GetQuote(id); ok {
+			info.Quote = &InstrumentQuote{Bid: q.Bid, Ask: q.Ask, BidSize: q.BidSize, AskSize: q.AskSize, UpdatedAt: q.UpdatedAt}
+		}
+		if book, ok := e.pmClient.GetBook(id); ok {
+			info.Book = &book
+		}
+	case VenueKalshi:
+		if q, ok := e.kalshiClient.GetQuote(instrument.KalshiYes(info.Market)); ok {
+			info.Quote = &InstrumentQuote{Bid: q.YesBid, Ask: q.YesAsk, UpdatedAt: q.UpdatedAt}
+			if info.Outcome == instrument.SideNo {
+				info.Quote.Bid, info.Quote.Ask = q.NoBid, q.NoAsk
+			}
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,56 @@
+package arb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestInstrument(t *testing.T) {
+	floor := 4.25
+	closes := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	pairs := []MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", PMTitle: "Fed above 4.25%?", KalshiTicker: "K1", KalshiTitle: "Fed rate above 4.25%", Category: "economics", MatchScore: 0.9,
+			Meta: MarketMetadata{KalshiStrikeType: "greater", KalshiFloorStrike: &floor, KalshiCloseTime: &closes}},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTokenNo: "N2", PMTitle: "Fed at or below 4.25%?", KalshiTicker: "K1", KalshiTitle: "Fed rate above 4.25%", Inverted: true},
+	}
+	e := newTestEngine(t, pairs)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{{TokenID: "N1", Bid: 0.55, Ask: 0.57, BidSize: 100, AskSize: 80, UpdatedAt: now}})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now}})
+
+	pm, err := e.Instrument(instrument.PMToken("N1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pm.Venue != VenuePolymarket || pm.Market != "C1" || pm.Outcome != "no" || pm.Category != "economics" ||
+		pm.Quote == nil || pm.Quote.Ask != 0.57 || pm.Quote.AskSize != 80 {
+		t.Errorf("unexpected Polymarket instrument %+v", pm)
+	}
+	if len(pm.Pairs) != 1 || pm.Pairs[0].Counterpart != instrument.KalshiNo("K1") || pm.Pairs[0].PairID != pairs[0].ID() {
+		t.Errorf("unexpected pair memberships %+v", pm.Pairs)
+	}
+
+	// Kalshi NO pays with the first pair's NO and the inverted pair's YES
+	k, err := e.Instrument(instrument.KalshiNo("K1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Venue != VenueKalshi || k.Outcome != "no" || k.StrikeType != "greater" || k.FloorStrike == nil || *k.FloorStrike != floor ||
+		k.CloseTime == nil || !k.CloseTime.Equal(closes) || k.Quote == nil || k.Quote.Bid != 0.55 || k.Quote.Ask != 0.57 {
+		t.Errorf("unexpected Kalshi instrument %+v", k)
+	}
+	if len(k.Pairs) != 2 || k.Pairs[0].Counterpart != instrument.PMToken("N1") || k.Pairs[1].Counterpart != instrument.PMToken("Y2") || !k.Pairs[1].Inverted {
+		t.Errorf("unexpected pair memberships %+v", k.Pairs)
+	}
+
+	if info, err := e.Instrument(instrument.PMToken("Y2")); err != nil || info.Quote != nil {
+		t.Errorf("an unquoted instrument still resolves, without a quote: %+v, %v", info, err)
+	}
+	if _, err := e.Instrument(instrument.PMToken("X")); !errors.Is(err, ErrInstrumentNotFound) {
+		t.Errorf("unknown instrument: %v", err)
+	}
+}
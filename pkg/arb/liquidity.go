@@ -0,0 +1,178 @@
+package arb
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LiquiditySnapshot is the latest sampled trading activity of a pair's two
+// markets. A venue's fields are zero until it has been sampled.
+type LiquiditySnapshot struct {
+	PMVolume24h float64    `json:"pm_volume_24h"` // USD
+	PMLiquidity float64    `json:"pm_liquidity"`  // USD resting on the book
+	PMSampledAt *time.Time `json:"pm_sampled_at"`
+
+	KalshiVolume24h    float64    `json:"kalshi_volume_24h"`    // Contracts
+	KalshiOpenInterest float64    `json:"kalshi_open_interest"` // Contracts
+	KalshiLiquidity    float64    `json:"kalshi_liquidity"`     // USD
+	KalshiSampledAt    *time.Time `json:"kalshi_sampled_at"`
+}
+
+// Volume24h returns the thinner venue's 24h volume, counting a Kalshi
+// contract as its $1 notional. A pair trades no better than its thinner side.
+func (s LiquiditySnapshot) Volume24h() float64 {
+	return math.Min(s.PMVolume24h, s.KalshiVolume24h)
+}
+
+// Liquidity returns the thinner venue's liquidity in USD
+func (s LiquiditySnapshot) Liquidity() float64 {
+	return math.Min(s.PMLiquidity, s.KalshiLiquidity)
+}
+
+// pmActivity and kalshiActivity are one venue's sampled market activity
+type pmActivity struct {
+	volume24h, liquidity float64
+	at                   time.Time
+}
+
+type kalshiActivity struct {
+	volume24h, openInterest, liquidity float64
+	at                                 time.Time
+}
+
+// LiquidityBook holds the latest activity samples of monitored markets,
+// refreshed by the market status poller
+type LiquidityBook struct {
+	mu     sync.RWMutex
+	pm     map[string]pmActivity     // condition ID -> activity
+	kalshi map[string]kalshiActivity // ticker -> activity
+}
+
+// NewLiquidityBook creates an empty book
+func NewLiquidityBook() *LiquidityBook {
+	return &LiquidityBook{
+		pm:     make(map[string]pmActivity),
+		kalshi: make(map[string]kalshiActivity),
+	}
+}
+
+// SetPolymarket records a Polymarket market's 24h volume and liquidity in USD
+func (b *LiquidityBook) SetPolymarket(conditionID string, volume24h, liquidity float64, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pm[conditionID] = pmActivity{volume24h: volume24h, liquidity: liquidity, at: at}
+}
+
+// SetKalshi records a Kalshi market's 24h volume and open interest in
+// contracts and its liquidity in USD
+func (b *LiquidityBook) SetKalshi(ticker string, volume24h, openInterest, liquidity float64, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.kalshi[ticker] = kalshiActivity{volume24h: volume24h, openInterest: openInterest, liquidity: liquidity, at: at}
+}
+
+// ForPair returns the pair's snapshot; ok is false until either of its
+// markets has been sampled
+func (b *LiquidityBook) ForPair(pair MarketPair) (s LiquiditySnapshot, ok bool) {
+	if b == nil {
+		return LiquiditySnapshot{}, false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if a, found := b.pm[pair.PMConditionID]; found {
+		at := a.at
+		s.PMVolume24h, s.PMLiquidity, s.PMSampledAt = a.volume24h, a.liquidity, &at
+		ok = true
+	}
+	if a, found := b.kalshi[pair.KalshiTicker]; found {
+		at := a.at
+		s.KalshiVolume24h, s.KalshiOpenInterest, s.KalshiLiquidity, s.KalshiSampledAt = a.volume24h, a.openInterest, a.liquidity, &at
+		ok = true
+	}
+	return s, ok
+}
+
+// PairListing is a monitored pair with its latest liquidity snapshot, nil
+// until sampled
+type PairListing struct {
+	ID string `json:"id"` // See MarketPair.ID
+	MarketPair
+	Snapshot *LiquiditySnapshot `json:"liquidity_snapshot"`
+}
+
+// Listings returns the pairs with their snapshots, in the given order
+func (b *LiquidityBook) Listings(pairs []MarketPair) []PairListing {
+	listings := make([]PairListing, 0, len(pairs))
+	for _, p := range pairs {
+		l := PairListing{ID: p.ID(), MarketPair: p}
+		if s, ok := b.ForPair(p); ok {
+			l.Snapshot = &s
+		}
+		listings = append(listings, l)
+	}
+	return listings
+}
+
+// listingSortKeys maps the sort keys of SortListings to the value compared
+var listingSortKeys = map[string]func(PairListing) float64{
+	"match_score":       func(l PairListing) float64 { return l.MatchScore },
+	"volume_24h":        snapshotValue(LiquiditySnapshot.Volume24h),
+	"liquidity":         snapshotValue(LiquiditySnapshot.Liquidity),
+	"open_interest":     snapshotValue(func(s LiquiditySnapshot) float64 { return s.KalshiOpenInterest }),
+	"pm_volume_24h":     snapshotValue(func(s LiquiditySnapshot) float64 { return s.PMVolume24h }),
+	"pm_liquidity":      snapshotValue(func(s LiquiditySnapshot) float64 { return s.PMLiquidity }),
+	"kalshi_volume_24h": snapshotValue(func(s LiquiditySnapshot) float64 { return s.KalshiVolume24h }),
+	"kalshi_liquidity":  snapshotValue(func(s LiquiditySnapshot) float64 { return s.KalshiLiquidity }),
+}
+
+// snapshotValue reads a snapshot value, 0 for unsampled pairs
+func snapshotValue(fn func(LiquiditySnapshot) float64) func(PairListing) float64 {
+	return func(l PairListing) float64 {
+		if l.Snapshot == nil {
+			return 0
+		}
+		return fn(*l.Snapshot)
+	}
+}
+
+// SortListings sorts listings by a key in descending order: match_score,
+// volume_24h, liquidity, open_interest (Kalshi), or a single venue's figure:
+// pm_volume_24h, pm_liquidity, kalshi_volume_24h or kalshi_liquidity
+func SortListings(listings []PairListing, key string) error {
+	value, ok := listingSortKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown sort key %q", key)
+	}
+	sort.SliceStable(listings, func(i, j int) bool { return value(listings[i]) > value(listings[j]) })
+	return nil
+}
+
+// ListingFloor prunes listings below minimum activity. Pairs not yet
+// sampled fail any non-zero floor.
+type ListingFloor struct {
+	MinVolume24h    float64 // Thinner venue's 24h volume
+	MinLiquidity    float64 // Thinner venue's liquidity, USD
+	MinOpenInterest float64 // Kalshi contracts
+}
+
+// Apply returns the listings that meet the floor
+func (f ListingFloor) Apply(listings []PairListing) []PairListing {
+	if f == (ListingFloor{}) {
+		return listings
+	}
+	kept := listings[:0]
+	for _, l := range listings {
+		if l.Snapshot == nil ||
+			l.Snapshot.Volume24h() < f.MinVolume24h ||
+			l.Snapshot.Liquidity() < f.MinLiquidity ||
+			l.Snapshot.KalshiOpenInterest < f.MinOpenInterest {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
@@ -0,0 +1,46 @@
+package arb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLiquidityListingsSortAndFloor(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "C1", KalshiTicker: "K1", MatchScore: 0.9},
+		{PMConditionID: "C2", KalshiTicker: "K2", MatchScore: 0.8},
+		{PMConditionID: "C3", KalshiTicker: "K3", MatchScore: 0.7}, // Never sampled
+	}
+	now := time.Now()
+	b := NewLiquidityBook()
+	b.SetPolymarket("C1", 5000, 800, now)
+	b.SetKalshi("K1", 200, 1000, 300, now) // Thin on Kalshi
+	b.SetPolymarket("C2", 40000, 6000, now)
+	b.SetKalshi("K2", 30000, 9000, 5000, now)
+
+	listings := b.Listings(pairs)
+	if listings[2].Snapshot != nil {
+		t.Error("unsampled pair should have no snapshot")
+	}
+	if s := listings[0].Snapshot; s == nil || s.Volume24h() != 200 || s.Liquidity() != 300 || s.PMSampledAt == nil {
+		t.Errorf("unexpected snapshot %+v", s)
+	}
+
+	if err := SortListings(listings, "volume_24h"); err != nil {
+		t.Fatal(err)
+	}
+	if listings[0].KalshiTicker != "K2" || listings[2].KalshiTicker != "K3" {
+		t.Errorf("unexpected order %s, %s, %s", listings[0].KalshiTicker, listings[1].KalshiTicker, listings[2].KalshiTicker)
+	}
+	if err := SortListings(listings, "title"); err == nil {
+		t.Error("unknown sort key accepted")
+	}
+
+	if got := (ListingFloor{}).Apply(b.Listings(pairs)); len(got) != 3 {
+		t.Errorf("empty floor pruned to %d", len(got))
+	}
+	got := ListingFloor{MinLiquidity: 500}.Apply(b.Listings(pairs))
+	if len(got) != 1 || got[0].KalshiTicker != "K2" {
+		t.Errorf("expected only K2 above the floor, got %+v", got)
+	}
+}
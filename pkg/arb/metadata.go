@@ -0,0 +1,73 @@
+package arb
+
+import "time"
+
+// MarketMetadata carries the venue details behind an opportunity so consumers
+// can act on it without re-fetching either market. Fields a venue's catalog
+// does not report are zero or nil. Each venue's trading close and expected
+// resolution are reported separately (see match.Deadlines); Polymarket
+// markets usually trade until resolution and report no close.
+type MarketMetadata struct {
+	PMConditionID    string     `json:"pm_condition_id"`
+	PMTokenYes       string     `json:"pm_token_yes"`
+	PMTokenNo        string     `json:"pm_token_no"`
+	PMCloseTime      *time.Time `json:"pm_close_time"`
+	PMResolutionTime *time.Time `json:"pm_resolution_time"`
+
+	KalshiEventTicker  string     `json:"kalshi_event_ticker"`
+	KalshiStrikeType   string     `json:"kalshi_strike_type"` // e.g. "greater", "between"; empty for plain binary markets
+	KalshiFloorStrike  *float64   `json:"kalshi_floor_strike"`
+	KalshiCapStrike    *float64   `json:"kalshi_cap_strike"`
+	KalshiCloseTime    *time.Time `json:"kalshi_close_time"`
+	KalshiLiquidity    float64    `json:"kalshi_liquidity"` // USD
+	KalshiVolume       float64    `json:"kalshi_volume"`    // Contracts traded over the market's life
+	KalshiVolume24h    float64    `json:"kalshi_volume_24h"`
+	KalshiOpenInterest float64    `json:"kalshi_open_interest"`
+
+	KalshiResolutionTime *time.Time `json:"kalshi_resolution_time"`
+
+	// How each market settles, see match.Resolution. Sources are canonical
+	// names of the sources the rules cite, empty when none is recognized.
+	PMOracle                string   `json:"pm_oracle"`
+	PMResolutionSources     []string `json:"pm_resolution_sources"`
+	KalshiOracle            string   `json:"kalshi_oracle"`
+	KalshiResolutionSources []string `json:"kalshi_resolution_sources"`
+}
+
+// Metadata returns the pair's venue metadata, completed with the identifiers
+// the pair itself holds
+func (p MarketPair) Metadata() MarketMetadata {
+	m := p.Meta
+	m.PMConditionID = p.PMConditionID
+	m.PMTokenYes = p.PMTokenYes
+	m.PMTokenNo = p.PMTokenNo
+	if m.KalshiLiquidity == 0 {
+		m.KalshiLiquidity = p.Liquidity
+	}
+	return m
+}
+
+// SettlesAt returns when both markets are expected to have resolved: the
+// later venue's resolution, falling back to its trading close. ok is false
+// unless both venues report one.
+func (m MarketMetadata) SettlesAt() (t time.Time, ok bool) {
+	pm := firstTime(m.PMResolutionTime, m.PMCloseTime)
+	kalshi := firstTime(m.KalshiResolutionTime, m.KalshiCloseTime)
+	if pm == nil || kalshi == nil {
+		return time.Time{}, false
+	}
+	if kalshi.After(*pm) {
+		return *kalshi, true
+	}
+	return *pm, true
+}
+
+// firstTime returns the first non-nil time
+func firstTime(times ...*time.Time) *time.Time {
+	for _, t := range times {
+		if t != nil {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package arb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Near-miss review statuses
+const (
+	NearMissPending   = "pending"
+	NearMissPromoted  = "promoted"
+	NearMissDismissed = "dismissed"
+)
+
+// ErrNearMissNotFound is returned when deciding an unknown near-miss
+var ErrNearMissNotFound = errors.New("near-miss not found")
+
+// NearMiss is a candidate pair whose title similarity fell just below the
+// matching threshold, kept for an operator to promote or dismiss
+type NearMiss struct {
+	ID            string     `json:"id"`
+	PMConditionID string     `json:"pm_condition_id"`
+	PMTokenYes    string     `json:"pm_token_yes"`
+	PMTitle       string     `json:"pm_title"`
+	KalshiTicker  string     `json:"kalshi_ticker"`
+	KalshiTitle   string     `json:"kalshi_title"`
+	Score         float64    `json:"score"`
+	FirstSeen     time.Time  `json:"first_seen"`
+	Status        string     `json:"status"`
+	DecidedBy     string     `json:"decided_by,omitempty"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+
+	pair MarketPair // Full pair for promotion; zero for candidates not seen since restart
+}
+
+// NearMissID derives the stable identifier of a candidate pair
+func NearMissID(pair MarketPair) string {
+	sum := sha1.Sum([]byte(pair.Key()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NearMissRegistry holds near-miss candidates and operator decisions on them
+type NearMissRegistry struct {
+	mu       sync.Mutex
+	items    map[string]*NearMiss
+	onChange func(NearMiss)
+}
+
+// NewNearMissRegistry creates a registry seeded with previously persisted
+// near-misses, so decisions survive restarts
+func NewNearMissRegistry(persisted []NearMiss) *NearMissRegistry {
+	r := &NearMissRegistry{items: make(map[string]*NearMiss, len(persisted))}
+	for i := range persisted {
+		n := persisted[i]
+		r.items[n.ID] = &n
+	}
+	return r
+}
+
+// OnChange installs a callback invoked with every new candidate and every
+// decision, e.g. to persist it. Call before Observe.
+func (r *NearMissRegistry) OnChange(fn func(NearMiss)) {
+	r.onChange = fn
+}
+
+// Observe records a near-miss candidate found during matching. It returns
+// true when an operator has already promoted the pair, in which case it
+// should be monitored like a regular match.
+func (r *NearMissRegistry) Observe(pair MarketPair, now time.Time) (promoted bool) {
+	if r == nil {
+		return false
+	}
+	id := NearMissID(pair)
+
+	r.mu.Lock()
+	n, known := r.items[id]
+	if !known {
+		n = &NearMiss{
+			ID:            id,
+			PMConditionID: pair.PMConditionID,
+			PMTokenYes:    pair.PMTokenYes,
+			PMTitle:       pair.PMTitle,
+			KalshiTicker:  pair.KalshiTicker,
+			KalshiTitle:   pair.KalshiTitle,
+			FirstSeen:     now,
+			Status:        NearMissPending,
+		}
+		r.items[id] = n
+	}
+	n.Score = pair.MatchScore
+	n.pair = pair
+	snapshot := *n
+	r.mu.Unlock()
+
+	if !known && r.onChange != nil {
+		r.onChange(snapshot)
+	}
+	return snapshot.Status == NearMissPromoted
+}
+
+// List returns the near-misses with the given status (all when empty),
+// highest score first
+func (r *NearMissRegistry) List(status string) []NearMiss {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]NearMiss, 0, len(r.items))
+	for _, n := range r.items {
+		if status == "" || n.Status == status {
+			out = append(out, *n)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Get returns a near-miss by ID
+func (r *NearMissRegistry) Get(id string) (NearMiss, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := r.items[id]; ok {
+		return *n, true
+	}
+	return NearMiss{}, false
+}
+
+// Decide records an operator's promotion or dismissal of a near-miss. ok
+// reports whether the returned pair should start being monitored: only on a
+// new promotion of a candidate seen since restart. Others are picked up when
+// the next bootstrap matches them again.
+func (r *NearMissRegistry) Decide(id, status, by string, now time.Time) (n NearMiss, pair MarketPair, ok bool, err error) {
+	if status != NearMissPromoted && status != NearMissDismissed && status != NearMissPending {
+		return NearMiss{}, MarketPair{}, false, fmt.Errorf("invalid status %q: want promoted, dismissed or pending", status)
+	}
+
+	r.mu.Lock()
+	item, found := r.items[id]
+	if !found {
+		r.mu.Unlock()
+		return NearMiss{}, MarketPair{}, false, ErrNearMissNotFound
+	}
+	wasPromoted := item.Status == NearMissPromoted
+	item.Status = status
+	item.DecidedBy = by
+	item.DecidedAt = &now
+	n, pair = *item, item.pair
+	r.mu.Unlock()
+
+	if r.onChange != nil {
+		r.onChange(n)
+	}
+	ok = status == NearMissPromoted && !wasPromoted && pair.KalshiTicker != ""
+	return n, pair, ok, nil
+}
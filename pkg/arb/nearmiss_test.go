@@ -0,0 +1,50 @@
+package arb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNearMissRegistryPromotion(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pair := MarketPair{PMTokenYes: "y", KalshiTicker: "K", PMTitle: "Fed cut?", MatchScore: 0.5}
+
+	var changes []NearMiss
+	r := NewNearMissRegistry(nil)
+	r.OnChange(func(n NearMiss) { changes = append(changes, n) })
+
+	if r.Observe(pair, now) {
+		t.Fatal("a new candidate must not be promoted")
+	}
+	r.Observe(pair, now.Add(time.Minute)) // Seen again: no new change
+	if len(changes) != 1 || changes[0].Status != NearMissPending {
+		t.Fatalf("expected one pending change, got %+v", changes)
+	}
+
+	id := NearMissID(pair)
+	n, got, activate, err := r.Decide(id, NearMissPromoted, "key:aaaa", now)
+	if err != nil || !activate || got.KalshiTicker != "K" || n.DecidedBy != "key:aaaa" {
+		t.Fatalf("Decide = %+v, %+v, %v, %v", n, got, activate, err)
+	}
+	if _, _, activate, _ := r.Decide(id, NearMissPromoted, "key:aaaa", now); activate {
+		t.Error("promoting twice must not activate the pair again")
+	}
+
+	// After a restart the persisted decision promotes the pair at bootstrap
+	restarted := NewNearMissRegistry(r.List(""))
+	if !restarted.Observe(pair, now.Add(time.Hour)) {
+		t.Error("expected a persisted promotion to be honoured")
+	}
+}
+
+func TestNearMissRegistryDecideErrors(t *testing.T) {
+	r := NewNearMissRegistry(nil)
+	if _, _, _, err := r.Decide("missing", NearMissDismissed, "", time.Now()); !errors.Is(err, ErrNearMissNotFound) {
+		t.Errorf("expected ErrNearMissNotFound, got %v", err)
+	}
+	r.Observe(MarketPair{PMTokenYes: "y", KalshiTicker: "K"}, time.Now())
+	if _, _, _, err := r.Decide(NearMissID(MarketPair{PMTokenYes: "y", KalshiTicker: "K"}), "maybe", "", time.Now()); err == nil {
+		t.Error("expected an invalid status error")
+	}
+}
@@ -0,0 +1,137 @@
+package arb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// NewsHold is a pair held out of opportunities because one of its markets
+// just moved sharply. Around news one venue reprices before the other, and
+// the apparent edge is usually an unfillable quote or a resolution mismatch.
+type NewsHold struct {
+	PMTitle      string    `json:"pm_title"`
+	KalshiTicker string    `json:"kalshi_ticker"`
+	KalshiTitle  string    `json:"kalshi_title"`
+	Venue        string    `json:"venue"`   // Venue with the larger move
+	MovePP       float64   `json:"move_pp"` // Implied YES probability range within the window, percentage points
+	Since        time.Time `json:"since"`
+}
+
+// probSample is one cycle's implied YES probability on each venue
+type probSample struct {
+	at         time.Time
+	pm, kalshi float64
+}
+
+// NewsWindow holds back pairs whose implied probability on either venue
+// moved by more than a threshold within a trailing window. A pair is
+// released once the move has aged out of the window.
+type NewsWindow struct {
+	movePP float64 // Percentage points
+	window time.Duration
+
+	mu      sync.Mutex
+	samples map[string][]probSample // pair key -> samples within the window
+	held    map[string]NewsHold     // pair key -> hold
+}
+
+// NewNewsWindow creates a window holding pairs whose implied probability
+// moved more than movePP percentage points within window
+func NewNewsWindow(movePP float64, window time.Duration) *NewsWindow {
+	return &NewsWindow{
+		movePP:  movePP,
+		window:  window,
+		samples: make(map[string][]probSample),
+		held:    make(map[string]NewsHold),
+	}
+}
+
+// Screen records each state's implied probabilities and returns the states
+// of pairs not held. Safe on a nil window, which holds nothing.
+func (w *NewsWindow) Screen(states []PairState, now time.Time) []PairState {
+	if w == nil {
+		return states
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := now.Add(-w.window)
+	kept := states[:0]
+	for _, s := range states {
+		key := s.Pair.Key()
+		samples := w.samples[key]
+		i := 0
+		for i < len(samples) && samples[i].at.Before(cutoff) {
+			i++
+		}
+		samples = append(samples[i:], probSample{at: now, pm: s.PMImpliedYes, kalshi: s.KalshiImpliedYes})
+		w.samples[key] = samples
+
+		venue, move := VenuePolymarket, probRange(samples, func(p probSample) float64 { return p.pm })
+		if k := probRange(samples, func(p probSample) float64 { return p.kalshi }); k > move {
+			venue, move = VenueKalshi, k
+		}
+		move *= 100
+
+		if move <= w.movePP {
+			delete(w.held, key)
+			kept = append(kept, s)
+			continue
+		}
+		hold, ok := w.held[key]
+		if !ok {
+			hold = NewsHold{PMTitle: s.Pair.PMTitle, KalshiTicker: s.Pair.KalshiTicker, KalshiTitle: s.Pair.KalshiTitle, Since: now}
+		}
+		hold.Venue, hold.MovePP = venue, move
+		w.held[key] = hold
+	}
+	// Forget pairs no longer quoted
+	for key, samples := range w.samples {
+		if samples[len(samples)-1].at.Before(cutoff) {
+			delete(w.samples, key)
+			delete(w.held, key)
+		}
+	}
+	metrics.SetNewsHeldPairs(len(w.held))
+	return kept
+}
+
+// probRange returns the spread between the largest and smallest value
+func probRange(samples []probSample, value func(probSample) float64) float64 {
+	lo, hi := value(samples[0]), value(samples[0])
+	for _, s := range samples[1:] {
+		v := value(s)
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return hi - lo
+}
+
+// Held returns the pairs currently held, by Kalshi ticker
+func (w *NewsWindow) Held() []NewsHold {
+	out := make([]NewsHold, 0)
+	if w == nil {
+		return out
+	}
+	w.mu.Lock()
+	for _, h := range w.held {
+		out = append(out, h)
+	}
+	w.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].KalshiTicker < out[j].KalshiTicker })
+	return out
+}
+
+// SetNewsWindow installs the news window. Pairs it holds are excluded from
+// opportunities, and so from alerts and execution. Call before Start.
+func (e *Engine) SetNewsWindow(w *NewsWindow) {
+	e.news = w
+}
+
+// NewsHeldPairs returns the pairs held by the news window
+func (e *Engine) NewsHeldPairs() []NewsHold {
+	return e.news.Held()
+}
@@ -0,0 +1,44 @@
+package arb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewsWindowHoldsSharpMoves(t *testing.T) {
+	w := NewNewsWindow(5, time.Minute)
+	a := MarketPair{PMTokenYes: "Y1", KalshiTicker: "K1"}
+	b := MarketPair{PMTokenYes: "Y2", KalshiTicker: "K2"}
+	state := func(p MarketPair, pm, kalshi float64) PairState {
+		return PairState{Pair: p, PMImpliedYes: pm, KalshiImpliedYes: kalshi}
+	}
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got := w.Screen([]PairState{state(a, 0.40, 0.41), state(b, 0.60, 0.60)}, start); len(got) != 2 {
+		t.Fatalf("expected both pairs, got %d", len(got))
+	}
+
+	// Kalshi reprices K1 by 8 points; K2 drifts by 3
+	got := w.Screen([]PairState{state(a, 0.40, 0.49), state(b, 0.60, 0.63)}, start.Add(10*time.Second))
+	if len(got) != 1 || got[0].Pair.KalshiTicker != "K2" {
+		t.Fatalf("expected only K2 to pass, got %+v", got)
+	}
+	held := w.Held()
+	if len(held) != 1 || held[0].KalshiTicker != "K1" || held[0].Venue != VenueKalshi || held[0].MovePP < 7.99 || held[0].MovePP > 8.01 {
+		t.Fatalf("unexpected holds %+v", held)
+	}
+
+	// Still held while the move is inside the window
+	if got := w.Screen([]PairState{state(a, 0.49, 0.49)}, start.Add(50*time.Second)); len(got) != 0 {
+		t.Error("K1 released while the move is within the window")
+	}
+	// Released once the pre-move samples age out
+	if got := w.Screen([]PairState{state(a, 0.49, 0.49)}, start.Add(2*time.Minute)); len(got) != 1 || len(w.Held()) != 0 {
+		t.Errorf("K1 not released after the window, holds %+v", w.Held())
+	}
+
+	var none *NewsWindow
+	if got := none.Screen([]PairState{state(a, 0, 1)}, start); len(got) != 1 || len(none.Held()) != 0 {
+		t.Error("a nil window must hold nothing")
+	}
+}
@@ -0,0 +1,112 @@
+package arb
+
+import (
+	"sync"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+)
+
+// PMMarketRules are a Polymarket market's order constraints, as its CLOB
+// market reports them
+type PMMarketRules struct {
+	TickSize     float64 `json:"tick_size"`      // Price increment, 0.01 or 0.001
+	MinOrderSize float64 `json:"min_order_size"` // Shares, 0 when unknown
+	NegRisk      bool    `json:"neg_risk"`       // Orders go through the neg-risk exchange
+}
+
+// PMRules returns the pair's Polymarket order constraints
+func (p MarketPair) PMRules() PMMarketRules {
+	return PMMarketRules{TickSize: p.PMTick(), MinOrderSize: p.PMMinOrderSize, NegRisk: p.PMNegRisk}
+}
+
+// withPMRules returns the pair with its Polymarket order constraints replaced
+func (p MarketPair) withPMRules(r PMMarketRules) MarketPair {
+	p.PMTickSize, p.PMMinOrderSize, p.PMNegRisk = r.TickSize, r.MinOrderSize, r.NegRisk
+	return p
+}
+
+// PMRulesCache holds the latest order constraints of monitored Polymarket
+// markets, keyed by condition ID. Pairs carry the constraints their market
+// had when matched; the cache keeps them fresh from status polls and tick
+// size changes pushed over the market channel.
+type PMRulesCache struct {
+	mu    sync.RWMutex
+	rules map[string]PMMarketRules
+}
+
+// NewPMRulesCache creates a cache seeded with the given pairs' constraints
+func NewPMRulesCache(pairs []MarketPair) *PMRulesCache {
+	c := &PMRulesCache{rules: make(map[string]PMMarketRules)}
+	c.AddPairs(pairs)
+	return c
+}
+
+// AddPairs seeds the constraints of pairs' markets not cached yet
+func (c *PMRulesCache) AddPairs(pairs []MarketPair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, pair := range pairs {
+		if _, ok := c.rules[pair.PMConditionID]; !ok && pair.PMConditionID != "" {
+			c.rules[pair.PMConditionID] = pair.PMRules()
+		}
+	}
+}
+
+// Set records a market's constraints. It returns true when they changed.
+// A tick size of 0 or less means price.DefaultPolymarketTick.
+func (c *PMRulesCache) Set(conditionID string, r PMMarketRules) bool {
+	if r.TickSize <= 0 {
+		r.TickSize = price.DefaultPolymarketTick
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.rules[conditionID]; ok && old == r {
+		return false
+	}
+	c.rules[conditionID] = r
+	return true
+}
+
+// SetTickSize records a market's new tick size, keeping its other
+// constraints. It returns true when the tick size changed; ticks of 0 or
+// less are ignored.
+func (c *PMRulesCache) SetTickSize(conditionID string, tick float64) bool {
+	if tick <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r := c.rules[conditionID]
+	if r.TickSize == tick {
+		return false
+	}
+	r.TickSize = tick
+	c.rules[conditionID] = r
+	return true
+}
+
+// Get returns a market's cached constraints
+func (c *PMRulesCache) Get(conditionID string) (PMMarketRules, bool) {
+	if c == nil {
+		return PMMarketRules{}, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	r, ok := c.rules[conditionID]
+	return r, ok
+}
+
+// Apply returns the pair with its market's cached constraints, unchanged
+// when the market is not cached
+func (c *PMRulesCache) Apply(pair MarketPair) MarketPair {
+	if r, ok := c.Get(pair.PMConditionID); ok {
+		return pair.withPMRules(r)
+	}
+	return pair
+}
+
+// SetPMRules installs the cache of Polymarket order constraints, which
+// override the ones pairs were matched with. Call before Start.
+func (e *Engine) SetPMRules(c *PMRulesCache) {
+	e.pmRules = c
+}
@@ -0,0 +1,42 @@
+package arb
+
+import "testing"
+
+func TestPMRulesCache(t *testing.T) {
+	pair := MarketPair{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1", PMMinOrderSize: 5}
+	cache := NewPMRulesCache([]MarketPair{pair})
+	if r, ok := cache.Get("C1"); !ok || r.TickSize != 0.01 || r.MinOrderSize != 5 {
+		t.Fatalf("seeded rules = %+v, %v; want the default tick and the pair's minimum", r, ok)
+	}
+
+	if !cache.SetTickSize("C1", 0.001) || cache.SetTickSize("C1", 0.001) {
+		t.Error("SetTickSize must report only changes")
+	}
+	if got := cache.Apply(pair); got.PMTick() != 0.001 || got.PMMinOrderSize != 5 {
+		t.Errorf("Apply = tick %v, min %v; want 0.001, 5", got.PMTick(), got.PMMinOrderSize)
+	}
+
+	if !cache.Set("C1", PMMarketRules{TickSize: 0.01, MinOrderSize: 15, NegRisk: true}) {
+		t.Error("Set must report a change")
+	}
+	if got := cache.Apply(pair); got.PMTick() != 0.01 || got.PMMinOrderSize != 15 || !got.PMNegRisk {
+		t.Errorf("Apply = %+v", got.PMRules())
+	}
+	if got := cache.Apply(MarketPair{PMConditionID: "C2"}); got.PMRules() != (PMMarketRules{TickSize: 0.01}) {
+		t.Error("uncached markets must be left alone")
+	}
+}
+
+func TestStrategiesEnforceMinOrderSize(t *testing.T) {
+	// 11.1% on turnover with 10 PM YES shares at the ask, see TestArbitrageStrategy
+	state := testPairState(0.40, 0.62, 0.50, 0.52)
+	state.Pair.PMMinOrderSize = 15
+	if opps := (arbitrageStrategy{threshold: NewThreshold(3)}).Evaluate(state); len(opps) != 0 {
+		t.Errorf("expected nothing below the minimum order size, got %+v", opps)
+	}
+
+	state.Pair.PMMinOrderSize = 5
+	if opps := (arbitrageStrategy{threshold: NewThreshold(3)}).Evaluate(state); len(opps) != 1 {
+		t.Errorf("expected 1 opportunity above the minimum order size, got %+v", opps)
+	}
+}
@@ -0,0 +1,207 @@
+package arb
+
+import (
+	_ "embed"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// OpportunityProto is the protobuf schema of the Opportunity payload
+//
+//go:embed schema/opportunity.proto
+var OpportunityProto []byte
+
+// MarshalOpportunitiesProto encodes opps as an arbws.v1.OpportunityList
+// message (see schema/opportunity.proto). Zero values are omitted, as proto3
+// encoders do.
+func MarshalOpportunitiesProto(opps []Opportunity) []byte {
+	var b []byte
+	for i := range opps {
+		b = appendMessage(b, 1, marshalOpportunityProto(&opps[i]))
+	}
+	return b
+}
+
+// marshalOpportunityProto encodes one arbws.v1.Opportunity message
+func marshalOpportunityProto(o *Opportunity) []byte {
+	var b []byte
+	b = appendString(b, 1, o.ID)
+	if o.SchemaVersion != 0 {
+		b = protowire.AppendTag(b, 2, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(int64(o.SchemaVersion)))
+	}
+	b = appendTimestamp(b, 3, o.Timestamp)
+	b = appendTimestamp(b, 4, o.FirstSeen)
+	b = appendString(b, 5, o.Category)
+	b = appendString(b, 6, o.Combo)
+	b = appendDouble(b, 7, o.EdgeAbs)
+	b = appendDouble(b, 8, o.EdgePctTurn)
+	b = appendString(b, 9, o.PMTitle)
+	b = appendDouble(b, 10, o.PMYesAsk)
+	b = appendDouble(b, 11, o.PMNoAsk)
+	b = appendString(b, 12, o.KalshiTicker)
+	b = appendString(b, 13, o.KalshiTitle)
+	b = appendDouble(b, 14, o.KalshiYesBid)
+	b = appendDouble(b, 15, o.KalshiYesAsk)
+	b = appendDouble(b, 16, o.KalshiNoBid)
+	b = appendDouble(b, 17, o.KalshiNoAsk)
+	b = appendDouble(b, 18, o.TotalCost)
+	b = appendString(b, 19, string(o.PMInstrument))
+	b = appendString(b, 20, string(o.KalshiInstrument))
+	b = appendDouble(b, 21, o.MaxSize)
+	b = appendDouble(b, 22, o.Liquidity)
+	b = appendDouble(b, 23, o.MatchScore)
+	b = appendTimestamp(b, 24, o.PMQuoteTime)
+	b = appendTimestamp(b, 25, o.KalshiQuoteTime)
+	b = appendDouble(b, 26, o.Confidence)
+	b = appendDouble(b, 27, o.PMImpliedYes)
+	b = appendDouble(b, 28, o.KalshiImpliedYes)
+	b = appendDouble(b, 29, o.ImpliedDivergence)
+	if o.Ack != nil {
+		var a []byte
+		a = appendString(a, 1, o.Ack.OppID)
+		a = appendTimestamp(a, 2, o.Ack.FirstSeen)
+		a = appendString(a, 3, o.Ack.Status)
+		a = appendString(a, 4, o.Ack.Reason)
+		a = appendString(a, 5, o.Ack.By)
+		a = appendTimestamp(a, 6, o.Ack.At)
+		b = appendMessage(b, 30, a)
+	}
+
+	m := &o.Market
+	var md []byte
+	md = appendString(md, 1, m.PMConditionID)
+	md = appendString(md, 2, m.PMTokenYes)
+	md = appendString(md, 3, m.PMTokenNo)
+	md = appendTimestampPtr(md, 4, m.PMCloseTime)
+	md = appendString(md, 5, m.KalshiEventTicker)
+	md = appendString(md, 6, m.KalshiStrikeType)
+	md = appendOptionalDouble(md, 7, m.KalshiFloorStrike)
+	md = appendOptionalDouble(md, 8, m.KalshiCapStrike)
+	md = appendTimestampPtr(md, 9, m.KalshiCloseTime)
+	md = appendDouble(md, 10, m.KalshiLiquidity)
+	md = appendDouble(md, 11, m.KalshiVolume)
+	md = appendDouble(md, 12, m.KalshiVolume24h)
+	md = appendDouble(md, 13, m.KalshiOpenInterest)
+	md = appendTimestampPtr(md, 14, m.PMResolutionTime)
+	md = appendTimestampPtr(md, 15, m.KalshiResolutionTime)
+	md = appendString(md, 16, m.PMOracle)
+	for _, s := range m.PMResolutionSources {
+		md = protowire.AppendTag(md, 17, protowire.BytesType)
+		md = protowire.AppendString(md, s)
+	}
+	md = appendString(md, 18, m.KalshiOracle)
+	for _, s := range m.KalshiResolutionSources {
+		md = protowire.AppendTag(md, 19, protowire.BytesType)
+		md = protowire.AppendString(md, s)
+	}
+	if len(md) > 0 {
+		b = appendMessage(b, 31, md)
+	}
+	b = appendString(b, 32, o.Strategy)
+	if o.Annotation != nil {
+		var a []byte
+		a = appendString(a, 1, o.Annotation.PMConditionID)
+		a = appendString(a, 2, o.Annotation.KalshiTicker)
+		if o.Annotation.Verified {
+			a = protowire.AppendTag(a, 3, protowire.VarintType)
+			a = protowire.AppendVarint(a, 1)
+		}
+		a = appendString(a, 4, o.Annotation.Note)
+		a = appendString(a, 5, o.Annotation.By)
+		a = appendTimestamp(a, 6, o.Annotation.At)
+		for _, tag := range o.Annotation.Tags {
+			a = protowire.AppendTag(a, 7, protowire.BytesType)
+			a = protowire.AppendString(a, tag)
+		}
+		b = appendMessage(b, 33, a)
+	}
+	if o.ResolutionSourceDiffers {
+		b = protowire.AppendTag(b, 34, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	b = appendTimestampPtr(b, 35, o.ExpiresAt)
+	if o.Expired {
+		b = protowire.AppendTag(b, 36, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	if c := o.Capital; c != nil {
+		var cb []byte
+		cb = appendDouble(cb, 1, c.Contracts)
+		cb = appendMessage(cb, 2, marshalLegCapitalProto(c.PM))
+		cb = appendMessage(cb, 3, marshalLegCapitalProto(c.Kalshi))
+		cb = appendDouble(cb, 4, c.Required)
+		cb = appendDouble(cb, 5, c.Payout)
+		cb = appendDouble(cb, 6, c.NetEdge)
+		cb = appendDouble(cb, 7, c.ReturnOnCapital)
+		b = appendMessage(b, 37, cb)
+	}
+	b = appendOptionalDouble(b, 38, o.AnnualizedReturn)
+	b = appendDouble(b, 39, o.Fees)
+	return b
+}
+
+// marshalLegCapitalProto encodes one arbws.v1.LegCapital message
+func marshalLegCapitalProto(l LegCapital) []byte {
+	var b []byte
+	b = appendDouble(b, 1, l.Price)
+	b = appendDouble(b, 2, l.Cost)
+	b = appendDouble(b, 3, l.Fee)
+	return appendDouble(b, 4, l.Collateral)
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+// appendOptionalDouble encodes an optional double, present whenever v is set
+func appendOptionalDouble(b []byte, num protowire.Number, v *float64) []byte {
+	if v == nil {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(*v))
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+// appendTimestamp encodes t as a google.protobuf.Timestamp, omitting the zero time
+func appendTimestamp(b []byte, num protowire.Number, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	var ts []byte
+	if secs := t.Unix(); secs != 0 {
+		ts = protowire.AppendTag(ts, 1, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(secs))
+	}
+	if nanos := t.Nanosecond(); nanos != 0 {
+		ts = protowire.AppendTag(ts, 2, protowire.VarintType)
+		ts = protowire.AppendVarint(ts, uint64(nanos))
+	}
+	return appendMessage(b, num, ts)
+}
+
+func appendTimestampPtr(b []byte, num protowire.Number, t *time.Time) []byte {
+	if t == nil {
+		return b
+	}
+	return appendTimestamp(b, num, *t)
+}
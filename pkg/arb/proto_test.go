@@ -0,0 +1,136 @@
+package arb
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TestOpportunityProtoMatchesStruct keeps the protobuf schema in step with
+// the Opportunity struct, like TestOpportunitySchemaMatchesStruct does for JSON
+func TestOpportunityProtoMatchesStruct(t *testing.T) {
+	body := string(OpportunityProto)
+	start := strings.Index(body, "message Opportunity {")
+	end := strings.Index(body[start:], "}")
+	if start < 0 || end < 0 {
+		t.Fatal("Opportunity message not found")
+	}
+
+	var protoFields []string
+	for _, m := range regexp.MustCompile(`(?m)^\s+(?:optional )?\S+ (\w+) = \d+;`).FindAllStringSubmatch(body[start:start+end], -1) {
+		protoFields = append(protoFields, m[1])
+	}
+
+	var fields []string
+	typ := reflect.TypeOf(Opportunity{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+
+	sort.Strings(fields)
+	sort.Strings(protoFields)
+	if !reflect.DeepEqual(fields, protoFields) {
+		t.Errorf("proto fields %v do not match struct fields %v", protoFields, fields)
+	}
+}
+
+// protoFields decodes one message level into field number -> raw values
+func protoFields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	t.Helper()
+	out := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("bad tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			t.Fatalf("bad field %d: %v", num, protowire.ParseError(m))
+		}
+		value := b[:m]
+		if typ == protowire.BytesType {
+			value, _ = protowire.ConsumeBytes(b)
+		}
+		out[num] = append(out[num], value)
+		b = b[m:]
+	}
+	return out
+}
+
+func TestMarshalOpportunitiesProto(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 500, time.UTC)
+	floor := 0.0
+	opps := []Opportunity{
+		{ID: "a", SchemaVersion: 1, Timestamp: ts, EdgePctTurn: 4.5, Ack: &Acknowledgment{OppID: "a", Status: AckClaimed, At: ts},
+			Market: MarketMetadata{KalshiEventTicker: "EV-1", KalshiFloorStrike: &floor, KalshiCloseTime: &ts,
+				KalshiResolutionSources: []string{"ap", "fox_news"}},
+			Annotation: &Annotation{KalshiTicker: "K1", Verified: true, Note: "same source"}, ResolutionSourceDiffers: true,
+			Capital: newCapital(10, 0.40, 0.57)},
+		{ID: "b"},
+	}
+
+	list := protoFields(t, MarshalOpportunitiesProto(opps))
+	if len(list[1]) != 2 {
+		t.Fatalf("expected 2 opportunities, got %d", len(list[1]))
+	}
+
+	first := protoFields(t, list[1][0])
+	if string(first[1][0]) != "a" {
+		t.Errorf("id = %q", first[1][0])
+	}
+	if v, _ := protowire.ConsumeFixed64(first[8][0]); math.Float64frombits(v) != 4.5 {
+		t.Errorf("edge_pct_turn = %v", math.Float64frombits(v))
+	}
+
+	var got timestamppb.Timestamp
+	if err := proto.Unmarshal(first[3][0], &got); err != nil || !got.AsTime().Equal(ts) {
+		t.Errorf("timestamp = %v, %v", got.AsTime(), err)
+	}
+
+	ack := protoFields(t, first[30][0])
+	if string(ack[3][0]) != AckClaimed {
+		t.Errorf("ack status = %q", ack[3][0])
+	}
+
+	market := protoFields(t, first[31][0])
+	if string(market[5][0]) != "EV-1" || len(market[9]) != 1 {
+		t.Errorf("unexpected market metadata %v", market)
+	}
+	if len(market[7]) != 1 || len(market[8]) != 0 {
+		t.Error("a zero strike must be encoded and a missing one omitted")
+	}
+	if len(market[19]) != 2 || string(market[19][1]) != "fox_news" {
+		t.Errorf("kalshi_resolution_sources = %q", market[19])
+	}
+	if v, _ := protowire.ConsumeVarint(first[34][0]); v != 1 {
+		t.Error("resolution_source_differs not encoded")
+	}
+
+	annotation := protoFields(t, first[33][0])
+	if v, _ := protowire.ConsumeVarint(annotation[3][0]); v != 1 || string(annotation[4][0]) != "same source" || len(annotation[6]) != 0 {
+		t.Errorf("unexpected annotation %v", annotation)
+	}
+
+	capital := protoFields(t, first[37][0])
+	kalshi := protoFields(t, capital[3][0])
+	if v, _ := protowire.ConsumeFixed64(kalshi[2][0]); math.Abs(math.Float64frombits(v)-5.7) > 1e-9 || len(kalshi[3]) != 0 {
+		t.Errorf("unexpected kalshi leg capital %v", kalshi)
+	}
+
+	second := protoFields(t, list[1][1])
+	if len(second) != 1 {
+		t.Errorf("zero fields must be omitted, got %d fields", len(second))
+	}
+}
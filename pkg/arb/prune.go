@@ -0,0 +1,121 @@
+package arb
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SubscriptionLimits caps the WebSocket subscriptions the monitored pairs
+// may take on each venue: Polymarket tokens, two per pair, and Kalshi
+// tickers. Pairs sharing a market share its subscription. Zero is unlimited.
+type SubscriptionLimits struct {
+	Polymarket int
+	Kalshi     int
+}
+
+// PrunedPair is a matched pair left unmonitored because its markets did not
+// fit in a venue's subscription limit
+type PrunedPair struct {
+	MarketPair
+	Venue    string    `json:"venue"` // The venue whose limit it would exceed
+	PrunedAt time.Time `json:"pruned_at"`
+}
+
+// SubscriptionPruner keeps the monitored pair set within the venues'
+// subscription limits. Rather than failing to subscribe, or silently
+// missing markets past the limit, it admits the best pairs that fit and
+// records the rest.
+type SubscriptionPruner struct {
+	limits SubscriptionLimits
+
+	mu            sync.Mutex
+	pmTokens      map[string]struct{} // Subscribed by admitted pairs
+	kalshiTickers map[string]struct{}
+	pruned        map[string]PrunedPair // Pair key -> pruned pair
+}
+
+// NewSubscriptionPruner creates a pruner for limits
+func NewSubscriptionPruner(limits SubscriptionLimits) *SubscriptionPruner {
+	return &SubscriptionPruner{
+		limits:        limits,
+		pmTokens:      make(map[string]struct{}),
+		kalshiTickers: make(map[string]struct{}),
+		pruned:        make(map[string]PrunedPair),
+	}
+}
+
+// Admit returns the pairs to monitor out of a batch of newly matched ones:
+// best first, by liquidity and then match score, each whose markets still
+// fit alongside those admitted before. The others are returned, and listed
+// by Pruned, in the same order; a smaller pair past one that did not fit may
+// still be admitted.
+func (p *SubscriptionPruner) Admit(pairs []MarketPair) (admitted []MarketPair, pruned []PrunedPair) {
+	ranked := append([]MarketPair(nil), pairs...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Liquidity != b.Liquidity {
+			return a.Liquidity > b.Liquidity
+		}
+		return a.MatchScore > b.MatchScore
+	})
+
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, pair := range ranked {
+		var newTokens []string
+		for _, token := range []string{pair.PMTokenYes, pair.PMTokenNo} {
+			if _, ok := p.pmTokens[token]; !ok && !slices.Contains(newTokens, token) {
+				newTokens = append(newTokens, token)
+			}
+		}
+		_, subscribed := p.kalshiTickers[pair.KalshiTicker]
+
+		venue := ""
+		switch {
+		case p.limits.Polymarket > 0 && len(p.pmTokens)+len(newTokens) > p.limits.Polymarket:
+			venue = VenuePolymarket
+		case p.limits.Kalshi > 0 && !subscribed && len(p.kalshiTickers)+1 > p.limits.Kalshi:
+			venue = VenueKalshi
+		}
+		if venue != "" {
+			pp := PrunedPair{MarketPair: pair, Venue: venue, PrunedAt: now}
+			p.pruned[pair.Key()] = pp
+			pruned = append(pruned, pp)
+			continue
+		}
+
+		for _, token := range newTokens {
+			p.pmTokens[token] = struct{}{}
+		}
+		p.kalshiTickers[pair.KalshiTicker] = struct{}{}
+		delete(p.pruned, pair.Key())
+		admitted = append(admitted, pair)
+	}
+	return admitted, pruned
+}
+
+// Pruned returns the pairs left unmonitored, best first
+func (p *SubscriptionPruner) Pruned() []PrunedPair {
+	p.mu.Lock()
+	out := make([]PrunedPair, 0, len(p.pruned))
+	for _, pp := range p.pruned {
+		out = append(out, pp)
+	}
+	p.mu.Unlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch {
+		case a.Liquidity != b.Liquidity:
+			return a.Liquidity > b.Liquidity
+		case a.MatchScore != b.MatchScore:
+			return a.MatchScore > b.MatchScore
+		}
+		return a.Key() < b.Key()
+	})
+	return out
+}
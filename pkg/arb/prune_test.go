@@ -0,0 +1,45 @@
+package arb
+
+import "testing"
+
+func TestSubscriptionPruner(t *testing.T) {
+	pair := func(pmYes, pmNo, ticker string, liquidity, score float64) MarketPair {
+		return MarketPair{PMTokenYes: pmYes, PMTokenNo: pmNo, KalshiTicker: ticker, Liquidity: liquidity, MatchScore: score}
+	}
+	p := NewSubscriptionPruner(SubscriptionLimits{Polymarket: 4, Kalshi: 3})
+
+	admitted, pruned := p.Admit([]MarketPair{
+		pair("c-yes", "c-no", "K3", 100, 0.9),
+		pair("a-yes", "a-no", "K1", 5000, 0.8),
+		pair("b-yes", "b-no", "K2", 5000, 0.95), // Best: equal liquidity, higher score
+		pair("a-yes", "a-no", "K4", 10, 0.7),    // Shares a's tokens
+	})
+	if len(admitted) != 3 || admitted[0].KalshiTicker != "K2" || admitted[1].KalshiTicker != "K1" || admitted[2].KalshiTicker != "K4" {
+		t.Fatalf("unexpected admitted pairs %+v", admitted)
+	}
+	if len(pruned) != 1 || pruned[0].KalshiTicker != "K3" || pruned[0].Venue != VenuePolymarket {
+		t.Fatalf("unexpected pruned pairs %+v", pruned)
+	}
+
+	// A later batch fits only what the earlier pairs left room for
+	admitted, pruned = p.Admit([]MarketPair{
+		pair("b-yes", "b-no", "K5", 50, 0.9), // Kalshi limit reached
+		pair("a-yes", "a-no", "K2", 1, 0.5),  // Both markets already subscribed
+	})
+	if len(admitted) != 1 || admitted[0].KalshiTicker != "K2" {
+		t.Fatalf("unexpected admitted pairs %+v", admitted)
+	}
+	if len(pruned) != 1 || pruned[0].Venue != VenueKalshi {
+		t.Fatalf("unexpected pruned pairs %+v", pruned)
+	}
+
+	all := p.Pruned()
+	if len(all) != 2 || all[0].KalshiTicker != "K3" || all[1].KalshiTicker != "K5" {
+		t.Errorf("unexpected pruned list %+v", all)
+	}
+
+	unlimited := NewSubscriptionPruner(SubscriptionLimits{})
+	if admitted, pruned := unlimited.Admit([]MarketPair{pair("x", "y", "K", 0, 0)}); len(admitted) != 1 || len(pruned) != 0 {
+		t.Errorf("unlimited pruner pruned %+v", pruned)
+	}
+}
@@ -0,0 +1,39 @@
+package arb
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrHistoryNotFound is returned when asking for the history of an
+// opportunity that is neither open nor recently ended
+var ErrHistoryNotFound = errors.New("opportunity not open or recently ended")
+
+// rlockContext read-locks e.mu, giving up when ctx is done first. A compute
+// cycle holds the write lock for its whole run, so a query behind a slow one
+// would otherwise outlive its request. When ctx wins, the lock is released
+// as soon as it is eventually acquired.
+func (e *Engine) rlockContext(ctx context.Context) (unlock func(), err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if e.mu.TryRLock() {
+		return e.mu.RUnlock, nil
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		e.mu.RLock()
+		close(locked)
+	}()
+	select {
+	case <-locked:
+		return e.mu.RUnlock, nil
+	case <-ctx.Done():
+		go func() {
+			<-locked
+			e.mu.RUnlock()
+		}()
+		return nil, ctx.Err()
+	}
+}
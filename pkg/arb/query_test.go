@@ -0,0 +1,44 @@
+package arb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueriesGiveUpWithTheirContext(t *testing.T) {
+	e := newTestEngine(t, nil)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := e.GetOpportunities(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetOpportunities with a canceled context: %v", err)
+	}
+
+	// A compute cycle holds the engine past the request's deadline
+	e.mu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := e.GetOpportunityHistory(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetOpportunityHistory behind a held lock: %v", err)
+	}
+	e.mu.Unlock()
+
+	// The abandoned read lock is released once acquired
+	done := make(chan struct{})
+	go func() {
+		e.mu.Lock()
+		e.mu.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("abandoned read lock never released")
+	}
+
+	if _, err := e.GetOpportunityHistory(context.Background(), "a"); !errors.Is(err, ErrHistoryNotFound) {
+		t.Errorf("unknown id: %v", err)
+	}
+}
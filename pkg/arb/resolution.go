@@ -0,0 +1,64 @@
+package arb
+
+import "sync"
+
+// ResolutionBook holds the outcomes of resolved markets on both venues, as
+// reported by their market status APIs. Methods are safe for concurrent use
+// and on a nil book, which knows no outcomes.
+type ResolutionBook struct {
+	mu     sync.RWMutex
+	pm     map[string]string // condition ID -> winning token ID
+	kalshi map[string]bool   // ticker -> YES won
+}
+
+// NewResolutionBook creates an empty book
+func NewResolutionBook() *ResolutionBook {
+	return &ResolutionBook{pm: make(map[string]string), kalshi: make(map[string]bool)}
+}
+
+// SetPolymarket records a resolved Polymarket market's winning token
+func (b *ResolutionBook) SetPolymarket(conditionID, winningToken string) {
+	if b == nil || conditionID == "" || winningToken == "" {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pm[conditionID] = winningToken
+}
+
+// SetKalshi records a resolved Kalshi market's result
+func (b *ResolutionBook) SetKalshi(ticker string, yesWon bool) {
+	if b == nil || ticker == "" {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.kalshi[ticker] = yesWon
+}
+
+// Outcome reports whether YES won on each venue of a pair; ok is false
+// until both markets have resolved
+func (b *ResolutionBook) Outcome(pmConditionID, pmTokenYes, kalshiTicker string) (pmYesWon, kalshiYesWon, ok bool) {
+	if b == nil {
+		return false, false, false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	winner, pmOK := b.pm[pmConditionID]
+	kalshiYesWon, kalshiOK := b.kalshi[kalshiTicker]
+	return winner == pmTokenYes, kalshiYesWon, pmOK && kalshiOK
+}
+
+// Payout returns what a combo pays per contract on resolution: $1 when the
+// two markets resolved consistently, $2 or $0 when they diverged
+func Payout(combo string, pmYesWon, kalshiYesWon bool) float64 {
+	pmYes, kalshiYes := ComboSides(combo)
+	var payout float64
+	if pmYes == pmYesWon {
+		payout++
+	}
+	if kalshiYes == kalshiYesWon {
+		payout++
+	}
+	return payout
+}
@@ -0,0 +1,43 @@
+package arb
+
+import "testing"
+
+func TestResolutionBook(t *testing.T) {
+	var none *ResolutionBook
+	none.SetKalshi("K", true)
+	if _, _, ok := none.Outcome("c", "y", "K"); ok {
+		t.Error("nil book reported an outcome")
+	}
+
+	b := NewResolutionBook()
+	b.SetKalshi("K", false)
+	if _, _, ok := b.Outcome("c", "y", "K"); ok {
+		t.Error("outcome reported before the Polymarket market resolved")
+	}
+	b.SetPolymarket("c", "n")
+	pmYesWon, kalshiYesWon, ok := b.Outcome("c", "y", "K")
+	if !ok || pmYesWon || kalshiYesWon {
+		t.Errorf("Outcome = %v, %v, %v; want false, false, true", pmYesWon, kalshiYesWon, ok)
+	}
+}
+
+func TestPayout(t *testing.T) {
+	for _, c := range []struct {
+		combo             string
+		pmYesWon, kYesWon bool
+		want              float64
+	}{
+		{ComboPMYesKalshiNo, true, true, 1},
+		{ComboPMYesKalshiNo, true, false, 2}, // Diverged in our favor
+		{ComboPMYesKalshiNo, false, true, 0}, // Diverged against us
+		{ComboKalshiYesPMNo, false, false, 1},
+		{ComboKalshiYesPMNo, false, true, 2},
+		{ComboPMYesKalshiYes, true, false, 1}, // Inverted pair resolving consistently
+		{ComboKalshiNoPMNo, false, true, 1},
+		{ComboPMYesKalshiYes, true, true, 2},
+	} {
+		if got := Payout(c.combo, c.pmYesWon, c.kYesWon); got != c.want {
+			t.Errorf("Payout(%s, %v, %v) = %v, want %v", c.combo, c.pmYesWon, c.kYesWon, got, c.want)
+		}
+	}
+}
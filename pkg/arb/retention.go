@@ -0,0 +1,78 @@
+package arb
+
+import (
+	"sort"
+	"time"
+)
+
+// Default retention limits
+const (
+	DefaultMaxOpportunities = 1000
+	DefaultRetentionAge     = 10 * time.Minute
+)
+
+// Retention bounds what the engine keeps in memory
+type Retention struct {
+	// MaxOpportunities caps the published list, best edges first; the rest
+	// are counted as truncated. It also caps how many ended streaks are kept.
+	MaxOpportunities int
+	// MaxAge is how long the edge history of an ended streak stays available
+	// after the opportunity disappears. 0 drops it at once.
+	MaxAge time.Duration
+}
+
+// endedTrack is the trajectory of a streak that has ended
+type endedTrack struct {
+	*edgeTrack
+	endedAt time.Time
+}
+
+// SetRetention replaces the default retention limits. A non-positive
+// MaxOpportunities keeps the default. Call before Start.
+func (e *Engine) SetRetention(r Retention) {
+	if r.MaxOpportunities <= 0 {
+		r.MaxOpportunities = DefaultMaxOpportunities
+	}
+	e.maxOpps = r.MaxOpportunities
+	e.retentionAge = r.MaxAge
+}
+
+// Truncated returns how many opportunities the last compute cycle found
+// beyond MaxOpportunities and left out of the published list
+func (e *Engine) Truncated() int {
+	return e.Snapshot().Truncated
+}
+
+// retire moves the tracks of streaks that ended this cycle to the ended set
+// and prunes it by age and count; e.mu must be held
+func (e *Engine) retire(tracks map[string]*edgeTrack, now time.Time) {
+	if e.retentionAge <= 0 {
+		e.ended = nil
+		return
+	}
+	if e.ended == nil {
+		e.ended = make(map[string]endedTrack)
+	}
+
+	for id, track := range e.tracks {
+		if _, ok := tracks[id]; !ok {
+			e.ended[id] = endedTrack{edgeTrack: track, endedAt: now}
+		}
+	}
+	for id, t := range e.ended {
+		if _, open := tracks[id]; open || now.Sub(t.endedAt) > e.retentionAge {
+			delete(e.ended, id)
+		}
+	}
+
+	if excess := len(e.ended) - e.maxOpps; excess > 0 {
+		ids := make([]string, 0, len(e.ended))
+		for id := range e.ended {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return e.ended[ids[i]].endedAt.Before(e.ended[ids[j]].endedAt) })
+		for _, id := range ids[:excess] {
+			delete(e.ended, id)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package arb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestRetentionTruncatesAndKeepsEndedHistory(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTokenNo: "N2", KalshiTicker: "K2"},
+	}
+	e := newTestEngine(t, pairs)
+	e.SetRetention(Retention{MaxOpportunities: 1, MaxAge: time.Minute})
+
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "Y1", Ask: 0.40, UpdatedAt: now},
+		{TokenID: "N1", Ask: 0.62, UpdatedAt: now},
+		{TokenID: "Y2", Ask: 0.40, UpdatedAt: now},
+		{TokenID: "N2", Ask: 0.62, UpdatedAt: now},
+	})
+	kalshi := func(ticker string, yesBid float64, at time.Time) ws.KalshiPriceUpdate {
+		return ws.KalshiPriceUpdate{Ticker: ticker, YesBid: yesBid, YesAsk: yesBid + 0.02, NoBid: 1 - yesBid - 0.02, NoAsk: 1 - yesBid, UpdatedAt: at}
+	}
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{kalshi("K1", 0.43, now), kalshi("K2", 0.44, now)})
+	e.computeOpportunities()
+
+	opps := e.Snapshot().Opportunities
+	if len(opps) != 1 || e.Truncated() != 1 {
+		t.Fatalf("expected one opportunity and one truncated, got %d and %d", len(opps), e.Truncated())
+	}
+	ids := make([]string, 0, 2)
+	for id := range e.tracks {
+		ids = append(ids, id)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("truncated opportunities should still be tracked, got %v", ids)
+	}
+
+	// K1's edge disappears: its streak ends but the history stays queryable
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{kalshi("K1", 0.36, now.Add(time.Second))})
+	e.computeOpportunities()
+	if e.Truncated() != 0 {
+		t.Errorf("expected nothing truncated, got %d", e.Truncated())
+	}
+	var endedID string
+	for _, id := range ids {
+		h, err := e.GetOpportunityHistory(context.Background(), id)
+		if err != nil {
+			t.Fatalf("history for %s: %v", id, err)
+		}
+		if h.EndedAt != nil {
+			endedID = id
+		}
+	}
+	if endedID == "" || len(e.Snapshot().Opportunities) != 1 || e.Snapshot().Opportunities[0].ID == endedID {
+		t.Fatalf("expected one ended streak beside the open opportunity, ended %q", endedID)
+	}
+
+	// Past the retention age the ended history is dropped
+	e.mu.Lock()
+	e.retire(e.tracks, time.Now().Add(2*time.Minute))
+	e.mu.Unlock()
+	if _, err := e.GetOpportunityHistory(context.Background(), endedID); !errors.Is(err, ErrHistoryNotFound) {
+		t.Error("ended history kept past the retention age")
+	}
+}
@@ -0,0 +1,197 @@
+package arb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	celast "github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/interpreter"
+)
+
+// Rule is a compiled inclusion predicate over opportunities: a CEL
+// expression (see https://github.com/google/cel-spec) over the fields
+// listed by RuleFields, e.g.
+//
+//	edge_pct > 4 && category == 'economics' && days_to_expiry < 30
+//
+// Numbers are doubles, integer literals included, so they combine with the
+// fields freely. category is lower-cased, as the venues disagree on its case.
+// Fields without a value for an opportunity, such as days_to_expiry when no
+// venue reports a resolution time, are unbound: a rule that needs one fails
+// to evaluate and does not match, unless && or || decide without it.
+// Expressions are type-checked when compiled and must be boolean.
+type Rule struct {
+	src string
+	prg cel.Program
+}
+
+// ruleField is an opportunity field rules may refer to; value reports
+// false when the opportunity has none
+type ruleField struct {
+	typ   *cel.Type
+	value func(o *Opportunity, now time.Time) (any, bool)
+}
+
+func numField(f func(*Opportunity, time.Time) float64) ruleField {
+	return ruleField{typ: cel.DoubleType, value: func(o *Opportunity, now time.Time) (any, bool) { return f(o, now), true }}
+}
+
+func optNumField(f func(*Opportunity, time.Time) (float64, bool)) ruleField {
+	return ruleField{typ: cel.DoubleType, value: func(o *Opportunity, now time.Time) (any, bool) { return f(o, now) }}
+}
+
+func strField(f func(*Opportunity) string) ruleField {
+	return ruleField{typ: cel.StringType, value: func(o *Opportunity, _ time.Time) (any, bool) { return f(o), true }}
+}
+
+func boolField(f func(*Opportunity) bool) ruleField {
+	return ruleField{typ: cel.BoolType, value: func(o *Opportunity, _ time.Time) (any, bool) { return f(o), true }}
+}
+
+// ruleFields are the opportunity fields rules may refer to
+var ruleFields = map[string]ruleField{
+	"edge_pct":           numField(func(o *Opportunity, _ time.Time) float64 { return o.EdgePctTurn }),
+	"edge_abs":           numField(func(o *Opportunity, _ time.Time) float64 { return o.EdgeAbs }),
+	"total_cost":         numField(func(o *Opportunity, _ time.Time) float64 { return o.TotalCost }),
+	"max_size":           numField(func(o *Opportunity, _ time.Time) float64 { return o.MaxSize }),
+	"liquidity":          numField(func(o *Opportunity, _ time.Time) float64 { return o.Liquidity }),
+	"match_score":        numField(func(o *Opportunity, _ time.Time) float64 { return o.MatchScore }),
+	"confidence":         numField(func(o *Opportunity, _ time.Time) float64 { return o.Confidence }),
+	"implied_divergence": numField(func(o *Opportunity, _ time.Time) float64 { return o.ImpliedDivergence }),
+	"kalshi_volume_24h":  numField(func(o *Opportunity, _ time.Time) float64 { return o.Market.KalshiVolume24h }),
+	"annualized_return": optNumField(func(o *Opportunity, _ time.Time) (float64, bool) {
+		if o.AnnualizedReturn == nil {
+			return 0, false
+		}
+		return *o.AnnualizedReturn, true
+	}),
+	"return_on_capital": optNumField(func(o *Opportunity, _ time.Time) (float64, bool) {
+		if o.Capital == nil {
+			return 0, false
+		}
+		return o.Capital.ReturnOnCapital, true
+	}),
+	"required_capital": optNumField(func(o *Opportunity, _ time.Time) (float64, bool) {
+		if o.Capital == nil {
+			return 0, false
+		}
+		return o.Capital.Required, true
+	}),
+	"days_to_expiry": optNumField(func(o *Opportunity, now time.Time) (float64, bool) {
+		at, ok := o.resolvesAt()
+		if !ok {
+			return 0, false
+		}
+		return at.Sub(now).Hours() / 24, true
+	}),
+	"quote_age": numField(func(o *Opportunity, now time.Time) float64 { // Seconds, of the older leg
+		oldest := o.PMQuoteTime
+		if o.KalshiQuoteTime.Before(oldest) {
+			oldest = o.KalshiQuoteTime
+		}
+		return now.Sub(oldest).Seconds()
+	}),
+	"category":                  strField(func(o *Opportunity) string { return strings.ToLower(o.Category) }),
+	"strategy":                  strField(func(o *Opportunity) string { return o.Strategy }),
+	"combo":                     strField(func(o *Opportunity) string { return o.Combo }),
+	"kalshi_ticker":             strField(func(o *Opportunity) string { return o.KalshiTicker }),
+	"kalshi_event":              strField(func(o *Opportunity) string { return o.Market.KalshiEventTicker }),
+	"resolution_source_differs": boolField(func(o *Opportunity) bool { return o.ResolutionSourceDiffers }),
+	"verified":                  boolField(func(o *Opportunity) bool { return o.Annotation != nil && o.Annotation.Verified }),
+}
+
+// RuleFields lists the field names rules may refer to, sorted
+func RuleFields() []string {
+	names := make([]string, 0, len(ruleFields))
+	for name := range ruleFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ruleEnv declares the fields as CEL variables, shared by every rule
+var ruleEnv = sync.OnceValues(func() (*cel.Env, error) {
+	opts := []cel.EnvOption{cel.CrossTypeNumericComparisons(true)}
+	for name, f := range ruleFields {
+		opts = append(opts, cel.Variable(name, f.typ))
+	}
+	return cel.NewEnv(opts...)
+})
+
+// CompileRule parses and type-checks a rule expression
+func CompileRule(src string) (*Rule, error) {
+	env, err := ruleEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, iss := env.Parse(src)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	doubleLiterals(ast)
+	ast, iss = env.Check(ast)
+	if iss.Err() != nil {
+		return nil, iss.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("rule is a %s, not a condition", ast.OutputType())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	return &Rule{src: src, prg: prg}, nil
+}
+
+// doubleLiterals turns a parsed rule's integer literals into doubles, the
+// type of every numeric field, as CEL's arithmetic does not mix the two
+func doubleLiterals(ast *cel.Ast) {
+	fac := celast.NewExprFactory()
+	celast.PostOrderVisit(ast.NativeRep().Expr(), celast.NewExprVisitor(func(e celast.Expr) {
+		if n, ok := e.AsLiteral().(types.Int); ok && e.Kind() == celast.LiteralKind {
+			e.SetKindCase(fac.NewLiteral(e.ID(), types.Double(n)))
+		}
+	}))
+}
+
+// Match reports whether an opportunity satisfies the rule at now
+func (r *Rule) Match(opp *Opportunity, now time.Time) bool {
+	out, _, err := r.prg.Eval(ruleActivation{opp: opp, now: now})
+	if err != nil {
+		return false
+	}
+	matched, ok := out.Value().(bool)
+	return ok && matched
+}
+
+// String returns the rule's source
+func (r *Rule) String() string {
+	return r.src
+}
+
+// ruleActivation resolves a rule's fields from an opportunity as they are
+// read, leaving those without a value unbound
+type ruleActivation struct {
+	opp *Opportunity
+	now time.Time
+}
+
+// ResolveName implements interpreter.Activation
+func (a ruleActivation) ResolveName(name string) (any, bool) {
+	f, ok := ruleFields[name]
+	if !ok {
+		return nil, false
+	}
+	return f.value(a.opp, a.now)
+}
+
+// Parent implements interpreter.Activation
+func (a ruleActivation) Parent() interpreter.Activation {
+	return nil
+}
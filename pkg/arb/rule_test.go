@@ -0,0 +1,87 @@
+package arb
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRuleMatch(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	in := func(days int) *time.Time { t := now.AddDate(0, 0, days); return &t }
+	econ := Opportunity{EdgePctTurn: 5, EdgeAbs: 0.05, Category: "Economics", MaxSize: 200, Confidence: 0.9,
+		Market: MarketMetadata{KalshiResolutionTime: in(10)}, Capital: &Capital{Required: 95, ReturnOnCapital: 4.2}}
+	noExpiry := Opportunity{EdgePctTurn: 5, Category: "economics"}
+
+	tests := []struct {
+		rule string
+		opp  Opportunity
+		want bool
+	}{
+		{"edge_pct > 4 && category == 'economics' && days_to_expiry < 30", econ, true},
+		{"edge_pct > 4 && category == \"politics\"", econ, false},
+		{"edge_pct > 4 && days_to_expiry < 30", noExpiry, false},
+		{"days_to_expiry >= 30", noExpiry, false},
+		{"days_to_expiry != 10", noExpiry, false},
+		{"edge_pct > 6 || max_size * (1 - total_cost) >= 10", econ, true},
+		{"edge_abs * max_size - required_capital / 100 > 9", econ, true},
+		{"!(category == 'economics') || confidence < 0.5", econ, false},
+		{"-edge_pct < -4.5 && !verified && resolution_source_differs == false", econ, true},
+		{"return_on_capital > 4", noExpiry, false},
+		{"1 + 2 * 3 == 7 && (1 + 2) * 3 == 9 && 8 / 4 / 2 == 1", econ, true},
+		{"days_to_expiry < 30 || edge_pct > 4", noExpiry, true},
+		{"category in ['economics', 'politics'] && !kalshi_ticker.startsWith('KX')", econ, true},
+	}
+	for _, tt := range tests {
+		r, err := CompileRule(tt.rule)
+		if err != nil {
+			t.Errorf("CompileRule(%q): %v", tt.rule, err)
+			continue
+		}
+		if got := r.Match(&tt.opp, now); got != tt.want {
+			t.Errorf("%q matched %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestCompileRuleErrors(t *testing.T) {
+	for rule, want := range map[string]string{
+		"":                        "Syntax error",
+		"edge_pct":                "not a condition",
+		"edge_pct > 'four'":       "no matching overload for '_>_' applied to '(double, string)'",
+		"edge > 4":                "undeclared reference to 'edge'",
+		"1 < edge_pct < 5":        "no matching overload for '_<_' applied to '(bool, double)'",
+		"edge_pct > 4 && 5":       "expected type 'bool' but found 'double'",
+		"!edge_pct":               "no matching overload for '!_'",
+		"category + 1 > 2":        "no matching overload for '_+_'",
+		"(edge_pct > 4":           "missing ')'",
+		"edge_pct > 4)":           "extraneous input ')'",
+		"category == 'economics":  "token recognition error",
+		"edge_pct > 4 & max_size": "token recognition error",
+		"edge_pct > 1.2.3":        "extraneous input '.3'",
+	} {
+		_, err := CompileRule(rule)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("CompileRule(%q) = %v, want an error containing %q", rule, err, want)
+		}
+	}
+}
+
+func TestRuleFilter(t *testing.T) {
+	chain, err := BuildFilters([]string{"rule"}, FilterParams{Rule: "category == 'economics' && edge_pct >= 3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rejected []string
+	kept := chain.Apply([]Opportunity{
+		{ID: "a", Category: "economics", EdgePctTurn: 3},
+		{ID: "b", Category: "sports", EdgePctTurn: 9},
+	}, time.Now(), func(name string) { rejected = append(rejected, name) })
+	if len(kept) != 1 || kept[0].ID != "a" || len(rejected) != 1 || rejected[0] != "rule" {
+		t.Errorf("kept %+v, rejected by %v", kept, rejected)
+	}
+
+	if _, err := BuildFilters([]string{"rule"}, FilterParams{Rule: "edge_pct >"}); err == nil {
+		t.Error("invalid rule accepted")
+	}
+}
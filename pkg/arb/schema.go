@@ -0,0 +1,55 @@
+package arb
+
+import (
+	"embed"
+	"fmt"
+	"sort"
+)
+
+// OpportunitySchemaVersion is the version of the Opportunity JSON payload
+// produced by this build. Adding fields keeps the version; removing,
+// renaming, retyping or redefining a field bumps it, and the previous
+// version stays selectable (see OpportunityAsVersion). Version 2 nets fees
+// out of total_cost and edge_abs.
+const OpportunitySchemaVersion = 2
+
+//go:embed schema/opportunity.v*.json
+var schemaFS embed.FS
+
+// opportunitySchemaFiles maps each supported schema version to its document
+var opportunitySchemaFiles = map[int]string{
+	1: "schema/opportunity.v1.json",
+	2: "schema/opportunity.v2.json",
+}
+
+// OpportunityAsVersion returns o as an earlier supported payload version
+// reports it. Version 1 totals and edges leave fees out.
+func OpportunityAsVersion(o Opportunity, version int) Opportunity {
+	if version >= OpportunitySchemaVersion {
+		return o
+	}
+	o.SchemaVersion = version
+	o.TotalCost -= o.Fees
+	o.EdgeAbs += o.Fees
+	o.EdgePctTurn = ComputeROI(o.EdgeAbs, o.TotalCost)
+	return o
+}
+
+// OpportunitySchema returns the JSON Schema document for a payload version
+func OpportunitySchema(version int) ([]byte, error) {
+	name, ok := opportunitySchemaFiles[version]
+	if !ok {
+		return nil, fmt.Errorf("unsupported opportunity schema version %d", version)
+	}
+	return schemaFS.ReadFile(name)
+}
+
+// SupportedOpportunitySchemaVersions lists the payload versions that can be served, ascending
+func SupportedOpportunitySchemaVersions() []int {
+	versions := make([]int, 0, len(opportunitySchemaFiles))
+	for v := range opportunitySchemaFiles {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions
+}
@@ -0,0 +1,75 @@
+package arb
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestOpportunitySchemaMatchesStruct keeps the published schema in step with
+// the Opportunity struct: every JSON field must be documented and required.
+func TestOpportunitySchemaMatchesStruct(t *testing.T) {
+	doc, err := OpportunitySchema(OpportunitySchemaVersion)
+	if err != nil {
+		t.Fatalf("OpportunitySchema: %v", err)
+	}
+
+	var schema struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(doc, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	var fields []string
+	typ := reflect.TypeOf(Opportunity{})
+	for i := 0; i < typ.NumField(); i++ {
+		name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+
+	props := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		props = append(props, name)
+	}
+
+	sort.Strings(fields)
+	sort.Strings(props)
+	required := append([]string(nil), schema.Required...)
+	sort.Strings(required)
+
+	if !reflect.DeepEqual(fields, props) {
+		t.Errorf("schema properties %v do not match struct fields %v", props, fields)
+	}
+	if !reflect.DeepEqual(fields, required) {
+		t.Errorf("schema required %v do not match struct fields %v", required, fields)
+	}
+}
+
+func TestOpportunitySchemaVersions(t *testing.T) {
+	versions := SupportedOpportunitySchemaVersions()
+	if len(versions) == 0 || versions[len(versions)-1] != OpportunitySchemaVersion {
+		t.Errorf("current version %d must be the newest supported, got %v", OpportunitySchemaVersion, versions)
+	}
+	if _, err := OpportunitySchema(0); err == nil {
+		t.Error("expected error for unsupported version")
+	}
+}
+
+func TestOpportunityAsVersion(t *testing.T) {
+	o := Opportunity{SchemaVersion: OpportunitySchemaVersion, TotalCost: 0.9872, Fees: 0.0172, EdgeAbs: 0.0128}
+	o.EdgePctTurn = ComputeROI(o.EdgeAbs, o.TotalCost)
+
+	v1 := OpportunityAsVersion(o, 1)
+	if v1.SchemaVersion != 1 || !near(v1.TotalCost, 0.97) || !near(v1.EdgeAbs, 0.03) || !near(v1.EdgePctTurn, ComputeROI(0.03, 0.97)) || v1.Fees != o.Fees {
+		t.Errorf("v1 = %+v, want the totals and edge before fees", v1)
+	}
+	if cur := OpportunityAsVersion(o, OpportunitySchemaVersion); !reflect.DeepEqual(cur, o) {
+		t.Errorf("current version changed: %+v", cur)
+	}
+}
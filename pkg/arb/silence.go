@@ -0,0 +1,125 @@
+package arb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// SilenceOptions configure a SilenceDetector
+type SilenceOptions struct {
+	After    time.Duration // How long the engine may stay silent before the alarm
+	MarginPP float64       // Edges within this many percentage points below the threshold count as activity
+	MinPairs int           // Evaluated pairs below which silence is expected rather than suspect
+}
+
+// Silence is a prolonged stretch of compute cycles with neither an
+// opportunity nor an edge near the threshold, despite healthy feeds and
+// enough evaluated pairs. Efficient markets still come close now and then;
+// total silence usually means prices are mis-scaled or mis-normalized.
+type Silence struct {
+	Since        time.Time `json:"since"`         // First silent cycle
+	BestEdgePct  float64   `json:"best_edge_pct"` // Best edge of the last cycle
+	ThresholdPct float64   `json:"threshold_pct"`
+	Evaluated    int       `json:"evaluated"` // Pairs evaluated in the last cycle
+	DetectedAt   time.Time `json:"detected_at"`
+}
+
+// SilenceStatus is a SilenceDetector's current state
+type SilenceStatus struct {
+	QuietSince *time.Time `json:"quiet_since"` // First silent cycle of the current stretch, nil when active
+	Alarmed    bool       `json:"alarmed"`
+}
+
+// SilenceDetector watches compute cycles for suspicious silence. Cycles
+// with unhealthy feeds or too few evaluated pairs explain any silence and
+// restart the clock.
+type SilenceDetector struct {
+	opts      SilenceOptions
+	threshold *Threshold
+	healthy   func() bool
+
+	mu         sync.Mutex
+	quietSince time.Time
+	alarmed    bool
+	onAlarm    []func(Silence)
+	onClear    []func(Silence)
+}
+
+// NewSilenceDetector creates a detector judging edges against threshold.
+// healthy reports whether every venue feed is connected; nil is always
+// healthy. MinPairs is at least 1.
+func NewSilenceDetector(opts SilenceOptions, threshold *Threshold, healthy func() bool) *SilenceDetector {
+	opts.MinPairs = max(opts.MinPairs, 1)
+	return &SilenceDetector{opts: opts, threshold: threshold, healthy: healthy}
+}
+
+// OnAlarm registers a handler called once when a silence lasts longer than
+// the configured period, and OnClear one called when it ends after that.
+// Handlers run on the engine's compute goroutine and must not block. Call
+// before the detector observes cycles.
+func (d *SilenceDetector) OnAlarm(fn func(Silence)) { d.onAlarm = append(d.onAlarm, fn) }
+
+// OnClear registers a handler called when an alarmed silence ends, see OnAlarm
+func (d *SilenceDetector) OnClear(fn func(Silence)) { d.onClear = append(d.onClear, fn) }
+
+// Observe judges one compute cycle from its published snapshot; register it
+// with Engine.OnUpdate through Engine.Snapshot
+func (d *SilenceDetector) Observe(snap *Snapshot) {
+	now := snap.ComputedAt
+	thresholdPct := d.threshold.Pct()
+	s := Silence{
+		BestEdgePct:  snap.Pairs.BestEdgePct,
+		ThresholdPct: thresholdPct,
+		Evaluated:    snap.Pairs.Evaluated,
+		DetectedAt:   now,
+	}
+	explained := snap.Pairs.Evaluated < d.opts.MinPairs || (d.healthy != nil && !d.healthy())
+	active := len(snap.Opportunities) > 0 || snap.Pairs.BestEdgePct >= thresholdPct-d.opts.MarginPP
+
+	d.mu.Lock()
+	var alarm, clear bool
+	if explained || active {
+		s.Since = d.quietSince
+		clear = d.alarmed
+		d.quietSince, d.alarmed = time.Time{}, false
+	} else {
+		if d.quietSince.IsZero() {
+			d.quietSince = now
+		}
+		s.Since = d.quietSince
+		alarm = !d.alarmed && now.Sub(d.quietSince) >= d.opts.After
+		d.alarmed = d.alarmed || alarm
+	}
+	quiet, alarmed := d.quietSince, d.alarmed
+	d.mu.Unlock()
+
+	var quietFor time.Duration
+	if !quiet.IsZero() {
+		quietFor = now.Sub(quiet)
+	}
+	metrics.SetSilence(quietFor, alarmed)
+
+	handlers := d.onAlarm
+	if clear {
+		handlers = d.onClear
+	} else if !alarm {
+		return
+	}
+	for _, fn := range handlers {
+		fn(s)
+	}
+}
+
+// Status returns the current silent stretch, if any
+func (d *SilenceDetector) Status() SilenceStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	st := SilenceStatus{Alarmed: d.alarmed}
+	if !d.quietSince.IsZero() {
+		since := d.quietSince
+		st.QuietSince = &since
+	}
+	return st
+}
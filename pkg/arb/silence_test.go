@@ -0,0 +1,61 @@
+package arb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSilenceDetector(t *testing.T) {
+	healthy := true
+	d := NewSilenceDetector(SilenceOptions{After: 10 * time.Minute, MarginPP: 1, MinPairs: 5}, NewThreshold(2), func() bool { return healthy })
+	var alarms, clears []Silence
+	d.OnAlarm(func(s Silence) { alarms = append(alarms, s) })
+	d.OnClear(func(s Silence) { clears = append(clears, s) })
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cycle := func(at time.Duration, evaluated int, best float64, opps int) {
+		d.Observe(&Snapshot{
+			ComputedAt:    start.Add(at),
+			Opportunities: make([]Opportunity, opps),
+			Pairs:         PairStats{Evaluated: evaluated, BestEdgePct: best},
+		})
+	}
+
+	// A near-threshold edge is activity; a disconnected feed or too few
+	// pairs explains silence and restarts the clock
+	cycle(0, 20, -3, 0)
+	cycle(5*time.Minute, 20, 1.2, 0)
+	cycle(6*time.Minute, 20, -3, 0)
+	healthy = false
+	cycle(15*time.Minute, 20, -3, 0)
+	healthy = true
+	cycle(16*time.Minute, 3, -3, 0)
+	cycle(17*time.Minute, 20, -3, 0)
+	cycle(26*time.Minute, 20, -3, 0)
+	if len(alarms) != 0 {
+		t.Fatalf("unexpected alarms %+v", alarms)
+	}
+	if st := d.Status(); st.Alarmed || st.QuietSince == nil || !st.QuietSince.Equal(start.Add(17*time.Minute)) {
+		t.Fatalf("unexpected status %+v", st)
+	}
+
+	cycle(27*time.Minute, 20, -2.5, 0)
+	cycle(40*time.Minute, 20, -2.5, 0)
+	if len(alarms) != 1 {
+		t.Fatalf("expected a single alarm, got %+v", alarms)
+	}
+	if a := alarms[0]; !a.Since.Equal(start.Add(17*time.Minute)) || a.BestEdgePct != -2.5 || a.ThresholdPct != 2 || a.Evaluated != 20 {
+		t.Errorf("unexpected alarm %+v", a)
+	}
+	if !d.Status().Alarmed {
+		t.Error("expected the alarm in the status")
+	}
+
+	cycle(41*time.Minute, 20, 3, 1)
+	if len(clears) != 1 || !clears[0].Since.Equal(start.Add(17*time.Minute)) {
+		t.Fatalf("expected the alarm cleared, got %+v", clears)
+	}
+	if st := d.Status(); st.Alarmed || st.QuietSince != nil {
+		t.Errorf("unexpected status after activity %+v", st)
+	}
+}
@@ -0,0 +1,51 @@
+package arb
+
+import "time"
+
+// PairStats counts the monitored pairs by how far they got in a compute cycle
+type PairStats struct {
+	Monitored int `json:"monitored"`
+	Quoted    int `json:"quoted"`    // Two-sided quotes on both venues, not halted or suspended
+	Evaluated int `json:"evaluated"` // Quoted and not held out after a sharp move
+
+	// BestEdgePct is the best strict-arbitrage edge_pct_turn among evaluated
+	// pairs, above the threshold or not; 0 when none was evaluated
+	BestEdgePct float64 `json:"best_edge_pct"`
+}
+
+// Snapshot is an immutable view of the engine's published state. Readers
+// share it and must not modify it or its slices.
+type Snapshot struct {
+	Revision      uint64        `json:"revision"` // Changes whenever Opportunities does
+	ComputedAt    time.Time     `json:"computed_at"`
+	Opportunities []Opportunity `json:"opportunities"` // Sorted by edge, best first
+	Truncated     int           `json:"truncated"`     // Opportunities beyond the cap in the last cycle
+	Pairs         PairStats     `json:"pairs"`
+}
+
+// emptySnapshot is served before the first publication
+var emptySnapshot = &Snapshot{Opportunities: []Opportunity{}}
+
+// Snapshot returns the latest published state without taking the engine
+// lock, so consumers can encode it as slowly as their clients read
+func (e *Engine) Snapshot() *Snapshot {
+	if s := e.snapshot.Load(); s != nil {
+		return s
+	}
+	return emptySnapshot
+}
+
+// publish replaces the snapshot with the current state; e.mu must be held
+func (e *Engine) publish() {
+	stats := e.stats
+	stats.Monitored = len(e.pairs)
+	snap := &Snapshot{
+		Revision:      e.revision,
+		ComputedAt:    e.computedAt,
+		Opportunities: e.opportunities,
+		Truncated:     e.truncated,
+		Pairs:         stats,
+	}
+	e.snapshot.Store(snap)
+	e.remember(snap)
+}
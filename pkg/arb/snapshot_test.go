@@ -0,0 +1,52 @@
+package arb
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestSnapshotPublishedPerCycle(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTokenNo: "N2", KalshiTicker: "K2"},
+	}
+	e := newTestEngine(t, pairs)
+	if s := e.Snapshot(); s.Pairs.Monitored != 2 || len(s.Opportunities) != 0 {
+		t.Fatalf("unexpected initial snapshot %+v", s)
+	}
+
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "Y1", Bid: 0.38, Ask: 0.40, UpdatedAt: now},
+		{TokenID: "N1", Bid: 0.60, Ask: 0.62, UpdatedAt: now},
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesBid: 0.43, YesAsk: 0.45, NoBid: 0.55, NoAsk: 0.57, UpdatedAt: now}})
+	e.computeOpportunities()
+
+	first := e.Snapshot()
+	if len(first.Opportunities) != 1 || first.ComputedAt.IsZero() {
+		t.Fatalf("expected one opportunity, got %+v", first)
+	}
+	if first.Pairs.Monitored != 2 || first.Pairs.Quoted != 1 || first.Pairs.Evaluated != 1 || math.Abs(first.Pairs.BestEdgePct-ComputeROI(0.03, 0.97)) > 1e-9 {
+		t.Errorf("unexpected pair stats %+v", first.Pairs)
+	}
+	if opps, rev, err := e.GetOpportunitiesWithRevision(context.Background()); err != nil || rev != first.Revision || len(opps) != 1 {
+		t.Errorf("accessors disagree with the snapshot: revision %d, %d opportunities", rev, len(opps))
+	}
+
+	// Acknowledging publishes a new snapshot and leaves the old one intact
+	if _, err := e.Acknowledge(first.Opportunities[0].ID, AckClaimed, "", ""); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+	second := e.Snapshot()
+	if second == first || second.Revision == first.Revision || second.Opportunities[0].Ack == nil {
+		t.Errorf("acknowledgment not published: %+v", second)
+	}
+	if first.Opportunities[0].Ack != nil {
+		t.Error("a published snapshot must not change")
+	}
+}
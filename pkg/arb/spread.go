@@ -0,0 +1,54 @@
+package arb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"time"
+)
+
+// ID returns a short stable identifier of the pair, derived from Key, for
+// use in URLs
+func (p MarketPair) ID() string {
+	sum := sha1.Sum([]byte(p.Key()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// SpreadSample is one observation of a pair's YES mid on each venue
+type SpreadSample struct {
+	PairID    string    `json:"-"`
+	At        time.Time `json:"at"`
+	PMMid     float64   `json:"pm_mid"`
+	KalshiMid float64   `json:"kalshi_mid"` // Of the side matching PM YES: NO for an inverted pair
+	Spread    float64   `json:"spread"`     // PMMid - KalshiMid, in probability
+}
+
+// SpreadSamples returns the current YES mids of every pair with a two-sided
+// book on both venues, stamped at
+func (e *Engine) SpreadSamples(at time.Time) []SpreadSample {
+	pairs := e.Pairs()
+	out := make([]SpreadSample, 0, len(pairs))
+	if !e.kalshiClient.IsQuoting() {
+		return out
+	}
+
+	for _, pair := range pairs {
+		pm, ok1 := e.pmClient.GetQuote(pair.PMYes())
+		k, ok2 := e.kalshiClient.GetQuote(pair.KalshiYes())
+		if !ok1 || !ok2 || pm.Bid <= 0 || pm.Ask <= 0 || k.YesBid <= 0 || k.YesAsk <= 0 {
+			continue
+		}
+		pmMid := (pm.Bid + pm.Ask) / 2
+		kalshiMid := (k.YesBid + k.YesAsk) / 2
+		if pair.Inverted {
+			kalshiMid = 1 - kalshiMid
+		}
+		out = append(out, SpreadSample{
+			PairID:    pair.ID(),
+			At:        at,
+			PMMid:     pmMid,
+			KalshiMid: kalshiMid,
+			Spread:    pmMid - kalshiMid,
+		})
+	}
+	return out
+}
@@ -0,0 +1,38 @@
+package arb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestSpreadSamples(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTokenNo: "N2", KalshiTicker: "K2"},
+	}
+	e := newTestEngine(t, pairs)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "Y1", Bid: 0.40, Ask: 0.44, UpdatedAt: now},
+		{TokenID: "Y2", Ask: 0.30, UpdatedAt: now}, // One-sided book
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{
+		{Ticker: "K1", YesBid: 0.45, YesAsk: 0.47, UpdatedAt: now},
+		{Ticker: "K2", YesBid: 0.25, YesAsk: 0.27, UpdatedAt: now},
+	})
+
+	samples := e.SpreadSamples(now)
+	if len(samples) != 1 {
+		t.Fatalf("expected a sample for the two-sided pair only, got %+v", samples)
+	}
+	s := samples[0]
+	if s.PairID != pairs[0].ID() || math.Abs(s.PMMid-0.42) > 1e-9 || math.Abs(s.KalshiMid-0.46) > 1e-9 || math.Abs(s.Spread+0.04) > 1e-9 {
+		t.Errorf("unexpected sample %+v", s)
+	}
+	if pairs[0].ID() == pairs[1].ID() || len(pairs[0].ID()) != 16 {
+		t.Errorf("pair IDs %q and %q", pairs[0].ID(), pairs[1].ID())
+	}
+}
@@ -0,0 +1,300 @@
+package arb
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// Built-in strategy names
+const (
+	StrategyArbitrage     = "arbitrage"
+	StrategyDivergence    = "divergence"
+	StrategySpreadCapture = "spread_capture"
+)
+
+// Combos: which side is bought on each venue for a hedged position. The
+// last two hedge inverted pairs, where PM YES and Kalshi NO are the same
+// outcome.
+const (
+	ComboPMYesKalshiNo  = "PM-YES + K-NO"
+	ComboKalshiYesPMNo  = "K-YES + PM-NO"
+	ComboPMYesKalshiYes = "PM-YES + K-YES"
+	ComboKalshiNoPMNo   = "K-NO + PM-NO"
+)
+
+// ComboSides reports whether a combo buys YES on each venue
+func ComboSides(combo string) (pmYes, kalshiYes bool) {
+	switch combo {
+	case ComboPMYesKalshiNo:
+		return true, false
+	case ComboPMYesKalshiYes:
+		return true, true
+	case ComboKalshiNoPMNo:
+		return false, false
+	}
+	return false, true
+}
+
+// PairState is a pair's current quotes on both venues, as strategies see
+// them. The engine only builds states for pairs quoted on both venues.
+// Kalshi is aligned with the Polymarket question: for an inverted pair its
+// YES and NO sides are swapped, so strategies price every pair alike and
+// the engine maps their combos back to the sides actually bought.
+type PairState struct {
+	Pair   MarketPair
+	PMYes  ws.PMPriceUpdate
+	PMNo   ws.PMPriceUpdate
+	Kalshi ws.KalshiPriceUpdate
+
+	// Polymarket order books of the YES and NO tokens, nil when the client
+	// keeps none; their depth sizes opportunities beyond the top of book
+	PMYesBook *ws.PMOrderBook
+	PMNoBook  *ws.PMOrderBook
+
+	// Vig-free probability of the Polymarket question's YES on each venue,
+	// see price.ImpliedProbability
+	PMImpliedYes     float64
+	KalshiImpliedYes float64
+
+	// Fees are netted out of the opportunities' edges
+	Fees FeeSchedule
+}
+
+// Strategy turns pair states into opportunities. The engine runs every
+// strategy concurrently each cycle, so implementations must not share
+// mutable state; each strategy gets its own metrics by Name. Opportunities
+// from different strategies must have distinct IDs (see StrategyOpportunityID).
+type Strategy interface {
+	Name() string
+	Evaluate(state PairState) []Opportunity
+}
+
+// StrategyParams holds the tunables for the built-in strategies
+type StrategyParams struct {
+	EdgeThreshold        *Threshold // Minimum ROI on turnover in percent (arbitrage), adjustable at runtime
+	MinDivergence        float64    // Minimum implied probability gap, 0-1 (divergence)
+	SpreadCaptureMinEdge float64    // Minimum ROI on turnover in percent with the Kalshi leg at its bid (spread_capture)
+}
+
+// BuildStrategies constructs the named built-in strategies. Known names:
+// arbitrage, divergence, spread_capture. A nil EdgeThreshold is 0.
+func BuildStrategies(names []string, params StrategyParams) ([]Strategy, error) {
+	if params.EdgeThreshold == nil {
+		params.EdgeThreshold = NewThreshold(0)
+	}
+	strategies := make([]Strategy, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case StrategyArbitrage:
+			strategies = append(strategies, arbitrageStrategy{threshold: params.EdgeThreshold})
+		case StrategyDivergence:
+			strategies = append(strategies, divergenceStrategy{min: params.MinDivergence})
+		case StrategySpreadCapture:
+			strategies = append(strategies, spreadCaptureStrategy{threshold: params.SpreadCaptureMinEdge})
+		default:
+			return nil, fmt.Errorf("unknown strategy %q", name)
+		}
+	}
+	return strategies, nil
+}
+
+// StrategyOpportunityID derives the opportunity identifier for a strategy's
+// combo on a pair. Strict arbitrage keeps the plain OpportunityID so IDs are
+// stable across versions.
+func StrategyOpportunityID(strategy string, pair MarketPair, combo string) string {
+	if strategy == StrategyArbitrage {
+		return OpportunityID(pair, combo)
+	}
+	return OpportunityID(pair, strategy+"|"+combo)
+}
+
+// minEdge is the smallest edge worth reporting on the pair: half a tick on
+// each venue. Anything smaller is float noise or a quote between ticks that
+// no order can capture.
+func (s PairState) minEdge() float64 {
+	return (s.Pair.PMTick() + price.KalshiTick) / 2
+}
+
+// orderable reports whether an opportunity's size reaches the Polymarket
+// market's minimum order; unknown sizes pass
+func (s PairState) orderable(o Opportunity) bool {
+	return o.MaxSize == 0 || o.MaxSize >= s.Pair.PMMinOrderSize
+}
+
+// opportunity builds a strategy's opportunity for a combo, priced at the
+// given cost of the PM and Kalshi legs. Costs are rounded up to each venue's
+// tick, the prices a buy order can actually be placed at, and the size down
+// to whole contracts: the Polymarket book's depth at the leg's price, or
+// the top of book without one. The fees of buying that size are added to the
+// total cost. Combos are as seen on the aligned state; an inverted pair's are
+// mapped to the Kalshi sides it trades.
+func (s PairState) opportunity(strategy, combo string, pmCost, kalshiCost float64) Opportunity {
+	kalshi := s.Kalshi
+	if s.Pair.Inverted {
+		kalshi = invertKalshi(kalshi)
+		if combo == ComboPMYesKalshiNo {
+			combo = ComboPMYesKalshiYes
+		} else {
+			combo = ComboKalshiNoPMNo
+		}
+	}
+	pmCost = price.CeilTick(pmCost, s.Pair.PMTick())
+	kalshiCost = price.CeilTick(kalshiCost, price.KalshiTick)
+	pmYes, kalshiYes := ComboSides(combo)
+	size, book := s.PMNo.AskSize, s.PMNoBook
+	if pmYes {
+		size, book = s.PMYes.AskSize, s.PMYesBook
+	}
+	if book != nil && len(book.Asks) > 0 {
+		size = book.AskDepth(pmCost)
+	}
+	size = math.Floor(size)
+	fees := s.Fees.perContract(size, pmCost, kalshiCost)
+	totalCost := pmCost + kalshiCost + fees
+	edgeAbs := 1.0 - totalCost
+
+	o := Opportunity{
+		ID:            StrategyOpportunityID(strategy, s.Pair, combo),
+		SchemaVersion: OpportunitySchemaVersion,
+		Strategy:      strategy,
+		Timestamp:     time.Now(),
+		Category:      s.Pair.Category,
+		Combo:         combo,
+		EdgeAbs:       edgeAbs,
+		EdgePctTurn:   ComputeROI(edgeAbs, totalCost),
+		PMTitle:       s.Pair.PMTitle,
+		PMYesAsk:      s.PMYes.Ask,
+		PMNoAsk:       s.PMNo.Ask,
+		KalshiTicker:  s.Pair.KalshiTicker,
+		KalshiTitle:   s.Pair.KalshiTitle,
+		KalshiYesBid:  kalshi.YesBid,
+		KalshiYesAsk:  kalshi.YesAsk,
+		KalshiNoBid:   kalshi.NoBid,
+		KalshiNoAsk:   kalshi.NoAsk,
+		TotalCost:     totalCost,
+		Fees:          fees,
+		MaxSize:       size,
+
+		Liquidity:       s.Pair.Liquidity,
+		MatchScore:      s.Pair.MatchScore,
+		PMQuoteTime:     latest(s.PMYes.UpdatedAt, s.PMNo.UpdatedAt),
+		KalshiQuoteTime: s.Kalshi.UpdatedAt,
+
+		PMImpliedYes:      s.PMImpliedYes,
+		KalshiImpliedYes:  s.KalshiImpliedYes,
+		ImpliedDivergence: s.PMImpliedYes - s.KalshiImpliedYes,
+
+		Market:                  s.Pair.Metadata(),
+		ResolutionSourceDiffers: s.Pair.ResolutionSourceDiffers,
+	}
+	if pmYes {
+		o.PMInstrument = s.Pair.PMYes()
+	} else {
+		o.PMInstrument = s.Pair.PMNo()
+	}
+	o.Capital = newCapital(o.MaxSize, pmCost, kalshiCost)
+	if kalshiYes {
+		o.KalshiInstrument = s.Pair.KalshiYes()
+	} else {
+		o.KalshiInstrument = s.Pair.KalshiNo()
+	}
+	return o
+}
+
+// arbitrageStrategy is strict cross-venue arbitrage: both legs bought at the
+// ask for less than the $1 the pair pays out
+type arbitrageStrategy struct{ threshold *Threshold }
+
+func (arbitrageStrategy) Name() string { return StrategyArbitrage }
+
+func (a arbitrageStrategy) Evaluate(s PairState) []Opportunity {
+	var opps []Opportunity
+	for _, o := range []Opportunity{
+		s.opportunity(StrategyArbitrage, ComboPMYesKalshiNo, s.PMYes.Ask, s.Kalshi.NoAsk),
+		s.opportunity(StrategyArbitrage, ComboKalshiYesPMNo, s.PMNo.Ask, s.Kalshi.YesAsk),
+	} {
+		if o.TotalCost > 0 && o.EdgeAbs >= s.minEdge() && s.orderable(o) && o.EdgePctTurn >= a.threshold.For(s.Pair.Category) {
+			opps = append(opps, o)
+		}
+	}
+	return opps
+}
+
+// divergenceStrategy flags pairs whose venues disagree on the outcome by at
+// least min once vig is removed. It takes the hedged combo buying YES where
+// it is cheaper; the position profits if prices converge before resolution,
+// even when its edge at entry is negative.
+type divergenceStrategy struct{ min float64 }
+
+func (divergenceStrategy) Name() string { return StrategyDivergence }
+
+func (d divergenceStrategy) Evaluate(s PairState) []Opportunity {
+	divergence := s.PMImpliedYes - s.KalshiImpliedYes
+	if math.Abs(divergence) < d.min {
+		return nil
+	}
+	var o Opportunity
+	if divergence < 0 {
+		o = s.opportunity(StrategyDivergence, ComboPMYesKalshiNo, s.PMYes.Ask, s.Kalshi.NoAsk)
+	} else {
+		o = s.opportunity(StrategyDivergence, ComboKalshiYesPMNo, s.PMNo.Ask, s.Kalshi.YesAsk)
+	}
+	if !s.orderable(o) {
+		return nil
+	}
+	return []Opportunity{o}
+}
+
+// spreadCaptureStrategy prices the Kalshi leg at its bid, as a resting maker
+// order would fill, and the Polymarket leg at the ask. It finds edges that
+// only exist inside Kalshi's spread; the Kalshi order may never fill.
+type spreadCaptureStrategy struct{ threshold float64 }
+
+func (spreadCaptureStrategy) Name() string { return StrategySpreadCapture }
+
+func (c spreadCaptureStrategy) Evaluate(s PairState) []Opportunity {
+	var opps []Opportunity
+	if s.Kalshi.NoBid > 0 && s.Kalshi.NoBid < s.Kalshi.NoAsk {
+		opps = append(opps, s.opportunity(StrategySpreadCapture, ComboPMYesKalshiNo, s.PMYes.Ask, s.Kalshi.NoBid))
+	}
+	if s.Kalshi.YesBid > 0 && s.Kalshi.YesBid < s.Kalshi.YesAsk {
+		opps = append(opps, s.opportunity(StrategySpreadCapture, ComboKalshiYesPMNo, s.PMNo.Ask, s.Kalshi.YesBid))
+	}
+
+	kept := opps[:0]
+	for _, o := range opps {
+		if o.EdgeAbs >= s.minEdge() && s.orderable(o) && o.EdgePctTurn >= c.threshold {
+			kept = append(kept, o)
+		}
+	}
+	return kept
+}
+
+// newPairState returns the state of a pair quoted on both venues
+func newPairState(pair MarketPair, pmYes, pmNo ws.PMPriceUpdate, kalshi ws.KalshiPriceUpdate) PairState {
+	if pair.Inverted {
+		kalshi = invertKalshi(kalshi)
+	}
+	pmImplied, _ := price.ImpliedProbability(pmYes.Ask, pmNo.Ask)
+	kalshiImplied, _ := price.ImpliedProbability(kalshi.YesAsk, kalshi.NoAsk)
+	return PairState{
+		Pair:             pair,
+		PMYes:            pmYes,
+		PMNo:             pmNo,
+		Kalshi:           kalshi,
+		PMImpliedYes:     pmImplied,
+		KalshiImpliedYes: kalshiImplied,
+	}
+}
+
+// invertKalshi swaps a Kalshi quote's YES and NO sides
+func invertKalshi(q ws.KalshiPriceUpdate) ws.KalshiPriceUpdate {
+	q.YesBid, q.NoBid = q.NoBid, q.YesBid
+	q.YesAsk, q.NoAsk = q.NoAsk, q.YesAsk
+	return q
+}
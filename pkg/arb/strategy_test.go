@@ -0,0 +1,214 @@
+package arb
+
+import (
+	"math"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func testPairState(pmYesAsk, pmNoAsk, kYesBid, kYesAsk float64) PairState {
+	pair := MarketPair{PMTokenYes: "y", PMTokenNo: "n", KalshiTicker: "K"}
+	return newPairState(pair,
+		ws.PMPriceUpdate{Ask: pmYesAsk, AskSize: 10},
+		ws.PMPriceUpdate{Ask: pmNoAsk, AskSize: 20},
+		ws.KalshiPriceUpdate{YesBid: kYesBid, YesAsk: kYesAsk, NoBid: 1 - kYesAsk, NoAsk: 1 - kYesBid},
+	)
+}
+
+func TestArbitrageStrategy(t *testing.T) {
+	// PM YES 0.40 + Kalshi NO 0.50 = 0.90: 11.1% on turnover
+	state := testPairState(0.40, 0.62, 0.50, 0.52)
+	threshold := NewThreshold(3)
+	opps := arbitrageStrategy{threshold: threshold}.Evaluate(state)
+	if len(opps) != 1 {
+		t.Fatalf("expected 1 opportunity, got %+v", opps)
+	}
+	o := opps[0]
+	if o.Combo != ComboPMYesKalshiNo || o.Strategy != StrategyArbitrage || o.MaxSize != 10 {
+		t.Errorf("unexpected opportunity %+v", o)
+	}
+	if o.ID != OpportunityID(state.Pair, ComboPMYesKalshiNo) {
+		t.Error("strict arbitrage must keep the plain opportunity ID")
+	}
+	if o.PMInstrument != state.Pair.PMYes() || o.KalshiInstrument != state.Pair.KalshiNo() {
+		t.Errorf("unexpected instruments %s %s", o.PMInstrument, o.KalshiInstrument)
+	}
+
+	// A runtime change applies to the next evaluation
+	if err := threshold.Set(12); err != nil {
+		t.Fatal(err)
+	}
+	if opps := (arbitrageStrategy{threshold: threshold}).Evaluate(state); len(opps) != 0 {
+		t.Errorf("expected nothing above the raised threshold, got %+v", opps)
+	}
+	if err := threshold.Set(-1); err == nil || threshold.Pct() != 12 {
+		t.Error("negative threshold accepted")
+	}
+}
+
+func TestArbitrageStrategyCategoryThreshold(t *testing.T) {
+	// 11.1% on turnover, see TestArbitrageStrategy
+	state := testPairState(0.40, 0.62, 0.50, 0.52)
+	state.Pair.Category = "sports"
+	threshold := NewThreshold(3)
+
+	if err := threshold.SetCategory("sports", 12); err != nil {
+		t.Fatal(err)
+	}
+	if opps := (arbitrageStrategy{threshold: threshold}).Evaluate(state); len(opps) != 0 {
+		t.Errorf("expected nothing above the category's threshold, got %+v", opps)
+	}
+	if threshold.For("politics") != 3 {
+		t.Errorf("politics threshold = %v, want the default 3", threshold.For("politics"))
+	}
+
+	if err := threshold.SetCategory("sports", 10); err != nil {
+		t.Fatal(err)
+	}
+	if opps := (arbitrageStrategy{threshold: threshold}).Evaluate(state); len(opps) != 1 {
+		t.Errorf("expected 1 opportunity under the lowered category threshold, got %+v", opps)
+	}
+	if err := threshold.SetCategory("sports", -1); err == nil || threshold.Categories()["sports"] != 10 {
+		t.Error("negative category threshold accepted")
+	}
+}
+
+func TestArbitrageStrategyNetOfFees(t *testing.T) {
+	// PM YES 0.45 + Kalshi NO 0.52 = 0.97 before fees: 3.1% on turnover
+	state := testPairState(0.45, 0.62, 0.48, 0.50)
+	threshold := NewThreshold(2)
+	if opps := (arbitrageStrategy{threshold: threshold}).Evaluate(state); len(opps) != 1 {
+		t.Fatalf("expected the fee-free arb, got %+v", opps)
+	}
+
+	// 10 contracts on Kalshi cost 0.07 × 10 × 0.52 × 0.48 = $0.17472, $0.18
+	// rounded up, and the Polymarket relayer $0.01: 1.9¢ per contract
+	state.Fees = FeeSchedule{KalshiRate: DefaultKalshiFeeRate, PMRelayer: 0.01}
+	o := (arbitrageStrategy{threshold: NewThreshold(0)}).Evaluate(state)
+	if len(o) != 1 || math.Abs(o[0].Fees-0.019) > 1e-9 || math.Abs(o[0].TotalCost-0.989) > 1e-9 || math.Abs(o[0].EdgeAbs-0.011) > 1e-9 {
+		t.Fatalf("opportunities = %+v, want 1.9¢ of fees in the total cost", o)
+	}
+	if opps := (arbitrageStrategy{threshold: threshold}).Evaluate(state); len(opps) != 0 {
+		t.Errorf("expected the 1.1%% net edge below the threshold, got %+v", opps)
+	}
+}
+
+func TestArbitrageStrategyBookDepth(t *testing.T) {
+	// PM YES 0.4851 on a 0.001 tick is bought at 0.486: every share offered
+	// up to that price fills, more than the top of book's 10
+	state := testPairState(0.4851, 0.62, 0.55, 0.57)
+	state.Pair.PMTickSize = 0.001
+	state.PMYesBook = &ws.PMOrderBook{Asks: []ws.PMBookLevel{{Price: 0.4851, Size: 12}, {Price: 0.486, Size: 8.5}, {Price: 0.49, Size: 100}}}
+	opps := arbitrageStrategy{threshold: NewThreshold(3)}.Evaluate(state)
+	if len(opps) != 1 || opps[0].MaxSize != 20 || opps[0].Capital.Contracts != 20 {
+		t.Fatalf("opportunities = %+v, want 20 contracts from the book", opps)
+	}
+
+	// A book without asks yet leaves the top of book's size
+	state.PMYesBook = &ws.PMOrderBook{Bids: []ws.PMBookLevel{{Price: 0.48, Size: 50}}}
+	if opps := (arbitrageStrategy{threshold: NewThreshold(3)}).Evaluate(state); len(opps) != 1 || opps[0].MaxSize != 10 {
+		t.Errorf("opportunities = %+v, want the quote's 10 contracts", opps)
+	}
+}
+
+func TestArbitrageStrategyTicks(t *testing.T) {
+	// Mid-tick quotes round up to what a buy order pays: PM YES 0.4851 on a
+	// 0.001 tick costs 0.486 and Kalshi NO 0.4955 costs 0.50
+	state := testPairState(0.4851, 0.62, 0.5045, 0.52)
+	state.Pair.PMTickSize = 0.001
+	state.Kalshi.NoAsk = 0.4955
+	state.PMYes.AskSize = 10.7
+	opps := arbitrageStrategy{threshold: NewThreshold(0)}.Evaluate(state)
+	if len(opps) != 1 {
+		t.Fatalf("expected 1 opportunity, got %+v", opps)
+	}
+	if o := opps[0]; math.Abs(o.TotalCost-0.986) > 1e-9 || o.MaxSize != 10 {
+		t.Errorf("expected cost 0.986 for 10 contracts, got %v for %v", o.TotalCost, o.MaxSize)
+	}
+
+	// At PM YES 0.496 the 0.004 edge is below half a tick on each venue
+	state.PMYes.Ask = 0.4951
+	if opps := (arbitrageStrategy{threshold: NewThreshold(0)}).Evaluate(state); len(opps) != 0 {
+		t.Errorf("expected sub-tick edge to be dropped, got %+v", opps)
+	}
+}
+
+func TestDivergenceStrategy(t *testing.T) {
+	// PM implies 0.40, Kalshi 0.50: buy YES on PM, where it is cheaper
+	state := testPairState(0.42, 0.63, 0.49, 0.53)
+	opps := divergenceStrategy{min: 0.05}.Evaluate(state)
+	if len(opps) != 1 || opps[0].Combo != ComboPMYesKalshiNo {
+		t.Fatalf("expected PM-YES + K-NO, got %+v", opps)
+	}
+	if opps[0].ID == OpportunityID(state.Pair, ComboPMYesKalshiNo) {
+		t.Error("divergence IDs must not collide with arbitrage IDs")
+	}
+
+	if opps := (divergenceStrategy{min: 0.2}).Evaluate(state); len(opps) != 0 {
+		t.Errorf("expected nothing below the minimum divergence, got %+v", opps)
+	}
+}
+
+func TestSpreadCaptureStrategy(t *testing.T) {
+	// At the asks PM YES 0.50 + Kalshi NO 0.55 costs 1.05, but resting at the
+	// Kalshi NO bid of 0.40 costs 0.90
+	state := testPairState(0.50, 0.52, 0.45, 0.60)
+	if opps := (arbitrageStrategy{threshold: NewThreshold(3)}).Evaluate(state); len(opps) != 0 {
+		t.Fatalf("no taker arbitrage expected, got %+v", opps)
+	}
+
+	opps := spreadCaptureStrategy{threshold: 5}.Evaluate(state)
+	if len(opps) != 1 || opps[0].Combo != ComboPMYesKalshiNo {
+		t.Fatalf("expected PM-YES + K-NO, got %+v", opps)
+	}
+	if o := opps[0]; o.TotalCost < 0.899 || o.TotalCost > 0.901 {
+		t.Errorf("expected cost 0.90 at the Kalshi bid, got %v", o.TotalCost)
+	}
+}
+
+func TestBuildStrategies(t *testing.T) {
+	strategies, err := BuildStrategies([]string{"arbitrage", " Divergence ", "spread_capture"}, StrategyParams{})
+	if err != nil || len(strategies) != 3 || strategies[1].Name() != StrategyDivergence {
+		t.Fatalf("BuildStrategies = %v, %v", strategies, err)
+	}
+	if _, err := BuildStrategies([]string{"martingale"}, StrategyParams{}); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestInvertedPairSwapsKalshiLegs(t *testing.T) {
+	// "Will X happen?" on PM against "Will X not happen?" on Kalshi: PM YES
+	// at 0.40 is hedged by Kalshi YES at 0.52
+	pair := MarketPair{PMTokenYes: "y", PMTokenNo: "n", KalshiTicker: "K", Inverted: true}
+	kalshi := ws.KalshiPriceUpdate{YesBid: 0.50, YesAsk: 0.52, NoBid: 0.48, NoAsk: 0.50}
+	state := newPairState(pair, ws.PMPriceUpdate{Ask: 0.40, AskSize: 10}, ws.PMPriceUpdate{Ask: 0.62, AskSize: 20}, kalshi)
+	if state.KalshiImpliedYes > 0.5 {
+		t.Errorf("Kalshi's probability of the PM question should come from its NO side, got %v", state.KalshiImpliedYes)
+	}
+
+	opps := arbitrageStrategy{threshold: NewThreshold(3)}.Evaluate(state)
+	if len(opps) != 1 {
+		t.Fatalf("expected 1 opportunity, got %+v", opps)
+	}
+	o := opps[0]
+	if o.Combo != ComboPMYesKalshiYes || o.TotalCost != 0.92 || o.MaxSize != 10 {
+		t.Errorf("unexpected opportunity %s at %v", o.Combo, o.TotalCost)
+	}
+	if o.PMInstrument != pair.PMYes() || o.KalshiInstrument != pair.KalshiYes() {
+		t.Errorf("unexpected instruments %s %s", o.PMInstrument, o.KalshiInstrument)
+	}
+	if o.KalshiYesAsk != 0.52 || o.KalshiNoAsk != 0.50 {
+		t.Errorf("opportunities must carry the venue's own quotes, got yes %v no %v", o.KalshiYesAsk, o.KalshiNoAsk)
+	}
+	if pmYes, kalshiYes := ComboSides(o.Combo); !pmYes || !kalshiYes {
+		t.Errorf("ComboSides(%s) = %v, %v", o.Combo, pmYes, kalshiYes)
+	}
+
+	// The other hedge buys NO on both venues
+	state = newPairState(pair, ws.PMPriceUpdate{Ask: 0.62}, ws.PMPriceUpdate{Ask: 0.40}, kalshi)
+	opps = arbitrageStrategy{threshold: NewThreshold(3)}.Evaluate(state)
+	if len(opps) != 1 || opps[0].Combo != ComboKalshiNoPMNo || opps[0].KalshiInstrument != pair.KalshiNo() {
+		t.Errorf("expected K-NO + PM-NO, got %+v", opps)
+	}
+}
@@ -0,0 +1,247 @@
+package arb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/match"
+)
+
+// Market text fields watched for edits
+const (
+	TextFieldTitle = "title"
+	TextFieldRules = "rules"
+)
+
+// ErrTextChangeNotFound is returned when accepting an unknown text change
+var ErrTextChangeNotFound = errors.New("text change not found")
+
+// TextChange is a material edit to the question or rules of a paired market,
+// see match.CompareText
+type TextChange struct {
+	ID         string             `json:"id"`
+	Venue      string             `json:"venue"`
+	Market     string             `json:"market"` // PM condition ID or Kalshi ticker
+	Field      string             `json:"field"`  // title or rules
+	Before     string             `json:"before"`
+	After      string             `json:"after"`
+	Reason     string             `json:"reason"`
+	Similarity float64            `json:"similarity"`
+	DetectedAt time.Time          `json:"detected_at"`
+	Pairs      []PairRevalidation `json:"pairs"`
+	Suspended  bool               `json:"suspended"` // Some pair failed re-validation and is excluded until accepted
+	AcceptedBy string             `json:"accepted_by,omitempty"`
+	AcceptedAt *time.Time         `json:"accepted_at,omitempty"`
+}
+
+// PairRevalidation is a pair affected by a text change, re-scored against
+// the edited title. Rules edits can't be re-scored and leave pairs valid.
+type PairRevalidation struct {
+	Key          string  `json:"key"`
+	PMTitle      string  `json:"pm_title"`
+	KalshiTicker string  `json:"kalshi_ticker"`
+	KalshiTitle  string  `json:"kalshi_title"`
+	MatchScore   float64 `json:"match_score"` // When paired
+	NewScore     float64 `json:"new_score"`   // Against the edited text
+	Valid        bool    `json:"valid"`       // NewScore still meets the matching threshold
+}
+
+// TextWatch detects edits to the questions and rules of paired markets. A
+// title edit re-scores the affected pairs, and those falling below the
+// matching threshold are excluded from opportunities until an operator
+// accepts the change. Each market is compared against the text it was paired
+// on (its rules against the first text seen), which moves on only with a
+// material change, so small edits can't drift the text unnoticed.
+type TextWatch struct {
+	mu        sync.Mutex
+	threshold float64
+	pairs     map[string][]MarketPair // venue|market -> pairs it belongs to
+	baseline  map[string]string       // venue|market|field -> text
+	changes   map[string]*TextChange
+	suspended map[string]string // pair key -> ID of the change suspending it
+	onChange  func(TextChange)
+}
+
+// NewTextWatch creates a watch over the given pairs. threshold is the title
+// similarity affected pairs must still meet, normally the matching threshold.
+func NewTextWatch(pairs []MarketPair, threshold float64) *TextWatch {
+	w := &TextWatch{
+		threshold: threshold,
+		pairs:     make(map[string][]MarketPair),
+		baseline:  make(map[string]string),
+		changes:   make(map[string]*TextChange),
+		suspended: make(map[string]string),
+	}
+	w.AddPairs(pairs)
+	return w
+}
+
+// OnChange installs a callback invoked with every material change detected,
+// e.g. to alert an operator. Call before Observe.
+func (w *TextWatch) OnChange(fn func(TextChange)) {
+	w.onChange = fn
+}
+
+// AddPairs extends the watch to additional pairs, taking their titles as the
+// baseline. Safe to call while observing.
+func (w *TextWatch) AddPairs(pairs []MarketPair) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, pair := range pairs {
+		for _, m := range []struct{ venue, market, title string }{
+			{VenuePolymarket, pair.PMConditionID, pair.PMTitle},
+			{VenueKalshi, pair.KalshiTicker, pair.KalshiTitle},
+		} {
+			if m.market == "" {
+				continue
+			}
+			key := m.venue + "|" + m.market
+			w.pairs[key] = append(w.pairs[key], pair)
+			if _, ok := w.baseline[key+"|"+TextFieldTitle]; !ok {
+				w.baseline[key+"|"+TextFieldTitle] = m.title
+			}
+		}
+	}
+}
+
+// Observe compares a market's current title and rules with its baseline,
+// recording a TextChange for each material edit. Markets not in any watched
+// pair are ignored.
+func (w *TextWatch) Observe(venue, market, title, rules string, now time.Time) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	var detected []TextChange
+	for _, f := range []struct{ field, text string }{{TextFieldTitle, title}, {TextFieldRules, rules}} {
+		if c, ok := w.compare(venue, market, f.field, f.text, now); ok {
+			detected = append(detected, c)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, c := range detected {
+		metrics.RecordMarketTextChange(venue, c.Field)
+		if w.onChange != nil {
+			w.onChange(c)
+		}
+	}
+}
+
+// compare checks one field against its baseline; w.mu must be held
+func (w *TextWatch) compare(venue, market, field, text string, now time.Time) (TextChange, bool) {
+	marketKey := venue + "|" + market
+	pairs, watched := w.pairs[marketKey]
+	if !watched || text == "" {
+		return TextChange{}, false
+	}
+	key := marketKey + "|" + field
+	before, ok := w.baseline[key]
+	if !ok || before == "" {
+		w.baseline[key] = text
+		return TextChange{}, false
+	}
+
+	diff := match.CompareText(before, text)
+	if !diff.Material {
+		return TextChange{}, false
+	}
+	w.baseline[key] = text
+
+	sum := sha1.Sum([]byte(key + "|" + text + "|" + now.String()))
+	c := &TextChange{
+		ID:         hex.EncodeToString(sum[:8]),
+		Venue:      venue,
+		Market:     market,
+		Field:      field,
+		Before:     before,
+		After:      text,
+		Reason:     diff.Reason,
+		Similarity: diff.Similarity,
+		DetectedAt: now,
+		Pairs:      make([]PairRevalidation, 0, len(pairs)),
+	}
+	for _, pair := range pairs {
+		r := PairRevalidation{
+			Key:          pair.Key(),
+			PMTitle:      pair.PMTitle,
+			KalshiTicker: pair.KalshiTicker,
+			KalshiTitle:  pair.KalshiTitle,
+			MatchScore:   pair.MatchScore,
+			NewScore:     pair.MatchScore,
+			Valid:        true,
+		}
+		if field == TextFieldTitle {
+			other := pair.KalshiTitle
+			if venue == VenueKalshi {
+				other = pair.PMTitle
+			}
+			r.NewScore = match.CategorySimilarity(pair.Category, text, other)
+			r.Valid = r.NewScore >= w.threshold
+		}
+		if !r.Valid {
+			w.suspended[r.Key] = c.ID
+			c.Suspended = true
+		}
+		c.Pairs = append(c.Pairs, r)
+	}
+	w.changes[c.ID] = c
+	return *c, true
+}
+
+// Suspended reports whether a pair is excluded pending review of a text change
+func (w *TextWatch) Suspended(pair MarketPair) bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.suspended[pair.Key()]
+	return ok
+}
+
+// Changes returns the detected text changes, newest first
+func (w *TextWatch) Changes() []TextChange {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make([]TextChange, 0, len(w.changes))
+	for _, c := range w.changes {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].DetectedAt.Equal(out[j].DetectedAt) {
+			return out[i].DetectedAt.After(out[j].DetectedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// Accept records an operator's review of a text change, returning the pairs
+// it suspended to monitoring
+func (w *TextWatch) Accept(id, by string, now time.Time) (TextChange, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	c, ok := w.changes[id]
+	if !ok {
+		return TextChange{}, ErrTextChangeNotFound
+	}
+	for key, changeID := range w.suspended {
+		if changeID == id {
+			delete(w.suspended, key)
+		}
+	}
+	c.Suspended = false
+	c.AcceptedBy = by
+	c.AcceptedAt = &now
+	return *c, nil
+}
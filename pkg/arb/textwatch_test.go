@@ -0,0 +1,73 @@
+package arb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTextWatch(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "c1", PMTokenYes: "y1", PMTitle: "Will BTC close above $100K on Dec 31, 2026?",
+			KalshiTicker: "K1", KalshiTitle: "Bitcoin above 100000 on Dec 31 2026", MatchScore: 0.6},
+		{PMConditionID: "c2", PMTokenYes: "y2", PMTitle: "Will the Fed cut rates in March 2026?",
+			KalshiTicker: "K2", KalshiTitle: "Fed cuts rates in March 2026", MatchScore: 0.6},
+	}
+	w := NewTextWatch(pairs, 0.5)
+	var alerts []TextChange
+	w.OnChange(func(c TextChange) { alerts = append(alerts, c) })
+	t0 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Cosmetic edits and the first rules seen set no change
+	w.Observe(VenuePolymarket, "c1", "will btc close above 100,000 on December 31 2026", "Resolves YES if the Coinbase close exceeds the threshold.", t0)
+	w.Observe(VenueKalshi, "K2", "Fed cuts rates in March 2026", "Resolves per the FOMC statement.", t0)
+	w.Observe(VenueKalshi, "OTHER", "Anything", "", t0)
+	if len(alerts) != 0 {
+		t.Fatalf("unexpected changes %+v", alerts)
+	}
+
+	// A moved threshold still scores as a match but is flagged
+	w.Observe(VenuePolymarket, "c1", "Will BTC close above $150K on Dec 31, 2026?", "Resolves YES if the Coinbase close exceeds the threshold.", t0.Add(time.Minute))
+	if len(alerts) != 1 || alerts[0].Field != TextFieldTitle || alerts[0].Reason != "numbers changed" {
+		t.Fatalf("expected one title change, got %+v", alerts)
+	}
+	if alerts[0].Suspended || !alerts[0].Pairs[0].Valid {
+		t.Errorf("pair still above threshold should stay valid, got %+v", alerts[0])
+	}
+
+	// Amended rules alert without suspending
+	w.Observe(VenueKalshi, "K2", "Fed cuts rates in March 2026", "Resolves per the FOMC statement, or the effective rate if no statement is issued by April 2026.", t0.Add(2*time.Minute))
+	if len(alerts) != 2 || alerts[1].Field != TextFieldRules || alerts[1].Suspended {
+		t.Fatalf("expected an unsuspended rules change, got %+v", alerts)
+	}
+
+	// A reworded question no longer matching suspends its pair
+	w.Observe(VenueKalshi, "K2", "Who will chair the Federal Reserve after Powell?", "Resolves per the FOMC statement, or the effective rate if no statement is issued by April 2026.", t0.Add(3*time.Minute))
+	if len(alerts) != 3 || !alerts[2].Suspended || alerts[2].Pairs[0].Valid {
+		t.Fatalf("expected a suspending title change, got %+v", alerts)
+	}
+	if !w.Suspended(pairs[1]) || w.Suspended(pairs[0]) {
+		t.Error("only the reworded pair should be suspended")
+	}
+
+	// Repeating the same text is not a new change
+	w.Observe(VenueKalshi, "K2", "Who will chair the Federal Reserve after Powell?", "", t0.Add(4*time.Minute))
+	if len(alerts) != 3 {
+		t.Errorf("unchanged text alerted again: %+v", alerts[3:])
+	}
+
+	if got := w.Changes(); len(got) != 3 || got[0].ID != alerts[2].ID {
+		t.Errorf("expected 3 changes newest first, got %+v", got)
+	}
+
+	if _, err := w.Accept("missing", "ops", t0); !errors.Is(err, ErrTextChangeNotFound) {
+		t.Errorf("expected ErrTextChangeNotFound, got %v", err)
+	}
+	c, err := w.Accept(alerts[2].ID, "ops", t0.Add(5*time.Minute))
+	if err != nil || c.Suspended || c.AcceptedBy != "ops" {
+		t.Fatalf("accept: %+v, %v", c, err)
+	}
+	if w.Suspended(pairs[1]) {
+		t.Error("accepted change should release its pair")
+	}
+}
@@ -0,0 +1,88 @@
+package arb
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// Threshold is a minimum edge in percent that can be changed while the
+// engine runs, e.g. by an operator through the admin API. Categories may
+// override it, e.g. once calibrated on their own history.
+type Threshold struct {
+	bits       atomic.Uint64
+	categories atomic.Pointer[map[string]float64] // Replaced on write, never modified
+}
+
+// NewThreshold creates a threshold of pct percent
+func NewThreshold(pct float64) *Threshold {
+	t := &Threshold{}
+	t.bits.Store(math.Float64bits(pct))
+	return t
+}
+
+// Pct returns the current threshold in percent
+func (t *Threshold) Pct() float64 {
+	return math.Float64frombits(t.bits.Load())
+}
+
+// Set changes the threshold, taking effect from the next compute cycle
+func (t *Threshold) Set(pct float64) error {
+	if err := validThreshold(pct); err != nil {
+		return err
+	}
+	t.bits.Store(math.Float64bits(pct))
+	return nil
+}
+
+// For returns the threshold of a category: its override if it has one,
+// Pct otherwise
+func (t *Threshold) For(category string) float64 {
+	if categories := t.categories.Load(); categories != nil {
+		if pct, ok := (*categories)[category]; ok {
+			return pct
+		}
+	}
+	return t.Pct()
+}
+
+// SetCategory overrides the threshold of one category, taking effect from
+// the next compute cycle
+func (t *Threshold) SetCategory(category string, pct float64) error {
+	if err := validThreshold(pct); err != nil {
+		return err
+	}
+	for {
+		old := t.categories.Load()
+		categories := t.Categories()
+		categories[category] = pct
+		if t.categories.CompareAndSwap(old, &categories) {
+			return nil
+		}
+	}
+}
+
+// Categories returns a copy of the category overrides
+func (t *Threshold) Categories() map[string]float64 {
+	out := make(map[string]float64)
+	if categories := t.categories.Load(); categories != nil {
+		for category, pct := range *categories {
+			out[category] = pct
+		}
+	}
+	return out
+}
+
+// validThreshold rejects thresholds that are not a non-negative percentage
+func validThreshold(pct float64) error {
+	if math.IsNaN(pct) || math.IsInf(pct, 0) || pct < 0 {
+		return fmt.Errorf("invalid threshold %v: must be a non-negative percentage", pct)
+	}
+	return nil
+}
+
+// EdgeThreshold returns the strict arbitrage strategy's minimum edge, shared
+// with the strategies built from StrategyParams.EdgeThreshold
+func (e *Engine) EdgeThreshold() *Threshold {
+	return e.edgeThreshold
+}
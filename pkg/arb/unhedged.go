@@ -0,0 +1,119 @@
+package arb
+
+import (
+	"sort"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+)
+
+// SignalUnhedged labels an UnhedgedSignal
+const SignalUnhedged = "unhedged"
+
+// UnhedgedSignal is a leg priced far below the probability the other
+// venue's YES and NO asks imply, on a pair its own venue quotes on one side
+// only. Strategies skip such pairs, since a one-sided book gives no reliable
+// price to hedge against. Buying the leg is a directional bet, not an
+// arbitrage: it is never published as an opportunity nor streamed with them.
+type UnhedgedSignal struct {
+	Kind         string        `json:"kind"` // Always SignalUnhedged
+	PMTitle      string        `json:"pm_title"`
+	KalshiTicker string        `json:"kalshi_ticker"`
+	Category     string        `json:"category"`
+	Venue        string        `json:"venue"`      // Venue to buy on
+	Instrument   instrument.ID `json:"instrument"` // Leg to buy
+	Outcome      string        `json:"outcome"`    // "yes" or "no" of the Polymarket question
+	Ask          float64       `json:"ask"`
+	// Implied is the outcome's vig-free probability on the other venue, see
+	// price.ImpliedProbability
+	Implied   float64       `json:"implied"`
+	Edge      float64       `json:"edge"`    // Implied - Ask
+	Missing   instrument.ID `json:"missing"` // Side of the leg's venue without a quote
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SetUnhedgedMinEdge sets how far below the other venue's implied
+// probability a lone leg must be offered to raise an UnhedgedSignal, in
+// probability points. 0 disables the signals. Call before Start.
+func (e *Engine) SetUnhedgedMinEdge(edge float64) {
+	e.unhedgedMinEdge = edge
+}
+
+// UnhedgedMinEdge returns the edge set by SetUnhedgedMinEdge
+func (e *Engine) UnhedgedMinEdge() float64 {
+	return e.unhedgedMinEdge
+}
+
+// UnhedgedSignals returns the current unhedged signals, largest edge
+// first. Pairs only get one when one venue quotes both outcomes and the
+// other only one; halted, closed and suspended pairs are skipped, as is Kalshi
+// while disabled or in maintenance.
+func (e *Engine) UnhedgedSignals() []UnhedgedSignal {
+	out := make([]UnhedgedSignal, 0)
+	if e.unhedgedMinEdge <= 0 || !e.kalshiClient.IsQuoting() || (e.kalshiPaused != nil && e.kalshiPaused()) {
+		return out
+	}
+
+	for _, pair := range e.Pairs() {
+		if len(e.pairHalts(pair)) > 0 || e.texts.Suspended(pair) {
+			continue
+		}
+		pmYes, pmYesOK := e.pmClient.GetQuote(pair.PMYes())
+		pmNo, pmNoOK := e.pmClient.GetQuote(pair.PMNo())
+		k, kOK := e.kalshiClient.GetQuote(pair.KalshiYes())
+		pmYesOK = pmYesOK && pmYes.Ask > 0
+		pmNoOK = pmNoOK && pmNo.Ask > 0
+
+		// Kalshi quotes YES only: its NO ask is there when a YES bid is.
+		// Align the sides with the Polymarket question.
+		kYes, kNo := pair.KalshiYes(), pair.KalshiNo()
+		kYesOK, kNoOK := kOK && k.YesAsk > 0, kOK && k.YesBid > 0
+		kYesAsk, kNoAsk := k.YesAsk, k.NoAsk
+		if pair.Inverted {
+			kYes, kNo = kNo, kYes
+			kYesOK, kNoOK = kNoOK, kYesOK
+			kYesAsk, kNoAsk = kNoAsk, kYesAsk
+		}
+
+		signal := func(venue string, leg, missing instrument.ID, outcome string, ask, implied float64, at time.Time) {
+			if implied-ask < e.unhedgedMinEdge {
+				return
+			}
+			out = append(out, UnhedgedSignal{
+				Kind:         SignalUnhedged,
+				PMTitle:      pair.PMTitle,
+				KalshiTicker: pair.KalshiTicker,
+				Category:     pair.Category,
+				Venue:        venue,
+				Instrument:   leg,
+				Outcome:      outcome,
+				Ask:          ask,
+				Implied:      implied,
+				Edge:         implied - ask,
+				Missing:      missing,
+				UpdatedAt:    at,
+			})
+		}
+
+		switch {
+		case pmYesOK && pmNoOK && kYesOK != kNoOK:
+			p, _ := price.ImpliedProbability(pmYes.Ask, pmNo.Ask)
+			if kYesOK {
+				signal(VenueKalshi, kYes, kNo, "yes", kYesAsk, p, k.UpdatedAt)
+			} else {
+				signal(VenueKalshi, kNo, kYes, "no", kNoAsk, 1-p, k.UpdatedAt)
+			}
+		case kYesOK && kNoOK && pmYesOK != pmNoOK:
+			p, _ := price.ImpliedProbability(kYesAsk, kNoAsk)
+			if pmYesOK {
+				signal(VenuePolymarket, pair.PMYes(), pair.PMNo(), "yes", pmYes.Ask, p, pmYes.UpdatedAt)
+			} else {
+				signal(VenuePolymarket, pair.PMNo(), pair.PMYes(), "no", pmNo.Ask, 1-p, pmNo.UpdatedAt)
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Edge > out[j].Edge })
+	return out
+}
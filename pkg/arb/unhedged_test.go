@@ -0,0 +1,57 @@
+package arb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestUnhedgedSignals(t *testing.T) {
+	pairs := []MarketPair{
+		{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"},
+		{PMConditionID: "C2", PMTokenYes: "Y2", PMTokenNo: "N2", KalshiTicker: "K2", Inverted: true},
+		{PMConditionID: "C3", PMTokenYes: "Y3", PMTokenNo: "N3", KalshiTicker: "K3"},
+	}
+	e := newTestEngine(t, pairs)
+	now := time.Now()
+	e.pmClient.SeedQuotes([]ws.PMPriceUpdate{
+		{TokenID: "Y1", Ask: 0.60, UpdatedAt: now},
+		{TokenID: "N1", Ask: 0.42, UpdatedAt: now},
+		{TokenID: "N2", Ask: 0.15, UpdatedAt: now}, // No YES ask
+		{TokenID: "Y3", Ask: 0.50, UpdatedAt: now},
+		{TokenID: "N3", Ask: 0.52, UpdatedAt: now},
+	})
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{
+		{Ticker: "K1", YesAsk: 0.30, NoBid: 0.70, NoAsk: 1, UpdatedAt: now}, // No YES bid, so no NO ask
+		{Ticker: "K2", YesBid: 0.38, YesAsk: 0.40, NoBid: 0.60, NoAsk: 0.62, UpdatedAt: now},
+		{Ticker: "K3", YesAsk: 0.45, NoBid: 0.55, NoAsk: 1, UpdatedAt: now}, // Close to Polymarket
+	})
+
+	if got := e.UnhedgedSignals(); len(got) != 0 {
+		t.Fatalf("signals raised while disabled: %+v", got)
+	}
+	e.SetUnhedgedMinEdge(0.2)
+
+	got := e.UnhedgedSignals()
+	if len(got) != 2 {
+		t.Fatalf("expected two signals, got %+v", got)
+	}
+	if s := got[0]; s.Venue != VenueKalshi || s.Instrument != instrument.KalshiYes("K1") || s.Missing != instrument.KalshiNo("K1") ||
+		s.Outcome != "yes" || math.Abs(s.Edge-(0.60/1.02-0.30)) > 1e-9 {
+		t.Errorf("unexpected Kalshi signal %+v", s)
+	}
+	// Inverted K2: the Polymarket question's NO is Kalshi YES, implied 0.40/1.02
+	if s := got[1]; s.Kind != SignalUnhedged || s.Instrument != instrument.PMToken("N2") || s.Missing != instrument.PMToken("Y2") ||
+		s.Outcome != "no" || math.Abs(s.Implied-0.40/1.02) > 1e-9 || math.Abs(s.Edge-(0.40/1.02-0.15)) > 1e-9 {
+		t.Errorf("unexpected Polymarket signal %+v", s)
+	}
+
+	// One-sided pairs never reach the strategies
+	e.computeOpportunities()
+	if opps := e.Snapshot().Opportunities; len(opps) != 0 {
+		t.Errorf("one-sided pairs published as opportunities: %+v", opps)
+	}
+}
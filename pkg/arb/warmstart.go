@@ -0,0 +1,72 @@
+package arb
+
+import (
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// WarmState is what a running instance hands a freshly started peer so it
+// can begin detecting immediately: the pair set, recent quotes and the open
+// opportunities with their streak start times
+type WarmState struct {
+	ExportedAt    time.Time              `json:"exported_at"`
+	Pairs         []MarketPair           `json:"pairs"`
+	PMQuotes      []ws.PMPriceUpdate     `json:"pm_quotes"`
+	KalshiQuotes  []ws.KalshiPriceUpdate `json:"kalshi_quotes"`
+	Opportunities []Opportunity          `json:"opportunities"`
+}
+
+// ExportWarmState snapshots the engine for a peer. Quotes older than maxAge
+// are left out; 0 includes every quote.
+func (e *Engine) ExportWarmState(maxAge time.Duration) WarmState {
+	now := time.Now()
+	fresh := func(at time.Time) bool { return maxAge <= 0 || now.Sub(at) <= maxAge }
+
+	state := WarmState{
+		ExportedAt:    now,
+		Pairs:         append([]MarketPair(nil), e.Pairs()...),
+		PMQuotes:      make([]ws.PMPriceUpdate, 0),
+		KalshiQuotes:  make([]ws.KalshiPriceUpdate, 0),
+		Opportunities: e.Snapshot().Opportunities,
+	}
+	for _, q := range e.pmClient.Quotes() {
+		if fresh(q.UpdatedAt) {
+			state.PMQuotes = append(state.PMQuotes, q)
+		}
+	}
+	for _, q := range e.kalshiClient.Quotes() {
+		if fresh(q.UpdatedAt) {
+			state.KalshiQuotes = append(state.KalshiQuotes, q)
+		}
+	}
+	return state
+}
+
+// ImportWarmState seeds the price caches and open opportunities from a
+// peer's snapshot. Opportunities keep their first-seen time, so a streak
+// still open on the first compute cycle continues rather than restarting.
+// The pair set is not imported; construct the engine with state.Pairs.
+// Call before Start.
+func (e *Engine) ImportWarmState(state WarmState) (pmQuotes, kalshiQuotes int) {
+	pmQuotes = e.pmClient.SeedQuotes(state.PMQuotes)
+	kalshiQuotes = e.kalshiClient.SeedQuotes(state.KalshiQuotes)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	opps := make([]Opportunity, 0, len(state.Opportunities))
+	for _, o := range state.Opportunities {
+		if _, seen := e.tracks[o.ID]; seen {
+			continue
+		}
+		o.Ack = nil // Acknowledgments are per instance
+		e.tracks[o.ID] = &edgeTrack{firstSeen: o.FirstSeen}
+		e.tracks[o.ID].add(EdgeSample{Timestamp: o.Timestamp, EdgeAbs: o.EdgeAbs, EdgePctTurn: o.EdgePctTurn})
+		opps = append(opps, o)
+	}
+	// Appended into a new array; the published snapshot shares the old one
+	e.opportunities = append(e.opportunities[:len(e.opportunities):len(e.opportunities)], opps...)
+	e.revision++
+	e.publish()
+	return pmQuotes, kalshiQuotes
+}
@@ -0,0 +1,101 @@
+package arb
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// newTestEngine returns an engine over unstarted clients, Kalshi enabled
+func newTestEngine(t *testing.T, pairs []MarketPair) *Engine {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	pm := ws.NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kalshi, err := ws.NewKalshiClientWithKey(context.Background(), "", "test", key, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pm.Close(); kalshi.Close() })
+	return NewEngine(context.Background(), pairs, pm, kalshi, 1, logger)
+}
+
+func TestWarmStartRoundTrip(t *testing.T) {
+	now := time.Now()
+	firstSeen := now.Add(-10 * time.Minute)
+	pair := MarketPair{PMConditionID: "C1", PMTokenYes: "Y1", PMTokenNo: "N1", KalshiTicker: "K1"}
+
+	state := WarmState{
+		ExportedAt: now,
+		Pairs:      []MarketPair{pair},
+		PMQuotes: []ws.PMPriceUpdate{
+			{TokenID: "Y1", Ask: 0.40, UpdatedAt: now.Add(-time.Minute)},
+			{TokenID: "N1", Ask: 0.55, UpdatedAt: now.Add(-time.Hour)},
+		},
+		KalshiQuotes: []ws.KalshiPriceUpdate{{Ticker: "K1", YesAsk: 0.45, NoAsk: 0.57, UpdatedAt: now.Add(-time.Minute)}},
+		Opportunities: []Opportunity{{
+			ID:        "opp-1",
+			Timestamp: now.Add(-time.Minute),
+			FirstSeen: firstSeen,
+			EdgeAbs:   0.03,
+			Ack:       &Acknowledgment{By: "peer"},
+		}},
+	}
+
+	e := newTestEngine(t, state.Pairs)
+	before := e.Snapshot().Revision
+	pmQuotes, kalshiQuotes := e.ImportWarmState(state)
+	if pmQuotes != 2 || kalshiQuotes != 1 {
+		t.Fatalf("imported %d pm and %d kalshi quotes, want 2 and 1", pmQuotes, kalshiQuotes)
+	}
+
+	opps, after := e.Snapshot().Opportunities, e.Snapshot().Revision
+	if after == before {
+		t.Error("revision not bumped by import")
+	}
+	if len(opps) != 1 || !opps[0].FirstSeen.Equal(firstSeen) {
+		t.Fatalf("opportunities = %+v, want opp-1 first seen at %v", opps, firstSeen)
+	}
+	if opps[0].Ack != nil {
+		t.Error("peer acknowledgment imported")
+	}
+	if track := e.tracks["opp-1"]; track == nil || !track.firstSeen.Equal(firstSeen) {
+		t.Error("streak start not carried into the edge track")
+	}
+	if q, ok := e.pmClient.GetQuote(instrument.PMToken("Y1")); !ok || q.Ask != 0.40 {
+		t.Errorf("pm quote = %+v, %v", q, ok)
+	}
+
+	// Re-exporting drops quotes older than maxAge
+	exported := e.ExportWarmState(5 * time.Minute)
+	if len(exported.PMQuotes) != 1 || len(exported.KalshiQuotes) != 1 || len(exported.Opportunities) != 1 {
+		t.Errorf("export = %d pm, %d kalshi quotes, %d opportunities; want 1, 1, 1",
+			len(exported.PMQuotes), len(exported.KalshiQuotes), len(exported.Opportunities))
+	}
+	if len(exported.Pairs) != 1 || exported.Pairs[0].Key() != pair.Key() {
+		t.Errorf("export pairs = %+v", exported.Pairs)
+	}
+}
+
+func TestSeedQuotesKeepsNewer(t *testing.T) {
+	e := newTestEngine(t, nil)
+	now := time.Now()
+	e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesAsk: 0.50, UpdatedAt: now}})
+
+	if n := e.kalshiClient.SeedQuotes([]ws.KalshiPriceUpdate{{Ticker: "K1", YesAsk: 0.40, UpdatedAt: now.Add(-time.Second)}}); n != 0 {
+		t.Errorf("older quote taken (%d)", n)
+	}
+	if q, _ := e.kalshiClient.GetQuote(instrument.KalshiYes("K1")); q.YesAsk != 0.50 {
+		t.Errorf("yes ask = %v, want 0.50", q.YesAsk)
+	}
+}
@@ -0,0 +1,49 @@
+package arb
+
+import (
+	"errors"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// ErrOpportunityExpired is returned when claiming or executing an
+// opportunity past its execution window
+var ErrOpportunityExpired = errors.New("opportunity past its execution window")
+
+// SetExecutionWindow sets how long after detection an opportunity stays
+// executable. Past it the opportunity stays listed but is marked Expired:
+// claims and executions are refused and shadow ledgers skip it, since an
+// alert acted on minutes later likely meets moved prices. A new streak of
+// the same edge opens a new window. 0 leaves opportunities executable for
+// their whole streak. Call before Start.
+func (e *Engine) SetExecutionWindow(window time.Duration) {
+	e.window = max(window, 0)
+}
+
+// ExecutionWindow returns how long opportunities stay executable, 0 when
+// unlimited
+func (e *Engine) ExecutionWindow() time.Duration {
+	return e.window
+}
+
+// stampExpiry sets an opportunity's execution deadline from its streak and
+// counts it once when it passes. Caller holds mu.
+func (e *Engine) stampExpiry(o *Opportunity, track *edgeTrack, now time.Time) {
+	if e.window <= 0 {
+		return
+	}
+	expiresAt := track.firstSeen.Add(e.window)
+	o.ExpiresAt = &expiresAt
+	o.Expired = !now.Before(expiresAt)
+	if o.Expired && !track.expired {
+		track.expired = true
+		metrics.RecordOpportunityExpired(o.Strategy)
+	}
+}
+
+// Executable reports whether an opportunity is within its execution window
+// at now
+func (o *Opportunity) Executable(now time.Time) bool {
+	return o.ExpiresAt == nil || now.Before(*o.ExpiresAt)
+}
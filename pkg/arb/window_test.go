@@ -0,0 +1,47 @@
+package arb
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStampExpiry(t *testing.T) {
+	first := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+	track := &edgeTrack{firstSeen: first}
+
+	e := &Engine{}
+	var o Opportunity
+	e.stampExpiry(&o, track, first.Add(time.Hour))
+	if o.ExpiresAt != nil || o.Expired || !o.Executable(first.Add(time.Hour)) {
+		t.Errorf("no window must leave the opportunity executable: %+v", o)
+	}
+
+	e.SetExecutionWindow(time.Minute)
+	e.stampExpiry(&o, track, first.Add(30*time.Second))
+	if o.ExpiresAt == nil || !o.ExpiresAt.Equal(first.Add(time.Minute)) || o.Expired {
+		t.Errorf("within the window: %+v", o)
+	}
+	e.stampExpiry(&o, track, first.Add(time.Minute))
+	if !o.Expired || !track.expired || o.Executable(first.Add(time.Minute)) {
+		t.Errorf("past the window: %+v", o)
+	}
+}
+
+func TestAcknowledgeExpired(t *testing.T) {
+	expired := time.Now().Add(-time.Second)
+	e := &Engine{
+		opportunities: []Opportunity{{ID: "a", ExpiresAt: &expired, Expired: true}},
+		tracks:        map[string]*edgeTrack{"a": {}},
+		acks:          newAckRegistry(),
+	}
+
+	for _, status := range []string{AckClaimed, AckExecuted} {
+		if _, err := e.Acknowledge("a", status, "", ""); !errors.Is(err, ErrOpportunityExpired) {
+			t.Errorf("%s: expected ErrOpportunityExpired, got %v", status, err)
+		}
+	}
+	if _, err := e.Acknowledge("a", AckSkipped, "too late", ""); err != nil {
+		t.Errorf("skipping an expired opportunity: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+// Package auth signs outbound requests to the exchanges. Each exchange's
+// scheme is a Signer producing the authentication headers for a request;
+// timestamps come from a Clock that corrects for skew against the exchange.
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Signer produces an exchange's authentication headers for a request. path
+// is the request path without query string; body is nil for requests
+// without one.
+type Signer interface {
+	Headers(method, path string, body []byte) (http.Header, error)
+}
+
+// SignRequest adds s's headers to req, reading and restoring its body
+func SignRequest(s Signer, req *http.Request) error {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return fmt.Errorf("read request body: %w", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	headers, err := s.Headers(req.Method, req.URL.Path, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	return nil
+}
+
+// DefaultSkewTolerance is the clock difference to an exchange left
+// uncorrected. Exchanges accept timestamps within a window of several
+// seconds, and Date headers only have one-second resolution.
+const DefaultSkewTolerance = 2 * time.Second
+
+// Clock supplies signing timestamps. It tracks the exchange's clock from its
+// responses and, once the local clock is off by more than the tolerance,
+// shifts timestamps by the measured skew. A nil Clock uses the local clock.
+type Clock struct {
+	tolerance time.Duration
+	now       func() time.Time
+	offset    atomic.Int64 // Nanoseconds added to the local time
+}
+
+// NewClock creates a clock correcting skew beyond tolerance, or beyond
+// DefaultSkewTolerance when tolerance is not positive
+func NewClock(tolerance time.Duration) *Clock {
+	if tolerance <= 0 {
+		tolerance = DefaultSkewTolerance
+	}
+	return &Clock{tolerance: tolerance, now: time.Now}
+}
+
+// Now returns the current time, corrected for skew
+func (c *Clock) Now() time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	return c.now().Add(time.Duration(c.offset.Load()))
+}
+
+// Offset returns the correction currently applied
+func (c *Clock) Offset() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(c.offset.Load())
+}
+
+// Observe records the exchange's current time. Skew within the tolerance
+// clears any correction; beyond it, the skew becomes the correction.
+func (c *Clock) Observe(exchange time.Time) {
+	if c == nil {
+		return
+	}
+	skew := exchange.Sub(c.now())
+	if skew.Abs() <= c.tolerance {
+		skew = 0
+	}
+	c.offset.Store(int64(skew))
+}
+
+// ObserveResponse records the exchange's time from a response's Date
+// header, if any. Responses to failed requests count too: a rejected
+// signature is often the skew itself.
+func (c *Clock) ObserveResponse(resp *http.Response) {
+	if c == nil || resp == nil {
+		return
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	// The header is truncated to the second; assume the middle of it
+	c.Observe(date.Add(500 * time.Millisecond))
+}
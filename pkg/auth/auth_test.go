@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedClock returns a clock whose local time is t
+func fixedClock(t time.Time) *Clock {
+	c := NewClock(0)
+	c.now = func() time.Time { return t }
+	return c
+}
+
+func TestClockSkew(t *testing.T) {
+	local := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := fixedClock(local)
+
+	c.Observe(local.Add(1500 * time.Millisecond))
+	if c.Offset() != 0 || !c.Now().Equal(local) {
+		t.Errorf("skew within tolerance corrected to %v", c.Offset())
+	}
+
+	c.Observe(local.Add(-5 * time.Second))
+	if c.Offset() != -5*time.Second || !c.Now().Equal(local.Add(-5*time.Second)) {
+		t.Errorf("offset = %v, want -5s", c.Offset())
+	}
+
+	resp := &http.Response{Header: http.Header{"Date": {local.Add(10 * time.Second).Format(http.TimeFormat)}}}
+	c.ObserveResponse(resp)
+	if c.Offset() != 10500*time.Millisecond {
+		t.Errorf("offset from Date header = %v, want 10.5s", c.Offset())
+	}
+
+	c.ObserveResponse(&http.Response{Header: http.Header{}})
+	if c.Offset() != 10500*time.Millisecond {
+		t.Error("a response without Date must leave the offset")
+	}
+
+	var none *Clock
+	if none.Offset() != 0 || time.Since(none.Now()) > time.Second {
+		t.Error("nil clock should be the local clock")
+	}
+}
+
+func TestKalshiSigner(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewKalshiSigner("", key, nil); err == nil {
+		t.Error("signer without key id accepted")
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := fixedClock(now)
+	clock.Observe(now.Add(-time.Minute))
+	s, err := NewKalshiSigner("key-1", key, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.elections.kalshi.com/trade-api/ws/v2?x=1", nil)
+	if err := SignRequest(s, req); err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := req.Header.Get(KalshiTimestampHeader)
+	if want := strconv.FormatInt(now.Add(-time.Minute).UnixMilli(), 10); timestamp != want {
+		t.Errorf("timestamp = %s, want the skew-corrected %s", timestamp, want)
+	}
+	if req.Header.Get(KalshiKeyHeader) != "key-1" {
+		t.Errorf("key header = %q", req.Header.Get(KalshiKeyHeader))
+	}
+
+	// The signature covers timestamp, method and path, without the query
+	signature, err := base64.StdEncoding.DecodeString(req.Header.Get(KalshiSignatureHeader))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := sha256.Sum256([]byte(timestamp + "GET/trade-api/ws/v2"))
+	if err := rsa.VerifyPSS(&key.PublicKey, crypto.SHA256, hashed[:], signature, nil); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+}
+
+func TestPolymarketL2Signer(t *testing.T) {
+	// Vector computed independently with Python's hmac module
+	secret := "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	const want = "NqLbSyojPq4RAHdFZ_T7pYFyNvQEukia7VXbbJmH6Ck="
+
+	now := time.Unix(1700000000, 0)
+	s, err := NewPolymarketL2Signer("0xabc", "api-key", secret, "pass", fixedClock(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, "https://clob.polymarket.com/order", strings.NewReader(`{"side":"BUY"}`))
+	if err := SignRequest(s, req); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := req.Header[PolymarketSignatureHeader]; len(got) != 1 || got[0] != want {
+		t.Errorf("signature = %v, want %s", got, want)
+	}
+	for name, want := range map[string]string{
+		PolymarketAddressHeader:    "0xabc",
+		PolymarketTimestampHeader:  "1700000000",
+		PolymarketAPIKeyHeader:     "api-key",
+		PolymarketPassphraseHeader: "pass",
+	} {
+		if got := req.Header[name]; len(got) != 1 || got[0] != want {
+			t.Errorf("%s = %v, want %s", name, got, want)
+		}
+	}
+	if body, _ := io.ReadAll(req.Body); string(body) != `{"side":"BUY"}` {
+		t.Errorf("body not restored: %q", body)
+	}
+
+	if _, err := NewPolymarketL2Signer("0xabc", "api-key", "not base64!", "pass", nil); err == nil {
+		t.Error("undecodable secret accepted")
+	}
+}
+
+// fakeWallet records the digest it signs
+type fakeWallet struct {
+	digest [32]byte
+}
+
+func (w *fakeWallet) Address() string { return "0x00000000000000000000000000000000000000aa" }
+
+func (w *fakeWallet) SignDigest(digest [32]byte) ([]byte, error) {
+	w.digest = digest
+	return make([]byte, 65), nil
+}
+
+func TestPolymarketL1Signer(t *testing.T) {
+	// Keccak-256 vectors
+	if got := hex.EncodeToString(keccak256(nil)); got != "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" {
+		t.Errorf("keccak256(\"\") = %s", got)
+	}
+	if got := hex.EncodeToString(keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId)"))); got != "c2f8787176b8ac6bf7215b4adcc1e069bf4ab82d9ab1df05a57a91d425935b6e" {
+		t.Errorf("domain type hash = %s", got)
+	}
+
+	wallet := &fakeWallet{}
+	s, err := NewPolymarketL1Signer(wallet, 3, fixedClock(time.Unix(1700000000, 0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers, err := s.Headers(http.MethodGet, "/auth/derive-api-key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers[PolymarketNonceHeader][0] != "3" || headers[PolymarketTimestampHeader][0] != "1700000000" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+	if sig := headers[PolymarketSignatureHeader][0]; len(sig) != 2+130 || !strings.HasPrefix(sig, "0x") {
+		t.Errorf("signature header %q", sig)
+	}
+
+	digest, err := ClobAuthDigest(wallet.Address(), "1700000000", 3)
+	if err != nil || digest != wallet.digest {
+		t.Errorf("wallet signed %x, want %x (%v)", wallet.digest, digest, err)
+	}
+	if other, _ := ClobAuthDigest(wallet.Address(), "1700000000", 4); other == digest {
+		t.Error("digest does not cover the nonce")
+	}
+	if _, err := ClobAuthDigest("0x1234", "1700000000", 0); err == nil {
+		t.Error("short address accepted")
+	}
+}
+
+func TestKeyWallet(t *testing.T) {
+	// Vectors from the web3.js accounts documentation
+	w, err := ParseWalletKey([]byte("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Address(); got != "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23" {
+		t.Errorf("address = %s", got)
+	}
+
+	var digest [32]byte
+	copy(digest[:], keccak256([]byte("\x19Ethereum Signed Message:\n9Some data")))
+	sig, err := w.SignDigest(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd" +
+		"6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a029" + "1c"
+	if got := hex.EncodeToString(sig); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+
+	for _, key := range []string{"", "0x1234", strings.Repeat("00", 32), strings.Repeat("ff", 32)} {
+		if _, err := ParseWalletKey([]byte(key)); err == nil {
+			t.Errorf("key %q accepted", key)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// KeyWallet is a WalletSigner holding an Ethereum private key in memory,
+// signing with the secp256k1 implementation of dcrd
+type KeyWallet struct {
+	key     *secp256k1.PrivateKey
+	address string
+}
+
+// ParseWalletKey parses a hex-encoded secp256k1 private key, with or
+// without 0x prefix and surrounding whitespace, as stored in a key file
+func ParseWalletKey(data []byte) (*KeyWallet, error) {
+	text := strings.TrimPrefix(strings.TrimSpace(string(data)), "0x")
+	raw, err := hex.DecodeString(text)
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("wallet key must be 32 hex-encoded bytes")
+	}
+	var scalar secp256k1.ModNScalar
+	overflow := scalar.SetByteSlice(raw)
+	clear(raw)
+	if overflow || scalar.IsZero() {
+		return nil, errors.New("wallet key out of range")
+	}
+
+	key := secp256k1.NewPrivateKey(&scalar)
+	pub := key.PubKey().SerializeUncompressed()[1:] // Without the 0x04 prefix
+	return &KeyWallet{key: key, address: checksumAddress(keccak256(pub)[12:])}, nil
+}
+
+// Address implements WalletSigner, returning the EIP-55 checksummed address
+func (w *KeyWallet) Address() string {
+	return w.address
+}
+
+// SignDigest implements WalletSigner with a deterministic (RFC 6979),
+// low-s signature; v is 27 or 28
+func (w *KeyWallet) SignDigest(digest [32]byte) ([]byte, error) {
+	// The compact form leads with v, Ethereum's trails r and s
+	sig := ecdsa.SignCompact(w.key, digest[:], false)
+	return append(sig[1:], sig[0]), nil
+}
+
+// checksumAddress formats a 20-byte address with EIP-55 mixed-case checksum
+func checksumAddress(addr []byte) string {
+	lower := hex.EncodeToString(addr)
+	hash := keccak256([]byte(lower))
+	out := []byte(lower)
+	for i, c := range out {
+		if c >= 'a' && hash[i/2]>>(4*(1-uint(i%2)))&0x0f >= 8 {
+			out[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(out)
+}
@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// kalshiCandleInterval is the candlestick period requested, the finest Kalshi offers
+const kalshiCandleInterval = time.Minute
+
+// kalshiCandle is one candlestick from the Kalshi candlesticks endpoint.
+// Prices are in cents.
+type kalshiCandle struct {
+	EndPeriodTS int64 `json:"end_period_ts"`
+	YesBid      struct {
+		Close float64 `json:"close"`
+	} `json:"yes_bid"`
+	YesAsk struct {
+		Close float64 `json:"close"`
+	} `json:"yes_ask"`
+}
+
+// KalshiSeriesTicker returns the series of a Kalshi event: event tickers are
+// the series ticker followed by "-" and the event's own suffix
+func KalshiSeriesTicker(eventTicker string) string {
+	series, _, _ := strings.Cut(eventTicker, "-")
+	return series
+}
+
+// FetchKalshiCandlesticks returns a Kalshi market's quote history between
+// from and to as one quote per minute: the closing yes bid and ask of each
+// one-minute candlestick, stamped with the candle's end. Minutes without a
+// two-sided quote are left out.
+func FetchKalshiCandlesticks(ctx context.Context, client *http.Client, baseURL, seriesTicker, ticker string, from, to time.Time) ([]ws.KalshiPriceUpdate, error) {
+	query := url.Values{}
+	query.Set("start_ts", fmt.Sprint(from.Unix()))
+	query.Set("end_ts", fmt.Sprint(to.Unix()))
+	query.Set("period_interval", fmt.Sprint(int(kalshiCandleInterval/time.Minute)))
+
+	var result struct {
+		Candlesticks []kalshiCandle `json:"candlesticks"`
+	}
+	u := fmt.Sprintf("%s/series/%s/markets/%s/candlesticks?%s", baseURL, url.PathEscape(seriesTicker), url.PathEscape(ticker), query.Encode())
+	if err := getJSON(ctx, client, u, &result); err != nil {
+		return nil, fmt.Errorf("fetch candlesticks for %s: %w", ticker, err)
+	}
+
+	quotes := make([]ws.KalshiPriceUpdate, 0, len(result.Candlesticks))
+	for _, c := range result.Candlesticks {
+		yesBid, yesAsk := c.YesBid.Close/100, c.YesAsk.Close/100
+		if yesBid <= 0 || yesAsk <= 0 {
+			continue
+		}
+		noBid, noAsk := price.NoFromYes(yesBid, yesAsk)
+		quotes = append(quotes, ws.KalshiPriceUpdate{
+			Ticker:    ticker,
+			YesBid:    yesBid,
+			YesAsk:    yesAsk,
+			NoBid:     noBid,
+			NoAsk:     noAsk,
+			UpdatedAt: time.Unix(c.EndPeriodTS, 0),
+		})
+	}
+	return quotes, nil
+}
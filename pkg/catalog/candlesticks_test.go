@@ -0,0 +1,62 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestKalshiSeriesTicker(t *testing.T) {
+	for event, want := range map[string]string{
+		"KXFEDDECISION-25DEC": "KXFEDDECISION",
+		"PRES-2028":           "PRES",
+		"SOLO":                "SOLO",
+	} {
+		if got := KalshiSeriesTicker(event); got != want {
+			t.Errorf("KalshiSeriesTicker(%q) = %q, want %q", event, got, want)
+		}
+	}
+}
+
+func TestFetchKalshiCandlesticks(t *testing.T) {
+	from := time.Unix(1_700_000_000, 0)
+	to := from.Add(3 * time.Minute)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/series/KXFED/markets/KXFED-25DEC-T4/candlesticks" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("start_ts") != "1700000000" || q.Get("end_ts") != "1700000180" || q.Get("period_interval") != "1" {
+			t.Errorf("query = %s", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"ticker": "KXFED-25DEC-T4",
+			"candlesticks": []map[string]interface{}{
+				{"end_period_ts": 1_700_000_060, "yes_bid": map[string]int{"close": 41}, "yes_ask": map[string]int{"close": 44}},
+				{"end_period_ts": 1_700_000_120, "yes_bid": map[string]int{"close": 0}, "yes_ask": map[string]int{"close": 45}},
+				{"end_period_ts": 1_700_000_180, "yes_bid": map[string]int{"close": 42}, "yes_ask": map[string]int{"close": 45}},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	quotes, err := FetchKalshiCandlesticks(context.Background(), srv.Client(), srv.URL, "KXFED", "KXFED-25DEC-T4", from, to)
+	if err != nil {
+		t.Fatalf("FetchKalshiCandlesticks: %v", err)
+	}
+	if len(quotes) != 2 {
+		t.Fatalf("got %d quotes, want 2 (one-sided minute skipped)", len(quotes))
+	}
+	q := quotes[0]
+	if q.Ticker != "KXFED-25DEC-T4" || q.YesBid != 0.41 || q.YesAsk != 0.44 || !q.UpdatedAt.Equal(from.Add(time.Minute)) {
+		t.Errorf("first quote = %+v", q)
+	}
+	if math.Abs(q.NoBid-0.56) > 1e-9 || math.Abs(q.NoAsk-0.59) > 1e-9 {
+		t.Errorf("no side = %v/%v", q.NoBid, q.NoAsk)
+	}
+}
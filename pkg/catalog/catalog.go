@@ -0,0 +1,171 @@
+// Package catalog pages through the Polymarket, Kalshi and Smarkets REST
+// market catalogs. It backs the server's bootstrap (open markets only) and
+// cmd/market-dump (everything, including closed markets).
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// kalshiPageLimit is the largest page size the Kalshi markets endpoint accepts
+const kalshiPageLimit = 1000
+
+// retryPolicy retries catalog requests failing with network errors, rate
+// limiting or server errors; a failed page would otherwise fail the whole
+// catalog
+var retryPolicy = backoff.Policy{Base: 500 * time.Millisecond, Max: 10 * time.Second, MaxAttempts: 4}
+
+// PolymarketPages calls fn with the raw market objects of each page of the
+// Polymarket CLOB catalog, following next_cursor until exhausted
+func PolymarketPages(ctx context.Context, client *http.Client, baseURL string, fn func(page []json.RawMessage) error) error {
+	cursor := ""
+	for {
+		u := baseURL + "/markets"
+		if cursor != "" {
+			u += "?next_cursor=" + url.QueryEscape(cursor)
+		}
+
+		var result struct {
+			Data       []json.RawMessage `json:"data"`
+			NextCursor string            `json:"next_cursor"`
+		}
+		if err := getJSON(ctx, client, u, &result); err != nil {
+			return err
+		}
+		if err := fn(result.Data); err != nil {
+			return err
+		}
+
+		// The CLOB API signals the end with an empty cursor or "LTE=" (base64 "-1")
+		cursor = result.NextCursor
+		if cursor == "" || cursor == "LTE=" {
+			return nil
+		}
+	}
+}
+
+// KalshiPages calls fn with the raw market objects of each page of the Kalshi
+// catalog, following cursor until exhausted. status filters markets (e.g.
+// "open"); empty returns markets in every status.
+func KalshiPages(ctx context.Context, client *http.Client, baseURL, status string, fn func(page []json.RawMessage) error) error {
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprint(kalshiPageLimit))
+		if status != "" {
+			query.Set("status", status)
+		}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+
+		var result struct {
+			Markets []json.RawMessage `json:"markets"`
+			Cursor  string            `json:"cursor"`
+		}
+		if err := getJSON(ctx, client, baseURL+"/markets?"+query.Encode(), &result); err != nil {
+			return err
+		}
+		if err := fn(result.Markets); err != nil {
+			return err
+		}
+
+		cursor = result.Cursor
+		if cursor == "" {
+			return nil
+		}
+	}
+}
+
+// FetchPolymarketMarkets returns all active, open Polymarket markets
+func FetchPolymarketMarkets(ctx context.Context, client *http.Client, baseURL string, logger *slog.Logger) ([]ws.PolymarketMarket, error) {
+	markets := make([]ws.PolymarketMarket, 0)
+
+	err := PolymarketPages(ctx, client, baseURL, func(page []json.RawMessage) error {
+		for _, raw := range page {
+			var m ws.PolymarketMarket
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return fmt.Errorf("decode market: %w", err)
+			}
+			// Filter for active/open markets
+			if m.Active && !m.Closed {
+				markets = append(markets, m)
+			}
+		}
+		logger.Debug("polymarket pagination", "fetched", len(markets))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+// FetchKalshiMarkets returns all open Kalshi markets
+func FetchKalshiMarkets(ctx context.Context, client *http.Client, baseURL string, logger *slog.Logger) ([]ws.KalshiMarket, error) {
+	markets := make([]ws.KalshiMarket, 0)
+
+	err := KalshiPages(ctx, client, baseURL, "open", func(page []json.RawMessage) error {
+		for _, raw := range page {
+			var m ws.KalshiMarket
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return fmt.Errorf("decode market: %w", err)
+			}
+			markets = append(markets, m)
+		}
+		logger.Debug("kalshi pagination", "fetched", len(markets))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+// getJSON performs a GET and decodes the JSON response into out, retrying
+// transient failures
+func getJSON(ctx context.Context, client *http.Client, u string, out interface{}) error {
+	return backoff.Retry(ctx, retryPolicy, func(ctx context.Context) error {
+		return getJSONOnce(ctx, client, u, out)
+	})
+}
+
+// getJSONOnce performs a single GET; errors not worth retrying are Permanent
+func getJSONOnce(ctx context.Context, client *http.Client, u string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("create request: %w", err))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return backoff.Permanent(fmt.Errorf("decode response: %w", err))
+	}
+	return nil
+}
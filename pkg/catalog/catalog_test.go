@@ -0,0 +1,65 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPolymarketPagesFollowsCursor(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("next_cursor") {
+		case "":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []map[string]string{{"condition_id": "a"}}, "next_cursor": "MQ=="})
+		case "MQ==":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []map[string]string{{"condition_id": "b"}}, "next_cursor": "LTE="})
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("next_cursor"))
+		}
+	}))
+	defer srv.Close()
+
+	var total int
+	err := PolymarketPages(context.Background(), srv.Client(), srv.URL, func(page []json.RawMessage) error {
+		total += len(page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PolymarketPages: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("expected 2 markets across pages, got %d", total)
+	}
+}
+
+func TestKalshiPagesStatusFilter(t *testing.T) {
+	var statuses []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statuses = append(statuses, r.URL.Query().Get("status"))
+		cursor := ""
+		if r.URL.Query().Get("cursor") == "" {
+			cursor = "next"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"markets": []map[string]string{{"ticker": "K"}}, "cursor": cursor})
+	}))
+	defer srv.Close()
+
+	var total int
+	err := KalshiPages(context.Background(), srv.Client(), srv.URL, "", func(page []json.RawMessage) error {
+		total += len(page)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("KalshiPages: %v", err)
+	}
+	if total != 2 || len(statuses) != 2 {
+		t.Fatalf("expected 2 pages, got %d markets over %d requests", total, len(statuses))
+	}
+	for _, s := range statuses {
+		if s != "" {
+			t.Errorf("empty status must not filter, got status=%q", s)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+const (
+	// smarketsPageLimit is the page size requested from the events endpoint
+	smarketsPageLimit = 100
+	// smarketsIDBatch is how many IDs are looked up per markets/contracts request
+	smarketsIDBatch = 50
+)
+
+// smarketsEvent is an event from the Smarkets events endpoint
+type smarketsEvent struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	StartDatetime string `json:"start_datetime"`
+}
+
+// smarketsMarket is a market from the Smarkets markets endpoint
+type smarketsMarket struct {
+	ID      string `json:"id"`
+	EventID string `json:"event_id"`
+	Name    string `json:"name"`
+	State   string `json:"state"`
+}
+
+// FetchSmarketsContracts returns the contracts of every upcoming or live
+// Smarkets event in the given type domains (e.g. "politics",
+// "current_affairs"), skipping settled and voided markets. Events are paged
+// through next_page; markets and contracts are looked up in ID batches.
+func FetchSmarketsContracts(ctx context.Context, client *http.Client, baseURL string, typeDomains []string, logger *slog.Logger) ([]ws.SmarketsContract, error) {
+	query := url.Values{}
+	query.Add("state", "upcoming")
+	query.Add("state", "live")
+	query.Set("limit", fmt.Sprint(smarketsPageLimit))
+	for _, domain := range typeDomains {
+		query.Add("type_domain", domain)
+	}
+
+	events := make(map[string]smarketsEvent)
+	eventIDs := make([]string, 0)
+	next := "?" + query.Encode()
+	for next != "" {
+		var result struct {
+			Events     []smarketsEvent `json:"events"`
+			Pagination struct {
+				NextPage string `json:"next_page"`
+			} `json:"pagination"`
+		}
+		if err := getJSON(ctx, client, baseURL+"/events/"+next, &result); err != nil {
+			return nil, fmt.Errorf("fetch events: %w", err)
+		}
+		for _, e := range result.Events {
+			if _, seen := events[e.ID]; !seen {
+				events[e.ID] = e
+				eventIDs = append(eventIDs, e.ID)
+			}
+		}
+		next = result.Pagination.NextPage
+	}
+	logger.Debug("smarkets events fetched", "count", len(eventIDs))
+
+	markets := make(map[string]smarketsMarket)
+	marketIDs := make([]string, 0)
+	err := inBatches(eventIDs, func(ids string) error {
+		var result struct {
+			Markets []smarketsMarket `json:"markets"`
+		}
+		if err := getJSON(ctx, client, baseURL+"/events/"+ids+"/markets/", &result); err != nil {
+			return fmt.Errorf("fetch markets: %w", err)
+		}
+		for _, m := range result.Markets {
+			if m.State == "settled" || m.State == "voided" {
+				continue
+			}
+			markets[m.ID] = m
+			marketIDs = append(marketIDs, m.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contracts := make([]ws.SmarketsContract, 0)
+	err = inBatches(marketIDs, func(ids string) error {
+		var result struct {
+			Contracts []ws.SmarketsContract `json:"contracts"`
+		}
+		if err := getJSON(ctx, client, baseURL+"/markets/"+ids+"/contracts/", &result); err != nil {
+			return fmt.Errorf("fetch contracts: %w", err)
+		}
+		for _, c := range result.Contracts {
+			m, ok := markets[c.MarketID]
+			if !ok {
+				continue
+			}
+			e := events[m.EventID]
+			c.MarketName, c.EventID, c.EventName, c.EventStart = m.Name, e.ID, e.Name, e.StartDatetime
+			contracts = append(contracts, c)
+		}
+		logger.Debug("smarkets contracts fetched", "fetched", len(contracts))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return contracts, nil
+}
+
+// inBatches calls fn with comma-separated batches of at most smarketsIDBatch
+// IDs, the form the Smarkets lookup endpoints take in their path
+func inBatches(ids []string, fn func(ids string) error) error {
+	for start := 0; start < len(ids); start += smarketsIDBatch {
+		end := min(start+smarketsIDBatch, len(ids))
+		if err := fn(strings.Join(ids[start:end], ",")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchSmarketsContracts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/events/":
+			if r.URL.Query().Get("type_domain") != "politics" {
+				t.Errorf("type_domain = %q, want politics", r.URL.Query().Get("type_domain"))
+			}
+			if r.URL.Query().Get("last_id") == "" {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"events":     []map[string]string{{"id": "1", "name": "UK General Election"}},
+					"pagination": map[string]string{"next_page": "?last_id=1&type_domain=politics"},
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"events":     []map[string]string{{"id": "2", "name": "Labour Leadership"}},
+				"pagination": map[string]string{},
+			})
+		case "/events/1,2/markets/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"markets": []map[string]string{
+				{"id": "10", "event_id": "1", "name": "Most Seats", "state": "live"},
+				{"id": "20", "event_id": "2", "name": "Next Leader", "state": "settled"},
+			}})
+		case "/markets/10/contracts/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"contracts": []map[string]string{
+				{"id": "100", "market_id": "10", "name": "Labour"},
+				{"id": "101", "market_id": "10", "name": "Conservative"},
+			}})
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	contracts, err := FetchSmarketsContracts(context.Background(), srv.Client(), srv.URL, []string{"politics"}, logger)
+	if err != nil {
+		t.Fatalf("FetchSmarketsContracts: %v", err)
+	}
+	if len(contracts) != 2 {
+		t.Fatalf("expected 2 contracts from the unsettled market, got %d", len(contracts))
+	}
+	c := contracts[0]
+	if c.EventName != "UK General Election" || c.Title() != "Most Seats: Labour" {
+		t.Errorf("got event %q title %q", c.EventName, c.Title())
+	}
+}
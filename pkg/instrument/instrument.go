@@ -0,0 +1,102 @@
+// Package instrument defines the canonical identifier for a tradable outcome
+// on either venue, so price caches, pairs, opportunities, persistence and logs
+// all refer to instruments the same way:
+//
+//	pm:token:<token id>      a Polymarket outcome token
+//	kalshi:<ticker>:yes      the YES side of a Kalshi market
+//	kalshi:<ticker>:no       the NO side of a Kalshi market
+//	smarkets:contract:<id>   a Smarkets contract, quoted as its YES side
+package instrument
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Venue prefixes
+const (
+	VenuePolymarket = "pm"
+	VenueKalshi     = "kalshi"
+	VenueSmarkets   = "smarkets"
+)
+
+// Kalshi sides
+const (
+	SideYes = "yes"
+	SideNo  = "no"
+)
+
+// ID is a canonical instrument identifier
+type ID string
+
+// PMToken returns the ID of a Polymarket outcome token
+func PMToken(tokenID string) ID {
+	return ID(VenuePolymarket + ":token:" + tokenID)
+}
+
+// Kalshi returns the ID of one side ("yes" or "no") of a Kalshi market
+func Kalshi(ticker, side string) ID {
+	return ID(VenueKalshi + ":" + ticker + ":" + strings.ToLower(side))
+}
+
+// KalshiYes returns the ID of the YES side of a Kalshi market
+func KalshiYes(ticker string) ID {
+	return Kalshi(ticker, SideYes)
+}
+
+// KalshiNo returns the ID of the NO side of a Kalshi market
+func KalshiNo(ticker string) ID {
+	return Kalshi(ticker, SideNo)
+}
+
+// SmarketsContract returns the ID of a Smarkets contract
+func SmarketsContract(contractID string) ID {
+	return ID(VenueSmarkets + ":contract:" + contractID)
+}
+
+// Parse validates s as a canonical instrument ID
+func Parse(s string) (ID, error) {
+	parts := strings.Split(s, ":")
+	switch {
+	case len(parts) == 3 && parts[0] == VenuePolymarket && parts[1] == "token" && parts[2] != "":
+		return ID(s), nil
+	case len(parts) == 3 && parts[0] == VenueSmarkets && parts[1] == "contract" && parts[2] != "":
+		return ID(s), nil
+	case len(parts) == 3 && parts[0] == VenueKalshi && parts[1] != "" && (parts[2] == SideYes || parts[2] == SideNo):
+		return ID(s), nil
+	default:
+		return "", fmt.Errorf("invalid instrument id %q", s)
+	}
+}
+
+// Venue returns the venue prefix ("pm", "kalshi" or "smarkets")
+func (id ID) Venue() string {
+	venue, _, _ := strings.Cut(string(id), ":")
+	return venue
+}
+
+// Key returns the venue-native identifier: the token ID, the Kalshi ticker or
+// the Smarkets contract ID
+func (id ID) Key() string {
+	parts := strings.Split(string(id), ":")
+	if len(parts) != 3 {
+		return ""
+	}
+	if parts[0] == VenuePolymarket || parts[0] == VenueSmarkets {
+		return parts[2]
+	}
+	return parts[1]
+}
+
+// Side returns "yes" or "no" for Kalshi instruments and "" for other venues
+func (id ID) Side() string {
+	if id.Venue() != VenueKalshi {
+		return ""
+	}
+	return string(id)[strings.LastIndexByte(string(id), ':')+1:]
+}
+
+// String implements fmt.Stringer
+func (id ID) String() string {
+	return string(id)
+}
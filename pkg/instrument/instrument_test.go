@@ -0,0 +1,40 @@
+package instrument
+
+import "testing"
+
+func TestConstructorsAndAccessors(t *testing.T) {
+	tests := []struct {
+		id    ID
+		str   string
+		venue string
+		key   string
+		side  string
+	}{
+		{PMToken("7123"), "pm:token:7123", VenuePolymarket, "7123", ""},
+		{KalshiYes("KXFED-25DEC"), "kalshi:KXFED-25DEC:yes", VenueKalshi, "KXFED-25DEC", SideYes},
+		{Kalshi("KXFED-25DEC", "NO"), "kalshi:KXFED-25DEC:no", VenueKalshi, "KXFED-25DEC", SideNo},
+		{SmarketsContract("44981231"), "smarkets:contract:44981231", VenueSmarkets, "44981231", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if tt.id.String() != tt.str {
+				t.Errorf("String() = %q, want %q", tt.id, tt.str)
+			}
+			if tt.id.Venue() != tt.venue || tt.id.Key() != tt.key || tt.id.Side() != tt.side {
+				t.Errorf("got venue=%q key=%q side=%q", tt.id.Venue(), tt.id.Key(), tt.id.Side())
+			}
+			if parsed, err := Parse(tt.str); err != nil || parsed != tt.id {
+				t.Errorf("Parse(%q) = %q, %v", tt.str, parsed, err)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, s := range []string{"", "123", "pm:123", "pm:token:", "kalshi:KX", "kalshi:KX:maybe", "nyse:token:1"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", s)
+		}
+	}
+}
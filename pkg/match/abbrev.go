@@ -0,0 +1,149 @@
+package match
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultAbbreviations maps common prediction-market abbreviations to their
+// expansions. Tokens are expanded rather than contracted so "Fed" and
+// "Federal Reserve" produce the same tokens whichever side abbreviates.
+var DefaultAbbreviations = map[string]string{
+	// Politics
+	"gop":    "republican",
+	"dem":    "democrat",
+	"dems":   "democrats",
+	"potus":  "president",
+	"flotus": "first lady",
+	"vp":     "vice president",
+	"scotus": "supreme court",
+	"gov":    "governor",
+	"sen":    "senator",
+	"doj":    "department of justice",
+	"sec":    "securities and exchange commission",
+	"usa":    "united states",
+	"uk":     "united kingdom",
+	"eu":     "european union",
+	"un":     "united nations",
+	"nyc":    "new york city",
+
+	// Economics
+	"fed":  "federal reserve",
+	"fomc": "federal reserve",
+	"ecb":  "european central bank",
+	"boe":  "bank of england",
+	"boj":  "bank of japan",
+	"cpi":  "consumer price index",
+	"ppi":  "producer price index",
+	"pce":  "personal consumption expenditures",
+	"nfp":  "nonfarm payrolls",
+	"gdp":  "gross domestic product",
+	"bps":  "basis points",
+
+	// Crypto
+	"btc": "bitcoin",
+	"eth": "ethereum",
+
+	// Sports
+	"epl": "premier league",
+	"ucl": "champions league",
+	"ufc": "ultimate fighting championship",
+	"mvp": "most valuable player",
+}
+
+// Abbreviations expands abbreviation tokens during tokenization
+type Abbreviations struct {
+	expansions map[string][]string
+}
+
+// active is the dictionary applied by Tokenize; nil disables expansion
+var active atomic.Pointer[Abbreviations]
+
+func init() {
+	active.Store(NewAbbreviations(DefaultAbbreviations))
+}
+
+// NewAbbreviations builds a dictionary from abbreviation → expansion
+// entries. Both sides are normalized like titles; entries whose abbreviation
+// is not a single token are ignored.
+func NewAbbreviations(entries map[string]string) *Abbreviations {
+	a := &Abbreviations{expansions: make(map[string][]string, len(entries))}
+	for abbr, expansion := range entries {
+		key := strings.Fields(NormalizeTitle(abbr))
+		words := strings.Fields(NormalizeTitle(expansion))
+		if len(key) != 1 || len(words) == 0 {
+			continue
+		}
+		a.expansions[key[0]] = words
+	}
+	return a
+}
+
+// ParseAbbreviations builds a dictionary from the defaults, adding or
+// overriding entries given as "abbr=expansion" and then removing the
+// abbreviations listed in disable. A disable entry of "*" drops every
+// default so only the added entries apply.
+func ParseAbbreviations(add, disable []string) (*Abbreviations, error) {
+	entries := make(map[string]string, len(DefaultAbbreviations)+len(add))
+	dropDefaults := false
+	for _, d := range disable {
+		if strings.TrimSpace(d) == "*" {
+			dropDefaults = true
+		}
+	}
+	if !dropDefaults {
+		for k, v := range DefaultAbbreviations {
+			entries[k] = v
+		}
+	}
+
+	for _, entry := range add {
+		abbr, expansion, ok := strings.Cut(entry, "=")
+		abbr, expansion = strings.TrimSpace(abbr), strings.TrimSpace(expansion)
+		if !ok || abbr == "" || expansion == "" {
+			return nil, fmt.Errorf("invalid abbreviation %q, want abbr=expansion", entry)
+		}
+		if len(strings.Fields(NormalizeTitle(abbr))) != 1 {
+			return nil, fmt.Errorf("abbreviation %q must be a single word", abbr)
+		}
+		entries[strings.ToLower(abbr)] = expansion
+	}
+
+	for _, d := range disable {
+		delete(entries, strings.ToLower(strings.TrimSpace(d)))
+	}
+
+	return NewAbbreviations(entries), nil
+}
+
+// SetAbbreviations replaces the dictionary applied by Tokenize; nil disables
+// abbreviation expansion
+func SetAbbreviations(a *Abbreviations) {
+	active.Store(a)
+}
+
+// Len returns the number of entries in the dictionary
+func (a *Abbreviations) Len() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.expansions)
+}
+
+// Expand replaces abbreviation tokens with their expansion words
+func (a *Abbreviations) Expand(tokens []string) []string {
+	if a == nil || len(a.expansions) == 0 {
+		return tokens
+	}
+
+	out := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if words, ok := a.expansions[t]; ok {
+			out = append(out, words...)
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
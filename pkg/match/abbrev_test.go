@@ -0,0 +1,71 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeExpandsAbbreviations(t *testing.T) {
+	got := Tokenize(NormalizeTitle("Will the Fed cut rates before GOP convention?"))
+	want := []string{"will", "the", "federal", "reserve", "cut", "rates", "before", "republican", "convention"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize = %v, want %v", got, want)
+	}
+}
+
+func TestTitleSimilarityAbbreviations(t *testing.T) {
+	tests := []struct {
+		title1 string
+		title2 string
+	}{
+		{"Will the Fed cut rates?", "Will the Federal Reserve cut rates?"},
+		{"Will SCOTUS overturn the ruling?", "Will the Supreme Court overturn the ruling?"},
+		{"Will BTC hit $100k?", "Will Bitcoin hit 100,000?"},
+		{"Who wins the EPL?", "Who wins the Premier League?"},
+	}
+
+	for _, tt := range tests {
+		if sim := TitleSimilarity(tt.title1, tt.title2); sim != 1.0 {
+			t.Errorf("TitleSimilarity(%q, %q) = %.2f, want 1.0", tt.title1, tt.title2, sim)
+		}
+	}
+}
+
+func TestParseAbbreviations(t *testing.T) {
+	defer SetAbbreviations(NewAbbreviations(DefaultAbbreviations))
+
+	dict, err := ParseAbbreviations([]string{"NHL=National Hockey League", "fed=fed funds"}, []string{"btc"})
+	if err != nil {
+		t.Fatalf("ParseAbbreviations: %v", err)
+	}
+	if got := dict.Expand([]string{"nhl"}); !reflect.DeepEqual(got, []string{"national", "hockey", "league"}) {
+		t.Errorf("added entry not applied: %v", got)
+	}
+	if got := dict.Expand([]string{"fed"}); !reflect.DeepEqual(got, []string{"fed", "funds"}) {
+		t.Errorf("override not applied: %v", got)
+	}
+	if got := dict.Expand([]string{"btc"}); !reflect.DeepEqual(got, []string{"btc"}) {
+		t.Errorf("disabled entry still applied: %v", got)
+	}
+
+	only, err := ParseAbbreviations([]string{"nhl=hockey"}, []string{"*"})
+	if err != nil {
+		t.Fatalf("ParseAbbreviations: %v", err)
+	}
+	if only.Len() != 1 {
+		t.Errorf("disabling * should drop all defaults, got %d entries", only.Len())
+	}
+
+	SetAbbreviations(nil)
+	if got := Tokenize("fed"); !reflect.DeepEqual(got, []string{"fed"}) {
+		t.Errorf("nil dictionary should disable expansion, got %v", got)
+	}
+}
+
+func TestParseAbbreviationsInvalid(t *testing.T) {
+	for _, entry := range []string{"fed", "=reserve", "s p=standard and poors"} {
+		if _, err := ParseAbbreviations([]string{entry}, nil); err == nil {
+			t.Errorf("expected error for %q", entry)
+		}
+	}
+}
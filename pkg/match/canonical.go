@@ -0,0 +1,125 @@
+package match
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Month names and abbreviations, matched case-folded
+const monthPattern = `(jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)`
+
+var (
+	// "march 31", "mar. 31st", "march 31, 2025"
+	monthDay = regexp.MustCompile(`\b` + monthPattern + `\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b(?:,?\s+(\d{4})\b)?`)
+	// "31 march", "31st of mar", "31 march 2025"
+	dayMonth = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?` + monthPattern + `\b\.?(?:,?\s+(\d{4})\b)?`)
+	// "3/31", "3/31/25", "3/31/2025"
+	slashDate = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})(?:/(\d{4}|\d{2}))?\b`)
+	// "2025-03-31"
+	isoDate = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	// Any remaining month name, e.g. "june 2025"
+	bareMonth = regexp.MustCompile(`\b` + monthPattern + `\b\.?`)
+
+	// "100,000", "100k", "$1.5m", "2 million", "3.0"
+	number = regexp.MustCompile(`\b(\d{1,3}(?:,\d{3})+|\d+)(?:\.(\d+))?(?:\s?(thousand|million|billion|trillion)|(k|m|mm|b|bn|tn))?\b`)
+)
+
+// monthAbbrev maps the first three letters of a month name to its number
+var monthAbbrev = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var monthNames = [...]string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// numberScale maps magnitude suffixes to multipliers
+var numberScale = map[string]int64{
+	"k": 1e3, "thousand": 1e3,
+	"m": 1e6, "mm": 1e6, "million": 1e6,
+	"b": 1e9, "bn": 1e9, "billion": 1e9,
+	"tn": 1e12, "trillion": 1e12,
+}
+
+// CanonicalizeDates rewrites the common ways of writing a calendar date to a
+// single token "<mon><day>" (e.g. "March 31", "31 Mar" and "3/31" all become
+// "mar31"), followed by the year as its own token when one was given. Bare
+// month names are shortened to their three-letter form. s must already be
+// lowercase; slash dates are read month-first unless that is impossible.
+func CanonicalizeDates(s string) string {
+	s = isoDate.ReplaceAllStringFunc(s, func(m string) string {
+		g := isoDate.FindStringSubmatch(m)
+		month, _ := strconv.Atoi(g[2])
+		day, _ := strconv.Atoi(g[3])
+		return dateToken(month, day, g[1], m)
+	})
+	s = monthDay.ReplaceAllStringFunc(s, func(m string) string {
+		g := monthDay.FindStringSubmatch(m)
+		day, _ := strconv.Atoi(g[2])
+		return dateToken(monthAbbrev[g[1][:3]], day, g[3], m)
+	})
+	s = dayMonth.ReplaceAllStringFunc(s, func(m string) string {
+		g := dayMonth.FindStringSubmatch(m)
+		day, _ := strconv.Atoi(g[1])
+		return dateToken(monthAbbrev[g[2][:3]], day, g[3], m)
+	})
+	s = slashDate.ReplaceAllStringFunc(s, func(m string) string {
+		g := slashDate.FindStringSubmatch(m)
+		month, _ := strconv.Atoi(g[1])
+		day, _ := strconv.Atoi(g[2])
+		if month > 12 && day <= 12 {
+			month, day = day, month // Day-first, e.g. 31/3
+		}
+		year := g[3]
+		if len(year) == 2 {
+			year = "20" + year
+		}
+		return dateToken(month, day, year, m)
+	})
+	return bareMonth.ReplaceAllStringFunc(s, func(m string) string {
+		return monthNames[monthAbbrev[m[:3]]]
+	})
+}
+
+// dateToken formats a canonical date, returning orig if the date is invalid
+func dateToken(month, day int, year, orig string) string {
+	if month < 1 || month > 12 || day < 1 || day > 31 {
+		return orig
+	}
+	tok := fmt.Sprintf("%s%d", monthNames[month], day)
+	if year != "" {
+		tok += " " + year
+	}
+	return tok
+}
+
+// CanonicalizeNumbers rewrites numbers to plain digits with magnitude
+// suffixes expanded and thousands separators, currency signs and trailing
+// decimal zeros dropped: "$100K", "100k" and "100,000" all become "100000",
+// and "3.0" becomes "3". s must already be lowercase.
+func CanonicalizeNumbers(s string) string {
+	return number.ReplaceAllStringFunc(s, func(m string) string {
+		g := number.FindStringSubmatch(m)
+
+		lit := strings.ReplaceAll(g[1], ",", "")
+		if g[2] != "" {
+			lit += "." + g[2]
+		}
+		v, ok := new(big.Rat).SetString(lit)
+		if !ok {
+			return m
+		}
+
+		suffix := g[3] + g[4]
+		if scale, ok := numberScale[suffix]; ok {
+			v.Mul(v, new(big.Rat).SetInt64(scale))
+		}
+
+		if v.IsInt() {
+			return v.Num().String()
+		}
+		return strings.TrimRight(v.FloatString(len(g[2])), "0")
+	})
+}
@@ -0,0 +1,84 @@
+package match
+
+import "testing"
+
+func TestCanonicalizeNumbers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"100k", "100000"},
+		{"100,000", "100000"},
+		{"$100k", "$100000"},
+		{"1,000,000", "1000000"},
+		{"1.5m", "1500000"},
+		{"2 million", "2000000"},
+		{"$3bn", "$3000000000"},
+		{"3.0%", "3%"},
+		{"2.50", "2.5"},
+		{"0.25k", "250"},
+		{"q3 2024", "q3 2024"},
+		{"5th", "5th"},
+		{"gpt-5", "gpt-5"},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalizeNumbers(tt.input); got != tt.expected {
+			t.Errorf("CanonicalizeNumbers(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestCanonicalizeDates(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"march 31", "mar31"},
+		{"mar 31", "mar31"},
+		{"mar. 31st", "mar31"},
+		{"31 mar", "mar31"},
+		{"31st of march", "mar31"},
+		{"3/31", "mar31"},
+		{"31/3", "mar31"},
+		{"3/31/25", "mar31 2025"},
+		{"december 31, 2024", "dec31 2024"},
+		{"31 dec 2024", "dec31 2024"},
+		{"2024-12-31", "dec31 2024"},
+		{"june 2025", "jun 2025"},
+		{"by sept 30?", "by sep30?"},
+		{"13/13", "13/13"},
+		{"yes/no", "yes/no"},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalizeDates(tt.input); got != tt.expected {
+			t.Errorf("CanonicalizeDates(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTitleSimilarityFormatting(t *testing.T) {
+	tests := []struct {
+		title1 string
+		title2 string
+	}{
+		{"Will Bitcoin reach $100K by March 31?", "Will Bitcoin reach 100,000 by 31 Mar?"},
+		{"Will Bitcoin reach $100k by 3/31?", "Will Bitcoin reach $100,000 by March 31st?"},
+		{"Will CPI be above 3.0%?", "Will CPI be above 3%?"},
+		{"Will GDP exceed $2.5 trillion?", "Will GDP exceed 2,500bn?"},
+	}
+
+	for _, tt := range tests {
+		if sim := TitleSimilarity(tt.title1, tt.title2); sim != 1.0 {
+			t.Errorf("TitleSimilarity(%q, %q) = %.2f, want 1.0 (normalized: %q vs %q)",
+				tt.title1, tt.title2, sim, NormalizeTitle(tt.title1), NormalizeTitle(tt.title2))
+		}
+	}
+}
+
+func TestNormalizeTitleKeepsDecimals(t *testing.T) {
+	if got := NormalizeTitle("Will CPI be above 2.5%?"); got != "will cpi be above 2.5" {
+		t.Errorf("NormalizeTitle kept %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package match
+
+import (
+	"sort"
+	"sync"
+)
+
+// Contested sides of a Collision
+const (
+	SidePolymarket = "polymarket"
+	SideKalshi     = "kalshi"
+)
+
+// Collision is a match dropped because a market in it already has its
+// allowance of better-scoring matches. Without resolution one contract
+// paired several times alerts once per pair.
+type Collision struct {
+	Suppressed MarketMatch
+	KeptBy     MarketMatch // First match kept for the contested market
+	Side       string      // Contested market: SidePolymarket or SideKalshi
+}
+
+// Resolver keeps each market in at most its allowance of matches, best
+// score first. It remembers the matches it kept, so markets matched in
+// successive batches are resolved against earlier batches too; a later,
+// better match cannot displace an earlier one, since that pair may already
+// be monitored. Safe for concurrent use.
+type Resolver struct {
+	maxPerPM     int // 0 allows any number
+	maxPerKalshi int
+
+	mu     sync.Mutex
+	pm     map[string][]MarketMatch // condition ID -> kept matches, in the order kept
+	kalshi map[string][]MarketMatch // ticker -> kept matches, in the order kept
+}
+
+// NewResolver creates a resolver keeping each Polymarket market in at most
+// maxPerPM matches and each Kalshi market in at most maxPerKalshi; 0 or
+// less is unlimited
+func NewResolver(maxPerPM, maxPerKalshi int) *Resolver {
+	return &Resolver{
+		maxPerPM:     max(maxPerPM, 0),
+		maxPerKalshi: max(maxPerKalshi, 0),
+		pm:           make(map[string][]MarketMatch),
+		kalshi:       make(map[string][]MarketMatch),
+	}
+}
+
+// Resolve keeps the matches whose markets both have allowance left,
+// claiming it greedily from the highest score down, and reports the rest.
+// Kept matches are returned in their original order. A nil Resolver keeps
+// everything.
+func (r *Resolver) Resolve(matches []MarketMatch) (kept []MarketMatch, collisions []Collision) {
+	if r == nil {
+		return matches, nil
+	}
+	order := make([]int, len(matches))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return matches[order[a]].Score > matches[order[b]].Score })
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keep := make([]bool, len(matches))
+	for _, i := range order {
+		m := matches[i]
+		pmKept, kalshiKept := r.pm[m.PM.ConditionID], r.kalshi[m.Kalshi.Ticker]
+		switch {
+		case r.maxPerPM > 0 && len(pmKept) >= r.maxPerPM:
+			collisions = append(collisions, Collision{Suppressed: m, KeptBy: pmKept[0], Side: SidePolymarket})
+		case r.maxPerKalshi > 0 && len(kalshiKept) >= r.maxPerKalshi:
+			collisions = append(collisions, Collision{Suppressed: m, KeptBy: kalshiKept[0], Side: SideKalshi})
+		default:
+			keep[i] = true
+			r.pm[m.PM.ConditionID] = append(pmKept, m)
+			r.kalshi[m.Kalshi.Ticker] = append(kalshiKept, m)
+		}
+	}
+
+	kept = make([]MarketMatch, 0, len(matches)-len(collisions))
+	for i, m := range matches {
+		if keep[i] {
+			kept = append(kept, m)
+		}
+	}
+	return kept, collisions
+}
@@ -0,0 +1,82 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func collisionMatch(condition, ticker string, score float64) MarketMatch {
+	return MarketMatch{
+		PM:     ws.PolymarketMarket{ConditionID: condition},
+		Kalshi: ws.KalshiMarket{Ticker: ticker},
+		Score:  score,
+	}
+}
+
+func matchKeys(matches []MarketMatch) []string {
+	keys := make([]string, len(matches))
+	for i, m := range matches {
+		keys[i] = m.PM.ConditionID + "/" + m.Kalshi.Ticker
+	}
+	return keys
+}
+
+func TestResolverKeepsBestMatch(t *testing.T) {
+	r := NewResolver(1, 1)
+	kept, collisions := r.Resolve([]MarketMatch{
+		collisionMatch("pm1", "K1", 0.80),
+		collisionMatch("pm1", "K2", 0.95), // pm1's best
+		collisionMatch("pm2", "K2", 0.90), // K2 already taken by pm1
+		collisionMatch("pm2", "K3", 0.85),
+	})
+
+	got := matchKeys(kept)
+	want := []string{"pm1/K2", "pm2/K3"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("kept = %v, want %v", got, want)
+	}
+	if len(collisions) != 2 {
+		t.Fatalf("collisions = %d, want 2", len(collisions))
+	}
+	// Reported best score first
+	if c := collisions[0]; c.Side != SideKalshi || c.Suppressed.PM.ConditionID != "pm2" || c.KeptBy.PM.ConditionID != "pm1" {
+		t.Errorf("first collision = %+v, want pm2/K2 suppressed by pm1/K2 on kalshi", c)
+	}
+	if c := collisions[1]; c.Side != SidePolymarket || c.Suppressed.Kalshi.Ticker != "K1" || c.KeptBy.Kalshi.Ticker != "K2" {
+		t.Errorf("second collision = %+v, want pm1/K1 suppressed by pm1/K2 on polymarket", c)
+	}
+}
+
+func TestResolverAllowance(t *testing.T) {
+	r := NewResolver(2, 0)
+	kept, collisions := r.Resolve([]MarketMatch{
+		collisionMatch("pm1", "K1", 0.9),
+		collisionMatch("pm1", "K2", 0.8),
+		collisionMatch("pm1", "K3", 0.7),
+		collisionMatch("pm2", "K1", 0.6), // Kalshi unlimited
+	})
+	if len(kept) != 3 || len(collisions) != 1 || collisions[0].Suppressed.Kalshi.Ticker != "K3" {
+		t.Errorf("kept %v, collisions %d; want pm1/K3 suppressed only", matchKeys(kept), len(collisions))
+	}
+}
+
+func TestResolverAcrossBatches(t *testing.T) {
+	r := NewResolver(1, 1)
+	if kept, _ := r.Resolve([]MarketMatch{collisionMatch("pm1", "K1", 0.7)}); len(kept) != 1 {
+		t.Fatalf("first batch kept %d, want 1", len(kept))
+	}
+	// A better match later does not displace one already monitored
+	kept, collisions := r.Resolve([]MarketMatch{collisionMatch("pm1", "K2", 0.99)})
+	if len(kept) != 0 || len(collisions) != 1 || collisions[0].KeptBy.Kalshi.Ticker != "K1" {
+		t.Errorf("second batch kept %v, collisions %+v; want it suppressed by pm1/K1", matchKeys(kept), collisions)
+	}
+}
+
+func TestResolverNil(t *testing.T) {
+	var r *Resolver
+	matches := []MarketMatch{collisionMatch("pm1", "K1", 0.9), collisionMatch("pm1", "K2", 0.8)}
+	if kept, collisions := r.Resolve(matches); len(kept) != 2 || collisions != nil {
+		t.Errorf("nil resolver kept %d with %d collisions, want everything", len(kept), len(collisions))
+	}
+}
@@ -0,0 +1,98 @@
+package match
+
+import (
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// Deadlines are a market's two distinct deadlines, in UTC. Zero values are
+// unknown.
+//
+// The venues report them differently. Kalshi's close_time is when trading
+// stops and expected_expiration_time when the market is expected to resolve,
+// which can be days later (expiration_time is only the latest possible
+// resolution). Polymarket's end_date_iso is the expected resolution date;
+// its markets trade until resolved, except sports markets, which stop at
+// game_start_time.
+type Deadlines struct {
+	Close      time.Time // Trading stops
+	Resolution time.Time // Outcome expected to be determined
+}
+
+// Zone-less timestamps are read in the venue's home zone. Kalshi's is
+// loaded on first use so binaries embedding time/tzdata have it registered.
+var (
+	polymarketZone = time.UTC
+	kalshiZone     = sync.OnceValue(func() *time.Location {
+		loc, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			return time.FixedZone("EST", -5*60*60)
+		}
+		return loc
+	})
+)
+
+// PolymarketDeadlines returns a Polymarket market's deadlines
+func PolymarketDeadlines(pm ws.PolymarketMarket) Deadlines {
+	return Deadlines{
+		Close:      ParseDeadline(pm.GameStartTime, polymarketZone),
+		Resolution: ParseDeadline(pm.EndDateISO, polymarketZone),
+	}
+}
+
+// KalshiDeadlines returns a Kalshi market's deadlines
+func KalshiDeadlines(k ws.KalshiMarket) Deadlines {
+	resolution := ParseDeadline(k.ExpectedExpirationTime, kalshiZone())
+	if resolution.IsZero() {
+		resolution = ParseDeadline(k.ExpirationTime, kalshiZone())
+	}
+	return Deadlines{
+		Close:      ParseDeadline(k.CloseTime, kalshiZone()),
+		Resolution: resolution,
+	}
+}
+
+// ParseDeadline parses a venue timestamp to UTC. It accepts RFC 3339 (also
+// with a space for the T), a timestamp without zone, read in loc, and a bare
+// date, taken as the end of that day in loc. It returns the zero time when s
+// is empty or unparseable.
+func ParseDeadline(s string, loc *time.Location) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05Z07:00"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.UTC()
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, loc); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second).UTC()
+	}
+	return time.Time{}
+}
+
+// DeadlinesWithin reports whether two markets' deadlines agree within
+// window. Only like deadlines are compared, trading close with trading close
+// and resolution with resolution, and only when both venues report them.
+func DeadlinesWithin(a, b Deadlines, window time.Duration) bool {
+	return within(a.Close, b.Close, window) && within(a.Resolution, b.Resolution, window)
+}
+
+// within reports whether two times are at most window apart, or either is unknown
+func within(a, b time.Time, window time.Duration) bool {
+	if a.IsZero() || b.IsZero() {
+		return true
+	}
+	diff := a.Sub(b)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= window
+}
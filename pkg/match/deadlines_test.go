@@ -0,0 +1,63 @@
+package match
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestParseDeadline(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata")
+	}
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-03-20T12:00:00Z", time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)},
+		{"2026-03-20T08:00:00-04:00", time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)},
+		{"2026-03-20 12:00:00+00:00", time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)},
+		{"2026-03-20T08:00:00", time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)},
+		{"2026-03-20", time.Date(2026, 3, 21, 3, 59, 59, 0, time.UTC)},
+		{"", time.Time{}},
+		{"soon", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseDeadline(tt.in, ny); !got.Equal(tt.want) {
+			t.Errorf("ParseDeadline(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDeadlinesCompareLikeWithLike(t *testing.T) {
+	// Kalshi stops trading a week before the expected resolution; Polymarket
+	// trades until it resolves on the same day Kalshi expects to
+	pm := PolymarketDeadlines(ws.PolymarketMarket{EndDateISO: "2026-03-20T00:00:00Z"})
+	k := KalshiDeadlines(ws.KalshiMarket{
+		CloseTime:              "2026-03-13T00:00:00Z",
+		ExpectedExpirationTime: "2026-03-20T04:00:00Z",
+		ExpirationTime:         "2026-04-30T00:00:00Z",
+	})
+
+	if !pm.Close.IsZero() {
+		t.Errorf("polymarket market without a game start has no close, got %v", pm.Close)
+	}
+	if !DeadlinesWithin(pm, k, 24*time.Hour) {
+		t.Errorf("expected resolutions a few hours apart to match, got %+v and %+v", pm, k)
+	}
+
+	later := KalshiDeadlines(ws.KalshiMarket{CloseTime: "2026-03-13T00:00:00Z", ExpectedExpirationTime: "2026-06-20T00:00:00Z"})
+	if DeadlinesWithin(pm, later, 24*time.Hour) {
+		t.Error("expected resolutions months apart must not match")
+	}
+}
+
+func TestKalshiDeadlinesFallBackToLatestExpiration(t *testing.T) {
+	k := KalshiDeadlines(ws.KalshiMarket{ExpirationTime: "2026-04-30T00:00:00Z"})
+	if !k.Resolution.Equal(time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("resolution = %v, want expiration_time", k.Resolution)
+	}
+}
@@ -0,0 +1,5 @@
+// Package match decides which Polymarket and Kalshi markets ask the same
+// question. Titles are normalized, tokenized and compared by Jaccard
+// similarity (TitleSimilarity); MatchMarkets applies that to whole catalogs,
+// as fetched by package catalog.
+package match
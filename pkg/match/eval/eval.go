@@ -0,0 +1,181 @@
+// Package eval measures matcher quality against a labeled ground-truth
+// dataset of Polymarket/Kalshi title pairs, reporting precision and recall
+// at a range of similarity thresholds so matcher changes can be compared
+// quantitatively.
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/match"
+)
+
+// Example is one labeled pair: Match is true when both markets resolve on
+// the same event
+type Example struct {
+	PMTitle     string `json:"pm_title"`
+	KalshiTitle string `json:"kalshi_title"`
+	Match       bool   `json:"match"`
+	Note        string `json:"note,omitempty"`
+}
+
+// Scorer returns the similarity of two titles in [0, 1]
+type Scorer func(pmTitle, kalshiTitle string) float64
+
+// DefaultScorer is the matcher used by the server's bootstrap
+var DefaultScorer Scorer = match.TitleSimilarity
+
+// DefaultThresholds are the thresholds reported when none are given
+var DefaultThresholds = []float64{0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+// Result summarizes matcher quality at one threshold
+type Result struct {
+	Threshold      float64 `json:"threshold"`
+	TruePositives  int     `json:"true_positives"`
+	FalsePositives int     `json:"false_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	TrueNegatives  int     `json:"true_negatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+}
+
+// Scored is an example with the score the matcher gave it
+type Scored struct {
+	Example
+	Score float64 `json:"score"`
+}
+
+// Report is the outcome of evaluating a dataset
+type Report struct {
+	Examples int      `json:"examples"`
+	Positive int      `json:"positive"`
+	Results  []Result `json:"results"`
+	Scored   []Scored `json:"-"`
+}
+
+// Load reads a JSON-lines dataset of examples from path
+func Load(path string) ([]Example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open dataset: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read parses a JSON-lines dataset, skipping blank lines
+func Read(r io.Reader) ([]Example, error) {
+	var examples []Example
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ex Example
+		if err := json.Unmarshal(scanner.Bytes(), &ex); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		examples = append(examples, ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read dataset: %w", err)
+	}
+	return examples, nil
+}
+
+// Evaluate scores every example once and computes precision/recall at each
+// threshold; a pair is predicted to match when its score is >= threshold
+func Evaluate(examples []Example, scorer Scorer, thresholds []float64) Report {
+	if scorer == nil {
+		scorer = DefaultScorer
+	}
+	if len(thresholds) == 0 {
+		thresholds = DefaultThresholds
+	}
+
+	report := Report{Examples: len(examples), Scored: make([]Scored, 0, len(examples))}
+	for _, ex := range examples {
+		if ex.Match {
+			report.Positive++
+		}
+		report.Scored = append(report.Scored, Scored{Example: ex, Score: scorer(ex.PMTitle, ex.KalshiTitle)})
+	}
+
+	sorted := append([]float64(nil), thresholds...)
+	sort.Float64s(sorted)
+	for _, th := range sorted {
+		res := Result{Threshold: th}
+		for _, s := range report.Scored {
+			predicted := s.Score >= th
+			switch {
+			case predicted && s.Match:
+				res.TruePositives++
+			case predicted && !s.Match:
+				res.FalsePositives++
+			case !predicted && s.Match:
+				res.FalseNegatives++
+			default:
+				res.TrueNegatives++
+			}
+		}
+		res.Precision = ratio(res.TruePositives, res.TruePositives+res.FalsePositives)
+		res.Recall = ratio(res.TruePositives, res.TruePositives+res.FalseNegatives)
+		if res.Precision+res.Recall > 0 {
+			res.F1 = 2 * res.Precision * res.Recall / (res.Precision + res.Recall)
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+// Errors returns the examples misclassified at threshold, worst first
+func (r Report) Errors(threshold float64) []Scored {
+	var errs []Scored
+	for _, s := range r.Scored {
+		if (s.Score >= threshold) != s.Match {
+			errs = append(errs, s)
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return distance(errs[i], threshold) > distance(errs[j], threshold)
+	})
+	return errs
+}
+
+// Best returns the result with the highest F1
+func (r Report) Best() (Result, bool) {
+	if len(r.Results) == 0 {
+		return Result{}, false
+	}
+	best := r.Results[0]
+	for _, res := range r.Results[1:] {
+		if res.F1 > best.F1 {
+			best = res
+		}
+	}
+	return best, true
+}
+
+// distance is how far a misclassified example's score is from the threshold
+func distance(s Scored, threshold float64) float64 {
+	d := s.Score - threshold
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func ratio(num, den int) float64 {
+	if den == 0 {
+		return 0
+	}
+	return float64(num) / float64(den)
+}
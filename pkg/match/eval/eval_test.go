@@ -0,0 +1,71 @@
+package eval
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateCounts(t *testing.T) {
+	examples := []Example{
+		{PMTitle: "a", KalshiTitle: "a", Match: true},
+		{PMTitle: "b", KalshiTitle: "b", Match: true},
+		{PMTitle: "c", KalshiTitle: "c", Match: false},
+		{PMTitle: "d", KalshiTitle: "d", Match: false},
+	}
+	scores := map[string]float64{"a": 0.9, "b": 0.4, "c": 0.8, "d": 0.1}
+	scorer := func(pm, _ string) float64 { return scores[pm] }
+
+	report := Evaluate(examples, scorer, []float64{0.5})
+	if report.Examples != 4 || report.Positive != 2 {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	res := report.Results[0]
+	if res.TruePositives != 1 || res.FalsePositives != 1 || res.FalseNegatives != 1 || res.TrueNegatives != 1 {
+		t.Errorf("unexpected confusion matrix: %+v", res)
+	}
+	if res.Precision != 0.5 || res.Recall != 0.5 || res.F1 != 0.5 {
+		t.Errorf("expected precision/recall/F1 of 0.5, got %+v", res)
+	}
+
+	errs := report.Errors(0.5)
+	if len(errs) != 2 || errs[0].PMTitle != "c" {
+		t.Errorf("expected errors ordered by distance from threshold, got %+v", errs)
+	}
+}
+
+func TestEvaluateThresholdsSorted(t *testing.T) {
+	report := Evaluate([]Example{{PMTitle: "x", KalshiTitle: "x", Match: true}}, nil, []float64{0.9, 0.1, 0.5})
+	for i := 1; i < len(report.Results); i++ {
+		if report.Results[i].Threshold < report.Results[i-1].Threshold {
+			t.Fatalf("results not sorted by threshold: %+v", report.Results)
+		}
+	}
+	if report.Results[0].Recall != 1 {
+		t.Errorf("identical titles should match at every threshold, got %+v", report.Results[0])
+	}
+}
+
+func TestReadRejectsMalformedLine(t *testing.T) {
+	_, err := Read(strings.NewReader("{\"pm_title\":\"a\"}\n\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("expected error naming line 3, got %v", err)
+	}
+}
+
+func TestGroundTruthDataset(t *testing.T) {
+	examples, err := Load("testdata/pairs.jsonl")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	report := Evaluate(examples, nil, nil)
+	if report.Positive == 0 || report.Positive == report.Examples {
+		t.Fatalf("dataset needs both matches and non-matches, got %d/%d", report.Positive, report.Examples)
+	}
+
+	best, ok := report.Best()
+	if !ok || math.IsNaN(best.F1) {
+		t.Fatalf("no best result: %+v", report.Results)
+	}
+	t.Logf("best threshold %.2f: precision %.2f recall %.2f F1 %.2f", best.Threshold, best.Precision, best.Recall, best.F1)
+}
@@ -0,0 +1,136 @@
+package match
+
+import (
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// MarketMatch is a Polymarket market and a Kalshi market judged to be the
+// same question
+type MarketMatch struct {
+	PM         ws.PolymarketMarket
+	Kalshi     ws.KalshiMarket
+	PMTokenYes string
+	PMTokenNo  string
+	Score      float64 // Title similarity, 0-1
+	Inverted   bool    // The questions negate each other, see Inverted
+}
+
+// MatchOptions tunes MatchMarkets
+type MatchOptions struct {
+	Threshold float64 // Minimum title similarity
+	// TimeWindow is the largest allowed gap between the two markets'
+	// trading close and between their resolution, each only checked when
+	// both venues report it (see DeadlinesWithin)
+	TimeWindow time.Duration
+	// NearMissFloor, when below Threshold, makes MatchMarketsWithNearMisses
+	// also report pairs scoring in [NearMissFloor, Threshold)
+	NearMissFloor float64
+}
+
+// MatchMarkets returns every pair of markets whose titles are at least
+// opts.Threshold similar and whose deadlines are within opts.TimeWindow.
+// Titles are compared with the pair's category vocabulary, if any (see
+// SetVocabularies).
+// Polymarket markets without both a YES and a NO token are skipped.
+func MatchMarkets(pmMarkets []ws.PolymarketMarket, kalshiMarkets []ws.KalshiMarket, opts MatchOptions) []MarketMatch {
+	opts.NearMissFloor = 0
+	matches, _ := MatchMarketsWithNearMisses(pmMarkets, kalshiMarkets, opts)
+	return matches
+}
+
+// MatchMarketsWithNearMisses is MatchMarkets that also returns the pairs
+// scoring at least opts.NearMissFloor but below opts.Threshold, subject to
+// the same deadline check, for manual review
+func MatchMarketsWithNearMisses(pmMarkets []ws.PolymarketMarket, kalshiMarkets []ws.KalshiMarket, opts MatchOptions) (matches, nearMisses []MarketMatch) {
+	matches = make([]MarketMatch, 0)
+	floor := opts.Threshold
+	if opts.NearMissFloor > 0 && opts.NearMissFloor < floor {
+		floor = opts.NearMissFloor
+	}
+
+	vocab := vocabularies.Load()
+	for _, pm := range pmMarkets {
+		yes, no, ok := YesNoTokens(pm)
+		if !ok {
+			continue
+		}
+		pmDeadlines := PolymarketDeadlines(pm)
+
+		for _, k := range kalshiMarkets {
+			var similarity float64
+			if vocab.Len() > 0 {
+				similarity = vocab.For(Category(pm, k)).Similarity(pm.Question, k.Title)
+			} else {
+				similarity = TitleSimilarity(pm.Question, k.Title)
+			}
+			if similarity < floor {
+				continue
+			}
+
+			if !DeadlinesWithin(pmDeadlines, KalshiDeadlines(k), opts.TimeWindow) {
+				continue // Deadlines too far apart
+			}
+
+			m := MarketMatch{
+				PM:         pm,
+				Kalshi:     k,
+				PMTokenYes: yes,
+				PMTokenNo:  no,
+				Score:      similarity,
+				Inverted:   Inverted(pm.Question, k.Title),
+			}
+			if similarity < opts.Threshold {
+				nearMisses = append(nearMisses, m)
+			} else {
+				matches = append(matches, m)
+			}
+		}
+	}
+
+	return matches, nearMisses
+}
+
+// YesNoTokens returns the token IDs of a Polymarket market's YES and NO outcomes
+func YesNoTokens(pm ws.PolymarketMarket) (yes, no string, ok bool) {
+	for _, token := range pm.Tokens {
+		switch token.Outcome {
+		case "YES":
+			yes = token.TokenID
+		case "NO":
+			no = token.TokenID
+		}
+	}
+	return yes, no, yes != "" && no != ""
+}
+
+// SmarketsMatch is a Smarkets contract and the Kalshi market judged to be the
+// same question
+type SmarketsMatch struct {
+	Smarkets ws.SmarketsContract
+	Kalshi   ws.KalshiMarket
+	Score    float64 // Title similarity, 0-1
+}
+
+// MatchSmarkets pairs each Smarkets contract with its most similar Kalshi
+// market, if that market's title is at least threshold similar. Expirations
+// are not compared: Smarkets events carry a start time, not a close time.
+func MatchSmarkets(contracts []ws.SmarketsContract, kalshiMarkets []ws.KalshiMarket, threshold float64) []SmarketsMatch {
+	matches := make([]SmarketsMatch, 0)
+
+	for _, c := range contracts {
+		title := c.Title()
+		best := SmarketsMatch{Smarkets: c}
+		for _, k := range kalshiMarkets {
+			if similarity := TitleSimilarity(title, k.Title); similarity > best.Score {
+				best.Kalshi, best.Score = k, similarity
+			}
+		}
+		if best.Score > 0 && best.Score >= threshold {
+			matches = append(matches, best)
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,78 @@
+package match
+
+import (
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestMatchMarkets(t *testing.T) {
+	tokens := []ws.PMToken{{TokenID: "y", Outcome: "YES"}, {TokenID: "n", Outcome: "NO"}}
+	pm := []ws.PolymarketMarket{
+		{Question: "Will the Fed cut rates in March?", Tokens: tokens, EndDateISO: "2026-03-20T00:00:00Z"},
+		{Question: "Will the Fed cut rates in March?", Tokens: tokens[:1]}, // No NO token
+	}
+	kalshi := []ws.KalshiMarket{
+		{Ticker: "A", Title: "Will the Fed cut rates in March?", ExpirationTime: "2026-03-21T00:00:00Z"},
+		{Ticker: "B", Title: "Will the Fed cut rates in March?", ExpirationTime: "2026-06-21T00:00:00Z"},
+		{Ticker: "C", Title: "Who wins the World Series?"},
+	}
+
+	matches := MatchMarkets(pm, kalshi, MatchOptions{Threshold: 0.6, TimeWindow: 7 * 24 * time.Hour})
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %+v", matches)
+	}
+	m := matches[0]
+	if m.Kalshi.Ticker != "A" || m.PMTokenYes != "y" || m.PMTokenNo != "n" || m.Score < 0.99 || m.Inverted {
+		t.Errorf("unexpected match %+v", m)
+	}
+
+	kalshi = []ws.KalshiMarket{{Ticker: "D", Title: "Will the Fed not cut rates in March?", ExpirationTime: "2026-03-21T00:00:00Z"}}
+	matches = MatchMarkets(pm, kalshi, MatchOptions{Threshold: 0.6, TimeWindow: 7 * 24 * time.Hour})
+	if len(matches) != 1 || !matches[0].Inverted {
+		t.Errorf("expected an inverted match, got %+v", matches)
+	}
+}
+
+func TestMatchSmarkets(t *testing.T) {
+	contracts := []ws.SmarketsContract{
+		{ID: "1", MarketName: "Will Keir Starmer remain Prime Minister through 2026?", Name: "Yes"},
+		{ID: "2", MarketName: "Eurovision winner", Name: "Sweden"},
+	}
+	kalshi := []ws.KalshiMarket{
+		{Ticker: "A", Title: "Who wins the World Series?"},
+		{Ticker: "B", Title: "Will Keir Starmer remain Prime Minister through 2026?"},
+	}
+
+	matches := MatchSmarkets(contracts, kalshi, 0.6)
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %+v", matches)
+	}
+	if matches[0].Smarkets.ID != "1" || matches[0].Kalshi.Ticker != "B" {
+		t.Errorf("unexpected match %+v", matches[0])
+	}
+}
+
+func TestMatchMarketsWithNearMisses(t *testing.T) {
+	tokens := []ws.PMToken{{TokenID: "y", Outcome: "YES"}, {TokenID: "n", Outcome: "NO"}}
+	pm := []ws.PolymarketMarket{{Question: "Will the Fed cut rates in March?", Tokens: tokens}}
+	kalshi := []ws.KalshiMarket{
+		{Ticker: "A", Title: "Will the Fed cut rates in March?"},
+		{Ticker: "B", Title: "Fed cut rates March 2026 meeting decision"},
+		{Ticker: "C", Title: "Who wins the World Series?"},
+	}
+
+	near := TitleSimilarity(pm[0].Question, kalshi[1].Title)
+	matches, nearMisses := MatchMarketsWithNearMisses(pm, kalshi, MatchOptions{Threshold: near + 0.01, NearMissFloor: near - 0.01})
+	if len(matches) != 1 || matches[0].Kalshi.Ticker != "A" {
+		t.Fatalf("unexpected matches %+v", matches)
+	}
+	if len(nearMisses) != 1 || nearMisses[0].Kalshi.Ticker != "B" {
+		t.Fatalf("unexpected near-misses %+v", nearMisses)
+	}
+
+	if _, none := MatchMarketsWithNearMisses(pm, kalshi, MatchOptions{Threshold: near + 0.01}); len(none) != 0 {
+		t.Errorf("expected no near-misses without a floor, got %+v", none)
+	}
+}
@@ -0,0 +1,96 @@
+package match
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// Oracles settling each venue's markets. Polymarket markets are settled
+// through UMA's optimistic oracle, where a proposed outcome stands unless
+// disputed and disputes go to a vote of UMA holders; Kalshi settles its
+// markets itself from the sources its rules name.
+const (
+	OracleUMA    = "uma"
+	OracleKalshi = "kalshi"
+)
+
+// Resolution is how a market settles: its oracle and the data sources its
+// rules cite. Two markets asking the same question can still settle apart
+// when they read different sources, say a network's race call against the
+// certified result.
+type Resolution struct {
+	Oracle  string
+	Sources []string // Canonical names of the cited sources, sorted; nil when none is recognized
+}
+
+// resolutionSources maps the sources markets commonly settle on to a
+// canonical name, by the names and domains rules refer to them with
+var resolutionSources = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	// The bare abbreviation in capitals only, as "ap" turns up in URL paths
+	{"ap", regexp.MustCompile(`\bAP\b|(?i:\bassociated press\b|\bapnews\.com\b)`)},
+	{"bls", regexp.MustCompile(`(?i)\bbureau of labor statistics\b|\bBLS\b|\bbls\.gov\b`)},
+	{"bea", regexp.MustCompile(`(?i)\bbureau of economic analysis\b|\bBEA\b|\bbea\.gov\b`)},
+	{"federal_reserve", regexp.MustCompile(`(?i)\bfederal reserve\b|\bFOMC\b|\bfederalreserve\.gov\b`)},
+	{"noaa", regexp.MustCompile(`(?i)\bnational weather service\b|\bNOAA\b|\bNWS\b|\bweather\.gov\b`)},
+	{"binance", regexp.MustCompile(`(?i)\bbinance\b`)},
+	{"coinbase", regexp.MustCompile(`(?i)\bcoinbase\b`)},
+	{"coingecko", regexp.MustCompile(`(?i)\bcoingecko\b`)},
+	{"coinmarketcap", regexp.MustCompile(`(?i)\bcoinmarketcap\b`)},
+	{"cf_benchmarks", regexp.MustCompile(`(?i)\bcf benchmarks\b|\bcfbenchmarks\b`)},
+	{"chainlink", regexp.MustCompile(`(?i)\bchainlink\b`)},
+	{"espn", regexp.MustCompile(`(?i)\bespn\b`)},
+	{"decision_desk", regexp.MustCompile(`(?i)\bdecision desk\b|\bDDHQ\b`)},
+	{"fox_news", regexp.MustCompile(`(?i)\bfox news\b`)},
+	{"nbc", regexp.MustCompile(`(?i)\bNBC\b`)},
+	{"cnn", regexp.MustCompile(`(?i)\bCNN\b`)},
+	{"box_office_mojo", regexp.MustCompile(`(?i)\bbox office mojo\b|\bboxofficemojo\.com\b`)},
+	{"rotten_tomatoes", regexp.MustCompile(`(?i)\brotten tomatoes\b|\brottentomatoes\.com\b`)},
+	{"billboard", regexp.MustCompile(`(?i)\bbillboard\b`)},
+	{"x", regexp.MustCompile(`(?i)\btwitter\.com\b|\bx\.com\b`)},
+}
+
+// PolymarketResolution returns how a Polymarket market settles, from the
+// sources its description cites
+func PolymarketResolution(pm ws.PolymarketMarket) Resolution {
+	return Resolution{Oracle: OracleUMA, Sources: CitedSources(pm.Description)}
+}
+
+// KalshiResolution returns how a Kalshi market settles, from the sources its
+// rules cite
+func KalshiResolution(k ws.KalshiMarket) Resolution {
+	return Resolution{Oracle: OracleKalshi, Sources: CitedSources(k.Rules())}
+}
+
+// CitedSources returns the canonical names of the resolution sources a
+// market's rules cite, sorted
+func CitedSources(rules string) []string {
+	var sources []string
+	for _, s := range resolutionSources {
+		if s.pattern.MatchString(rules) {
+			sources = append(sources, s.name)
+		}
+	}
+	slices.Sort(sources)
+	return sources
+}
+
+// ResolutionSourcesDiffer reports whether two markets settle on different
+// sources: both cite a recognized source and none in common. Markets whose
+// rules cite nothing recognized are not flagged, since nothing is known
+// about them.
+func ResolutionSourcesDiffer(a, b Resolution) bool {
+	if len(a.Sources) == 0 || len(b.Sources) == 0 {
+		return false
+	}
+	for _, s := range a.Sources {
+		if slices.Contains(b.Sources, s) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestCitedSources(t *testing.T) {
+	tests := []struct {
+		rules string
+		want  []string
+	}{
+		{"This market resolves per the race call of the Associated Press.", []string{"ap"}},
+		{"Resolves Yes if AP, Fox News and NBC all call the race.", []string{"ap", "fox_news", "nbc"}},
+		{"The resolution source is https://www.bls.gov/cpi/.", []string{"bls"}},
+		{"Resolves on the Binance BTC/USDT 1 minute candle.", []string{"binance"}},
+		{"Settles on the official price from the map at /apps/prices.", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := CitedSources(tt.rules); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CitedSources(%q) = %v, want %v", tt.rules, got, tt.want)
+		}
+	}
+}
+
+func TestResolutionSourcesDiffer(t *testing.T) {
+	pm := PolymarketResolution(ws.PolymarketMarket{Description: "The resolution source for this market will be Decision Desk HQ."})
+	if pm.Oracle != OracleUMA {
+		t.Errorf("Polymarket oracle = %q", pm.Oracle)
+	}
+
+	tests := []struct {
+		rules string
+		want  bool
+	}{
+		{"The market settles on the call of the Associated Press.", true},
+		{"Settles on the call of the AP or Decision Desk HQ.", false},
+		{"Settles on the certified result.", false}, // Nothing recognized
+	}
+	for _, tt := range tests {
+		k := KalshiResolution(ws.KalshiMarket{RulesPrimary: tt.rules})
+		if k.Oracle != OracleKalshi {
+			t.Errorf("Kalshi oracle = %q", k.Oracle)
+		}
+		if got := ResolutionSourcesDiffer(pm, k); got != tt.want {
+			t.Errorf("ResolutionSourcesDiffer(%v, %v) = %v, want %v", pm.Sources, k.Sources, got, tt.want)
+		}
+	}
+}
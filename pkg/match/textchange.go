@@ -0,0 +1,83 @@
+package match
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// MaterialSimilarity is the word overlap below which an edit counts as a
+// rewording rather than a cosmetic fix
+const MaterialSimilarity = 0.8
+
+// negations flip a question's meaning when added or removed
+var negations = map[string]struct{}{
+	"not": {}, "no": {}, "never": {}, "without": {}, "fail": {}, "fails": {},
+	"unless": {}, "except": {}, "excluding": {},
+}
+
+// TextChange classifies an edit to a market's question or rules
+type TextChange struct {
+	Changed    bool    // The text differs after normalization
+	Material   bool    // The edit may change what the market resolves on
+	Reason     string  // Why the edit is material, e.g. "numbers changed"
+	Similarity float64 // Word overlap of the two versions, 1 when unchanged
+}
+
+// CompareText classifies an edit from before to after. Changes in case,
+// punctuation and number or date formatting are ignored. An edit is material
+// when it changes the numbers or dates mentioned, adds or removes a negation,
+// or rewords the text below MaterialSimilarity.
+func CompareText(before, after string) TextChange {
+	normBefore, normAfter := NormalizeTitle(before), NormalizeTitle(after)
+	if normBefore == normAfter {
+		return TextChange{Similarity: 1}
+	}
+
+	wordsBefore, wordsAfter := Tokenize(normBefore), Tokenize(normAfter)
+	c := TextChange{Changed: true, Similarity: JaccardSimilarity(wordsBefore, wordsAfter)}
+	switch {
+	case !slices.Equal(numericTokens(wordsBefore), numericTokens(wordsAfter)):
+		c.Material, c.Reason = true, "numbers changed"
+	case countNegations(wordsBefore) != countNegations(wordsAfter):
+		c.Material, c.Reason = true, "negation changed"
+	case c.Similarity < MaterialSimilarity:
+		c.Material, c.Reason = true, "reworded"
+	}
+	return c
+}
+
+// numericTokens returns the sorted tokens containing a digit
+func numericTokens(tokens []string) []string {
+	out := make([]string, 0)
+	for _, t := range tokens {
+		if strings.IndexFunc(t, unicode.IsDigit) >= 0 {
+			out = append(out, t)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// countNegations returns the number of negating words. Normalization
+// splits contractions, so "won't" arrives as "won" "t".
+func countNegations(tokens []string) int {
+	n := 0
+	for i, t := range tokens {
+		if _, ok := negations[t]; ok {
+			n++
+		} else if t == "t" && i > 0 && strings.HasSuffix(tokens[i-1], "n") {
+			n++
+		}
+	}
+	return n
+}
+
+// Inverted reports whether two titles ask opposite questions, one carrying
+// a negation the other lacks ("Will X happen?" and "Will X not happen?").
+// Only the parity counts, so a double negative reads as none.
+func Inverted(title1, title2 string) bool {
+	n1 := countNegations(Tokenize(NormalizeTitle(title1)))
+	n2 := countNegations(Tokenize(NormalizeTitle(title2)))
+	return n1%2 != n2%2
+}
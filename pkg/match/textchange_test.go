@@ -0,0 +1,89 @@
+package match
+
+import "testing"
+
+func TestCompareText(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		changed  bool
+		material bool
+		reason   string
+	}{
+		{
+			name:   "identical",
+			before: "Will BTC close above $100K on Dec 31, 2026?",
+			after:  "Will BTC close above $100K on Dec 31, 2026?",
+		},
+		{
+			name:   "formatting only",
+			before: "Will BTC close above $100K on Dec 31, 2026?",
+			after:  "will btc close above 100,000 on December 31 2026",
+		},
+		{
+			name:     "threshold moved",
+			before:   "Will BTC close above $100K on Dec 31, 2026?",
+			after:    "Will BTC close above $110K on Dec 31, 2026?",
+			changed:  true,
+			material: true,
+			reason:   "numbers changed",
+		},
+		{
+			name:     "date moved",
+			before:   "Will BTC close above $100K on Dec 31, 2026?",
+			after:    "Will BTC close above $100K on Jan 31, 2027?",
+			changed:  true,
+			material: true,
+			reason:   "numbers changed",
+		},
+		{
+			name:     "negated",
+			before:   "Will the bill pass the Senate in 2026?",
+			after:    "Will the bill not pass the Senate in 2026?",
+			changed:  true,
+			material: true,
+			reason:   "negation changed",
+		},
+		{
+			name:    "minor wording",
+			before:  "Will the Federal Reserve cut interest rates at its March 2026 meeting?",
+			after:   "Will the Federal Reserve cut interest rates at the March 2026 meeting?",
+			changed: true,
+		},
+		{
+			name:     "different question",
+			before:   "Will the Federal Reserve cut interest rates in March 2026?",
+			after:    "Who will chair the Federal Reserve in March 2026?",
+			changed:  true,
+			material: true,
+			reason:   "reworded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareText(tt.before, tt.after)
+			if got.Changed != tt.changed || got.Material != tt.material || got.Reason != tt.reason {
+				t.Errorf("CompareText = %+v, want changed=%v material=%v reason=%q", got, tt.changed, tt.material, tt.reason)
+			}
+		})
+	}
+}
+
+func TestInverted(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want bool
+	}{
+		{"Will the bill pass the Senate in 2026?", "Will the bill pass the Senate in 2026?", false},
+		{"Will the bill pass the Senate in 2026?", "Will the bill not pass the Senate in 2026?", true},
+		{"Will the bill pass the Senate in 2026?", "Bill won't pass the Senate in 2026", true},
+		{"Will the bill fail to pass?", "Will the bill not fail to pass?", true},
+		{"Will the bill not fail to pass?", "Will the bill pass?", false}, // Double negative
+	} {
+		if got := Inverted(c.a, c.b); got != c.want {
+			t.Errorf("Inverted(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -1,35 +1,67 @@
 package match
 
 import (
-	"regexp"
 	"strings"
 	"unicode"
-)
 
-var (
-	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9\s]+`)
-	multiSpace      = regexp.MustCompile(`\s+`)
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
-// NormalizeTitle converts a title to lowercase, removes special characters,
-// and normalizes whitespace for consistent comparison.
+// folder applies Unicode case folding (e.g. "ß" → "ss", "Σ" → "σ")
+var folder = cases.Fold()
+
+// NormalizeTitle converts a title to a canonical form for comparison:
+// compatibility decomposition (NFKD), diacritic stripping on Latin, Greek
+// and Cyrillic letters, Unicode case folding, date and number
+// canonicalization (see CanonicalizeDates and CanonicalizeNumbers),
+// punctuation to spaces and whitespace collapsing. Letters and digits from any script are kept, so
+// "Müller" and "Muller" normalize alike and non-Latin titles survive intact.
 func NormalizeTitle(title string) string {
-	// Convert to lowercase
-	s := strings.ToLower(title)
-	// Remove non-alphanumeric except spaces
-	s = nonAlphanumeric.ReplaceAllString(s, " ")
-	// Normalize multiple spaces to single space
-	s = multiSpace.ReplaceAllString(s, " ")
-	// Trim leading/trailing whitespace
-	return strings.TrimSpace(s)
+	// Decompose so accents become separate combining marks, and fold
+	// compatibility forms (full-width digits, ligatures) to their plain form
+	s := norm.NFKD.String(title)
+	s = folder.String(s)
+	s = CanonicalizeNumbers(CanonicalizeDates(s))
+
+	runes := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	space := true       // Suppress leading spaces
+	alphabetic := false // Last letter was from a script whose marks are accents
+	for i, r := range runes {
+		switch {
+		case r == '.' && i > 0 && i+1 < len(runes) && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]):
+			// Keep decimal points so "2.5" stays one token
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Drop accents on alphabetic scripts; elsewhere (kana voicing
+			// marks, Indic vowel signs) the mark is part of the letter
+			if !alphabetic {
+				b.WriteRune(r)
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+			alphabetic = unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic)
+		case !space:
+			// Punctuation, symbols and whitespace all separate tokens
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+
+	// Recompose what remains so equivalent scripts compare byte-for-byte
+	return norm.NFC.String(strings.TrimRight(b.String(), " "))
 }
 
-// Tokenize splits a normalized title into words.
+// Tokenize splits a normalized title into words, expanding abbreviations
+// from the active dictionary (see SetAbbreviations).
 func Tokenize(s string) []string {
 	if s == "" {
 		return []string{}
 	}
-	return strings.Fields(s)
+	return active.Load().Expand(strings.Fields(s))
 }
 
 // JaccardSimilarity computes the Jaccard similarity coefficient between two token sets.
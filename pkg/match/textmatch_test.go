@@ -331,3 +331,38 @@ func BenchmarkNormalizeTitle(b *testing.B) {
 		NormalizeTitle(title)
 	}
 }
+
+func TestNormalizeTitleUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"french accents", "Will Macron résign before 2027?", "will macron resign before 2027"},
+		{"german umlaut", "Will Thomas Müller retire?", "will thomas muller retire"},
+		{"sharp s folds", "Straße", "strasse"},
+		{"spanish", "¿Ganará Sánchez las elecciones?", "ganara sanchez las elecciones"},
+		{"cyrillic", "Выборы в России 2024", "выборы в россии 2024"},
+		{"greek final sigma", "ΕΚΛΟΓΈΣ", "εκλογεσ"},
+		{"japanese", "東京オリンピック 2024", "東京オリンピック 2024"},
+		{"full-width digits", "Election ２０２４", "election 2024"},
+		{"ligature", "ﬁnal vote", "final vote"},
+		{"curly quotes", "Trump’s “big” win", "trump s big win"},
+		{"non-breaking space", "Will Biden win", "will biden win"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormalizeTitle(tt.input)
+			if result != tt.expected {
+				t.Errorf("NormalizeTitle(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTitleSimilarityAccents(t *testing.T) {
+	if sim := TitleSimilarity("Will Müller score?", "Will Muller score?"); sim != 1.0 {
+		t.Errorf("accented and unaccented titles should be identical, got %.2f", sim)
+	}
+}
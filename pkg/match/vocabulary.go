@@ -0,0 +1,146 @@
+package match
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+// Vocabulary tunes title comparison within one market category. Its stop
+// words carry no weight there, like "election" among political titles, and
+// its aliases expand like abbreviations, like team nicknames in sports.
+type Vocabulary struct {
+	stopWords map[string]struct{}
+	aliases   *Abbreviations
+}
+
+// Vocabularies holds the Vocabulary of each category that has one
+type Vocabularies struct {
+	byCategory map[string]*Vocabulary
+}
+
+// vocabularies are the vocabularies applied by CategorySimilarity; nil
+// compares every category alike
+var vocabularies atomic.Pointer[Vocabularies]
+
+// ParseVocabularies builds per-category vocabularies from stop words given
+// as "category:word" and aliases given as "category:alias=expansion".
+// Categories are matched case-insensitively, as Category reports them.
+func ParseVocabularies(stopWords, aliases []string) (*Vocabularies, error) {
+	v := &Vocabularies{byCategory: make(map[string]*Vocabulary)}
+	vocabulary := func(category string) *Vocabulary {
+		voc, ok := v.byCategory[category]
+		if !ok {
+			voc = &Vocabulary{stopWords: make(map[string]struct{})}
+			v.byCategory[category] = voc
+		}
+		return voc
+	}
+
+	for _, entry := range stopWords {
+		category, word, ok := cutCategory(entry)
+		if !ok {
+			return nil, fmt.Errorf("invalid stop word %q, want category:word", entry)
+		}
+		words := strings.Fields(NormalizeTitle(word))
+		if len(words) != 1 {
+			return nil, fmt.Errorf("stop word %q must be a single word", word)
+		}
+		vocabulary(category).stopWords[words[0]] = struct{}{}
+	}
+
+	aliasEntries := make(map[string]map[string]string)
+	for _, entry := range aliases {
+		category, alias, ok := cutCategory(entry)
+		name, expansion, hasExpansion := strings.Cut(alias, "=")
+		name, expansion = strings.TrimSpace(name), strings.TrimSpace(expansion)
+		if !ok || !hasExpansion || name == "" || expansion == "" {
+			return nil, fmt.Errorf("invalid alias %q, want category:alias=expansion", entry)
+		}
+		if len(strings.Fields(NormalizeTitle(name))) != 1 {
+			return nil, fmt.Errorf("alias %q must be a single word", name)
+		}
+		if aliasEntries[category] == nil {
+			aliasEntries[category] = make(map[string]string)
+		}
+		aliasEntries[category][name] = expansion
+	}
+	for category, entries := range aliasEntries {
+		vocabulary(category).aliases = NewAbbreviations(entries)
+	}
+	return v, nil
+}
+
+// cutCategory splits a "category:value" entry
+func cutCategory(entry string) (category, value string, ok bool) {
+	category, value, ok = strings.Cut(entry, ":")
+	category, value = strings.ToLower(strings.TrimSpace(category)), strings.TrimSpace(value)
+	return category, value, ok && category != "" && value != ""
+}
+
+// SetVocabularies replaces the vocabularies applied by CategorySimilarity;
+// nil compares every category alike
+func SetVocabularies(v *Vocabularies) {
+	vocabularies.Store(v)
+}
+
+// Len returns the number of categories with a vocabulary
+func (v *Vocabularies) Len() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.byCategory)
+}
+
+// For returns a category's vocabulary, nil when it has none
+func (v *Vocabularies) For(category string) *Vocabulary {
+	if v == nil {
+		return nil
+	}
+	return v.byCategory[strings.ToLower(category)]
+}
+
+// Tokens normalizes and tokenizes a title like Tokenize, then expands the
+// vocabulary's aliases and drops its stop words. A nil Vocabulary only
+// tokenizes.
+func (v *Vocabulary) Tokens(title string) []string {
+	tokens := Tokenize(NormalizeTitle(title))
+	if v == nil {
+		return tokens
+	}
+	tokens = v.aliases.Expand(tokens)
+	kept := tokens[:0]
+	for _, t := range tokens {
+		if _, stop := v.stopWords[t]; !stop {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// Similarity is TitleSimilarity within the vocabulary's category
+func (v *Vocabulary) Similarity(title1, title2 string) float64 {
+	return JaccardSimilarity(v.Tokens(title1), v.Tokens(title2))
+}
+
+// CategorySimilarity is TitleSimilarity using the category's vocabulary,
+// if SetVocabularies gave it one
+func CategorySimilarity(category, title1, title2 string) float64 {
+	return vocabularies.Load().For(category).Similarity(title1, title2)
+}
+
+// Category picks a category for a pair of markets, preferring Kalshi's
+// category and falling back to the first Polymarket tag, lowercased
+func Category(pm ws.PolymarketMarket, k ws.KalshiMarket) string {
+	if k.Category != "" {
+		return strings.ToLower(k.Category)
+	}
+	for _, tag := range pm.Tags {
+		if tag != "" && !strings.EqualFold(tag, "all") {
+			return strings.ToLower(tag)
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,96 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/ws"
+)
+
+func TestParseVocabularies(t *testing.T) {
+	v, err := ParseVocabularies(
+		[]string{"Politics:election", "politics:presidential"},
+		[]string{"sports:niners=San Francisco 49ers"},
+	)
+	if err != nil {
+		t.Fatalf("ParseVocabularies: %v", err)
+	}
+	if v.Len() != 2 {
+		t.Fatalf("Len = %d, want 2", v.Len())
+	}
+
+	got := v.For("POLITICS").Tokens("Who wins the 2028 presidential election?")
+	if want := []string{"who", "wins", "the", "2028"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("politics tokens = %v, want %v", got, want)
+	}
+	got = v.For("sports").Tokens("Will the Niners win?")
+	if want := []string{"will", "the", "san", "francisco", "49ers", "win"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sports tokens = %v, want %v", got, want)
+	}
+	if v.For("crypto") != nil {
+		t.Error("expected no vocabulary for crypto")
+	}
+
+	for _, bad := range [][2][]string{
+		{{"election"}, nil},
+		{{"politics:two words"}, nil},
+		{nil, {"sports:niners"}},
+		{nil, {"sports:san francisco=49ers"}},
+	} {
+		if _, err := ParseVocabularies(bad[0], bad[1]); err == nil {
+			t.Errorf("ParseVocabularies(%q, %q) succeeded, want error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestCategorySimilarity(t *testing.T) {
+	v, err := ParseVocabularies([]string{"politics:election", "politics:presidential"}, nil)
+	if err != nil {
+		t.Fatalf("ParseVocabularies: %v", err)
+	}
+	SetVocabularies(v)
+	defer SetVocabularies(nil)
+
+	a, b := "Presidential election winner Newsom", "Election winner Newsom"
+	if sim := CategorySimilarity("politics", a, b); sim != 1.0 {
+		t.Errorf("politics similarity = %.2f, want 1.0", sim)
+	}
+	if sim, plain := CategorySimilarity("sports", a, b), TitleSimilarity(a, b); sim != plain {
+		t.Errorf("sports similarity = %.2f, want the plain %.2f", sim, plain)
+	}
+}
+
+func TestMatchMarketsUsesCategoryVocabulary(t *testing.T) {
+	pm := []ws.PolymarketMarket{{
+		Question: "Presidential election winner Newsom",
+		Tokens:   []ws.PMToken{{TokenID: "y", Outcome: "YES"}, {TokenID: "n", Outcome: "NO"}},
+	}}
+	kalshi := []ws.KalshiMarket{{Ticker: "PRES", Title: "Winner Newsom", Category: "Politics"}}
+	opts := MatchOptions{Threshold: 0.9}
+
+	if got := MatchMarkets(pm, kalshi, opts); len(got) != 0 {
+		t.Fatalf("matched without a vocabulary: %+v", got)
+	}
+
+	v, err := ParseVocabularies([]string{"politics:election", "politics:presidential"}, nil)
+	if err != nil {
+		t.Fatalf("ParseVocabularies: %v", err)
+	}
+	SetVocabularies(v)
+	defer SetVocabularies(nil)
+	if got := MatchMarkets(pm, kalshi, opts); len(got) != 1 || got[0].Score != 1.0 {
+		t.Errorf("MatchMarkets = %+v, want one exact match", got)
+	}
+}
+
+func TestCategory(t *testing.T) {
+	if got := Category(ws.PolymarketMarket{Tags: []string{"Sports"}}, ws.KalshiMarket{Category: "Politics"}); got != "politics" {
+		t.Errorf("Category = %q, want Kalshi's", got)
+	}
+	if got := Category(ws.PolymarketMarket{Tags: []string{"All", "Sports"}}, ws.KalshiMarket{}); got != "sports" {
+		t.Errorf("Category = %q, want the first Polymarket tag", got)
+	}
+	if got := Category(ws.PolymarketMarket{}, ws.KalshiMarket{}); got != "other" {
+		t.Errorf("Category = %q, want other", got)
+	}
+}
@@ -0,0 +1,86 @@
+// Package price normalizes binary-market quotes from every venue into
+// comparable probabilities. Prices are in dollars per contract, 0-1.
+package price
+
+import "math"
+
+// ImpliedProbability returns the vig-free probability of YES implied by a
+// venue's YES and NO asks. The asks of a binary market sum to more than 1 by
+// the venue's overround; normalizing by that sum removes it proportionally.
+// ok is false when either ask is missing.
+func ImpliedProbability(yesAsk, noAsk float64) (p float64, ok bool) {
+	if yesAsk <= 0 || noAsk <= 0 {
+		return 0, false
+	}
+	return yesAsk / (yesAsk + noAsk), true
+}
+
+// Overround returns how far a venue's YES and NO asks sum above 1
+func Overround(yesAsk, noAsk float64) float64 {
+	return yesAsk + noAsk - 1
+}
+
+// NoFromYes derives the NO side of a venue that only quotes YES, as Kalshi's
+// ticker channel does: buying NO is selling YES, so the NO bid is 1 - YES ask
+// and the NO ask is 1 - YES bid
+func NoFromYes(yesBid, yesAsk float64) (noBid, noAsk float64) {
+	return 1 - yesAsk, 1 - yesBid
+}
+
+// FromCents converts a Kalshi amount in cents (prices, liquidity) to dollars
+func FromCents(cents float64) float64 {
+	return cents / 100
+}
+
+// FromDecimalOdds converts decimal (European) odds, as quoted by betting
+// exchanges such as Smarkets, to a price: a back bet at odds o returns o per
+// unit staked, so the stake buying a $1 payout is 1/o. ok is false for odds
+// at or below 1, which pay nothing beyond the stake.
+func FromDecimalOdds(odds float64) (p float64, ok bool) {
+	if odds <= 1 {
+		return 0, false
+	}
+	return 1 / odds, true
+}
+
+// KalshiTick is the price increment of Kalshi markets: whole cents
+const KalshiTick = 0.01
+
+// DefaultPolymarketTick is the price increment of Polymarket markets that do
+// not report theirs; others trade in 0.001
+const DefaultPolymarketTick = 0.01
+
+// Valid reports whether bid and ask are prices a venue can quote: each
+// within 0-1, where 0 is an empty side, and the bid not above the ask when
+// both sides are quoted. NaN and infinities are invalid.
+func Valid(bid, ask float64) bool {
+	if !(bid >= 0 && bid <= 1 && ask >= 0 && ask <= 1) {
+		return false
+	}
+	return bid == 0 || ask == 0 || bid <= ask
+}
+
+// tickEpsilon absorbs floating-point noise in prices already on a tick
+const tickEpsilon = 1e-9
+
+// CeilTick rounds a price up to the next multiple of tick, the best price a
+// buy order can be placed at to pay it. Prices already on a tick are kept.
+// A tick of 0 or less leaves the price unchanged.
+func CeilTick(p, tick float64) float64 {
+	if tick <= 0 {
+		return p
+	}
+	perDollar := math.Round(1 / tick)
+	return math.Ceil(p*perDollar-tickEpsilon) / perDollar
+}
+
+// FloorTick rounds a price down to the previous multiple of tick, the best
+// price a sell order can be placed at to receive it. A tick of 0 or less
+// leaves the price unchanged.
+func FloorTick(p, tick float64) float64 {
+	if tick <= 0 {
+		return p
+	}
+	perDollar := math.Round(1 / tick)
+	return math.Floor(p*perDollar+tickEpsilon) / perDollar
+}
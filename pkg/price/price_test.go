@@ -0,0 +1,94 @@
+package price
+
+import (
+	"math"
+	"testing"
+)
+
+func TestImpliedProbability(t *testing.T) {
+	tests := []struct {
+		name   string
+		yesAsk float64
+		noAsk  float64
+		want   float64
+		ok     bool
+	}{
+		{"no vig", 0.40, 0.60, 0.40, true},
+		{"symmetric vig", 0.52, 0.52, 0.50, true},
+		{"skewed vig", 0.63, 0.42, 0.60, true},
+		{"missing yes", 0, 0.5, 0, false},
+		{"missing no", 0.5, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ImpliedProbability(tt.yesAsk, tt.noAsk)
+			if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ImpliedProbability(%v, %v) = %v, %v; want %v, %v", tt.yesAsk, tt.noAsk, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestImpliedRemovesVenueVig(t *testing.T) {
+	// Same 50/50 market quoted with different overrounds: raw YES asks differ
+	// by 4 cents but the vig-free probabilities agree
+	pm, _ := ImpliedProbability(0.51, 0.51)
+	k, _ := ImpliedProbability(0.55, 0.55)
+	if math.Abs(pm-k) > 1e-9 {
+		t.Errorf("expected equal implied probabilities, got %v and %v", pm, k)
+	}
+	if o := Overround(0.55, 0.55); math.Abs(o-0.10) > 1e-9 {
+		t.Errorf("Overround = %v, want 0.10", o)
+	}
+}
+
+func TestNoFromYes(t *testing.T) {
+	noBid, noAsk := NoFromYes(0.40, 0.45)
+	if math.Abs(noBid-0.55) > 1e-9 || math.Abs(noAsk-0.60) > 1e-9 {
+		t.Errorf("NoFromYes = %v, %v; want 0.55, 0.60", noBid, noAsk)
+	}
+}
+
+func TestFromDecimalOdds(t *testing.T) {
+	tests := []struct {
+		odds float64
+		want float64
+		ok   bool
+	}{
+		{2.0, 0.5, true},
+		{4.0, 0.25, true},
+		{1.25, 0.8, true},
+		{1.0, 0, false},
+		{0, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := FromDecimalOdds(tt.odds)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("FromDecimalOdds(%v) = %v, %v; want %v, %v", tt.odds, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTickRounding(t *testing.T) {
+	tests := []struct {
+		p, tick     float64
+		ceil, floor float64
+	}{
+		{0.40, 0.01, 0.40, 0.40},
+		{0.1 + 0.2, 0.01, 0.30, 0.30}, // float noise stays on the tick
+		{0.403, 0.01, 0.41, 0.40},
+		{0.4035, 0.001, 0.404, 0.403},
+		{0.4035, 0, 0.4035, 0.4035},
+	}
+
+	for _, tt := range tests {
+		if got := CeilTick(tt.p, tt.tick); math.Abs(got-tt.ceil) > 1e-12 {
+			t.Errorf("CeilTick(%v, %v) = %v; want %v", tt.p, tt.tick, got, tt.ceil)
+		}
+		if got := FloorTick(tt.p, tt.tick); math.Abs(got-tt.floor) > 1e-12 {
+			t.Errorf("FloorTick(%v, %v) = %v; want %v", tt.p, tt.tick, got, tt.floor)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ws
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+)
+
+func TestPolymarketRefusesInvalidAndStaleUpdates(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	defer c.Close()
+
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t1","price":"0.40","side":"sell","size":"10","timestamp":"2000"}`))
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t1","price":"0.90","side":"sell","size":"10","timestamp":"1000"}`))
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t1","price":"1.5","side":"sell","size":"10","timestamp":"3000"}`))
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t1","price":"0.20","side":"sell","size":"-5","timestamp":"3000"}`))
+
+	q, ok := c.GetQuote(instrument.PMToken("t1"))
+	if !ok || q.Ask != 0.40 || !q.ExchangeTime.Equal(time.UnixMilli(2000)) {
+		t.Fatalf("quote = %+v, %v; want the 0.40 ask stamped at 2000", q, ok)
+	}
+
+	// Once an instrument's updates are stamped, an unstamped one can't be ordered
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t1","price":"0.38","side":"sell","size":"10"}`))
+	if q, _ := c.GetQuote(instrument.PMToken("t1")); q.Ask != 0.40 {
+		t.Errorf("quote = %+v, want the unstamped 0.38 ask refused", q)
+	}
+
+	// Venues that never stamp updates are taken in arrival order
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t2","price":"0.40","side":"sell","size":"10"}`))
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t2","price":"0.45","side":"sell","size":"10"}`))
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t2","price":"0.40","side":"sell","size":"0"}`))
+	if q, _ := c.GetQuote(instrument.PMToken("t2")); q.Ask != 0.45 {
+		t.Errorf("quote = %+v, want the later unstamped 0.45 ask", q)
+	}
+}
+
+func TestKalshiRefusesInvalidAndStaleUpdates(t *testing.T) {
+	c := newTestKalshiClient(t)
+
+	c.handleMessage([]byte(`{"type":"ticker","channel":"ticker","ticker":"K1","yes_bid":0.43,"yes_ask":0.45,"ts":20}`))
+	c.handleMessage([]byte(`{"type":"ticker","channel":"ticker","ticker":"K1","yes_bid":0.10,"yes_ask":0.12,"ts":10}`))
+	c.handleMessage([]byte(`{"type":"ticker","channel":"ticker","ticker":"K1","yes_bid":0.50,"yes_ask":0.40,"ts":30}`))
+	c.handleMessage([]byte(`{"type":"ticker","channel":"ticker","ticker":"K1","yes_bid":-7,"yes_ask":0.45,"ts":30}`))
+
+	q, ok := c.GetQuote(instrument.KalshiYes("K1"))
+	if !ok || q.YesBid != 0.43 || q.YesAsk != 0.45 || !q.ExchangeTime.Equal(time.Unix(20, 0)) {
+		t.Fatalf("quote = %+v, %v; want 0.43/0.45 stamped at 20", q, ok)
+	}
+}
+
+// TestChaosFramesNeverCacheUnusableQuotes streams valid frames with rising
+// timestamps through corruption and reordering, and checks the cache never
+// holds an invalid price or goes back in exchange time
+func TestChaosFramesNeverCacheUnusableQuotes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	pm := NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	defer pm.Close()
+	kalshi := newTestKalshiClient(t)
+	injector := chaos.NewInjector(chaos.Faults{Corrupt: 0.3, Reorder: 0.3, Drop: 0.05, Seed: 42})
+
+	var pmLast, kalshiLast time.Time
+	for n := 1; n <= 2000; n++ {
+		side, p := "sell", 0.30+float64(n%40)/100
+		if n%2 == 0 {
+			side, p = "buy", p-0.05
+		}
+		injector.Frames("pm", []byte(fmt.Sprintf(
+			`{"event_type":"price_change","asset":"t%d","price":"%.2f","side":"%s","size":"100","timestamp":"%d"}`,
+			n%3, p, side, n*1000)), pm.handleMessage)
+		injector.Frames("kalshi", []byte(fmt.Sprintf(
+			`{"type":"ticker","channel":"ticker","ticker":"K%d","yes_bid":%.2f,"yes_ask":%.2f,"ts":%d}`,
+			n%3, p-0.02, p, n)), kalshi.handleMessage)
+
+		for _, q := range pm.Quotes() {
+			if !(q.Ask >= 0 && q.Ask < 1) || !(q.Bid >= 0 && q.Bid < 1) || q.AskSize < 0 || q.BidSize < 0 {
+				t.Fatalf("frame %d: invalid polymarket quote cached: %+v", n, q)
+			}
+			if q.TokenID == "t1" {
+				if q.ExchangeTime.Before(pmLast) {
+					t.Fatalf("frame %d: polymarket quote went back from %v to %v", n, pmLast, q.ExchangeTime)
+				}
+				pmLast = q.ExchangeTime
+			}
+		}
+		for _, q := range kalshi.Quotes() {
+			if !price.Valid(q.YesBid, q.YesAsk) {
+				t.Fatalf("frame %d: invalid kalshi quote cached: %+v", n, q)
+			}
+			if q.Ticker == "K1" {
+				if q.ExchangeTime.Before(kalshiLast) {
+					t.Fatalf("frame %d: kalshi quote went back from %v to %v", n, kalshiLast, q.ExchangeTime)
+				}
+				kalshiLast = q.ExchangeTime
+			}
+		}
+	}
+	if pmLast.IsZero() || kalshiLast.IsZero() {
+		t.Fatal("no update got through")
+	}
+}
+
+// newTestKalshiClient returns an unstarted Kalshi client
+func newTestKalshiClient(t *testing.T) *KalshiClient {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewKalshiClientWithKey(context.Background(), "", "test", key, nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
@@ -0,0 +1,17 @@
+package ws
+
+import "time"
+
+// ConnectionEvent is a client's connection coming up or going down
+type ConnectionEvent struct {
+	Source    string // Metric source label, e.g. "pm" or "kalshi"
+	Connected bool
+	At        time.Time
+}
+
+// notifyConnection reports a connection change to fn, when set
+func notifyConnection(fn func(ConnectionEvent), source string, connected bool) {
+	if fn != nil {
+		fn(ConnectionEvent{Source: source, Connected: connected, At: time.Now()})
+	}
+}
@@ -0,0 +1,136 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultHandshakeTimeout bounds the WebSocket opening handshake when
+// DialOptions leaves it unset
+const DefaultHandshakeTimeout = 10 * time.Second
+
+// DialOptions tunes how a client connects to an exchange. Deployments
+// colocated with an exchange typically want compression off and a specific
+// interface; home connections benefit from compression.
+type DialOptions struct {
+	// Compression negotiates permessage-deflate (RFC 7692)
+	Compression bool
+	// DisableNoDelay re-enables Nagle's algorithm; Go sets TCP_NODELAY by default
+	DisableNoDelay bool
+	// LocalAddr binds outgoing connections to this IP (or IP:port)
+	LocalAddr string
+	// Interface binds outgoing connections to the first address of this
+	// network interface, resolved on every dial so address changes are picked up
+	Interface string
+	// HandshakeTimeout bounds the TCP, TLS and WebSocket handshakes;
+	// 0 means DefaultHandshakeTimeout
+	HandshakeTimeout time.Duration
+}
+
+// NewDialer builds a WebSocket dialer from opts. It fails when LocalAddr
+// is malformed, when both LocalAddr and Interface are set, or when
+// Interface doesn't exist.
+func NewDialer(opts DialOptions) (*websocket.Dialer, error) {
+	if opts.LocalAddr != "" && opts.Interface != "" {
+		return nil, fmt.Errorf("local address and interface are mutually exclusive")
+	}
+
+	var local *net.TCPAddr
+	if opts.LocalAddr != "" {
+		addr, err := parseLocalAddr(opts.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		local = addr
+	}
+	if opts.Interface != "" {
+		if _, err := interfaceAddr(opts.Interface); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := opts.HandshakeTimeout
+	if timeout <= 0 {
+		timeout = DefaultHandshakeTimeout
+	}
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		d := net.Dialer{Timeout: timeout}
+		if local != nil {
+			d.LocalAddr = local
+		} else if opts.Interface != "" {
+			ifaceAddr, err := interfaceAddr(opts.Interface)
+			if err != nil {
+				return nil, err
+			}
+			d.LocalAddr = ifaceAddr
+		}
+
+		conn, err := d.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if tcp, ok := conn.(*net.TCPConn); ok && opts.DisableNoDelay {
+			if err := tcp.SetNoDelay(false); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("set no-delay: %w", err)
+			}
+		}
+		return conn, nil
+	}
+
+	return &websocket.Dialer{
+		Proxy:             http.ProxyFromEnvironment,
+		NetDialContext:    dial,
+		HandshakeTimeout:  timeout,
+		EnableCompression: opts.Compression,
+	}, nil
+}
+
+// parseLocalAddr accepts "ip" or "ip:port"
+func parseLocalAddr(s string) (*net.TCPAddr, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		return &net.TCPAddr{IP: ip}, nil
+	}
+	addr, err := net.ResolveTCPAddr("tcp", s)
+	if err != nil || addr.IP == nil {
+		return nil, fmt.Errorf("invalid local address %q", s)
+	}
+	return addr, nil
+}
+
+// interfaceAddr returns the first IPv4 address of the named interface,
+// falling back to its first IPv6 address
+func interfaceAddr(name string) (*net.TCPAddr, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("interface %q: %w", name, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("interface %q addresses: %w", name, err)
+	}
+
+	var v6 net.IP
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipnet.IP.To4() != nil {
+			return &net.TCPAddr{IP: ipnet.IP}, nil
+		}
+		if v6 == nil && !ipnet.IP.IsLinkLocalUnicast() {
+			v6 = ipnet.IP
+		}
+	}
+	if v6 != nil {
+		return &net.TCPAddr{IP: v6}, nil
+	}
+	return nil, fmt.Errorf("interface %q has no usable address", name)
+}
@@ -0,0 +1,62 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestNewDialerOptions(t *testing.T) {
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}))
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	for _, compression := range []bool{false, true} {
+		d, err := NewDialer(DialOptions{
+			Compression:      compression,
+			DisableNoDelay:   true,
+			LocalAddr:        "127.0.0.1",
+			HandshakeTimeout: time.Second,
+		})
+		if err != nil {
+			t.Fatalf("NewDialer: %v", err)
+		}
+		conn, resp, err := d.DialContext(context.Background(), wsURL, nil)
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		conn.Close()
+
+		negotiated := strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+		if negotiated != compression {
+			t.Errorf("compression=%v: negotiated permessage-deflate=%v", compression, negotiated)
+		}
+		if host := conn.LocalAddr().String(); !strings.HasPrefix(host, "127.0.0.1:") {
+			t.Errorf("local address %s, want 127.0.0.1", host)
+		}
+	}
+}
+
+func TestNewDialerRejectsBadOptions(t *testing.T) {
+	for name, opts := range map[string]DialOptions{
+		"bad local addr":    {LocalAddr: "not-an-ip"},
+		"unknown interface": {Interface: "does-not-exist0"},
+		"both":              {LocalAddr: "127.0.0.1", Interface: "lo"},
+	} {
+		if _, err := NewDialer(opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Package ws provides streaming clients for the Polymarket CLOB market and
+// user channels, the Kalshi trade API WebSocket and the Smarkets quote
+// stream. Clients are created with their New* constructor, optionally given a
+// dialer (see NewDialer), started with Start and read through their channels
+// or GetQuote.
+//
+// Clients record Prometheus metrics on the default registry.
+package ws
@@ -0,0 +1,60 @@
+package ws
+
+import (
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// FlapPolicy defines a flapping connection: one that connects more than
+// MaxReconnects times within Window. A zero policy disables detection.
+type FlapPolicy struct {
+	MaxReconnects int
+	Window        time.Duration
+}
+
+// Flap reports a connection that started flapping
+type Flap struct {
+	Source     string // Metric source label, e.g. "pm" or "kalshi"
+	Reconnects int    // Connections within Window
+	Window     time.Duration
+	DetectedAt time.Time
+}
+
+// flapGuard tracks a client's connections. Each brief success would reset
+// the reconnect backoff to its base delay, so a connection dropping soon
+// after every connect would retry every few seconds forever; while
+// flapping, the client keeps its backoff growing instead. It is only used
+// from the client's connection manager.
+type flapGuard struct {
+	source   string
+	policy   FlapPolicy
+	onFlap   func(Flap)
+	connects []time.Time
+	flapping bool
+}
+
+// connected records a successful connection and reports whether the
+// connection is flapping, calling the hook when it starts to
+func (g *flapGuard) connected(now time.Time) bool {
+	if g.policy.MaxReconnects <= 0 || g.policy.Window <= 0 {
+		return false
+	}
+	kept := g.connects[:0]
+	for _, at := range g.connects {
+		if now.Sub(at) < g.policy.Window {
+			kept = append(kept, at)
+		}
+	}
+	g.connects = append(kept, now)
+
+	flapping := len(g.connects) > g.policy.MaxReconnects
+	if flapping && !g.flapping && g.onFlap != nil {
+		g.onFlap(Flap{Source: g.source, Reconnects: len(g.connects), Window: g.policy.Window, DetectedAt: now})
+	}
+	if flapping != g.flapping {
+		metrics.SetWSFlapping(g.source, flapping)
+	}
+	g.flapping = flapping
+	return flapping
+}
@@ -0,0 +1,49 @@
+package ws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlapGuard(t *testing.T) {
+	var flaps []Flap
+	g := flapGuard{
+		source: "pm",
+		policy: FlapPolicy{MaxReconnects: 2, Window: time.Minute},
+		onFlap: func(f Flap) { flaps = append(flaps, f) },
+	}
+	start := time.Unix(1_700_000_000, 0)
+
+	for i, want := range []bool{false, false, true, true} {
+		if got := g.connected(start.Add(time.Duration(i) * 10 * time.Second)); got != want {
+			t.Fatalf("connect %d: flapping = %v, want %v", i, got, want)
+		}
+	}
+	if len(flaps) != 1 {
+		t.Fatalf("got %d flap reports, want 1 while flapping continues", len(flaps))
+	}
+	if f := flaps[0]; f.Source != "pm" || f.Reconnects != 3 || !f.DetectedAt.Equal(start.Add(20*time.Second)) {
+		t.Errorf("flap = %+v", f)
+	}
+
+	// Once the earlier connects age out of the window, the connection settles
+	// and a later burst reports again
+	if g.connected(start.Add(5 * time.Minute)) {
+		t.Fatal("still flapping after a quiet window")
+	}
+	g.connected(start.Add(5*time.Minute + time.Second))
+	g.connected(start.Add(5*time.Minute + 2*time.Second))
+	if len(flaps) != 2 {
+		t.Errorf("got %d flap reports, want 2", len(flaps))
+	}
+}
+
+func TestFlapGuardDisabled(t *testing.T) {
+	var g flapGuard
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		if g.connected(now) {
+			t.Fatal("zero policy reported flapping")
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package ws
+
+import "time"
+
+// Reasons a decoded quote update is refused, as metric labels
+const (
+	rejectInvalid    = "invalid"      // Prices outside 0-1 or crossed
+	rejectOutOfOrder = "out_of_order" // Older than the instrument's last update
+)
+
+// maxEventTypeLen bounds event type labels; longer values are exchange
+// garbage and would only add metric cardinality
+const maxEventTypeLen = 32
+
+// eventTypeLabel returns the metrics label for a decoded frame's event type
+func eventTypeLabel(eventType string) string {
+	switch {
+	case eventType == "":
+		return "unknown"
+	case len(eventType) > maxEventTypeLen:
+		return "other"
+	default:
+		return eventType
+	}
+}
+
+// outOfOrder reports whether an update the exchange stamped at t is older
+// than the last one applied, stamped at last. A venue stamping an
+// instrument's updates stamps all of them, so once one was stamped an
+// update without a timestamp can't be ordered and counts as out of order.
+func outOfOrder(t, last time.Time) bool {
+	return !last.IsZero() && (t.IsZero() || t.Before(last))
+}
@@ -0,0 +1,64 @@
+package ws
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+func TestHandleMessageCountsFrames(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	defer c.Close()
+
+	parsed := func(eventType string) float64 {
+		return testutil.ToFloat64(metrics.WSFramesParsedTotal.WithLabelValues("pm", eventType))
+	}
+	failures := func() float64 {
+		return testutil.ToFloat64(metrics.WSFrameParseFailuresTotal.WithLabelValues("pm"))
+	}
+	bookBefore, unknownBefore, otherBefore, failBefore := parsed("book"), parsed("unknown"), parsed("other"), failures()
+
+	c.handleMessage([]byte(`{"event_type":"book","asset":"t1","price":"0.4","side":"sell","size":"10"}`))
+	c.handleMessage([]byte(`{"asset":"t1"}`))
+	c.handleMessage([]byte(`{"event_type":"` + strings.Repeat("x", 64) + `"}`))
+	c.handleMessage([]byte(`not json`))
+
+	if got := parsed("book") - bookBefore; got != 1 {
+		t.Errorf("book frames = %v, want 1", got)
+	}
+	if got := parsed("unknown") - unknownBefore; got != 1 {
+		t.Errorf("unknown frames = %v, want 1", got)
+	}
+	if got := parsed("other") - otherBefore; got != 1 {
+		t.Errorf("oversized event type frames = %v, want 1", got)
+	}
+	if got := failures() - failBefore; got != 1 {
+		t.Errorf("parse failures = %v, want 1", got)
+	}
+}
+
+func TestHandleMessageTickSizeChange(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	defer c.Close()
+
+	var market string
+	var tick float64
+	c.SetTickSizeHook(func(conditionID string, newTick float64) { market, tick = conditionID, newTick })
+
+	c.handleMessage([]byte(`{"event_type":"tick_size_change","market":"0xc1","asset":"t1","old_tick_size":"0.01","new_tick_size":"0.001"}`))
+	if market != "0xc1" || tick != 0.001 {
+		t.Errorf("hook got %q, %v; want 0xc1, 0.001", market, tick)
+	}
+	if _, ok := c.GetQuote(instrument.PMToken("t1")); ok {
+		t.Error("a tick size change must not be taken for a quote")
+	}
+}
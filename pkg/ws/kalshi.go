@@ -0,0 +1,754 @@
+package ws
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/auth"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultKalshiWSURL is the production Kalshi WebSocket endpoint
+	DefaultKalshiWSURL = "wss://api.elections.kalshi.com/trade-api/ws/v2"
+	// DefaultKalshiRESTURL is the production Kalshi trade API base URL
+	DefaultKalshiRESTURL = "https://api.elections.kalshi.com/trade-api/v2"
+	kalshiPingInterval   = 30 * time.Second
+	kalshiReadDeadline   = 60 * time.Second
+)
+
+// kalshiReconnectPolicy spaces reconnect attempts to the Kalshi WebSocket
+var kalshiReconnectPolicy = backoff.Policy{Base: 2 * time.Second, Max: 60 * time.Second}
+
+// KalshiMarket represents a market from Kalshi REST API
+type KalshiMarket struct {
+	Ticker         string  `json:"ticker"`
+	Title          string  `json:"title"`
+	Status         string  `json:"status"`
+	Category       string  `json:"category"`
+	Liquidity      float64 `json:"liquidity"` // Cents
+	YesBid         float64 `json:"yes_bid"`
+	YesAsk         float64 `json:"yes_ask"`
+	CloseTime      string  `json:"close_time"`      // Trading stops
+	ExpirationTime string  `json:"expiration_time"` // Latest possible resolution
+	Result         string  `json:"result"`          // "yes" or "no" once determined
+
+	EventTicker  string   `json:"event_ticker"`
+	StrikeType   string   `json:"strike_type"`
+	FloorStrike  *float64 `json:"floor_strike"`
+	CapStrike    *float64 `json:"cap_strike"`
+	Volume       float64  `json:"volume"`
+	Volume24h    float64  `json:"volume_24h"`
+	OpenInterest float64  `json:"open_interest"`
+
+	ExpectedExpirationTime string `json:"expected_expiration_time"` // Expected resolution
+
+	RulesPrimary   string `json:"rules_primary"`
+	RulesSecondary string `json:"rules_secondary"`
+}
+
+// Rules returns the market's full resolution rules
+func (m KalshiMarket) Rules() string {
+	if m.RulesSecondary == "" {
+		return m.RulesPrimary
+	}
+	return m.RulesPrimary + "\n\n" + m.RulesSecondary
+}
+
+// KalshiSubscribeMsg is the subscription message for Kalshi WS
+type KalshiSubscribeMsg struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel"`
+	Ticker  string `json:"ticker,omitempty"`
+}
+
+// KalshiMessage represents incoming WebSocket messages from Kalshi
+type KalshiMessage struct {
+	Type    string  `json:"type"`
+	Channel string  `json:"channel"`
+	Ticker  string  `json:"ticker"`
+	YesBid  float64 `json:"yes_bid"`
+	YesAsk  float64 `json:"yes_ask"`
+	Price   float64 `json:"price"`
+	TS      int64   `json:"ts"` // Exchange time, seconds since the epoch
+}
+
+// KalshiPriceUpdate represents a price update for a Kalshi market
+type KalshiPriceUpdate struct {
+	Ticker    string
+	YesBid    float64
+	YesAsk    float64
+	NoBid     float64   // Computed as 1 - YesAsk
+	NoAsk     float64   // Computed as 1 - YesBid
+	UpdatedAt time.Time // Local receive time of the latest update
+
+	// ExchangeTime is the exchange's timestamp of the latest update, zero
+	// when it sent none. Once set, older and unstamped updates are refused
+	// as out of order.
+	ExchangeTime time.Time
+}
+
+// KalshiFill is a fill of one of our own orders from Kalshi's private fill channel
+type KalshiFill struct {
+	TradeID      string    `json:"trade_id"`
+	OrderID      string    `json:"order_id"`
+	MarketTicker string    `json:"market_ticker"`
+	IsTaker      bool      `json:"is_taker"`
+	Side         string    `json:"side"`   // "yes" or "no"
+	Action       string    `json:"action"` // "buy" or "sell"
+	Count        float64   `json:"count"`
+	YesPrice     float64   `json:"yes_price"` // Cents
+	NoPrice      float64   `json:"no_price"`  // Cents
+	ReceivedAt   time.Time `json:"-"`
+}
+
+// kalshiFillEnvelope is the wire format of private channel messages
+type kalshiFillEnvelope struct {
+	Type string     `json:"type"`
+	Msg  KalshiFill `json:"msg"`
+}
+
+// KalshiClient manages WebSocket connection to Kalshi
+type KalshiClient struct {
+	mu           sync.RWMutex
+	conn         *websocket.Conn
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wsURL        string
+	signer       auth.Signer // nil when disabled
+	clock        *auth.Clock // Tracks Kalshi's clock from handshake responses
+	tickers      []string
+	prices       map[instrument.ID]*KalshiPriceUpdate // kalshi:<ticker>:yes -> price update for both sides
+	priceChan    chan KalshiPriceUpdate
+	fillChan     chan KalshiFill
+	fills        bool // Subscribe to the private fill channel
+	maintenance  func() bool
+	reconnectCh  chan struct{}
+	flaps        flapGuard
+	onConnection func(ConnectionEvent)
+	connected    bool
+	enabled      bool
+	dialer       *websocket.Dialer // nil uses a dialer with DefaultHandshakeTimeout
+	onUpdate     func(id instrument.ID, at time.Time)
+	onRestore    func(lostAt, restoredAt time.Time)
+	lostAt       time.Time   // When an established connection dropped; zero while connected
+	poll         *kalshiPoll // Public REST polling in place of the WebSocket; nil when not enabled
+	logger       *slog.Logger
+}
+
+// NewKalshiClient creates a new Kalshi WebSocket client, loading the private key from keyPath
+func NewKalshiClient(ctx context.Context, wsURL, keyID, keyPath string, tickers []string, logger *slog.Logger) (*KalshiClient, error) {
+	// Check if Kalshi credentials are provided
+	if keyID == "" || keyPath == "" {
+		logger.Warn("kalshi credentials not provided, kalshi websocket disabled")
+		return NewKalshiClientWithKey(ctx, wsURL, "", nil, tickers, logger)
+	}
+
+	// Load private key
+	privateKey, err := loadPrivateKey(keyPath)
+	if err != nil {
+		logger.Warn("failed to load kalshi private key, kalshi websocket disabled", "error", err)
+		return NewKalshiClientWithKey(ctx, wsURL, "", nil, tickers, logger)
+	}
+
+	return NewKalshiClientWithKey(ctx, wsURL, keyID, privateKey, tickers, logger)
+}
+
+// NewKalshiClientWithKey creates a new Kalshi WebSocket client from an already
+// parsed private key. The client is disabled when keyID or privateKey is missing.
+func NewKalshiClientWithKey(ctx context.Context, wsURL, keyID string, privateKey *rsa.PrivateKey, tickers []string, logger *slog.Logger) (*KalshiClient, error) {
+	if keyID == "" || privateKey == nil {
+		return NewKalshiClientWithSigner(ctx, wsURL, nil, nil, tickers, logger)
+	}
+
+	clock := auth.NewClock(0)
+	signer, err := auth.NewKalshiSigner(keyID, privateKey, clock)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("kalshi client initialized", "key_id", keyID)
+	return NewKalshiClientWithSigner(ctx, wsURL, signer, clock, tickers, logger)
+}
+
+// NewKalshiClientWithSigner creates a new Kalshi WebSocket client signing its
+// handshake with signer. clock, when set, is corrected from the handshake
+// responses and should be the one signer takes timestamps from. The client is
+// disabled when signer is nil.
+func NewKalshiClientWithSigner(ctx context.Context, wsURL string, signer auth.Signer, clock *auth.Clock, tickers []string, logger *slog.Logger) (*KalshiClient, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	if wsURL == "" {
+		wsURL = DefaultKalshiWSURL
+	}
+
+	return &KalshiClient{
+		ctx:         ctx,
+		cancel:      cancel,
+		wsURL:       wsURL,
+		signer:      signer,
+		clock:       clock,
+		tickers:     tickers,
+		prices:      make(map[instrument.ID]*KalshiPriceUpdate),
+		priceChan:   make(chan KalshiPriceUpdate, 1000),
+		fillChan:    make(chan KalshiFill, 100),
+		reconnectCh: make(chan struct{}, 1),
+		enabled:     signer != nil,
+		logger:      logger,
+	}, nil
+}
+
+// loadPrivateKey loads an RSA private key from a PEM file
+func loadPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	defer func() {
+		for i := range data {
+			data[i] = 0
+		}
+	}()
+
+	return ParsePrivateKey(data)
+}
+
+// ParsePrivateKey parses a PEM-encoded RSA private key in PKCS8 or PKCS1 form.
+// The caller keeps ownership of data and may zero it afterwards.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+	defer func() {
+		for i := range block.Bytes {
+			block.Bytes[i] = 0
+		}
+	}()
+
+	// Try parsing as PKCS8 first (more common)
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+		return nil, fmt.Errorf("key is not RSA")
+	}
+
+	// Fall back to PKCS1
+	rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	return rsaKey, nil
+}
+
+// EnableFills subscribes to the private fill channel on every (re)connect.
+// Call before Start; fills are delivered on GetFillChannel.
+func (c *KalshiClient) EnableFills() {
+	c.fills = true
+}
+
+// SetMaintenanceCheck installs a predicate reporting whether a scheduled
+// maintenance window is in progress. During maintenance, connection failures
+// are expected and are logged quietly instead of counted as reconnects.
+// Call before Start.
+func (c *KalshiClient) SetMaintenanceCheck(fn func() bool) {
+	c.maintenance = fn
+}
+
+// SetDialer replaces the default WebSocket dialer (see NewDialer). Call before Start.
+func (c *KalshiClient) SetDialer(d *websocket.Dialer) {
+	c.dialer = d
+}
+
+// SetFlapDetection reports the connection flapping to fn, and keeps the
+// reconnect backoff growing while it flaps. Call before Start.
+func (c *KalshiClient) SetFlapDetection(policy FlapPolicy, fn func(Flap)) {
+	c.flaps = flapGuard{source: "kalshi", policy: policy, onFlap: fn}
+}
+
+// SetConnectionHook installs a callback invoked from the connection manager
+// each time the connection comes up or goes down. It must not block. Call
+// before Start.
+func (c *KalshiClient) SetConnectionHook(fn func(ConnectionEvent)) {
+	c.onConnection = fn
+}
+
+// SetUpdateHook installs a callback invoked on the read goroutine for every
+// ticker update, keyed by the market's YES instrument. It must not block.
+// Call before Start.
+func (c *KalshiClient) SetUpdateHook(fn func(id instrument.ID, at time.Time)) {
+	c.onUpdate = fn
+}
+
+// SetRestoreHook installs a callback invoked on its own goroutine whenever
+// a dropped connection is re-established, with when it dropped and when it
+// came back, e.g. to backfill the updates missed in between. Call before Start.
+func (c *KalshiClient) SetRestoreHook(fn func(lostAt, restoredAt time.Time)) {
+	c.onRestore = fn
+}
+
+// inMaintenance reports whether a maintenance window is in progress
+func (c *KalshiClient) inMaintenance() bool {
+	return c.maintenance != nil && c.maintenance()
+}
+
+// Start initiates the WebSocket connection with automatic reconnection, or
+// quote polling for a client without credentials with polling enabled
+func (c *KalshiClient) Start() error {
+	if !c.enabled && c.poll != nil {
+		go c.pollLoop()
+		return nil
+	}
+	if !c.enabled {
+		c.logger.Info("kalshi client disabled, skipping start")
+		return nil
+	}
+
+	go c.connectionManager()
+	return nil
+}
+
+// connectionManager handles reconnection logic with jittered exponential backoff
+func (c *KalshiClient) connectionManager() {
+	retry := backoff.New(kalshiReconnectPolicy)
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.logger.Info("kalshi connection manager stopping")
+			return
+		default:
+		}
+
+		err := c.connect()
+		if err != nil {
+			if c.inMaintenance() {
+				c.logger.Info("kalshi unavailable during maintenance window", "error", err)
+			} else {
+				c.logger.Error("kalshi connection failed", "error", err)
+				metrics.RecordWSReconnect("kalshi")
+			}
+			metrics.SetWSConnectionStatus("kalshi", false)
+
+			delay, _ := retry.Next()
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-c.reconnectCh:
+				// Explicit reconnect request (e.g. maintenance ended): retry now
+				retry.Reset()
+			case <-time.After(delay):
+			}
+			continue
+		}
+
+		// Reset backoff on successful connection, unless the connection is
+		// flapping: then the next drop waits out a longer backoff
+		if !c.flaps.connected(time.Now()) {
+			retry.Reset()
+		}
+		metrics.SetWSConnectionStatus("kalshi", true)
+		notifyConnection(c.onConnection, "kalshi", true)
+
+		// Wait for reconnect signal or context cancellation
+		select {
+		case <-c.reconnectCh:
+			c.logger.Info("kalshi reconnect triggered")
+			notifyConnection(c.onConnection, "kalshi", false)
+		case <-c.ctx.Done():
+			notifyConnection(c.onConnection, "kalshi", false)
+			return
+		}
+		if c.flaps.flapping && !retry.Wait(c.ctx) {
+			return
+		}
+	}
+}
+
+// connect establishes WebSocket connection with authentication
+func (c *KalshiClient) connect() error {
+	c.logger.Info("connecting to kalshi", "url", c.wsURL)
+
+	// Generate authentication headers
+	headers, err := c.authHeaders()
+	if err != nil {
+		return fmt.Errorf("generate auth headers: %w", err)
+	}
+
+	dialer := c.dialer
+	if dialer == nil {
+		dialer = &websocket.Dialer{HandshakeTimeout: DefaultHandshakeTimeout}
+	}
+
+	conn, resp, err := dialer.DialContext(c.ctx, c.wsURL, headers)
+	c.clock.ObserveResponse(resp) // A rejected handshake may be our clock's skew
+	if err != nil {
+		return fmt.Errorf("dial failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.connected = true
+	c.mu.Unlock()
+
+	// Subscribe to ticker channel
+	if err := c.subscribe(); err != nil {
+		conn.Close()
+		return fmt.Errorf("subscribe failed: %w", err)
+	}
+
+	c.logger.Info("kalshi connected and subscribed", "tickers", len(c.tickers))
+
+	c.mu.Lock()
+	lostAt := c.lostAt
+	c.lostAt = time.Time{}
+	c.mu.Unlock()
+	if !lostAt.IsZero() && c.onRestore != nil {
+		go c.onRestore(lostAt, time.Now())
+	}
+
+	// Start ping/pong and read loops
+	go c.pingLoop()
+	go c.readLoop()
+
+	return nil
+}
+
+// authHeaders signs the WebSocket handshake
+func (c *KalshiClient) authHeaders() (http.Header, error) {
+	u, err := url.Parse(c.wsURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse ws url: %w", err)
+	}
+	return c.signer.Headers(http.MethodGet, u.Path, nil)
+}
+
+// subscribe sends subscription messages for all tickers
+func (c *KalshiClient) subscribe() error {
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return fmt.Errorf("no connection")
+	}
+
+	// Subscribe to the ticker channel (market-wide updates)
+	msg := KalshiSubscribeMsg{
+		Type:    "subscribe",
+		Channel: "ticker",
+	}
+
+	if err := conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("write subscription: %w", err)
+	}
+
+	c.logger.Debug("kalshi subscribed to ticker channel")
+
+	if c.fills {
+		if err := conn.WriteJSON(KalshiSubscribeMsg{Type: "subscribe", Channel: "fill"}); err != nil {
+			return fmt.Errorf("write fill subscription: %w", err)
+		}
+		c.logger.Debug("kalshi subscribed to fill channel")
+	}
+
+	return nil
+}
+
+// pingLoop sends periodic pings to keep connection alive
+func (c *KalshiClient) pingLoop() {
+	ticker := time.NewTicker(kalshiPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.RLock()
+			conn := c.conn
+			c.mu.RUnlock()
+
+			if conn == nil {
+				return
+			}
+
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.logger.Error("kalshi ping failed", "error", err)
+				c.triggerReconnect()
+				return
+			}
+		}
+	}
+}
+
+// readLoop reads messages from WebSocket
+func (c *KalshiClient) readLoop() {
+	defer c.triggerReconnect()
+
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return
+	}
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(kalshiReadDeadline)); err != nil {
+			c.logger.Error("kalshi set read deadline failed", "error", err)
+			return
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				if c.inMaintenance() {
+					c.logger.Info("kalshi connection closed during maintenance window", "error", err)
+				} else {
+					c.logger.Error("kalshi read error", "error", err)
+				}
+			}
+			return
+		}
+
+		metrics.RecordWSFrame("kalshi")
+		chaos.Frames("kalshi", message, c.handleMessage)
+	}
+}
+
+// handleMessage processes incoming WebSocket messages
+func (c *KalshiClient) handleMessage(data []byte) {
+	var msg KalshiMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		metrics.RecordWSFrameParseFailure("kalshi")
+		c.logger.Debug("kalshi unmarshal failed", "error", err)
+		return
+	}
+	eventType := msg.Type
+	if eventType == "" {
+		eventType = msg.Channel
+	}
+	metrics.RecordWSFrameParsed("kalshi", eventTypeLabel(eventType))
+
+	// Private fills arrive in an envelope: {"type":"fill","msg":{...}}
+	if msg.Type == "fill" || msg.Channel == "fill" {
+		c.handleFill(data)
+		return
+	}
+
+	// Handle ticker updates
+	if msg.Channel == "ticker" && msg.Ticker != "" {
+		if msg.TS < 0 {
+			metrics.RecordWSFrameRejected("kalshi", rejectInvalid)
+			return
+		}
+		var exchangeTime time.Time
+		if msg.TS > 0 {
+			exchangeTime = time.Unix(msg.TS, 0)
+		}
+		c.storeQuote(msg.Ticker, msg.YesBid, msg.YesAsk, time.Now(), exchangeTime)
+	}
+}
+
+// storeQuote caches a market's quote and forwards it to the price channel.
+// Invalid quotes, and quotes the exchange stamped before the cached one, are
+// refused.
+func (c *KalshiClient) storeQuote(ticker string, yesBid, yesAsk float64, at, exchangeTime time.Time) {
+	if !price.Valid(yesBid, yesAsk) {
+		metrics.RecordWSFrameRejected("kalshi", rejectInvalid)
+		return
+	}
+	noBid, noAsk := price.NoFromYes(yesBid, yesAsk)
+	update := KalshiPriceUpdate{
+		Ticker: ticker,
+		YesBid: yesBid,
+		YesAsk: yesAsk,
+		NoBid:  noBid,
+		NoAsk:  noAsk,
+
+		UpdatedAt:    at,
+		ExchangeTime: exchangeTime,
+	}
+
+	// Update internal state
+	id := instrument.KalshiYes(ticker)
+	c.mu.Lock()
+	if existing, ok := c.prices[id]; ok {
+		if outOfOrder(exchangeTime, existing.ExchangeTime) {
+			c.mu.Unlock()
+			metrics.RecordWSFrameRejected("kalshi", rejectOutOfOrder)
+			return
+		}
+	}
+	c.prices[id] = &update
+	c.mu.Unlock()
+
+	metrics.RecordPriceUpdate("kalshi")
+	if c.onUpdate != nil {
+		c.onUpdate(instrument.KalshiYes(ticker), update.UpdatedAt)
+	}
+
+	// Send to channel
+	select {
+	case c.priceChan <- update:
+	default:
+		c.logger.Warn("kalshi price channel full, dropping update", "instrument", instrument.KalshiYes(ticker))
+	}
+}
+
+// handleFill decodes a private fill and forwards it to the fill channel
+func (c *KalshiClient) handleFill(data []byte) {
+	var env kalshiFillEnvelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Msg.OrderID == "" {
+		c.logger.Debug("kalshi fill unmarshal failed", "error", err)
+		return
+	}
+	env.Msg.ReceivedAt = time.Now()
+
+	select {
+	case c.fillChan <- env.Msg:
+	default:
+		c.logger.Warn("kalshi fill channel full, dropping fill", "order_id", env.Msg.OrderID)
+	}
+}
+
+// triggerReconnect signals the connection manager to reconnect
+func (c *KalshiClient) triggerReconnect() {
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	if c.connected && c.lostAt.IsZero() {
+		c.lostAt = time.Now()
+	}
+	c.connected = false
+	c.mu.Unlock()
+
+	metrics.SetWSConnectionStatus("kalshi", false)
+
+	select {
+	case c.reconnectCh <- struct{}{}:
+	default:
+	}
+}
+
+// Reconnect drops the current connection (if any) and reconnects immediately,
+// skipping any pending backoff delay
+func (c *KalshiClient) Reconnect() {
+	c.triggerReconnect()
+}
+
+// ClearPrices discards all cached quotes so nothing is computed from prices
+// that froze when the feed went away
+func (c *KalshiClient) ClearPrices() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prices = make(map[instrument.ID]*KalshiPriceUpdate)
+}
+
+// GetPriceChannel returns the channel for receiving price updates
+func (c *KalshiClient) GetPriceChannel() <-chan KalshiPriceUpdate {
+	return c.priceChan
+}
+
+// GetFillChannel returns the channel for receiving our own fills
+func (c *KalshiClient) GetFillChannel() <-chan KalshiFill {
+	return c.fillChan
+}
+
+// GetPrice returns the current prices for the market of a Kalshi instrument (either side)
+func (c *KalshiClient) GetPrice(id instrument.ID) (yesBid, yesAsk, noBid, noAsk float64, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if p, found := c.prices[instrument.KalshiYes(id.Key())]; found {
+		return p.YesBid, p.YesAsk, p.NoBid, p.NoAsk, true
+	}
+	return 0, 0, 0, 0, false
+}
+
+// GetQuote returns a copy of the full two-sided quote for the market of a
+// Kalshi instrument (either side), including receive time
+func (c *KalshiClient) GetQuote(id instrument.ID) (KalshiPriceUpdate, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if p, found := c.prices[instrument.KalshiYes(id.Key())]; found {
+		return *p, true
+	}
+	return KalshiPriceUpdate{}, false
+}
+
+// Quotes returns a copy of every cached quote
+func (c *KalshiClient) Quotes() []KalshiPriceUpdate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	quotes := make([]KalshiPriceUpdate, 0, len(c.prices))
+	for _, p := range c.prices {
+		quotes = append(quotes, *p)
+	}
+	return quotes
+}
+
+// SeedQuotes fills the cache with quotes received elsewhere, e.g. from a
+// peer instance at warm start. Cached quotes are only replaced by newer ones.
+// It returns the number of quotes taken.
+func (c *KalshiClient) SeedQuotes(quotes []KalshiPriceUpdate) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var n int
+	for _, q := range quotes {
+		id := instrument.KalshiYes(q.Ticker)
+		if existing, ok := c.prices[id]; ok && !q.UpdatedAt.After(existing.UpdatedAt) {
+			continue
+		}
+		c.prices[id] = &q
+		n++
+	}
+	return n
+}
+
+// IsConnected returns whether the client is currently connected
+func (c *KalshiClient) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected
+}
+
+// IsEnabled returns whether the Kalshi client is enabled
+func (c *KalshiClient) IsEnabled() bool {
+	return c.enabled
+}
+
+// Close gracefully closes the WebSocket connection
+func (c *KalshiClient) Close() error {
+	c.cancel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		err := c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package ws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// DefaultKalshiPollInterval spaces quote polls when none is configured
+const DefaultKalshiPollInterval = 2 * time.Second
+
+// kalshiPollBatch is the number of tickers requested per markets call
+const kalshiPollBatch = 100
+
+// kalshiPoll configures quote polling of Kalshi's public REST API
+type kalshiPoll struct {
+	restURL  string
+	interval time.Duration
+	client   *http.Client
+}
+
+// EnablePolling lets a client without credentials, which would otherwise
+// stay disabled, poll the quotes of its tickers from Kalshi's public markets
+// endpoint every interval instead, so scan-only deployments still price
+// both venues. Quotes are only as fresh as the last poll, and the private
+// fill channel stays unavailable. The client reports itself connected while
+// polls succeed. Has no effect on a client with credentials. Call before
+// Start.
+func (c *KalshiClient) EnablePolling(restURL string, interval time.Duration, client *http.Client) {
+	if c.enabled {
+		return
+	}
+	if restURL == "" {
+		restURL = DefaultKalshiRESTURL
+	}
+	if interval <= 0 {
+		interval = DefaultKalshiPollInterval
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	c.poll = &kalshiPoll{restURL: strings.TrimRight(restURL, "/"), interval: interval, client: client}
+}
+
+// IsPolling reports whether the client polls quotes over REST instead of
+// streaming them
+func (c *KalshiClient) IsPolling() bool {
+	return !c.enabled && c.poll != nil
+}
+
+// IsQuoting reports whether the client produces quotes, streamed with
+// credentials or polled without
+func (c *KalshiClient) IsQuoting() bool {
+	return c.enabled || c.poll != nil
+}
+
+// AddTickers adds markets to poll, e.g. as background matching confirms
+// pairs. The WebSocket subscription is market-wide and needs no update.
+func (c *KalshiClient) AddTickers(tickers []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range tickers {
+		if !slices.Contains(c.tickers, t) {
+			c.tickers = append(c.tickers, t)
+		}
+	}
+}
+
+// pollLoop polls quotes until the client is closed. A Reconnect polls
+// immediately.
+func (c *KalshiClient) pollLoop() {
+	c.logger.Info("kalshi polling public quotes", "url", c.poll.restURL, "interval", c.poll.interval)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.logger.Info("kalshi quote polling stopping")
+			return
+		case <-c.reconnectCh:
+		case <-timer.C:
+		}
+
+		err := c.pollQuotes()
+		c.mu.Lock()
+		wasConnected, lostAt := c.connected, c.lostAt
+		c.connected = err == nil
+		switch {
+		case err != nil && wasConnected:
+			c.lostAt = time.Now()
+		case err == nil:
+			c.lostAt = time.Time{}
+		}
+		c.mu.Unlock()
+		metrics.SetWSConnectionStatus("kalshi", err == nil)
+		if wasConnected != (err == nil) {
+			notifyConnection(c.onConnection, "kalshi", err == nil)
+		}
+
+		switch {
+		case err != nil && c.inMaintenance():
+			c.logger.Info("kalshi unavailable during maintenance window", "error", err)
+		case err != nil:
+			c.logger.Error("kalshi quote poll failed", "error", err)
+		case !wasConnected && !lostAt.IsZero() && c.onRestore != nil:
+			go c.onRestore(lostAt, time.Now())
+		}
+		timer.Reset(c.poll.interval)
+	}
+}
+
+// pollQuotes fetches the quotes of every ticker, in batches
+func (c *KalshiClient) pollQuotes() error {
+	c.mu.RLock()
+	tickers := slices.Clone(c.tickers)
+	c.mu.RUnlock()
+
+	for batch := range slices.Chunk(tickers, kalshiPollBatch) {
+		query := url.Values{}
+		query.Set("tickers", strings.Join(batch, ","))
+		query.Set("limit", fmt.Sprint(len(batch)))
+
+		req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.poll.restURL+"/markets?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		resp, err := c.poll.client.Do(req)
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Markets []KalshiMarket `json:"markets"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("markets: status %d", resp.StatusCode)
+		}
+		if err != nil {
+			return fmt.Errorf("decode markets: %w", err)
+		}
+
+		now := time.Now()
+		for _, m := range result.Markets {
+			// The endpoint may pad a batch with markets not asked for
+			if !slices.Contains(batch, m.Ticker) || (m.YesBid == 0 && m.YesAsk == 0) {
+				continue
+			}
+			c.storeQuote(m.Ticker, m.YesBid, m.YesAsk, now, time.Time{})
+		}
+	}
+	return nil
+}
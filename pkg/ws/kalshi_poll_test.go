@@ -0,0 +1,72 @@
+package ws
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+func TestKalshiPollQuotes(t *testing.T) {
+	var asked string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked = r.URL.Query().Get("tickers")
+		io.WriteString(w, `{"markets": [
+			{"ticker": "A", "yes_bid": 0.40, "yes_ask": 0.45},
+			{"ticker": "B", "yes_bid": 0, "yes_ask": 0},
+			{"ticker": "OTHER", "yes_bid": 0.10, "yes_ask": 0.20}
+		]}`)
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c, err := NewKalshiClientWithKey(context.Background(), "", "", nil, []string{"A"}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if c.IsPolling() {
+		t.Fatal("polling must be opt-in")
+	}
+	c.EnablePolling(srv.URL, 0, srv.Client())
+	c.AddTickers([]string{"B", "A"})
+	if !c.IsPolling() || !c.IsQuoting() || c.IsEnabled() {
+		t.Fatal("a client without credentials polls, and stays without fills")
+	}
+
+	if err := c.pollQuotes(); err != nil {
+		t.Fatalf("pollQuotes: %v", err)
+	}
+	if asked != "A,B" {
+		t.Errorf("tickers asked = %q, want A,B", asked)
+	}
+	q, ok := c.GetQuote(instrument.KalshiYes("A"))
+	if !ok || q.YesBid != 0.40 || q.YesAsk != 0.45 || q.NoBid != 1-0.45 {
+		t.Errorf("quote A = %+v, %v", q, ok)
+	}
+	if _, ok := c.GetQuote(instrument.KalshiYes("B")); ok {
+		t.Error("a market without quotes must not be cached")
+	}
+	if _, ok := c.GetQuote(instrument.KalshiYes("OTHER")); ok {
+		t.Error("markets not asked for must be ignored")
+	}
+}
+
+func TestKalshiPollFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c, _ := NewKalshiClientWithKey(context.Background(), "", "", nil, []string{"A"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer c.Close()
+	c.EnablePolling(srv.URL, 0, srv.Client())
+	if err := c.pollQuotes(); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("pollQuotes error = %v, want the status", err)
+	}
+}
@@ -0,0 +1,637 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultPolymarketWSURL is the production Polymarket market-channel endpoint
+	DefaultPolymarketWSURL = "wss://ws-subscriptions-clob.polymarket.com/ws/"
+	// DefaultPolymarketRESTURL is the production Polymarket CLOB REST base URL
+	DefaultPolymarketRESTURL = "https://clob.polymarket.com"
+	pmPingInterval           = 30 * time.Second
+	pmReadDeadline           = 60 * time.Second
+)
+
+// pmReconnectPolicy spaces reconnect attempts to the Polymarket channels
+var pmReconnectPolicy = backoff.Policy{Base: 2 * time.Second, Max: 60 * time.Second}
+
+// PolymarketMarket represents a market from Polymarket REST API
+type PolymarketMarket struct {
+	ConditionID      string    `json:"condition_id"`
+	QuestionID       string    `json:"question_id"`
+	Question         string    `json:"question"`
+	Description      string    `json:"description"` // Resolution rules
+	Tokens           []PMToken `json:"tokens"`
+	Tags             []string  `json:"tags"`
+	Active           bool      `json:"active"`
+	Closed           bool      `json:"closed"`
+	AcceptingOrders  bool      `json:"accepting_orders"`   // False while trading is paused
+	EndDateISO       string    `json:"end_date_iso"`       // Expected resolution
+	GameStartTime    string    `json:"game_start_time"`    // Sports markets stop trading at kickoff
+	MinimumTickSize  float64   `json:"minimum_tick_size"`  // Price increment, 0.01 or 0.001
+	MinimumOrderSize float64   `json:"minimum_order_size"` // Shares
+	NegRisk          bool      `json:"neg_risk"`           // Traded through the neg-risk exchange
+}
+
+// PMToken represents a token (outcome) in a Polymarket market
+type PMToken struct {
+	TokenID string  `json:"token_id"`
+	Outcome string  `json:"outcome"`
+	Price   float64 `json:"price,string"`
+	Winner  bool    `json:"winner"` // Set on the winning token once resolved
+}
+
+// PolymarketMarketStats is a market's trading activity as reported by
+// Polymarket's Gamma markets API; the CLOB API does not report it
+type PolymarketMarketStats struct {
+	ConditionID string  `json:"conditionId"`
+	Volume24h   float64 `json:"volume24hr"`   // USD
+	Liquidity   float64 `json:"liquidityNum"` // USD resting on the book
+}
+
+// PMSubscribeMsg is the subscription message for Polymarket WS
+type PMSubscribeMsg struct {
+	Type      string   `json:"type,omitempty"`
+	AssetsIDs []string `json:"assets_ids"`
+	Operation string   `json:"operation,omitempty"` // "subscribe" adds assets to a live connection
+}
+
+// PMMessage represents incoming WebSocket messages from Polymarket
+type PMMessage struct {
+	EventType string          `json:"event_type"`
+	Market    string          `json:"market"`
+	Asset     string          `json:"asset"`
+	Price     float64         `json:"price,string"`
+	Side      string          `json:"side"`
+	Size      float64         `json:"size,string"`
+	Book      json.RawMessage `json:"book"`
+	AssetID   string          `json:"asset_id"`         // The token, as book snapshots name it
+	Timestamp int64           `json:"timestamp,string"` // Exchange time, milliseconds since the epoch
+
+	// NewTickSize is set on tick_size_change events, sent when a market's
+	// price nears 0 or 1 and its tick narrows, or back
+	NewTickSize float64 `json:"new_tick_size,string"`
+}
+
+// tokenID returns the token a message is about
+func (m PMMessage) tokenID() string {
+	if m.Asset != "" {
+		return m.Asset
+	}
+	return m.AssetID
+}
+
+// PMPriceUpdate represents a price update for an outcome
+type PMPriceUpdate struct {
+	Instrument instrument.ID
+	TokenID    string
+	Outcome    string    // "YES" or "NO"
+	Ask        float64   // Best ask price
+	Bid        float64   // Best bid price
+	AskSize    float64   // Size at best ask, 0 when unknown
+	BidSize    float64   // Size at best bid, 0 when unknown
+	UpdatedAt  time.Time // Local receive time of the latest update
+
+	// ExchangeTime is the exchange's timestamp of the latest update, zero
+	// when it sent none. Once set, older and unstamped updates are refused
+	// as out of order.
+	ExchangeTime time.Time
+}
+
+// PolymarketClient manages WebSocket connection to Polymarket
+type PolymarketClient struct {
+	mu           sync.RWMutex
+	writeMu      sync.Mutex // Serializes writes; gorilla allows one concurrent writer
+	conn         *websocket.Conn
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wsURL        string
+	tokenIDs     []string
+	chunkSize    int
+	prices       map[instrument.ID]*PMPriceUpdate // pm:token:<id> -> price update
+	books        map[instrument.ID]*pmBook        // pm:token:<id> -> order book
+	priceChan    chan PMPriceUpdate
+	reconnectCh  chan struct{}
+	flaps        flapGuard
+	onConnection func(ConnectionEvent)
+	connected    bool
+	dialer       *websocket.Dialer // nil uses websocket.DefaultDialer
+	onUpdate     func(id instrument.ID, at time.Time)
+	onTickSize   func(conditionID string, tick float64)
+	logger       *slog.Logger
+}
+
+// NewPolymarketClient creates a new Polymarket WebSocket client
+func NewPolymarketClient(ctx context.Context, wsURL string, tokenIDs []string, chunkSize int, logger *slog.Logger) *PolymarketClient {
+	ctx, cancel := context.WithCancel(ctx)
+	if wsURL == "" {
+		wsURL = DefaultPolymarketWSURL
+	}
+	return &PolymarketClient{
+		ctx:         ctx,
+		cancel:      cancel,
+		wsURL:       wsURL,
+		tokenIDs:    tokenIDs,
+		chunkSize:   chunkSize,
+		prices:      make(map[instrument.ID]*PMPriceUpdate),
+		books:       make(map[instrument.ID]*pmBook),
+		priceChan:   make(chan PMPriceUpdate, 1000),
+		reconnectCh: make(chan struct{}, 1),
+		logger:      logger,
+	}
+}
+
+// SetDialer replaces the default WebSocket dialer (see NewDialer). Call before Start.
+func (c *PolymarketClient) SetDialer(d *websocket.Dialer) {
+	c.dialer = d
+}
+
+// SetFlapDetection reports the connection flapping to fn, and keeps the
+// reconnect backoff growing while it flaps. Call before Start.
+func (c *PolymarketClient) SetFlapDetection(policy FlapPolicy, fn func(Flap)) {
+	c.flaps = flapGuard{source: "pm", policy: policy, onFlap: fn}
+}
+
+// SetConnectionHook installs a callback invoked from the connection manager
+// each time the connection comes up or goes down. It must not block. Call
+// before Start.
+func (c *PolymarketClient) SetConnectionHook(fn func(ConnectionEvent)) {
+	c.onConnection = fn
+}
+
+// SetUpdateHook installs a callback invoked on the read goroutine for every
+// price update, e.g. to learn per-instrument update rates. It must not block.
+// Call before Start.
+func (c *PolymarketClient) SetUpdateHook(fn func(id instrument.ID, at time.Time)) {
+	c.onUpdate = fn
+}
+
+// SetTickSizeHook installs a callback invoked on the read goroutine when a
+// market's tick size changes, with the market's condition ID. It must not
+// block. Call before Start.
+func (c *PolymarketClient) SetTickSizeHook(fn func(conditionID string, tick float64)) {
+	c.onTickSize = fn
+}
+
+// AddTokens subscribes to additional tokens, on the live connection and on
+// every reconnect. Tokens already subscribed are ignored.
+func (c *PolymarketClient) AddTokens(tokenIDs []string) error {
+	c.mu.Lock()
+	known := make(map[string]struct{}, len(c.tokenIDs))
+	for _, id := range c.tokenIDs {
+		known[id] = struct{}{}
+	}
+	added := make([]string, 0, len(tokenIDs))
+	for _, id := range tokenIDs {
+		if _, ok := known[id]; ok {
+			continue
+		}
+		known[id] = struct{}{}
+		added = append(added, id)
+	}
+	c.tokenIDs = append(c.tokenIDs, added...)
+	conn := c.conn
+	c.mu.Unlock()
+
+	if len(added) == 0 || conn == nil {
+		return nil
+	}
+	if err := c.writeSubscriptions(conn, added, PMSubscribeMsg{Operation: "subscribe"}); err != nil {
+		return err
+	}
+	c.logger.Info("polymarket tokens added", "added", len(added))
+	return nil
+}
+
+// TokenCount returns the number of subscribed tokens
+func (c *PolymarketClient) TokenCount() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.tokenIDs)
+}
+
+// Start initiates the WebSocket connection with automatic reconnection
+func (c *PolymarketClient) Start() error {
+	go c.connectionManager()
+	return nil
+}
+
+// connectionManager handles reconnection logic with jittered exponential backoff
+func (c *PolymarketClient) connectionManager() {
+	retry := backoff.New(pmReconnectPolicy)
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.logger.Info("polymarket connection manager stopping")
+			return
+		default:
+		}
+
+		err := c.connect()
+		if err != nil {
+			c.logger.Error("polymarket connection failed", "error", err)
+			metrics.RecordWSReconnect("pm")
+			metrics.SetWSConnectionStatus("pm", false)
+
+			if !retry.Wait(c.ctx) {
+				return
+			}
+			continue
+		}
+
+		// Reset backoff on successful connection, unless the connection is
+		// flapping: then the next drop waits out a longer backoff
+		if !c.flaps.connected(time.Now()) {
+			retry.Reset()
+		}
+		metrics.SetWSConnectionStatus("pm", true)
+		notifyConnection(c.onConnection, "pm", true)
+
+		// Wait for reconnect signal or context cancellation
+		select {
+		case <-c.reconnectCh:
+			c.logger.Info("polymarket reconnect triggered")
+			notifyConnection(c.onConnection, "pm", false)
+		case <-c.ctx.Done():
+			notifyConnection(c.onConnection, "pm", false)
+			return
+		}
+		if c.flaps.flapping && !retry.Wait(c.ctx) {
+			return
+		}
+	}
+}
+
+// connect establishes WebSocket connection and starts message handling
+func (c *PolymarketClient) connect() error {
+	c.logger.Info("connecting to polymarket", "url", c.wsURL)
+
+	dialer := c.dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	conn, _, err := dialer.DialContext(c.ctx, c.wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.connected = true
+	c.mu.Unlock()
+
+	// Subscribe to tokens in chunks
+	if err := c.subscribe(); err != nil {
+		conn.Close()
+		return fmt.Errorf("subscribe failed: %w", err)
+	}
+
+	c.logger.Info("polymarket connected and subscribed", "tokens", c.TokenCount())
+
+	// Start ping/pong and read loops
+	go c.pingLoop()
+	go c.readLoop()
+
+	return nil
+}
+
+// subscribe sends subscription messages in chunks
+func (c *PolymarketClient) subscribe() error {
+	c.mu.RLock()
+	conn := c.conn
+	tokenIDs := c.tokenIDs
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return fmt.Errorf("no connection")
+	}
+
+	return c.writeSubscriptions(conn, tokenIDs, PMSubscribeMsg{Type: "MARKET"})
+}
+
+// writeSubscriptions sends tokenIDs in chunks using template for the other
+// message fields, to avoid overwhelming the server
+func (c *PolymarketClient) writeSubscriptions(conn *websocket.Conn, tokenIDs []string, template PMSubscribeMsg) error {
+	for i := 0; i < len(tokenIDs); i += c.chunkSize {
+		end := i + c.chunkSize
+		if end > len(tokenIDs) {
+			end = len(tokenIDs)
+		}
+
+		msg := template
+		msg.AssetsIDs = tokenIDs[i:end]
+
+		c.writeMu.Lock()
+		err := conn.WriteJSON(msg)
+		c.writeMu.Unlock()
+		if err != nil {
+			return fmt.Errorf("write subscription: %w", err)
+		}
+
+		c.logger.Debug("polymarket subscribed chunk", "from", i, "to", end)
+
+		// Small delay between chunks
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return nil
+}
+
+// pingLoop sends periodic pings to keep connection alive
+func (c *PolymarketClient) pingLoop() {
+	ticker := time.NewTicker(pmPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.mu.RLock()
+			conn := c.conn
+			c.mu.RUnlock()
+
+			if conn == nil {
+				return
+			}
+
+			c.writeMu.Lock()
+			err := conn.WriteMessage(websocket.PingMessage, nil)
+			c.writeMu.Unlock()
+			if err != nil {
+				c.logger.Error("polymarket ping failed", "error", err)
+				c.triggerReconnect()
+				return
+			}
+		}
+	}
+}
+
+// readLoop reads messages from WebSocket
+func (c *PolymarketClient) readLoop() {
+	defer c.triggerReconnect()
+
+	c.mu.RLock()
+	conn := c.conn
+	c.mu.RUnlock()
+
+	if conn == nil {
+		return
+	}
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(pmReadDeadline)); err != nil {
+			c.logger.Error("polymarket set read deadline failed", "error", err)
+			return
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.logger.Error("polymarket read error", "error", err)
+			}
+			return
+		}
+
+		metrics.RecordWSFrame("pm")
+		chaos.Frames("pm", message, c.handleMessage)
+	}
+}
+
+// handleMessage processes incoming WebSocket messages
+func (c *PolymarketClient) handleMessage(data []byte) {
+	var msg PMMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		metrics.RecordWSFrameParseFailure("pm")
+		c.logger.Debug("polymarket unmarshal failed", "error", err)
+		return
+	}
+	metrics.RecordWSFrameParsed("pm", eventTypeLabel(msg.EventType))
+
+	if msg.EventType == "tick_size_change" {
+		if c.onTickSize != nil && msg.Market != "" && msg.NewTickSize > 0 {
+			c.onTickSize(msg.Market, msg.NewTickSize)
+		}
+		return
+	}
+
+	switch msg.EventType {
+	case "book":
+		c.handleBook(msg, data)
+	case "price_change":
+		c.handlePriceChange(msg)
+	}
+}
+
+// handleBook replaces a token's order book with a snapshot, sent on
+// subscribe and after trades, seeding its quote at once. The levels are at
+// the top of the message, or nested under "book".
+func (c *PolymarketClient) handleBook(msg PMMessage, data []byte) {
+	tokenID := msg.tokenID()
+	if tokenID == "" {
+		return
+	}
+	var snapshot pmBookSnapshot
+	if len(msg.Book) > 0 {
+		data = msg.Book
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		metrics.RecordWSFrameRejected("pm", rejectInvalid)
+		return
+	}
+	if !snapshot.valid() || msg.Timestamp < 0 {
+		metrics.RecordWSFrameRejected("pm", rejectInvalid)
+		return
+	}
+
+	c.applyBook(tokenID, msg.Timestamp, func(b *pmBook) { b.replace(snapshot) })
+}
+
+// handlePriceChange updates one level of a token's order book: its new
+// resting size, zero when the level emptied
+func (c *PolymarketClient) handlePriceChange(msg PMMessage) {
+	tokenID := msg.tokenID()
+	if tokenID == "" || msg.Price <= 0 {
+		return
+	}
+	if !price.Valid(0, msg.Price) || !(msg.Size >= 0) || msg.Timestamp < 0 {
+		metrics.RecordWSFrameRejected("pm", rejectInvalid)
+		return
+	}
+
+	c.applyBook(tokenID, msg.Timestamp, func(b *pmBook) { b.set(msg.Side, msg.Price, msg.Size) })
+}
+
+// applyBook changes a token's order book, unless the change is out of
+// order, and publishes the resulting top of book as its quote
+func (c *PolymarketClient) applyBook(tokenID string, timestamp int64, change func(*pmBook)) {
+	id := instrument.PMToken(tokenID)
+	var exchangeTime time.Time
+	if timestamp > 0 {
+		exchangeTime = time.UnixMilli(timestamp)
+	}
+
+	c.mu.Lock()
+	quote, ok := c.prices[id]
+	if ok && outOfOrder(exchangeTime, quote.ExchangeTime) {
+		c.mu.Unlock()
+		metrics.RecordWSFrameRejected("pm", rejectOutOfOrder)
+		return
+	}
+	if !ok {
+		quote = &PMPriceUpdate{Instrument: id, TokenID: tokenID}
+		c.prices[id] = quote
+	}
+	book, ok := c.books[id]
+	if !ok {
+		book = newPMBook()
+		c.books[id] = book
+	}
+	change(book)
+	book.apply(quote)
+	if !exchangeTime.IsZero() {
+		quote.ExchangeTime = exchangeTime
+	}
+	quote.UpdatedAt = time.Now()
+	update := *quote
+	c.mu.Unlock()
+
+	metrics.RecordPriceUpdate("pm")
+	if c.onUpdate != nil {
+		c.onUpdate(id, update.UpdatedAt)
+	}
+
+	// Send to channel
+	select {
+	case c.priceChan <- update:
+	default:
+		c.logger.Warn("polymarket price channel full, dropping update", "instrument", id)
+	}
+}
+
+// triggerReconnect signals the connection manager to reconnect
+func (c *PolymarketClient) triggerReconnect() {
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	c.connected = false
+	c.mu.Unlock()
+
+	metrics.SetWSConnectionStatus("pm", false)
+
+	select {
+	case c.reconnectCh <- struct{}{}:
+	default:
+	}
+}
+
+// GetPriceChannel returns the channel for receiving price updates
+func (c *PolymarketClient) GetPriceChannel() <-chan PMPriceUpdate {
+	return c.priceChan
+}
+
+// GetPrice returns the current price for a token instrument
+func (c *PolymarketClient) GetPrice(id instrument.ID) (ask, bid float64, ok bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if p, found := c.prices[id]; found {
+		return p.Ask, p.Bid, true
+	}
+	return 0, 0, false
+}
+
+// GetQuote returns a copy of the full quote for a token instrument, including sizes and receive time
+func (c *PolymarketClient) GetQuote(id instrument.ID) (PMPriceUpdate, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if p, found := c.prices[id]; found {
+		return *p, true
+	}
+	return PMPriceUpdate{}, false
+}
+
+// GetBook returns a copy of a token instrument's order book, best levels
+// first. Quotes seeded with SeedQuotes have no book.
+func (c *PolymarketClient) GetBook(id instrument.ID) (PMOrderBook, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	book, ok := c.books[id]
+	if !ok {
+		return PMOrderBook{}, false
+	}
+	q := c.prices[id]
+	return book.copy(q.TokenID, q.UpdatedAt), true
+}
+
+// Quotes returns a copy of every cached quote
+func (c *PolymarketClient) Quotes() []PMPriceUpdate {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	quotes := make([]PMPriceUpdate, 0, len(c.prices))
+	for _, p := range c.prices {
+		quotes = append(quotes, *p)
+	}
+	return quotes
+}
+
+// SeedQuotes fills the cache with quotes received elsewhere, e.g. from a
+// peer instance at warm start. Cached quotes are only replaced by newer ones.
+// It returns the number of quotes taken.
+func (c *PolymarketClient) SeedQuotes(quotes []PMPriceUpdate) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var n int
+	for _, q := range quotes {
+		id := instrument.PMToken(q.TokenID)
+		if existing, ok := c.prices[id]; ok && !q.UpdatedAt.After(existing.UpdatedAt) {
+			continue
+		}
+		q.Instrument = id
+		c.prices[id] = &q
+		n++
+	}
+	return n
+}
+
+// IsConnected returns whether the client is currently connected
+func (c *PolymarketClient) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected
+}
+
+// Close gracefully closes the WebSocket connection
+func (c *PolymarketClient) Close() error {
+	c.cancel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		err := c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
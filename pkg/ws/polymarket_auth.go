@@ -0,0 +1,72 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/auth"
+)
+
+// Polymarket CLOB endpoints issuing API credentials, signed with L1 auth
+const (
+	pmDeriveAPIKeyPath = "/auth/derive-api-key"
+	pmCreateAPIKeyPath = "/auth/api-key"
+)
+
+// DerivePMAPICredentials obtains the CLOB API credentials of the wallet
+// behind signer (see auth.PolymarketL1Signer) from the REST API at restURL:
+// the credentials already issued for the signer's nonce, or new ones when
+// none exist yet. They authenticate the user channel; the market channel
+// needs none.
+func DerivePMAPICredentials(ctx context.Context, client *http.Client, restURL string, signer auth.Signer) (PMAPICredentials, error) {
+	restURL = strings.TrimSuffix(restURL, "/")
+	creds, status, err := requestPMAPICredentials(ctx, client, http.MethodGet, restURL, pmDeriveAPIKeyPath, signer)
+	if err == nil {
+		return creds, nil
+	}
+	// Deriving fails with a client error until credentials were created
+	if status < 400 || status >= 500 {
+		return PMAPICredentials{}, fmt.Errorf("derive polymarket api key: %w", err)
+	}
+	creds, _, err = requestPMAPICredentials(ctx, client, http.MethodPost, restURL, pmCreateAPIKeyPath, signer)
+	if err != nil {
+		return PMAPICredentials{}, fmt.Errorf("create polymarket api key: %w", err)
+	}
+	return creds, nil
+}
+
+// requestPMAPICredentials performs one signed credentials request, returning
+// the response status alongside any error
+func requestPMAPICredentials(ctx context.Context, client *http.Client, method, restURL, path string, signer auth.Signer) (PMAPICredentials, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, restURL+path, nil)
+	if err != nil {
+		return PMAPICredentials{}, 0, fmt.Errorf("create request: %w", err)
+	}
+	if err := auth.SignRequest(signer, req); err != nil {
+		return PMAPICredentials{}, 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return PMAPICredentials{}, 0, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return PMAPICredentials{}, resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var creds PMAPICredentials
+	if err := json.NewDecoder(resp.Body).Decode(&creds); err != nil {
+		return PMAPICredentials{}, resp.StatusCode, fmt.Errorf("decode response: %w", err)
+	}
+	if !creds.Valid() {
+		return PMAPICredentials{}, resp.StatusCode, fmt.Errorf("polymarket api credentials incomplete")
+	}
+	return creds, resp.StatusCode, nil
+}
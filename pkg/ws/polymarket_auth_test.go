@@ -0,0 +1,63 @@
+package ws
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/auth"
+)
+
+// staticSigner sets a fixed address header
+type staticSigner struct{}
+
+func (staticSigner) Headers(method, path string, body []byte) (http.Header, error) {
+	return http.Header{auth.PolymarketAddressHeader: {"0xabc"}}, nil
+}
+
+func TestDerivePMAPICredentials(t *testing.T) {
+	created := false
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Header.Get(auth.PolymarketAddressHeader) == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/auth/derive-api-key" && !created:
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":"Could not derive api key!"}`)
+		case r.URL.Path == "/auth/api-key" && r.Method == http.MethodPost:
+			created = true
+			fallthrough
+		default:
+			io.WriteString(w, `{"apiKey":"k","secret":"s","passphrase":"p"}`)
+		}
+	}))
+	defer srv.Close()
+
+	// No credentials yet: deriving fails, so they are created
+	want := PMAPICredentials{APIKey: "k", Secret: "s", Passphrase: "p"}
+	creds, err := DerivePMAPICredentials(context.Background(), srv.Client(), srv.URL+"/", staticSigner{})
+	if err != nil || creds != want {
+		t.Fatalf("got %+v, %v", creds, err)
+	}
+	// Then derived
+	if creds, err = DerivePMAPICredentials(context.Background(), srv.Client(), srv.URL, staticSigner{}); err != nil || creds != want {
+		t.Fatalf("got %+v, %v", creds, err)
+	}
+	if len(calls) != 3 || calls[1] != "POST /auth/api-key" || calls[2] != "GET /auth/derive-api-key" {
+		t.Errorf("unexpected calls %v", calls)
+	}
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	if _, err := DerivePMAPICredentials(context.Background(), down.Client(), down.URL, staticSigner{}); err == nil {
+		t.Error("server error accepted")
+	}
+}
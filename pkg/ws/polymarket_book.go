@@ -0,0 +1,155 @@
+package ws
+
+import (
+	"sort"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+)
+
+// PMBookLevel is one price level of a Polymarket order book
+type PMBookLevel struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"` // Shares resting at the price
+}
+
+// PMOrderBook is a copy of a Polymarket token's order book, each side best
+// first: bids by descending price, asks by ascending price
+type PMOrderBook struct {
+	TokenID   string        `json:"token_id"`
+	Bids      []PMBookLevel `json:"bids"`
+	Asks      []PMBookLevel `json:"asks"`
+	UpdatedAt time.Time     `json:"updated_at"` // Local receive time of the latest change
+}
+
+// AskDepth returns the shares offered at limit or cheaper
+func (b PMOrderBook) AskDepth(limit float64) float64 {
+	var size float64
+	for _, l := range b.Asks {
+		if l.Price > limit {
+			break
+		}
+		size += l.Size
+	}
+	return size
+}
+
+// pmWireLevel is a book level as the market channel sends it
+type pmWireLevel struct {
+	Price float64 `json:"price,string"`
+	Size  float64 `json:"size,string"`
+}
+
+// pmBookSnapshot is the levels of a book message
+type pmBookSnapshot struct {
+	Bids []pmWireLevel `json:"bids"`
+	Asks []pmWireLevel `json:"asks"`
+}
+
+// valid reports whether every level has a price within 0-1 and a size
+func (s pmBookSnapshot) valid() bool {
+	for _, side := range [][]pmWireLevel{s.Bids, s.Asks} {
+		for _, l := range side {
+			if l.Price <= 0 || !price.Valid(0, l.Price) || !(l.Size >= 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// pmBook is a token's live order book: resting size by price on each side.
+// Until a book snapshot arrives it only holds the levels price changes
+// touched, so it is not authoritative for a side it has no levels on.
+type pmBook struct {
+	bids     map[float64]float64
+	asks     map[float64]float64
+	snapshot bool // A book snapshot was applied
+}
+
+func newPMBook() *pmBook {
+	return &pmBook{bids: make(map[float64]float64), asks: make(map[float64]float64)}
+}
+
+// replace sets the book to a snapshot's levels
+func (b *pmBook) replace(s pmBookSnapshot) {
+	clear(b.bids)
+	clear(b.asks)
+	for _, l := range s.Bids {
+		b.set("buy", l.Price, l.Size)
+	}
+	for _, l := range s.Asks {
+		b.set("sell", l.Price, l.Size)
+	}
+	b.snapshot = true
+}
+
+// set updates the level at price on side "buy" or "sell" to size; zero size
+// removes it
+func (b *pmBook) set(side string, price, size float64) {
+	var levels map[float64]float64
+	switch side {
+	case "buy":
+		levels = b.bids
+	case "sell":
+		levels = b.asks
+	default:
+		return
+	}
+	if size == 0 {
+		delete(levels, price)
+		return
+	}
+	levels[price] = size
+}
+
+// apply writes the book's top onto a quote. Sides the book has no levels on
+// are left as they are until a snapshot makes the book authoritative, so a
+// quote seeded at warm start survives the first price changes.
+func (b *pmBook) apply(q *PMPriceUpdate) {
+	if bid, size, ok := bestLevel(b.bids, true); ok {
+		q.Bid, q.BidSize = bid, size
+	} else if b.snapshot {
+		q.Bid, q.BidSize = 0, 0
+	}
+	if ask, size, ok := bestLevel(b.asks, false); ok {
+		q.Ask, q.AskSize = ask, size
+	} else if b.snapshot {
+		q.Ask, q.AskSize = 0, 0
+	}
+}
+
+// copy returns the book's levels, best first
+func (b *pmBook) copy(tokenID string, at time.Time) PMOrderBook {
+	return PMOrderBook{
+		TokenID:   tokenID,
+		Bids:      sortedLevels(b.bids, true),
+		Asks:      sortedLevels(b.asks, false),
+		UpdatedAt: at,
+	}
+}
+
+// bestLevel returns the highest priced level when descending, else the lowest
+func bestLevel(levels map[float64]float64, descending bool) (price, size float64, ok bool) {
+	for p, s := range levels {
+		if !ok || (descending && p > price) || (!descending && p < price) {
+			price, size, ok = p, s, true
+		}
+	}
+	return price, size, ok
+}
+
+// sortedLevels returns levels ordered by price
+func sortedLevels(levels map[float64]float64, descending bool) []PMBookLevel {
+	out := make([]PMBookLevel, 0, len(levels))
+	for p, s := range levels {
+		out = append(out, PMBookLevel{Price: p, Size: s})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if descending {
+			return out[i].Price > out[j].Price
+		}
+		return out[i].Price < out[j].Price
+	})
+	return out
+}
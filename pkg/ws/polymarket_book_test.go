@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+)
+
+func TestPolymarketOrderBook(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	defer c.Close()
+	id := instrument.PMToken("t1")
+
+	// A snapshot seeds the quote at once
+	c.handleMessage([]byte(`{"event_type":"book","asset_id":"t1","market":"m1","timestamp":"1000",
+		"bids":[{"price":"0.46","size":"30"},{"price":"0.48","size":"20"}],
+		"asks":[{"price":"0.53","size":"40"},{"price":"0.51","size":"25"},{"price":"0.52","size":"10"}]}`))
+	q, ok := c.GetQuote(id)
+	if !ok || q.Bid != 0.48 || q.BidSize != 20 || q.Ask != 0.51 || q.AskSize != 25 {
+		t.Fatalf("quote = %+v, %v; want 0.48x20 / 0.51x25 from the snapshot", q, ok)
+	}
+	book, ok := c.GetBook(id)
+	if !ok || len(book.Bids) != 2 || len(book.Asks) != 3 || book.Bids[0].Price != 0.48 || book.Asks[0].Price != 0.51 || book.Asks[2].Price != 0.53 {
+		t.Fatalf("book = %+v, %v; want levels best first", book, ok)
+	}
+	if d := book.AskDepth(0.52); d != 35 {
+		t.Errorf("ask depth to 0.52 = %v, want 35", d)
+	}
+
+	// Emptying the best ask exposes the next level
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t1","price":"0.51","side":"sell","size":"0","timestamp":"2000"}`))
+	if q, _ := c.GetQuote(id); q.Ask != 0.52 || q.AskSize != 10 || !q.ExchangeTime.Equal(time.UnixMilli(2000)) {
+		t.Errorf("quote = %+v, want 0.52x10 after the 0.51 level emptied", q)
+	}
+
+	// A later snapshot replaces every level; an empty side is unquoted
+	c.handleMessage([]byte(`{"event_type":"book","asset":"t1","timestamp":"3000","book":{"bids":[],"asks":[{"price":"0.60","size":"5"}]}}`))
+	if q, _ := c.GetQuote(id); q.Bid != 0 || q.BidSize != 0 || q.Ask != 0.60 {
+		t.Errorf("quote = %+v, want no bid and the 0.60 ask", q)
+	}
+	if book, _ := c.GetBook(id); len(book.Bids) != 0 || len(book.Asks) != 1 {
+		t.Errorf("book = %+v, want the nested snapshot's single ask", book)
+	}
+
+	// Invalid and out of order snapshots are refused whole
+	c.handleMessage([]byte(`{"event_type":"book","asset_id":"t1","timestamp":"4000","bids":[{"price":"0.40","size":"5"}],"asks":[{"price":"1.2","size":"5"}]}`))
+	c.handleMessage([]byte(`{"event_type":"book","asset_id":"t1","timestamp":"2500","bids":[{"price":"0.40","size":"5"}]}`))
+	if q, _ := c.GetQuote(id); q.Bid != 0 || q.Ask != 0.60 {
+		t.Errorf("quote = %+v, want the refused snapshots ignored", q)
+	}
+}
+
+func TestPolymarketPriceChangeKeepsSeededSide(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := NewPolymarketClient(context.Background(), "", nil, 0, logger)
+	defer c.Close()
+
+	c.SeedQuotes([]PMPriceUpdate{{TokenID: "t1", Bid: 0.40, Ask: 0.44, UpdatedAt: time.Now().Add(-time.Second)}})
+	c.handleMessage([]byte(`{"event_type":"price_change","asset":"t1","price":"0.43","side":"sell","size":"10"}`))
+
+	// Without a snapshot the book does not know the bid side
+	if q, _ := c.GetQuote(instrument.PMToken("t1")); q.Bid != 0.40 || q.Ask != 0.43 {
+		t.Errorf("quote = %+v, want the seeded 0.40 bid and the new 0.43 ask", q)
+	}
+}
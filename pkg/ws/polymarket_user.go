@@ -0,0 +1,328 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultPolymarketUserWSURL is the production Polymarket authenticated user-channel endpoint
+const DefaultPolymarketUserWSURL = "wss://ws-subscriptions-clob.polymarket.com/ws/user"
+
+// PMAPICredentials are the CLOB L2 API credentials used to authenticate the user channel
+type PMAPICredentials struct {
+	APIKey     string `json:"apiKey"`
+	Secret     string `json:"secret"`
+	Passphrase string `json:"passphrase"`
+}
+
+// Valid reports whether all credential parts are present
+func (c PMAPICredentials) Valid() bool {
+	return c.APIKey != "" && c.Secret != "" && c.Passphrase != ""
+}
+
+// pmUserSubscribeMsg is the subscription message for the user channel
+type pmUserSubscribeMsg struct {
+	Auth    PMAPICredentials `json:"auth"`
+	Markets []string         `json:"markets"`
+	Type    string           `json:"type"`
+}
+
+// PMUserEvent is a trade or order event for our own account from the user channel
+type PMUserEvent struct {
+	EventType    string         `json:"event_type"` // "trade" or "order"
+	ID           string         `json:"id"`
+	Market       string         `json:"market"`   // Condition ID
+	AssetID      string         `json:"asset_id"` // Token ID
+	Side         string         `json:"side"`     // "BUY" or "SELL"
+	Price        float64        `json:"price,string"`
+	Size         float64        `json:"size,string"`          // Trade size
+	Status       string         `json:"status"`               // Trade status: MATCHED, MINED, CONFIRMED, FAILED
+	Type         string         `json:"type"`                 // Order event: PLACEMENT, UPDATE, CANCELLATION
+	OriginalSize float64        `json:"original_size,string"` // Order events only
+	SizeMatched  float64        `json:"size_matched,string"`  // Order events only
+	TakerOrderID string         `json:"taker_order_id"`       // Trade events only
+	MakerOrders  []PMMakerOrder `json:"maker_orders"`         // Trade events only
+	ReceivedAt   time.Time      `json:"-"`
+}
+
+// PMMakerOrder is a resting order a trade matched against. A maker order may
+// be on the complementary token of the taker's, so it carries its own.
+type PMMakerOrder struct {
+	OrderID       string  `json:"order_id"`
+	Owner         string  `json:"owner"`    // API key of the order's owner
+	AssetID       string  `json:"asset_id"` // Token ID
+	Outcome       string  `json:"outcome"`
+	Price         float64 `json:"price,string"`
+	MatchedAmount float64 `json:"matched_amount,string"` // Contracts filled by this trade
+}
+
+// PolymarketUserClient manages the authenticated user-channel connection
+type PolymarketUserClient struct {
+	mu           sync.RWMutex
+	conn         *websocket.Conn
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wsURL        string
+	creds        PMAPICredentials
+	markets      []string // Condition IDs; empty receives events for all markets
+	eventChan    chan PMUserEvent
+	reconnectCh  chan struct{}
+	flaps        flapGuard
+	onConnection func(ConnectionEvent)
+	connected    bool
+	dialer       *websocket.Dialer // nil uses websocket.DefaultDialer
+	logger       *slog.Logger
+}
+
+// NewPolymarketUserClient creates a user-channel client; creds must be valid
+func NewPolymarketUserClient(ctx context.Context, wsURL string, creds PMAPICredentials, markets []string, logger *slog.Logger) (*PolymarketUserClient, error) {
+	if !creds.Valid() {
+		return nil, fmt.Errorf("polymarket api credentials incomplete")
+	}
+	if wsURL == "" {
+		wsURL = DefaultPolymarketUserWSURL
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &PolymarketUserClient{
+		ctx:         ctx,
+		cancel:      cancel,
+		wsURL:       wsURL,
+		creds:       creds,
+		markets:     markets,
+		eventChan:   make(chan PMUserEvent, 100),
+		reconnectCh: make(chan struct{}, 1),
+		logger:      logger,
+	}, nil
+}
+
+// SetDialer replaces the default WebSocket dialer (see NewDialer). Call before Start.
+func (c *PolymarketUserClient) SetDialer(d *websocket.Dialer) {
+	c.dialer = d
+}
+
+// SetFlapDetection reports the connection flapping to fn, and keeps the
+// reconnect backoff growing while it flaps. Call before Start.
+func (c *PolymarketUserClient) SetFlapDetection(policy FlapPolicy, fn func(Flap)) {
+	c.flaps = flapGuard{source: "pm_user", policy: policy, onFlap: fn}
+}
+
+// SetConnectionHook installs a callback invoked from the connection manager
+// each time the connection comes up or goes down. It must not block. Call
+// before Start.
+func (c *PolymarketUserClient) SetConnectionHook(fn func(ConnectionEvent)) {
+	c.onConnection = fn
+}
+
+// Start initiates the WebSocket connection with automatic reconnection
+func (c *PolymarketUserClient) Start() error {
+	go c.connectionManager()
+	return nil
+}
+
+// connectionManager handles reconnection logic with jittered exponential backoff
+func (c *PolymarketUserClient) connectionManager() {
+	retry := backoff.New(pmReconnectPolicy)
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			c.logger.Info("polymarket user connection manager stopping")
+			return
+		default:
+		}
+
+		if err := c.connect(); err != nil {
+			c.logger.Error("polymarket user connection failed", "error", err)
+			metrics.RecordWSReconnect("pm_user")
+			metrics.SetWSConnectionStatus("pm_user", false)
+
+			if !retry.Wait(c.ctx) {
+				return
+			}
+			continue
+		}
+
+		// A flapping connection waits out a longer backoff after each drop
+		if !c.flaps.connected(time.Now()) {
+			retry.Reset()
+		}
+		metrics.SetWSConnectionStatus("pm_user", true)
+		notifyConnection(c.onConnection, "pm_user", true)
+
+		select {
+		case <-c.reconnectCh:
+			c.logger.Info("polymarket user reconnect triggered")
+			notifyConnection(c.onConnection, "pm_user", false)
+		case <-c.ctx.Done():
+			notifyConnection(c.onConnection, "pm_user", false)
+			return
+		}
+		if c.flaps.flapping && !retry.Wait(c.ctx) {
+			return
+		}
+	}
+}
+
+// connect dials the user channel and authenticates via the subscription message
+func (c *PolymarketUserClient) connect() error {
+	c.logger.Info("connecting to polymarket user channel", "url", c.wsURL)
+
+	dialer := c.dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	conn, _, err := dialer.DialContext(c.ctx, c.wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("dial failed: %w", err)
+	}
+
+	msg := pmUserSubscribeMsg{Auth: c.creds, Markets: c.markets, Type: "user"}
+	if msg.Markets == nil {
+		msg.Markets = []string{}
+	}
+	if err := conn.WriteJSON(msg); err != nil {
+		conn.Close()
+		return fmt.Errorf("write subscription: %w", err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.connected = true
+	c.mu.Unlock()
+
+	go c.pingLoop(conn)
+	go c.readLoop(conn)
+
+	return nil
+}
+
+// pingLoop sends periodic pings to keep connection alive
+func (c *PolymarketUserClient) pingLoop(conn *websocket.Conn) {
+	ticker := time.NewTicker(pmPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.logger.Error("polymarket user ping failed", "error", err)
+				c.triggerReconnect()
+				return
+			}
+		}
+	}
+}
+
+// readLoop reads messages from WebSocket
+func (c *PolymarketUserClient) readLoop(conn *websocket.Conn) {
+	defer c.triggerReconnect()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(pmReadDeadline)); err != nil {
+			return
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.logger.Error("polymarket user read error", "error", err)
+			}
+			return
+		}
+
+		metrics.RecordWSFrame("pm_user")
+		chaos.Frames("pm_user", message, c.handleMessage)
+	}
+}
+
+// handleMessage decodes user events; the channel may batch events in an array
+func (c *PolymarketUserClient) handleMessage(data []byte) {
+	var events []PMUserEvent
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &events); err != nil {
+			metrics.RecordWSFrameParseFailure("pm_user")
+			c.logger.Debug("polymarket user unmarshal failed", "error", err)
+			return
+		}
+	} else {
+		var ev PMUserEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			metrics.RecordWSFrameParseFailure("pm_user")
+			c.logger.Debug("polymarket user unmarshal failed", "error", err)
+			return
+		}
+		events = append(events, ev)
+	}
+
+	now := time.Now()
+	for _, ev := range events {
+		metrics.RecordWSFrameParsed("pm_user", eventTypeLabel(ev.EventType))
+		if ev.EventType != "trade" && ev.EventType != "order" {
+			continue
+		}
+		ev.ReceivedAt = now
+
+		select {
+		case c.eventChan <- ev:
+		default:
+			c.logger.Warn("polymarket user event channel full, dropping event", "id", ev.ID)
+		}
+	}
+}
+
+// triggerReconnect signals the connection manager to reconnect
+func (c *PolymarketUserClient) triggerReconnect() {
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	c.connected = false
+	c.mu.Unlock()
+
+	metrics.SetWSConnectionStatus("pm_user", false)
+
+	select {
+	case c.reconnectCh <- struct{}{}:
+	default:
+	}
+}
+
+// GetEventChannel returns the channel for receiving our own trade and order events
+func (c *PolymarketUserClient) GetEventChannel() <-chan PMUserEvent {
+	return c.eventChan
+}
+
+// IsConnected returns whether the client is currently connected
+func (c *PolymarketUserClient) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected
+}
+
+// Close gracefully closes the WebSocket connection
+func (c *PolymarketUserClient) Close() error {
+	c.cancel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		err := c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/backoff"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/chaos"
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/instrument"
+	"github.com/artemgubar/prediction-markets/arb-ws/pkg/price"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultSmarketsWSURL is the production Smarkets quote stream endpoint
+	DefaultSmarketsWSURL = "wss://api.smarkets.com/v3/streaming/"
+	// DefaultSmarketsRESTURL is the production Smarkets API base URL
+	DefaultSmarketsRESTURL = "https://api.smarkets.com/v3"
+	smarketsPingInterval   = 30 * time.Second
+	smarketsReadDeadline   = 60 * time.Second
+)
+
+// smarketsReconnectPolicy spaces reconnect attempts to the Smarkets stream
+var smarketsReconnectPolicy = backoff.Policy{Base: 2 * time.Second, Max: 60 * time.Second}
+
+// SmarketsContract is one contract of a Smarkets market, with the names of
+// its market and event. Each contract trades like a binary market: backing
+// it buys YES, laying it sells YES.
+type SmarketsContract struct {
+	ID         string `json:"id"`
+	MarketID   string `json:"market_id"`
+	Name       string `json:"name"`
+	State      string `json:"state_or_outcome"`
+	MarketName string `json:"-"`
+	EventID    string `json:"-"`
+	EventName  string `json:"-"`
+	EventStart string `json:"-"` // RFC 3339, empty when unscheduled
+}
+
+// Title describes the contract for matching against other venues' markets:
+// the market name, qualified by the contract name unless the market is a
+// plain yes/no question
+func (c SmarketsContract) Title() string {
+	switch c.Name {
+	case "", "Yes", "yes", "YES":
+		return c.MarketName
+	}
+	return c.MarketName + ": " + c.Name
+}
+
+// Instrument returns the contract's canonical instrument ID
+func (c SmarketsContract) Instrument() instrument.ID {
+	return instrument.SmarketsContract(c.ID)
+}
+
+// SmarketsOdds is one level of a Smarkets order book side
+type SmarketsOdds struct {
+	Odds     float64 `json:"odds"`     // Decimal odds
+	Quantity float64 `json:"quantity"` // Stake available at these odds
+}
+
+// SmarketsSubscribeMsg subscribes to quotes for a set of markets
+type SmarketsSubscribeMsg struct {
+	Type      string   `json:"type"`
+	MarketIDs []string `json:"market_ids"`
+}
+
+// SmarketsMessage is a quote stream message: the best back and lay levels
+// of one contract
+type SmarketsMessage struct {
+	Type       string         `json:"type"`
+	MarketID   string         `json:"market_id"`
+	ContractID string         `json:"contract_id"`
+	Back       []SmarketsOdds `json:"back"` // Odds on offer to back (buy YES)
+	Lay        []SmarketsOdds `json:"lay"`  // Odds on offer to lay (sell YES)
+}
+
+// SmarketsPriceUpdate is a contract's quote normalized to the same YES/NO
+// dollar prices as the other venues
+type SmarketsPriceUpdate struct {
+	ContractID string
+	MarketID   string
+	YesBid     float64
+	YesAsk     float64
+	NoBid      float64   // Computed as 1 - YesAsk
+	NoAsk      float64   // Computed as 1 - YesBid
+	AskSize    float64   // Contracts ($1 payouts) available at YesAsk
+	UpdatedAt  time.Time // Local receive time of the latest update
+}
+
+// NormalizeSmarketsQuote converts the best back and lay odds of a contract
+// into YES/NO prices. The best back odds are the highest (the cheapest YES),
+// the best lay odds the lowest (the highest YES bid). A side without valid
+// odds is left at 0.
+func NormalizeSmarketsQuote(msg SmarketsMessage) SmarketsPriceUpdate {
+	update := SmarketsPriceUpdate{ContractID: msg.ContractID, MarketID: msg.MarketID}
+
+	var bestBack, bestLay SmarketsOdds
+	for _, l := range msg.Back {
+		if l.Odds > bestBack.Odds {
+			bestBack = l
+		}
+	}
+	for _, l := range msg.Lay {
+		if l.Odds > 1 && (bestLay.Odds == 0 || l.Odds < bestLay.Odds) {
+			bestLay = l
+		}
+	}
+
+	if p, ok := price.FromDecimalOdds(bestBack.Odds); ok {
+		update.YesAsk = p
+		update.AskSize = bestBack.Quantity * bestBack.Odds
+	}
+	if p, ok := price.FromDecimalOdds(bestLay.Odds); ok {
+		update.YesBid = p
+	}
+	if update.YesAsk > 0 && update.YesBid > 0 {
+		update.NoBid, update.NoAsk = price.NoFromYes(update.YesBid, update.YesAsk)
+	}
+	return update
+}
+
+// SmarketsClient streams Smarkets quotes for a set of markets
+type SmarketsClient struct {
+	mu           sync.RWMutex
+	conn         *websocket.Conn
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wsURL        string
+	sessionToken string
+	marketIDs    []string
+	prices       map[instrument.ID]*SmarketsPriceUpdate
+	priceChan    chan SmarketsPriceUpdate
+	reconnectCh  chan struct{}
+	flaps        flapGuard
+	onConnection func(ConnectionEvent)
+	connected    bool
+	dialer       *websocket.Dialer // nil uses a dialer with DefaultHandshakeTimeout
+	logger       *slog.Logger
+}
+
+// NewSmarketsClient creates a Smarkets quote stream client for the given markets
+func NewSmarketsClient(ctx context.Context, wsURL string, marketIDs []string, logger *slog.Logger) *SmarketsClient {
+	ctx, cancel := context.WithCancel(ctx)
+	if wsURL == "" {
+		wsURL = DefaultSmarketsWSURL
+	}
+
+	return &SmarketsClient{
+		ctx:         ctx,
+		cancel:      cancel,
+		wsURL:       wsURL,
+		marketIDs:   marketIDs,
+		prices:      make(map[instrument.ID]*SmarketsPriceUpdate),
+		priceChan:   make(chan SmarketsPriceUpdate, 1000),
+		reconnectCh: make(chan struct{}, 1),
+		logger:      logger,
+	}
+}
+
+// SetSessionToken authenticates the stream with a Smarkets session token.
+// Call before Start.
+func (c *SmarketsClient) SetSessionToken(token string) {
+	c.sessionToken = token
+}
+
+// SetDialer replaces the default WebSocket dialer (see NewDialer). Call before Start.
+func (c *SmarketsClient) SetDialer(d *websocket.Dialer) {
+	c.dialer = d
+}
+
+// SetFlapDetection reports the connection flapping to fn, and keeps the
+// reconnect backoff growing while it flaps. Call before Start.
+func (c *SmarketsClient) SetFlapDetection(policy FlapPolicy, fn func(Flap)) {
+	c.flaps = flapGuard{source: "smarkets", policy: policy, onFlap: fn}
+}
+
+// SetConnectionHook installs a callback invoked from the connection manager
+// each time the connection comes up or goes down. It must not block. Call
+// before Start.
+func (c *SmarketsClient) SetConnectionHook(fn func(ConnectionEvent)) {
+	c.onConnection = fn
+}
+
+// Start initiates the WebSocket connection with automatic reconnection
+func (c *SmarketsClient) Start() {
+	go c.connectionManager()
+}
+
+// connectionManager handles reconnection logic with jittered exponential backoff
+func (c *SmarketsClient) connectionManager() {
+	retry := backoff.New(smarketsReconnectPolicy)
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		default:
+		}
+
+		if err := c.connect(); err != nil {
+			c.logger.Error("smarkets connection failed", "error", err)
+			metrics.RecordWSReconnect("smarkets")
+			metrics.SetWSConnectionStatus("smarkets", false)
+
+			if !retry.Wait(c.ctx) {
+				return
+			}
+			continue
+		}
+
+		// A flapping connection waits out a longer backoff after each drop
+		if !c.flaps.connected(time.Now()) {
+			retry.Reset()
+		}
+		metrics.SetWSConnectionStatus("smarkets", true)
+		notifyConnection(c.onConnection, "smarkets", true)
+
+		select {
+		case <-c.reconnectCh:
+			c.logger.Info("smarkets reconnect triggered")
+			notifyConnection(c.onConnection, "smarkets", false)
+		case <-c.ctx.Done():
+			notifyConnection(c.onConnection, "smarkets", false)
+			return
+		}
+		if c.flaps.flapping && !retry.Wait(c.ctx) {
+			return
+		}
+	}
+}
+
+// connect dials the stream and subscribes to every market
+func (c *SmarketsClient) connect() error {
+	c.logger.Info("connecting to smarkets", "url", c.wsURL)
+
+	headers := http.Header{}
+	if c.sessionToken != "" {
+		headers.Set("Authorization", "Session-Token "+c.sessionToken)
+	}
+
+	dialer := c.dialer
+	if dialer == nil {
+		dialer = &websocket.Dialer{HandshakeTimeout: DefaultHandshakeTimeout}
+	}
+
+	conn, _, err := dialer.DialContext(c.ctx, c.wsURL, headers)
+	if err != nil {
+		return fmt.Errorf("dial failed: %w", err)
+	}
+
+	if err := conn.WriteJSON(SmarketsSubscribeMsg{Type: "subscribe", MarketIDs: c.marketIDs}); err != nil {
+		conn.Close()
+		return fmt.Errorf("subscribe failed: %w", err)
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.connected = true
+	c.mu.Unlock()
+
+	c.logger.Info("smarkets connected and subscribed", "markets", len(c.marketIDs))
+
+	go c.pingLoop(conn)
+	go c.readLoop(conn)
+	return nil
+}
+
+// pingLoop sends periodic pings to keep connection alive
+func (c *SmarketsClient) pingLoop(conn *websocket.Conn) {
+	ticker := time.NewTicker(smarketsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.logger.Error("smarkets ping failed", "error", err)
+				c.triggerReconnect()
+				return
+			}
+		}
+	}
+}
+
+// readLoop reads messages from WebSocket
+func (c *SmarketsClient) readLoop(conn *websocket.Conn) {
+	defer c.triggerReconnect()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(smarketsReadDeadline)); err != nil {
+			c.logger.Error("smarkets set read deadline failed", "error", err)
+			return
+		}
+
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				c.logger.Error("smarkets read error", "error", err)
+			}
+			return
+		}
+
+		metrics.RecordWSFrame("smarkets")
+		chaos.Frames("smarkets", message, c.handleMessage)
+	}
+}
+
+// handleMessage processes incoming quote messages
+func (c *SmarketsClient) handleMessage(data []byte) {
+	var msg SmarketsMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		metrics.RecordWSFrameParseFailure("smarkets")
+		c.logger.Debug("smarkets unmarshal failed", "error", err)
+		return
+	}
+	metrics.RecordWSFrameParsed("smarkets", eventTypeLabel(msg.Type))
+
+	if msg.Type != "quotes" || msg.ContractID == "" {
+		return
+	}
+
+	update := NormalizeSmarketsQuote(msg)
+	update.UpdatedAt = time.Now()
+	id := instrument.SmarketsContract(msg.ContractID)
+
+	c.mu.Lock()
+	c.prices[id] = &update
+	c.mu.Unlock()
+
+	metrics.RecordPriceUpdate("smarkets")
+
+	select {
+	case c.priceChan <- update:
+	default:
+		c.logger.Warn("smarkets price channel full, dropping update", "instrument", id)
+	}
+}
+
+// triggerReconnect signals the connection manager to reconnect
+func (c *SmarketsClient) triggerReconnect() {
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+	c.connected = false
+	c.mu.Unlock()
+
+	metrics.SetWSConnectionStatus("smarkets", false)
+
+	select {
+	case c.reconnectCh <- struct{}{}:
+	default:
+	}
+}
+
+// GetPriceChannel returns the channel for receiving price updates
+func (c *SmarketsClient) GetPriceChannel() <-chan SmarketsPriceUpdate {
+	return c.priceChan
+}
+
+// GetQuote returns a copy of a contract's latest normalized quote
+func (c *SmarketsClient) GetQuote(id instrument.ID) (SmarketsPriceUpdate, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if p, found := c.prices[id]; found {
+		return *p, true
+	}
+	return SmarketsPriceUpdate{}, false
+}
+
+// IsConnected returns whether the client is currently connected
+func (c *SmarketsClient) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connected
+}
+
+// Close gracefully closes the WebSocket connection
+func (c *SmarketsClient) Close() error {
+	c.cancel()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		err := c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
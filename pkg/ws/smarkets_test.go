@@ -0,0 +1,48 @@
+package ws
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNormalizeSmarketsQuote(t *testing.T) {
+	got := NormalizeSmarketsQuote(SmarketsMessage{
+		ContractID: "100",
+		Back:       []SmarketsOdds{{Odds: 2.0, Quantity: 10}, {Odds: 2.5, Quantity: 4}},
+		Lay:        []SmarketsOdds{{Odds: 3.0, Quantity: 5}, {Odds: 2.6, Quantity: 8}},
+	})
+
+	want := SmarketsPriceUpdate{ContractID: "100", YesAsk: 0.4, YesBid: 1 / 2.6, NoBid: 0.6, NoAsk: 1 - 1/2.6, AskSize: 10}
+	for name, pair := range map[string][2]float64{
+		"YesAsk":  {got.YesAsk, want.YesAsk},
+		"YesBid":  {got.YesBid, want.YesBid},
+		"NoBid":   {got.NoBid, want.NoBid},
+		"NoAsk":   {got.NoAsk, want.NoAsk},
+		"AskSize": {got.AskSize, want.AskSize},
+	} {
+		if math.Abs(pair[0]-pair[1]) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, pair[0], pair[1])
+		}
+	}
+}
+
+func TestNormalizeSmarketsQuoteOneSided(t *testing.T) {
+	got := NormalizeSmarketsQuote(SmarketsMessage{
+		ContractID: "100",
+		Back:       []SmarketsOdds{{Odds: 4.0, Quantity: 1}},
+	})
+	if got.YesAsk != 0.25 || got.YesBid != 0 || got.NoBid != 0 || got.NoAsk != 0 {
+		t.Errorf("one-sided quote must not derive a NO side, got %+v", got)
+	}
+}
+
+func TestSmarketsContractTitle(t *testing.T) {
+	yes := SmarketsContract{MarketName: "Will Starmer remain PM?", Name: "Yes"}
+	if yes.Title() != "Will Starmer remain PM?" {
+		t.Errorf("yes/no market title = %q", yes.Title())
+	}
+	named := SmarketsContract{MarketName: "Most Seats", Name: "Labour"}
+	if named.Title() != "Most Seats: Labour" {
+		t.Errorf("named contract title = %q", named.Title())
+	}
+}
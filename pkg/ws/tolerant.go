@@ -0,0 +1,131 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+// Results of decoding a flexFloat other than a well-formed quoted number
+const (
+	fieldCoerced = "coerced" // Empty string, null or an unquoted number
+	fieldFailed  = "failed"  // Not a number; decoded as 0
+)
+
+// flexFloat decodes a numeric field Polymarket sends as a JSON string. A
+// `json:",string"` float fails the whole object, and with it a whole market
+// page, when one value is empty or not a number; flexFloat instead decodes
+// such values as 0 and remembers why, for take to count.
+type flexFloat struct {
+	value  float64
+	result string // "" when well-formed, fieldCoerced or fieldFailed
+}
+
+// UnmarshalJSON implements json.Unmarshaler; it never fails
+func (f *flexFloat) UnmarshalJSON(data []byte) error {
+	*f = flexFloat{}
+	data = bytes.TrimSpace(data)
+	text, quoted := string(data), false
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			f.result = fieldFailed
+			return nil
+		}
+		quoted = true
+	}
+
+	if text == "" && quoted || text == "null" && !quoted {
+		f.result = fieldCoerced
+		return nil
+	}
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		f.result = fieldFailed
+		return nil
+	}
+	f.value = v
+	if !quoted {
+		f.result = fieldCoerced
+	}
+	return nil
+}
+
+// take counts a tolerated value under field and returns the decoded value
+func (f flexFloat) take(field string) float64 {
+	if f.result != "" {
+		metrics.RecordJSONFieldTolerated(field, f.result)
+	}
+	return f.value
+}
+
+// UnmarshalJSON implements json.Unmarshaler, tolerating a malformed price
+func (t *PMToken) UnmarshalJSON(data []byte) error {
+	type plain PMToken
+	aux := struct {
+		*plain
+		Price flexFloat `json:"price"`
+	}{plain: (*plain)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	t.Price = aux.Price.take("pm_token.price")
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, tolerating a malformed price
+// or size
+func (m *PMMessage) UnmarshalJSON(data []byte) error {
+	type plain PMMessage
+	aux := struct {
+		*plain
+		Price flexFloat `json:"price"`
+		Size  flexFloat `json:"size"`
+	}{plain: (*plain)(m)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.Price = aux.Price.take("pm_message.price")
+	m.Size = aux.Size.take("pm_message.size")
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, tolerating malformed prices
+// and sizes
+func (e *PMUserEvent) UnmarshalJSON(data []byte) error {
+	type plain PMUserEvent
+	aux := struct {
+		*plain
+		Price        flexFloat `json:"price"`
+		Size         flexFloat `json:"size"`
+		OriginalSize flexFloat `json:"original_size"`
+		SizeMatched  flexFloat `json:"size_matched"`
+	}{plain: (*plain)(e)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	e.Price = aux.Price.take("pm_user_event.price")
+	e.Size = aux.Size.take("pm_user_event.size")
+	e.OriginalSize = aux.OriginalSize.take("pm_user_event.original_size")
+	e.SizeMatched = aux.SizeMatched.take("pm_user_event.size_matched")
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, tolerating a malformed price
+// or matched amount
+func (o *PMMakerOrder) UnmarshalJSON(data []byte) error {
+	type plain PMMakerOrder
+	aux := struct {
+		*plain
+		Price         flexFloat `json:"price"`
+		MatchedAmount flexFloat `json:"matched_amount"`
+	}{plain: (*plain)(o)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	o.Price = aux.Price.take("pm_maker_order.price")
+	o.MatchedAmount = aux.MatchedAmount.take("pm_maker_order.matched_amount")
+	return nil
+}
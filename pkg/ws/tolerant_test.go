@@ -0,0 +1,61 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/artemgubar/prediction-markets/arb-ws/internal/metrics"
+)
+
+func TestTolerantNumericFields(t *testing.T) {
+	tolerated := func(field, result string) float64 {
+		return testutil.ToFloat64(metrics.JSONFieldsToleratedTotal.WithLabelValues(field, result))
+	}
+	coercedBefore, failedBefore := tolerated("pm_token.price", fieldCoerced), tolerated("pm_token.price", fieldFailed)
+
+	var markets []PolymarketMarket
+	page := `[
+		{"condition_id":"c1","tokens":[{"token_id":"y","outcome":"Yes","price":"0.42"},{"token_id":"n","outcome":"No","price":""}]},
+		{"condition_id":"c2","tokens":[{"token_id":"y","outcome":"Yes","price":0.3,"winner":true},{"token_id":"n","outcome":"No","price":"n/a"}]},
+		{"condition_id":"c3","tokens":[{"token_id":"y","outcome":"Yes","price":null},{"token_id":"n","outcome":"No","price":"NaN"}]}
+	]`
+	if err := json.Unmarshal([]byte(page), &markets); err != nil {
+		t.Fatalf("a malformed price failed the page: %v", err)
+	}
+	if len(markets) != 3 || markets[0].Tokens[0].Price != 0.42 || markets[1].Tokens[0].Price != 0.3 || !markets[1].Tokens[0].Winner {
+		t.Errorf("unexpected markets %+v", markets)
+	}
+	if markets[0].Tokens[1].Price != 0 || markets[1].Tokens[1].Price != 0 || markets[1].Tokens[1].TokenID != "n" {
+		t.Errorf("malformed prices should decode as 0 with the rest intact: %+v", markets[1].Tokens[1])
+	}
+	if got := tolerated("pm_token.price", fieldCoerced) - coercedBefore; got != 3 {
+		t.Errorf("coerced = %v, want 3 (empty, unquoted, null)", got)
+	}
+	if got := tolerated("pm_token.price", fieldFailed) - failedBefore; got != 2 {
+		t.Errorf("failed = %v, want 2 (n/a, NaN)", got)
+	}
+
+	var msg PMMessage
+	if err := json.Unmarshal([]byte(`{"event_type":"price_change","asset":"t1","price":"0.55","size":{}}`), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.EventType != "price_change" || msg.Price != 0.55 || msg.Size != 0 {
+		t.Errorf("unexpected message %+v", msg)
+	}
+
+	var ev PMUserEvent
+	if err := json.Unmarshal([]byte(`{"event_type":"trade","price":"0.5","size":"10","size_matched":""}`), &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.Price != 0.5 || ev.Size != 10 || ev.SizeMatched != 0 {
+		t.Errorf("unexpected event %+v", ev)
+	}
+
+	// Well-formed objects encode as before
+	out, _ := json.Marshal(PMToken{TokenID: "y", Price: 0.42})
+	if string(out) != `{"token_id":"y","outcome":"","price":"0.42","winner":false}` {
+		t.Errorf("unexpected encoding %s", out)
+	}
+}